@@ -2,19 +2,35 @@ package main
 
 import (
 	"context"
+	"errors"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
 	"transaction-api-w-go/config"
+	"transaction-api-w-go/pkg/bulkhead"
+	"transaction-api-w-go/pkg/cache"
+	"transaction-api-w-go/pkg/circuitbreaker"
 	"transaction-api-w-go/pkg/database"
+	"transaction-api-w-go/pkg/domain"
+	"transaction-api-w-go/pkg/fallback"
+	"transaction-api-w-go/pkg/featureflag"
+	"transaction-api-w-go/pkg/killswitch"
+	"transaction-api-w-go/pkg/loadbalancer"
 	"transaction-api-w-go/pkg/logger"
+	"transaction-api-w-go/pkg/middleware"
+	"transaction-api-w-go/pkg/payment"
 	"transaction-api-w-go/pkg/repository"
+	"transaction-api-w-go/pkg/rpc"
 	"transaction-api-w-go/pkg/server"
 	"transaction-api-w-go/pkg/server/handlers"
 	"transaction-api-w-go/pkg/service"
+	"transaction-api-w-go/pkg/webhook"
+	"transaction-api-w-go/pkg/worker"
 
+	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
 )
 
@@ -26,26 +42,261 @@ func main() {
 	database.Connect(cfg)
 	database.RunMigrations()
 
+	if err := domain.SetMaxBatchSize(cfg.MaxBatchSize); err != nil {
+		log.Warn().Err(err).Int("max_batch_size", cfg.MaxBatchSize).Msg("Geçersiz batch limiti, varsayılan kullanılıyor")
+	}
+
+	// Uygulamanın kök context'i; arka planda çalışan bileşenler buna bağlanır ve
+	// kapanışta tek bir cancel hepsini durdurur
+	appCtx, appCancel := context.WithCancel(context.Background())
+	defer appCancel()
+
 	// Repository'leri oluştur
 	userRepo := repository.NewUserRepository(database.GetDB())
 	transactionRepo := repository.NewTransactionRepository(database.GetDB())
 	balanceRepo := repository.NewBalanceRepository(database.GetDB())
+	balanceRepo.SetBaseCurrency(cfg.DefaultBalanceCurrency)
+	holdRepo := repository.NewBalanceHoldRepository(database.GetDB())
+	txManager := repository.NewTxManager(database.GetDB())
+	eventStore := repository.NewPostgresEventStoreWithMode(database.GetDB(), repository.DeserializationMode(cfg.EventDeserializationMode))
+
+	// Feature flag'ler: varsayılanlar config'den, çalışma zamanı override'ları Redis'ten
+	defaultFlags, err := featureflag.ParseDefaults(cfg.FeatureFlags)
+	if err != nil {
+		log.Warn().Err(err).Str("feature_flags", cfg.FeatureFlags).Msg("Geçersiz feature flag tanımı, varsayılanlar kullanılıyor")
+		defaultFlags = nil
+	}
+	featureFlags := featureflag.NewService(defaultFlags)
+	// Kill switch Redis üzerinden tüm instance'larda zamanlayıcı, batch ve worker işlemlerini duraklatır
+	automationSwitch := killswitch.New(cfg.AutomationPaused)
+	var nonceStore middleware.NonceStore = middleware.NewMemoryNonceStore()
+	var redisCache *cache.RedisCache
+	if cfg.RedisHost != "" {
+		rc, err := cache.NewRedisCacheWithContext(appCtx, cache.CacheConfig{
+			Host:         cfg.RedisHost,
+			Port:         cfg.RedisPort,
+			Password:     cfg.RedisPassword,
+			PoolSize:     cfg.RedisPoolSize,
+			MinIdleConns: cfg.RedisMinIdleConns,
+			MaxRetries:   cfg.RedisMaxRetries,
+			DialTimeout:  time.Duration(cfg.RedisDialTimeoutMS) * time.Millisecond,
+			ReadTimeout:  time.Duration(cfg.RedisReadTimeoutMS) * time.Millisecond,
+			WriteTimeout: time.Duration(cfg.RedisWriteTimeoutMS) * time.Millisecond,
+			Format:       cache.SerializationFormat(cfg.CacheSerializer),
+		}, logger.Structured())
+		if err != nil {
+			log.Warn().Err(err).Msg("Redis'e bağlanılamadı, feature flag override'ları yalnızca bellekte tutulacak")
+		} else {
+			redisCache = rc
+			defer redisCache.Close()
+			// Redis kesintisinde cache çağrıları zaman aşımını beklemeden veritabanına düşer
+			redisCache.SetCircuitBreaker(circuitbreaker.NewCircuitBreakerWithContext(appCtx, cache.BreakerName, cache.DefaultBreakerConfig()))
+			featureFlags.SetStore(redisCache)
+			automationSwitch.SetStore(redisCache)
+			nonceStore = redisCache
+		}
+	}
 
 	// Servisleri oluştur
 	authService := service.NewAuthService(userRepo, cfg.JWTSecret, cfg.JWTRefreshSecret)
+	if cfg.AutoCreateBalances {
+		// Kayıt olan kullanıcının bakiyeleri ilk işlemi beklemeden açılır
+		provisioner, err := service.NewBalanceProvisioner(balanceRepo,
+			repository.NewMultiCurrencyBalanceRepository(database.GetDB()),
+			cfg.DefaultBalanceCurrency, cfg.RegistrationCurrencies)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Bakiye oluşturma ayarları geçersiz")
+		}
+		authService.Subscribe(provisioner)
+	}
 	userService := service.NewUserService(userRepo)
-	transactionService := service.NewTransactionService(transactionRepo, balanceRepo, userRepo)
-	balanceService := service.NewBalanceService(balanceRepo)
+	transactionService := service.NewTransactionService(transactionRepo, balanceRepo, holdRepo, userRepo, txManager)
+	transactionService.SetUniqueReferences(cfg.UniqueReferenceIDs)
+	transactionService.SetDefaultCurrency(cfg.DefaultBalanceCurrency)
+	transactionService.SetFeatureFlags(featureFlags)
+	transactionService.SetEventStore(eventStore)
+	// Gerçek bir sağlayıcı bağlanana kadar dış ödemeler stub ağ geçidinde pending_settlement olarak kalır;
+	// sandbox'ta mock ağ geçidi talimatları gecikmeli olarak sonuçlandırır
+	var gateway payment.PaymentGateway = payment.NewStubGateway()
+	if cfg.PaymentGateway == "mock" {
+		gateway = payment.NewMockGateway(time.Duration(cfg.PaymentGatewaySettleAfterMS) * time.Millisecond)
+	}
+	paymentGateway := payment.NewCircuitBreakerGateway(gateway,
+		circuitbreaker.NewCircuitBreakerWithContext(appCtx, payment.BreakerName, payment.DefaultBreakerConfig()))
+	transactionService.SetPaymentGateway(paymentGateway)
+	if cfg.FeeAccountID != "" {
+		feeSchedule, err := domain.ParseFeeSchedule(cfg.TransactionFees)
+		feeAccountID, idErr := uuid.Parse(cfg.FeeAccountID)
+		if err != nil || idErr != nil {
+			log.Warn().Err(errors.Join(err, idErr)).Str("transaction_fees", cfg.TransactionFees).Msg("Geçersiz ücret tanımı, işlemler ücretsiz")
+		} else {
+			transactionService.SetFees(feeSchedule, feeAccountID)
+		}
+	}
+	var fraudScreener *webhook.Screener
+	if cfg.FraudScreeningURL != "" {
+		fraudScreener = webhook.NewScreener(cfg.FraudScreeningURL, time.Duration(cfg.FraudScreeningTimeoutMS)*time.Millisecond)
+		// Servis kesintisinde işlemler zaman aşımını beklemeden fail-open/fail-closed kararına düşer
+		fraudScreener.SetCircuitBreaker(circuitbreaker.NewCircuitBreakerWithContext(appCtx, webhook.ScreenerBreakerName, webhook.DefaultScreenerBreakerConfig()))
+		transactionService.SetScreening(fraudScreener, cfg.FraudScreeningThreshold, cfg.FraudScreeningFailOpen)
+	}
+	balanceService := service.NewBalanceService(balanceRepo, holdRepo, transactionRepo, txManager)
+	limitRepo := repository.NewTransactionLimitRepository(database.GetDB())
+	balanceService.SetLimitRepository(limitRepo)
+	balanceService.SetDefaultCurrency(cfg.DefaultBalanceCurrency)
+	limitService := service.NewTransactionLimitService(limitRepo, logger.Structured())
+	limitService.SetLimitTiers(userRepo, nil)
+	transactionService.SetLimits(limitService)
+	webhookNotifier := webhook.NewNotifier(webhook.DefaultTimeout)
+	webhookNotifier.SetSecret(cfg.WebhookSigningSecret)
+	webhookDispatcher := webhook.NewDispatcher(appCtx, repository.NewWebhookDeliveryRepository(database.GetDB()), webhookNotifier, cfg.WebhookMaxAttempts)
+	alertService := service.NewBalanceAlertService(
+		repository.NewBalanceAlertRepository(database.GetDB()),
+		webhookDispatcher,
+	)
+	transactionService.SetBalanceAlerts(alertService)
+	balanceService.SetBalanceAlerts(alertService)
+	receiptService := service.NewReceiptService(repository.NewReceiptRepository(database.GetDB()), transactionRepo)
+	transactionService.SetReceipts(receiptService)
+	balanceService.SetReceipts(receiptService)
+	disputeService := service.NewDisputeService(repository.NewDisputeRepository(database.GetDB()), transactionRepo, eventStore)
+	disputeService.SetLimits(limitService)
+	eventRepo := repository.NewEventRepository(eventStore)
+	eventReplayService := service.NewEventReplayService(eventStore, eventRepo, logger.Structured())
+
+	// HA bileşenleri: arka plan health check'leri kök context'e bağlıdır
+	var dbCluster *database.DatabaseCluster
+	if cfg.HADBClusterEnabled {
+		dbPort, _ := strconv.Atoi(cfg.DBPort)
+		dbCluster, err = database.NewDatabaseClusterWithContext(appCtx, database.ReplicationConfig{
+			MasterNode: database.DatabaseNode{
+				Name:     "master",
+				Host:     cfg.DBHost,
+				Port:     dbPort,
+				Database: cfg.DBName,
+				Username: cfg.DBUser,
+				Password: cfg.DBPassword,
+				SSLMode:  "disable",
+				Role:     "master",
+				IsActive: true,
+			},
+			HealthCheckInterval: database.DefaultHealthCheckInterval,
+			FailoverEnabled:     true,
+		})
+		if err != nil {
+			log.Warn().Err(err).Msg("Veritabanı cluster'ı başlatılamadı, HA veritabanı endpoint'leri devre dışı")
+		} else {
+			defer dbCluster.Close()
+		}
+	}
+	loadBalancer := loadbalancer.NewLoadBalancerWithContext(
+		appCtx,
+		loadbalancer.NewRoundRobinStrategy(),
+		loadbalancer.NewHealthChecker(time.Duration(cfg.LBHealthCheckTimeoutMS)*time.Millisecond),
+		loadbalancer.LoadBalancerConfig{
+			HealthCheckInterval: time.Duration(cfg.LBHealthCheckIntervalMS) * time.Millisecond,
+			HealthCheckTimeout:  time.Duration(cfg.LBHealthCheckTimeoutMS) * time.Millisecond,
+		},
+	)
+	defer loadBalancer.Close()
+	loadBalancer.SetStore(repository.NewPostgresBackendStore(database.GetDB()))
+	if restored, err := loadBalancer.Restore(appCtx); err != nil {
+		log.Warn().Err(err).Msg("Kayıtlı load balancer backend'leri yüklenemedi")
+	} else {
+		log.Info().Int("backends", restored).Msg("Load balancer backend'leri yüklendi")
+	}
+	fallbackManager := fallback.NewFallbackManagerWithContext(appCtx, fallback.DefaultConfig(), fallback.NewSequentialFallbackStrategy(fallback.DefaultConfig()))
+	defer fallbackManager.Close()
+
+	// Saklama süresi dolan kayıtları temizleyen job'u başlat
+	retentionJob := worker.NewRetentionJob(repository.NewRetentionRepository(database.GetDB()), domain.RetentionPolicy{
+		TransactionRetention: time.Duration(cfg.RetentionTransactionDays) * 24 * time.Hour,
+		EventRetention:       time.Duration(cfg.RetentionEventDays) * 24 * time.Hour,
+		BatchSize:            cfg.RetentionBatchSize,
+		Interval:             time.Duration(cfg.RetentionIntervalHours) * time.Hour,
+		Mode:                 domain.RetentionMode(cfg.RetentionMode),
+	})
+	retentionJob.Start()
+	defer retentionJob.Stop()
+
+	// Bakiyeleri işlem geçmişiyle karşılaştıran günlük mutabakat job'unu başlat
+	reconciliationJob := worker.NewReconciliationJob(
+		repository.NewReconciliationRepository(database.GetDB()),
+		eventStore,
+		time.Duration(cfg.ReconciliationIntervalHours)*time.Hour,
+	)
+	reconciliationJob.Start()
+	defer reconciliationJob.Stop()
+
+	// Ağ geçidinde sonuç bekleyen dış ödemeleri sorgulayan job'u başlat
+	settlementJob := worker.NewSettlementJob(transactionService,
+		time.Duration(cfg.PaymentSettlementIntervalSeconds)*time.Second, service.DefaultSettlementBatchSize)
+	settlementJob.Start()
+	defer settlementJob.Stop()
+
+	// Backoff süresi dolan başarısız webhook teslimatlarını yeniden deneyen job'u başlat
+	webhookRetryJob := worker.NewWebhookRetryJob(webhookDispatcher,
+		time.Duration(cfg.WebhookRetryIntervalSeconds)*time.Second, webhook.DefaultRetryBatchSize)
+	webhookRetryJob.Start()
+	defer webhookRetryJob.Stop()
 
 	// Handler'ları oluştur
 	authHandler := handlers.NewAuthHandler(authService)
 	userHandler := handlers.NewUserHandler(userService)
 	transactionHandler := handlers.NewTransactionHandler(transactionService)
 	balanceHandler := handlers.NewBalanceHandler(balanceService)
+	alertHandler := handlers.NewBalanceAlertHandler(alertService)
+	disputeHandler := handlers.NewDisputeHandler(disputeService)
+	eventHandler := server.NewEventHandler(eventReplayService, eventStore)
+	// Zamanlanmış, batch ve çoklu para birimi servisleri ile cache/worker handler'ları domain
+	// arayüzlerini karşılayan repository ve servisler gelene kadar bağlanmaz; nil olan
+	// handler'ların route'ları kaydedilmez
+	advancedHandler := server.NewAdvancedTransactionHandler(nil, nil, limitService, nil)
+	haHandler := server.NewHAHandler(appCtx, dbCluster, loadBalancer, fallbackManager)
+	if redisCache != nil {
+		haHandler.RegisterCircuitBreaker(cache.BreakerName, redisCache.CircuitBreaker())
+	}
+	if fraudScreener != nil {
+		haHandler.RegisterCircuitBreaker(webhook.ScreenerBreakerName, fraudScreener.CircuitBreaker())
+	}
+	haHandler.RegisterCircuitBreaker(payment.BreakerName, paymentGateway.CircuitBreaker())
+	reconcileHandler := server.NewReconciliationHandler(reconciliationJob)
+	flagHandler := server.NewFeatureFlagHandler(featureFlags)
 
 	// HTTP sunucusunu başlat
 	srv := server.NewServer(8081)
-	srv.SetHandlers(authHandler, userHandler, transactionHandler, balanceHandler)
+	srv.SetRequestSigning(middleware.SignatureConfig{
+		Secret: cfg.RequestSigningSecret,
+		Window: time.Duration(cfg.RequestSigningWindowSeconds) * time.Second,
+		Routes: middleware.ParseSignedRoutes(cfg.SignedRoutes),
+		Nonces: nonceStore,
+	})
+	srv.SetAuditLog(repository.NewAuditRepository(database.GetDB()))
+	srv.SetWebhookDeliveries(webhookDispatcher)
+	srv.SetKillSwitch(automationSwitch)
+	srv.SetDrainTimeout(time.Duration(cfg.ShutdownDrainTimeoutMS) * time.Millisecond)
+	bulkheadLimits, err := bulkhead.ParseLimits(cfg.BulkheadLimits)
+	if err != nil {
+		log.Warn().Err(err).Str("bulkhead_limits", cfg.BulkheadLimits).Msg("Geçersiz bulkhead tanımı, eşzamanlılık sınırları kapalı")
+	}
+	bulkheads := bulkhead.NewRegistry(bulkheadLimits)
+	srv.SetBulkheads(bulkheads)
+	haHandler.RegisterBulkheads(bulkheads)
+	srv.SetHandlers(
+		authHandler,
+		userHandler,
+		transactionHandler,
+		balanceHandler,
+		alertHandler,
+		disputeHandler,
+		eventHandler,
+		nil,
+		advancedHandler,
+		haHandler,
+		nil,
+		reconcileHandler,
+		flagHandler,
+	)
 
 	go func() {
 		if err := srv.Start(); err != nil {
@@ -53,6 +304,14 @@ func main() {
 		}
 	}()
 
+	// gRPC sunucusunu aynı servis katmanıyla başlat
+	grpcSrv := rpc.NewServer(cfg.GRPCPort, cfg.JWTSecret, rpc.NewTransactionServer(transactionService, balanceService))
+	go func() {
+		if err := grpcSrv.Start(); err != nil {
+			log.Fatal().Err(err).Msg("gRPC sunucusu başlatılamadı")
+		}
+	}()
+
 	// Graceful shutdown için sinyal bekle
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -60,21 +319,48 @@ func main() {
 	<-quit
 	log.Info().Msg("Shutdown signal received")
 
+	// Önce süren HTTP isteklerinin bitmesi kendi drain süresi boyunca beklenir; replay ve export gibi
+	// uzun istekler temizlik süresinden pay yemez
+	if err := srv.Shutdown(context.Background()); err != nil {
+		log.Error().Err(err).Msg("HTTP sunucusu kapatılırken hata oluştu")
+	}
+
 	// Graceful shutdown için timeout ile context oluştur
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer shutdownCancel()
 
-	cleanup(shutdownCtx, srv)
+	// Kök context iptal edildiğinde durması beklenen arka plan goroutine'leri
+	background := []<-chan struct{}{loadBalancer.Done(), fallbackManager.Done(), paymentGateway.CircuitBreaker().Done()}
+	if dbCluster != nil {
+		background = append(background, dbCluster.Done())
+	}
+	if redisCache != nil {
+		background = append(background, redisCache.CircuitBreaker().Done())
+	}
+	if fraudScreener != nil {
+		background = append(background, fraudScreener.CircuitBreaker().Done())
+	}
+
+	cleanup(shutdownCtx, grpcSrv, appCancel, background)
 }
 
-func cleanup(ctx context.Context, srv *server.Server) {
+func cleanup(ctx context.Context, grpcSrv *rpc.Server, appCancel context.CancelFunc, background []<-chan struct{}) {
 	log.Info().Msg("Temizlik işlemleri başlatılıyor...")
 
 	done := make(chan bool)
 	go func() {
-		// HTTP sunucusunu kapat
-		if err := srv.Shutdown(ctx); err != nil {
-			log.Error().Err(err).Msg("HTTP sunucusu kapatılırken hata oluştu")
+		// gRPC sunucusunu kapat
+		if err := grpcSrv.Shutdown(ctx); err != nil {
+			log.Error().Err(err).Msg("gRPC sunucusu kapatılırken hata oluştu")
+		}
+
+		// Arka plan goroutine'lerini durdur ve bitmelerini bekle
+		appCancel()
+		for _, done := range background {
+			select {
+			case <-done:
+			case <-ctx.Done():
+			}
 		}
 
 		// Veritabanı bağlantısını kapat
@@ -2,6 +2,8 @@ package config
 
 import (
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
@@ -15,20 +17,167 @@ type Config struct {
 	JWTSecret        string
 	JWTRefreshSecret string
 	ServerPort       string
+	GRPCPort         int
+	MaxBatchSize     int
+	// UniqueReferenceIDs aynı kullanıcı için tekrar eden reference id'leri reddeder
+	UniqueReferenceIDs bool
+
+	RetentionTransactionDays int
+	RetentionEventDays       int
+	RetentionBatchSize       int
+	RetentionIntervalHours   int
+	RetentionMode            string
+
+	ReconciliationIntervalHours int
+
+	// EventDeserializationMode "strict" ya da "lenient"; lenient modda bozuk event'ler dead-letter tablosuna alınır
+	EventDeserializationMode string
+
+	// RedisHost boşsa Redis kullanılmaz; feature flag override'ları yalnızca bellekte tutulur
+	RedisHost     string
+	RedisPort     int
+	RedisPassword string
+	// Redis bağlantı havuzu ve zaman aşımları; yavaş bir Redis isteklerin asılı kalmasına yol açmamalı
+	RedisPoolSize       int
+	RedisMinIdleConns   int
+	RedisMaxRetries     int
+	RedisDialTimeoutMS  int
+	RedisReadTimeoutMS  int
+	RedisWriteTimeoutMS int
+	// CacheSerializer cache değerlerinin biçimi: "json" (varsayılan) veya "msgpack"
+	CacheSerializer string
+	// FeatureFlags varsayılan flag değerleri, ör. "new_transfer_path=25,parallel_batch=true"
+	FeatureFlags string
+
+	// RequestSigningSecret boşsa imzalı istek kontrolü kapalıdır
+	RequestSigningSecret        string
+	RequestSigningWindowSeconds int
+	// SignedRoutes imza gerektiren route'lar, ör. "POST /api/v1/transactions/transfer"
+	SignedRoutes string
+
+	// HADBClusterEnabled açıksa veritabanı için health check yapan HA cluster'ı başlatılır
+	HADBClusterEnabled      bool
+	LBHealthCheckIntervalMS int
+	LBHealthCheckTimeoutMS  int
+
+	// TransactionFees ücret tarifesi, ör. "TRANSFER=flat:0.5,percent:1;premium.TRANSFER=percent:0.5";
+	// FeeAccountID boşsa ücret alınmaz
+	TransactionFees string
+	FeeAccountID    string
+
+	// FraudScreeningURL boşsa işlemler taranmaz; FraudScreeningThreshold ve üzerindeki tutarlar
+	// tamamlanmadan önce bu servise sorulur
+	FraudScreeningURL       string
+	FraudScreeningThreshold float64
+	FraudScreeningTimeoutMS int
+	// FraudScreeningFailOpen açıksa servise ulaşılamadığında işlemler onaylanır, kapalıysa reddedilir
+	FraudScreeningFailOpen bool
+
+	// BulkheadLimits pahalı işlem sınıfları için eşzamanlılık sınırları, ör. "batch=4,replay=1,export=2"
+	BulkheadLimits string
+
+	// PaymentGateway "stub" (talimatlar hiç sonuçlanmaz) ya da "mock" (sandbox); mock talimatları
+	// PaymentGatewaySettleAfterMS sonra sonuçlandırır. Sonuç bekleyen ödemeler
+	// PaymentSettlementIntervalSeconds aralıkla ağ geçidine sorulur.
+	PaymentGateway                   string
+	PaymentGatewaySettleAfterMS      int
+	PaymentSettlementIntervalSeconds int
+
+	// WebhookSigningSecret boşsa webhook'lar imzalanmaz; başarısız teslimatlar WebhookMaxAttempts
+	// denemeden sonra dead-letter'a düşer ve WebhookRetryIntervalSeconds aralıkla yeniden denenir
+	WebhookSigningSecret        string
+	WebhookMaxAttempts          int
+	WebhookRetryIntervalSeconds int
+
+	// AutoCreateBalances açıkken kayıt olan kullanıcıya DefaultBalanceCurrency cinsinden bir bakiye ve
+	// RegistrationCurrencies'teki her para birimi için bir çoklu para birimi bakiyesi açılır.
+	// DefaultBalanceCurrency bakiyesi olmayan kullanıcıya ilk işlemde açılan bakiyede de kullanılır.
+	AutoCreateBalances     bool
+	DefaultBalanceCurrency string
+	RegistrationCurrencies []string
+
+	// AutomationPaused kill switch'in Redis'te kayıt yokken geçerli başlangıç durumudur; açıkken
+	// zamanlayıcı, batch ve worker işlemleri admin anahtarı bırakana kadar duraklatılır
+	AutomationPaused bool
+
+	// ShutdownDrainTimeoutMS kapanışta süren HTTP isteklerinin tamamlanması için beklenen süre;
+	// 30 saniyelik temizlik süresi bu bekleme bittikten sonra başlar
+	ShutdownDrainTimeoutMS int
 }
 
 func LoadConfig() *Config {
 	godotenv.Load()
 
 	return &Config{
-		DBHost:           getEnv("DB_HOST", "localhost"),
-		DBPort:           getEnv("DB_PORT", "5432"),
-		DBUser:           getEnv("DB_USER", "postgres"),
-		DBPassword:       getEnv("DB_PASSWORD", "postgres"),
-		DBName:           getEnv("DB_NAME", "transaction_db"),
-		JWTSecret:        getEnv("JWT_SECRET", "your-secret-key"),
-		JWTRefreshSecret: getEnv("JWT_REFRESH_SECRET", "your-refresh-secret-key"),
-		ServerPort:       getEnv("SERVER_PORT", "8080"),
+		DBHost:             getEnv("DB_HOST", "localhost"),
+		DBPort:             getEnv("DB_PORT", "5432"),
+		DBUser:             getEnv("DB_USER", "postgres"),
+		DBPassword:         getEnv("DB_PASSWORD", "postgres"),
+		DBName:             getEnv("DB_NAME", "transaction_db"),
+		JWTSecret:          getEnv("JWT_SECRET", "your-secret-key"),
+		JWTRefreshSecret:   getEnv("JWT_REFRESH_SECRET", "your-refresh-secret-key"),
+		ServerPort:         getEnv("SERVER_PORT", "8080"),
+		GRPCPort:           getEnvInt("GRPC_PORT", 50051),
+		MaxBatchSize:       getEnvInt("MAX_BATCH_SIZE", 1000),
+		UniqueReferenceIDs: getEnvBool("UNIQUE_REFERENCE_IDS", false),
+
+		RetentionTransactionDays: getEnvInt("RETENTION_TRANSACTION_DAYS", 365),
+		RetentionEventDays:       getEnvInt("RETENTION_EVENT_DAYS", 365),
+		RetentionBatchSize:       getEnvInt("RETENTION_BATCH_SIZE", 500),
+		RetentionIntervalHours:   getEnvInt("RETENTION_INTERVAL_HOURS", 24),
+		RetentionMode:            getEnv("RETENTION_MODE", "archive"),
+
+		ReconciliationIntervalHours: getEnvInt("RECONCILIATION_INTERVAL_HOURS", 24),
+
+		EventDeserializationMode: getEnv("EVENT_DESERIALIZATION_MODE", "strict"),
+
+		RedisHost:     getEnv("REDIS_HOST", ""),
+		RedisPort:     getEnvInt("REDIS_PORT", 6379),
+		RedisPassword: getEnv("REDIS_PASSWORD", ""),
+
+		RedisPoolSize:       getEnvInt("REDIS_POOL_SIZE", 0),
+		RedisMinIdleConns:   getEnvInt("REDIS_MIN_IDLE_CONNS", 0),
+		RedisMaxRetries:     getEnvInt("REDIS_MAX_RETRIES", 2),
+		RedisDialTimeoutMS:  getEnvInt("REDIS_DIAL_TIMEOUT_MS", 2000),
+		RedisReadTimeoutMS:  getEnvInt("REDIS_READ_TIMEOUT_MS", 500),
+		RedisWriteTimeoutMS: getEnvInt("REDIS_WRITE_TIMEOUT_MS", 500),
+		CacheSerializer:     getEnv("CACHE_SERIALIZER", "json"),
+
+		FeatureFlags: getEnv("FEATURE_FLAGS", ""),
+
+		RequestSigningSecret:        getEnv("REQUEST_SIGNING_SECRET", ""),
+		RequestSigningWindowSeconds: getEnvInt("REQUEST_SIGNING_WINDOW_SECONDS", 300),
+		SignedRoutes:                getEnv("SIGNED_ROUTES", "POST /api/v1/transactions/transfer"),
+
+		HADBClusterEnabled:      getEnvBool("HA_DB_CLUSTER_ENABLED", false),
+		LBHealthCheckIntervalMS: getEnvInt("LB_HEALTH_CHECK_INTERVAL_MS", 30000),
+		LBHealthCheckTimeoutMS:  getEnvInt("LB_HEALTH_CHECK_TIMEOUT_MS", 5000),
+
+		TransactionFees: getEnv("TRANSACTION_FEES", ""),
+		FeeAccountID:    getEnv("FEE_ACCOUNT_ID", ""),
+
+		FraudScreeningURL:       getEnv("FRAUD_SCREENING_URL", ""),
+		FraudScreeningThreshold: getEnvFloat("FRAUD_SCREENING_THRESHOLD", 10000),
+		FraudScreeningTimeoutMS: getEnvInt("FRAUD_SCREENING_TIMEOUT_MS", 2000),
+		FraudScreeningFailOpen:  getEnvBool("FRAUD_SCREENING_FAIL_OPEN", false),
+
+		BulkheadLimits: getEnv("BULKHEAD_LIMITS", "batch=4,replay=1,export=2"),
+
+		PaymentGateway:                   getEnv("PAYMENT_GATEWAY", "stub"),
+		PaymentGatewaySettleAfterMS:      getEnvInt("PAYMENT_GATEWAY_SETTLE_AFTER_MS", 5000),
+		PaymentSettlementIntervalSeconds: getEnvInt("PAYMENT_SETTLEMENT_INTERVAL_SECONDS", 60),
+
+		WebhookSigningSecret:        getEnv("WEBHOOK_SIGNING_SECRET", ""),
+		WebhookMaxAttempts:          getEnvInt("WEBHOOK_MAX_ATTEMPTS", 6),
+		WebhookRetryIntervalSeconds: getEnvInt("WEBHOOK_RETRY_INTERVAL_SECONDS", 15),
+
+		AutoCreateBalances:     getEnvBool("AUTO_CREATE_BALANCES", true),
+		DefaultBalanceCurrency: getEnv("DEFAULT_BALANCE_CURRENCY", "TRY"),
+		RegistrationCurrencies: getEnvList("REGISTRATION_CURRENCIES"),
+
+		AutomationPaused: getEnvBool("AUTOMATION_PAUSED", false),
+
+		ShutdownDrainTimeoutMS: getEnvInt("SHUTDOWN_DRAIN_TIMEOUT_MS", 25000),
 	}
 }
 
@@ -39,3 +188,38 @@ func getEnv(key, defaultValue string) string {
 	}
 	return value
 }
+
+func getEnvInt(key string, defaultValue int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+func getEnvBool(key string, defaultValue bool) bool {
+	value, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+// getEnvList virgülle ayrılmış değeri boşlukları kırparak listeye çevirir; boş öğeler atlanır
+func getEnvList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+func getEnvFloat(key string, defaultValue float64) float64 {
+	value, err := strconv.ParseFloat(os.Getenv(key), 64)
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
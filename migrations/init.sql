@@ -1,5 +1,21 @@
 DROP TABLE IF EXISTS audit_logs;
+DROP TABLE IF EXISTS webhook_delivery_attempts;
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS transaction_limit_reservations;
+DROP TABLE IF EXISTS transaction_limits;
+DROP TABLE IF EXISTS event_dead_letters;
+DROP TABLE IF EXISTS batch_transaction_items;
+DROP TABLE IF EXISTS batch_transactions;
+DROP TABLE IF EXISTS scheduled_transactions;
+DROP TABLE IF EXISTS transaction_receipts;
+DROP TABLE IF EXISTS balance_alert_rules;
+DROP TABLE IF EXISTS reconciliation_reports;
+DROP TABLE IF EXISTS disputes;
+DROP TABLE IF EXISTS transactions_archive;
+DROP TABLE IF EXISTS balance_holds;
 DROP TABLE IF EXISTS balance_history;
+DROP TABLE IF EXISTS multi_currency_transactions;
+DROP TABLE IF EXISTS multi_currency_balances;
 DROP TABLE IF EXISTS balances;
 DROP TABLE IF EXISTS transactions;
 DROP TABLE IF EXISTS users;
@@ -11,6 +27,7 @@ CREATE TABLE IF NOT EXISTS users (
     first_name VARCHAR(255) NOT NULL,
     last_name VARCHAR(255) NOT NULL,
     role VARCHAR(20) NOT NULL DEFAULT 'user',
+    limit_tier VARCHAR(20) NOT NULL DEFAULT 'basic',
     created_at TIMESTAMP NOT NULL,
     updated_at TIMESTAMP NOT NULL,
     INDEX idx_email (email)
@@ -23,22 +40,101 @@ CREATE TABLE IF NOT EXISTS transactions (
     amount DECIMAL(19,4) NOT NULL,
     description TEXT,
     reference_id VARCHAR(100),
+    reference_unique BOOLEAN NOT NULL DEFAULT FALSE,
+    -- UNIQUE_REFERENCE_IDS açıkken oluşturulan işlemlerde boş olmayan reference id kullanıcı başına tekildir
+    unique_reference_key VARCHAR(100) GENERATED ALWAYS AS (IF(reference_unique, NULLIF(reference_id, ''), NULL)) STORED,
+    counterparty_id VARCHAR(36),
+    category VARCHAR(50),
+    tags JSON,
+    metadata JSON,
+    external_order_id VARCHAR(100) GENERATED ALWAYS AS (JSON_UNQUOTE(JSON_EXTRACT(metadata, '$.external_order_id'))) VIRTUAL,
+    device_id VARCHAR(100) GENERATED ALWAYS AS (JSON_UNQUOTE(JSON_EXTRACT(metadata, '$.device_id'))) VIRTUAL,
     balance_after DECIMAL(19,4) NOT NULL,
     status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    status_reason VARCHAR(255),
+    external_account JSON,
+    gateway_reference VARCHAR(100),
+    disputed BOOLEAN NOT NULL DEFAULT FALSE,
     created_at TIMESTAMP NOT NULL,
     updated_at TIMESTAMP NOT NULL,
+    deleted_at TIMESTAMP NULL,
     INDEX idx_user_id (user_id),
     INDEX idx_created_at (created_at),
+    INDEX idx_deleted_at (deleted_at),
+    INDEX idx_disputed (disputed),
+    INDEX idx_status_created (status, created_at),
+    INDEX idx_gateway_reference (gateway_reference),
+    INDEX idx_user_category (user_id, category),
+    INDEX idx_user_created (user_id, created_at, id),
+    INDEX idx_reference_id (reference_id),
+    UNIQUE INDEX uq_user_reference (user_id, unique_reference_key),
+    INDEX idx_counterparty_id (counterparty_id),
+    INDEX idx_external_order_id (external_order_id),
+    INDEX idx_device_id (device_id),
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+CREATE TABLE IF NOT EXISTS transactions_archive LIKE transactions;
+
+CREATE TABLE IF NOT EXISTS disputes (
+    id VARCHAR(36) PRIMARY KEY,
+    transaction_id VARCHAR(36) NOT NULL,
+    user_id VARCHAR(36) NOT NULL,
+    reason TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'open',
+    resolution TEXT,
+    resolved_by VARCHAR(36),
+    opened_at TIMESTAMP NOT NULL,
+    resolved_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    -- Bir işlem için aynı anda yalnızca bir açık itiraz olabilir
+    open_transaction_id VARCHAR(36) GENERATED ALWAYS AS (IF(status = 'open', transaction_id, NULL)) STORED,
+    INDEX idx_transaction_status (transaction_id, status),
+    UNIQUE INDEX uq_open_dispute (open_transaction_id),
+    FOREIGN KEY (transaction_id) REFERENCES transactions(id),
     FOREIGN KEY (user_id) REFERENCES users(id)
 );
 
 CREATE TABLE IF NOT EXISTS balances (
     id VARCHAR(36) PRIMARY KEY,
-    user_id VARCHAR(36) NOT NULL UNIQUE,
+    user_id VARCHAR(36) NOT NULL,
     amount DECIMAL(19,4) NOT NULL DEFAULT 0.0000,
-    currency VARCHAR(3) DEFAULT 'TRY',
+    minimum_balance DECIMAL(19,4) NOT NULL DEFAULT 0,
+    currency VARCHAR(3) NOT NULL DEFAULT 'TRY',
     created_at TIMESTAMP NOT NULL,
     updated_at TIMESTAMP NOT NULL,
+    -- Kullanıcının her para biriminde ayrı bir bakiyesi olabilir
+    UNIQUE INDEX idx_balances_user_currency (user_id, currency),
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+CREATE TABLE IF NOT EXISTS multi_currency_balances (
+    id VARCHAR(36) PRIMARY KEY,
+    user_id VARCHAR(36) NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    amount DECIMAL(19,4) NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    UNIQUE INDEX idx_user_currency (user_id, currency),
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+CREATE TABLE IF NOT EXISTS multi_currency_transactions (
+    id VARCHAR(36) PRIMARY KEY,
+    user_id VARCHAR(36) NOT NULL,
+    type VARCHAR(30) NOT NULL,
+    from_currency VARCHAR(3) NOT NULL,
+    to_currency VARCHAR(3) NOT NULL,
+    from_amount DECIMAL(19,4) NOT NULL CHECK (from_amount > 0),
+    to_amount DECIMAL(19,4) NOT NULL,
+    rate DECIMAL(19,8) NOT NULL CHECK (rate > 0),
+    fee DECIMAL(19,4) NOT NULL DEFAULT 0,
+    rate_source VARCHAR(50),
+    created_at TIMESTAMP NOT NULL,
+    INDEX idx_user_created (user_id, created_at),
+    INDEX idx_user_from_currency (user_id, from_currency),
+    INDEX idx_user_to_currency (user_id, to_currency),
     FOREIGN KEY (user_id) REFERENCES users(id)
 );
 
@@ -51,13 +147,286 @@ CREATE TABLE IF NOT EXISTS balance_history (
     FOREIGN KEY (user_id) REFERENCES users(id)
 );
 
+CREATE TABLE IF NOT EXISTS balance_holds (
+    id VARCHAR(36) PRIMARY KEY,
+    user_id VARCHAR(36) NOT NULL,
+    amount DECIMAL(19,4) NOT NULL CHECK (amount > 0),
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    description TEXT,
+    reference_id VARCHAR(100),
+    transaction_id VARCHAR(36),
+    captured_at TIMESTAMP NULL,
+    released_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    INDEX idx_user_status (user_id, status),
+    INDEX idx_transaction_status (transaction_id, status),
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+CREATE TABLE IF NOT EXISTS balance_alert_rules (
+    id VARCHAR(36) PRIMARY KEY,
+    user_id VARCHAR(36) NOT NULL,
+    direction VARCHAR(10) NOT NULL CHECK (direction IN ('below', 'above')),
+    threshold DECIMAL(19,4) NOT NULL,
+    hysteresis DECIMAL(19,4) NOT NULL DEFAULT 0,
+    webhook_url VARCHAR(2048) NOT NULL,
+    triggered BOOLEAN NOT NULL DEFAULT FALSE,
+    last_triggered_at TIMESTAMP NULL,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    INDEX idx_user_active (user_id, is_active),
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+CREATE TABLE IF NOT EXISTS reconciliation_reports (
+    id VARCHAR(36) PRIMARY KEY,
+    run_at TIMESTAMP NOT NULL,
+    users_checked BIGINT NOT NULL DEFAULT 0,
+    discrepancy_count INT NOT NULL DEFAULT 0,
+    discrepancies JSON,
+    duration VARCHAR(50),
+    created_at TIMESTAMP NOT NULL,
+    INDEX idx_run_at (run_at)
+);
+
+CREATE TABLE IF NOT EXISTS transaction_receipts (
+    id VARCHAR(36) PRIMARY KEY,
+    transaction_id VARCHAR(36) NOT NULL UNIQUE,
+    user_id VARCHAR(36) NOT NULL,
+    sequence BIGINT NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    amount DECIMAL(19,4) NOT NULL,
+    balance_after DECIMAL(19,4) NOT NULL,
+    previous_hash CHAR(64) NOT NULL,
+    hash CHAR(64) NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    UNIQUE INDEX idx_receipt_user_sequence (user_id, sequence),
+    -- Retention işlemi transactions_archive'a taşıyabildiğinden transaction_id'ye yabancı anahtar konmaz
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+CREATE TABLE IF NOT EXISTS scheduled_transactions (
+    id VARCHAR(36) PRIMARY KEY,
+    user_id VARCHAR(36) NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    amount DECIMAL(19,4) NOT NULL,
+    currency VARCHAR(3) NOT NULL DEFAULT 'USD',
+    description TEXT,
+    reference_id VARCHAR(100),
+    to_user_id VARCHAR(36),
+    scheduled_at TIMESTAMP NOT NULL,
+    timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    recurring_type VARCHAR(20),
+    recurring_config JSON,
+    occurrence_count INT NOT NULL DEFAULT 0,
+    execution_window JSON,
+    max_retries INT NOT NULL DEFAULT 3,
+    retry_count INT NOT NULL DEFAULT 0,
+    last_retry_at TIMESTAMP NULL,
+    next_retry_at TIMESTAMP NULL,
+    lease_owner VARCHAR(100),
+    lease_expires_at TIMESTAMP NULL,
+    external_account JSON,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    deleted_at TIMESTAMP NULL,
+    INDEX idx_user_id (user_id),
+    INDEX idx_deleted_at (deleted_at),
+    INDEX idx_scheduled_at (scheduled_at),
+    INDEX idx_status_scheduled_at (status, scheduled_at),
+    INDEX idx_status_next_retry_at (status, next_retry_at),
+    INDEX idx_status_lease_expires_at (status, lease_expires_at),
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+CREATE TABLE IF NOT EXISTS batch_transactions (
+    id VARCHAR(36) PRIMARY KEY,
+    user_id VARCHAR(36) NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    currency VARCHAR(3) NOT NULL DEFAULT 'USD',
+    description TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    total_amount DECIMAL(19,4) NOT NULL,
+    item_count INT NOT NULL,
+    processed_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    deleted_at TIMESTAMP NULL,
+    INDEX idx_user_id (user_id),
+    INDEX idx_status (status),
+    INDEX idx_created_at (created_at),
+    INDEX idx_deleted_at (deleted_at)
+);
+
+CREATE TABLE IF NOT EXISTS batch_transaction_items (
+    id VARCHAR(36) PRIMARY KEY,
+    batch_id VARCHAR(36) NOT NULL,
+    transaction_id VARCHAR(36) NOT NULL,
+    amount DECIMAL(19,4) NOT NULL,
+    description TEXT,
+    reference_id VARCHAR(100),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    error_message TEXT,
+    processed_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    deleted_at TIMESTAMP NULL,
+    INDEX idx_batch_id (batch_id),
+    INDEX idx_status (status),
+    INDEX idx_transaction_id (transaction_id),
+    INDEX idx_deleted_at (deleted_at),
+    FOREIGN KEY (batch_id) REFERENCES batch_transactions(id) ON DELETE CASCADE
+);
+
+-- Boş tier kullanıcıya özel limiti, dolu tier ise seviyeden türetilen kullanım kaydını ifade eder
+CREATE TABLE IF NOT EXISTS transaction_limits (
+    id VARCHAR(36) PRIMARY KEY,
+    user_id VARCHAR(36) NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    daily_limit DECIMAL(19,4) NOT NULL,
+    weekly_limit DECIMAL(19,4) NOT NULL,
+    monthly_limit DECIMAL(19,4) NOT NULL,
+    single_limit DECIMAL(19,4) NOT NULL,
+    daily_count INT NOT NULL DEFAULT 0,
+    weekly_count INT NOT NULL DEFAULT 0,
+    monthly_count INT NOT NULL DEFAULT 0,
+    daily_amount DECIMAL(19,4) NOT NULL DEFAULT 0,
+    weekly_amount DECIMAL(19,4) NOT NULL DEFAULT 0,
+    monthly_amount DECIMAL(19,4) NOT NULL DEFAULT 0,
+    last_reset_date TIMESTAMP NOT NULL,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    tier VARCHAR(20) NOT NULL DEFAULT '',
+    daily_count_limit INT NOT NULL DEFAULT 100,
+    weekly_count_limit INT NOT NULL DEFAULT 700,
+    monthly_count_limit INT NOT NULL DEFAULT 3000,
+    weekly_reset_date TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    monthly_reset_date TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    UNIQUE INDEX idx_transaction_limits_user_currency (user_id, currency),
+    INDEX idx_currency (currency),
+    CONSTRAINT chk_transaction_limits_count_limits
+        CHECK (daily_count_limit > 0 AND weekly_count_limit > 0 AND monthly_count_limit > 0)
+);
+
+CREATE TABLE IF NOT EXISTS transaction_limit_reservations (
+    id VARCHAR(36) PRIMARY KEY,
+    transaction_id VARCHAR(36) NOT NULL UNIQUE,
+    limit_id VARCHAR(36) NOT NULL,
+    user_id VARCHAR(36) NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    amount DECIMAL(19,4) NOT NULL,
+    reserved_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    refunded_at TIMESTAMP NULL,
+    INDEX idx_limit_id (limit_id),
+    FOREIGN KEY (limit_id) REFERENCES transaction_limits(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS event_store (
+    id VARCHAR(36) PRIMARY KEY,
+    type VARCHAR(100) NOT NULL,
+    aggregate_id VARCHAR(36) NOT NULL,
+    version BIGINT NOT NULL,
+    timestamp TIMESTAMP NOT NULL,
+    data JSON NOT NULL,
+    metadata JSON,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_aggregate_version (aggregate_id, version),
+    INDEX idx_type_timestamp (type, timestamp),
+    INDEX idx_timestamp (timestamp)
+);
+
+CREATE TABLE IF NOT EXISTS event_store_archive LIKE event_store;
+
+-- Event'ler yalnızca aggregate'in bu versiyonu kapsayan bir snapshot'ı varsa temizlenir
+CREATE TABLE IF NOT EXISTS aggregate_snapshots (
+    aggregate_id VARCHAR(36) NOT NULL,
+    version BIGINT NOT NULL,
+    data JSON NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (aggregate_id, version)
+);
+
+CREATE TABLE IF NOT EXISTS projection_checkpoints (
+    projection_name VARCHAR(100) PRIMARY KEY,
+    last_timestamp TIMESTAMP NOT NULL,
+    last_event_id VARCHAR(36) NOT NULL,
+    processed_count BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS event_dead_letters (
+    id VARCHAR(36) PRIMARY KEY,
+    type VARCHAR(100) NOT NULL,
+    aggregate_id VARCHAR(36) NOT NULL,
+    version BIGINT NOT NULL,
+    timestamp TIMESTAMP NOT NULL,
+    data JSON,
+    metadata JSON,
+    error TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_aggregate_id (aggregate_id)
+);
+
+CREATE TABLE IF NOT EXISTS ha_load_balancer_backends (
+    id VARCHAR(100) PRIMARY KEY,
+    url VARCHAR(255) NOT NULL,
+    weight INT NOT NULL DEFAULT 0,
+    region VARCHAR(50),
+    tags JSON,
+    metadata JSON,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    INDEX idx_region (region)
+);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id VARCHAR(36) PRIMARY KEY,
+    event VARCHAR(100) NOT NULL,
+    url VARCHAR(2048) NOT NULL,
+    payload JSON NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    max_attempts INT NOT NULL,
+    next_attempt_at TIMESTAMP NULL,
+    last_status_code INT,
+    last_error TEXT,
+    delivered_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    INDEX idx_event (event),
+    INDEX idx_status_created (status, created_at),
+    INDEX idx_next_attempt_at (next_attempt_at)
+);
+
+CREATE TABLE IF NOT EXISTS webhook_delivery_attempts (
+    id VARCHAR(36) PRIMARY KEY,
+    delivery_id VARCHAR(36) NOT NULL,
+    attempt INT NOT NULL,
+    status_code INT,
+    error TEXT,
+    duration_ms BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL,
+    INDEX idx_delivery_id (delivery_id),
+    FOREIGN KEY (delivery_id) REFERENCES webhook_deliveries(id) ON DELETE CASCADE
+);
+
 CREATE TABLE IF NOT EXISTS audit_logs (
-    id BIGINT PRIMARY KEY AUTO_INCREMENT,
-    entity_type VARCHAR(50) NOT NULL,
-    entity_id VARCHAR(36) NOT NULL,
-    action VARCHAR(50) NOT NULL,
-    details JSON,
-    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-    INDEX idx_entity (entity_type, entity_id),
-    INDEX idx_created_at (created_at)
+    id VARCHAR(36) PRIMARY KEY,
+    actor_id VARCHAR(64) NOT NULL,
+    actor_role VARCHAR(32),
+    action VARCHAR(255) NOT NULL,
+    target VARCHAR(255),
+    path VARCHAR(255) NOT NULL,
+    status_code INT NOT NULL,
+    request_id VARCHAR(64),
+    client_ip VARCHAR(64),
+    timestamp TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_actor_id (actor_id),
+    INDEX idx_action (action),
+    INDEX idx_request_id (request_id),
+    INDEX idx_timestamp (timestamp)
 );
\ No newline at end of file
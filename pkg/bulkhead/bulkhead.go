@@ -0,0 +1,147 @@
+package bulkhead
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// İşlem sınıfları; pahalı işlemler ayrı bölmelerde sınırlanır ki ucuz istekleri aç bırakmasınlar
+const (
+	OperationBatch  = "batch"
+	OperationReplay = "replay"
+	OperationExport = "export"
+)
+
+var (
+	// ErrBulkheadFull bölmedeki tüm yerler doluyken döner; istek kuyruğa alınmaz
+	ErrBulkheadFull = errors.New("bulkhead is full")
+	// ErrInvalidLimits sınır tanımı çözülemediğinde döner
+	ErrInvalidLimits = errors.New("invalid bulkhead limits")
+)
+
+// Bulkhead bir işlem sınıfının eşzamanlı çalışma sayısını semafor ile sınırlar. Dolu bölmeye
+// gelen istekler beklemeden reddedilir; böylece yük altında bekleyen istekler sınırsız birikmez.
+type Bulkhead struct {
+	name          string
+	maxConcurrent int
+	slots         chan struct{}
+	accepted      atomic.Uint64
+	rejected      atomic.Uint64
+}
+
+func New(name string, maxConcurrent int) *Bulkhead {
+	if maxConcurrent <= 0 {
+		maxConcurrent = 1
+	}
+	return &Bulkhead{
+		name:          name,
+		maxConcurrent: maxConcurrent,
+		slots:         make(chan struct{}, maxConcurrent),
+	}
+}
+
+func (b *Bulkhead) Name() string {
+	return b.name
+}
+
+// TryAcquire boş yer varsa alır ve true döner; başarılı her çağrıdan sonra Release çağrılmalıdır
+func (b *Bulkhead) TryAcquire() bool {
+	select {
+	case b.slots <- struct{}{}:
+		b.accepted.Add(1)
+		return true
+	default:
+		b.rejected.Add(1)
+		return false
+	}
+}
+
+func (b *Bulkhead) Release() {
+	<-b.slots
+}
+
+// Execute fn'i bölmede yer varsa çalıştırır; yoksa fn'i çağırmadan ErrBulkheadFull döner.
+// Circuit breaker ile birlikte kullanıldığında bulkhead dışta olmalıdır: reddedilen istekler
+// breaker'a hiç ulaşmaz ve aşağı akış hatası sayılmaz. FallbackManager ile sarılan çağrılarda
+// ErrBulkheadFull degrade yola düşer.
+func (b *Bulkhead) Execute(fn func() error) error {
+	if !b.TryAcquire() {
+		return fmt.Errorf("%w: %s", ErrBulkheadFull, b.name)
+	}
+	defer b.Release()
+
+	return fn()
+}
+
+func (b *Bulkhead) InFlight() int {
+	return len(b.slots)
+}
+
+func (b *Bulkhead) GetStats() map[string]interface{} {
+	return map[string]interface{}{
+		"name":           b.name,
+		"max_concurrent": b.maxConcurrent,
+		"in_flight":      b.InFlight(),
+		"accepted":       b.accepted.Load(),
+		"rejected":       b.rejected.Load(),
+	}
+}
+
+// Registry işlem sınıfı adına göre bulkhead'leri tutar; oluşturulduktan sonra değişmez
+type Registry struct {
+	bulkheads map[string]*Bulkhead
+}
+
+func NewRegistry(limits map[string]int) *Registry {
+	r := &Registry{bulkheads: make(map[string]*Bulkhead, len(limits))}
+	for name, limit := range limits {
+		r.bulkheads[name] = New(name, limit)
+	}
+	return r
+}
+
+// Get sınıfın bulkhead'ini döner; sınıf için sınır tanımlı değilse nil döner ve sınırlama yapılmaz
+func (r *Registry) Get(name string) *Bulkhead {
+	if r == nil {
+		return nil
+	}
+	return r.bulkheads[name]
+}
+
+func (r *Registry) GetStats() map[string]interface{} {
+	stats := make(map[string]interface{})
+	if r == nil {
+		return stats
+	}
+
+	for name, b := range r.bulkheads {
+		stats[name] = b.GetStats()
+	}
+	return stats
+}
+
+// ParseLimits "batch=4,replay=1" biçimindeki tanımı çözer; sınırlar pozitif olmalıdır
+func ParseLimits(spec string) (map[string]int, error) {
+	limits := make(map[string]int)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		name, value, ok := strings.Cut(entry, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidLimits, entry)
+		}
+		limit, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || limit <= 0 {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidLimits, entry)
+		}
+		limits[name] = limit
+	}
+	return limits, nil
+}
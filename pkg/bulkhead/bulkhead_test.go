@@ -0,0 +1,103 @@
+package bulkhead
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestBulkheadExecute(t *testing.T) {
+	tests := []struct {
+		name         string
+		limit        int
+		inFlight     int
+		wantErr      error
+		wantAccepted uint64
+		wantRejected uint64
+	}{
+		{name: "boş bölme", limit: 3, wantAccepted: 1},
+		{name: "son yer", limit: 3, inFlight: 2, wantAccepted: 3},
+		{name: "dolu bölme beklemeden reddeder", limit: 3, inFlight: 3, wantErr: ErrBulkheadFull, wantAccepted: 3, wantRejected: 1},
+		{name: "sıfır sınır bire yükseltilir", limit: 0, inFlight: 1, wantErr: ErrBulkheadFull, wantAccepted: 1, wantRejected: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := New(OperationBatch, tt.limit)
+
+			release := make(chan struct{})
+			var started, done sync.WaitGroup
+			for i := 0; i < tt.inFlight; i++ {
+				started.Add(1)
+				done.Add(1)
+				go func() {
+					defer done.Done()
+					b.Execute(func() error {
+						started.Done()
+						<-release
+						return nil
+					})
+				}()
+			}
+			started.Wait()
+
+			called := false
+			err := b.Execute(func() error {
+				called = true
+				return nil
+			})
+			close(release)
+			done.Wait()
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Execute = %v, beklenen %v", err, tt.wantErr)
+			}
+			if called != (tt.wantErr == nil) {
+				t.Errorf("fn çağrıldı: %v, reddedilen istekte çağrılmamalı", called)
+			}
+			if b.InFlight() != 0 {
+				t.Errorf("InFlight = %d, tüm yerler bırakılmalı", b.InFlight())
+			}
+			stats := b.GetStats()
+			if stats["accepted"] != tt.wantAccepted || stats["rejected"] != tt.wantRejected {
+				t.Errorf("kabul/ret = %v/%v, beklenen %v/%v", stats["accepted"], stats["rejected"], tt.wantAccepted, tt.wantRejected)
+			}
+		})
+	}
+}
+
+func TestParseLimits(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		want    map[string]int
+		wantErr bool
+	}{
+		{name: "boş tanım", spec: "", want: map[string]int{}},
+		{name: "birden fazla sınıf", spec: "batch=4, replay=1,export=2", want: map[string]int{"batch": 4, "replay": 1, "export": 2}},
+		{name: "boş girdiler atlanır", spec: "batch=4,,", want: map[string]int{"batch": 4}},
+		{name: "eşittir yok", spec: "batch", wantErr: true},
+		{name: "sıfır sınır", spec: "batch=0", wantErr: true},
+		{name: "sayı değil", spec: "batch=çok", wantErr: true},
+		{name: "isimsiz sınıf", spec: "=3", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseLimits(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseLimits(%q) hatası = %v, hata bekleniyor: %v", tt.spec, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidLimits) {
+					t.Errorf("hata = %v, beklenen ErrInvalidLimits", err)
+				}
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseLimits(%q) = %v, beklenen %v", tt.spec, got, tt.want)
+			}
+		})
+	}
+}
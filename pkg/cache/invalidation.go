@@ -33,7 +33,7 @@ func NewCacheInvalidator(cache *RedisCache, logger domain.Logger) *CacheInvalida
 		cache:      cache,
 		keyGen:     NewCacheKeyGenerator(),
 		patternGen: NewCachePatternGenerator(),
-		logger:     logger,
+		logger:     domain.LoggerOrNop(logger),
 	}
 }
 
@@ -265,6 +265,40 @@ func (b *InvalidationRuleBuilder) Build() []InvalidationRule {
 	return b.rules
 }
 
+const (
+	EntityTypeUser            = "user"
+	EntityTypeTransaction     = "transaction"
+	EntityTypeBalance         = "balance"
+	EntityTypeEvent           = "event"
+	EntityTypeAggregateEvents = "aggregate_events"
+)
+
+// KnownEntityTypes toplu invalidation için desteklenen entity tipleri
+var KnownEntityTypes = []string{
+	EntityTypeUser,
+	EntityTypeTransaction,
+	EntityTypeBalance,
+	EntityTypeEvent,
+	EntityTypeAggregateEvents,
+}
+
+func IsKnownEntityType(entityType string) bool {
+	for _, known := range KnownEntityTypes {
+		if known == entityType {
+			return true
+		}
+	}
+	return false
+}
+
+type BatchInvalidationResult struct {
+	EntityType    string `json:"entity_type"`
+	EntityCount   int    `json:"entity_count"`
+	RulesCount    int    `json:"rules_count"`
+	PatternsCount int    `json:"patterns_count"`
+	KeysCount     int    `json:"keys_count"`
+}
+
 type BatchInvalidator struct {
 	invalidator *CacheInvalidator
 	logger      domain.Logger
@@ -273,7 +307,7 @@ type BatchInvalidator struct {
 func NewBatchInvalidator(invalidator *CacheInvalidator, logger domain.Logger) *BatchInvalidator {
 	return &BatchInvalidator{
 		invalidator: invalidator,
-		logger:      logger,
+		logger:      domain.LoggerOrNop(logger),
 	}
 }
 
@@ -312,38 +346,50 @@ func (b *BatchInvalidator) InvalidateBatch(ctx context.Context, rules []Invalida
 	return nil
 }
 
-func (b *BatchInvalidator) InvalidateByEntityType(ctx context.Context, entityType string, entityIDs []uuid.UUID) error {
+func (b *BatchInvalidator) InvalidateByEntityType(ctx context.Context, entityType string, entityIDs []uuid.UUID) (*BatchInvalidationResult, error) {
+	if !IsKnownEntityType(entityType) {
+		return nil, fmt.Errorf("%w: %s", domain.ErrUnknownEntityType, entityType)
+	}
+
+	result := &BatchInvalidationResult{
+		EntityType:  entityType,
+		EntityCount: len(entityIDs),
+	}
 	if len(entityIDs) == 0 {
-		return nil
+		return result, nil
 	}
 
 	builder := NewInvalidationRuleBuilder()
 
 	switch entityType {
-	case "user":
+	case EntityTypeUser:
 		for _, userID := range entityIDs {
 			builder.AddUserRule(userID)
 		}
-	case "transaction":
+	case EntityTypeTransaction:
 		for _, transactionID := range entityIDs {
 			builder.AddTransactionRule(transactionID)
 		}
-	case "balance":
+	case EntityTypeBalance:
 		for _, userID := range entityIDs {
 			builder.AddBalanceRule(userID)
 		}
-	case "event":
+	case EntityTypeEvent:
 		for _, eventID := range entityIDs {
 			builder.AddEventRule(eventID)
 		}
-	case "aggregate_events":
+	case EntityTypeAggregateEvents:
 		for _, aggregateID := range entityIDs {
 			builder.AddAggregateEventsRule(aggregateID)
 		}
-	default:
-		return fmt.Errorf("unknown entity type: %s", entityType)
 	}
 
 	rules := builder.Build()
-	return b.InvalidateBatch(ctx, rules)
+	for _, rule := range rules {
+		result.PatternsCount += len(rule.Patterns)
+		result.KeysCount += len(rule.Keys)
+	}
+	result.RulesCount = len(rules)
+
+	return result, b.InvalidateBatch(ctx, rules)
 }
@@ -0,0 +1,92 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"transaction-api-w-go/pkg/domain"
+
+	"github.com/google/uuid"
+)
+
+func TestBatchInvalidatorInvalidateByEntityType(t *testing.T) {
+	keys := NewCacheKeyGenerator()
+	users := []uuid.UUID{uuid.New(), uuid.New()}
+	transactions := []uuid.UUID{uuid.New(), uuid.New()}
+	untouchedUser, untouchedTransaction := uuid.New(), uuid.New()
+
+	userKeys := func(userID uuid.UUID) []string {
+		return []string{keys.UserKey(userID), keys.UserTransactionsKey(userID, 20, 0), keys.BalanceKey(userID)}
+	}
+
+	tests := []struct {
+		name         string
+		entityType   string
+		ids          []uuid.UUID
+		wantDeleted  []string
+		wantKept     []string
+		wantPatterns int
+		wantErr      error
+	}{
+		{
+			name:         "kullanıcılar",
+			entityType:   EntityTypeUser,
+			ids:          users,
+			wantDeleted:  append(userKeys(users[0]), userKeys(users[1])...),
+			wantKept:     append(userKeys(untouchedUser), keys.TransactionKey(transactions[0])),
+			wantPatterns: 6,
+		},
+		{
+			name:         "işlemler",
+			entityType:   EntityTypeTransaction,
+			ids:          transactions,
+			wantDeleted:  []string{keys.TransactionKey(transactions[0]), keys.TransactionKey(transactions[1])},
+			wantKept:     append(userKeys(users[0]), keys.TransactionKey(untouchedTransaction)),
+			wantPatterns: 2,
+		},
+		{
+			name:       "bilinmeyen tip",
+			entityType: "invoice",
+			ids:        users,
+			wantKept:   append(userKeys(users[0]), keys.TransactionKey(transactions[0])),
+			wantErr:    domain.ErrUnknownEntityType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache, server := newTestRedisCache(t)
+			for _, userID := range append(users, untouchedUser) {
+				for _, key := range userKeys(userID) {
+					server.Set(key, "{}")
+				}
+			}
+			for _, transactionID := range append(transactions, untouchedTransaction) {
+				server.Set(keys.TransactionKey(transactionID), "{}")
+			}
+
+			batch := NewBatchInvalidator(NewCacheInvalidator(cache, nil), nil)
+			result, err := batch.InvalidateByEntityType(context.Background(), tt.entityType, tt.ids)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("InvalidateByEntityType = %v, beklenen %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil {
+				if result.EntityCount != len(tt.ids) || result.RulesCount != len(tt.ids) || result.PatternsCount != tt.wantPatterns {
+					t.Errorf("sonuç = %+v, beklenen %d entity, %d kural, %d desen", result, len(tt.ids), len(tt.ids), tt.wantPatterns)
+				}
+			}
+
+			for _, key := range tt.wantDeleted {
+				if server.Exists(key) {
+					t.Errorf("%s silinmedi", key)
+				}
+			}
+			for _, key := range tt.wantKept {
+				if !server.Exists(key) {
+					t.Errorf("%s silinmemeliydi", key)
+				}
+			}
+		})
+	}
+}
@@ -2,10 +2,15 @@ package cache
 
 import (
 	"context"
-	"encoding/json"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
+	"os"
+	"sync"
 	"time"
 
+	"transaction-api-w-go/pkg/circuitbreaker"
 	"transaction-api-w-go/pkg/domain"
 
 	"github.com/go-redis/redis/v8"
@@ -13,50 +18,283 @@ import (
 )
 
 type RedisCache struct {
-	client *redis.Client
-	logger domain.Logger
+	client redis.UniversalClient
+	// cluster yalnızca cluster modunda doludur; SCAN gibi komutların tüm shard'larda
+	// çalıştırılması için kullanılır
+	cluster *redis.ClusterClient
+	logger  domain.Logger
+	// opTimeout deadline'ı olmayan context'lerle yapılan işlemlere uygulanır
+	opTimeout time.Duration
+	// breaker Redis kesintisinde komutları göndermeden kısa devre yapar; nil ise devre dışıdır
+	breaker *circuitbreaker.CircuitBreaker
+	// serializer Set/Get değerlerini kodlar; okuma her zaman değerin yazıldığı biçime göre yapılır
+	serializer Serializer
+}
+
+// BreakerName Redis cache circuit breaker'ının adı
+const BreakerName = "redis_cache"
+
+// DefaultBreakerConfig Redis cache için circuit breaker ayarları; cache kaybı yalnızca
+// gecikmeye yol açtığından breaker hızlı açılır ve kısa aralıklarla yeniden denenir
+func DefaultBreakerConfig() circuitbreaker.Config {
+	return circuitbreaker.Config{
+		FailureThreshold:    5,
+		SuccessThreshold:    2,
+		Timeout:             10 * time.Second,
+		HalfOpenMaxRequests: 3,
+		WindowSize:          10 * time.Second,
+		MinRequestCount:     5,
+	}
 }
 
+type RedisMode string
+
+const (
+	RedisModeSingle   RedisMode = "single"
+	RedisModeCluster  RedisMode = "cluster"
+	RedisModeSentinel RedisMode = "sentinel"
+)
+
 type CacheConfig struct {
-	Host     string
-	Port     int
-	Password string
-	DB       int
-	PoolSize int
+	Mode RedisMode
+	Host string
+	Port int
+	// Addrs cluster node'ları veya sentinel adresleri; boşsa Host:Port kullanılır
+	Addrs            []string
+	MasterName       string
+	SentinelPassword string
+	Username         string
+	Password         string
+	DB               int
+	PoolSize         int
+
+	// TLS yönetilen (managed) Redis servisleri için
+	TLSEnabled            bool
+	TLSCAFile             string
+	TLSInsecureSkipVerify bool
+	TLSServerName         string
+
+	// OperationTimeout çağıranın context'inde deadline yoksa her işleme uygulanan süre;
+	// sıfırsa domain.DefaultCacheTimeout kullanılır
+	OperationTimeout time.Duration
+
+	// Bağlantı havuzu ve soket zaman aşımları; sıfır değerlerde go-redis varsayılanları geçerlidir.
+	// MaxRetries -1 ise başarısız komutlar yeniden denenmez
+	DialTimeout  time.Duration
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+	MinIdleConns int
+	MaxRetries   int
+
+	// Format değerlerin yazılacağı biçim; boşsa JSON kullanılır
+	Format SerializationFormat
 }
 
 func NewRedisCache(config CacheConfig, logger domain.Logger) (*RedisCache, error) {
-	client := redis.NewClient(&redis.Options{
-		Addr:     fmt.Sprintf("%s:%d", config.Host, config.Port),
-		Password: config.Password,
-		DB:       config.DB,
-		PoolSize: config.PoolSize,
-	})
+	return NewRedisCacheWithContext(context.Background(), config, logger)
+}
+
+// NewRedisCacheWithContext bağlantı kontrolünü (PING) verilen context ile yapar;
+// context'in deadline'ı yoksa OperationTimeout uygulanır
+func NewRedisCacheWithContext(ctx context.Context, config CacheConfig, logger domain.Logger) (*RedisCache, error) {
+	tlsConfig, err := buildTLSConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	serializer, err := NewSerializer(config.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	var client redis.UniversalClient
+	var cluster *redis.ClusterClient
+
+	addrs := config.Addrs
+	if len(addrs) == 0 {
+		addrs = []string{fmt.Sprintf("%s:%d", config.Host, config.Port)}
+	}
+
+	switch config.Mode {
+	case RedisModeCluster:
+		cluster = redis.NewClusterClient(&redis.ClusterOptions{
+			Addrs:        addrs,
+			Username:     config.Username,
+			Password:     config.Password,
+			PoolSize:     config.PoolSize,
+			MinIdleConns: config.MinIdleConns,
+			MaxRetries:   config.MaxRetries,
+			DialTimeout:  config.DialTimeout,
+			ReadTimeout:  config.ReadTimeout,
+			WriteTimeout: config.WriteTimeout,
+			TLSConfig:    tlsConfig,
+		})
+		client = cluster
+	case RedisModeSentinel:
+		if config.MasterName == "" {
+			return nil, fmt.Errorf("sentinel mode requires a master name")
+		}
+		client = redis.NewFailoverClient(&redis.FailoverOptions{
+			MasterName:       config.MasterName,
+			SentinelAddrs:    addrs,
+			SentinelPassword: config.SentinelPassword,
+			Username:         config.Username,
+			Password:         config.Password,
+			DB:               config.DB,
+			PoolSize:         config.PoolSize,
+			MinIdleConns:     config.MinIdleConns,
+			MaxRetries:       config.MaxRetries,
+			DialTimeout:      config.DialTimeout,
+			ReadTimeout:      config.ReadTimeout,
+			WriteTimeout:     config.WriteTimeout,
+			TLSConfig:        tlsConfig,
+		})
+	case RedisModeSingle, "":
+		client = redis.NewClient(&redis.Options{
+			Addr:         addrs[0],
+			Username:     config.Username,
+			Password:     config.Password,
+			DB:           config.DB,
+			PoolSize:     config.PoolSize,
+			MinIdleConns: config.MinIdleConns,
+			MaxRetries:   config.MaxRetries,
+			DialTimeout:  config.DialTimeout,
+			ReadTimeout:  config.ReadTimeout,
+			WriteTimeout: config.WriteTimeout,
+			TLSConfig:    tlsConfig,
+		})
+	default:
+		return nil, fmt.Errorf("unknown Redis mode: %s", config.Mode)
+	}
+
+	opTimeout := config.OperationTimeout
+	if opTimeout <= 0 {
+		opTimeout = domain.DefaultCacheTimeout
+	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	pingCtx, cancel := domain.WithDefaultTimeout(ctx, opTimeout)
 	defer cancel()
 
-	if err := client.Ping(ctx).Err(); err != nil {
+	if err := client.Ping(pingCtx).Err(); err != nil {
+		client.Close()
 		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
 	}
 
 	return &RedisCache{
-		client: client,
-		logger: logger,
+		client:     client,
+		cluster:    cluster,
+		logger:     domain.LoggerOrNop(logger),
+		opTimeout:  opTimeout,
+		serializer: serializer,
 	}, nil
 }
 
+// SetCircuitBreaker Redis komutlarını verilen breaker üzerinden çalıştırır
+func (c *RedisCache) SetCircuitBreaker(breaker *circuitbreaker.CircuitBreaker) {
+	c.breaker = breaker
+}
+
+// CircuitBreaker kullanılan breaker'ı döner; ayarlanmamışsa nil
+func (c *RedisCache) CircuitBreaker() *circuitbreaker.CircuitBreaker {
+	return c.breaker
+}
+
+// execute fn'i circuit breaker üzerinden çalıştırır. Breaker açıksa fn çağrılmadan
+// ErrCacheConnection döner ve çağıran doğrudan veritabanına düşer. redis.Nil (cache miss)
+// ve çağıranın iptal ettiği context'ler Redis hatası sayılmaz.
+func (c *RedisCache) execute(fn func() error) error {
+	if c.breaker == nil {
+		return fn()
+	}
+
+	called := false
+	var opErr error
+	err := c.breaker.Execute(func() error {
+		called = true
+		opErr = fn()
+		if opErr == redis.Nil || errors.Is(opErr, context.Canceled) {
+			return nil
+		}
+		return opErr
+	})
+	if !called {
+		return fmt.Errorf("%w: %v", domain.ErrCacheConnection, err)
+	}
+	return opErr
+}
+
+// withTimeout isteğin deadline'ını korur; deadline yoksa opTimeout ekler
+func (c *RedisCache) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return domain.WithDefaultTimeout(ctx, c.opTimeout)
+}
+
+// buildTLSConfig TLS kapalıysa nil döndürür; CA dosyası verilmişse sistem havuzu yerine onu kullanır
+func buildTLSConfig(config CacheConfig) (*tls.Config, error) {
+	if !config.TLSEnabled {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         config.TLSServerName,
+		InsecureSkipVerify: config.TLSInsecureSkipVerify,
+	}
+	if tlsConfig.ServerName == "" {
+		tlsConfig.ServerName = config.Host
+	}
+
+	if config.TLSCAFile != "" {
+		caCert, err := os.ReadFile(config.TLSCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read Redis CA file: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("failed to parse Redis CA file %s", config.TLSCAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	return tlsConfig, nil
+}
+
+// forEachNode fn'i cluster modunda her master shard için, diğer modlarda tek istemci için çalıştırır
+func (c *RedisCache) forEachNode(ctx context.Context, fn func(ctx context.Context, node redis.Cmdable) error) error {
+	if c.cluster != nil {
+		return c.cluster.ForEachMaster(ctx, func(ctx context.Context, node *redis.Client) error {
+			return fn(ctx, node)
+		})
+	}
+	return fn(ctx, c.client)
+}
+
+// deleteKeys anahtarları tek tek DEL komutlarıyla pipeline üzerinden siler;
+// böylece cluster modunda CROSSSLOT hatası oluşmaz
+func (c *RedisCache) deleteKeys(ctx context.Context, keys []string) error {
+	pipe := c.client.Pipeline()
+	for _, key := range keys {
+		pipe.Del(ctx, key)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
 func (c *RedisCache) Close() error {
 	return c.client.Close()
 }
 
 func (c *RedisCache) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
-	data, err := json.Marshal(value)
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	data, err := c.serializer.Marshal(value)
 	if err != nil {
 		return fmt.Errorf("failed to marshal value: %w", err)
 	}
 
-	err = c.client.Set(ctx, key, data, expiration).Err()
+	err = c.execute(func() error {
+		return c.client.Set(ctx, key, data, expiration).Err()
+	})
 	if err != nil {
 		return fmt.Errorf("failed to set cache key %s: %w", key, err)
 	}
@@ -66,7 +304,15 @@ func (c *RedisCache) Set(ctx context.Context, key string, value interface{}, exp
 }
 
 func (c *RedisCache) Get(ctx context.Context, key string, dest interface{}) error {
-	data, err := c.client.Get(ctx, key).Bytes()
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	var data []byte
+	err := c.execute(func() error {
+		var err error
+		data, err = c.client.Get(ctx, key).Bytes()
+		return err
+	})
 	if err != nil {
 		if err == redis.Nil {
 			return domain.ErrCacheMiss
@@ -74,7 +320,7 @@ func (c *RedisCache) Get(ctx context.Context, key string, dest interface{}) erro
 		return fmt.Errorf("failed to get cache key %s: %w", key, err)
 	}
 
-	if err := json.Unmarshal(data, dest); err != nil {
+	if err := c.serializer.Unmarshal(data, dest); err != nil {
 		return fmt.Errorf("failed to unmarshal cached value: %w", err)
 	}
 
@@ -83,7 +329,12 @@ func (c *RedisCache) Get(ctx context.Context, key string, dest interface{}) erro
 }
 
 func (c *RedisCache) Delete(ctx context.Context, key string) error {
-	err := c.client.Del(ctx, key).Err()
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	err := c.execute(func() error {
+		return c.client.Del(ctx, key).Err()
+	})
 	if err != nil {
 		return fmt.Errorf("failed to delete cache key %s: %w", key, err)
 	}
@@ -92,16 +343,51 @@ func (c *RedisCache) Delete(ctx context.Context, key string) error {
 	return nil
 }
 
+// Expire anahtarı desen kullanmadan, tam adıyla hemen siler ve silmeden önce var olup olmadığını döner
+func (c *RedisCache) Expire(ctx context.Context, key string) (bool, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	var deleted int64
+	err := c.execute(func() error {
+		var err error
+		deleted, err = c.client.Del(ctx, key).Result()
+		return err
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to expire cache key %s: %w", key, err)
+	}
+
+	c.logger.Info("Cache key force-expired", "key", key, "existed", deleted > 0)
+	return deleted > 0, nil
+}
+
 func (c *RedisCache) DeletePattern(ctx context.Context, pattern string) error {
-	iter := c.client.Scan(ctx, 0, pattern, 0).Iterator()
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	var mu sync.Mutex
 	var keys []string
 
-	for iter.Next(ctx) {
-		keys = append(keys, iter.Val())
+	err := c.execute(func() error {
+		return c.forEachNode(ctx, func(ctx context.Context, node redis.Cmdable) error {
+			iter := node.Scan(ctx, 0, pattern, 0).Iterator()
+			for iter.Next(ctx) {
+				mu.Lock()
+				keys = append(keys, iter.Val())
+				mu.Unlock()
+			}
+			return iter.Err()
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("failed to scan cache pattern %s: %w", pattern, err)
 	}
 
 	if len(keys) > 0 {
-		err := c.client.Del(ctx, keys...).Err()
+		err := c.execute(func() error {
+			return c.deleteKeys(ctx, keys)
+		})
 		if err != nil {
 			return fmt.Errorf("failed to delete cache pattern %s: %w", pattern, err)
 		}
@@ -112,7 +398,15 @@ func (c *RedisCache) DeletePattern(ctx context.Context, pattern string) error {
 }
 
 func (c *RedisCache) Exists(ctx context.Context, key string) (bool, error) {
-	result, err := c.client.Exists(ctx, key).Result()
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	var result int64
+	err := c.execute(func() error {
+		var err error
+		result, err = c.client.Exists(ctx, key).Result()
+		return err
+	})
 	if err != nil {
 		return false, fmt.Errorf("failed to check cache key existence %s: %w", key, err)
 	}
@@ -121,12 +415,20 @@ func (c *RedisCache) Exists(ctx context.Context, key string) (bool, error) {
 }
 
 func (c *RedisCache) SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error) {
-	data, err := json.Marshal(value)
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	data, err := c.serializer.Marshal(value)
 	if err != nil {
 		return false, fmt.Errorf("failed to marshal value: %w", err)
 	}
 
-	result, err := c.client.SetNX(ctx, key, data, expiration).Result()
+	var result bool
+	err = c.execute(func() error {
+		var err error
+		result, err = c.client.SetNX(ctx, key, data, expiration).Result()
+		return err
+	})
 	if err != nil {
 		return false, fmt.Errorf("failed to set NX cache key %s: %w", key, err)
 	}
@@ -136,7 +438,15 @@ func (c *RedisCache) SetNX(ctx context.Context, key string, value interface{}, e
 }
 
 func (c *RedisCache) Increment(ctx context.Context, key string, value int64) (int64, error) {
-	result, err := c.client.IncrBy(ctx, key, value).Result()
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	var result int64
+	err := c.execute(func() error {
+		var err error
+		result, err = c.client.IncrBy(ctx, key, value).Result()
+		return err
+	})
 	if err != nil {
 		return 0, fmt.Errorf("failed to increment cache key %s: %w", key, err)
 	}
@@ -145,7 +455,15 @@ func (c *RedisCache) Increment(ctx context.Context, key string, value int64) (in
 }
 
 func (c *RedisCache) GetTTL(ctx context.Context, key string) (time.Duration, error) {
-	ttl, err := c.client.TTL(ctx, key).Result()
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	var ttl time.Duration
+	err := c.execute(func() error {
+		var err error
+		ttl, err = c.client.TTL(ctx, key).Result()
+		return err
+	})
 	if err != nil {
 		return 0, fmt.Errorf("failed to get TTL for cache key %s: %w", key, err)
 	}
@@ -154,7 +472,14 @@ func (c *RedisCache) GetTTL(ctx context.Context, key string) (time.Duration, err
 }
 
 func (c *RedisCache) FlushAll(ctx context.Context) error {
-	err := c.client.FlushAll(ctx).Err()
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	err := c.execute(func() error {
+		return c.forEachNode(ctx, func(ctx context.Context, node redis.Cmdable) error {
+			return node.FlushAll(ctx).Err()
+		})
+	})
 	if err != nil {
 		return fmt.Errorf("failed to flush all cache: %w", err)
 	}
@@ -164,7 +489,15 @@ func (c *RedisCache) FlushAll(ctx context.Context) error {
 }
 
 func (c *RedisCache) GetStats(ctx context.Context) (*CacheStats, error) {
-	info, err := c.client.Info(ctx, "stats").Result()
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	var info string
+	err := c.execute(func() error {
+		var err error
+		info, err = c.client.Info(ctx, "stats").Result()
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get Redis stats: %w", err)
 	}
@@ -173,7 +506,20 @@ func (c *RedisCache) GetStats(ctx context.Context) (*CacheStats, error) {
 		Info: info,
 	}
 
-	dbSize, err := c.client.DBSize(ctx).Result()
+	var mu sync.Mutex
+	var dbSize int64
+	err = c.execute(func() error {
+		return c.forEachNode(ctx, func(ctx context.Context, node redis.Cmdable) error {
+			size, err := node.DBSize(ctx).Result()
+			if err != nil {
+				return err
+			}
+			mu.Lock()
+			dbSize += size
+			mu.Unlock()
+			return nil
+		})
+	})
 	if err == nil {
 		stats.DBSize = dbSize
 	}
@@ -181,6 +527,102 @@ func (c *RedisCache) GetStats(ctx context.Context) (*CacheStats, error) {
 	return stats, nil
 }
 
+const (
+	DefaultKeyScanLimit = 100
+	MaxKeyScanLimit     = 1000
+
+	// scanBatchSize her SCAN çağrısında Redis'ten istenen anahtar sayısı
+	scanBatchSize = 100
+	// maxScanIterations KEYS benzeri uzun taramaları önlemek için SCAN çağrı sınırı
+	maxScanIterations = 200
+)
+
+type CacheKeyInfo struct {
+	Key        string `json:"key"`
+	TTL        string `json:"ttl"`
+	TTLSeconds int64  `json:"ttl_seconds"`
+}
+
+// ScanKeys pattern ile eşleşen anahtarları SCAN ile sınırlı sayıda döndürür.
+// KEYS kullanılmaz; tarama hem sonuç sayısı hem de iterasyon sayısı ile sınırlandırılır.
+func (c *RedisCache) ScanKeys(ctx context.Context, pattern string, limit int) ([]CacheKeyInfo, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	if limit <= 0 {
+		limit = DefaultKeyScanLimit
+	}
+	if limit > MaxKeyScanLimit {
+		limit = MaxKeyScanLimit
+	}
+
+	var mu sync.Mutex
+	var keys []string
+	err := c.execute(func() error {
+		return c.forEachNode(ctx, func(ctx context.Context, node redis.Cmdable) error {
+			var cursor uint64
+			for i := 0; i < maxScanIterations; i++ {
+				mu.Lock()
+				full := len(keys) >= limit
+				mu.Unlock()
+				if full {
+					return nil
+				}
+
+				batch, next, err := node.Scan(ctx, cursor, pattern, scanBatchSize).Result()
+				if err != nil {
+					return err
+				}
+
+				mu.Lock()
+				keys = append(keys, batch...)
+				mu.Unlock()
+
+				cursor = next
+				if cursor == 0 {
+					return nil
+				}
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan cache pattern %s: %w", pattern, err)
+	}
+
+	if len(keys) > limit {
+		keys = keys[:limit]
+	}
+	if len(keys) == 0 {
+		return []CacheKeyInfo{}, nil
+	}
+
+	pipe := c.client.Pipeline()
+	ttlCmds := make([]*redis.DurationCmd, len(keys))
+	for i, key := range keys {
+		ttlCmds[i] = pipe.TTL(ctx, key)
+	}
+	err = c.execute(func() error {
+		_, err := pipe.Exec(ctx)
+		return err
+	})
+	if err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("failed to get TTLs for cache pattern %s: %w", pattern, err)
+	}
+
+	result := make([]CacheKeyInfo, 0, len(keys))
+	for i, key := range keys {
+		ttl := ttlCmds[i].Val()
+		result = append(result, CacheKeyInfo{
+			Key:        key,
+			TTL:        ttl.String(),
+			TTLSeconds: int64(ttl.Seconds()),
+		})
+	}
+
+	return result, nil
+}
+
 type CacheStats struct {
 	Info   string `json:"info"`
 	DBSize int64  `json:"db_size"`
@@ -0,0 +1,569 @@
+package cache
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"transaction-api-w-go/pkg/circuitbreaker"
+	"transaction-api-w-go/pkg/domain"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/alicebob/miniredis/v2/server"
+	"github.com/go-redis/redis/v8"
+)
+
+// newTestRedisCache miniredis üzerinde çalışan, test bitince kapanan bir RedisCache döner
+func newTestRedisCache(t *testing.T) (*RedisCache, *miniredis.Miniredis) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	cache, err := NewRedisCache(CacheConfig{Addrs: []string{server.Addr()}}, nil)
+	if err != nil {
+		t.Fatalf("NewRedisCache: %v", err)
+	}
+	t.Cleanup(func() { cache.Close() })
+	return cache, server
+}
+
+func TestRedisCacheScanKeys(t *testing.T) {
+	cache, server := newTestRedisCache(t)
+	for i := 0; i < 250; i++ {
+		server.Set(fmt.Sprintf("user:%d", i), "{}")
+		server.SetTTL(fmt.Sprintf("user:%d", i), time.Hour)
+	}
+	for i := 0; i < 20; i++ {
+		server.Set(fmt.Sprintf("transaction:%d", i), "{}")
+	}
+
+	tests := []struct {
+		name      string
+		pattern   string
+		limit     int
+		wantCount int
+		wantTTL   bool
+	}{
+		{name: "limit sonucu sınırlar", pattern: "user:*", limit: 10, wantCount: 10, wantTTL: true},
+		{name: "varsayılan limit", pattern: "user:*", wantCount: DefaultKeyScanLimit, wantTTL: true},
+		{name: "limit eşleşenlerden büyük", pattern: "transaction:*", limit: 50, wantCount: 20},
+		{name: "eşleşme yok", pattern: "balance:*", limit: 10, wantCount: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys, err := cache.ScanKeys(context.Background(), tt.pattern, tt.limit)
+			if err != nil {
+				t.Fatalf("ScanKeys: %v", err)
+			}
+			if keys == nil || len(keys) != tt.wantCount {
+				t.Fatalf("anahtar sayısı = %d, beklenen %d", len(keys), tt.wantCount)
+			}
+
+			prefix := strings.TrimSuffix(tt.pattern, "*")
+			seen := make(map[string]bool, len(keys))
+			for _, key := range keys {
+				if !strings.HasPrefix(key.Key, prefix) {
+					t.Errorf("anahtar %q desene uymuyor", key.Key)
+				}
+				if seen[key.Key] {
+					t.Errorf("anahtar %q iki kez döndü", key.Key)
+				}
+				seen[key.Key] = true
+				if hasTTL := key.TTLSeconds > 0; hasTTL != tt.wantTTL {
+					t.Errorf("%s TTL = %ds, TTL bekleniyor: %v", key.Key, key.TTLSeconds, tt.wantTTL)
+				}
+			}
+		})
+	}
+}
+
+// writeTestCertificate 127.0.0.1 için kendinden imzalı bir sertifika üretir ve PEM'ini dosyaya yazar
+func writeTestCertificate(t *testing.T) (tls.Certificate, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("anahtar üretilemedi: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "redis-test"},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("sertifika üretilemedi: %v", err)
+	}
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatalf("CA dosyası yazılamadı: %v", err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, caFile
+}
+
+func TestBuildTLSConfig(t *testing.T) {
+	_, caFile := writeTestCertificate(t)
+	invalidCA := filepath.Join(t.TempDir(), "invalid.pem")
+	if err := os.WriteFile(invalidCA, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatalf("dosya yazılamadı: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		config         CacheConfig
+		wantNil        bool
+		wantServerName string
+		wantSkipVerify bool
+		wantRootCAs    bool
+		wantErr        bool
+	}{
+		{name: "TLS kapalı", config: CacheConfig{Host: "redis.internal"}, wantNil: true},
+		{name: "sunucu adı host'tan alınır", config: CacheConfig{Host: "redis.internal", TLSEnabled: true}, wantServerName: "redis.internal"},
+		{
+			name:           "açık sunucu adı ve doğrulama atlama",
+			config:         CacheConfig{Host: "10.0.0.1", TLSEnabled: true, TLSServerName: "redis.example.com", TLSInsecureSkipVerify: true},
+			wantServerName: "redis.example.com",
+			wantSkipVerify: true,
+		},
+		{name: "CA dosyası", config: CacheConfig{Host: "redis.internal", TLSEnabled: true, TLSCAFile: caFile}, wantServerName: "redis.internal", wantRootCAs: true},
+		{name: "okunamayan CA dosyası", config: CacheConfig{TLSEnabled: true, TLSCAFile: filepath.Join(t.TempDir(), "missing.pem")}, wantErr: true},
+		{name: "geçersiz CA dosyası", config: CacheConfig{TLSEnabled: true, TLSCAFile: invalidCA}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := buildTLSConfig(tt.config)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("buildTLSConfig = %v, hata bekleniyor: %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if (got == nil) != tt.wantNil {
+				t.Fatalf("TLS config = %v, nil bekleniyor: %v", got, tt.wantNil)
+			}
+			if got == nil {
+				return
+			}
+			if got.MinVersion != tls.VersionTLS12 || got.ServerName != tt.wantServerName || got.InsecureSkipVerify != tt.wantSkipVerify || (got.RootCAs != nil) != tt.wantRootCAs {
+				t.Errorf("TLS config = {MinVersion:%x ServerName:%q InsecureSkipVerify:%v RootCAs:%v}, beklenen {%q %v %v}",
+					got.MinVersion, got.ServerName, got.InsecureSkipVerify, got.RootCAs != nil, tt.wantServerName, tt.wantSkipVerify, tt.wantRootCAs)
+			}
+		})
+	}
+}
+
+func TestNewRedisCacheTLS(t *testing.T) {
+	certificate, caFile := writeTestCertificate(t)
+	server, err := miniredis.RunTLS(&tls.Config{Certificates: []tls.Certificate{certificate}})
+	if err != nil {
+		t.Fatalf("RunTLS: %v", err)
+	}
+	t.Cleanup(server.Close)
+
+	cache, err := NewRedisCache(CacheConfig{
+		Addrs:      []string{server.Addr()},
+		Host:       "127.0.0.1",
+		Username:   "default",
+		TLSEnabled: true,
+		TLSCAFile:  caFile,
+	}, nil)
+	if err != nil {
+		t.Fatalf("NewRedisCache: %v", err)
+	}
+	defer cache.Close()
+
+	options := cache.client.(*redis.Client).Options()
+	if options.TLSConfig == nil || options.TLSConfig.RootCAs == nil || options.TLSConfig.ServerName != "127.0.0.1" {
+		t.Errorf("istemcinin TLS ayarları eksik: %+v", options.TLSConfig)
+	}
+	if options.Username != "default" {
+		t.Errorf("Username = %q, beklenen default", options.Username)
+	}
+
+	ctx := context.Background()
+	if err := cache.Set(ctx, "key", "value", time.Minute); err != nil {
+		t.Fatalf("TLS üzerinden Set: %v", err)
+	}
+
+	// TLS'siz istemci TLS bekleyen sunucuya bağlanamaz
+	if _, err := NewRedisCache(CacheConfig{Addrs: []string{server.Addr()}, OperationTimeout: time.Second}, nil); err == nil {
+		t.Error("TLS kapalıyken bağlantı kurulmamalıydı")
+	}
+}
+
+// runTestSentinel master'ı master adresine yönlendiren, yalnızca istemcinin ihtiyaç duyduğu
+// SENTINEL komutlarını yanıtlayan sahte bir sentinel başlatır
+func runTestSentinel(t *testing.T, masterName, masterAddr string) string {
+	t.Helper()
+	sentinel, err := server.NewServer("127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("sentinel başlatılamadı: %v", err)
+	}
+	t.Cleanup(sentinel.Close)
+
+	host, port, _ := net.SplitHostPort(masterAddr)
+	err = sentinel.Register("SENTINEL", func(c *server.Peer, cmd string, args []string) {
+		switch {
+		case len(args) == 2 && strings.EqualFold(args[0], "get-master-addr-by-name") && args[1] == masterName:
+			c.WriteStrings([]string{host, port})
+		case len(args) == 2 && strings.EqualFold(args[0], "get-master-addr-by-name"):
+			c.WriteNull()
+		case len(args) == 2 && strings.EqualFold(args[0], "sentinels"):
+			c.WriteLen(0)
+		default:
+			c.WriteError("ERR unsupported sentinel command")
+		}
+	})
+	if err != nil {
+		t.Fatalf("SENTINEL kaydedilemedi: %v", err)
+	}
+	return sentinel.Addr().String()
+}
+
+func TestNewRedisCacheModes(t *testing.T) {
+	master := miniredis.RunT(t)
+	sentinelAddr := runTestSentinel(t, "mymaster", master.Addr())
+
+	tests := []struct {
+		name        string
+		config      CacheConfig
+		wantClient  string
+		wantCluster bool
+		wantErr     bool
+	}{
+		{name: "varsayılan tek node", config: CacheConfig{Addrs: []string{master.Addr()}}, wantClient: "*redis.Client"},
+		{name: "tek node", config: CacheConfig{Mode: RedisModeSingle, Addrs: []string{master.Addr()}}, wantClient: "*redis.Client"},
+		{name: "cluster", config: CacheConfig{Mode: RedisModeCluster, Addrs: []string{master.Addr()}}, wantClient: "*redis.ClusterClient", wantCluster: true},
+		{name: "sentinel", config: CacheConfig{Mode: RedisModeSentinel, Addrs: []string{sentinelAddr}, MasterName: "mymaster"}, wantClient: "*redis.Client"},
+		{name: "sentinel master adı olmadan", config: CacheConfig{Mode: RedisModeSentinel, Addrs: []string{sentinelAddr}}, wantErr: true},
+		{name: "bilinmeyen master", config: CacheConfig{Mode: RedisModeSentinel, Addrs: []string{sentinelAddr}, MasterName: "other", MaxRetries: -1}, wantErr: true},
+		{name: "bilinmeyen mod", config: CacheConfig{Mode: "ring", Addrs: []string{master.Addr()}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config.OperationTimeout = 2 * time.Second
+			cache, err := NewRedisCache(tt.config, nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewRedisCache = %v, hata bekleniyor: %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			defer cache.Close()
+
+			if got := fmt.Sprintf("%T", cache.client); got != tt.wantClient {
+				t.Errorf("istemci = %s, beklenen %s", got, tt.wantClient)
+			}
+			if (cache.cluster != nil) != tt.wantCluster {
+				t.Errorf("cluster istemcisi: %v, beklenen %v", cache.cluster != nil, tt.wantCluster)
+			}
+
+			// Metot yüzeyi modlardan bağımsız çalışmalı; tarama cluster'da tüm shard'ları gezer
+			ctx := context.Background()
+			key := "mode:" + strings.ReplaceAll(tt.name, " ", "-")
+			if err := cache.Set(ctx, key, "value", time.Minute); err != nil {
+				t.Fatalf("Set: %v", err)
+			}
+			var got string
+			if err := cache.Get(ctx, key, &got); err != nil || got != "value" {
+				t.Fatalf("Get = %q, %v", got, err)
+			}
+			keys, err := cache.ScanKeys(ctx, key, 10)
+			if err != nil || len(keys) != 1 {
+				t.Fatalf("ScanKeys = %v, %v", keys, err)
+			}
+			if err := cache.DeletePattern(ctx, key+"*"); err != nil {
+				t.Fatalf("DeletePattern: %v", err)
+			}
+			if master.Exists(key) {
+				t.Errorf("%s silinmedi", key)
+			}
+		})
+	}
+}
+
+// runStalledRedis PING'e yanıt veren ama GET'i release kapanana kadar bekleten sahte bir Redis başlatır
+func runStalledRedis(t *testing.T) string {
+	t.Helper()
+	stalled, err := server.NewServer("127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("sunucu başlatılamadı: %v", err)
+	}
+	t.Cleanup(stalled.Close)
+
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+
+	if err := stalled.Register("PING", func(c *server.Peer, cmd string, args []string) {
+		c.WriteInline("PONG")
+	}); err != nil {
+		t.Fatalf("PING kaydedilemedi: %v", err)
+	}
+	if err := stalled.Register("GET", func(c *server.Peer, cmd string, args []string) {
+		<-release
+		c.WriteNull()
+	}); err != nil {
+		t.Fatalf("GET kaydedilemedi: %v", err)
+	}
+	return stalled.Addr().String()
+}
+
+func TestRedisCacheGetHonorsContext(t *testing.T) {
+	tests := []struct {
+		name    string
+		context func() (context.Context, context.CancelFunc)
+		wantErr error
+	}{
+		{
+			name: "iptal edilen istek okumayı keser",
+			context: func() (context.Context, context.CancelFunc) {
+				ctx, cancel := context.WithCancel(context.Background())
+				time.AfterFunc(50*time.Millisecond, cancel)
+				return ctx, cancel
+			},
+			wantErr: context.Canceled,
+		},
+		{
+			name: "istek deadline'ı uygulanır",
+			context: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 50*time.Millisecond)
+			},
+			wantErr: context.DeadlineExceeded,
+		},
+		{
+			name: "deadline yoksa işlem timeout'u uygulanır",
+			context: func() (context.Context, context.CancelFunc) {
+				return context.Background(), func() {}
+			},
+			wantErr: context.DeadlineExceeded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache, err := NewRedisCache(CacheConfig{
+				Addrs:            []string{runStalledRedis(t)},
+				OperationTimeout: 200 * time.Millisecond,
+				ReadTimeout:      time.Minute,
+				MaxRetries:       -1,
+			}, nil)
+			if err != nil {
+				t.Fatalf("NewRedisCache: %v", err)
+			}
+			t.Cleanup(func() { cache.Close() })
+
+			ctx, cancel := tt.context()
+			defer cancel()
+
+			start := time.Now()
+			var value string
+			err = cache.Get(ctx, "slow", &value)
+			elapsed := time.Since(start)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Get = %v, beklenen %v", err, tt.wantErr)
+			}
+			if elapsed > 2*time.Second {
+				t.Errorf("Get %v sürdü; yavaş okuma kesilmedi", elapsed)
+			}
+		})
+	}
+}
+
+func TestNewRedisCachePoolOptions(t *testing.T) {
+	master := miniredis.RunT(t)
+	sentinelAddr := runTestSentinel(t, "mymaster", master.Addr())
+
+	pool := CacheConfig{
+		PoolSize:     7,
+		MinIdleConns: 3,
+		MaxRetries:   4,
+		DialTimeout:  time.Second,
+		ReadTimeout:  2 * time.Second,
+		WriteTimeout: 3 * time.Second,
+	}
+
+	tests := []struct {
+		name   string
+		mode   RedisMode
+		addr   string
+		master string
+	}{
+		{name: "tek node", mode: RedisModeSingle, addr: master.Addr()},
+		{name: "cluster", mode: RedisModeCluster, addr: master.Addr()},
+		{name: "sentinel", mode: RedisModeSentinel, addr: sentinelAddr, master: "mymaster"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := pool
+			config.Mode = tt.mode
+			config.Addrs = []string{tt.addr}
+			config.MasterName = tt.master
+			cache, err := NewRedisCache(config, nil)
+			if err != nil {
+				t.Fatalf("NewRedisCache: %v", err)
+			}
+			defer cache.Close()
+
+			type poolOptions struct {
+				PoolSize, MinIdleConns, MaxRetries     int
+				DialTimeout, ReadTimeout, WriteTimeout time.Duration
+			}
+			var got poolOptions
+			switch client := cache.client.(type) {
+			case *redis.Client:
+				o := client.Options()
+				got = poolOptions{o.PoolSize, o.MinIdleConns, o.MaxRetries, o.DialTimeout, o.ReadTimeout, o.WriteTimeout}
+			case *redis.ClusterClient:
+				o := client.Options()
+				got = poolOptions{o.PoolSize, o.MinIdleConns, o.MaxRetries, o.DialTimeout, o.ReadTimeout, o.WriteTimeout}
+			default:
+				t.Fatalf("beklenmeyen istemci %T", client)
+			}
+
+			want := poolOptions{pool.PoolSize, pool.MinIdleConns, pool.MaxRetries, pool.DialTimeout, pool.ReadTimeout, pool.WriteTimeout}
+			if got != want {
+				t.Errorf("havuz ayarları = %+v, beklenen %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestRedisCacheReadTimeoutOnStalledServer(t *testing.T) {
+	tests := []struct {
+		name        string
+		readTimeout time.Duration
+		maxElapsed  time.Duration
+	}{
+		{name: "kısa okuma süresi", readTimeout: 100 * time.Millisecond, maxElapsed: 500 * time.Millisecond},
+		{name: "uzun okuma süresi işlem timeout'una takılır", readTimeout: time.Minute, maxElapsed: 2 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache, err := NewRedisCache(CacheConfig{
+				Addrs:            []string{runStalledRedis(t)},
+				OperationTimeout: time.Second,
+				ReadTimeout:      tt.readTimeout,
+				MaxRetries:       -1,
+			}, nil)
+			if err != nil {
+				t.Fatalf("NewRedisCache: %v", err)
+			}
+			t.Cleanup(func() { cache.Close() })
+
+			start := time.Now()
+			var value string
+			err = cache.Get(context.Background(), "slow", &value)
+			elapsed := time.Since(start)
+
+			if err == nil || errors.Is(err, domain.ErrCacheMiss) {
+				t.Fatalf("Get = %v, beklenen zaman aşımı hatası", err)
+			}
+			if elapsed > tt.maxElapsed {
+				t.Errorf("Get %v sürdü, beklenen en fazla %v", elapsed, tt.maxElapsed)
+			}
+		})
+	}
+}
+
+// runCountingRedis GET çağrılarını sayan sahte bir Redis başlatır; fail ise GET hata, değilse nil döner
+func runCountingRedis(t *testing.T, fail bool) (string, *atomic.Int64) {
+	t.Helper()
+	fake, err := server.NewServer("127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("sunucu başlatılamadı: %v", err)
+	}
+	t.Cleanup(fake.Close)
+
+	var gets atomic.Int64
+	if err := fake.Register("PING", func(c *server.Peer, cmd string, args []string) {
+		c.WriteInline("PONG")
+	}); err != nil {
+		t.Fatalf("PING kaydedilemedi: %v", err)
+	}
+	if err := fake.Register("GET", func(c *server.Peer, cmd string, args []string) {
+		gets.Add(1)
+		if fail {
+			c.WriteError("ERR redis kullanılamıyor")
+			return
+		}
+		c.WriteNull()
+	}); err != nil {
+		t.Fatalf("GET kaydedilemedi: %v", err)
+	}
+	return fake.Addr().String(), &gets
+}
+
+func TestRedisCacheCircuitBreakerBypassesRedis(t *testing.T) {
+	tests := []struct {
+		name      string
+		fail      bool
+		calls     int
+		wantOpen  bool
+		wantGets  int64
+		wantErrIs error
+	}{
+		{name: "hatalar breaker'ı açar ve Redis atlanır", fail: true, calls: 10, wantOpen: true, wantGets: 3, wantErrIs: domain.ErrCacheConnection},
+		{name: "cache miss breaker'ı açmaz", calls: 10, wantGets: 10, wantErrIs: domain.ErrCacheMiss},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addr, gets := runCountingRedis(t, tt.fail)
+			cache, err := NewRedisCache(CacheConfig{Addrs: []string{addr}, MaxRetries: -1}, nil)
+			if err != nil {
+				t.Fatalf("NewRedisCache: %v", err)
+			}
+			t.Cleanup(func() { cache.Close() })
+
+			breaker := circuitbreaker.NewCircuitBreaker(BreakerName, circuitbreaker.Config{
+				FailureThreshold:    3,
+				SuccessThreshold:    1,
+				Timeout:             time.Hour,
+				HalfOpenMaxRequests: 1,
+			})
+			t.Cleanup(breaker.Close)
+			cache.SetCircuitBreaker(breaker)
+
+			var lastErr error
+			for i := 0; i < tt.calls; i++ {
+				var value string
+				lastErr = cache.Get(context.Background(), "key", &value)
+			}
+
+			if open := breaker.GetState() == circuitbreaker.StateOpen; open != tt.wantOpen {
+				t.Errorf("breaker durumu = %s, açık bekleniyor: %v", breaker.GetState(), tt.wantOpen)
+			}
+			if got := gets.Load(); got != tt.wantGets {
+				t.Errorf("Redis'e giden GET = %d, beklenen %d", got, tt.wantGets)
+			}
+			if !errors.Is(lastErr, tt.wantErrIs) {
+				t.Errorf("son Get = %v, beklenen %v", lastErr, tt.wantErrIs)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package cache
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ugorji/go/codec"
+)
+
+// SerializationFormat cache değerlerinin Redis'e yazılma biçimi
+type SerializationFormat string
+
+const (
+	FormatJSON    SerializationFormat = "json"
+	FormatMsgpack SerializationFormat = "msgpack"
+)
+
+// msgpackMarker msgpack değerlerinin başına yazılan biçim işaretidir. Geçerli bir JSON belgesi bu
+// baytla başlayamaz; işaretsiz değerler JSON kabul edildiği için geçiş sırasında eski instance'ların
+// yazdığı JSON değerler de okunabilir kalır.
+const msgpackMarker byte = 0x01
+
+// Serializer cache değerlerini Redis'e yazılacak baytlara çevirir
+type Serializer interface {
+	Format() SerializationFormat
+	Marshal(value interface{}) ([]byte, error)
+	Unmarshal(data []byte, dest interface{}) error
+}
+
+// NewSerializer biçim adına göre serializer döner; boş ad JSON kabul edilir
+func NewSerializer(format SerializationFormat) (Serializer, error) {
+	switch format {
+	case FormatJSON, "":
+		return JSONSerializer{}, nil
+	case FormatMsgpack:
+		return NewMsgpackSerializer(), nil
+	default:
+		return nil, fmt.Errorf("unknown cache serialization format: %s", format)
+	}
+}
+
+// JSONSerializer değerleri işaretsiz JSON olarak yazar; önceki sürümlerin yazdığı değerlerle aynı biçimdir
+type JSONSerializer struct{}
+
+func (JSONSerializer) Format() SerializationFormat {
+	return FormatJSON
+}
+
+func (JSONSerializer) Marshal(value interface{}) ([]byte, error) {
+	return json.Marshal(value)
+}
+
+func (JSONSerializer) Unmarshal(data []byte, dest interface{}) error {
+	return decode(data, dest)
+}
+
+// MsgpackSerializer büyük değerler için JSON'dan daha küçük ve hızlı olan msgpack biçimini kullanır.
+// Alan adları json tag'lerinden alınır, böylece iki biçim aynı şemayı paylaşır.
+type MsgpackSerializer struct{}
+
+var msgpackHandle = func() *codec.MsgpackHandle {
+	h := &codec.MsgpackHandle{}
+	h.WriteExt = true // time.Time msgpack'in timestamp uzantısıyla yazılır
+	h.TypeInfos = codec.NewTypeInfos([]string{"codec", "json"})
+	return h
+}()
+
+func NewMsgpackSerializer() MsgpackSerializer {
+	return MsgpackSerializer{}
+}
+
+func (MsgpackSerializer) Format() SerializationFormat {
+	return FormatMsgpack
+}
+
+func (MsgpackSerializer) Marshal(value interface{}) ([]byte, error) {
+	buf := bytes.NewBuffer([]byte{msgpackMarker})
+	if err := codec.NewEncoder(buf, msgpackHandle).Encode(value); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (MsgpackSerializer) Unmarshal(data []byte, dest interface{}) error {
+	return decode(data, dest)
+}
+
+// decode değeri hangi serializer yapılandırılmış olursa olsun yazıldığı biçime göre çözer;
+// böylece biçim değiştirilirken cache'te kalan eski değerler bozulmadan okunur
+func decode(data []byte, dest interface{}) error {
+	if len(data) > 0 && data[0] == msgpackMarker {
+		return codec.NewDecoderBytes(data[1:], msgpackHandle).Decode(dest)
+	}
+	return json.Unmarshal(data, dest)
+}
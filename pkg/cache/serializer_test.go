@@ -0,0 +1,89 @@
+package cache
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type serializerTestValue struct {
+	ID        uuid.UUID         `json:"id"`
+	UserID    string            `json:"user_id"`
+	Amount    float64           `json:"amount"`
+	Count     int               `json:"count"`
+	Active    bool              `json:"active"`
+	Tags      []string          `json:"tags"`
+	Metadata  map[string]string `json:"metadata"`
+	UpdatedAt time.Time         `json:"updated_at"`
+	DeletedAt *time.Time        `json:"deleted_at,omitempty"`
+}
+
+func newSerializerTestValue() serializerTestValue {
+	return serializerTestValue{
+		ID:        uuid.New(),
+		UserID:    "user-1",
+		Amount:    1234.5678,
+		Count:     42,
+		Active:    true,
+		Tags:      []string{"a", "b"},
+		Metadata:  map[string]string{"source": "test"},
+		UpdatedAt: time.Date(2024, 3, 31, 1, 30, 0, 123456789, time.UTC),
+	}
+}
+
+func TestSerializerRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		write  SerializationFormat
+		read   SerializationFormat
+		marker bool
+	}{
+		{name: "json", write: FormatJSON, read: FormatJSON},
+		{name: "msgpack", write: FormatMsgpack, read: FormatMsgpack, marker: true},
+		{name: "varsayılan biçim json", write: "", read: FormatJSON},
+		{name: "json yazılan değer msgpack ile okunur", write: FormatJSON, read: FormatMsgpack},
+		{name: "msgpack yazılan değer json ile okunur", write: FormatMsgpack, read: FormatJSON, marker: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer, err := NewSerializer(tt.write)
+			if err != nil {
+				t.Fatalf("NewSerializer(%q): %v", tt.write, err)
+			}
+			reader, err := NewSerializer(tt.read)
+			if err != nil {
+				t.Fatalf("NewSerializer(%q): %v", tt.read, err)
+			}
+
+			want := newSerializerTestValue()
+			data, err := writer.Marshal(want)
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+			if got := len(data) > 0 && data[0] == msgpackMarker; got != tt.marker {
+				t.Errorf("biçim işareti = %v, beklenen %v", got, tt.marker)
+			}
+
+			var got serializerTestValue
+			if err := reader.Unmarshal(data, &got); err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+			if !got.UpdatedAt.Equal(want.UpdatedAt) {
+				t.Errorf("UpdatedAt = %v, beklenen %v", got.UpdatedAt, want.UpdatedAt)
+			}
+			got.UpdatedAt = want.UpdatedAt
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("değer = %+v, beklenen %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestNewSerializerUnknownFormat(t *testing.T) {
+	if _, err := NewSerializer("xml"); err == nil {
+		t.Fatal("NewSerializer(\"xml\") hata dönmeli")
+	}
+}
@@ -55,7 +55,7 @@ func NewCacheWarmuper(
 		transactionRepo: transactionRepo,
 		balanceRepo:     balanceRepo,
 		eventRepo:       eventRepo,
-		logger:          logger,
+		logger:          domain.LoggerOrNop(logger),
 	}
 }
 
@@ -435,7 +435,7 @@ type WarmupScheduler struct {
 func NewWarmupScheduler(warmuper *CacheWarmuper, logger domain.Logger) *WarmupScheduler {
 	return &WarmupScheduler{
 		warmuper: warmuper,
-		logger:   logger,
+		logger:   domain.LoggerOrNop(logger),
 		stopChan: make(chan struct{}),
 	}
 }
@@ -2,9 +2,12 @@ package circuitbreaker
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
+
+	"transaction-api-w-go/pkg/clock"
 )
 
 type State int
@@ -35,6 +38,39 @@ type Config struct {
 	HalfOpenMaxRequests int           `json:"half_open_max_requests"` // Half-open durumunda maksimum istek
 	WindowSize          time.Duration `json:"window_size"`            // Sliding window boyutu
 	MinRequestCount     int           `json:"min_request_count"`      // Minimum istek sayısı
+	HistorySize         int           `json:"history_size"`           // Saklanacak durum geçişi sayısı
+}
+
+const DefaultHistorySize = 50
+
+// ErrInvalidConfig eşik veya sürelerden biri geçersiz olduğunda döner
+var ErrInvalidConfig = errors.New("invalid circuit breaker config")
+
+// Validate eşiklerin ve açık kalma süresinin pozitif, diğer alanların negatif olmadığını kontrol eder
+func (c Config) Validate() error {
+	switch {
+	case c.FailureThreshold <= 0:
+		return fmt.Errorf("%w: failure_threshold must be positive", ErrInvalidConfig)
+	case c.SuccessThreshold <= 0:
+		return fmt.Errorf("%w: success_threshold must be positive", ErrInvalidConfig)
+	case c.Timeout <= 0:
+		return fmt.Errorf("%w: timeout must be positive", ErrInvalidConfig)
+	case c.HalfOpenMaxRequests <= 0:
+		return fmt.Errorf("%w: half_open_max_requests must be positive", ErrInvalidConfig)
+	case c.WindowSize < 0:
+		return fmt.Errorf("%w: window_size must not be negative", ErrInvalidConfig)
+	case c.MinRequestCount < 0:
+		return fmt.Errorf("%w: min_request_count must not be negative", ErrInvalidConfig)
+	}
+	return nil
+}
+
+// Transition breaker'ın bir durumdan diğerine geçişini kaydeder
+type Transition struct {
+	Timestamp time.Time `json:"timestamp"`
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	Reason    string    `json:"reason"`
 }
 
 type CircuitBreaker struct {
@@ -47,6 +83,15 @@ type CircuitBreaker struct {
 	mu              sync.RWMutex
 	ctx             context.Context
 	cancel          context.CancelFunc
+	// done izleme goroutine'i sonlandığında kapanır
+	done chan struct{}
+	// clock açık kalma süresi ve durum geçiş zamanları için kullanılır
+	clock clock.Clock
+
+	// history son durum geçişlerini tutan sabit boyutlu ring buffer
+	history     []Transition
+	historyNext int
+	historyLen  int
 }
 
 type Counts struct {
@@ -66,19 +111,40 @@ type Result struct {
 }
 
 func NewCircuitBreaker(name string, config Config) *CircuitBreaker {
-	ctx, cancel := context.WithCancel(context.Background())
+	return NewCircuitBreakerWithContext(context.Background(), name, config)
+}
+
+// NewCircuitBreakerWithContext izleme goroutine'ini parent context'e bağlar; parent
+// iptal edildiğinde (ör. uygulama kapanırken) goroutine de sonlanır
+func NewCircuitBreakerWithContext(parent context.Context, name string, config Config) *CircuitBreaker {
+	return NewCircuitBreakerWithClock(parent, name, config, clock.Real())
+}
+
+// NewCircuitBreakerWithClock açık kalma süresini verilen saate göre ölçer; testlerde clock.Fake
+// ilerletilerek half-open geçişi beklemeden tetiklenebilir
+func NewCircuitBreakerWithClock(parent context.Context, name string, config Config, clk clock.Clock) *CircuitBreaker {
+	ctx, cancel := context.WithCancel(parent)
+
+	if config.HistorySize <= 0 {
+		config.HistorySize = DefaultHistorySize
+	}
 
 	cb := &CircuitBreaker{
 		name:            name,
 		config:          config,
 		state:           StateClosed,
 		counts:          &Counts{},
-		lastStateChange: time.Now(),
+		lastStateChange: clk.Now(),
 		ctx:             ctx,
 		cancel:          cancel,
+		done:            make(chan struct{}),
+		clock:           clk,
+		history:         make([]Transition, config.HistorySize),
 	}
 
-	go cb.monitorState()
+	// Ticker goroutine başlamadan kurulur; aksi halde sahte saat goroutine ticker'ı
+	// oluşturmadan ilerletildiğinde ilk timeout kaçırılır
+	go cb.monitorState(clk.NewTicker(1 * time.Second))
 
 	return cb
 }
@@ -131,13 +197,17 @@ func (cb *CircuitBreaker) ExecuteWithContext(ctx context.Context, fn func() erro
 
 func (cb *CircuitBreaker) Ready() bool {
 	cb.mu.RLock()
-	defer cb.mu.RUnlock()
+	state := cb.state
+	lastStateChange := cb.lastStateChange
+	config := cb.config
+	cb.mu.RUnlock()
 
-	switch cb.state {
+	switch state {
 	case StateClosed:
 		return true
 	case StateOpen:
-		if time.Since(cb.lastStateChange) >= cb.config.Timeout {
+		// transitionToHalfOpen yazma kilidi aldığı için okuma kilidi bırakıldıktan sonra çağrılır
+		if cb.clock.Now().Sub(lastStateChange) >= config.Timeout {
 			cb.transitionToHalfOpen()
 			return true
 		}
@@ -147,50 +217,71 @@ func (cb *CircuitBreaker) Ready() bool {
 		requests := cb.counts.Requests
 		cb.counts.mu.RUnlock()
 
-		return requests < int64(cb.config.HalfOpenMaxRequests)
+		return requests < int64(config.HalfOpenMaxRequests)
 	default:
 		return false
 	}
 }
 
 func (cb *CircuitBreaker) recordResult(err error, latency time.Duration) {
+	// Durum ve ayarlar counts kilidinden önce okunur; geçiş fonksiyonları kilitleri
+	// cb.mu -> counts.mu sırasıyla aldığından ters sıra kilitlenmeye yol açar
+	cb.mu.RLock()
+	state := cb.state
+	config := cb.config
+	cb.mu.RUnlock()
+
 	cb.counts.mu.Lock()
-	defer cb.counts.mu.Unlock()
 
+	var toOpen, toClose bool
 	if err != nil {
 		cb.counts.TotalErrors++
 		cb.counts.ConsecutiveErrors++
 		cb.counts.ConsecutiveSuccesses = 0
-		cb.counts.LastErrorTime = time.Now()
+		cb.counts.LastErrorTime = cb.clock.Now()
 		cb.lastError = err
 
-		if cb.shouldOpen() {
-			cb.transitionToOpen()
-		}
+		toOpen = cb.shouldOpen(state, config)
 	} else {
 		cb.counts.ConsecutiveSuccesses++
 		cb.counts.ConsecutiveErrors = 0
 
-		if cb.shouldClose() {
-			cb.transitionToClosed()
-		}
+		toClose = cb.shouldClose(state, config)
+	}
+	cb.counts.mu.Unlock()
+
+	// Geçişler counts kilidi bırakıldıktan sonra yapılır; geçiş fonksiyonları
+	// sayaçları sıfırlarken aynı kilidi alır
+	if toOpen {
+		cb.transitionToOpen()
+	}
+	if toClose {
+		cb.transitionToClosed()
 	}
 }
 
-func (cb *CircuitBreaker) shouldOpen() bool {
-	if cb.counts.Requests < int64(cb.config.MinRequestCount) {
+// shouldOpen counts kilidi tutulurken çağrılmalıdır
+func (cb *CircuitBreaker) shouldOpen(state State, config Config) bool {
+	// Half-open durumundaki deneme isteği başarısızsa breaker beklemeden yeniden açılır;
+	// aksi halde HalfOpenMaxRequests dolduğunda breaker kalıcı olarak kapalı kalırdı
+	if state == StateHalfOpen {
+		return true
+	}
+
+	if cb.counts.Requests < int64(config.MinRequestCount) {
 		return false
 	}
 
-	return cb.counts.ConsecutiveErrors >= int64(cb.config.FailureThreshold)
+	return cb.counts.ConsecutiveErrors >= int64(config.FailureThreshold)
 }
 
-func (cb *CircuitBreaker) shouldClose() bool {
-	if cb.state != StateHalfOpen {
+// shouldClose counts kilidi tutulurken çağrılmalıdır
+func (cb *CircuitBreaker) shouldClose(state State, config Config) bool {
+	if state != StateHalfOpen {
 		return false
 	}
 
-	return cb.counts.ConsecutiveSuccesses >= int64(cb.config.SuccessThreshold)
+	return cb.counts.ConsecutiveSuccesses >= int64(config.SuccessThreshold)
 }
 
 func (cb *CircuitBreaker) transitionToOpen() {
@@ -198,9 +289,9 @@ func (cb *CircuitBreaker) transitionToOpen() {
 	defer cb.mu.Unlock()
 
 	if cb.state != StateOpen {
-		cb.state = StateOpen
-		cb.lastStateChange = time.Now()
-		fmt.Printf("Circuit breaker %s: CLOSED -> OPEN\n", cb.name)
+		from := cb.state
+		cb.setState(StateOpen, "failure threshold reached")
+		fmt.Printf("Circuit breaker %s: %s -> OPEN\n", cb.name, from)
 	}
 }
 
@@ -209,8 +300,7 @@ func (cb *CircuitBreaker) transitionToHalfOpen() {
 	defer cb.mu.Unlock()
 
 	if cb.state == StateOpen {
-		cb.state = StateHalfOpen
-		cb.lastStateChange = time.Now()
+		cb.setState(StateHalfOpen, "open timeout elapsed")
 
 		cb.counts.mu.Lock()
 		cb.counts.Requests = 0
@@ -227,8 +317,7 @@ func (cb *CircuitBreaker) transitionToClosed() {
 	defer cb.mu.Unlock()
 
 	if cb.state == StateHalfOpen {
-		cb.state = StateClosed
-		cb.lastStateChange = time.Now()
+		cb.setState(StateClosed, "success threshold reached")
 
 		cb.counts.mu.Lock()
 		cb.counts.Requests = 0
@@ -241,15 +330,48 @@ func (cb *CircuitBreaker) transitionToClosed() {
 	}
 }
 
-func (cb *CircuitBreaker) monitorState() {
-	ticker := time.NewTicker(1 * time.Second)
+// setState durumu değiştirir ve geçişi history'ye ekler; cb.mu yazma kilidi tutulurken çağrılmalıdır
+func (cb *CircuitBreaker) setState(to State, reason string) {
+	now := cb.clock.Now()
+	from := cb.state
+
+	cb.state = to
+	cb.lastStateChange = now
+
+	cb.history[cb.historyNext] = Transition{
+		Timestamp: now,
+		From:      from.String(),
+		To:        to.String(),
+		Reason:    reason,
+	}
+	cb.historyNext = (cb.historyNext + 1) % len(cb.history)
+	if cb.historyLen < len(cb.history) {
+		cb.historyLen++
+	}
+}
+
+// GetHistory kaydedilen durum geçişlerini eskiden yeniye sıralı döner
+func (cb *CircuitBreaker) GetHistory() []Transition {
+	cb.mu.RLock()
+	defer cb.mu.RUnlock()
+
+	history := make([]Transition, 0, cb.historyLen)
+	start := (cb.historyNext - cb.historyLen + len(cb.history)) % len(cb.history)
+	for i := 0; i < cb.historyLen; i++ {
+		history = append(history, cb.history[(start+i)%len(cb.history)])
+	}
+	return history
+}
+
+func (cb *CircuitBreaker) monitorState(ticker clock.Ticker) {
+	defer close(cb.done)
 	defer ticker.Stop()
 
 	for {
 		select {
 		case <-cb.ctx.Done():
 			return
-		case <-ticker.C:
+		case <-ticker.C():
 			cb.checkStateTransition()
 		}
 	}
@@ -259,9 +381,10 @@ func (cb *CircuitBreaker) checkStateTransition() {
 	cb.mu.RLock()
 	state := cb.state
 	lastChange := cb.lastStateChange
+	timeout := cb.config.Timeout
 	cb.mu.RUnlock()
 
-	if state == StateOpen && time.Since(lastChange) >= cb.config.Timeout {
+	if state == StateOpen && cb.clock.Now().Sub(lastChange) >= timeout {
 		cb.transitionToHalfOpen()
 	}
 }
@@ -318,8 +441,7 @@ func (cb *CircuitBreaker) ForceOpen() {
 	cb.mu.Lock()
 	defer cb.mu.Unlock()
 
-	cb.state = StateOpen
-	cb.lastStateChange = time.Now()
+	cb.setState(StateOpen, "forced open")
 	fmt.Printf("Circuit breaker %s: FORCED OPEN\n", cb.name)
 }
 
@@ -327,8 +449,7 @@ func (cb *CircuitBreaker) ForceClose() {
 	cb.mu.Lock()
 	defer cb.mu.Unlock()
 
-	cb.state = StateClosed
-	cb.lastStateChange = time.Now()
+	cb.setState(StateClosed, "forced closed")
 
 	cb.counts.mu.Lock()
 	cb.counts.Requests = 0
@@ -344,8 +465,7 @@ func (cb *CircuitBreaker) Reset() {
 	cb.mu.Lock()
 	defer cb.mu.Unlock()
 
-	cb.state = StateClosed
-	cb.lastStateChange = time.Now()
+	cb.setState(StateClosed, "reset")
 	cb.lastError = nil
 
 	cb.counts.mu.Lock()
@@ -358,6 +478,36 @@ func (cb *CircuitBreaker) Reset() {
 	fmt.Printf("Circuit breaker %s: RESET\n", cb.name)
 }
 
+// Config breaker'ın kullandığı ayarları döner
+func (cb *CircuitBreaker) Config() Config {
+	cb.mu.RLock()
+	defer cb.mu.RUnlock()
+	return cb.config
+}
+
+// UpdateConfig eşik ve süre ayarlarını çalışma zamanında değiştirir. Mevcut durum ve
+// sayaçlar korunur; yeni eşikler bir sonraki istek sonucundan itibaren uygulanır.
+// History boyutu oluşturulduktan sonra değişmez.
+func (cb *CircuitBreaker) UpdateConfig(config Config) error {
+	if err := config.Validate(); err != nil {
+		return err
+	}
+
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	config.HistorySize = cb.config.HistorySize
+	cb.config = config
+
+	fmt.Printf("Circuit breaker %s: CONFIG UPDATED\n", cb.name)
+	return nil
+}
+
+// Done izleme goroutine'i durduğunda kapanan kanalı döner
+func (cb *CircuitBreaker) Done() <-chan struct{} {
+	return cb.done
+}
+
 func (cb *CircuitBreaker) Close() {
 	cb.cancel()
 }
@@ -0,0 +1,216 @@
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"transaction-api-w-go/pkg/clock"
+)
+
+func newTestBreaker(t *testing.T, config Config) (*CircuitBreaker, *clock.Fake) {
+	t.Helper()
+	clk := clock.NewFake(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	cb := NewCircuitBreakerWithClock(context.Background(), "test", config, clk)
+	t.Cleanup(cb.Close)
+	return cb, clk
+}
+
+func testConfig() Config {
+	return Config{
+		FailureThreshold:    2,
+		SuccessThreshold:    1,
+		Timeout:             time.Minute,
+		HalfOpenMaxRequests: 1,
+	}
+}
+
+var errTest = errors.New("fail")
+
+func TestCircuitBreakerHistory(t *testing.T) {
+	tests := []struct {
+		name        string
+		historySize int
+		steps       []func(cb *CircuitBreaker, clk *clock.Fake)
+		want        []Transition
+	}{
+		{
+			name:        "geçişler eskiden yeniye sıralanır",
+			historySize: 10,
+			steps: []func(cb *CircuitBreaker, clk *clock.Fake){
+				func(cb *CircuitBreaker, clk *clock.Fake) {
+					cb.Execute(func() error { return errTest })
+					cb.Execute(func() error { return errTest })
+				},
+				func(cb *CircuitBreaker, clk *clock.Fake) {
+					clk.Advance(time.Minute)
+					cb.Ready()
+				},
+				func(cb *CircuitBreaker, clk *clock.Fake) {
+					cb.Execute(func() error { return nil })
+				},
+			},
+			want: []Transition{
+				{From: "CLOSED", To: "OPEN", Reason: "failure threshold reached"},
+				{From: "OPEN", To: "HALF_OPEN", Reason: "open timeout elapsed"},
+				{From: "HALF_OPEN", To: "CLOSED", Reason: "success threshold reached"},
+			},
+		},
+		{
+			name:        "boyut aşılınca en eski geçişler atılır",
+			historySize: 2,
+			steps: []func(cb *CircuitBreaker, clk *clock.Fake){
+				func(cb *CircuitBreaker, clk *clock.Fake) { cb.ForceOpen() },
+				func(cb *CircuitBreaker, clk *clock.Fake) { cb.ForceClose() },
+				func(cb *CircuitBreaker, clk *clock.Fake) { cb.ForceOpen() },
+				func(cb *CircuitBreaker, clk *clock.Fake) { cb.Reset() },
+			},
+			want: []Transition{
+				{From: "CLOSED", To: "OPEN", Reason: "forced open"},
+				{From: "OPEN", To: "CLOSED", Reason: "reset"},
+			},
+		},
+		{
+			name:        "geçiş yoksa boş",
+			historySize: 2,
+			want:        []Transition{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := testConfig()
+			config.HistorySize = tt.historySize
+			cb, clk := newTestBreaker(t, config)
+
+			for _, step := range tt.steps {
+				clk.Advance(time.Second)
+				step(cb, clk)
+			}
+
+			got := cb.GetHistory()
+			if len(got) != len(tt.want) {
+				t.Fatalf("%d geçiş döndü, beklenen %d: %+v", len(got), len(tt.want), got)
+			}
+			for i, want := range tt.want {
+				if got[i].From != want.From || got[i].To != want.To || got[i].Reason != want.Reason {
+					t.Errorf("geçiş %d = %+v, beklenen %+v", i, got[i], want)
+				}
+				if i > 0 && got[i].Timestamp.Before(got[i-1].Timestamp) {
+					t.Errorf("geçiş %d zaman sırası bozuk: %v < %v", i, got[i].Timestamp, got[i-1].Timestamp)
+				}
+			}
+		})
+	}
+}
+
+func TestCircuitBreakerStopsWithRootContext(t *testing.T) {
+	tests := []struct {
+		name string
+		stop func(cancelRoot context.CancelFunc, cb *CircuitBreaker)
+	}{
+		{name: "kök context iptali", stop: func(cancelRoot context.CancelFunc, cb *CircuitBreaker) { cancelRoot() }},
+		{name: "Close", stop: func(cancelRoot context.CancelFunc, cb *CircuitBreaker) { cb.Close() }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, cancelRoot := context.WithCancel(context.Background())
+			defer cancelRoot()
+			cb := NewCircuitBreakerWithContext(root, "test", testConfig())
+			defer cb.Close()
+
+			select {
+			case <-cb.Done():
+				t.Fatal("izleme goroutine'i iptalden önce durdu")
+			case <-time.After(20 * time.Millisecond):
+			}
+
+			tt.stop(cancelRoot, cb)
+			select {
+			case <-cb.Done():
+			case <-time.After(time.Second):
+				t.Fatal("izleme goroutine'i iptalden sonra durmadı")
+			}
+		})
+	}
+}
+
+func TestCircuitBreakerUpdateConfigAtRuntime(t *testing.T) {
+	tests := []struct {
+		name        string
+		before      int
+		threshold   int
+		wantErr     bool
+		wantTripped int
+	}{
+		{name: "eşik düşürülünce sonraki hatalarda daha erken açılır", threshold: 2, wantTripped: 2},
+		{name: "mevcut sayaçlar korunur", before: 2, threshold: 3, wantTripped: 1},
+		{name: "sayaç yeni eşiği zaten aşmışsa ilk hatada açılır", before: 3, threshold: 2, wantTripped: 1},
+		{name: "eşik yükseltilince daha geç açılır", before: 1, threshold: 8, wantTripped: 7},
+		{name: "geçersiz config reddedilir", before: 1, threshold: 0, wantErr: true, wantTripped: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := testConfig()
+			config.FailureThreshold = 5
+			cb, _ := newTestBreaker(t, config)
+
+			for i := 0; i < tt.before; i++ {
+				cb.Execute(func() error { return errTest })
+			}
+
+			config.FailureThreshold = tt.threshold
+			if err := cb.UpdateConfig(config); (err != nil) != tt.wantErr {
+				t.Fatalf("UpdateConfig hatası = %v, hata bekleniyor: %v", err, tt.wantErr)
+			}
+			if got := cb.GetCounts().ConsecutiveErrors; got != int64(tt.before) {
+				t.Fatalf("güncelleme sonrası ardışık hata = %d, beklenen %d", got, tt.before)
+			}
+
+			tripped := 0
+			for cb.GetState() != StateOpen && tripped < 10 {
+				cb.Execute(func() error { return errTest })
+				tripped++
+			}
+			if tripped != tt.wantTripped {
+				t.Errorf("breaker %d hatada açıldı, beklenen %d", tripped, tt.wantTripped)
+			}
+		})
+	}
+}
+
+func TestCircuitBreakerHalfOpenAfterFakeClockAdvance(t *testing.T) {
+	tests := []struct {
+		name      string
+		advance   time.Duration
+		wantReady bool
+		wantState State
+	}{
+		{name: "saat ilerlemeden açık kalır", wantState: StateOpen},
+		{name: "timeout dolmadan açık kalır", advance: time.Minute - time.Second, wantState: StateOpen},
+		{name: "timeout dolunca yarı açığa geçer", advance: time.Minute, wantReady: true, wantState: StateHalfOpen},
+		{name: "timeout aşılınca yarı açığa geçer", advance: 10 * time.Minute, wantReady: true, wantState: StateHalfOpen},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cb, clk := newTestBreaker(t, testConfig())
+			cb.Execute(func() error { return errTest })
+			cb.Execute(func() error { return errTest })
+			if cb.GetState() != StateOpen {
+				t.Fatalf("durum = %s, beklenen OPEN", cb.GetState())
+			}
+
+			clk.Advance(tt.advance)
+			if got := cb.Ready(); got != tt.wantReady {
+				t.Errorf("Ready() = %v, beklenen %v", got, tt.wantReady)
+			}
+			if got := cb.GetState(); got != tt.wantState {
+				t.Errorf("durum = %s, beklenen %s", got, tt.wantState)
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package clock
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock zamana bağlı mantığın (limit sıfırlama, breaker timeout vb.) gerçek saat yerine
+// kontrol edilebilir bir saatle çalıştırılabilmesini sağlar
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+	NewTicker(d time.Duration) Ticker
+}
+
+// Ticker time.Ticker'ın Clock üzerinden oluşturulabilen karşılığıdır
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+	Reset(d time.Duration)
+}
+
+// Real sistem saatini kullanan Clock döner
+func Real() Clock {
+	return realClock{}
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+func (realClock) NewTicker(d time.Duration) Ticker {
+	return &realTicker{ticker: time.NewTicker(d)}
+}
+
+type realTicker struct {
+	ticker *time.Ticker
+}
+
+func (t *realTicker) C() <-chan time.Time {
+	return t.ticker.C
+}
+
+func (t *realTicker) Stop() {
+	t.ticker.Stop()
+}
+
+func (t *realTicker) Reset(d time.Duration) {
+	t.ticker.Reset(d)
+}
+
+// Fake yalnızca Advance ile ilerleyen deterministik saattir. Süresi dolan After kanalları ve
+// ticker'lar Advance sırasında tetiklenir; ticker kanalı doluysa tick, time.Ticker'daki gibi düşürülür.
+type Fake struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []*fakeWaiter
+}
+
+type fakeWaiter struct {
+	deadline time.Time
+	// period sıfırdan büyükse bekleyici bir ticker'dır ve her tetiklemede yeniden kurulur
+	period time.Duration
+	ch     chan time.Time
+}
+
+// NewFake verilen andan başlayan sahte saat oluşturur
+func NewFake(now time.Time) *Fake {
+	return &Fake{now: now}
+}
+
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+func (f *Fake) After(d time.Duration) <-chan time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- f.now
+		return ch
+	}
+	f.waiters = append(f.waiters, &fakeWaiter{deadline: f.now.Add(d), ch: ch})
+	return ch
+}
+
+func (f *Fake) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("clock: non-positive interval for NewTicker")
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	waiter := &fakeWaiter{deadline: f.now.Add(d), period: d, ch: make(chan time.Time, 1)}
+	f.waiters = append(f.waiters, waiter)
+	return &fakeTicker{clock: f, waiter: waiter}
+}
+
+// Advance saati d kadar ilerletir ve süresi dolan bekleyicileri sırasıyla tetikler
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.now = f.now.Add(d)
+
+	sort.SliceStable(f.waiters, func(i, j int) bool {
+		return f.waiters[i].deadline.Before(f.waiters[j].deadline)
+	})
+
+	remaining := f.waiters[:0]
+	for _, waiter := range f.waiters {
+		if waiter.deadline.After(f.now) {
+			remaining = append(remaining, waiter)
+			continue
+		}
+
+		select {
+		case waiter.ch <- f.now:
+		default:
+		}
+
+		if waiter.period > 0 {
+			for !waiter.deadline.After(f.now) {
+				waiter.deadline = waiter.deadline.Add(waiter.period)
+			}
+			remaining = append(remaining, waiter)
+		}
+	}
+	f.waiters = remaining
+}
+
+func (f *Fake) remove(target *fakeWaiter) {
+	for i, waiter := range f.waiters {
+		if waiter == target {
+			f.waiters = append(f.waiters[:i], f.waiters[i+1:]...)
+			return
+		}
+	}
+}
+
+type fakeTicker struct {
+	clock  *Fake
+	waiter *fakeWaiter
+}
+
+func (t *fakeTicker) C() <-chan time.Time {
+	return t.waiter.ch
+}
+
+func (t *fakeTicker) Stop() {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	t.clock.remove(t.waiter)
+}
+
+func (t *fakeTicker) Reset(d time.Duration) {
+	if d <= 0 {
+		panic("clock: non-positive interval for Ticker.Reset")
+	}
+
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+
+	t.clock.remove(t.waiter)
+	t.waiter.period = d
+	t.waiter.deadline = t.clock.now.Add(d)
+	t.clock.waiters = append(t.clock.waiters, t.waiter)
+}
@@ -23,9 +23,7 @@ func createConnection(cfg *config.Config) (*gorm.DB, error) {
 		Str("database", cfg.DBName).
 		Msg("Attempting database connection")
 
-	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info),
-	})
+	db, err := Open(mysql.Open(dsn))
 	if err != nil {
 		return nil, err
 	}
@@ -47,6 +45,23 @@ func createConnection(cfg *config.Config) (*gorm.DB, error) {
 	return db, nil
 }
 
+// Open tüm bağlantılarda (testlerdekiler dahil) aynı gorm ayarlarını ve zaman damgası eklentisini kullanır
+func Open(dialector gorm.Dialector) (*gorm.DB, error) {
+	db, err := gorm.Open(dialector, &gorm.Config{
+		Logger:  logger.Default.LogMode(logger.Info),
+		NowFunc: Now,
+		// Tekillik ihlalleri gorm.ErrDuplicatedKey olarak döner; repository'ler bunu domain hatasına çevirir
+		TranslateError: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Use(Timestamps{}); err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
 func Connect(cfg *config.Config) {
 	var err error
 	maxRetries := 5
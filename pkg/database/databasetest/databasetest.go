@@ -0,0 +1,106 @@
+// Package databasetest repository ve servis testleri için migrations/init.sql şemasıyla açılmış,
+// bellek içi ve MySQL protokolüyle konuşan bir veritabanı sağlar. Her Open çağrısı boş bir şemayla
+// başlar; testler birbirinin verisini görmez.
+package databasetest
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"testing"
+
+	"transaction-api-w-go/pkg/database"
+
+	sqle "github.com/dolthub/go-mysql-server"
+	"github.com/dolthub/go-mysql-server/memory"
+	"github.com/dolthub/go-mysql-server/server"
+	"github.com/sirupsen/logrus"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const databaseName = "transaction_api"
+
+// unnamedForeignKey isimsiz FOREIGN KEY tanımlarını bulur. Bellek içi sunucu aynı tablodaki isimsiz
+// kısıtlamalara aynı adı verdiğinden şema yüklenmeden önce her birine benzersiz bir ad verilir.
+var unnamedForeignKey = regexp.MustCompile(`(?m)^(\s*)FOREIGN KEY`)
+
+// virtualColumn VIRTUAL üretilmiş kolonları bulur. Bellek içi sunucu bu kolonlar üzerinden indeksle
+// okunan satırlarda panikler; değerleri aynı olduğundan testlerde STORED olarak yüklenirler.
+var virtualColumn = regexp.MustCompile(`\)\s+VIRTUAL\b`)
+
+// Bellek içi sunucunun bağlantı logları test çıktısını boğmasın diye kapatılır
+func init() {
+	logrus.SetOutput(io.Discard)
+}
+
+// Open bellek içi bir veritabanı sunucusu başlatır, init.sql şemasını yükler ve uygulamanın gorm
+// ayarlarıyla açılmış bağlantıyı döner. Sunucu ve bağlantı test bitince kapatılır.
+func Open(t testing.TB) *gorm.DB {
+	t.Helper()
+
+	db := memory.NewDatabase(databaseName)
+	db.EnablePrimaryKeyIndexes()
+	provider := memory.NewDBProvider(db)
+	engine := sqle.NewDefault(provider)
+
+	srv, err := server.NewServer(server.Config{Protocol: "tcp", Address: "127.0.0.1:0"}, engine, memory.NewSessionBuilder(provider), nil)
+	if err != nil {
+		t.Fatalf("databasetest: sunucu başlatılamadı: %v", err)
+	}
+	go func() { _ = srv.Start() }()
+	t.Cleanup(func() { _ = srv.Close() })
+
+	dsn := fmt.Sprintf("root:@tcp(%s)/%s?parseTime=true", srv.Listener.Addr(), databaseName)
+	gdb, err := database.Open(mysql.Open(dsn))
+	if err != nil {
+		t.Fatalf("databasetest: bağlantı açılamadı: %v", err)
+	}
+	gdb.Logger = logger.Default.LogMode(logger.Silent)
+	// Bellek içi sunucu savepoint desteklemez; iç içe transaction'lar dıştakinin içinde çalışır ve
+	// hata dönen iç transaction yine tüm işlemi geri aldırır
+	gdb.DisableNestedTransaction = true
+
+	sqlDB, err := gdb.DB()
+	if err != nil {
+		t.Fatalf("databasetest: %v", err)
+	}
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	Migrate(t, gdb)
+	return gdb
+}
+
+// Migrate init.sql şemasını RunMigrations'ın yaptığı gibi veritabanına uygular. Open tarafından
+// çağrılır; testler uygulamanın yeniden başlatılmasını taklit etmek için aynı veritabanına tekrar
+// çağırabilir.
+func Migrate(t testing.TB, db *gorm.DB) {
+	t.Helper()
+	if err := database.Migrate(db, schema(t)); err != nil {
+		t.Fatalf("databasetest: şema yüklenemedi: %v", err)
+	}
+}
+
+// schema migrations/init.sql'i bellek içi sunucunun kabul edeceği biçime getirir
+func schema(t testing.TB) string {
+	t.Helper()
+
+	_, file, _, _ := runtime.Caller(0)
+	path := filepath.Join(filepath.Dir(file), "..", "..", "..", "migrations", "init.sql")
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("databasetest: %v", err)
+	}
+
+	n := 0
+	text := unnamedForeignKey.ReplaceAllStringFunc(string(content), func(match string) string {
+		n++
+		indent := match[:len(match)-len("FOREIGN KEY")]
+		return fmt.Sprintf("%sCONSTRAINT fk_databasetest_%d FOREIGN KEY", indent, n)
+	})
+	return virtualColumn.ReplaceAllString(text, ") STORED")
+}
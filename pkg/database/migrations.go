@@ -1,11 +1,13 @@
 package database
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 
 	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
 )
 
 func RunMigrations() {
@@ -17,19 +19,26 @@ func RunMigrations() {
 		log.Fatal().Err(err).Str("path", migrationPath).Msg("Failed to read migration file")
 	}
 
-	queries := strings.Split(string(migrationSQL), ";")
+	if err := Migrate(DB, string(migrationSQL)); err != nil {
+		log.Fatal().Err(err).Msg("Failed to execute migration query")
+	}
+
+	log.Info().Msg("Database migrations completed successfully")
+}
 
-	for _, query := range queries {
+// Migrate migration dosyasını sorgulara böler ve sırayla çalıştırır; ilk başarısız sorguda durur.
+// Uygulama her açılışta aynı dosyayı yeniden çalıştırdığından kalıcı olması gereken tablolar
+// DROP edilmemeli, yalnızca CREATE TABLE IF NOT EXISTS ile oluşturulmalıdır.
+func Migrate(db *gorm.DB, migrationSQL string) error {
+	for _, query := range strings.Split(migrationSQL, ";") {
 		query = strings.TrimSpace(query)
 		if query == "" {
 			continue
 		}
 
-		result := DB.Exec(query)
-		if result.Error != nil {
-			log.Fatal().Err(result.Error).Str("query", query).Msg("Failed to execute migration query")
+		if err := db.Exec(query).Error; err != nil {
+			return fmt.Errorf("%w\n%s", err, query)
 		}
 	}
-
-	log.Info().Msg("Database migrations completed successfully")
+	return nil
 }
@@ -2,13 +2,15 @@ package database
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math/rand"
+	"sort"
 	"sync"
 	"time"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
 )
 
 type DatabaseNode struct {
@@ -33,10 +35,21 @@ type ReplicationConfig struct {
 	MaxIdleConns        int            `json:"max_idle_conns"`
 	ConnMaxLifetime     time.Duration  `json:"conn_max_lifetime"`
 	HealthCheckInterval time.Duration  `json:"health_check_interval"`
+	HealthCheckTimeout  time.Duration  `json:"health_check_timeout"`
+	HealthCheckJitter   time.Duration  `json:"health_check_jitter"`
 	FailoverEnabled     bool           `json:"failover_enabled"`
+	MaxReplicationLag   time.Duration  `json:"max_replication_lag"`
 	AutoFailbackEnabled bool           `json:"auto_failback_enabled"`
 }
 
+const (
+	DefaultHealthCheckInterval = 30 * time.Second
+	DefaultHealthCheckTimeout  = 5 * time.Second
+	DefaultMaxReplicationLag   = 10 * time.Second
+)
+
+var ErrNoPromotableReplica = errors.New("no replica passed promotion safety checks")
+
 type DatabaseCluster struct {
 	config     ReplicationConfig
 	masterDB   *gorm.DB
@@ -46,6 +59,15 @@ type DatabaseCluster struct {
 	healthChan chan HealthCheckResult
 	ctx        context.Context
 	cancel     context.CancelFunc
+	// done health check goroutine'i sonlandığında kapanır
+	done chan struct{}
+
+	// lastStatus her node için bilinen son health check sonucunu tutar
+	statusMu   sync.RWMutex
+	lastStatus map[string]HealthCheckResult
+
+	// probeCandidate failover adayına bağlanıp replikasyon gecikmesini ölçer
+	probeCandidate func(node DatabaseNode) (*gorm.DB, time.Duration, error)
 }
 
 type HealthCheckResult struct {
@@ -56,17 +78,17 @@ type HealthCheckResult struct {
 }
 
 func NewDatabaseCluster(config ReplicationConfig) (*DatabaseCluster, error) {
-	ctx, cancel := context.WithCancel(context.Background())
+	return NewDatabaseClusterWithContext(context.Background(), config)
+}
 
-	cluster := &DatabaseCluster{
-		config:     config,
-		healthChan: make(chan HealthCheckResult, 100),
-		ctx:        ctx,
-		cancel:     cancel,
-	}
+// NewDatabaseClusterWithContext health check goroutine'ini ve node kontrollerini parent context'e bağlar
+func NewDatabaseClusterWithContext(parent context.Context, config ReplicationConfig) (*DatabaseCluster, error) {
+	cluster := newDatabaseCluster(parent, config)
+	config = cluster.config
 
 	masterDB, err := cluster.connectToNode(config.MasterNode)
 	if err != nil {
+		cluster.cancel()
 		return nil, fmt.Errorf("failed to connect to master: %w", err)
 	}
 	cluster.masterDB = masterDB
@@ -94,13 +116,53 @@ func NewDatabaseCluster(config ReplicationConfig) (*DatabaseCluster, error) {
 	return cluster, nil
 }
 
+// newDatabaseCluster varsayılanları uygular ve bağlantı açmadan cluster yapısını hazırlar
+func newDatabaseCluster(parent context.Context, config ReplicationConfig) *DatabaseCluster {
+	ctx, cancel := context.WithCancel(parent)
+
+	if config.HealthCheckInterval <= 0 {
+		config.HealthCheckInterval = DefaultHealthCheckInterval
+	}
+	if config.HealthCheckTimeout <= 0 {
+		config.HealthCheckTimeout = DefaultHealthCheckTimeout
+	}
+	if config.MaxReplicationLag <= 0 {
+		config.MaxReplicationLag = DefaultMaxReplicationLag
+	}
+	if config.HealthCheckJitter < 0 {
+		config.HealthCheckJitter = 0
+	}
+
+	cluster := &DatabaseCluster{
+		config:     config,
+		healthChan: make(chan HealthCheckResult, 100),
+		ctx:        ctx,
+		cancel:     cancel,
+		done:       make(chan struct{}),
+		lastStatus: make(map[string]HealthCheckResult),
+	}
+
+	for _, node := range clusterNodes(config) {
+		cluster.lastStatus[node.Name] = HealthCheckResult{Node: node, Status: "unknown"}
+	}
+
+	cluster.probeCandidate = cluster.connectAndMeasureLag
+	return cluster
+}
+
+func clusterNodes(config ReplicationConfig) []DatabaseNode {
+	nodes := make([]DatabaseNode, 0, 1+len(config.SlaveNodes)+len(config.ReadReplicas))
+	nodes = append(nodes, config.MasterNode)
+	nodes = append(nodes, config.SlaveNodes...)
+	nodes = append(nodes, config.ReadReplicas...)
+	return nodes
+}
+
 func (c *DatabaseCluster) connectToNode(node DatabaseNode) (*gorm.DB, error) {
 	dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
 		node.Host, node.Port, node.Username, node.Password, node.Database, node.SSLMode)
 
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info),
-	})
+	db, err := Open(postgres.Open(dsn))
 	if err != nil {
 		return nil, err
 	}
@@ -169,6 +231,8 @@ func (c *DatabaseCluster) GetReadDB() *gorm.DB {
 }
 
 func (c *DatabaseCluster) startHealthMonitoring() {
+	defer close(c.done)
+
 	ticker := time.NewTicker(c.config.HealthCheckInterval)
 	defer ticker.Stop()
 
@@ -183,19 +247,36 @@ func (c *DatabaseCluster) startHealthMonitoring() {
 }
 
 func (c *DatabaseCluster) performHealthCheck() {
-	go c.checkNodeHealth(c.config.MasterNode, c.masterDB, "master")
+	go c.checkNodeHealthWithJitter(c.config.MasterNode, c.masterDB, "master")
 
 	for i, slaveNode := range c.config.SlaveNodes {
 		if i < len(c.slaveDBs) {
-			go c.checkNodeHealth(slaveNode, c.slaveDBs[i], "slave")
+			go c.checkNodeHealthWithJitter(slaveNode, c.slaveDBs[i], "slave")
 		}
 	}
 
 	for i, readNode := range c.config.ReadReplicas {
 		if i < len(c.readDBs) {
-			go c.checkNodeHealth(readNode, c.readDBs[i], "read_replica")
+			go c.checkNodeHealthWithJitter(readNode, c.readDBs[i], "read_replica")
+		}
+	}
+}
+
+// checkNodeHealthWithJitter node'ları aynı anda pinglememek için her kontrolden önce
+// [0, HealthCheckJitter) aralığında rastgele bekler.
+func (c *DatabaseCluster) checkNodeHealthWithJitter(node DatabaseNode, db *gorm.DB, nodeType string) {
+	if jitter := c.config.HealthCheckJitter; jitter > 0 {
+		timer := time.NewTimer(time.Duration(rand.Int63n(int64(jitter))))
+		defer timer.Stop()
+
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-timer.C:
 		}
 	}
+
+	c.checkNodeHealth(node, db, nodeType)
 }
 
 func (c *DatabaseCluster) checkNodeHealth(node DatabaseNode, db *gorm.DB, nodeType string) {
@@ -203,15 +284,15 @@ func (c *DatabaseCluster) checkNodeHealth(node DatabaseNode, db *gorm.DB, nodeTy
 
 	sqlDB, err := db.DB()
 	if err != nil {
-		c.healthChan <- HealthCheckResult{
+		c.recordHealthResult(HealthCheckResult{
 			Node:   node,
 			Status: "unhealthy",
 			Error:  err,
-		}
+		})
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(c.ctx, c.config.HealthCheckTimeout)
 	defer cancel()
 
 	err = sqlDB.PingContext(ctx)
@@ -236,6 +317,16 @@ func (c *DatabaseCluster) checkNodeHealth(node DatabaseNode, db *gorm.DB, nodeTy
 		c.updateNodeStatus(node.Name, true)
 	}
 
+	c.recordHealthResult(result)
+}
+
+// recordHealthResult sonucu son bilinen durum haritasına yazar ve kanal dinleyicilerine
+// bloklamadan iletir.
+func (c *DatabaseCluster) recordHealthResult(result HealthCheckResult) {
+	c.statusMu.Lock()
+	c.lastStatus[result.Node.Name] = result
+	c.statusMu.Unlock()
+
 	select {
 	case c.healthChan <- result:
 	default:
@@ -268,54 +359,141 @@ func (c *DatabaseCluster) updateNodeStatus(nodeName string, isActive bool) {
 	}
 }
 
-func (c *DatabaseCluster) triggerFailover() {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	var bestSlave *DatabaseNode
-	for i := range c.config.SlaveNodes {
-		if c.config.SlaveNodes[i].IsActive {
-			if bestSlave == nil || c.config.SlaveNodes[i].Weight > bestSlave.Weight {
-				bestSlave = &c.config.SlaveNodes[i]
-			}
+// triggerFailover aktif slave'leri ağırlığa göre sıralar ve güvenlik kontrollerinden
+// (sağlıklı, erişilebilir, replikasyon gecikmesi MaxReplicationLag altında) geçen ilk
+// adayı master'a yükseltir. Uygun aday yoksa failover reddedilir.
+func (c *DatabaseCluster) triggerFailover() error {
+	c.mu.RLock()
+	candidates := make([]DatabaseNode, 0, len(c.config.SlaveNodes))
+	for _, slave := range c.config.SlaveNodes {
+		if slave.IsActive {
+			candidates = append(candidates, slave)
 		}
 	}
+	c.mu.RUnlock()
 
-	if bestSlave != nil {
-		oldMaster := c.config.MasterNode
-		c.config.MasterNode = *bestSlave
-		c.config.MasterNode.Role = "master"
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Weight > candidates[j].Weight
+	})
 
-		if newMasterDB, err := c.connectToNode(c.config.MasterNode); err == nil {
-			c.masterDB = newMasterDB
+	for _, candidate := range candidates {
+		candidateDB, err := c.verifyPromotionCandidate(candidate)
+		if err != nil {
+			fmt.Printf("Warning: skipping failover candidate %s: %v\n", candidate.Name, err)
+			continue
 		}
 
-		oldMaster.Role = "slave"
-		c.config.SlaveNodes = append(c.config.SlaveNodes, oldMaster)
+		c.promote(candidate, candidateDB)
+		fmt.Printf("Failover completed: %s promoted to master\n", candidate.Name)
+		return nil
+	}
+
+	fmt.Printf("ALERT: failover refused, no promotable replica among %d candidates\n", len(candidates))
+	return ErrNoPromotableReplica
+}
+
+// verifyPromotionCandidate adayın erişilebilir olduğunu ve replikasyon gecikmesinin
+// MaxReplicationLag altında kaldığını doğrular; kontroller geçerse açılan bağlantıyı döner.
+func (c *DatabaseCluster) verifyPromotionCandidate(node DatabaseNode) (*gorm.DB, error) {
+	db, lag, err := c.probeCandidate(node)
+	if err != nil {
+		return nil, err
+	}
 
-		fmt.Printf("Failover completed: %s promoted to master\n", bestSlave.Name)
+	if c.config.MaxReplicationLag > 0 && lag > c.config.MaxReplicationLag {
+		closeDB(db)
+		return nil, fmt.Errorf("replication lag %s exceeds %s", lag, c.config.MaxReplicationLag)
 	}
+
+	return db, nil
 }
 
-func (c *DatabaseCluster) GetHealthStatus() map[string]HealthCheckResult {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+// connectAndMeasureLag adaya bağlanır, erişilebilirliğini kontrol eder ve replikasyon gecikmesini okur
+func (c *DatabaseCluster) connectAndMeasureLag(node DatabaseNode) (*gorm.DB, time.Duration, error) {
+	db, err := c.connectToNode(node)
+	if err != nil {
+		return nil, 0, fmt.Errorf("unreachable: %w", err)
+	}
 
-	status := make(map[string]HealthCheckResult)
+	ctx, cancel := context.WithTimeout(c.ctx, c.config.HealthCheckTimeout)
+	defer cancel()
 
-	for {
-		select {
-		case result := <-c.healthChan:
-			status[result.Node.Name] = result
-		default:
-			goto done
-		}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, 0, fmt.Errorf("unreachable: %w", err)
+	}
+
+	if err := sqlDB.PingContext(ctx); err != nil {
+		sqlDB.Close()
+		return nil, 0, fmt.Errorf("unreachable: %w", err)
+	}
+
+	lag, err := replicationLag(ctx, db)
+	if err != nil {
+		sqlDB.Close()
+		return nil, 0, fmt.Errorf("failed to read replication lag: %w", err)
+	}
+
+	return db, lag, nil
+}
+
+func closeDB(db *gorm.DB) {
+	if db == nil {
+		return
+	}
+	if sqlDB, err := db.DB(); err == nil {
+		sqlDB.Close()
+	}
+}
+
+// replicationLag replica'nın son uygulanan işlemden bu yana geçen süresini döner.
+// Alınan ve uygulanan WAL konumları eşitse replica güncel kabul edilir.
+func replicationLag(ctx context.Context, db *gorm.DB) (time.Duration, error) {
+	var seconds float64
+	err := db.WithContext(ctx).Raw(`
+		SELECT CASE
+			WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+			ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)
+		END`).Scan(&seconds).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
+func (c *DatabaseCluster) promote(node DatabaseNode, db *gorm.DB) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	oldMaster := c.config.MasterNode
+	c.config.MasterNode = node
+	c.config.MasterNode.Role = "master"
+	c.masterDB = db
+
+	oldMaster.Role = "slave"
+	c.config.SlaveNodes = append(c.config.SlaveNodes, oldMaster)
+}
+
+// GetHealthStatus her node için bilinen son health check sonucunun bir kopyasını döner.
+// Henüz kontrol edilmemiş node'lar "unknown" durumuyla yer alır.
+func (c *DatabaseCluster) GetHealthStatus() map[string]HealthCheckResult {
+	c.statusMu.RLock()
+	defer c.statusMu.RUnlock()
+
+	status := make(map[string]HealthCheckResult, len(c.lastStatus))
+	for name, result := range c.lastStatus {
+		status[name] = result
 	}
-done:
 
 	return status
 }
 
+// Done health check goroutine'i durduğunda kapanan kanalı döner
+func (c *DatabaseCluster) Done() <-chan struct{} {
+	return c.done
+}
+
 func (c *DatabaseCluster) Close() error {
 	c.cancel()
 
@@ -0,0 +1,217 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func newTestCluster(t *testing.T, config ReplicationConfig) *DatabaseCluster {
+	t.Helper()
+	cluster := newDatabaseCluster(context.Background(), config)
+	t.Cleanup(cluster.cancel)
+	return cluster
+}
+
+func testReplicationConfig() ReplicationConfig {
+	return ReplicationConfig{
+		MasterNode: DatabaseNode{Name: "master", Role: "master", IsActive: true},
+		SlaveNodes: []DatabaseNode{
+			{Name: "slave-1", Role: "slave", Weight: 10, IsActive: true},
+			{Name: "slave-2", Role: "slave", Weight: 5, IsActive: true},
+		},
+		ReadReplicas: []DatabaseNode{
+			{Name: "read-1", Role: "read_replica", Weight: 1, IsActive: true},
+		},
+	}
+}
+
+func TestGetHealthStatusIsConsistent(t *testing.T) {
+	config := testReplicationConfig()
+
+	tests := []struct {
+		name    string
+		results []HealthCheckResult
+	}{
+		{
+			name: "hiç kontrol yapılmadan",
+		},
+		{
+			name: "kontrol sonuçları kaydedildikten sonra",
+			results: []HealthCheckResult{
+				{Node: config.MasterNode, Status: "healthy"},
+				{Node: config.SlaveNodes[0], Status: "unhealthy", Error: errors.New("timeout")},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := newTestCluster(t, config)
+			for _, result := range tt.results {
+				cluster.recordHealthResult(result)
+			}
+
+			first := cluster.GetHealthStatus()
+			second := cluster.GetHealthStatus()
+			if !reflect.DeepEqual(first, second) {
+				t.Fatalf("ardışık çağrılar farklı: %v, %v", first, second)
+			}
+
+			// Dönen harita bir kopya olmalı; değiştirmek sonraki çağrıları etkilememeli
+			delete(first, "master")
+			if _, ok := cluster.GetHealthStatus()["master"]; !ok {
+				t.Errorf("dönen haritayı değiştirmek cluster durumunu etkiledi")
+			}
+		})
+	}
+}
+
+func TestGetHealthStatusKeepsAllNodes(t *testing.T) {
+	config := testReplicationConfig()
+
+	tests := []struct {
+		name       string
+		results    []HealthCheckResult
+		wantStatus map[string]string
+	}{
+		{
+			name: "kontrol edilmemiş node'lar unknown",
+			wantStatus: map[string]string{
+				"master": "unknown", "slave-1": "unknown", "slave-2": "unknown", "read-1": "unknown",
+			},
+		},
+		{
+			name: "tek node kontrolü diğerlerini silmez",
+			results: []HealthCheckResult{
+				{Node: config.SlaveNodes[1], Status: "healthy"},
+			},
+			wantStatus: map[string]string{
+				"master": "unknown", "slave-1": "unknown", "slave-2": "healthy", "read-1": "unknown",
+			},
+		},
+		{
+			name: "her node için son sonuç tutulur",
+			results: []HealthCheckResult{
+				{Node: config.MasterNode, Status: "healthy"},
+				{Node: config.SlaveNodes[0], Status: "healthy"},
+				{Node: config.SlaveNodes[1], Status: "healthy"},
+				{Node: config.ReadReplicas[0], Status: "healthy"},
+				{Node: config.MasterNode, Status: "unhealthy", Error: errors.New("timeout")},
+			},
+			wantStatus: map[string]string{
+				"master": "unhealthy", "slave-1": "healthy", "slave-2": "healthy", "read-1": "healthy",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := newTestCluster(t, config)
+			for _, result := range tt.results {
+				cluster.recordHealthResult(result)
+			}
+
+			// Kanalı okuyan başka bir tüketici sonuçları haritadan eksiltmemeli
+			for len(cluster.healthChan) > 0 {
+				<-cluster.healthChan
+			}
+
+			status := cluster.GetHealthStatus()
+			if len(status) != len(tt.wantStatus) {
+				t.Fatalf("%d node döndü, beklenen %d: %v", len(status), len(tt.wantStatus), status)
+			}
+			for name, want := range tt.wantStatus {
+				if got := status[name].Status; got != want {
+					t.Errorf("%s durumu = %q, beklenen %q", name, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestTriggerFailoverSkipsUnsafeReplicas(t *testing.T) {
+	tests := []struct {
+		name       string
+		slaves     []DatabaseNode
+		lag        map[string]time.Duration
+		unreach    map[string]bool
+		wantMaster string
+		wantErr    error
+	}{
+		{
+			name: "gecikmeli en ağır slave atlanır",
+			slaves: []DatabaseNode{
+				{Name: "laggy", Weight: 10, IsActive: true},
+				{Name: "healthy", Weight: 5, IsActive: true},
+			},
+			lag:        map[string]time.Duration{"laggy": time.Minute, "healthy": time.Second},
+			wantMaster: "healthy",
+		},
+		{
+			name: "erişilemeyen aday atlanır",
+			slaves: []DatabaseNode{
+				{Name: "down", Weight: 10, IsActive: true},
+				{Name: "healthy", Weight: 5, IsActive: true},
+			},
+			unreach:    map[string]bool{"down": true},
+			wantMaster: "healthy",
+		},
+		{
+			name: "pasif slave aday olmaz",
+			slaves: []DatabaseNode{
+				{Name: "inactive", Weight: 20},
+				{Name: "healthy", Weight: 5, IsActive: true},
+			},
+			wantMaster: "healthy",
+		},
+		{
+			name: "uygun aday yoksa failover reddedilir",
+			slaves: []DatabaseNode{
+				{Name: "laggy-1", Weight: 10, IsActive: true},
+				{Name: "laggy-2", Weight: 5, IsActive: true},
+			},
+			lag:        map[string]time.Duration{"laggy-1": time.Minute, "laggy-2": time.Hour},
+			wantMaster: "master",
+			wantErr:    ErrNoPromotableReplica,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := newTestCluster(t, ReplicationConfig{
+				MasterNode:        DatabaseNode{Name: "master", Role: "master"},
+				SlaveNodes:        tt.slaves,
+				MaxReplicationLag: 10 * time.Second,
+			})
+			cluster.probeCandidate = func(node DatabaseNode) (*gorm.DB, time.Duration, error) {
+				if tt.unreach[node.Name] {
+					return nil, 0, errors.New("unreachable")
+				}
+				return nil, tt.lag[node.Name], nil
+			}
+
+			if err := cluster.triggerFailover(); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("triggerFailover = %v, beklenen %v", err, tt.wantErr)
+			}
+
+			master := cluster.config.MasterNode
+			if master.Name != tt.wantMaster {
+				t.Fatalf("master = %q, beklenen %q", master.Name, tt.wantMaster)
+			}
+			if tt.wantErr == nil {
+				if master.Role != "master" {
+					t.Errorf("yükseltilen node rolü = %q", master.Role)
+				}
+				last := cluster.config.SlaveNodes[len(cluster.config.SlaveNodes)-1]
+				if last.Name != "master" || last.Role != "slave" {
+					t.Errorf("eski master slave listesine eklenmedi: %+v", last)
+				}
+			}
+		})
+	}
+}
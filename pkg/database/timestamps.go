@@ -0,0 +1,79 @@
+package database
+
+import (
+	"reflect"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// timestampPrecision veritabanının zaman damgası hassasiyeti; init.sql'deki TIMESTAMP sütunları
+// saniye hassasiyetindedir. Bellekteki değer kayıttaki ile aynı kalsın diye tüm zaman damgaları bu
+// hassasiyete kırpılır.
+const timestampPrecision = time.Second
+
+// Now gorm'un CreatedAt/UpdatedAt alanlarına yazdığı zamanı üretir. Değer UTC'dir ve veritabanı
+// hassasiyetine kırpılır; böylece kaydedilen nesnenin UpdatedAt'i yeniden okunan satırla aynıdır.
+func Now() time.Time {
+	return normalizeTimestamp(time.Now())
+}
+
+func normalizeTimestamp(t time.Time) time.Time {
+	return t.UTC().Truncate(timestampPrecision)
+}
+
+// Timestamps CreatedAt/UpdatedAt alanlarını tek bir yerde yöneten gorm eklentisidir. gorm güncellemede
+// UpdatedAt'i zaten NowFunc ile yeniler; eklenti ise oluşturma sırasında çağıranın (ör. sahte saatle
+// çalışan bir constructor'ın) verdiği zaman damgalarını aynı UTC ve hassasiyete çeker. Boş bırakılan
+// alanlar gorm tarafından NowFunc ile doldurulur.
+type Timestamps struct{}
+
+func (Timestamps) Name() string {
+	return "timestamps"
+}
+
+func (Timestamps) Initialize(db *gorm.DB) error {
+	return db.Callback().Create().Before("gorm:create").Register("timestamps:normalize", normalizeCreateTimestamps)
+}
+
+func normalizeCreateTimestamps(db *gorm.DB) {
+	if db.Statement.Schema == nil || db.Statement.SkipHooks {
+		return
+	}
+
+	var fields []*schema.Field
+	for _, field := range db.Statement.Schema.Fields {
+		if field.AutoCreateTime > 0 || field.AutoUpdateTime > 0 {
+			fields = append(fields, field)
+		}
+	}
+	if len(fields) == 0 {
+		return
+	}
+
+	rv := db.Statement.ReflectValue
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			normalizeRowTimestamps(db, fields, reflect.Indirect(rv.Index(i)))
+		}
+	case reflect.Struct:
+		normalizeRowTimestamps(db, fields, rv)
+	}
+}
+
+func normalizeRowTimestamps(db *gorm.DB, fields []*schema.Field, row reflect.Value) {
+	if row.Kind() != reflect.Struct {
+		return
+	}
+	for _, field := range fields {
+		value, isZero := field.ValueOf(db.Statement.Context, row)
+		if isZero {
+			continue
+		}
+		if t, ok := value.(time.Time); ok {
+			db.AddError(field.Set(db.Statement.Context, row, normalizeTimestamp(t)))
+		}
+	}
+}
@@ -2,10 +2,13 @@ package domain
 
 import (
 	"encoding/json"
+	"math"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 // Currency para birimi
@@ -18,6 +21,42 @@ const (
 	CurrencyGBP Currency = "GBP"
 )
 
+func (c Currency) IsSupported() bool {
+	switch c {
+	case CurrencyUSD, CurrencyEUR, CurrencyTRY, CurrencyGBP:
+		return true
+	}
+	return false
+}
+
+const (
+	// DefaultMaxBatchSize batch başına izin verilen varsayılan kalem sayısı
+	DefaultMaxBatchSize = 1000
+	// HardMaxBatchSize yapılandırmayla aşılamayan üst sınır
+	HardMaxBatchSize = 10000
+)
+
+var maxBatchSize atomic.Int64
+
+func init() {
+	maxBatchSize.Store(DefaultMaxBatchSize)
+}
+
+// SetMaxBatchSize tek batch'te kabul edilen kalem sayısını ayarlar; değer pozitif olmalı ve
+// HardMaxBatchSize'ı aşmamalıdır
+func SetMaxBatchSize(size int) error {
+	if size <= 0 || size > HardMaxBatchSize {
+		return ErrInvalidBatchSize
+	}
+	maxBatchSize.Store(int64(size))
+	return nil
+}
+
+// MaxBatchSize yapılandırılmış batch başına kalem sınırını döner
+func MaxBatchSize() int {
+	return int(maxBatchSize.Load())
+}
+
 type ExchangeRate struct {
 	FromCurrency Currency  `json:"from_currency"`
 	ToCurrency   Currency  `json:"to_currency"`
@@ -27,38 +66,67 @@ type ExchangeRate struct {
 }
 
 type ScheduledTransaction struct {
-	ID              uuid.UUID       `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
-	UserID          uuid.UUID       `json:"user_id" gorm:"type:uuid;not null"`
-	Type            TransactionType `json:"type" gorm:"type:varchar(20);not null"`
-	Amount          float64         `json:"amount" gorm:"type:decimal(19,4);not null"`
-	Currency        Currency        `json:"currency" gorm:"type:varchar(3);not null;default:'USD'"`
-	Description     string          `json:"description" gorm:"type:text"`
-	ReferenceID     string          `json:"reference_id" gorm:"type:varchar(100)"`
-	ToUserID        *uuid.UUID      `json:"to_user_id,omitempty" gorm:"type:uuid"`
-	ScheduledAt     time.Time       `json:"scheduled_at" gorm:"not null;index"`
-	Status          string          `json:"status" gorm:"type:varchar(20);not null;default:'pending'"`
-	RecurringType   *string         `json:"recurring_type,omitempty" gorm:"type:varchar(20)"`
-	RecurringConfig *string         `json:"recurring_config,omitempty" gorm:"type:jsonb"`
-	MaxRetries      int             `json:"max_retries" gorm:"not null;default:3"`
-	RetryCount      int             `json:"retry_count" gorm:"not null;default:0"`
-	LastRetryAt     *time.Time      `json:"last_retry_at,omitempty"`
-	NextRetryAt     *time.Time      `json:"next_retry_at,omitempty"`
-	CreatedAt       time.Time       `json:"created_at" gorm:"not null"`
-	UpdatedAt       time.Time       `json:"updated_at" gorm:"not null"`
-	mu              sync.RWMutex    `json:"-"`
+	ID              uuid.UUID        `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	UserID          uuid.UUID        `json:"user_id" gorm:"type:uuid;not null"`
+	Type            TransactionType  `json:"type" gorm:"type:varchar(20);not null"`
+	Amount          float64          `json:"amount" gorm:"type:decimal(19,4);not null"`
+	Currency        Currency         `json:"currency" gorm:"type:varchar(3);not null;default:'USD'"`
+	Description     string           `json:"description" gorm:"type:text"`
+	ReferenceID     string           `json:"reference_id" gorm:"type:varchar(100)"`
+	ToUserID        *uuid.UUID       `json:"to_user_id,omitempty" gorm:"type:uuid"`
+	ScheduledAt     time.Time        `json:"scheduled_at" gorm:"not null;index"`
+	Timezone        string           `json:"timezone" gorm:"type:varchar(64);not null;default:'UTC'"`
+	Status          string           `json:"status" gorm:"type:varchar(20);not null;default:'pending'"`
+	RecurringType   *string          `json:"recurring_type,omitempty" gorm:"type:varchar(20)"`
+	RecurringConfig *string          `json:"recurring_config,omitempty" gorm:"type:jsonb"`
+	OccurrenceCount int              `json:"occurrence_count" gorm:"not null;default:0"`
+	ExecutionWindow *ExecutionWindow `json:"execution_window,omitempty" gorm:"type:jsonb"`
+	MaxRetries      int              `json:"max_retries" gorm:"not null;default:3"`
+	RetryCount      int              `json:"retry_count" gorm:"not null;default:0"`
+	LastRetryAt     *time.Time       `json:"last_retry_at,omitempty"`
+	NextRetryAt     *time.Time       `json:"next_retry_at,omitempty"`
+	CreatedAt       time.Time        `json:"created_at" gorm:"not null"`
+	UpdatedAt       time.Time        `json:"updated_at" gorm:"not null"`
+	DeletedAt       gorm.DeletedAt   `json:"deleted_at,omitempty" gorm:"index"`
+
+	// LeaseOwner işlemi çalıştırmak üzere kiralayan zamanlayıcı örneği; LeaseExpiresAt dolduğunda
+	// çöken örneğin bıraktığı işlem başka bir örnek tarafından yeniden alınabilir
+	LeaseOwner     *string    `json:"lease_owner,omitempty" gorm:"type:varchar(100)"`
+	LeaseExpiresAt *time.Time `json:"lease_expires_at,omitempty"`
+
+	// ExternalAccount transferin sistem dışı bir hesaba yapılacağını gösterir; ToUserID ile birlikte verilemez
+	ExternalAccount *ExternalAccount `json:"external_account,omitempty" gorm:"type:jsonb"`
+
+	mu sync.RWMutex `json:"-"`
 }
 
 type ScheduledTransactionRequest struct {
-	Type            TransactionType `json:"type" binding:"required"`
-	Amount          float64         `json:"amount" binding:"required,gt=0"`
-	Currency        Currency        `json:"currency" binding:"required"`
-	Description     string          `json:"description"`
-	ReferenceID     string          `json:"reference_id"`
-	ToUserID        *uuid.UUID      `json:"to_user_id,omitempty"`
-	ScheduledAt     time.Time       `json:"scheduled_at" binding:"required"`
-	RecurringType   *string         `json:"recurring_type,omitempty"`
-	RecurringConfig *string         `json:"recurring_config,omitempty"`
-	MaxRetries      *int            `json:"max_retries,omitempty"`
+	Type            TransactionType  `json:"type" binding:"required"`
+	Amount          float64          `json:"amount" binding:"required,gt=0"`
+	Currency        Currency         `json:"currency" binding:"required"`
+	Description     string           `json:"description"`
+	ReferenceID     string           `json:"reference_id"`
+	ToUserID        *uuid.UUID       `json:"to_user_id,omitempty"`
+	ScheduledAt     time.Time        `json:"scheduled_at" binding:"required"`
+	Timezone        string           `json:"timezone,omitempty"`
+	RecurringType   *string          `json:"recurring_type,omitempty"`
+	RecurringConfig *string          `json:"recurring_config,omitempty"`
+	ExecutionWindow *ExecutionWindow `json:"execution_window,omitempty"`
+	MaxRetries      *int             `json:"max_retries,omitempty"`
+	ExternalAccount *ExternalAccount `json:"external_account,omitempty"`
+}
+
+// MaxBulkScheduledTransactions tek bir toplu istekte oluşturulabilecek en fazla zamanlanmış işlem sayısı
+const MaxBulkScheduledTransactions = 100
+
+type BulkScheduledTransactionRequest struct {
+	Items []ScheduledTransactionRequest `json:"items" binding:"required,min=1"`
+}
+
+// BulkItemError toplu istekte geçersiz olan kalemin sırasını ve nedenini taşır
+type BulkItemError struct {
+	Index int    `json:"index"`
+	Error string `json:"error"`
 }
 
 type BatchTransaction struct {
@@ -73,41 +141,85 @@ type BatchTransaction struct {
 	ProcessedAt *time.Time      `json:"processed_at,omitempty"`
 	CreatedAt   time.Time       `json:"created_at" gorm:"not null"`
 	UpdatedAt   time.Time       `json:"updated_at" gorm:"not null"`
+	DeletedAt   gorm.DeletedAt  `json:"deleted_at,omitempty" gorm:"index"`
 	mu          sync.RWMutex    `json:"-"`
 }
 
 type BatchTransactionItem struct {
-	ID            uuid.UUID  `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
-	BatchID       uuid.UUID  `json:"batch_id" gorm:"type:uuid;not null"`
-	TransactionID uuid.UUID  `json:"transaction_id" gorm:"type:uuid;not null"`
-	Amount        float64    `json:"amount" gorm:"type:decimal(19,4);not null"`
-	Description   string     `json:"description" gorm:"type:text"`
-	ReferenceID   string     `json:"reference_id" gorm:"type:varchar(100)"`
-	Status        string     `json:"status" gorm:"type:varchar(20);not null;default:'pending'"`
-	ErrorMessage  *string    `json:"error_message,omitempty" gorm:"type:text"`
-	ProcessedAt   *time.Time `json:"processed_at,omitempty"`
-	CreatedAt     time.Time  `json:"created_at" gorm:"not null"`
-	UpdatedAt     time.Time  `json:"updated_at" gorm:"not null"`
+	ID            uuid.UUID      `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	BatchID       uuid.UUID      `json:"batch_id" gorm:"type:uuid;not null"`
+	TransactionID uuid.UUID      `json:"transaction_id" gorm:"type:uuid;not null"`
+	Amount        float64        `json:"amount" gorm:"type:decimal(19,4);not null"`
+	Description   string         `json:"description" gorm:"type:text"`
+	ReferenceID   string         `json:"reference_id" gorm:"type:varchar(100)"`
+	Status        string         `json:"status" gorm:"type:varchar(20);not null;default:'pending'"`
+	ErrorMessage  *string        `json:"error_message,omitempty" gorm:"type:text"`
+	ProcessedAt   *time.Time     `json:"processed_at,omitempty"`
+	CreatedAt     time.Time      `json:"created_at" gorm:"not null"`
+	UpdatedAt     time.Time      `json:"updated_at" gorm:"not null"`
+	DeletedAt     gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
 }
 
 type BatchTransactionRequest struct {
 	Type        TransactionType `json:"type" binding:"required"`
 	Currency    Currency        `json:"currency" binding:"required"`
 	Description string          `json:"description"`
-	Items       []BatchItem     `json:"items" binding:"required,min=1,max=1000"`
+	// TotalAmount verilirse kalem tutarları yazılmaz; toplam kalemlerin Weight'lerine göre bölüştürülür
+	TotalAmount float64     `json:"total_amount,omitempty"`
+	Items       []BatchItem `json:"items" binding:"required,min=1"`
 }
 
 type BatchItem struct {
-	Amount      float64    `json:"amount" binding:"required,gt=0"`
+	Amount      float64    `json:"amount"`
 	Description string     `json:"description"`
 	ReferenceID string     `json:"reference_id"`
 	ToUserID    *uuid.UUID `json:"to_user_id,omitempty"`
+	// Weight TotalAmount bölüştürülürken kalemin payıdır; verilmezse tüm kalemler eşit pay alır
+	Weight float64 `json:"weight,omitempty"`
 }
 
+// ResolveAmounts TotalAmount verilmişse kalem tutarlarını Allocate ile doldurur; paylar toplamı
+// tam olarak TotalAmount'a eşittir. TotalAmount yoksa kalem tutarları olduğu gibi kalır.
+func (req *BatchTransactionRequest) ResolveAmounts() error {
+	if req.TotalAmount == 0 {
+		return nil
+	}
+	if len(req.Items) == 0 {
+		return ErrInvalidBatchItems
+	}
+
+	weights := make([]float64, len(req.Items))
+	for i, item := range req.Items {
+		if item.Amount != 0 {
+			return ErrInvalidBatchSplit
+		}
+		weights[i] = item.Weight
+		if weights[i] == 0 {
+			weights[i] = 1
+		}
+	}
+
+	amounts, err := Allocate(req.TotalAmount, weights)
+	if err != nil {
+		return err
+	}
+	for i := range req.Items {
+		req.Items[i].Amount = amounts[i]
+	}
+	return nil
+}
+
+// Adet limiti tanımlanmamış kayıtlar için dönem başına izin verilen en fazla işlem sayısı
+const (
+	DefaultDailyCountLimit   = 100
+	DefaultWeeklyCountLimit  = 7 * DefaultDailyCountLimit
+	DefaultMonthlyCountLimit = 30 * DefaultDailyCountLimit
+)
+
 type TransactionLimit struct {
 	ID            uuid.UUID    `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
-	UserID        uuid.UUID    `json:"user_id" gorm:"type:uuid;not null;uniqueIndex"`
-	Currency      Currency     `json:"currency" gorm:"type:varchar(3);not null"`
+	UserID        uuid.UUID    `json:"user_id" gorm:"type:uuid;not null;uniqueIndex:idx_transaction_limits_user_currency"`
+	Currency      Currency     `json:"currency" gorm:"type:varchar(3);not null;uniqueIndex:idx_transaction_limits_user_currency"`
 	DailyLimit    float64      `json:"daily_limit" gorm:"type:decimal(19,4);not null"`
 	WeeklyLimit   float64      `json:"weekly_limit" gorm:"type:decimal(19,4);not null"`
 	MonthlyLimit  float64      `json:"monthly_limit" gorm:"type:decimal(19,4);not null"`
@@ -120,9 +232,17 @@ type TransactionLimit struct {
 	MonthlyAmount float64      `json:"monthly_amount" gorm:"type:decimal(19,4);not null;default:0"`
 	LastResetDate time.Time    `json:"last_reset_date" gorm:"not null"`
 	IsActive      bool         `json:"is_active" gorm:"not null;default:true"`
+	Tier          LimitTier    `json:"tier,omitempty" gorm:"type:varchar(20);not null;default:''"`
 	CreatedAt     time.Time    `json:"created_at" gorm:"not null"`
 	UpdatedAt     time.Time    `json:"updated_at" gorm:"not null"`
 	mu            sync.RWMutex `json:"-"`
+
+	// Dönem başına işlem adedi limitleri ve haftalık/aylık sayaçların sıfırlandığı zamanlar
+	DailyCountLimit   int       `json:"daily_count_limit" gorm:"not null;default:100"`
+	WeeklyCountLimit  int       `json:"weekly_count_limit" gorm:"not null;default:700"`
+	MonthlyCountLimit int       `json:"monthly_count_limit" gorm:"not null;default:3000"`
+	WeeklyResetDate   time.Time `json:"weekly_reset_date" gorm:"not null"`
+	MonthlyResetDate  time.Time `json:"monthly_reset_date" gorm:"not null"`
 }
 
 type TransactionLimitRequest struct {
@@ -131,6 +251,37 @@ type TransactionLimitRequest struct {
 	WeeklyLimit  float64  `json:"weekly_limit" binding:"required,gt=0"`
 	MonthlyLimit float64  `json:"monthly_limit" binding:"required,gt=0"`
 	SingleLimit  float64  `json:"single_limit" binding:"required,gt=0"`
+
+	// Adet limitleri isteğe bağlıdır; verilmezse varsayılan değerler kullanılır
+	DailyCountLimit   int `json:"daily_count_limit" binding:"omitempty,gt=0"`
+	WeeklyCountLimit  int `json:"weekly_count_limit" binding:"omitempty,gt=0"`
+	MonthlyCountLimit int `json:"monthly_count_limit" binding:"omitempty,gt=0"`
+}
+
+// Validate limit tutarlarının pozitif, adet limitlerinin verilmişse pozitif olduğunu kontrol eder
+func (r TransactionLimitRequest) Validate() error {
+	if r.DailyLimit <= 0 || r.WeeklyLimit <= 0 || r.MonthlyLimit <= 0 || r.SingleLimit <= 0 {
+		return ErrInvalidLimit
+	}
+	if r.DailyCountLimit < 0 || r.WeeklyCountLimit < 0 || r.MonthlyCountLimit < 0 {
+		return ErrInvalidLimit
+	}
+	return nil
+}
+
+// countLimits verilmeyen adet limitlerini varsayılanlarla doldurur
+func (r TransactionLimitRequest) countLimits() (daily, weekly, monthly int) {
+	daily, weekly, monthly = r.DailyCountLimit, r.WeeklyCountLimit, r.MonthlyCountLimit
+	if daily == 0 {
+		daily = DefaultDailyCountLimit
+	}
+	if weekly == 0 {
+		weekly = DefaultWeeklyCountLimit
+	}
+	if monthly == 0 {
+		monthly = DefaultMonthlyCountLimit
+	}
+	return daily, weekly, monthly
 }
 
 type MultiCurrencyBalance struct {
@@ -177,6 +328,29 @@ func NewScheduledTransaction(userID uuid.UUID, req ScheduledTransactionRequest)
 		return nil, ErrInvalidScheduledTime
 	}
 
+	timezone := req.Timezone
+	if timezone == "" {
+		timezone = DefaultTimezone
+	}
+	if _, err := LoadTimezone(timezone); err != nil {
+		return nil, err
+	}
+
+	if req.ExecutionWindow != nil {
+		if err := req.ExecutionWindow.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.ExternalAccount != nil {
+		if req.Type != TransactionTypeTransfer || req.ToUserID != nil {
+			return nil, ErrInvalidTransferDestination
+		}
+		if err := req.ExternalAccount.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
 	maxRetries := 3
 	if req.MaxRetries != nil {
 		maxRetries = *req.MaxRetries
@@ -191,10 +365,13 @@ func NewScheduledTransaction(userID uuid.UUID, req ScheduledTransactionRequest)
 		Description:     req.Description,
 		ReferenceID:     req.ReferenceID,
 		ToUserID:        req.ToUserID,
-		ScheduledAt:     req.ScheduledAt,
+		ScheduledAt:     req.ScheduledAt.UTC(),
+		Timezone:        timezone,
 		Status:          "pending",
 		RecurringType:   req.RecurringType,
 		RecurringConfig: req.RecurringConfig,
+		ExecutionWindow: req.ExecutionWindow,
+		ExternalAccount: req.ExternalAccount,
 		MaxRetries:      maxRetries,
 		RetryCount:      0,
 		CreatedAt:       time.Now(),
@@ -207,17 +384,19 @@ func NewBatchTransaction(userID uuid.UUID, req BatchTransactionRequest) (*BatchT
 		return nil, ErrInvalidBatchItems
 	}
 
-	if len(req.Items) > 1000 {
+	if len(req.Items) > MaxBatchSize() {
 		return nil, ErrBatchSizeExceeded
 	}
 
-	totalAmount := 0.0
+	// Toplam en küçük birim cinsinden tam sayılarla tutulur; float toplamı kalem sayısı arttıkça sapar
+	totalUnits := int64(0)
 	for _, item := range req.Items {
 		if item.Amount <= 0 {
 			return nil, ErrInvalidAmount
 		}
-		totalAmount += item.Amount
+		totalUnits += int64(math.Round(item.Amount * AllocationScale))
 	}
+	totalAmount := float64(totalUnits) / AllocationScale
 
 	return &BatchTransaction{
 		ID:          uuid.New(),
@@ -234,10 +413,11 @@ func NewBatchTransaction(userID uuid.UUID, req BatchTransactionRequest) (*BatchT
 }
 
 func NewTransactionLimit(userID uuid.UUID, req TransactionLimitRequest) (*TransactionLimit, error) {
-	if req.DailyLimit <= 0 || req.WeeklyLimit <= 0 || req.MonthlyLimit <= 0 || req.SingleLimit <= 0 {
-		return nil, ErrInvalidLimit
+	if err := req.Validate(); err != nil {
+		return nil, err
 	}
 
+	dailyCount, weeklyCount, monthlyCount := req.countLimits()
 	return &TransactionLimit{
 		ID:            uuid.New(),
 		UserID:        userID,
@@ -256,6 +436,12 @@ func NewTransactionLimit(userID uuid.UUID, req TransactionLimitRequest) (*Transa
 		IsActive:      true,
 		CreatedAt:     time.Now(),
 		UpdatedAt:     time.Now(),
+
+		DailyCountLimit:   dailyCount,
+		WeeklyCountLimit:  weeklyCount,
+		MonthlyCountLimit: monthlyCount,
+		WeeklyResetDate:   time.Now(),
+		MonthlyResetDate:  time.Now(),
 	}, nil
 }
 
@@ -288,6 +474,104 @@ func (st *ScheduledTransaction) CanRetry() bool {
 	return st.Status == "failed" && st.RetryCount < st.MaxRetries
 }
 
+// IsModifiable işlemin güncellenebilir olup olmadığını döner; çalıştırılmakta olan veya sonuçlanmış
+// işlemler değiştirilemez
+func (st *ScheduledTransaction) IsModifiable() bool {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+
+	return isModifiableScheduledStatus(st.Status)
+}
+
+// ModifiableScheduledStatuses güncellemeye izin verilen durumlar
+var ModifiableScheduledStatuses = []string{"pending", "paused"}
+
+func isModifiableScheduledStatus(status string) bool {
+	for _, s := range ModifiableScheduledStatuses {
+		if status == s {
+			return true
+		}
+	}
+	return false
+}
+
+const (
+	// ScheduledStatusExecuting bir zamanlayıcı örneğinin kiraladığı ve çalıştırmakta olduğu işlemlerin durumu
+	ScheduledStatusExecuting = "executing"
+	// DefaultScheduledLeaseDuration kiralanan işlemin başka bir örnek tarafından geri alınabilmesi için geçmesi gereken süre
+	DefaultScheduledLeaseDuration = 5 * time.Minute
+)
+
+// ApplyLease repository'de kazanılan kirayı nesneye yansıtır; işlem executing durumuna geçer.
+// Sonraki UpdateStatus veya RecordFailure çağrısı kirayı bırakır.
+func (st *ScheduledTransaction) ApplyLease(owner string, expiresAt time.Time) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	st.Status = ScheduledStatusExecuting
+	st.LeaseOwner = &owner
+	st.LeaseExpiresAt = &expiresAt
+}
+
+func (st *ScheduledTransaction) releaseLease() {
+	st.LeaseOwner = nil
+	st.LeaseExpiresAt = nil
+}
+
+const (
+	// ScheduledRetryBaseBackoff ilk yeniden denemeden önce beklenen süre; her denemede iki katına çıkar
+	ScheduledRetryBaseBackoff = time.Minute
+	// ScheduledRetryMaxBackoff yeniden denemeler arasındaki en uzun bekleme
+	ScheduledRetryMaxBackoff = time.Hour
+)
+
+// ScheduledRetryBackoff retryCount. başarısızlıktan sonra bir sonraki denemeye kadar beklenecek süreyi döner
+func ScheduledRetryBackoff(retryCount int) time.Duration {
+	backoff := ScheduledRetryBaseBackoff
+	for i := 1; i < retryCount && backoff < ScheduledRetryMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > ScheduledRetryMaxBackoff {
+		backoff = ScheduledRetryMaxBackoff
+	}
+	return backoff
+}
+
+// RecordFailure başarısız çalıştırmayı kaydeder. Deneme hakkı kaldıysa işlem failed durumuna
+// alınır ve NextRetryAt backoff kadar ileri kurulur; haklar tükendiyse işlem iptal edilir.
+// İşlem yeniden denenecekse true döner.
+func (st *ScheduledTransaction) RecordFailure(now time.Time) bool {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	st.RetryCount++
+	st.LastRetryAt = &now
+	st.UpdatedAt = now
+	st.releaseLease()
+
+	if st.RetryCount < st.MaxRetries {
+		next := now.Add(ScheduledRetryBackoff(st.RetryCount))
+		st.Status = "failed"
+		st.NextRetryAt = &next
+		return true
+	}
+
+	st.Status = "cancelled"
+	st.NextRetryAt = nil
+	return false
+}
+
+// IsRetryDue işlem yeniden denenebilir durumdaysa ve backoff süresi dolduysa true döner
+func (st *ScheduledTransaction) IsRetryDue(now time.Time) bool {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+
+	return st.Status == "failed" &&
+		st.RetryCount < st.MaxRetries &&
+		st.NextRetryAt != nil &&
+		!st.NextRetryAt.After(now)
+}
+
 func (st *ScheduledTransaction) IncrementRetry() {
 	st.mu.Lock()
 	defer st.mu.Unlock()
@@ -304,6 +588,9 @@ func (st *ScheduledTransaction) UpdateStatus(status string) {
 
 	st.Status = status
 	st.UpdatedAt = time.Now()
+	if status != ScheduledStatusExecuting {
+		st.releaseLease()
+	}
 }
 
 func (bt *BatchTransaction) UpdateStatus(status string) {
@@ -334,7 +621,15 @@ func (tl *TransactionLimit) CheckSingleLimit(amount float64) error {
 	return nil
 }
 
-func (tl *TransactionLimit) CheckDailyLimit(amount float64) error {
+// Haftalık ve aylık sayaçlar, günlük sayaç gibi son sıfırlamadan itibaren kayan pencereyle sıfırlanır
+const (
+	weeklyLimitWindow  = 7 * 24 * time.Hour
+	monthlyLimitWindow = 30 * 24 * time.Hour
+)
+
+// CheckDailyLimit tutarın günlük, haftalık ve aylık tutar/adet limitlerine sığıp sığmadığını kontrol eder;
+// now dönem sıfırlamalarının hesaplandığı andır
+func (tl *TransactionLimit) CheckDailyLimit(amount float64, now time.Time) error {
 	tl.mu.Lock()
 	defer tl.mu.Unlock()
 
@@ -342,34 +637,136 @@ func (tl *TransactionLimit) CheckDailyLimit(amount float64) error {
 		return nil
 	}
 
-	if time.Now().Sub(tl.LastResetDate) >= 24*time.Hour {
-		tl.resetDailyLimits()
+	tl.rollPeriods(now)
+	return tl.checkPeriods(amount)
+}
+
+// ReserveDailyUsage tekil ve dönemsel limit kontrolünü ve kullanımın işlenmesini tek kilit altında
+// yapar; tutar limite sığmıyorsa kullanım değişmez
+func (tl *TransactionLimit) ReserveDailyUsage(amount float64, now time.Time) error {
+	tl.mu.Lock()
+	defer tl.mu.Unlock()
+
+	if !tl.IsActive {
+		return nil
 	}
 
-	if tl.DailyAmount+amount > tl.DailyLimit {
-		return ErrDailyLimitExceeded
+	if amount > tl.SingleLimit {
+		return ErrTransactionLimitExceeded
 	}
 
-	if tl.DailyCount >= 100 {
-		return ErrDailyCountExceeded
+	tl.rollPeriods(now)
+	if err := tl.checkPeriods(amount); err != nil {
+		return err
 	}
 
+	tl.addUsage(amount, now)
 	return nil
 }
 
-func (tl *TransactionLimit) UpdateDailyUsage(amount float64) {
+func (tl *TransactionLimit) UpdateDailyUsage(amount float64, now time.Time) {
 	tl.mu.Lock()
 	defer tl.mu.Unlock()
 
+	tl.rollPeriods(now)
+	tl.addUsage(amount, now)
+}
+
+func (tl *TransactionLimit) checkPeriods(amount float64) error {
+	if tl.DailyAmount+amount > tl.DailyLimit {
+		return ErrDailyLimitExceeded
+	}
+	if tl.DailyCount >= countLimitOrDefault(tl.DailyCountLimit, DefaultDailyCountLimit) {
+		return ErrDailyCountExceeded
+	}
+	if tl.WeeklyAmount+amount > tl.WeeklyLimit {
+		return ErrWeeklyLimitExceeded
+	}
+	if tl.WeeklyCount >= countLimitOrDefault(tl.WeeklyCountLimit, DefaultWeeklyCountLimit) {
+		return ErrWeeklyCountExceeded
+	}
+	if tl.MonthlyAmount+amount > tl.MonthlyLimit {
+		return ErrMonthlyLimitExceeded
+	}
+	if tl.MonthlyCount >= countLimitOrDefault(tl.MonthlyCountLimit, DefaultMonthlyCountLimit) {
+		return ErrMonthlyCountExceeded
+	}
+	return nil
+}
+
+func (tl *TransactionLimit) addUsage(amount float64, now time.Time) {
 	tl.DailyAmount += amount
 	tl.DailyCount++
-	tl.UpdatedAt = time.Now()
+	tl.WeeklyAmount += amount
+	tl.WeeklyCount++
+	tl.MonthlyAmount += amount
+	tl.MonthlyCount++
+	tl.UpdatedAt = now
+}
+
+// rollPeriods süresi dolan dönemlerin sayaçlarını sıfırlar
+func (tl *TransactionLimit) rollPeriods(now time.Time) {
+	if now.Sub(tl.LastResetDate) >= 24*time.Hour {
+		tl.resetDailyLimits(now)
+	}
+	if now.Sub(tl.WeeklyResetDate) >= weeklyLimitWindow {
+		tl.WeeklyAmount = 0
+		tl.WeeklyCount = 0
+		tl.WeeklyResetDate = now
+	}
+	if now.Sub(tl.MonthlyResetDate) >= monthlyLimitWindow {
+		tl.MonthlyAmount = 0
+		tl.MonthlyCount = 0
+		tl.MonthlyResetDate = now
+	}
 }
 
-func (tl *TransactionLimit) resetDailyLimits() {
+func (tl *TransactionLimit) resetDailyLimits(now time.Time) {
 	tl.DailyAmount = 0
 	tl.DailyCount = 0
-	tl.LastResetDate = time.Now()
+	tl.LastResetDate = now
+}
+
+// countLimitOrDefault adet limiti tanımlanmadan oluşturulmuş kayıtlar için varsayılanı döndürür
+func countLimitOrDefault(limit, defaultLimit int) int {
+	if limit <= 0 {
+		return defaultLimit
+	}
+	return limit
+}
+
+// Apply limit değerlerini istekle günceller ve kaydı kullanıcıya özel limite çevirir; kullanım sayaçlarına dokunmaz
+func (tl *TransactionLimit) Apply(req TransactionLimitRequest) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	tl.mu.Lock()
+	defer tl.mu.Unlock()
+
+	tl.Tier = ""
+	tl.DailyLimit = req.DailyLimit
+	tl.WeeklyLimit = req.WeeklyLimit
+	tl.MonthlyLimit = req.MonthlyLimit
+	tl.SingleLimit = req.SingleLimit
+	tl.DailyCountLimit, tl.WeeklyCountLimit, tl.MonthlyCountLimit = req.countLimits()
+	tl.UpdatedAt = time.Now()
+	return nil
+}
+
+// ResetUsage tüm dönemlerin kullanım sayaçlarını sıfırlar
+func (tl *TransactionLimit) ResetUsage(now time.Time) {
+	tl.mu.Lock()
+	defer tl.mu.Unlock()
+
+	tl.resetDailyLimits(now)
+	tl.WeeklyAmount = 0
+	tl.WeeklyCount = 0
+	tl.WeeklyResetDate = now
+	tl.MonthlyAmount = 0
+	tl.MonthlyCount = 0
+	tl.MonthlyResetDate = now
+	tl.UpdatedAt = now
 }
 
 func (mcb *MultiCurrencyBalance) Add(amount float64) error {
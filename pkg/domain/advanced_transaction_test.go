@@ -0,0 +1,142 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func batchRequest(items int) BatchTransactionRequest {
+	req := BatchTransactionRequest{Type: TransactionTypeCredit, Currency: CurrencyTRY}
+	for i := 0; i < items; i++ {
+		req.Items = append(req.Items, BatchItem{Amount: 1})
+	}
+	return req
+}
+
+func setTestMaxBatchSize(t *testing.T, size int) {
+	t.Helper()
+	previous := MaxBatchSize()
+	if err := SetMaxBatchSize(size); err != nil {
+		t.Fatalf("SetMaxBatchSize(%d): %v", size, err)
+	}
+	t.Cleanup(func() { _ = SetMaxBatchSize(previous) })
+}
+
+func TestSetMaxBatchSize(t *testing.T) {
+	tests := []struct {
+		name    string
+		size    int
+		wantErr error
+	}{
+		{name: "sıfır", size: 0, wantErr: ErrInvalidBatchSize},
+		{name: "negatif", size: -1, wantErr: ErrInvalidBatchSize},
+		{name: "üst sınırın üstü", size: HardMaxBatchSize + 1, wantErr: ErrInvalidBatchSize},
+		{name: "en küçük", size: 1},
+		{name: "üst sınır", size: HardMaxBatchSize},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			previous := MaxBatchSize()
+			t.Cleanup(func() { _ = SetMaxBatchSize(previous) })
+
+			err := SetMaxBatchSize(tt.size)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("SetMaxBatchSize(%d) = %v, beklenen %v", tt.size, err, tt.wantErr)
+			}
+			want := tt.size
+			if tt.wantErr != nil {
+				want = previous
+			}
+			if got := MaxBatchSize(); got != want {
+				t.Errorf("MaxBatchSize() = %d, beklenen %d", got, want)
+			}
+		})
+	}
+}
+
+func TestNewBatchTransactionSizeLimit(t *testing.T) {
+	setTestMaxBatchSize(t, 3)
+
+	tests := []struct {
+		name    string
+		items   int
+		wantErr error
+	}{
+		{name: "boş batch", items: 0, wantErr: ErrInvalidBatchItems},
+		{name: "tek kalem", items: 1},
+		{name: "sınırda", items: 3},
+		{name: "sınırın bir fazlası", items: 4, wantErr: ErrBatchSizeExceeded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			batch, err := NewBatchTransaction(uuid.New(), batchRequest(tt.items))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewBatchTransaction(%d kalem) = %v, beklenen %v", tt.items, err, tt.wantErr)
+			}
+			if err == nil && batch.ItemCount != tt.items {
+				t.Errorf("ItemCount = %d, beklenen %d", batch.ItemCount, tt.items)
+			}
+		})
+	}
+}
+
+func TestTransactionLimitCountBoundary(t *testing.T) {
+	tests := []struct {
+		name     string
+		daily    int
+		weekly   int
+		monthly  int
+		wantOK   int
+		wantErr  error
+		validate error
+	}{
+		{name: "günlük adet sınırı", daily: 3, wantOK: 3, wantErr: ErrDailyCountExceeded},
+		{name: "haftalık adet sınırı", daily: 5, weekly: 2, wantOK: 2, wantErr: ErrWeeklyCountExceeded},
+		{name: "aylık adet sınırı", daily: 5, weekly: 5, monthly: 4, wantOK: 4, wantErr: ErrMonthlyCountExceeded},
+		{name: "verilmezse varsayılan", wantOK: DefaultDailyCountLimit, wantErr: ErrDailyCountExceeded},
+		{name: "negatif adet limiti", daily: -1, validate: ErrInvalidLimit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := TransactionLimitRequest{
+				Currency:          CurrencyTRY,
+				DailyLimit:        1e6,
+				WeeklyLimit:       1e6,
+				MonthlyLimit:      1e6,
+				SingleLimit:       1e6,
+				DailyCountLimit:   tt.daily,
+				WeeklyCountLimit:  tt.weekly,
+				MonthlyCountLimit: tt.monthly,
+			}
+			limit, err := NewTransactionLimit(uuid.New(), req)
+			if !errors.Is(err, tt.validate) {
+				t.Fatalf("NewTransactionLimit = %v, beklenen %v", err, tt.validate)
+			}
+			if err != nil {
+				return
+			}
+
+			now := time.Now()
+			for i := 0; i < tt.wantOK; i++ {
+				if err := limit.ReserveDailyUsage(1, now); err != nil {
+					t.Fatalf("%d. işlem = %v, sınırın altında kabul edilmeli", i+1, err)
+				}
+			}
+			if err := limit.CheckDailyLimit(1, now); !errors.Is(err, tt.wantErr) {
+				t.Errorf("sınırdaki CheckDailyLimit = %v, beklenen %v", err, tt.wantErr)
+			}
+			if err := limit.ReserveDailyUsage(1, now); !errors.Is(err, tt.wantErr) {
+				t.Errorf("sınırdaki ReserveDailyUsage = %v, beklenen %v", err, tt.wantErr)
+			}
+			if limit.DailyCount != tt.wantOK {
+				t.Errorf("günlük adet = %d, beklenen %d; reddedilen işlem sayılmamalı", limit.DailyCount, tt.wantOK)
+			}
+		})
+	}
+}
@@ -53,13 +53,36 @@ func (a *BaseAggregate) AddEvent(event Event) {
 	a.uncommittedEvents = append(a.uncommittedEvents, event)
 }
 
+// AnyVersion EventAppend için sürüm kontrolü yapılmadan aggregate akışının sonuna eklemeyi ifade eder;
+// event sourcing kullanmayan yazma yolları (ör. itiraz, mutabakat event'leri) için uygundur
+const AnyVersion int64 = -1
+
+// EventAppend tek bir aggregate'e eklenecek event'leri ve beklenen mevcut sürümü tanımlar
+type EventAppend struct {
+	AggregateID     uuid.UUID
+	Events          []Event
+	ExpectedVersion int64
+}
+
 type EventStore interface {
 	SaveEvents(ctx context.Context, aggregateID uuid.UUID, events []Event, expectedVersion int64) error
+	// AppendEvents birden fazla aggregate'in event'lerini tek transaction'da yazar; herhangi
+	// bir aggregate'in sürüm kontrolü başarısız olursa hiçbir event yazılmaz
+	AppendEvents(ctx context.Context, appends []EventAppend) error
 	GetEvents(ctx context.Context, aggregateID uuid.UUID) ([]Event, error)
 	GetEventsByType(ctx context.Context, eventType EventType, limit, offset int) ([]Event, error)
 	GetEventsByTimeRange(ctx context.Context, startTime, endTime time.Time) ([]Event, error)
 	GetAllEvents(ctx context.Context, limit, offset int) ([]Event, error)
 	GetEventCount(ctx context.Context, aggregateID uuid.UUID) (int64, error)
+	CountEventsByType(ctx context.Context, eventType EventType) (int64, error)
+	CountAllEvents(ctx context.Context) (int64, error)
+	// CountEventsGroupedByType event sayılarını tipe göre veritabanında gruplayarak döndürür
+	CountEventsGroupedByType(ctx context.Context) (map[EventType]int64, error)
+	// CountAggregatesByFirstEventType her aggregate'i ilk event'inin tipine göre bir kez sayar
+	CountAggregatesByFirstEventType(ctx context.Context) (map[EventType]int64, error)
+	// GetEventsAfter [startTime, endTime] aralığındaki event'leri (timestamp, id) sırasıyla
+	// after cursor'ından sonra başlayarak en fazla limit adet döndürür
+	GetEventsAfter(ctx context.Context, startTime, endTime time.Time, after *EventCursor, limit int) ([]Event, error)
 }
 
 type EventPublisher interface {
@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"math"
+	"sort"
+)
+
+// AllocationScale tutarların bölüştürüldüğü en küçük birim (kuruş/cent) sayısı
+const AllocationScale = 100
+
+// Allocate total'ı weights oranında bölüştürür ve toplamı tam olarak total'a eşit payları döner.
+// Hesap en küçük birim cinsinden tam sayılarla yapılır; aşağı yuvarlamadan kalan birimler en büyük
+// küsurata sahip paylara birer birer dağıtılır (largest remainder). Küsuratlar eşitse önce gelen
+// pay seçilir, böylece aynı girdi her zaman aynı sonucu verir.
+func Allocate(total float64, weights []float64) ([]float64, error) {
+	if total <= 0 {
+		return nil, ErrInvalidAmount
+	}
+	if len(weights) == 0 {
+		return nil, ErrInvalidBatchItems
+	}
+
+	weightSum := 0.0
+	for _, weight := range weights {
+		if weight <= 0 || math.IsInf(weight, 0) || math.IsNaN(weight) {
+			return nil, ErrInvalidAllocationWeight
+		}
+		weightSum += weight
+	}
+
+	units := int64(math.Round(total * AllocationScale))
+	if units < int64(len(weights)) {
+		// Her pay en az bir birim almalı; aksi halde sıfır tutarlı kalemler oluşur
+		return nil, ErrInvalidAmount
+	}
+
+	shares := make([]int64, len(weights))
+	remainders := make([]float64, len(weights))
+	allocated := int64(0)
+	for i, weight := range weights {
+		exact := float64(units) * weight / weightSum
+		shares[i] = int64(math.Floor(exact))
+		remainders[i] = exact - float64(shares[i])
+		allocated += shares[i]
+	}
+
+	order := make([]int, len(weights))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return remainders[order[a]] > remainders[order[b]]
+	})
+	for i := int64(0); i < units-allocated; i++ {
+		shares[order[i%int64(len(order))]]++
+	}
+
+	amounts := make([]float64, len(shares))
+	for i, share := range shares {
+		if share == 0 {
+			return nil, ErrInvalidAmount
+		}
+		amounts[i] = float64(share) / AllocationScale
+	}
+	return amounts, nil
+}
+
+// AllocateEvenly total'ı n eşit paya böler; bölünemeyen birimler ilk paylara dağıtılır
+func AllocateEvenly(total float64, n int) ([]float64, error) {
+	weights := make([]float64, n)
+	for i := range weights {
+		weights[i] = 1
+	}
+	return Allocate(total, weights)
+}
@@ -0,0 +1,124 @@
+package domain
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+// sumUnits payların toplamını en küçük birim cinsinden döner; float toplamı yuvarlama hatası taşır
+func sumUnits(amounts []float64) int64 {
+	var units int64
+	for _, amount := range amounts {
+		units += int64(math.Round(amount * AllocationScale))
+	}
+	return units
+}
+
+func TestAllocate(t *testing.T) {
+	tests := []struct {
+		name    string
+		total   float64
+		weights []float64
+		want    []float64
+		wantErr error
+	}{
+		{name: "100 üçe bölünür", total: 100, weights: []float64{1, 1, 1}, want: []float64{33.34, 33.33, 33.33}},
+		{name: "tam bölünen toplam", total: 90, weights: []float64{1, 1, 1}, want: []float64{30, 30, 30}},
+		{name: "ağırlıklı bölüşüm", total: 10, weights: []float64{1, 2}, want: []float64{3.33, 6.67}},
+		{name: "kalan en büyük küsurata gider", total: 1, weights: []float64{1, 1, 1, 3}, want: []float64{0.17, 0.17, 0.16, 0.5}},
+		{name: "her pay en az bir birim alır", total: 0.01, weights: []float64{1, 1}, wantErr: ErrInvalidAmount},
+		{name: "sıfır toplam", total: 0, weights: []float64{1}, wantErr: ErrInvalidAmount},
+		{name: "kalem yok", total: 10, wantErr: ErrInvalidBatchItems},
+		{name: "sıfır ağırlık", total: 10, weights: []float64{1, 0}, wantErr: ErrInvalidAllocationWeight},
+		{name: "negatif ağırlık", total: 10, weights: []float64{1, -1}, wantErr: ErrInvalidAllocationWeight},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Allocate(tt.total, tt.weights)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Allocate = %v, beklenen %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("paylar = %v, beklenen %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("paylar = %v, beklenen %v", got, tt.want)
+					break
+				}
+			}
+			if units, want := sumUnits(got), int64(math.Round(tt.total*AllocationScale)); units != want {
+				t.Errorf("payların toplamı %d birim, beklenen %d", units, want)
+			}
+		})
+	}
+}
+
+func TestAllocateEvenlyManyItems(t *testing.T) {
+	tests := []struct {
+		name  string
+		total float64
+		n     int
+	}{
+		{name: "100 üç kaleme", total: 100, n: 3},
+		{name: "100 yedi kaleme", total: 100, n: 7},
+		{name: "küsuratlı toplam çok kaleme", total: 1234.57, n: 99},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := AllocateEvenly(tt.total, tt.n)
+			if err != nil {
+				t.Fatalf("AllocateEvenly: %v", err)
+			}
+			if units, want := sumUnits(got), int64(math.Round(tt.total*AllocationScale)); units != want {
+				t.Errorf("payların toplamı %d birim, beklenen %d", units, want)
+			}
+			// Eşit paylar arasında en fazla bir birim fark olur
+			for _, amount := range got {
+				if diff := math.Abs(amount - got[0]); diff > 1.0/AllocationScale+1e-9 {
+					t.Errorf("paylar arasında %v fark var: %v", diff, got)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestBatchTransactionRequestResolveAmounts(t *testing.T) {
+	tests := []struct {
+		name    string
+		total   float64
+		items   []BatchItem
+		want    []float64
+		wantErr error
+	}{
+		{name: "toplam eşit bölüştürülür", total: 100, items: []BatchItem{{}, {}, {}}, want: []float64{33.34, 33.33, 33.33}},
+		{name: "toplam ağırlıkla bölüştürülür", total: 100, items: []BatchItem{{Weight: 1}, {Weight: 3}}, want: []float64{25, 75}},
+		{name: "toplam yoksa tutarlar korunur", items: []BatchItem{{Amount: 5}, {Amount: 7}}, want: []float64{5, 7}},
+		{name: "toplam ve kalem tutarı birlikte verilemez", total: 100, items: []BatchItem{{Amount: 5}, {}}, wantErr: ErrInvalidBatchSplit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := BatchTransactionRequest{Type: TransactionTypeCredit, Currency: CurrencyTRY, TotalAmount: tt.total, Items: tt.items}
+			err := req.ResolveAmounts()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ResolveAmounts = %v, beklenen %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			for i, item := range req.Items {
+				if item.Amount != tt.want[i] {
+					t.Errorf("%d. kalem = %v, beklenen %v", i, item.Amount, tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	DefaultAuditLogLimit = 50
+	MaxAuditLogLimit     = 500
+)
+
+// AuditLog etkisi yüksek bir admin işleminin kim tarafından, hangi hedefe ve ne zaman
+// yapıldığını kaydeder. Action route şablonuyla birlikte HTTP metodudur
+// (ör. "POST /api/v1/ha/circuitbreakers/:name/open"); Target route parametreleridir.
+type AuditLog struct {
+	ID         uuid.UUID `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	ActorID    string    `json:"actor_id" gorm:"type:varchar(64);not null;index"`
+	ActorRole  string    `json:"actor_role,omitempty" gorm:"type:varchar(32)"`
+	Action     string    `json:"action" gorm:"type:varchar(255);not null;index"`
+	Target     string    `json:"target,omitempty" gorm:"type:varchar(255)"`
+	Path       string    `json:"path" gorm:"type:varchar(255);not null"`
+	StatusCode int       `json:"status_code" gorm:"not null"`
+	RequestID  string    `json:"request_id,omitempty" gorm:"type:varchar(64);index"`
+	ClientIP   string    `json:"client_ip,omitempty" gorm:"type:varchar(64)"`
+	Timestamp  time.Time `json:"timestamp" gorm:"not null;index"`
+}
+
+func (AuditLog) TableName() string {
+	return "audit_logs"
+}
+
+// AuditLogFilter audit kayıtlarını sorgulamak için kullanılır; boş alanlar filtrelenmez
+type AuditLogFilter struct {
+	ActorID string
+	Action  string
+	Target  string
+	From    *time.Time
+	To      *time.Time
+	Limit   int
+	Offset  int
+}
+
+// Normalize limit ve offset değerlerini izin verilen aralığa çeker
+func (f *AuditLogFilter) Normalize() {
+	if f.Limit <= 0 {
+		f.Limit = DefaultAuditLogLimit
+	}
+	if f.Limit > MaxAuditLogLimit {
+		f.Limit = MaxAuditLogLimit
+	}
+	if f.Offset < 0 {
+		f.Offset = 0
+	}
+}
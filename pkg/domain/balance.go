@@ -9,13 +9,20 @@ import (
 )
 
 type Balance struct {
-	ID        uuid.UUID    `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
-	UserID    uuid.UUID    `json:"user_id" gorm:"type:uuid;not null;uniqueIndex"`
-	Amount    float64      `json:"amount" gorm:"type:decimal(19,4);not null"`
-	Currency  string       `json:"currency"`
+	ID       uuid.UUID `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	UserID   uuid.UUID `json:"user_id" gorm:"type:uuid;not null;uniqueIndex:idx_balances_user_currency"`
+	Amount   float64   `json:"amount" gorm:"type:decimal(19,4);not null"`
+	Currency string    `json:"currency" gorm:"type:varchar(3);not null;uniqueIndex:idx_balances_user_currency"`
+	// Ledger kayıtlı bakiye, Available ise aktif blokajlar ve bekleyen borçlar düşülmüş bakiyedir
+	Ledger    float64      `json:"ledger" gorm:"-"`
+	Available float64      `json:"available" gorm:"-"`
 	CreatedAt time.Time    `json:"created_at" gorm:"not null"`
 	UpdatedAt time.Time    `json:"updated_at" gorm:"not null"`
 	mu        sync.RWMutex `json:"-"`
+
+	// MinimumBalance borç ve transferlerden sonra kalması gereken en düşük bakiye;
+	// negatif değer bu tutara kadar eksi bakiyeye (overdraft) izin verir
+	MinimumBalance float64 `json:"minimum_balance" gorm:"type:decimal(19,4);not null;default:0"`
 }
 
 type BalanceHistory struct {
@@ -26,6 +33,28 @@ type BalanceHistory struct {
 	CreatedAt time.Time `json:"created_at" gorm:"not null"`
 }
 
+// BalanceSummary dashboard'lar için bakiye, günlük hareket ve limit kullanımını tek yanıtta toplar
+type BalanceSummary struct {
+	UserID       uuid.UUID   `json:"user_id"`
+	Currency     string      `json:"currency"`
+	Ledger       float64     `json:"ledger"`
+	Available    float64     `json:"available"`
+	TodayCredits float64     `json:"today_credits"`
+	TodayDebits  float64     `json:"today_debits"`
+	PendingCount int64       `json:"pending_count"`
+	LimitUsage   *LimitUsage `json:"limit_usage,omitempty"`
+	GeneratedAt  time.Time   `json:"generated_at"`
+}
+
+// LimitUsage kullanıcının tanımlı işlem limitlerinin ne kadarının kullanıldığını gösterir
+type LimitUsage struct {
+	DailyLimit     float64 `json:"daily_limit"`
+	DailyUsed      float64 `json:"daily_used"`
+	DailyRemaining float64 `json:"daily_remaining"`
+	MonthlyLimit   float64 `json:"monthly_limit"`
+	MonthlyUsed    float64 `json:"monthly_used"`
+}
+
 func NewBalance(userID uuid.UUID, initialAmount float64, currency string) (*Balance, error) {
 	if initialAmount < 0 {
 		return nil, ErrInvalidAmount
@@ -62,8 +91,8 @@ func (b *Balance) Subtract(amount float64) error {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
-	if b.Amount < amount {
-		return ErrInsufficientBalance
+	if err := b.checkWithdrawal(b.Amount, amount); err != nil {
+		return err
 	}
 
 	b.Amount -= amount
@@ -71,6 +100,25 @@ func (b *Balance) Subtract(amount float64) error {
 	return nil
 }
 
+// CheckWithdrawal kullanılabilir bakiyeden amount düşüldüğünde minimum bakiyenin altına inilip
+// inilmeyeceğini kontrol eder. Minimum tanımlı değilse (sıfır) ErrInsufficientBalance, tanımlıysa
+// ErrMinimumBalanceBreached döner.
+func (b *Balance) CheckWithdrawal(available, amount float64) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.checkWithdrawal(available, amount)
+}
+
+func (b *Balance) checkWithdrawal(available, amount float64) error {
+	if available-amount >= b.MinimumBalance {
+		return nil
+	}
+	if b.MinimumBalance == 0 {
+		return ErrInsufficientBalance
+	}
+	return ErrMinimumBalanceBreached
+}
+
 func (b *Balance) GetAmount() float64 {
 	b.mu.RLock()
 	defer b.mu.RUnlock()
@@ -0,0 +1,153 @@
+package domain
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type BalanceAlertDirection string
+
+const (
+	// BalanceAlertBelow bakiye eşiğin altına düştüğünde tetiklenir
+	BalanceAlertBelow BalanceAlertDirection = "below"
+	// BalanceAlertAbove bakiye eşiğin üstüne çıktığında tetiklenir
+	BalanceAlertAbove BalanceAlertDirection = "above"
+)
+
+// BalanceAlertRule kullanıcının bakiye eşiği kuralıdır. Tetiklenen kural, bakiye eşikten
+// Hysteresis kadar geri dönmeden yeniden tetiklenmez; böylece eşik etrafındaki salınımlar
+// art arda bildirim üretmez. IsActive'in varsayılanı yalnızca şemadadır; gorm default'u verilirse
+// pasif oluşturulan kural aktif kaydedilir.
+type BalanceAlertRule struct {
+	ID              uuid.UUID             `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	UserID          uuid.UUID             `json:"user_id" gorm:"type:uuid;not null;index"`
+	Direction       BalanceAlertDirection `json:"direction" gorm:"type:varchar(10);not null"`
+	Threshold       float64               `json:"threshold" gorm:"type:decimal(19,4);not null"`
+	Hysteresis      float64               `json:"hysteresis" gorm:"type:decimal(19,4);not null;default:0"`
+	WebhookURL      string                `json:"webhook_url" gorm:"type:varchar(2048);not null"`
+	Triggered       bool                  `json:"triggered" gorm:"not null;default:false"`
+	LastTriggeredAt *time.Time            `json:"last_triggered_at,omitempty"`
+	IsActive        bool                  `json:"is_active" gorm:"not null"`
+	CreatedAt       time.Time             `json:"created_at" gorm:"not null"`
+	UpdatedAt       time.Time             `json:"updated_at" gorm:"not null"`
+}
+
+type BalanceAlertRuleRequest struct {
+	Direction  BalanceAlertDirection `json:"direction" binding:"required"`
+	Threshold  float64               `json:"threshold"`
+	Hysteresis float64               `json:"hysteresis" binding:"gte=0"`
+	WebhookURL string                `json:"webhook_url" binding:"required"`
+	IsActive   *bool                 `json:"is_active,omitempty"`
+}
+
+// BalanceAlertNotification eşik aşıldığında webhook'a gönderilen gövdedir
+type BalanceAlertNotification struct {
+	RuleID      uuid.UUID             `json:"rule_id"`
+	UserID      uuid.UUID             `json:"user_id"`
+	Direction   BalanceAlertDirection `json:"direction"`
+	Threshold   float64               `json:"threshold"`
+	Balance     float64               `json:"balance"`
+	TriggeredAt time.Time             `json:"triggered_at"`
+}
+
+func (r BalanceAlertRuleRequest) Validate() error {
+	if r.Direction != BalanceAlertBelow && r.Direction != BalanceAlertAbove {
+		return ErrInvalidAlertRule
+	}
+	if r.Hysteresis < 0 {
+		return ErrInvalidAlertRule
+	}
+	u, err := url.Parse(r.WebhookURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrInvalidAlertRule
+	}
+	return nil
+}
+
+func NewBalanceAlertRule(userID uuid.UUID, req BalanceAlertRuleRequest) (*BalanceAlertRule, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	rule := &BalanceAlertRule{
+		ID:        uuid.New(),
+		UserID:    userID,
+		IsActive:  true,
+		CreatedAt: now,
+	}
+	rule.apply(req, now)
+	return rule, nil
+}
+
+// Update kuralı yeni değerlerle günceller ve tetiklenme durumunu sıfırlar
+func (r *BalanceAlertRule) Update(req BalanceAlertRuleRequest) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+	r.apply(req, time.Now())
+	r.Triggered = false
+	return nil
+}
+
+func (r *BalanceAlertRule) apply(req BalanceAlertRuleRequest, now time.Time) {
+	r.Direction = req.Direction
+	r.Threshold = req.Threshold
+	r.Hysteresis = req.Hysteresis
+	r.WebhookURL = req.WebhookURL
+	if req.IsActive != nil {
+		r.IsActive = *req.IsActive
+	}
+	r.UpdatedAt = now
+}
+
+// Evaluate yeni bakiyeye göre kuralın durumunu günceller. fired eşiğin yeni aşıldığını,
+// changed ise kaydedilmesi gereken bir durum değişikliği olduğunu belirtir.
+func (r *BalanceAlertRule) Evaluate(balance float64) (fired, changed bool) {
+	if !r.IsActive {
+		return false, false
+	}
+
+	var crossed, rearmed bool
+	switch r.Direction {
+	case BalanceAlertBelow:
+		crossed = balance < r.Threshold
+		rearmed = balance >= r.Threshold+r.Hysteresis
+	case BalanceAlertAbove:
+		crossed = balance > r.Threshold
+		rearmed = balance <= r.Threshold-r.Hysteresis
+	default:
+		return false, false
+	}
+
+	if !r.Triggered && crossed {
+		now := time.Now()
+		r.Triggered = true
+		r.LastTriggeredAt = &now
+		r.UpdatedAt = now
+		return true, true
+	}
+	if r.Triggered && rearmed {
+		r.Triggered = false
+		r.UpdatedAt = time.Now()
+		return false, true
+	}
+	return false, false
+}
+
+func (r *BalanceAlertRule) Notification(balance float64) BalanceAlertNotification {
+	triggeredAt := time.Now()
+	if r.LastTriggeredAt != nil {
+		triggeredAt = *r.LastTriggeredAt
+	}
+	return BalanceAlertNotification{
+		RuleID:      r.ID,
+		UserID:      r.UserID,
+		Direction:   r.Direction,
+		Threshold:   r.Threshold,
+		Balance:     balance,
+		TriggeredAt: triggeredAt,
+	}
+}
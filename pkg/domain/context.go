@@ -0,0 +1,26 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	// DefaultQueryTimeout deadline'ı olmayan context'lerle yapılan DB sorguları için üst sınır
+	DefaultQueryTimeout = 5 * time.Second
+	// DefaultCacheTimeout deadline'ı olmayan context'lerle yapılan cache işlemleri için üst sınır
+	DefaultCacheTimeout = 2 * time.Second
+)
+
+// WithDefaultTimeout context'in zaten bir deadline'ı varsa (ör. HTTP isteğinden gelen)
+// onu aynen kullanır; yoksa verilen süre kadar timeout ekler. Her iki durumda da
+// dönen cancel fonksiyonu çağrılmalıdır.
+func WithDefaultTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if _, ok := ctx.Deadline(); ok || timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type DisputeStatus string
+
+const (
+	DisputeStatusOpen     DisputeStatus = "open"
+	DisputeStatusAccepted DisputeStatus = "accepted"
+	DisputeStatusRejected DisputeStatus = "rejected"
+)
+
+// Dispute kullanıcının bir işleme yaptığı itirazı ve çözüm durumunu tutar
+type Dispute struct {
+	ID            uuid.UUID     `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	TransactionID uuid.UUID     `json:"transaction_id" gorm:"type:uuid;not null;index"`
+	UserID        uuid.UUID     `json:"user_id" gorm:"type:uuid;not null;index"`
+	Reason        string        `json:"reason" gorm:"type:text;not null"`
+	Status        DisputeStatus `json:"status" gorm:"type:varchar(20);not null;default:'open'"`
+	Resolution    string        `json:"resolution,omitempty" gorm:"type:text"`
+	ResolvedBy    *uuid.UUID    `json:"resolved_by,omitempty" gorm:"type:uuid"`
+	OpenedAt      time.Time     `json:"opened_at" gorm:"not null"`
+	ResolvedAt    *time.Time    `json:"resolved_at,omitempty"`
+	CreatedAt     time.Time     `json:"created_at" gorm:"not null"`
+	UpdatedAt     time.Time     `json:"updated_at" gorm:"not null"`
+	mu            sync.RWMutex  `json:"-"`
+}
+
+type DisputeRequest struct {
+	Reason string `json:"reason" binding:"required,max=1000"`
+}
+
+type ResolveDisputeRequest struct {
+	Status     DisputeStatus `json:"status" binding:"required,oneof=accepted rejected"`
+	Resolution string        `json:"resolution" binding:"omitempty,max=1000"`
+}
+
+func NewDispute(transaction *Transaction, req DisputeRequest) (*Dispute, error) {
+	if req.Reason == "" {
+		return nil, ErrInvalidDisputeReason
+	}
+
+	now := time.Now()
+	return &Dispute{
+		ID:            uuid.New(),
+		TransactionID: transaction.ID,
+		UserID:        transaction.UserID,
+		Reason:        req.Reason,
+		Status:        DisputeStatusOpen,
+		OpenedAt:      now,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}, nil
+}
+
+// Resolve açık itirazı kabul veya ret ile kapatır
+func (d *Dispute) Resolve(status DisputeStatus, resolution string, resolvedBy uuid.UUID) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.Status != DisputeStatusOpen {
+		return ErrDisputeNotOpen
+	}
+	if status != DisputeStatusAccepted && status != DisputeStatusRejected {
+		return ErrInvalidDisputeStatus
+	}
+
+	now := time.Now()
+	d.Status = status
+	d.Resolution = resolution
+	d.ResolvedBy = &resolvedBy
+	d.ResolvedAt = &now
+	d.UpdatedAt = now
+	return nil
+}
+
+func (d *Dispute) IsOpen() bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.Status == DisputeStatusOpen
+}
@@ -1,23 +1,43 @@
 package domain
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
-	ErrUserNotFound       = errors.New("user not found")
+	ErrUserNotFound       = fmt.Errorf("user %w", ErrNotFound)
 	ErrUserAlreadyExists  = errors.New("user already exists")
 	ErrInvalidCredentials = errors.New("invalid credentials")
 	ErrInvalidName        = errors.New("name must not be empty")
 	ErrInvalidEmail       = errors.New("invalid email format")
 	ErrInvalidPassword    = errors.New("password must be at least 8 characters")
 	ErrInvalidUsername    = errors.New("username must be at least 3 characters")
+	ErrInvalidToken       = errors.New("invalid token")
+	ErrInvalidTokenClaims = errors.New("invalid token claims")
 )
 
 var (
-	ErrTransactionNotFound      = errors.New("transaction not found")
+	ErrTransactionNotFound      = fmt.Errorf("transaction %w", ErrNotFound)
+	ErrTransactionAlreadyExists = errors.New("transaction with this reference id already exists")
 	ErrInvalidOperation         = errors.New("invalid operation")
 	ErrInvalidTransactionStatus = errors.New("invalid transaction status")
 	ErrInvalidState             = errors.New("invalid transaction state")
 	ErrTransactionFailed        = errors.New("transaction failed")
+	ErrMetadataTooLarge         = errors.New("transaction metadata exceeds the allowed size")
+	ErrInvalidMetadata          = errors.New("invalid transaction metadata")
+	ErrInvalidCursor            = errors.New("invalid pagination cursor")
+	ErrInvalidPagination        = errors.New("invalid pagination parameters")
+	ErrInvalidSearchFilter      = errors.New("invalid search filter")
+	ErrDisputeNotFound          = fmt.Errorf("dispute %w", ErrNotFound)
+	ErrDisputeAlreadyOpen       = errors.New("transaction already has an open dispute")
+	ErrDisputeNotOpen           = errors.New("dispute is not open")
+	ErrInvalidDisputeStatus     = errors.New("invalid dispute resolution status")
+	ErrInvalidDisputeReason     = errors.New("dispute reason must not be empty")
+	ErrReceiptNotFound          = fmt.Errorf("transaction receipt %w", ErrNotFound)
+	ErrInvalidTransactionType   = errors.New("invalid transaction type")
+	ErrRecipientRequired        = errors.New("transfer recipient is required")
+	ErrSelfTransfer             = errors.New("cannot transfer to the same account")
 )
 
 // Balance errors
@@ -25,24 +45,109 @@ var (
 	ErrInsufficientFunds   = errors.New("insufficient funds")
 	ErrInsufficientBalance = errors.New("insufficient balance")
 	ErrInvalidAmount       = errors.New("invalid amount")
+	ErrHoldNotFound        = fmt.Errorf("balance hold %w", ErrNotFound)
+	ErrHoldNotActive       = errors.New("balance hold is not active")
+	ErrReportNotFound      = fmt.Errorf("reconciliation report %w", ErrNotFound)
+	ErrAlertRuleNotFound   = fmt.Errorf("balance alert rule %w", ErrNotFound)
+	ErrInvalidAlertRule    = errors.New("invalid balance alert rule")
+	ErrBalanceNotFound     = fmt.Errorf("balance %w", ErrNotFound)
+	ErrNoBalanceAtTime     = errors.New("no balance record found at the given time")
 )
 
 var (
 	ErrCacheMiss          = errors.New("cache miss")
 	ErrCacheConnection    = errors.New("cache connection error")
 	ErrCacheSerialization = errors.New("cache serialization error")
+	ErrUnknownEntityType  = errors.New("unknown cache entity type")
 )
 
 var (
 	ErrInvalidScheduledTime         = errors.New("scheduled time must be in the future")
+	ErrInvalidTimezone              = errors.New("invalid IANA timezone name")
+	ErrInvalidScheduledStatus       = errors.New("operation not allowed in current scheduled transaction status")
+	ErrInvalidRecurringConfig       = errors.New("invalid recurring config")
+	ErrInvalidExecutionWindow       = errors.New("invalid execution window")
+	ErrTooManyBulkItems             = errors.New("bulk request exceeds the maximum number of items")
+	ErrBulkValidationFailed         = errors.New("one or more bulk items are invalid")
 	ErrInvalidBatchItems            = errors.New("batch must contain at least one item")
-	ErrBatchSizeExceeded            = errors.New("batch size cannot exceed 1000 items")
+	ErrBatchSizeExceeded            = errors.New("batch size exceeds the configured maximum")
+	ErrInvalidBatchSize             = errors.New("max batch size must be between 1 and the hard limit")
 	ErrInvalidLimit                 = errors.New("invalid transaction limit")
 	ErrTransactionLimitExceeded     = errors.New("transaction limit exceeded")
 	ErrDailyLimitExceeded           = errors.New("daily transaction limit exceeded")
 	ErrDailyCountExceeded           = errors.New("daily transaction count exceeded")
-	ErrScheduledTransactionNotFound = errors.New("scheduled transaction not found")
-	ErrBatchTransactionNotFound     = errors.New("batch transaction not found")
+	ErrWeeklyLimitExceeded          = errors.New("weekly transaction limit exceeded")
+	ErrWeeklyCountExceeded          = errors.New("weekly transaction count exceeded")
+	ErrMonthlyLimitExceeded         = errors.New("monthly transaction limit exceeded")
+	ErrMonthlyCountExceeded         = errors.New("monthly transaction count exceeded")
+	ErrScheduledTransactionNotFound = fmt.Errorf("scheduled transaction %w", ErrNotFound)
+	ErrBatchTransactionNotFound     = fmt.Errorf("batch transaction %w", ErrNotFound)
+	ErrTransactionLimitNotFound     = fmt.Errorf("transaction limit %w", ErrNotFound)
+	ErrInvalidLimitTier             = errors.New("invalid limit tier")
+	ErrLimitReservationNotFound     = fmt.Errorf("limit reservation %w", ErrNotFound)
 	ErrCurrencyNotSupported         = errors.New("currency not supported")
-	ErrExchangeRateNotFound         = errors.New("exchange rate not found")
+	ErrCurrencyMismatch             = errors.New("currency does not match the balance currency")
+	ErrExchangeRateNotFound         = fmt.Errorf("exchange rate %w", ErrNotFound)
+)
+
+var (
+	ErrFeatureFlagNotFound = fmt.Errorf("feature flag %w", ErrNotFound)
+	ErrInvalidFeatureFlag  = errors.New("invalid feature flag")
+)
+
+var (
+	ErrInvalidSignature = errors.New("invalid request signature")
+	ErrSignatureExpired = errors.New("request timestamp is outside the signature validity window")
+	ErrNonceReused      = errors.New("request nonce has already been used")
+)
+
+var (
+	ErrInvalidFeeConfig   = errors.New("invalid fee configuration")
+	ErrFeeAccountNotFound = errors.New("fee account balance not found")
+)
+
+// ErrMinimumBalanceBreached ErrInsufficientBalance'ı sarar; mevcut errors.Is kontrolleri eşleşmeye devam eder
+var ErrMinimumBalanceBreached = fmt.Errorf("%w: minimum balance would be breached", ErrInsufficientBalance)
+
+var (
+	ErrTransactionDenied        = errors.New("transaction denied by fraud screening")
+	ErrScreeningUnavailable     = errors.New("fraud screening unavailable")
+	ErrInvalidScreeningDecision = errors.New("invalid fraud screening decision")
+)
+
+// ErrTransactionNotHeld işlem inceleme kuyruğunda değilse veya başka bir inceleme tarafından sonuçlandırıldıysa döner
+var ErrTransactionNotHeld = errors.New("transaction is not held for review")
+
+var (
+	ErrInvalidExternalAccount     = errors.New("invalid external account")
+	ErrInvalidTransferDestination = errors.New("transfer requires exactly one of to_user_id or external_account")
+	ErrPaymentGatewayUnavailable  = errors.New("payment gateway unavailable")
+	ErrExternalTransferRejected   = errors.New("external transfer rejected by payment gateway")
+)
+
+var (
+	ErrTransactionNotPendingSettlement = errors.New("transaction is not pending settlement")
+	ErrHoldReservedForPayment          = errors.New("balance hold is reserved for a pending external payment")
 )
+
+var (
+	ErrWebhookDeliveryNotFound = fmt.Errorf("webhook delivery %w", ErrNotFound)
+	ErrWebhookNotRedeliverable = errors.New("only dead-lettered webhook deliveries can be redelivered")
+)
+
+var (
+	ErrInvalidAllocationWeight = errors.New("allocation weights must be positive")
+	ErrInvalidBatchSplit       = errors.New("total_amount cannot be combined with item amounts")
+)
+
+// ErrNoEventSubscribers event'ler yeniden yayınlanmak istendiğinde hiçbir abone kayıtlı değilse döner;
+// aksi halde hiçbir projeksiyon güncellenmediği halde yayın başarılı görünürdü
+var ErrNoEventSubscribers = errors.New("no event subscribers are registered")
+
+// ErrAutomationPaused otomatik işlemler kill switch ile duraklatılmışken döner
+var ErrAutomationPaused = errors.New("automation is paused by kill switch")
+
+// ErrNotFound kaydı bulunamayan tüm varlıkların ortak hatasıdır. Varlığa özgü *NotFound hataları bunu
+// sarar; handler'lar tek bir errors.Is(err, ErrNotFound) kontrolüyle 404 dönebilir. ErrFeeAccountNotFound
+// bir yapılandırma hatası olduğundan sarmaz.
+var ErrNotFound = errors.New("not found")
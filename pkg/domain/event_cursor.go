@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"encoding/base64"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EventCursor (timestamp, id) sıralamasında son okunan event'i işaret eder; offset
+// yerine keyset sayfalama yapıldığı için büyük event tablolarında da sabit maliyetlidir
+type EventCursor struct {
+	Timestamp time.Time
+	ID        uuid.UUID
+}
+
+// CursorAfter event'ten sonraki sayfayı işaret eden cursor'ı döndürür
+func CursorAfter(event Event) *EventCursor {
+	return &EventCursor{Timestamp: event.GetTimestamp(), ID: event.GetID()}
+}
+
+func (c *EventCursor) Encode() string {
+	raw := c.Timestamp.UTC().Format(time.RFC3339Nano) + "|" + c.ID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func DecodeEventCursor(cursor string) (*EventCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	parts := strings.SplitN(string(raw), "|", 2)
+	if len(parts) != 2 {
+		return nil, ErrInvalidCursor
+	}
+
+	timestamp, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	id, err := uuid.Parse(parts[1])
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &EventCursor{Timestamp: timestamp, ID: id}, nil
+}
@@ -15,12 +15,19 @@ const (
 	EventTransactionFailed     EventType = "transaction.failed"
 	EventTransactionCancelled  EventType = "transaction.cancelled"
 	EventTransactionRolledBack EventType = "transaction.rolled_back"
+	EventTransactionDisputed   EventType = "transaction.disputed"
+	EventDisputeResolved       EventType = "transaction.dispute_resolved"
+	EventTransactionHeld       EventType = "transaction.held"
+	// EventTransactionPendingSettlement dış transfer ödeme ağ geçidine iletildiğinde yayınlanır
+	EventTransactionPendingSettlement EventType = "transaction.pending_settlement"
 
 	EventBalanceCreated  EventType = "balance.created"
 	EventBalanceUpdated  EventType = "balance.updated"
 	EventBalanceDebited  EventType = "balance.debited"
 	EventBalanceCredited EventType = "balance.credited"
 
+	EventReconciliationDiscrepancy EventType = "reconciliation.discrepancy_detected"
+
 	EventUserCreated EventType = "user.created"
 	EventUserUpdated EventType = "user.updated"
 )
@@ -72,6 +79,22 @@ type TransactionStateChangedEvent struct {
 	Reason        string           `json:"reason,omitempty"`
 }
 
+type TransactionDisputeEvent struct {
+	BaseEvent
+	DisputeID     uuid.UUID     `json:"dispute_id"`
+	TransactionID uuid.UUID     `json:"transaction_id"`
+	UserID        uuid.UUID     `json:"user_id"`
+	Status        DisputeStatus `json:"status"`
+	Reason        string        `json:"reason,omitempty"`
+	Resolution    string        `json:"resolution,omitempty"`
+}
+
+type ReconciliationDiscrepancyEvent struct {
+	BaseEvent
+	ReportID         uuid.UUID `json:"report_id"`
+	DiscrepancyCount int       `json:"discrepancy_count"`
+}
+
 type BalanceCreatedEvent struct {
 	BaseEvent
 	UserID   uuid.UUID `json:"user_id"`
@@ -126,8 +149,27 @@ func NewTransactionCreatedEvent(transaction *Transaction) *TransactionCreatedEve
 	}
 }
 
+func NewUserCreatedEvent(user *User) *UserCreatedEvent {
+	data, _ := json.Marshal(user)
+
+	return &UserCreatedEvent{
+		BaseEvent: BaseEvent{
+			ID:          uuid.New(),
+			Type:        EventUserCreated,
+			AggregateID: user.ID,
+			Version:     1,
+			Timestamp:   time.Now(),
+			Data:        data,
+		},
+		UserID:    user.ID,
+		Email:     user.Email,
+		FirstName: user.FirstName,
+		LastName:  user.LastName,
+	}
+}
+
 func NewTransactionStateChangedEvent(transaction *Transaction, oldState, newState TransactionState, reason string) *TransactionStateChangedEvent {
-	return &TransactionStateChangedEvent{
+	event := &TransactionStateChangedEvent{
 		BaseEvent: BaseEvent{
 			ID:          uuid.New(),
 			Type:        EventTransactionStateChangedEventType(newState),
@@ -141,6 +183,53 @@ func NewTransactionStateChangedEvent(transaction *Transaction, oldState, newStat
 		NewState:      newState,
 		Reason:        reason,
 	}
+	// Event store okurken Data'yı bu alanlara çözer; data kolonu boş geçilemez
+	event.Data, _ = json.Marshal(struct {
+		TransactionID uuid.UUID        `json:"transaction_id"`
+		UserID        uuid.UUID        `json:"user_id"`
+		OldState      TransactionState `json:"old_state"`
+		NewState      TransactionState `json:"new_state"`
+		Reason        string           `json:"reason,omitempty"`
+	}{event.TransactionID, event.UserID, event.OldState, event.NewState, event.Reason})
+	return event
+}
+
+func NewTransactionDisputeEvent(eventType EventType, dispute *Dispute) *TransactionDisputeEvent {
+	data, _ := json.Marshal(dispute)
+
+	return &TransactionDisputeEvent{
+		BaseEvent: BaseEvent{
+			ID:          uuid.New(),
+			Type:        eventType,
+			AggregateID: dispute.TransactionID,
+			Version:     1,
+			Timestamp:   time.Now(),
+			Data:        data,
+		},
+		DisputeID:     dispute.ID,
+		TransactionID: dispute.TransactionID,
+		UserID:        dispute.UserID,
+		Status:        dispute.Status,
+		Reason:        dispute.Reason,
+		Resolution:    dispute.Resolution,
+	}
+}
+
+func NewReconciliationDiscrepancyEvent(report *ReconciliationReport) *ReconciliationDiscrepancyEvent {
+	data, _ := json.Marshal(report.Discrepancies)
+
+	return &ReconciliationDiscrepancyEvent{
+		BaseEvent: BaseEvent{
+			ID:          uuid.New(),
+			Type:        EventReconciliationDiscrepancy,
+			AggregateID: report.ID,
+			Version:     1,
+			Timestamp:   time.Now(),
+			Data:        data,
+		},
+		ReportID:         report.ID,
+		DiscrepancyCount: report.DiscrepancyCount,
+	}
 }
 
 func NewBalanceCreatedEvent(balance *Balance) *BalanceCreatedEvent {
@@ -187,6 +276,10 @@ func EventTransactionStateChangedEventType(state TransactionState) EventType {
 		return EventTransactionFailed
 	case TransactionStateCancelled:
 		return EventTransactionCancelled
+	case TransactionStateHeld:
+		return EventTransactionHeld
+	case TransactionStatePendingSettlement:
+		return EventTransactionPendingSettlement
 	default:
 		return EventTransactionStateChangedEventType(TransactionStatePending)
 	}
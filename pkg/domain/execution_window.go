@@ -0,0 +1,114 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// ExecutionWindow zamanlanmış bir işlemin yalnızca belirli gün ve saatlerde
+// çalışmasını sağlar (ör. hafta içi 09:00-17:00). Saatler işlemin timezone'unda yorumlanır.
+type ExecutionWindow struct {
+	// Start ve End "HH:MM" formatında günün başlangıç ve bitiş saatidir; End hariçtir
+	Start string `json:"start"`
+	End   string `json:"end"`
+	// Weekdays izin verilen günlerdir (0=Pazar ... 6=Cumartesi); boşsa her gün geçerlidir
+	Weekdays []int `json:"weekdays,omitempty"`
+}
+
+// Validate saat formatını, aralığı ve gün değerlerini doğrular
+func (w *ExecutionWindow) Validate() error {
+	start, err := parseTimeOfDay(w.Start)
+	if err != nil {
+		return ErrInvalidExecutionWindow
+	}
+	end, err := parseTimeOfDay(w.End)
+	if err != nil {
+		return ErrInvalidExecutionWindow
+	}
+	if start >= end {
+		return ErrInvalidExecutionWindow
+	}
+
+	for _, day := range w.Weekdays {
+		if day < 0 || day > 6 {
+			return ErrInvalidExecutionWindow
+		}
+	}
+	return nil
+}
+
+// NextSlot t anı pencere içindeyse t'yi, değilse pencerenin açıldığı bir sonraki anı döndürür.
+func (w *ExecutionWindow) NextSlot(t time.Time, loc *time.Location) (time.Time, error) {
+	if err := w.Validate(); err != nil {
+		return time.Time{}, err
+	}
+
+	start, _ := parseTimeOfDay(w.Start)
+	end, _ := parseTimeOfDay(w.End)
+
+	local := t.In(loc)
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+
+	for i := 0; i <= 7; i++ {
+		day := midnight.AddDate(0, 0, i)
+		if !w.allowsWeekday(day.Weekday()) {
+			continue
+		}
+
+		windowStart := day.Add(start)
+		windowEnd := day.Add(end)
+
+		if local.Before(windowStart) {
+			return windowStart.UTC(), nil
+		}
+		if local.Before(windowEnd) {
+			return t.UTC(), nil
+		}
+	}
+
+	return time.Time{}, ErrInvalidExecutionWindow
+}
+
+func (w *ExecutionWindow) allowsWeekday(day time.Weekday) bool {
+	if len(w.Weekdays) == 0 {
+		return true
+	}
+	for _, allowed := range w.Weekdays {
+		if allowed == int(day) {
+			return true
+		}
+	}
+	return false
+}
+
+func (w ExecutionWindow) Value() (driver.Value, error) {
+	return json.Marshal(w)
+}
+
+func (w *ExecutionWindow) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+
+	var data []byte
+	switch v := value.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("unsupported execution window type %T", value)
+	}
+	return json.Unmarshal(data, w)
+}
+
+// parseTimeOfDay "HH:MM" değerini gün başından itibaren geçen süreye çevirir
+func parseTimeOfDay(value string) (time.Duration, error) {
+	parsed, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(parsed.Hour())*time.Hour + time.Duration(parsed.Minute())*time.Minute, nil
+}
@@ -0,0 +1,98 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExecutionWindowNextSlot(t *testing.T) {
+	weekdays := []int{1, 2, 3, 4, 5}
+	// 2026-03-07 Cumartesi
+	saturday := time.Date(2026, 3, 7, 12, 0, 0, 0, time.UTC)
+	monday := time.Date(2026, 3, 9, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		window   ExecutionWindow
+		timezone string
+		at       time.Time
+		want     time.Time
+	}{
+		{
+			name:   "hafta sonu pazartesiye ertelenir",
+			window: ExecutionWindow{Start: "09:00", End: "17:00", Weekdays: weekdays},
+			at:     saturday,
+			want:   monday,
+		},
+		{
+			name:   "pazar gecesi pazartesi açılışına ertelenir",
+			window: ExecutionWindow{Start: "09:00", End: "17:00", Weekdays: weekdays},
+			at:     time.Date(2026, 3, 8, 23, 30, 0, 0, time.UTC),
+			want:   monday,
+		},
+		{
+			name:   "cuma mesai sonrası pazartesiye ertelenir",
+			window: ExecutionWindow{Start: "09:00", End: "17:00", Weekdays: weekdays},
+			at:     time.Date(2026, 3, 6, 17, 0, 0, 0, time.UTC),
+			want:   monday,
+		},
+		{
+			name:   "pencere içindeyse aynı an döner",
+			window: ExecutionWindow{Start: "09:00", End: "17:00", Weekdays: weekdays},
+			at:     monday.Add(2 * time.Hour),
+			want:   monday.Add(2 * time.Hour),
+		},
+		{
+			name:   "açılıştan önce aynı gün açılışa ertelenir",
+			window: ExecutionWindow{Start: "09:00", End: "17:00"},
+			at:     saturday.Add(-6 * time.Hour),
+			want:   saturday.Add(-3 * time.Hour),
+		},
+		{
+			name:     "saatler işlemin timezone'unda yorumlanır",
+			window:   ExecutionWindow{Start: "09:00", End: "17:00", Weekdays: weekdays},
+			timezone: "Europe/Istanbul",
+			at:       saturday,
+			// İstanbul UTC+3; pazartesi 09:00 yerel saat 06:00 UTC'dir
+			want: monday.Add(-3 * time.Hour),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loc, err := LoadTimezone(tt.timezone)
+			if err != nil {
+				t.Skipf("timezone verisi yok: %v", err)
+			}
+
+			got, err := tt.window.NextSlot(tt.at, loc)
+			if err != nil {
+				t.Fatalf("NextSlot: %v", err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("NextSlot = %v, beklenen %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExecutionWindowValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		window  ExecutionWindow
+		wantErr error
+	}{
+		{name: "geçerli", window: ExecutionWindow{Start: "09:00", End: "17:00", Weekdays: []int{1, 5}}},
+		{name: "hatalı saat", window: ExecutionWindow{Start: "9am", End: "17:00"}, wantErr: ErrInvalidExecutionWindow},
+		{name: "bitiş başlangıçtan önce", window: ExecutionWindow{Start: "17:00", End: "09:00"}, wantErr: ErrInvalidExecutionWindow},
+		{name: "geçersiz gün", window: ExecutionWindow{Start: "09:00", End: "17:00", Weekdays: []int{7}}, wantErr: ErrInvalidExecutionWindow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.window.Validate(); err != tt.wantErr {
+				t.Fatalf("Validate = %v, beklenen %v", err, tt.wantErr)
+			}
+		})
+	}
+}
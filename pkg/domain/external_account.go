@@ -0,0 +1,112 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// ExternalAccount transferin gönderileceği sistem dışı banka hesabı. IBAN ya da banka kodu ile
+// hesap numarası verilmelidir; ikisi birlikte verilemez.
+type ExternalAccount struct {
+	IBAN          string `json:"iban,omitempty"`
+	BankCode      string `json:"bank_code,omitempty"`
+	AccountNumber string `json:"account_number,omitempty"`
+	HolderName    string `json:"holder_name"`
+}
+
+func (a *ExternalAccount) Validate() error {
+	if strings.TrimSpace(a.HolderName) == "" || len(a.HolderName) > 100 {
+		return fmt.Errorf("%w: holder_name is required", ErrInvalidExternalAccount)
+	}
+
+	hasIBAN := a.IBAN != ""
+	hasAccount := a.BankCode != "" || a.AccountNumber != ""
+	switch {
+	case hasIBAN && hasAccount:
+		return fmt.Errorf("%w: provide either iban or bank_code/account_number", ErrInvalidExternalAccount)
+	case hasIBAN:
+		if !ValidIBAN(a.IBAN) {
+			return fmt.Errorf("%w: invalid iban", ErrInvalidExternalAccount)
+		}
+	case a.BankCode == "" || a.AccountNumber == "":
+		return fmt.Errorf("%w: bank_code and account_number are required", ErrInvalidExternalAccount)
+	case len(a.BankCode) > 20 || len(a.AccountNumber) > 34:
+		return fmt.Errorf("%w: bank_code or account_number too long", ErrInvalidExternalAccount)
+	}
+	return nil
+}
+
+// Reference hesabı işlem kaydında saklanan tek satırlık biçimde döner: normalize IBAN
+// ya da "banka_kodu/hesap_no"
+func (a *ExternalAccount) Reference() string {
+	if a.IBAN != "" {
+		return normalizeIBAN(a.IBAN)
+	}
+	return a.BankCode + "/" + a.AccountNumber
+}
+
+func (a ExternalAccount) Value() (driver.Value, error) {
+	return json.Marshal(a)
+}
+
+func (a *ExternalAccount) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+
+	var data []byte
+	switch v := value.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("unsupported type for external account: %T", value)
+	}
+	return json.Unmarshal(data, a)
+}
+
+func normalizeIBAN(iban string) string {
+	return strings.ToUpper(strings.ReplaceAll(iban, " ", ""))
+}
+
+// ValidIBAN IBAN'ın uzunluğunu, karakterlerini ve ISO 13616 mod-97 kontrol basamaklarını doğrular
+func ValidIBAN(iban string) bool {
+	iban = normalizeIBAN(iban)
+	if len(iban) < 15 || len(iban) > 34 {
+		return false
+	}
+	if !isUpperLetter(iban[0]) || !isUpperLetter(iban[1]) || !isDigit(iban[2]) || !isDigit(iban[3]) {
+		return false
+	}
+
+	// Ülke kodu ve kontrol basamakları sona alınır, harfler 10..35 sayılarına çevrilir
+	var digits strings.Builder
+	for _, c := range []byte(iban[4:] + iban[:4]) {
+		switch {
+		case isDigit(c):
+			digits.WriteByte(c)
+		case isUpperLetter(c):
+			fmt.Fprintf(&digits, "%d", c-'A'+10)
+		default:
+			return false
+		}
+	}
+
+	n, ok := new(big.Int).SetString(digits.String(), 10)
+	if !ok {
+		return false
+	}
+	return new(big.Int).Mod(n, big.NewInt(97)).Int64() == 1
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isUpperLetter(c byte) bool {
+	return c >= 'A' && c <= 'Z'
+}
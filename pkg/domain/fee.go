@@ -0,0 +1,142 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// FeeRule bir işlem tipi için alınacak ücreti tanımlar; sabit ve yüzdesel kısımlar toplanır.
+// Percent yüzde cinsindendir (1.5 => tutarın %1.5'i).
+type FeeRule struct {
+	Flat    float64 `json:"flat"`
+	Percent float64 `json:"percent"`
+}
+
+// Calculate tutar için ücreti kuruş hassasiyetinde hesaplar
+func (r FeeRule) Calculate(amount float64) float64 {
+	fee := r.Flat + amount*r.Percent/100
+	if fee <= 0 {
+		return 0
+	}
+	return math.Round(fee*100) / 100
+}
+
+func (r FeeRule) validate() error {
+	if r.Flat < 0 || r.Percent < 0 {
+		return ErrInvalidFeeConfig
+	}
+	if r.Percent >= 100 {
+		return fmt.Errorf("%w: percent must be below 100", ErrInvalidFeeConfig)
+	}
+	return nil
+}
+
+// FeeSchedule işlem tipine göre ücret kurallarını tutar. Kullanıcının seviyesi için tanımlı
+// kural varsa o, yoksa varsayılan kural uygulanır; kural yoksa işlem ücretsizdir.
+type FeeSchedule struct {
+	Rules     map[TransactionType]FeeRule               `json:"rules,omitempty"`
+	TierRules map[LimitTier]map[TransactionType]FeeRule `json:"tier_rules,omitempty"`
+}
+
+// IsEmpty hiçbir ücret kuralı tanımlı değilse true döner
+func (s FeeSchedule) IsEmpty() bool {
+	return len(s.Rules) == 0 && len(s.TierRules) == 0
+}
+
+// Fee verilen seviye ve işlem tipi için tutara uygulanacak ücreti döner
+func (s FeeSchedule) Fee(tier LimitTier, txType TransactionType, amount float64) float64 {
+	if rule, ok := s.TierRules[tier][txType]; ok {
+		return rule.Calculate(amount)
+	}
+	if rule, ok := s.Rules[txType]; ok {
+		return rule.Calculate(amount)
+	}
+	return 0
+}
+
+// ParseFeeSchedule "TRANSFER=flat:0.5,percent:1;premium.TRANSFER=percent:0.5" biçimindeki
+// tanımı çözer. Anahtar isteğe bağlı olarak seviye ile öneklenir; boş tanım ücretsiz tarife döner.
+func ParseFeeSchedule(spec string) (FeeSchedule, error) {
+	schedule := FeeSchedule{}
+
+	for _, entry := range strings.Split(spec, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		key, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			return FeeSchedule{}, fmt.Errorf("%w: %q", ErrInvalidFeeConfig, entry)
+		}
+
+		var tier LimitTier
+		typeName := strings.TrimSpace(key)
+		if prefix, rest, hasTier := strings.Cut(typeName, "."); hasTier {
+			tier = LimitTier(strings.ToLower(prefix))
+			typeName = rest
+			if !tier.IsValid() {
+				return FeeSchedule{}, fmt.Errorf("%w: unknown tier %q", ErrInvalidFeeConfig, prefix)
+			}
+		}
+		txType := TransactionType(strings.ToUpper(typeName))
+
+		rule, err := parseFeeRule(value)
+		if err != nil {
+			return FeeSchedule{}, err
+		}
+
+		if tier == "" {
+			if schedule.Rules == nil {
+				schedule.Rules = make(map[TransactionType]FeeRule)
+			}
+			schedule.Rules[txType] = rule
+			continue
+		}
+		if schedule.TierRules == nil {
+			schedule.TierRules = make(map[LimitTier]map[TransactionType]FeeRule)
+		}
+		if schedule.TierRules[tier] == nil {
+			schedule.TierRules[tier] = make(map[TransactionType]FeeRule)
+		}
+		schedule.TierRules[tier][txType] = rule
+	}
+
+	return schedule, nil
+}
+
+func parseFeeRule(value string) (FeeRule, error) {
+	var rule FeeRule
+	for _, part := range strings.Split(value, ",") {
+		name, raw, ok := strings.Cut(strings.TrimSpace(part), ":")
+		if !ok {
+			return FeeRule{}, fmt.Errorf("%w: %q", ErrInvalidFeeConfig, part)
+		}
+		amount, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+		if err != nil {
+			return FeeRule{}, fmt.Errorf("%w: %q", ErrInvalidFeeConfig, part)
+		}
+
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "flat":
+			rule.Flat = amount
+		case "percent":
+			rule.Percent = amount
+		default:
+			return FeeRule{}, fmt.Errorf("%w: unknown fee component %q", ErrInvalidFeeConfig, name)
+		}
+	}
+
+	if err := rule.validate(); err != nil {
+		return FeeRule{}, err
+	}
+	return rule, nil
+}
+
+// TransferFee transferle birlikte yazılan ücret işlemi ve ücretin aktarıldığı hesabın bakiyesi
+type TransferFee struct {
+	Transaction *Transaction
+	Balance     *Balance
+}
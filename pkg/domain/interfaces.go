@@ -3,6 +3,7 @@ package domain
 import (
 	"context"
 	"sync"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -14,6 +15,22 @@ type Logger interface {
 	Debug(msg string, keysAndValues ...interface{})
 }
 
+// NopLogger tüm kayıtları yok sayar; logger verilmeyen servislerde varsayılan olarak kullanılır
+type NopLogger struct{}
+
+func (NopLogger) Info(msg string, keysAndValues ...interface{})  {}
+func (NopLogger) Error(msg string, keysAndValues ...interface{}) {}
+func (NopLogger) Warn(msg string, keysAndValues ...interface{})  {}
+func (NopLogger) Debug(msg string, keysAndValues ...interface{}) {}
+
+// LoggerOrNop logger nil ise NopLogger döner; eksik logger servisleri panik yerine sessizce çalıştırır
+func LoggerOrNop(logger Logger) Logger {
+	if logger == nil {
+		return NopLogger{}
+	}
+	return logger
+}
+
 type UserService interface {
 	Register(ctx context.Context, user *User) error
 	Authenticate(ctx context.Context, email, password string) (*User, error)
@@ -34,10 +51,13 @@ type TransactionService interface {
 
 type ScheduledTransactionService interface {
 	CreateScheduledTransaction(ctx context.Context, userID uuid.UUID, req ScheduledTransactionRequest) (*ScheduledTransaction, error)
+	CreateScheduledTransactions(ctx context.Context, userID uuid.UUID, reqs []ScheduledTransactionRequest) ([]*ScheduledTransaction, []BulkItemError, error)
 	GetScheduledTransaction(ctx context.Context, id uuid.UUID) (*ScheduledTransaction, error)
 	GetUserScheduledTransactions(ctx context.Context, userID uuid.UUID) ([]*ScheduledTransaction, error)
 	UpdateScheduledTransaction(ctx context.Context, id uuid.UUID, req ScheduledTransactionRequest) error
 	CancelScheduledTransaction(ctx context.Context, id uuid.UUID) error
+	PauseScheduledTransaction(ctx context.Context, id uuid.UUID) (*ScheduledTransaction, error)
+	ResumeScheduledTransaction(ctx context.Context, id uuid.UUID) (*ScheduledTransaction, error)
 	ExecuteScheduledTransactions(ctx context.Context) error
 }
 
@@ -52,9 +72,15 @@ type BatchTransactionService interface {
 type TransactionLimitService interface {
 	CreateTransactionLimit(ctx context.Context, userID uuid.UUID, req TransactionLimitRequest) (*TransactionLimit, error)
 	GetTransactionLimit(ctx context.Context, userID uuid.UUID, currency Currency) (*TransactionLimit, error)
+	ListTransactionLimits(ctx context.Context, userID uuid.UUID) ([]*TransactionLimit, error)
 	UpdateTransactionLimit(ctx context.Context, userID uuid.UUID, currency Currency, req TransactionLimitRequest) error
 	CheckTransactionLimit(ctx context.Context, userID uuid.UUID, currency Currency, amount float64) error
-	UpdateTransactionUsage(ctx context.Context, userID uuid.UUID, currency Currency, amount float64) error
+	// PreviewTransactionLimit tutarın aştığı tüm limitleri hiçbir şey değiştirmeden döndürür
+	PreviewTransactionLimit(ctx context.Context, userID uuid.UUID, currency Currency, amount float64) ([]error, error)
+	ReserveTransactionLimit(ctx context.Context, userID uuid.UUID, currency Currency, amount float64, transactionID uuid.UUID) error
+	UpdateTransactionUsage(ctx context.Context, userID uuid.UUID, currency Currency, amount float64, transactionID uuid.UUID) error
+	// ReleaseTransactionLimit geri alınan veya başarısız olan işlemin limit kullanımını iade eder
+	ReleaseTransactionLimit(ctx context.Context, transactionID uuid.UUID) error
 	ResetTransactionLimits(ctx context.Context, userID uuid.UUID, currency Currency) error
 }
 
@@ -64,6 +90,9 @@ type MultiCurrencyService interface {
 	GetAllBalances(ctx context.Context, userID uuid.UUID) ([]*MultiCurrencyBalance, error)
 	ConvertCurrency(ctx context.Context, req CurrencyConversionRequest) (*CurrencyConversionResponse, error)
 	TransferBetweenCurrencies(ctx context.Context, userID uuid.UUID, fromCurrency, toCurrency Currency, amount float64) error
+	// GetTransactionHistory kullanıcının dönüşüm ve para birimleri arası transfer kayıtlarını en yeniden
+	// başlayarak filtreye göre sayfalı döner; toplam kayıt sayısını da verir
+	GetTransactionHistory(ctx context.Context, userID uuid.UUID, filter MultiCurrencyTransactionFilter) ([]*MultiCurrencyTransaction, int64, error)
 }
 
 type BalanceService interface {
@@ -93,11 +122,25 @@ type TransactionRepository interface {
 
 type ScheduledTransactionRepository interface {
 	Create(ctx context.Context, scheduledTransaction *ScheduledTransaction) error
+	CreateMany(ctx context.Context, scheduledTransactions []*ScheduledTransaction) error
 	GetByID(ctx context.Context, id uuid.UUID) (*ScheduledTransaction, error)
 	GetByUserID(ctx context.Context, userID uuid.UUID) ([]*ScheduledTransaction, error)
 	GetPendingScheduledTransactions(ctx context.Context) ([]*ScheduledTransaction, error)
+	// GetRetryableScheduledTransactions deneme hakkı kalan ve NextRetryAt'i now'a kadar dolmuş
+	// başarısız işlemleri döner
+	GetRetryableScheduledTransactions(ctx context.Context, now time.Time) ([]*ScheduledTransaction, error)
+	// GetExpiredLeaseScheduledTransactions kirası now'dan önce dolmuş, executing durumunda kalan işlemleri döner
+	GetExpiredLeaseScheduledTransactions(ctx context.Context, now time.Time) ([]*ScheduledTransaction, error)
+	// ClaimScheduledTransaction işlemi koşullu bir UPDATE ile owner adına leaseUntil'e kadar kiralar.
+	// İşlem hâlâ çalıştırılmaya uygunsa ve başka bir örnek tarafından alınmamışsa true döner.
+	ClaimScheduledTransaction(ctx context.Context, id uuid.UUID, owner string, now, leaseUntil time.Time) (bool, error)
 	Update(ctx context.Context, scheduledTransaction *ScheduledTransaction) error
+	// UpdateModifiable işlemi yalnızca veritabanındaki durumu hâlâ güncellenebilirse kaydeder; bu
+	// sırada bir zamanlayıcı işlemi kiralamışsa false döner
+	UpdateModifiable(ctx context.Context, scheduledTransaction *ScheduledTransaction) (bool, error)
+	// Delete işlemi soft-delete ile siler; GetByIDIncludingDeleted denetim için silinmiş işlemleri de döner
 	Delete(ctx context.Context, id uuid.UUID) error
+	GetByIDIncludingDeleted(ctx context.Context, id uuid.UUID) (*ScheduledTransaction, error)
 }
 
 type BatchTransactionRepository interface {
@@ -105,7 +148,9 @@ type BatchTransactionRepository interface {
 	GetByID(ctx context.Context, id uuid.UUID) (*BatchTransaction, error)
 	GetByUserID(ctx context.Context, userID uuid.UUID) ([]*BatchTransaction, error)
 	Update(ctx context.Context, batchTransaction *BatchTransaction) error
+	// Delete batch'i kalemleriyle birlikte soft-delete ile siler
 	Delete(ctx context.Context, id uuid.UUID) error
+	GetByIDIncludingDeleted(ctx context.Context, id uuid.UUID) (*BatchTransaction, error)
 }
 
 type BatchTransactionItemRepository interface {
@@ -118,18 +163,35 @@ type BatchTransactionItemRepository interface {
 type TransactionLimitRepository interface {
 	Create(ctx context.Context, limit *TransactionLimit) error
 	GetByUserIDAndCurrency(ctx context.Context, userID uuid.UUID, currency Currency) (*TransactionLimit, error)
+	GetByUserID(ctx context.Context, userID uuid.UUID) ([]*TransactionLimit, error)
+	// UpdateLocked kaydı satır kilidi altında okuyup fn ile değiştirir ve kaydeder; fn hata dönerse
+	// değişiklik yazılmaz. seed nil değilse ve kayıt yoksa önce seed eklenir. reservation verilirse
+	// aynı transaction'da yazılır; işlem için rezervasyon zaten varsa fn çağrılmaz
+	UpdateLocked(ctx context.Context, userID uuid.UUID, currency Currency, seed *TransactionLimit, reservation *LimitReservation, fn func(limit *TransactionLimit) error) error
+	// RefundLocked işlemin rezervasyonunu ve bağlı limit kaydını kilitleyip fn ile iade eder;
+	// rezervasyon daha önce iade edildiyse hiçbir şey yapmaz
+	RefundLocked(ctx context.Context, transactionID uuid.UUID, fn func(limit *TransactionLimit, reservation *LimitReservation) error) error
 	Update(ctx context.Context, limit *TransactionLimit) error
 	Delete(ctx context.Context, id uuid.UUID) error
 }
 
 type MultiCurrencyBalanceRepository interface {
 	Create(ctx context.Context, balance *MultiCurrencyBalance) error
+	// CreateMany bakiyeleri tek bir transaction'da ekler; biri başarısız olursa hiçbiri yazılmaz
+	CreateMany(ctx context.Context, balances []*MultiCurrencyBalance) error
 	GetByUserIDAndCurrency(ctx context.Context, userID uuid.UUID, currency Currency) (*MultiCurrencyBalance, error)
 	GetByUserID(ctx context.Context, userID uuid.UUID) ([]*MultiCurrencyBalance, error)
 	Update(ctx context.Context, balance *MultiCurrencyBalance) error
 	Delete(ctx context.Context, id uuid.UUID) error
 }
 
+type MultiCurrencyTransactionRepository interface {
+	// ApplyConversion kaydı ve iki bakiyenin güncellemesini tek bir transaction'da yazar; bakiyeler
+	// kayıt olmadan değişmez
+	ApplyConversion(ctx context.Context, record *MultiCurrencyTransaction, from, to *MultiCurrencyBalance) error
+	ListByUserID(ctx context.Context, userID uuid.UUID, filter MultiCurrencyTransactionFilter) ([]*MultiCurrencyTransaction, int64, error)
+}
+
 type BalanceRepository interface {
 	Create(ctx context.Context, balance *Balance) error
 	GetByID(ctx context.Context, id uint) (*Balance, error)
@@ -140,6 +202,13 @@ type BalanceRepository interface {
 	GetHistoryByUserID(ctx context.Context, userID uint) ([]*BalanceHistory, error)
 }
 
+// TxManager birden fazla repository çağrısını tek bir veritabanı transaction'ında çalıştırır. fn'e
+// verilen context'le yapılan tüm repository çağrıları aynı transaction'ı kullanır; fn hata dönerse
+// hepsi geri alınır.
+type TxManager interface {
+	WithTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
 type ExchangeRateService interface {
 	GetExchangeRate(ctx context.Context, fromCurrency, toCurrency Currency) (*ExchangeRate, error)
 	UpdateExchangeRate(ctx context.Context, fromCurrency, toCurrency Currency, rate float64) error
@@ -162,3 +231,31 @@ func (s *TransactionStats) UpdateStats(amount float64, processTime float64) {
 	s.TotalAmount += amount
 	s.AverageProcessTime = (s.AverageProcessTime*float64(s.TotalProcessed-1) + processTime) / float64(s.TotalProcessed)
 }
+
+// RecordFailure başarısız bir işlemi sayar
+func (s *TransactionStats) RecordFailure() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.TotalFailed++
+}
+
+// TransactionStatsSnapshot istatistiklerin API'de dönülebilecek kilitsiz kopyası
+type TransactionStatsSnapshot struct {
+	TotalProcessed     uint64  `json:"total_processed"`
+	TotalFailed        uint64  `json:"total_failed"`
+	TotalAmount        float64 `json:"total_amount"`
+	AverageProcessTime float64 `json:"average_process_time"`
+}
+
+func (s *TransactionStats) Snapshot() TransactionStatsSnapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return TransactionStatsSnapshot{
+		TotalProcessed:     s.TotalProcessed,
+		TotalFailed:        s.TotalFailed,
+		TotalAmount:        s.TotalAmount,
+		AverageProcessTime: s.AverageProcessTime,
+	}
+}
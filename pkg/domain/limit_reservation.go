@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LimitReservation bir işlem için limitten düşülen kullanımı kaydeder; işlem geri alındığında
+// veya başarısız olduğunda iade bu kayıt üzerinden ve işlem başına yalnızca bir kez yapılır
+type LimitReservation struct {
+	ID            uuid.UUID  `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	TransactionID uuid.UUID  `json:"transaction_id" gorm:"type:uuid;not null;uniqueIndex"`
+	LimitID       uuid.UUID  `json:"limit_id" gorm:"type:uuid;not null;index"`
+	UserID        uuid.UUID  `json:"user_id" gorm:"type:uuid;not null"`
+	Currency      Currency   `json:"currency" gorm:"type:varchar(3);not null"`
+	Amount        float64    `json:"amount" gorm:"type:decimal(19,4);not null"`
+	ReservedAt    time.Time  `json:"reserved_at" gorm:"not null"`
+	RefundedAt    *time.Time `json:"refunded_at,omitempty"`
+}
+
+func (LimitReservation) TableName() string {
+	return "transaction_limit_reservations"
+}
+
+func NewLimitReservation(transactionID, userID uuid.UUID, currency Currency, amount float64) *LimitReservation {
+	return &LimitReservation{
+		ID:            uuid.New(),
+		TransactionID: transactionID,
+		UserID:        userID,
+		Currency:      currency,
+		Amount:        amount,
+		ReservedAt:    time.Now(),
+	}
+}
+
+func (r *LimitReservation) IsRefunded() bool {
+	return r.RefundedAt != nil
+}
+
+// RefundDailyUsage rezervasyonla düşülen kullanımı geri verir. Rezervasyon bir dönemin sayacı
+// sıfırlanmadan önce yapıldıysa o dönemin kullanımı zaten sıfırlanmıştır ve sayaçlarına dokunulmaz;
+// sayaçlar hiçbir durumda negatife düşmez
+func (tl *TransactionLimit) RefundDailyUsage(amount float64, reservedAt time.Time) {
+	tl.mu.Lock()
+	defer tl.mu.Unlock()
+
+	if !reservedAt.Before(tl.LastResetDate) {
+		refundPeriod(&tl.DailyAmount, &tl.DailyCount, amount)
+	}
+	if !reservedAt.Before(tl.WeeklyResetDate) {
+		refundPeriod(&tl.WeeklyAmount, &tl.WeeklyCount, amount)
+	}
+	if !reservedAt.Before(tl.MonthlyResetDate) {
+		refundPeriod(&tl.MonthlyAmount, &tl.MonthlyCount, amount)
+	}
+	tl.UpdatedAt = time.Now()
+}
+
+func refundPeriod(used *float64, count *int, amount float64) {
+	*used -= amount
+	if *used < 0 {
+		*used = 0
+	}
+	if *count > 0 {
+		*count--
+	}
+}
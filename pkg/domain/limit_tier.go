@@ -0,0 +1,121 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LimitTier kullanıcıya atanan limit seviyesi; kullanıcıya özel limit tanımlı değilse
+// seviyenin varsayılan limitleri uygulanır
+type LimitTier string
+
+const (
+	LimitTierBasic    LimitTier = "basic"
+	LimitTierVerified LimitTier = "verified"
+	LimitTierPremium  LimitTier = "premium"
+)
+
+// LimitTierDefinition seviyenin her para biriminde uygulanan varsayılan limitleri
+type LimitTierDefinition struct {
+	Tier         LimitTier `json:"tier"`
+	DailyLimit   float64   `json:"daily_limit"`
+	WeeklyLimit  float64   `json:"weekly_limit"`
+	MonthlyLimit float64   `json:"monthly_limit"`
+	SingleLimit  float64   `json:"single_limit"`
+}
+
+// DefaultLimitTiers merkezi olarak tanımlanan seviye limitlerini döndürür
+func DefaultLimitTiers() map[LimitTier]LimitTierDefinition {
+	return map[LimitTier]LimitTierDefinition{
+		LimitTierBasic: {
+			Tier:         LimitTierBasic,
+			DailyLimit:   1000,
+			WeeklyLimit:  5000,
+			MonthlyLimit: 15000,
+			SingleLimit:  500,
+		},
+		LimitTierVerified: {
+			Tier:         LimitTierVerified,
+			DailyLimit:   10000,
+			WeeklyLimit:  50000,
+			MonthlyLimit: 150000,
+			SingleLimit:  5000,
+		},
+		LimitTierPremium: {
+			Tier:         LimitTierPremium,
+			DailyLimit:   100000,
+			WeeklyLimit:  500000,
+			MonthlyLimit: 1500000,
+			SingleLimit:  50000,
+		},
+	}
+}
+
+func (t LimitTier) IsValid() bool {
+	_, ok := DefaultLimitTiers()[t]
+	return ok
+}
+
+// LimitTierResolver kullanıcının atanmış limit seviyesini döndürür
+type LimitTierResolver interface {
+	GetLimitTier(ctx context.Context, userID uuid.UUID) (LimitTier, error)
+}
+
+// NewTierLimit seviye tanımından kullanıcının para birimi için kullanım sayacı tutan limit kaydı üretir
+func NewTierLimit(userID uuid.UUID, currency Currency, definition LimitTierDefinition) *TransactionLimit {
+	now := time.Now()
+	return &TransactionLimit{
+		ID:            uuid.New(),
+		UserID:        userID,
+		Currency:      currency,
+		DailyLimit:    definition.DailyLimit,
+		WeeklyLimit:   definition.WeeklyLimit,
+		MonthlyLimit:  definition.MonthlyLimit,
+		SingleLimit:   definition.SingleLimit,
+		Tier:          definition.Tier,
+		LastResetDate: now,
+		IsActive:      true,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+
+		DailyCountLimit:   DefaultDailyCountLimit,
+		WeeklyCountLimit:  DefaultWeeklyCountLimit,
+		MonthlyCountLimit: DefaultMonthlyCountLimit,
+		WeeklyResetDate:   now,
+		MonthlyResetDate:  now,
+	}
+}
+
+// IsOverride limit kaydının kullanıcıya özel tanımlanıp tanımlanmadığını döndürür;
+// seviyeden türetilen kayıtlar yalnızca kullanım sayaçlarını tutar
+func (tl *TransactionLimit) IsOverride() bool {
+	return tl.Tier == ""
+}
+
+// TierDefinition kaydın mevcut limit değerlerini seviye tanımı olarak döndürür
+func (tl *TransactionLimit) TierDefinition() LimitTierDefinition {
+	tl.mu.RLock()
+	defer tl.mu.RUnlock()
+
+	return LimitTierDefinition{
+		Tier:         tl.Tier,
+		DailyLimit:   tl.DailyLimit,
+		WeeklyLimit:  tl.WeeklyLimit,
+		MonthlyLimit: tl.MonthlyLimit,
+		SingleLimit:  tl.SingleLimit,
+	}
+}
+
+// ApplyTier seviyeden türetilmiş kaydın limit değerlerini güncel seviye tanımıyla eşitler
+func (tl *TransactionLimit) ApplyTier(definition LimitTierDefinition) {
+	tl.mu.Lock()
+	defer tl.mu.Unlock()
+
+	tl.Tier = definition.Tier
+	tl.DailyLimit = definition.DailyLimit
+	tl.WeeklyLimit = definition.WeeklyLimit
+	tl.MonthlyLimit = definition.MonthlyLimit
+	tl.SingleLimit = definition.SingleLimit
+}
@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MultiCurrencyTransactionType çoklu para birimi bakiyelerini değiştiren işlem türü
+type MultiCurrencyTransactionType string
+
+const (
+	MultiCurrencyTransactionConversion MultiCurrencyTransactionType = "conversion"
+	MultiCurrencyTransactionTransfer   MultiCurrencyTransactionType = "cross_currency_transfer"
+)
+
+func (t MultiCurrencyTransactionType) IsValid() bool {
+	switch t {
+	case MultiCurrencyTransactionConversion, MultiCurrencyTransactionTransfer:
+		return true
+	}
+	return false
+}
+
+// MultiCurrencyTransaction bir dönüşümün veya para birimleri arası transferin kaydıdır. FromAmount
+// kaynak bakiyeden düşülen, ToAmount hedef bakiyeye eklenen tutardır; Fee kaynak para biriminde
+// FromAmount'a dahildir ve dönüşüm Rate ile (FromAmount - Fee) üzerinden yapılır.
+type MultiCurrencyTransaction struct {
+	ID           uuid.UUID                    `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	UserID       uuid.UUID                    `json:"user_id" gorm:"type:uuid;not null;index"`
+	Type         MultiCurrencyTransactionType `json:"type" gorm:"type:varchar(30);not null"`
+	FromCurrency Currency                     `json:"from_currency" gorm:"type:varchar(3);not null"`
+	ToCurrency   Currency                     `json:"to_currency" gorm:"type:varchar(3);not null"`
+	FromAmount   float64                      `json:"from_amount" gorm:"type:decimal(19,4);not null"`
+	ToAmount     float64                      `json:"to_amount" gorm:"type:decimal(19,4);not null"`
+	Rate         float64                      `json:"rate" gorm:"type:decimal(19,8);not null"`
+	Fee          float64                      `json:"fee" gorm:"type:decimal(19,4);not null;default:0"`
+	RateSource   string                       `json:"rate_source,omitempty" gorm:"type:varchar(50)"`
+	CreatedAt    time.Time                    `json:"created_at" gorm:"not null;index"`
+}
+
+func (MultiCurrencyTransaction) TableName() string {
+	return "multi_currency_transactions"
+}
+
+// NewMultiCurrencyTransaction kaynak tutar, ücret ve kurdan hedef tutarı hesaplayarak kaydı oluşturur
+func NewMultiCurrencyTransaction(userID uuid.UUID, txType MultiCurrencyTransactionType, rate *ExchangeRate, fromAmount, fee float64) (*MultiCurrencyTransaction, error) {
+	if !txType.IsValid() {
+		return nil, ErrInvalidTransactionType
+	}
+	if !rate.FromCurrency.IsSupported() || !rate.ToCurrency.IsSupported() {
+		return nil, ErrCurrencyNotSupported
+	}
+	if fromAmount <= 0 || fee < 0 || fee >= fromAmount || rate.Rate <= 0 {
+		return nil, ErrInvalidAmount
+	}
+
+	return &MultiCurrencyTransaction{
+		ID:           uuid.New(),
+		UserID:       userID,
+		Type:         txType,
+		FromCurrency: rate.FromCurrency,
+		ToCurrency:   rate.ToCurrency,
+		FromAmount:   fromAmount,
+		ToAmount:     math.Round((fromAmount-fee)*rate.Rate*10000) / 10000, // decimal(19,4) hassasiyeti
+		Rate:         rate.Rate,
+		Fee:          fee,
+		RateSource:   rate.Source,
+		CreatedAt:    time.Now(),
+	}, nil
+}
+
+// MultiCurrencyTransactionFilter geçmiş sorgusunun filtresidir. Currency verilirse o para biriminden
+// çıkan veya o para birimine giren işlemler döner.
+type MultiCurrencyTransactionFilter struct {
+	Currency *Currency
+	Type     *MultiCurrencyTransactionType
+	Limit    int
+	Offset   int
+}
@@ -0,0 +1,69 @@
+package domain
+
+import "strconv"
+
+const (
+	DefaultPageLimit = 100
+	MaxPageLimit     = 1000
+)
+
+// PageInfo liste yanıtlarındaki sayfalama bilgisidir. Cursor tabanlı uçlarda NextCursor dolu olur.
+type PageInfo struct {
+	Limit      int    `json:"limit"`
+	Offset     int    `json:"offset"`
+	Total      int64  `json:"total"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// Page tüm liste uçlarının döndüğü standart { data, page } zarfıdır
+type Page[T any] struct {
+	Data []T      `json:"data"`
+	Page PageInfo `json:"page"`
+}
+
+// NewPage veritabanında sayfalanmış veriyi ve toplam kayıt sayısını zarfa koyar
+func NewPage[T any](data []T, limit, offset int, total int64) Page[T] {
+	if data == nil {
+		data = []T{}
+	}
+	return Page[T]{
+		Data: data,
+		Page: PageInfo{Limit: limit, Offset: offset, Total: total},
+	}
+}
+
+// Paginate bellekteki tam listeyi limit/offset ile keser
+func Paginate[T any](items []T, limit, offset int) Page[T] {
+	total := len(items)
+	start := offset
+	if start > total {
+		start = total
+	}
+	end := start + limit
+	if end > total {
+		end = total
+	}
+	return NewPage(items[start:end], limit, offset, int64(total))
+}
+
+// ParsePageParams limit ve offset sorgu parametrelerini doğrular; boş değerler varsayılanlara düşer
+func ParsePageParams(limitStr, offsetStr string) (limit, offset int, err error) {
+	limit = DefaultPageLimit
+	if limitStr != "" {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 {
+			return 0, 0, ErrInvalidPagination
+		}
+		if limit > MaxPageLimit {
+			limit = MaxPageLimit
+		}
+	}
+
+	if offsetStr != "" {
+		offset, err = strconv.Atoi(offsetStr)
+		if err != nil || offset < 0 {
+			return 0, 0, ErrInvalidPagination
+		}
+	}
+	return limit, offset, nil
+}
@@ -0,0 +1,102 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestPaginate(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5}
+
+	tests := []struct {
+		name   string
+		limit  int
+		offset int
+		want   []int
+	}{
+		{name: "ilk sayfa", limit: 2, want: []int{1, 2}},
+		{name: "ara sayfa", limit: 2, offset: 2, want: []int{3, 4}},
+		{name: "son eksik sayfa", limit: 2, offset: 4, want: []int{5}},
+		{name: "toplamı aşan offset", limit: 2, offset: 10, want: []int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page := Paginate(items, tt.limit, tt.offset)
+
+			if len(page.Data) != len(tt.want) {
+				t.Fatalf("data = %v, beklenen %v", page.Data, tt.want)
+			}
+			for i := range tt.want {
+				if page.Data[i] != tt.want[i] {
+					t.Fatalf("data = %v, beklenen %v", page.Data, tt.want)
+				}
+			}
+			if page.Page.Total != int64(len(items)) || page.Page.Limit != tt.limit || page.Page.Offset != tt.offset {
+				t.Errorf("page = %+v, beklenen toplam %d", page.Page, len(items))
+			}
+		})
+	}
+}
+
+func TestPageEnvelopeShape(t *testing.T) {
+	tests := []struct {
+		name     string
+		page     Page[string]
+		wantJSON string
+	}{
+		{
+			name:     "boş veri dizi olarak yazılır",
+			page:     NewPage[string](nil, 10, 0, 0),
+			wantJSON: `{"data":[],"page":{"limit":10,"offset":0,"total":0}}`,
+		},
+		{
+			name:     "cursor varsa yazılır",
+			page:     Page[string]{Data: []string{"a"}, Page: PageInfo{Limit: 1, Total: 3, NextCursor: "c1"}},
+			wantJSON: `{"data":["a"],"page":{"limit":1,"offset":0,"total":3,"next_cursor":"c1"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(tt.page)
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+			if string(got) != tt.wantJSON {
+				t.Errorf("json = %s, beklenen %s", got, tt.wantJSON)
+			}
+		})
+	}
+}
+
+func TestParsePageParams(t *testing.T) {
+	tests := []struct {
+		name       string
+		limit      string
+		offset     string
+		wantLimit  int
+		wantOffset int
+		wantErr    error
+	}{
+		{name: "varsayılanlar", wantLimit: DefaultPageLimit},
+		{name: "verilen değerler", limit: "20", offset: "40", wantLimit: 20, wantOffset: 40},
+		{name: "üst sınıra indirilir", limit: "5000", wantLimit: MaxPageLimit},
+		{name: "sıfır limit", limit: "0", wantErr: ErrInvalidPagination},
+		{name: "negatif offset", offset: "-1", wantErr: ErrInvalidPagination},
+		{name: "sayı olmayan limit", limit: "abc", wantErr: ErrInvalidPagination},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limit, offset, err := ParsePageParams(tt.limit, tt.offset)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("hata = %v, beklenen %v", err, tt.wantErr)
+			}
+			if limit != tt.wantLimit || offset != tt.wantOffset {
+				t.Errorf("limit/offset = %d/%d, beklenen %d/%d", limit, offset, tt.wantLimit, tt.wantOffset)
+			}
+		})
+	}
+}
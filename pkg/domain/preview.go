@@ -0,0 +1,142 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TransactionPreviewRequest bir işlemin hiçbir şey değiştirilmeden ön kontrolü için kullanılır
+type TransactionPreviewRequest struct {
+	Type     TransactionType `json:"type" binding:"required"`
+	Amount   float64         `json:"amount" binding:"required,gt=0"`
+	Currency Currency        `json:"currency"`
+	ToUserID *uuid.UUID      `json:"to_user_id"`
+}
+
+// PreviewReason işlemin neden reddedileceğini sabit bir kod ve okunabilir mesajla açıklar
+type PreviewReason struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type TransactionPreview struct {
+	Allowed   bool            `json:"allowed"`
+	Type      TransactionType `json:"type"`
+	Amount    float64         `json:"amount"`
+	Currency  Currency        `json:"currency"`
+	Available *float64        `json:"available,omitempty"`
+	Reasons   []PreviewReason `json:"reasons"`
+
+	// Fee işlem için alınacak ücret; bakiye kontrolü tutar + ücret üzerinden yapılır
+	Fee float64 `json:"fee,omitempty"`
+}
+
+// previewReasonCodes ön kontrolde raporlanan hataları istemcilerin eşleyebileceği kodlara çevirir
+var previewReasonCodes = []struct {
+	err  error
+	code string
+}{
+	{ErrInvalidAmount, "invalid_amount"},
+	{ErrInvalidTransactionType, "invalid_type"},
+	{ErrCurrencyNotSupported, "unsupported_currency"},
+	{ErrCurrencyMismatch, "currency_mismatch"},
+	{ErrBalanceNotFound, "balance_not_found"},
+	{ErrMinimumBalanceBreached, "minimum_balance_breached"},
+	{ErrInsufficientBalance, "insufficient_funds"},
+	{ErrRecipientRequired, "recipient_required"},
+	{ErrSelfTransfer, "self_transfer"},
+	{ErrUserNotFound, "recipient_not_found"},
+	{ErrTransactionLimitExceeded, "single_limit_exceeded"},
+	{ErrDailyLimitExceeded, "daily_limit_exceeded"},
+	{ErrDailyCountExceeded, "daily_count_exceeded"},
+	{ErrWeeklyLimitExceeded, "weekly_limit_exceeded"},
+	{ErrWeeklyCountExceeded, "weekly_count_exceeded"},
+	{ErrMonthlyLimitExceeded, "monthly_limit_exceeded"},
+	{ErrMonthlyCountExceeded, "monthly_count_exceeded"},
+}
+
+func NewTransactionPreview(req *TransactionPreviewRequest) *TransactionPreview {
+	return &TransactionPreview{
+		Type:     req.Type,
+		Amount:   req.Amount,
+		Currency: req.Currency,
+		Reasons:  []PreviewReason{},
+	}
+}
+
+// Reject işlemi reddedilecek olarak işaretleyip nedeni ekler
+func (p *TransactionPreview) Reject(err error) {
+	code := "rejected"
+	for _, entry := range previewReasonCodes {
+		if errors.Is(err, entry.err) {
+			code = entry.code
+			break
+		}
+	}
+	p.Reasons = append(p.Reasons, PreviewReason{Code: code, Message: err.Error()})
+}
+
+// Finalize nedeni olmayan önizlemeyi izin verilmiş olarak işaretler
+func (p *TransactionPreview) Finalize() *TransactionPreview {
+	p.Allowed = len(p.Reasons) == 0
+	return p
+}
+
+// Violations tutarın aştığı tüm limitleri kaydı değiştirmeden döndürür; süresi dolan dönemlerin
+// kullanımı now anına göre sıfırlanmış kabul edilir
+func (tl *TransactionLimit) Violations(amount float64, now time.Time) []error {
+	tl.mu.RLock()
+	snapshot := TransactionLimit{
+		DailyLimit:        tl.DailyLimit,
+		WeeklyLimit:       tl.WeeklyLimit,
+		MonthlyLimit:      tl.MonthlyLimit,
+		SingleLimit:       tl.SingleLimit,
+		DailyCount:        tl.DailyCount,
+		WeeklyCount:       tl.WeeklyCount,
+		MonthlyCount:      tl.MonthlyCount,
+		DailyAmount:       tl.DailyAmount,
+		WeeklyAmount:      tl.WeeklyAmount,
+		MonthlyAmount:     tl.MonthlyAmount,
+		LastResetDate:     tl.LastResetDate,
+		IsActive:          tl.IsActive,
+		DailyCountLimit:   tl.DailyCountLimit,
+		WeeklyCountLimit:  tl.WeeklyCountLimit,
+		MonthlyCountLimit: tl.MonthlyCountLimit,
+		WeeklyResetDate:   tl.WeeklyResetDate,
+		MonthlyResetDate:  tl.MonthlyResetDate,
+	}
+	tl.mu.RUnlock()
+
+	if !snapshot.IsActive {
+		return nil
+	}
+
+	var violations []error
+	if amount > snapshot.SingleLimit {
+		violations = append(violations, ErrTransactionLimitExceeded)
+	}
+
+	snapshot.rollPeriods(now)
+	checks := []struct {
+		used, limit float64
+		count       int
+		countLimit  int
+		amountErr   error
+		countErr    error
+	}{
+		{snapshot.DailyAmount, snapshot.DailyLimit, snapshot.DailyCount, countLimitOrDefault(snapshot.DailyCountLimit, DefaultDailyCountLimit), ErrDailyLimitExceeded, ErrDailyCountExceeded},
+		{snapshot.WeeklyAmount, snapshot.WeeklyLimit, snapshot.WeeklyCount, countLimitOrDefault(snapshot.WeeklyCountLimit, DefaultWeeklyCountLimit), ErrWeeklyLimitExceeded, ErrWeeklyCountExceeded},
+		{snapshot.MonthlyAmount, snapshot.MonthlyLimit, snapshot.MonthlyCount, countLimitOrDefault(snapshot.MonthlyCountLimit, DefaultMonthlyCountLimit), ErrMonthlyLimitExceeded, ErrMonthlyCountExceeded},
+	}
+	for _, check := range checks {
+		if check.used+amount > check.limit {
+			violations = append(violations, check.amountErr)
+		}
+		if check.count >= check.countLimit {
+			violations = append(violations, check.countErr)
+		}
+	}
+	return violations
+}
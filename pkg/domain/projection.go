@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Projection event akışını sırayla tüketip kendi okuma modelini güncelleyen bileşen
+type Projection interface {
+	Name() string
+	Handle(ctx context.Context, event Event) error
+}
+
+// ProjectionCheckpoint bir projeksiyonun başarıyla işlediği son event'i saklar; yeniden
+// başlatmada okuma bu noktadan devam eder
+type ProjectionCheckpoint struct {
+	ProjectionName string    `json:"projection_name" gorm:"primaryKey;type:varchar(100)"`
+	LastTimestamp  time.Time `json:"last_timestamp" gorm:"not null"`
+	LastEventID    uuid.UUID `json:"last_event_id" gorm:"type:uuid;not null"`
+	ProcessedCount int64     `json:"processed_count" gorm:"not null;default:0"`
+	UpdatedAt      time.Time `json:"updated_at" gorm:"not null"`
+}
+
+func (ProjectionCheckpoint) TableName() string {
+	return "projection_checkpoints"
+}
+
+// Cursor checkpoint'i GetEventsAfter ile kullanılabilecek cursor'a çevirir
+func (c *ProjectionCheckpoint) Cursor() *EventCursor {
+	if c == nil {
+		return nil
+	}
+	return &EventCursor{Timestamp: c.LastTimestamp, ID: c.LastEventID}
+}
+
+// Advance checkpoint'i başarıyla işlenen event'e ilerletir
+func (c *ProjectionCheckpoint) Advance(event Event) {
+	c.LastTimestamp = event.GetTimestamp()
+	c.LastEventID = event.GetID()
+	c.ProcessedCount++
+}
+
+// CheckpointStore projeksiyon adına göre checkpoint'leri saklar
+type CheckpointStore interface {
+	// Get checkpoint'i döndürür; projeksiyon henüz hiçbir event işlememişse nil, nil döner
+	Get(ctx context.Context, projectionName string) (*ProjectionCheckpoint, error)
+	Save(ctx context.Context, checkpoint *ProjectionCheckpoint) error
+}
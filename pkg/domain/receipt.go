@@ -0,0 +1,153 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReceiptGenesisHash kullanıcının ilk makbuzunun PreviousHash değeridir
+var ReceiptGenesisHash = strings.Repeat("0", sha256.Size*2)
+
+// TransactionReceipt tamamlanan bir işlemin değiştirilemezliğini kanıtlayan makbuzdur.
+// Her makbuzun hash'i aynı kullanıcının bir önceki makbuzunun hash'ini içerir; böylece
+// zincirdeki herhangi bir işlem sonradan değiştirilirse sonraki tüm hash'ler geçersiz olur.
+type TransactionReceipt struct {
+	ID            uuid.UUID       `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	TransactionID uuid.UUID       `json:"transaction_id" gorm:"type:uuid;not null;uniqueIndex"`
+	UserID        uuid.UUID       `json:"user_id" gorm:"type:uuid;not null;uniqueIndex:idx_receipt_user_sequence"`
+	Sequence      int64           `json:"sequence" gorm:"not null;uniqueIndex:idx_receipt_user_sequence"`
+	Type          TransactionType `json:"type" gorm:"type:varchar(20);not null"`
+	Amount        float64         `json:"amount" gorm:"type:decimal(19,4);not null"`
+	BalanceAfter  float64         `json:"balance_after" gorm:"type:decimal(19,4);not null"`
+	PreviousHash  string          `json:"previous_hash" gorm:"type:char(64);not null"`
+	Hash          string          `json:"hash" gorm:"type:char(64);not null"`
+	CreatedAt     time.Time       `json:"created_at" gorm:"not null"`
+}
+
+func (TransactionReceipt) TableName() string {
+	return "transaction_receipts"
+}
+
+// NewTransactionReceipt işlemi previous makbuzun ardına zincirler; previous nil ise zincirin ilk halkasıdır
+func NewTransactionReceipt(transaction *Transaction, previous *TransactionReceipt) *TransactionReceipt {
+	receipt := &TransactionReceipt{
+		ID:            uuid.New(),
+		TransactionID: transaction.ID,
+		UserID:        transaction.UserID,
+		Sequence:      1,
+		Type:          transaction.Type,
+		Amount:        transaction.Amount,
+		BalanceAfter:  transaction.BalanceAfter,
+		PreviousHash:  ReceiptGenesisHash,
+		CreatedAt:     time.Now(),
+	}
+	if previous != nil {
+		receipt.Sequence = previous.Sequence + 1
+		receipt.PreviousHash = previous.Hash
+	}
+	receipt.Hash = receipt.ComputeHash()
+	return receipt
+}
+
+// ComputeHash makbuz alanlarından hash'i yeniden hesaplar. Tutarlar veritabanındaki
+// decimal(19,4) hassasiyetiyle biçimlendirilir, böylece okuma/yazma turu hash'i değiştirmez.
+func (r *TransactionReceipt) ComputeHash() string {
+	payload := fmt.Sprintf("%s|%s|%s|%d|%s|%.4f|%.4f",
+		r.PreviousHash,
+		r.TransactionID,
+		r.UserID,
+		r.Sequence,
+		r.Type,
+		r.Amount,
+		r.BalanceAfter,
+	)
+	sum := sha256.Sum256([]byte(payload))
+	return hex.EncodeToString(sum[:])
+}
+
+// Matches makbuzun işlemin şu anki kaydıyla aynı değerleri taşıyıp taşımadığını kontrol eder
+func (r *TransactionReceipt) Matches(transaction *Transaction) bool {
+	return r.TransactionID == transaction.ID &&
+		r.UserID == transaction.UserID &&
+		r.Type == transaction.Type &&
+		fmt.Sprintf("%.4f", r.Amount) == fmt.Sprintf("%.4f", transaction.Amount) &&
+		fmt.Sprintf("%.4f", r.BalanceAfter) == fmt.Sprintf("%.4f", transaction.BalanceAfter)
+}
+
+// ReceiptChainBreak zincirin bozulduğu makbuzu ve nedenini tanımlar
+type ReceiptChainBreak struct {
+	Sequence      int64     `json:"sequence"`
+	TransactionID uuid.UUID `json:"transaction_id"`
+	Reason        string    `json:"reason"`
+}
+
+const (
+	ReceiptBreakHashMismatch        = "hash_mismatch"
+	ReceiptBreakPreviousHash        = "previous_hash_mismatch"
+	ReceiptBreakSequenceGap         = "sequence_gap"
+	ReceiptBreakTransactionModified = "transaction_modified"
+)
+
+// ReceiptChainVerification bir kullanıcının makbuz zincirinin doğrulama sonucudur
+type ReceiptChainVerification struct {
+	UserID   uuid.UUID           `json:"user_id"`
+	Valid    bool                `json:"valid"`
+	Receipts int                 `json:"receipts"`
+	LastHash string              `json:"last_hash"`
+	Breaks   []ReceiptChainBreak `json:"breaks"`
+	// MissingTransactions arşivlenmiş veya silinmiş işlemler; bunlar için yalnızca makbuz zinciri doğrulanır
+	MissingTransactions int       `json:"missing_transactions"`
+	VerifiedAt          time.Time `json:"verified_at"`
+}
+
+// VerifyReceiptChain sequence sırasına göre verilen makbuzların zincirini baştan hesaplar ve
+// her makbuzu transactions haritasındaki güncel işlem kaydıyla karşılaştırır
+func VerifyReceiptChain(userID uuid.UUID, receipts []*TransactionReceipt, transactions map[uuid.UUID]*Transaction) *ReceiptChainVerification {
+	result := &ReceiptChainVerification{
+		UserID:     userID,
+		Receipts:   len(receipts),
+		LastHash:   ReceiptGenesisHash,
+		Breaks:     []ReceiptChainBreak{},
+		VerifiedAt: time.Now(),
+	}
+
+	previousHash := ReceiptGenesisHash
+	var expectedSequence int64 = 1
+	for _, receipt := range receipts {
+		addBreak := func(reason string) {
+			result.Breaks = append(result.Breaks, ReceiptChainBreak{
+				Sequence:      receipt.Sequence,
+				TransactionID: receipt.TransactionID,
+				Reason:        reason,
+			})
+		}
+
+		if receipt.Sequence != expectedSequence {
+			addBreak(ReceiptBreakSequenceGap)
+		}
+		if receipt.PreviousHash != previousHash {
+			addBreak(ReceiptBreakPreviousHash)
+		}
+		if receipt.ComputeHash() != receipt.Hash {
+			addBreak(ReceiptBreakHashMismatch)
+		}
+
+		if transaction, ok := transactions[receipt.TransactionID]; !ok {
+			result.MissingTransactions++
+		} else if !receipt.Matches(transaction) {
+			addBreak(ReceiptBreakTransactionModified)
+		}
+
+		previousHash = receipt.Hash
+		expectedSequence = receipt.Sequence + 1
+	}
+
+	result.LastHash = previousHash
+	result.Valid = len(result.Breaks) == 0
+	return result
+}
@@ -0,0 +1,117 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// newTestReceiptChain kullanıcı için verilen tutarlarda alacak işlemleri ve zincirli makbuzlarını üretir
+func newTestReceiptChain(userID uuid.UUID, amounts ...float64) ([]*TransactionReceipt, map[uuid.UUID]*Transaction) {
+	receipts := make([]*TransactionReceipt, 0, len(amounts))
+	transactions := make(map[uuid.UUID]*Transaction, len(amounts))
+
+	var previous *TransactionReceipt
+	var balance float64
+	for _, amount := range amounts {
+		balance += amount
+		transaction := &Transaction{ID: uuid.New(), UserID: userID, Type: TransactionTypeCredit, Amount: amount, BalanceAfter: balance}
+		receipt := NewTransactionReceipt(transaction, previous)
+		receipts = append(receipts, receipt)
+		transactions[transaction.ID] = transaction
+		previous = receipt
+	}
+	return receipts, transactions
+}
+
+func TestVerifyReceiptChain(t *testing.T) {
+	tests := []struct {
+		name        string
+		tamper      func(receipts []*TransactionReceipt, transactions map[uuid.UUID]*Transaction) []*TransactionReceipt
+		wantValid   bool
+		wantBreaks  map[int64]string
+		wantMissing int
+	}{
+		{
+			name: "değiştirilmemiş zincir geçerli",
+			tamper: func(receipts []*TransactionReceipt, transactions map[uuid.UUID]*Transaction) []*TransactionReceipt {
+				return receipts
+			},
+			wantValid: true,
+		},
+		{
+			name: "işlem tutarı değiştirilirse zincir bozulur",
+			tamper: func(receipts []*TransactionReceipt, transactions map[uuid.UUID]*Transaction) []*TransactionReceipt {
+				transactions[receipts[1].TransactionID].Amount = 999
+				return receipts
+			},
+			wantBreaks: map[int64]string{2: ReceiptBreakTransactionModified},
+		},
+		{
+			name: "makbuz tutarı değiştirilirse hash tutmaz",
+			tamper: func(receipts []*TransactionReceipt, transactions map[uuid.UUID]*Transaction) []*TransactionReceipt {
+				receipts[0].Amount = 999
+				transactions[receipts[0].TransactionID].Amount = 999
+				return receipts
+			},
+			wantBreaks: map[int64]string{1: ReceiptBreakHashMismatch},
+		},
+		{
+			name: "yeniden hash'lenen makbuz sonraki halkayı bozar",
+			tamper: func(receipts []*TransactionReceipt, transactions map[uuid.UUID]*Transaction) []*TransactionReceipt {
+				receipts[0].Amount = 999
+				receipts[0].Hash = receipts[0].ComputeHash()
+				transactions[receipts[0].TransactionID].Amount = 999
+				return receipts
+			},
+			wantBreaks: map[int64]string{2: ReceiptBreakPreviousHash},
+		},
+		{
+			name: "silinen makbuz sıra boşluğu bırakır",
+			tamper: func(receipts []*TransactionReceipt, transactions map[uuid.UUID]*Transaction) []*TransactionReceipt {
+				return append(receipts[:1], receipts[2:]...)
+			},
+			wantBreaks: map[int64]string{3: ReceiptBreakSequenceGap},
+		},
+		{
+			name: "arşivlenen işlem zinciri bozmaz",
+			tamper: func(receipts []*TransactionReceipt, transactions map[uuid.UUID]*Transaction) []*TransactionReceipt {
+				delete(transactions, receipts[0].TransactionID)
+				return receipts
+			},
+			wantValid:   true,
+			wantMissing: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userID := uuid.New()
+			receipts, transactions := newTestReceiptChain(userID, 100, 50, 25)
+			receipts = tt.tamper(receipts, transactions)
+
+			result := VerifyReceiptChain(userID, receipts, transactions)
+
+			if result.Valid != tt.wantValid {
+				t.Errorf("Valid = %v, beklenen %v (%+v)", result.Valid, tt.wantValid, result.Breaks)
+			}
+			if result.MissingTransactions != tt.wantMissing {
+				t.Errorf("MissingTransactions = %d, beklenen %d", result.MissingTransactions, tt.wantMissing)
+			}
+			for sequence, reason := range tt.wantBreaks {
+				found := false
+				for _, b := range result.Breaks {
+					if b.Sequence == sequence && b.Reason == reason {
+						found = true
+					}
+				}
+				if !found {
+					t.Errorf("%d. makbuzda %s bekleniyordu: %+v", sequence, reason, result.Breaks)
+				}
+			}
+			if result.LastHash != receipts[len(receipts)-1].Hash {
+				t.Errorf("LastHash = %s, beklenen son makbuzun hash'i", result.LastHash)
+			}
+		})
+	}
+}
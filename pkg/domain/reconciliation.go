@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultReconciliationTolerance bakiye ile işlem geçmişi arasındaki kabul edilebilir fark (decimal(19,4) hassasiyeti)
+const DefaultReconciliationTolerance = 0.0001
+
+// BalanceDiscrepancy saklanan bakiye ile işlem geçmişinden hesaplanan bakiye arasındaki farkı tutar
+type BalanceDiscrepancy struct {
+	UserID     uuid.UUID `json:"user_id"`
+	Expected   float64   `json:"expected"`
+	Actual     float64   `json:"actual"`
+	Difference float64   `json:"difference"`
+}
+
+type BalanceDiscrepancies []BalanceDiscrepancy
+
+func (d BalanceDiscrepancies) Value() (driver.Value, error) {
+	if d == nil {
+		return "[]", nil
+	}
+	return json.Marshal(d)
+}
+
+func (d *BalanceDiscrepancies) Scan(value interface{}) error {
+	if value == nil {
+		*d = nil
+		return nil
+	}
+
+	var data []byte
+	switch v := value.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("unsupported discrepancies type %T", value)
+	}
+	return json.Unmarshal(data, d)
+}
+
+// ReconciliationReport günlük mutabakat çalışmasının sonucudur
+type ReconciliationReport struct {
+	ID               uuid.UUID            `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	RunAt            time.Time            `json:"run_at" gorm:"not null;index"`
+	UsersChecked     int64                `json:"users_checked" gorm:"not null"`
+	DiscrepancyCount int                  `json:"discrepancy_count" gorm:"not null"`
+	Discrepancies    BalanceDiscrepancies `json:"discrepancies" gorm:"type:json"`
+	Duration         string               `json:"duration" gorm:"type:varchar(50)"`
+	CreatedAt        time.Time            `json:"created_at" gorm:"not null"`
+}
+
+func (r *ReconciliationReport) HasDiscrepancies() bool {
+	return r.DiscrepancyCount > 0
+}
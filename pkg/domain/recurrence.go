@@ -0,0 +1,180 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// DefaultTimezone zamanlanmış işlemler için timezone belirtilmediğinde kullanılır
+const DefaultTimezone = "UTC"
+
+const (
+	RecurringDaily   = "daily"
+	RecurringWeekly  = "weekly"
+	RecurringMonthly = "monthly"
+	RecurringYearly  = "yearly"
+)
+
+// LoadTimezone IANA timezone adını doğrular ve konumunu döndürür
+func LoadTimezone(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, ErrInvalidTimezone
+	}
+	return loc, nil
+}
+
+// Location zamanlanmış işlemin timezone'unu döndürür, geçersizse UTC kullanılır
+func (st *ScheduledTransaction) Location() *time.Location {
+	loc, err := LoadTimezone(st.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// IsRecurring işlemin tekrarlayan bir seriye ait olup olmadığını döndürür
+func (st *ScheduledTransaction) IsRecurring() bool {
+	return st.RecurringType != nil && *st.RecurringType != ""
+}
+
+// ParseRecurringConfig kayıtlı JSON tekrar yapılandırmasını çözer
+func (st *ScheduledTransaction) ParseRecurringConfig() (*RecurringConfig, error) {
+	config := &RecurringConfig{Interval: 1}
+	if st.RecurringConfig != nil && *st.RecurringConfig != "" {
+		if err := json.Unmarshal([]byte(*st.RecurringConfig), config); err != nil {
+			return nil, ErrInvalidRecurringConfig
+		}
+	}
+
+	if st.RecurringType != nil && *st.RecurringType != "" {
+		config.Type = *st.RecurringType
+	}
+	if config.Interval <= 0 {
+		config.Interval = 1
+	}
+	return config, nil
+}
+
+// NextOccurrence serinin bir sonraki çalışma zamanını işlemin timezone'unda hesaplar
+// ve UTC olarak döndürür. Seri bittiyse ikinci değer false olur.
+func (st *ScheduledTransaction) NextOccurrence() (time.Time, bool, error) {
+	if !st.IsRecurring() {
+		return time.Time{}, false, nil
+	}
+
+	config, err := st.ParseRecurringConfig()
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	if config.MaxOccurrences != nil && st.OccurrenceCount+1 >= *config.MaxOccurrences {
+		return time.Time{}, false, nil
+	}
+
+	next, err := config.next(st.ScheduledAt.In(st.Location()))
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	if config.EndDate != nil && next.After(*config.EndDate) {
+		return time.Time{}, false, nil
+	}
+	return next.UTC(), true, nil
+}
+
+// next yerel saat üzerinden ilerler; böylece DST geçişlerinde duvar saati korunur
+func (c *RecurringConfig) next(current time.Time) (time.Time, error) {
+	switch c.Type {
+	case RecurringDaily:
+		return current.AddDate(0, 0, c.Interval), nil
+	case RecurringWeekly:
+		next := current.AddDate(0, 0, 7*c.Interval)
+		if c.DayOfWeek != nil {
+			if *c.DayOfWeek < 0 || *c.DayOfWeek > 6 {
+				return time.Time{}, ErrInvalidRecurringConfig
+			}
+			offset := (*c.DayOfWeek - int(next.Weekday()) + 7) % 7
+			next = next.AddDate(0, 0, offset)
+		}
+		return next, nil
+	case RecurringMonthly:
+		day := current.Day()
+		if c.DayOfMonth != nil {
+			if *c.DayOfMonth < 1 || *c.DayOfMonth > 31 {
+				return time.Time{}, ErrInvalidRecurringConfig
+			}
+			day = *c.DayOfMonth
+		}
+		return addMonthsClamped(current, c.Interval, day), nil
+	case RecurringYearly:
+		return addMonthsClamped(current, 12*c.Interval, current.Day()), nil
+	default:
+		return time.Time{}, ErrInvalidRecurringConfig
+	}
+}
+
+// addMonthsClamped ay sonunu aşan günleri ayın son gününe sabitler (ör. 31 -> 30 Nisan)
+func addMonthsClamped(current time.Time, months, day int) time.Time {
+	firstOfMonth := time.Date(current.Year(), current.Month()+time.Month(months), 1,
+		current.Hour(), current.Minute(), current.Second(), current.Nanosecond(), current.Location())
+	lastDay := firstOfMonth.AddDate(0, 1, -1).Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return firstOfMonth.AddDate(0, 0, day-1)
+}
+
+// Pause bekleyen bir zamanlanmış işlemi duraklatır; duraklatılan işlemler çalıştırılmaz
+func (st *ScheduledTransaction) Pause() error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	if st.Status != "pending" {
+		return ErrInvalidScheduledStatus
+	}
+
+	st.Status = "paused"
+	st.UpdatedAt = time.Now()
+	return nil
+}
+
+// Resume duraklatılmış işlemi yeniden bekleyen duruma alır. Tekrarlayan serilerde
+// duraklatma süresince kaçırılan çalışmalar atlanır, serinin çalışma sayısından düşülür ve
+// sıradaki gelecek zaman seçilir. Atlanan çalışmalarla seri biterse işlem tamamlanmış olur.
+func (st *ScheduledTransaction) Resume(now time.Time) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	if st.Status != "paused" {
+		return ErrInvalidScheduledStatus
+	}
+
+	if st.IsRecurring() {
+		for i := 0; i < maxSkippedOccurrences && st.ScheduledAt.Before(now); i++ {
+			next, ok, err := st.NextOccurrence()
+			if err != nil {
+				return err
+			}
+			// Kaçırılan çalışma da MaxOccurrences hakkından sayılır
+			st.OccurrenceCount++
+			if !ok {
+				st.Status = "completed"
+				st.UpdatedAt = now
+				return nil
+			}
+			st.ScheduledAt = next
+		}
+	}
+
+	st.Status = "pending"
+	st.UpdatedAt = now
+	return nil
+}
+
+// maxSkippedOccurrences resume sırasında atlanabilecek en fazla çalışma sayısı
+const maxSkippedOccurrences = 10000
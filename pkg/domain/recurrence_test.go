@@ -0,0 +1,175 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func newRecurringSchedule(t *testing.T, recurring, config string, scheduledAt time.Time) *ScheduledTransaction {
+	t.Helper()
+	return &ScheduledTransaction{
+		Status:          "paused",
+		ScheduledAt:     scheduledAt,
+		Timezone:        DefaultTimezone,
+		RecurringType:   &recurring,
+		RecurringConfig: &config,
+	}
+}
+
+func TestScheduledTransactionResume(t *testing.T) {
+	start := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name            string
+		config          string
+		occurrenceCount int
+		now             time.Time
+		wantStatus      string
+		wantScheduledAt time.Time
+		wantOccurrences int
+	}{
+		{
+			name:            "gelecekteki çalışma değişmez",
+			config:          `{"interval":1}`,
+			now:             start.Add(-time.Hour),
+			wantStatus:      "pending",
+			wantScheduledAt: start,
+			wantOccurrences: 0,
+		},
+		{
+			name:            "kaçırılan çalışmalar atlanır ve sayılır",
+			config:          `{"interval":1}`,
+			now:             start.AddDate(0, 0, 3).Add(time.Hour),
+			wantStatus:      "pending",
+			wantScheduledAt: start.AddDate(0, 0, 4),
+			wantOccurrences: 4,
+		},
+		{
+			name:            "atlanan çalışmalar MaxOccurrences'ı aşmaz",
+			config:          `{"interval":1,"max_occurrences":3}`,
+			now:             start.AddDate(0, 0, 10),
+			wantStatus:      "completed",
+			wantScheduledAt: start.AddDate(0, 0, 2),
+			wantOccurrences: 3,
+		},
+		{
+			name:            "önceki çalışmalar da hesaba katılır",
+			config:          `{"interval":1,"max_occurrences":5}`,
+			occurrenceCount: 3,
+			now:             start.AddDate(0, 0, 1).Add(time.Hour),
+			wantStatus:      "completed",
+			wantScheduledAt: start.AddDate(0, 0, 1),
+			wantOccurrences: 5,
+		},
+		{
+			name:            "bitiş tarihi geçtiyse seri tamamlanır",
+			config:          `{"interval":1,"end_date":"2026-03-02T09:00:00Z"}`,
+			now:             start.AddDate(0, 0, 5),
+			wantStatus:      "completed",
+			wantScheduledAt: start.AddDate(0, 0, 1),
+			wantOccurrences: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := newRecurringSchedule(t, RecurringDaily, tt.config, start)
+			st.OccurrenceCount = tt.occurrenceCount
+
+			if err := st.Resume(tt.now); err != nil {
+				t.Fatalf("Resume: %v", err)
+			}
+			if st.Status != tt.wantStatus {
+				t.Errorf("Status = %q, beklenen %q", st.Status, tt.wantStatus)
+			}
+			if !st.ScheduledAt.Equal(tt.wantScheduledAt) {
+				t.Errorf("ScheduledAt = %v, beklenen %v", st.ScheduledAt, tt.wantScheduledAt)
+			}
+			if st.OccurrenceCount != tt.wantOccurrences {
+				t.Errorf("OccurrenceCount = %d, beklenen %d", st.OccurrenceCount, tt.wantOccurrences)
+			}
+		})
+	}
+}
+
+func TestScheduledTransactionResumeRequiresPaused(t *testing.T) {
+	st := newRecurringSchedule(t, RecurringDaily, `{"interval":1}`, time.Now())
+	st.Status = "pending"
+
+	if err := st.Resume(time.Now()); err != ErrInvalidScheduledStatus {
+		t.Fatalf("Resume = %v, beklenen ErrInvalidScheduledStatus", err)
+	}
+}
+
+func TestScheduledTransactionNextOccurrenceAcrossDST(t *testing.T) {
+	tests := []struct {
+		name      string
+		timezone  string
+		recurring string
+		config    string
+		local     [5]int // yıl, ay, gün, saat, dakika (işlemin timezone'unda)
+		wantLocal [5]int
+	}{
+		{
+			name: "haftalık, Berlin yaz saatine geçiş", timezone: "Europe/Berlin",
+			recurring: RecurringWeekly, config: `{"interval":1}`,
+			local: [5]int{2026, 3, 23, 9, 0}, wantLocal: [5]int{2026, 3, 30, 9, 0},
+		},
+		{
+			name: "haftalık, Berlin kış saatine geçiş", timezone: "Europe/Berlin",
+			recurring: RecurringWeekly, config: `{"interval":1}`,
+			local: [5]int{2026, 10, 19, 9, 0}, wantLocal: [5]int{2026, 10, 26, 9, 0},
+		},
+		{
+			name: "haftalık, New York yaz saatine geçiş", timezone: "America/New_York",
+			recurring: RecurringWeekly, config: `{"interval":1}`,
+			local: [5]int{2026, 3, 2, 9, 0}, wantLocal: [5]int{2026, 3, 9, 9, 0},
+		},
+		{
+			name: "haftalık gün seçimi geçişi aşar", timezone: "Europe/Berlin",
+			recurring: RecurringWeekly, config: `{"interval":1,"day_of_week":1}`,
+			local: [5]int{2026, 3, 26, 9, 0}, wantLocal: [5]int{2026, 4, 6, 9, 0},
+		},
+		{
+			name: "günlük, geçiş gecesi", timezone: "Europe/Berlin",
+			recurring: RecurringDaily, config: `{"interval":1}`,
+			local: [5]int{2026, 3, 28, 9, 0}, wantLocal: [5]int{2026, 3, 29, 9, 0},
+		},
+		{
+			name: "aylık, geçişi içeren ay", timezone: "Europe/Berlin",
+			recurring: RecurringMonthly, config: `{"interval":1}`,
+			local: [5]int{2026, 3, 15, 9, 0}, wantLocal: [5]int{2026, 4, 15, 9, 0},
+		},
+		{
+			name: "UTC'de saat kaymaz", timezone: DefaultTimezone,
+			recurring: RecurringWeekly, config: `{"interval":1}`,
+			local: [5]int{2026, 3, 23, 9, 0}, wantLocal: [5]int{2026, 3, 30, 9, 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loc, err := LoadTimezone(tt.timezone)
+			if err != nil {
+				t.Skipf("timezone verisi yok: %v", err)
+			}
+			at := func(v [5]int) time.Time {
+				return time.Date(v[0], time.Month(v[1]), v[2], v[3], v[4], 0, 0, loc)
+			}
+
+			st := newRecurringSchedule(t, tt.recurring, tt.config, at(tt.local).UTC())
+			st.Timezone = tt.timezone
+
+			next, ok, err := st.NextOccurrence()
+			if err != nil || !ok {
+				t.Fatalf("NextOccurrence = %v, %v, %v", next, ok, err)
+			}
+			if next.Location() != time.UTC {
+				t.Errorf("sonuç UTC olmalı, %v", next.Location())
+			}
+			if want := at(tt.wantLocal); !next.Equal(want) {
+				t.Errorf("NextOccurrence = %v, beklenen %v (%v)", next.In(loc), want, want.UTC())
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package domain
+
+import "time"
+
+type RetentionMode string
+
+const (
+	// RetentionModeArchive eski kayıtları arşiv tablolarına taşır
+	RetentionModeArchive RetentionMode = "archive"
+	// RetentionModeDelete eski kayıtları kalıcı olarak siler
+	RetentionModeDelete RetentionMode = "delete"
+)
+
+// RetentionPolicy işlem geçmişi ve event store için saklama kurallarını tanımlar
+type RetentionPolicy struct {
+	TransactionRetention time.Duration `json:"transaction_retention"`
+	EventRetention       time.Duration `json:"event_retention"`
+	BatchSize            int           `json:"batch_size"`
+	Interval             time.Duration `json:"interval"`
+	Mode                 RetentionMode `json:"mode"`
+}
+
+func DefaultRetentionPolicy() RetentionPolicy {
+	return RetentionPolicy{
+		TransactionRetention: 365 * 24 * time.Hour,
+		EventRetention:       365 * 24 * time.Hour,
+		BatchSize:            500,
+		Interval:             24 * time.Hour,
+		Mode:                 RetentionModeArchive,
+	}
+}
+
+// TerminalTransactionStates saklama süresi dolduğunda arşivlenebilecek işlem durumları
+var TerminalTransactionStates = []TransactionState{
+	TransactionStateCompleted,
+	TransactionStateFailed,
+	TransactionStateCancelled,
+}
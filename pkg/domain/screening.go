@@ -0,0 +1,32 @@
+package domain
+
+import "context"
+
+// ScreeningDecision dış dolandırıcılık servisinin işlem hakkındaki kararı
+type ScreeningDecision string
+
+const (
+	ScreeningApprove ScreeningDecision = "approve"
+	ScreeningDeny    ScreeningDecision = "deny"
+	ScreeningHold    ScreeningDecision = "hold"
+)
+
+func (d ScreeningDecision) IsValid() bool {
+	switch d {
+	case ScreeningApprove, ScreeningDeny, ScreeningHold:
+		return true
+	}
+	return false
+}
+
+// ScreeningResult tarama sonucunu ve servis bir neden döndürdüyse onu taşır
+type ScreeningResult struct {
+	Decision ScreeningDecision `json:"decision"`
+	Reason   string            `json:"reason,omitempty"`
+}
+
+// TransactionScreener işlem tamamlanmadan önce dış bir servise onay sorar. Servise
+// ulaşılamazsa error döner; fail-open/fail-closed kararı çağırana aittir.
+type TransactionScreener interface {
+	Screen(ctx context.Context, transaction *Transaction) (*ScreeningResult, error)
+}
@@ -1,11 +1,14 @@
 package domain
 
 import (
+	"database/sql/driver"
 	"encoding/json"
+	"fmt"
 	"sync"
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type TransactionState string
@@ -15,6 +18,12 @@ const (
 	TransactionStateCompleted TransactionState = "completed"
 	TransactionStateFailed    TransactionState = "failed"
 	TransactionStateCancelled TransactionState = "cancelled"
+	// TransactionStateHeld dolandırıcılık taramasında incelemeye alınmış işlemdir; bakiyeler
+	// inceleme sonuçlanana kadar değişmez ama borç tutarı kullanılabilir bakiyeden düşülür
+	TransactionStateHeld TransactionState = "held"
+	// TransactionStatePendingSettlement dış hesaba giden transferin ödeme ağ geçidinden sonuç
+	// beklediği durumdur; tutar kullanılabilir bakiyeden düşülür ama bakiye henüz değişmez
+	TransactionStatePendingSettlement TransactionState = "pending_settlement"
 )
 
 type TransactionType string
@@ -23,31 +32,217 @@ const (
 	TransactionTypeCredit   TransactionType = "CREDIT"
 	TransactionTypeDebit    TransactionType = "DEBIT"
 	TransactionTypeTransfer TransactionType = "TRANSFER"
+	// TransactionTypeFee bir işlem için alınan ücreti ücret hesabına aktaran kayıttır
+	TransactionTypeFee TransactionType = "FEE"
 )
 
 type Transaction struct {
-	ID           uuid.UUID       `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
-	UserID       uuid.UUID       `json:"user_id" gorm:"type:uuid;not null"`
-	Type         TransactionType `json:"type" gorm:"type:varchar(20);not null"`
-	Amount       float64         `json:"amount" gorm:"type:decimal(19,4);not null"`
-	Description  string          `json:"description" gorm:"type:text"`
-	ReferenceID  string          `json:"reference_id" gorm:"type:varchar(100)"`
-	BalanceAfter float64         `json:"balance_after" gorm:"type:decimal(19,4);not null"`
-	Status       string          `json:"status" gorm:"type:varchar(20);not null;default:'pending'"`
-	CreatedAt    time.Time       `json:"created_at" gorm:"not null"`
-	UpdatedAt    time.Time       `json:"updated_at" gorm:"not null"`
-	mu           sync.Mutex      `json:"-"`
+	ID             uuid.UUID       `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	UserID         uuid.UUID       `json:"user_id" gorm:"type:uuid;not null"`
+	Type           TransactionType `json:"type" gorm:"type:varchar(20);not null"`
+	Amount         float64         `json:"amount" gorm:"type:decimal(19,4);not null"`
+	Description    string          `json:"description" gorm:"type:text"`
+	ReferenceID    string          `json:"reference_id" gorm:"type:varchar(100)"`
+	CounterpartyID *uuid.UUID      `json:"counterparty_id,omitempty" gorm:"type:uuid;index"`
+	Category       string          `json:"category,omitempty" gorm:"type:varchar(50);index"`
+	Tags           Tags            `json:"tags,omitempty" gorm:"type:json"`
+	Metadata       Metadata        `json:"metadata,omitempty" gorm:"type:json"`
+	BalanceAfter   float64         `json:"balance_after" gorm:"type:decimal(19,4);not null"`
+	Status         string          `json:"status" gorm:"type:varchar(20);not null;default:'pending'"`
+	// StatusReason işlem reddedildiğinde veya incelemeye alındığında nedenini taşır
+	StatusReason string `json:"status_reason,omitempty" gorm:"type:varchar(255)"`
+	// ExternalAccount dış hesaba giden transferlerde hedef hesaptır; GatewayReference ödeme ağ geçidinin
+	// transfere verdiği kimliktir
+	ExternalAccount  *ExternalAccount `json:"external_account,omitempty" gorm:"type:json"`
+	GatewayReference string           `json:"gateway_reference,omitempty" gorm:"type:varchar(100);index"`
+	// ReferenceUnique işlem UNIQUE_REFERENCE_IDS açıkken oluşturulduğunda işaretlenir; veritabanındaki
+	// (user_id, reference_id) tekillik indeksi yalnızca işaretli satırları kapsar
+	ReferenceUnique bool `json:"-" gorm:"not null;default:false"`
+	// Disputed açık bir itiraz olduğunu gösterir; bu işlemler otomatik işlemlerden (ör. retention) hariç tutulur
+	Disputed  bool      `json:"disputed" gorm:"not null;default:false;index"`
+	CreatedAt time.Time `json:"created_at" gorm:"not null"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"not null"`
+	// DeletedAt soft-delete zamanıdır; silinen işlemler normal sorgulardan çıkar ancak denetim için saklanır
+	DeletedAt gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
+	mu        sync.Mutex     `json:"-"`
+	// Receipt işlem tamamlandığında üretilen makbuzdur; ayrı tabloda saklanır
+	Receipt *TransactionReceipt `json:"receipt,omitempty" gorm:"-"`
 }
 
 type TransactionRequest struct {
-	Amount      float64 `json:"amount" binding:"required,gt=0"`
-	Description string  `json:"description"`
+	Amount      float64  `json:"amount" binding:"required,gt=0"`
+	Description string   `json:"description"`
+	ReferenceID string   `json:"reference_id" binding:"omitempty,max=100"`
+	Category    string   `json:"category" binding:"omitempty,max=50"`
+	Tags        []string `json:"tags" binding:"omitempty,max=10,dive,required,max=32"`
+	Metadata    Metadata `json:"metadata"`
+	// ExternalAccount verildiğinde alacak dış hesaptan çekilen bir yatırma, borç dış hesaba
+	// gönderilen bir çekim olarak ödeme ağ geçidi üzerinden işlenir
+	ExternalAccount *ExternalAccount `json:"external_account,omitempty"`
 }
 
 type TransferRequest struct {
 	Amount      float64   `json:"amount" binding:"required,gt=0"`
-	ToUserID    uuid.UUID `json:"to_user_id" binding:"required"`
+	ToUserID    uuid.UUID `json:"to_user_id"`
 	Description string    `json:"description"`
+	ReferenceID string    `json:"reference_id" binding:"omitempty,max=100"`
+	Category    string    `json:"category" binding:"omitempty,max=50"`
+	Tags        []string  `json:"tags" binding:"omitempty,max=10,dive,required,max=32"`
+	Metadata    Metadata  `json:"metadata"`
+	// ExternalAccount verildiğinde transfer sistem dışı bir hesaba yapılır; ToUserID ile birlikte verilemez
+	ExternalAccount *ExternalAccount `json:"external_account,omitempty"`
+}
+
+// Validate transferin tam olarak bir hedefi olduğunu ve dış hesabın geçerli olduğunu doğrular
+func (r *TransferRequest) Validate() error {
+	hasInternal := r.ToUserID != uuid.Nil
+	hasExternal := r.ExternalAccount != nil
+	if hasInternal == hasExternal {
+		return ErrInvalidTransferDestination
+	}
+	if hasExternal {
+		return r.ExternalAccount.Validate()
+	}
+	return nil
+}
+
+// IsExternal transfer dış hesaba yapılıyorsa true döner
+func (r *TransferRequest) IsExternal() bool {
+	return r.ExternalAccount != nil
+}
+
+type SortOrder string
+
+const (
+	SortOrderAsc  SortOrder = "asc"
+	SortOrderDesc SortOrder = "desc"
+)
+
+const (
+	DefaultSearchLimit = 50
+	MaxSearchLimit     = 200
+)
+
+// TransactionFilter işlem geçmişi ve arama sorgularında kullanılan filtreler
+type TransactionFilter struct {
+	Category       string
+	Tag            string
+	ReferenceID    string
+	CounterpartyID string
+	MinAmount      *float64
+	MaxAmount      *float64
+	StartDate      *time.Time
+	EndDate        *time.Time
+	SortOrder      SortOrder
+	Cursor         string
+	Limit          int
+}
+
+// TransactionSearchResult cursor tabanlı sayfalanmış arama sonucu
+type TransactionSearchResult struct {
+	Transactions []*Transaction `json:"transactions"`
+	NextCursor   string         `json:"next_cursor,omitempty"`
+	// Limit uygulanan sayfa boyutu, Total cursor'dan bağımsız olarak filtreye uyan kayıt sayısıdır
+	Limit int   `json:"limit"`
+	Total int64 `json:"total"`
+}
+
+// Tags işleme ait etiketleri JSON dizisi olarak saklar
+type Tags []string
+
+func (t Tags) Value() (driver.Value, error) {
+	if len(t) == 0 {
+		return nil, nil
+	}
+	return json.Marshal([]string(t))
+}
+
+func (t *Tags) Scan(value interface{}) error {
+	if value == nil {
+		*t = nil
+		return nil
+	}
+
+	var data []byte
+	switch v := value.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("unsupported type for tags: %T", value)
+	}
+
+	return json.Unmarshal(data, (*[]string)(t))
+}
+
+const (
+	// MaxMetadataSize metadata alanının JSON olarak alabileceği en büyük boyut (byte)
+	MaxMetadataSize = 4096
+	// MaxMetadataKeys metadata alanında izin verilen en fazla anahtar sayısı
+	MaxMetadataKeys = 50
+
+	// İndekslenen bilinen metadata anahtarları
+	MetadataKeyExternalOrderID = "external_order_id"
+	MetadataKeyDeviceID        = "device_id"
+)
+
+// Metadata entegratörlerin işleme eklediği serbest biçimli veriler
+type Metadata map[string]interface{}
+
+// Validate metadata boyutunu ve anahtar sayısını kontrol eder
+func (m Metadata) Validate() error {
+	if len(m) == 0 {
+		return nil
+	}
+	if len(m) > MaxMetadataKeys {
+		return ErrMetadataTooLarge
+	}
+
+	data, err := json.Marshal(map[string]interface{}(m))
+	if err != nil {
+		return ErrInvalidMetadata
+	}
+	if len(data) > MaxMetadataSize {
+		return ErrMetadataTooLarge
+	}
+	return nil
+}
+
+func (m Metadata) Value() (driver.Value, error) {
+	if len(m) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(map[string]interface{}(m))
+}
+
+func (m *Metadata) Scan(value interface{}) error {
+	if value == nil {
+		*m = nil
+		return nil
+	}
+
+	var data []byte
+	switch v := value.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("unsupported type for metadata: %T", value)
+	}
+
+	return json.Unmarshal(data, (*map[string]interface{})(m))
+}
+
+// HasTag işlemin verilen etikete sahip olup olmadığını döndürür
+func (t Tags) HasTag(tag string) bool {
+	for _, existing := range t {
+		if existing == tag {
+			return true
+		}
+	}
+	return false
 }
 
 func NewTransaction(userID uuid.UUID, amount float64, description string) (*Transaction, error) {
@@ -73,7 +268,16 @@ func (t *Transaction) UpdateState(newState TransactionState) error {
 
 	switch t.Status {
 	case "pending":
-		if newState != TransactionStateCompleted && newState != TransactionStateFailed && newState != TransactionStateCancelled {
+		if newState != TransactionStateCompleted && newState != TransactionStateFailed && newState != TransactionStateCancelled &&
+			newState != TransactionStatePendingSettlement {
+			return ErrInvalidState
+		}
+	case "held":
+		if newState != TransactionStateCompleted && newState != TransactionStateFailed && newState != TransactionStatePendingSettlement {
+			return ErrInvalidState
+		}
+	case "pending_settlement":
+		if newState != TransactionStateCompleted && newState != TransactionStateFailed {
 			return ErrInvalidState
 		}
 	case "completed":
@@ -89,6 +293,21 @@ func (t *Transaction) UpdateState(newState TransactionState) error {
 	return nil
 }
 
+// IsHeld işlem inceleme kuyruğunda bekliyorsa true döner
+func (t *Transaction) IsHeld() bool {
+	return t.Status == string(TransactionStateHeld)
+}
+
+// IsExternal işlem sistem dışı bir hesaba yapılan transferse true döner
+func (t *Transaction) IsExternal() bool {
+	return t.ExternalAccount != nil
+}
+
+// RejectHeldRequest incelemedeki işlemin reddedilme nedenini taşır
+type RejectHeldRequest struct {
+	Reason string `json:"reason" binding:"required,max=255"`
+}
+
 func (t *Transaction) MarshalJSON() ([]byte, error) {
 	t.mu.Lock()
 	defer t.mu.Unlock()
@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMetadataValidate(t *testing.T) {
+	manyKeys := Metadata{}
+	for i := 0; i <= MaxMetadataKeys; i++ {
+		manyKeys[strings.Repeat("k", i+1)] = i
+	}
+
+	tests := []struct {
+		name     string
+		metadata Metadata
+		wantErr  error
+	}{
+		{name: "boş metadata", metadata: nil},
+		{name: "bilinen anahtarlar", metadata: Metadata{MetadataKeyExternalOrderID: "ORD-1", MetadataKeyDeviceID: "ios-42"}},
+		{name: "boyut sınırında", metadata: Metadata{"note": strings.Repeat("a", MaxMetadataSize-len(`{"note":""}`))}},
+		{name: "boyut sınırını aşar", metadata: Metadata{"note": strings.Repeat("a", MaxMetadataSize)}, wantErr: ErrMetadataTooLarge},
+		{name: "anahtar sayısını aşar", metadata: manyKeys, wantErr: ErrMetadataTooLarge},
+		{name: "JSON'a çevrilemeyen değer", metadata: Metadata{"callback": func() {}}, wantErr: ErrInvalidMetadata},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.metadata.Validate(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate() = %v, beklenen %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMetadataValueScan(t *testing.T) {
+	tests := []struct {
+		name     string
+		metadata Metadata
+	}{
+		{name: "boş metadata", metadata: nil},
+		{name: "iç içe değerler", metadata: Metadata{
+			MetadataKeyExternalOrderID: "ORD-1",
+			"device":                   map[string]interface{}{"os": "ios", "version": "17.4"},
+			"attempts":                 float64(2),
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := tt.metadata.Value()
+			if err != nil {
+				t.Fatalf("Value: %v", err)
+			}
+
+			var got Metadata
+			if err := got.Scan(value); err != nil {
+				t.Fatalf("Scan: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.metadata) {
+				t.Errorf("metadata = %v, beklenen %v", got, tt.metadata)
+			}
+		})
+	}
+}
@@ -21,6 +21,7 @@ type User struct {
 	FirstName string    `json:"first_name" gorm:"not null"`
 	LastName  string    `json:"last_name" gorm:"not null"`
 	Role      Role      `json:"role" gorm:"type:varchar(20);not null;default:'user'"`
+	LimitTier LimitTier `json:"limit_tier" gorm:"type:varchar(20);not null;default:'basic'"`
 	CreatedAt time.Time `json:"created_at" gorm:"not null"`
 	UpdatedAt time.Time `json:"updated_at" gorm:"not null"`
 }
@@ -55,6 +56,7 @@ func NewUser(email, password, firstName, lastName string) (*User, error) {
 		FirstName: firstName,
 		LastName:  lastName,
 		Role:      RoleUser,
+		LimitTier: LimitTierBasic,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}, nil
@@ -0,0 +1,169 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type WebhookDeliveryStatus string
+
+const (
+	// WebhookDeliveryPending henüz hiç denenmemiş ya da elle yeniden gönderilmek üzere sıfırlanmış teslimattır
+	WebhookDeliveryPending WebhookDeliveryStatus = "pending"
+	// WebhookDeliveryRetrying en az bir kez başarısız olmuş ve NextAttemptAt'te yeniden denenecek teslimattır
+	WebhookDeliveryRetrying   WebhookDeliveryStatus = "retrying"
+	WebhookDeliveryDelivered  WebhookDeliveryStatus = "delivered"
+	WebhookDeliveryDeadLetter WebhookDeliveryStatus = "dead_letter"
+)
+
+const (
+	// DefaultWebhookMaxAttempts bir teslimatın dead-letter'a düşmeden önce denenme sayısı
+	DefaultWebhookMaxAttempts = 6
+	// WebhookRetryBaseBackoff ilk yeniden denemeden önce beklenen süre; her denemede iki katına çıkar
+	WebhookRetryBaseBackoff = 30 * time.Second
+	// WebhookRetryMaxBackoff yeniden denemeler arasındaki en uzun bekleme
+	WebhookRetryMaxBackoff = time.Hour
+
+	DefaultWebhookDeliveryLimit = 50
+	MaxWebhookDeliveryLimit     = 500
+)
+
+// WebhookDelivery bir olayın bir URL'e teslimatıdır. Payload gönderilen gövdenin kendisidir;
+// yeniden denemelerde aynı gövde ve aynı teslimat kimliği gönderilir ki alıcı tekrarları ayırt edebilsin.
+type WebhookDelivery struct {
+	ID             uuid.UUID             `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	Event          string                `json:"event" gorm:"type:varchar(100);not null;index"`
+	URL            string                `json:"url" gorm:"type:varchar(2048);not null"`
+	Payload        json.RawMessage       `json:"payload" gorm:"type:jsonb;not null"`
+	Status         WebhookDeliveryStatus `json:"status" gorm:"type:varchar(20);not null;index"`
+	Attempts       int                   `json:"attempts" gorm:"not null;default:0"`
+	MaxAttempts    int                   `json:"max_attempts" gorm:"not null"`
+	NextAttemptAt  *time.Time            `json:"next_attempt_at,omitempty" gorm:"index"`
+	LastStatusCode int                   `json:"last_status_code,omitempty"`
+	LastError      string                `json:"last_error,omitempty" gorm:"type:text"`
+	DeliveredAt    *time.Time            `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time             `json:"created_at" gorm:"not null"`
+	UpdatedAt      time.Time             `json:"updated_at" gorm:"not null"`
+
+	// AttemptLog teslimatın deneme geçmişidir; ayrı tabloda saklanır ve yalnızca tekil sorguda doldurulur
+	AttemptLog []WebhookDeliveryAttempt `json:"attempt_log,omitempty" gorm:"-"`
+}
+
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+// WebhookDeliveryAttempt tek bir gönderim denemesinin sonucudur
+type WebhookDeliveryAttempt struct {
+	ID         uuid.UUID `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	DeliveryID uuid.UUID `json:"delivery_id" gorm:"type:uuid;not null;index"`
+	Attempt    int       `json:"attempt" gorm:"not null"`
+	StatusCode int       `json:"status_code,omitempty"`
+	Error      string    `json:"error,omitempty" gorm:"type:text"`
+	DurationMS int64     `json:"duration_ms"`
+	CreatedAt  time.Time `json:"created_at" gorm:"not null"`
+}
+
+func (WebhookDeliveryAttempt) TableName() string {
+	return "webhook_delivery_attempts"
+}
+
+// WebhookDeliveryFilter teslimat listesini duruma göre süzer; boş Status tüm teslimatları döner
+type WebhookDeliveryFilter struct {
+	Status WebhookDeliveryStatus
+	Limit  int
+}
+
+// Normalize limiti izin verilen aralığa çeker
+func (f *WebhookDeliveryFilter) Normalize() {
+	if f.Limit <= 0 {
+		f.Limit = DefaultWebhookDeliveryLimit
+	}
+	if f.Limit > MaxWebhookDeliveryLimit {
+		f.Limit = MaxWebhookDeliveryLimit
+	}
+}
+
+func NewWebhookDelivery(url, event string, payload json.RawMessage, maxAttempts int, now time.Time) *WebhookDelivery {
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultWebhookMaxAttempts
+	}
+	return &WebhookDelivery{
+		ID:            uuid.New(),
+		Event:         event,
+		URL:           url,
+		Payload:       payload,
+		Status:        WebhookDeliveryPending,
+		MaxAttempts:   maxAttempts,
+		NextAttemptAt: &now,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+}
+
+// WebhookRetryBackoff attempts. başarısız denemeden sonra bir sonraki denemeye kadar beklenecek süreyi döner
+func WebhookRetryBackoff(attempts int) time.Duration {
+	backoff := WebhookRetryBaseBackoff
+	for i := 1; i < attempts && backoff < WebhookRetryMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > WebhookRetryMaxBackoff {
+		backoff = WebhookRetryMaxBackoff
+	}
+	return backoff
+}
+
+// RecordSuccess teslimatı başarılı olarak kapatır
+func (d *WebhookDelivery) RecordSuccess(statusCode int, now time.Time) {
+	d.Attempts++
+	d.Status = WebhookDeliveryDelivered
+	d.LastStatusCode = statusCode
+	d.LastError = ""
+	d.NextAttemptAt = nil
+	d.DeliveredAt = &now
+	d.UpdatedAt = now
+}
+
+// RecordFailure başarısız denemeyi kaydeder. Deneme hakkı kaldıysa teslimat backoff kadar sonra
+// yeniden denenmek üzere retrying durumuna alınır; haklar tükendiyse dead-letter'a düşer.
+// Teslimat yeniden denenecekse true döner.
+func (d *WebhookDelivery) RecordFailure(statusCode int, reason string, now time.Time) bool {
+	d.Attempts++
+	d.LastStatusCode = statusCode
+	d.LastError = reason
+	d.UpdatedAt = now
+
+	if d.Attempts >= d.MaxAttempts {
+		d.Status = WebhookDeliveryDeadLetter
+		d.NextAttemptAt = nil
+		return false
+	}
+
+	next := now.Add(WebhookRetryBackoff(d.Attempts))
+	d.Status = WebhookDeliveryRetrying
+	d.NextAttemptAt = &next
+	return true
+}
+
+// Defer denemeyi saymadan teslimatı ileri bir zamana erteler; alıcı hiç denenemediğinde
+// (ör. circuit breaker açıkken) deneme hakkı harcanmaz
+func (d *WebhookDelivery) Defer(delay time.Duration, reason string, now time.Time) {
+	next := now.Add(delay)
+	d.LastError = reason
+	d.NextAttemptAt = &next
+	d.UpdatedAt = now
+}
+
+// Redeliver dead-letter'daki teslimatı yeni bir deneme hakkı setiyle hemen gönderilmek üzere sıfırlar
+func (d *WebhookDelivery) Redeliver(now time.Time) error {
+	if d.Status != WebhookDeliveryDeadLetter {
+		return ErrWebhookNotRedeliverable
+	}
+	d.Status = WebhookDeliveryPending
+	d.Attempts = 0
+	d.NextAttemptAt = &now
+	d.UpdatedAt = now
+	return nil
+}
@@ -0,0 +1,107 @@
+package fallback
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+var (
+	ErrDegradationNotFound      = errors.New("degradation provider not found")
+	ErrDegradationAlreadyExists = errors.New("degradation provider already registered")
+)
+
+const (
+	DegradationEmptyList   = "empty_list"
+	DegradationEmptyObject = "empty_object"
+)
+
+// DegradedProvider primary başarısız olduğunda döndürülecek azaltılmış yanıtı üretir
+type DegradedProvider func(ctx context.Context, key string) (interface{}, error)
+
+// DegradationRegistry isimlendirilmiş, tekrar kullanılabilir degraded provider'ları tutar
+type DegradationRegistry struct {
+	providers map[string]DegradedProvider
+	mu        sync.RWMutex
+}
+
+func NewDegradationRegistry() *DegradationRegistry {
+	registry := &DegradationRegistry{
+		providers: make(map[string]DegradedProvider),
+	}
+
+	registry.providers[DegradationEmptyList] = func(ctx context.Context, key string) (interface{}, error) {
+		return []interface{}{}, nil
+	}
+	registry.providers[DegradationEmptyObject] = func(ctx context.Context, key string) (interface{}, error) {
+		return map[string]interface{}{}, nil
+	}
+
+	return registry
+}
+
+func (r *DegradationRegistry) Register(name string, provider DegradedProvider) error {
+	if name == "" || provider == nil {
+		return fmt.Errorf("degradation provider name and function are required")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.providers[name]; exists {
+		return fmt.Errorf("%w: %s", ErrDegradationAlreadyExists, name)
+	}
+
+	r.providers[name] = provider
+	return nil
+}
+
+func (r *DegradationRegistry) Unregister(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.providers, name)
+}
+
+func (r *DegradationRegistry) Get(name string) (DegradedProvider, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	provider, exists := r.providers[name]
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrDegradationNotFound, name)
+	}
+	return provider, nil
+}
+
+func (r *DegradationRegistry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.providers))
+	for name := range r.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Degradations manager'a ait degraded provider registry'sini döndürür
+func (fm *FallbackManager) Degradations() *DegradationRegistry {
+	return fm.degradations
+}
+
+// ExecuteWithDegradationKey primary başarısız olursa registry'de degradationName ile
+// kayıtlı provider'ı kullanır
+func (fm *FallbackManager) ExecuteWithDegradationKey(ctx context.Context, key string, primary func() (interface{}, error), degradationName string) (interface{}, error) {
+	provider, err := fm.degradations.Get(degradationName)
+	if err != nil {
+		return nil, err
+	}
+
+	return fm.ExecuteWithDegradation(ctx, key, primary, func() (interface{}, error) {
+		return provider(ctx, key)
+	})
+}
@@ -0,0 +1,96 @@
+package fallback
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestExecuteWithDegradationKey(t *testing.T) {
+	tests := []struct {
+		name        string
+		primaryErr  error
+		degradation string
+		want        interface{}
+		wantErr     error
+		wantKey     string
+	}{
+		{name: "primary başarılıysa provider çağrılmaz", degradation: "cached_balance", want: "primary"},
+		{name: "kayıtlı provider çağrılır", primaryErr: errPrimary, degradation: "cached_balance", want: "cached:balance:1", wantKey: "balance:1"},
+		{name: "hazır boş liste provider'ı", primaryErr: errPrimary, degradation: DegradationEmptyList, want: "[]"},
+		{name: "bilinmeyen provider", primaryErr: errPrimary, degradation: "missing", wantErr: ErrDegradationNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := NewFallbackManager(FallbackConfig{EnableDegradation: true}, NewSequentialFallbackStrategy(FallbackConfig{}))
+			defer manager.Close()
+
+			var gotKey string
+			err := manager.Degradations().Register("cached_balance", func(ctx context.Context, key string) (interface{}, error) {
+				gotKey = key
+				return "cached:" + key, nil
+			})
+			if err != nil {
+				t.Fatalf("Register: %v", err)
+			}
+
+			got, err := manager.ExecuteWithDegradationKey(context.Background(), "balance:1", func() (interface{}, error) {
+				if tt.primaryErr != nil {
+					return nil, tt.primaryErr
+				}
+				return "primary", nil
+			}, tt.degradation)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ExecuteWithDegradationKey = %v, beklenen %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("sonuç = %v, beklenen %v", got, tt.want)
+			}
+			if gotKey != tt.wantKey {
+				t.Errorf("provider'a verilen anahtar = %q, beklenen %q", gotKey, tt.wantKey)
+			}
+		})
+	}
+}
+
+func TestDegradationRegistryRegister(t *testing.T) {
+	provider := func(ctx context.Context, key string) (interface{}, error) { return nil, nil }
+
+	tests := []struct {
+		name     string
+		register string
+		provider DegradedProvider
+		wantErr  error
+		anyErr   bool
+	}{
+		{name: "yeni provider", register: "cached_balance", provider: provider},
+		{name: "aynı ad iki kez", register: DegradationEmptyList, provider: provider, wantErr: ErrDegradationAlreadyExists, anyErr: true},
+		{name: "boş ad", provider: provider, anyErr: true},
+		{name: "nil provider", register: "nil_provider", anyErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := NewDegradationRegistry()
+			err := registry.Register(tt.register, tt.provider)
+			if (err != nil) != tt.anyErr || (tt.wantErr != nil && !errors.Is(err, tt.wantErr)) {
+				t.Fatalf("Register = %v, beklenen %v", err, tt.wantErr)
+			}
+			if tt.anyErr {
+				return
+			}
+			if _, err := registry.Get(tt.register); err != nil {
+				t.Errorf("Get: %v", err)
+			}
+			registry.Unregister(tt.register)
+			if _, err := registry.Get(tt.register); !errors.Is(err, ErrDegradationNotFound) {
+				t.Errorf("Unregister sonrası Get = %v, beklenen ErrDegradationNotFound", err)
+			}
+		})
+	}
+}
@@ -24,9 +24,24 @@ type FallbackManager struct {
 	config   FallbackConfig
 	strategy FallbackStrategy
 	cache    *FallbackCache
-	mu       sync.RWMutex
-	ctx      context.Context
-	cancel   context.CancelFunc
+	keyStats map[string]*KeyStats
+	// degradations isimlendirilmiş degraded provider'lar
+	degradations *DegradationRegistry
+	mu           sync.RWMutex
+	ctx          context.Context
+	cancel       context.CancelFunc
+	// done cache temizleme goroutine'i sonlandığında kapanır
+	done chan struct{}
+}
+
+// KeyStats bir anahtar için primary/fallback/degradation sayaçlarını tutar
+type KeyStats struct {
+	PrimarySuccesses    uint64         `json:"primary_successes"`
+	FallbackActivations map[int]uint64 `json:"fallback_activations"`
+	Degradations        uint64         `json:"degradations"`
+	CacheHits           uint64         `json:"cache_hits"`
+	Failures            uint64         `json:"failures"`
+	LastFailureAt       *time.Time     `json:"last_failure_at,omitempty"`
 }
 
 type FallbackCache struct {
@@ -53,18 +68,28 @@ type DegradationFallbackStrategy struct {
 }
 
 func NewFallbackManager(config FallbackConfig, strategy FallbackStrategy) *FallbackManager {
-	ctx, cancel := context.WithCancel(context.Background())
+	return NewFallbackManagerWithContext(context.Background(), config, strategy)
+}
+
+// NewFallbackManagerWithContext cache temizleme goroutine'ini parent context'e bağlar
+func NewFallbackManagerWithContext(parent context.Context, config FallbackConfig, strategy FallbackStrategy) *FallbackManager {
+	ctx, cancel := context.WithCancel(parent)
 
 	fm := &FallbackManager{
-		config:   config,
-		strategy: strategy,
-		cache:    &FallbackCache{data: make(map[string]*CacheEntry)},
-		ctx:      ctx,
-		cancel:   cancel,
+		config:       config,
+		strategy:     strategy,
+		cache:        &FallbackCache{data: make(map[string]*CacheEntry)},
+		keyStats:     make(map[string]*KeyStats),
+		degradations: NewDegradationRegistry(),
+		ctx:          ctx,
+		cancel:       cancel,
+		done:         make(chan struct{}),
 	}
 
 	if config.EnableCaching {
 		go fm.startCacheCleanup()
+	} else {
+		close(fm.done)
 	}
 
 	return fm
@@ -73,29 +98,62 @@ func NewFallbackManager(config FallbackConfig, strategy FallbackStrategy) *Fallb
 func (fm *FallbackManager) Execute(ctx context.Context, key string, primary func() (interface{}, error), fallbacks ...func() (interface{}, error)) (interface{}, error) {
 	if fm.config.EnableCaching {
 		if cached, found := fm.cache.Get(key); found {
+			fm.record(key, func(stats *KeyStats) { stats.CacheHits++ })
 			return cached, nil
 		}
 	}
 
-	var result interface{}
-	var err error
+	var (
+		result   interface{}
+		resultMu sync.Mutex
+		resolved bool
+	)
+
+	// setResult yalnızca ilk başarılı sonucu saklar; paralel stratejide geç kalan
+	// fallback'lerin kazanan sonucu ezmesini engeller
+	setResult := func(value interface{}, err error) error {
+		if err != nil {
+			return err
+		}
+
+		resultMu.Lock()
+		defer resultMu.Unlock()
+		if !resolved {
+			result = value
+			resolved = true
+		}
+		return nil
+	}
 
 	primaryFn := func() error {
-		var primaryErr error
-		result, primaryErr = primary()
-		return primaryErr
+		if err := setResult(primary()); err != nil {
+			return err
+		}
+		fm.record(key, func(stats *KeyStats) { stats.PrimarySuccesses++ })
+		return nil
 	}
 
 	fallbackFns := make([]func() error, len(fallbacks))
-	for i, fallback := range fallbacks {
+	for i := range fallbacks {
+		// Her closure kendi fallback'ini çağırmalı; döngü değişkeni paylaşılmasın
+		fb := fallbacks[i]
+		index := i
 		fallbackFns[i] = func() error {
-			var fallbackErr error
-			result, fallbackErr = fallback()
-			return fallbackErr
+			if err := setResult(fb()); err != nil {
+				return err
+			}
+			fm.record(key, func(stats *KeyStats) { stats.FallbackActivations[index]++ })
+			return nil
 		}
 	}
 
-	err = fm.strategy.Execute(ctx, primaryFn, fallbackFns)
+	err := fm.strategy.Execute(ctx, primaryFn, fallbackFns)
+	if err != nil {
+		fm.recordFailure(key)
+	}
+
+	resultMu.Lock()
+	defer resultMu.Unlock()
 
 	if err == nil && fm.config.EnableCaching {
 		fm.cache.Set(key, result, fm.config.CacheTTL)
@@ -111,20 +169,65 @@ func (fm *FallbackManager) ExecuteWithDegradation(ctx context.Context, key strin
 
 	result, err := primary()
 	if err == nil {
+		fm.record(key, func(stats *KeyStats) { stats.PrimarySuccesses++ })
 		return result, nil
 	}
 
 	degradedResult, degradedErr := degraded()
 	if degradedErr != nil {
+		fm.recordFailure(key)
 		return nil, fmt.Errorf("both primary and degraded functions failed: primary: %v, degraded: %v", err, degradedErr)
 	}
 
+	fm.record(key, func(stats *KeyStats) { stats.Degradations++ })
+
 	fmt.Printf("Degradation activated for key: %s, primary error: %v\n", key, err)
 
 	return degradedResult, nil
 }
 
+func (fm *FallbackManager) record(key string, update func(stats *KeyStats)) {
+	fm.mu.Lock()
+	defer fm.mu.Unlock()
+
+	stats, exists := fm.keyStats[key]
+	if !exists {
+		stats = &KeyStats{FallbackActivations: make(map[int]uint64)}
+		fm.keyStats[key] = stats
+	}
+	update(stats)
+}
+
+func (fm *FallbackManager) recordFailure(key string) {
+	now := time.Now()
+	fm.record(key, func(stats *KeyStats) {
+		stats.Failures++
+		stats.LastFailureAt = &now
+	})
+}
+
+// GetKeyStats anahtar bazlı sayaçların bir kopyasını döndürür
+func (fm *FallbackManager) GetKeyStats() map[string]KeyStats {
+	fm.mu.RLock()
+	defer fm.mu.RUnlock()
+
+	result := make(map[string]KeyStats, len(fm.keyStats))
+	for key, stats := range fm.keyStats {
+		activations := make(map[int]uint64, len(stats.FallbackActivations))
+		for index, count := range stats.FallbackActivations {
+			activations[index] = count
+		}
+
+		snapshot := *stats
+		snapshot.FallbackActivations = activations
+		result[key] = snapshot
+	}
+	return result
+}
+
 func (fm *FallbackManager) startCacheCleanup() {
+	defer close(fm.done)
+
 	ticker := time.NewTicker(1 * time.Minute)
 	defer ticker.Stop()
 
@@ -183,10 +286,13 @@ func NewSequentialFallbackStrategy(config FallbackConfig) *SequentialFallbackStr
 }
 
 func (s *SequentialFallbackStrategy) Execute(ctx context.Context, primary func() error, fallbacks []func() error) error {
-	err := primary()
+	err := retryWithDelay(ctx, primary, s.config.MaxRetries, s.config.RetryDelay)
 	if err == nil {
 		return nil
 	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
 
 	for i, fallback := range fallbacks {
 		select {
@@ -208,6 +314,35 @@ func (s *SequentialFallbackStrategy) Execute(ctx context.Context, primary func()
 	return fmt.Errorf("all fallback attempts failed")
 }
 
+// retryWithDelay fn'i ilk denemeye ek olarak en fazla maxRetries kez, denemeler arasında
+// delay bekleyerek tekrar çalıştırır. Bekleme sırasında context iptal edilirse durur.
+func retryWithDelay(ctx context.Context, fn func() error, maxRetries int, delay time.Duration) error {
+	err := fn()
+	for attempt := 0; err != nil && attempt < maxRetries; attempt++ {
+		if sleepErr := sleepWithContext(ctx, delay); sleepErr != nil {
+			return err
+		}
+		err = fn()
+	}
+	return err
+}
+
+func sleepWithContext(ctx context.Context, delay time.Duration) error {
+	if delay <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
 func NewParallelFallbackStrategy(config FallbackConfig) *ParallelFallbackStrategy {
 	return &ParallelFallbackStrategy{config: config}
 }
@@ -225,6 +360,8 @@ func (p *ParallelFallbackStrategy) Execute(ctx context.Context, primary func() e
 	timeoutCtx, cancel := context.WithTimeout(ctx, p.config.Timeout)
 	defer cancel()
 
+	// Kanal buffer'lı olduğu için erken dönüldüğünde geride kalan goroutine'ler bloklanmaz;
+	// sonuçları yok sayılır
 	resultChan := make(chan error, len(fallbacks))
 
 	for _, fallback := range fallbacks {
@@ -233,22 +370,17 @@ func (p *ParallelFallbackStrategy) Execute(ctx context.Context, primary func() e
 		}(fallback)
 	}
 
-	successCount := 0
 	for i := 0; i < len(fallbacks); i++ {
 		select {
 		case <-timeoutCtx.Done():
 			return timeoutCtx.Err()
 		case fallbackErr := <-resultChan:
 			if fallbackErr == nil {
-				successCount++
+				return nil
 			}
 		}
 	}
 
-	if successCount > 0 {
-		return nil
-	}
-
 	return fmt.Errorf("all parallel fallback attempts failed")
 }
 
@@ -320,6 +452,11 @@ func LenientConfig() FallbackConfig {
 	}
 }
 
+// Done cache temizleme goroutine'i durduğunda (veya hiç başlatılmadıysa) kapalı olan kanalı döner
+func (fm *FallbackManager) Done() <-chan struct{} {
+	return fm.done
+}
+
 func (fm *FallbackManager) Close() {
 	fm.cancel()
 }
@@ -337,5 +474,6 @@ func (fm *FallbackManager) GetStats() map[string]interface{} {
 		"retry_delay":        fm.config.RetryDelay,
 		"timeout":            fm.config.Timeout,
 		"cache_ttl":          fm.config.CacheTTL,
+		"keys":               fm.GetKeyStats(),
 	}
 }
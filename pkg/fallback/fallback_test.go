@@ -0,0 +1,262 @@
+package fallback
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+var errPrimary = errors.New("primary failed")
+
+func TestSequentialFallbackStrategyRetriesPrimary(t *testing.T) {
+	tests := []struct {
+		name             string
+		maxRetries       int
+		primaryFailures  int
+		wantErr          bool
+		wantPrimaryCalls int
+		wantFallback     bool
+	}{
+		{name: "ilk denemede başarı", maxRetries: 3, wantPrimaryCalls: 1},
+		{name: "iki hatadan sonra başarı", maxRetries: 3, primaryFailures: 2, wantPrimaryCalls: 3},
+		{name: "tekrar hakkı tam yeter", maxRetries: 2, primaryFailures: 2, wantPrimaryCalls: 3},
+		{name: "tekrar hakkı biter, fallback çalışır", maxRetries: 1, primaryFailures: 2, wantPrimaryCalls: 2, wantFallback: true},
+		{name: "tekrar kapalı", primaryFailures: 1, wantPrimaryCalls: 1, wantFallback: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strategy := NewSequentialFallbackStrategy(FallbackConfig{MaxRetries: tt.maxRetries, RetryDelay: time.Millisecond})
+
+			primaryCalls, fallbackCalls := 0, 0
+			primary := func() error {
+				primaryCalls++
+				if primaryCalls <= tt.primaryFailures {
+					return errPrimary
+				}
+				return nil
+			}
+			fallback := func() error {
+				fallbackCalls++
+				return nil
+			}
+
+			if err := strategy.Execute(context.Background(), primary, []func() error{fallback}); (err != nil) != tt.wantErr {
+				t.Fatalf("Execute = %v, hata bekleniyor: %v", err, tt.wantErr)
+			}
+			if primaryCalls != tt.wantPrimaryCalls {
+				t.Errorf("primary %d kez çağrıldı, beklenen %d", primaryCalls, tt.wantPrimaryCalls)
+			}
+			if (fallbackCalls > 0) != tt.wantFallback {
+				t.Errorf("fallback %d kez çağrıldı, çağrılması bekleniyor: %v", fallbackCalls, tt.wantFallback)
+			}
+		})
+	}
+}
+
+func TestSequentialFallbackStrategyStopsOnCancel(t *testing.T) {
+	strategy := NewSequentialFallbackStrategy(FallbackConfig{MaxRetries: 5, RetryDelay: time.Hour})
+	ctx, cancel := context.WithCancel(context.Background())
+
+	primaryCalls, fallbackCalls := 0, 0
+	primary := func() error {
+		primaryCalls++
+		// Tekrar beklemesi sırasında iptal edilir
+		cancel()
+		return errPrimary
+	}
+	fallback := func() error {
+		fallbackCalls++
+		return nil
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- strategy.Execute(ctx, primary, []func() error{fallback}) }()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Execute = %v, beklenen context.Canceled", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("iptal edilen context tekrar beklemesini kesmedi")
+	}
+	if primaryCalls != 1 || fallbackCalls != 0 {
+		t.Errorf("primary/fallback çağrıları = %d/%d, beklenen 1/0", primaryCalls, fallbackCalls)
+	}
+}
+
+func TestParallelFallbackStrategyFirstSuccess(t *testing.T) {
+	tests := []struct {
+		name      string
+		fallbacks []time.Duration
+		failing   map[int]bool
+		timeout   time.Duration
+		wantErr   bool
+		wantUnder time.Duration
+	}{
+		{name: "hızlı fallback yavaşı beklemez", fallbacks: []time.Duration{0, 2 * time.Second}, timeout: 5 * time.Second, wantUnder: time.Second},
+		{name: "hatalı hızlı fallback sonrakini bekler", fallbacks: []time.Duration{0, 20 * time.Millisecond}, failing: map[int]bool{0: true}, timeout: 5 * time.Second, wantUnder: time.Second},
+		{name: "hepsi başarısız", fallbacks: []time.Duration{0, 10 * time.Millisecond}, failing: map[int]bool{0: true, 1: true}, timeout: 5 * time.Second, wantErr: true, wantUnder: time.Second},
+		{name: "zaman aşımı korunur", fallbacks: []time.Duration{2 * time.Second}, timeout: 50 * time.Millisecond, wantErr: true, wantUnder: time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strategy := NewParallelFallbackStrategy(FallbackConfig{Timeout: tt.timeout})
+			release := make(chan struct{})
+			defer close(release)
+
+			fallbacks := make([]func() error, len(tt.fallbacks))
+			for i, delay := range tt.fallbacks {
+				i, delay := i, delay
+				fallbacks[i] = func() error {
+					select {
+					case <-time.After(delay):
+					case <-release:
+					}
+					if tt.failing[i] {
+						return errors.New("fallback failed")
+					}
+					return nil
+				}
+			}
+
+			start := time.Now()
+			err := strategy.Execute(context.Background(), func() error { return errPrimary }, fallbacks)
+			elapsed := time.Since(start)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Execute = %v, hata bekleniyor: %v", err, tt.wantErr)
+			}
+			if elapsed >= tt.wantUnder {
+				t.Errorf("Execute %v sürdü, %v altında dönmeliydi", elapsed, tt.wantUnder)
+			}
+		})
+	}
+}
+
+func TestFallbackManagerExecuteCallsEachFallback(t *testing.T) {
+	tests := []struct {
+		name      string
+		succeeds  int
+		want      interface{}
+		wantCalls []int
+		wantErr   bool
+	}{
+		{name: "ilk fallback", succeeds: 0, want: "fallback-0", wantCalls: []int{0}},
+		{name: "ikinci fallback", succeeds: 1, want: "fallback-1", wantCalls: []int{0, 1}},
+		{name: "son fallback", succeeds: 2, want: "fallback-2", wantCalls: []int{0, 1, 2}},
+		{name: "hiçbiri başarılı olmaz", succeeds: -1, wantCalls: []int{0, 1, 2}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := NewFallbackManager(FallbackConfig{}, NewSequentialFallbackStrategy(FallbackConfig{}))
+			defer manager.Close()
+
+			var calls []int
+			fallbacks := make([]func() (interface{}, error), 3)
+			for i := range fallbacks {
+				i := i
+				fallbacks[i] = func() (interface{}, error) {
+					calls = append(calls, i)
+					if i != tt.succeeds {
+						return nil, errors.New("fallback failed")
+					}
+					return fmt.Sprintf("fallback-%d", i), nil
+				}
+			}
+
+			got, err := manager.Execute(context.Background(), "key", func() (interface{}, error) { return nil, errPrimary }, fallbacks...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Execute = %v, hata bekleniyor: %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("sonuç = %v, beklenen %v", got, tt.want)
+			}
+			if fmt.Sprint(calls) != fmt.Sprint(tt.wantCalls) {
+				t.Errorf("çağrı sırası = %v, beklenen %v", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestFallbackManagerKeyStats(t *testing.T) {
+	succeed := func() (interface{}, error) { return "ok", nil }
+	fail := func() (interface{}, error) { return nil, errPrimary }
+
+	tests := []struct {
+		name    string
+		config  FallbackConfig
+		execute func(manager *FallbackManager)
+		want    KeyStats
+	}{
+		{
+			name: "primary başarısı",
+			execute: func(manager *FallbackManager) {
+				manager.Execute(context.Background(), "key", succeed)
+				manager.Execute(context.Background(), "key", succeed)
+			},
+			want: KeyStats{PrimarySuccesses: 2, FallbackActivations: map[int]uint64{}},
+		},
+		{
+			name: "fallback indekse göre sayılır",
+			execute: func(manager *FallbackManager) {
+				manager.Execute(context.Background(), "key", fail, fail, succeed)
+				manager.Execute(context.Background(), "key", fail, succeed)
+			},
+			want: KeyStats{FallbackActivations: map[int]uint64{0: 1, 1: 1}},
+		},
+		{
+			name:   "cache isabeti",
+			config: FallbackConfig{EnableCaching: true, CacheTTL: time.Minute},
+			execute: func(manager *FallbackManager) {
+				manager.Execute(context.Background(), "key", succeed)
+				manager.Execute(context.Background(), "key", fail)
+			},
+			want: KeyStats{PrimarySuccesses: 1, CacheHits: 1, FallbackActivations: map[int]uint64{}},
+		},
+		{
+			name:   "degradation",
+			config: FallbackConfig{EnableDegradation: true},
+			execute: func(manager *FallbackManager) {
+				manager.ExecuteWithDegradation(context.Background(), "key", fail, succeed)
+				manager.ExecuteWithDegradation(context.Background(), "key", fail, fail)
+			},
+			want: KeyStats{Degradations: 1, Failures: 1, FallbackActivations: map[int]uint64{}},
+		},
+		{
+			name: "tüm fallback'ler başarısız",
+			execute: func(manager *FallbackManager) {
+				manager.Execute(context.Background(), "key", fail, fail)
+			},
+			want: KeyStats{Failures: 1, FallbackActivations: map[int]uint64{}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := NewFallbackManager(tt.config, NewSequentialFallbackStrategy(tt.config))
+			defer manager.Close()
+
+			tt.execute(manager)
+			manager.Execute(context.Background(), "other", succeed)
+
+			stats := manager.GetKeyStats()
+			got := stats["key"]
+			if (got.Failures > 0) != (got.LastFailureAt != nil) {
+				t.Errorf("LastFailureAt = %v, hata sayısı %d ile tutarsız", got.LastFailureAt, got.Failures)
+			}
+			got.LastFailureAt = nil
+			if fmt.Sprintf("%+v", got) != fmt.Sprintf("%+v", tt.want) {
+				t.Errorf("sayaçlar = %+v, beklenen %+v", got, tt.want)
+			}
+			if stats["other"].PrimarySuccesses != 1 {
+				t.Errorf("diğer anahtarın sayaçları karıştı: %+v", stats["other"])
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package featureflag
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+
+	"transaction-api-w-go/pkg/domain"
+)
+
+// Kademeli olarak açılan riskli özellikler
+const (
+	FlagNewTransferPath = "new_transfer_path"
+	FlagParallelBatch   = "parallel_batch"
+)
+
+// Flag bir özelliğin açık olup olmadığını ve kimler için açık olduğunu tanımlar.
+// Enabled false ise özellik herkes için kapalıdır; true ise Users listesindeki
+// kullanıcılar ve Percentage oranındaki kullanıcılar için açıktır.
+type Flag struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Enabled     bool     `json:"enabled"`
+	Percentage  int      `json:"percentage"`
+	Users       []string `json:"users,omitempty"`
+	// Overridden flag'in çalışma zamanında (admin endpoint'i ile) değiştirildiğini gösterir
+	Overridden bool `json:"overridden"`
+}
+
+// Validate oran ve isim alanlarını kontrol eder
+func (f Flag) Validate() error {
+	if f.Name == "" {
+		return fmt.Errorf("%w: name is required", domain.ErrInvalidFeatureFlag)
+	}
+	if f.Percentage < 0 || f.Percentage > 100 {
+		return fmt.Errorf("%w: percentage must be between 0 and 100", domain.ErrInvalidFeatureFlag)
+	}
+	return nil
+}
+
+// EnabledFor flag'in verilen kullanıcı için açık olup olmadığını döndürür.
+// Yüzdelik dağıtım kullanıcı id'sine göre deterministiktir: aynı kullanıcı aynı
+// oran için her zaman aynı sonucu alır ve oran arttıkça açık kullanıcılar kapanmaz.
+func (f Flag) EnabledFor(userID string) bool {
+	if !f.Enabled {
+		return false
+	}
+	for _, id := range f.Users {
+		if id == userID {
+			return true
+		}
+	}
+	if f.Percentage >= 100 {
+		return true
+	}
+	if f.Percentage <= 0 || userID == "" {
+		return false
+	}
+	return Bucket(f.Name, userID) < f.Percentage
+}
+
+// Bucket kullanıcıyı flag'e özgü 0-99 aralığındaki bir kovaya yerleştirir; flag adı
+// hash'e dahil edildiği için farklı flag'ler aynı kullanıcı grubuna denk gelmez
+func Bucket(flagName, userID string) int {
+	h := fnv.New32a()
+	h.Write([]byte(flagName))
+	h.Write([]byte{':'})
+	h.Write([]byte(userID))
+	return int(h.Sum32() % 100)
+}
+
+// builtinFlags kodda kullanılan flag'lerin varsayılan (kapalı) tanımları
+func builtinFlags() []Flag {
+	return []Flag{
+		{Name: FlagNewTransferPath, Description: "Transfer işlemini tek bir veritabanı transaction'ı içinde uygular"},
+		{Name: FlagParallelBatch, Description: "Batch kalemlerini paralel işler"},
+	}
+}
+
+// ParseDefaults "flag=değer" çiftlerinden oluşan virgülle ayrılmış tanımı çözer.
+// Değer true/false, on/off ya da 0-100 arasında bir yüzde olabilir:
+//
+//	new_transfer_path=25,parallel_batch=true
+func ParseDefaults(spec string) ([]Flag, error) {
+	var flags []Flag
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		name, value, ok := strings.Cut(part, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("%w: %q", domain.ErrInvalidFeatureFlag, part)
+		}
+
+		flag := Flag{Name: name}
+		value = strings.ToLower(strings.TrimSpace(value))
+		switch value {
+		case "on":
+			value = "true"
+		case "off":
+			value = "false"
+		}
+		if enabled, err := strconv.ParseBool(value); err == nil {
+			flag.Enabled = enabled
+			if enabled {
+				flag.Percentage = 100
+			}
+		} else if percentage, err := strconv.Atoi(strings.TrimSuffix(value, "%")); err == nil {
+			flag.Enabled = percentage > 0
+			flag.Percentage = percentage
+		} else {
+			return nil, fmt.Errorf("%w: %q", domain.ErrInvalidFeatureFlag, part)
+		}
+
+		if err := flag.Validate(); err != nil {
+			return nil, err
+		}
+		flags = append(flags, flag)
+	}
+	return flags, nil
+}
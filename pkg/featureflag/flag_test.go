@@ -0,0 +1,141 @@
+package featureflag
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"transaction-api-w-go/pkg/domain"
+)
+
+func testUsers(n int) []string {
+	users := make([]string, n)
+	for i := range users {
+		users[i] = fmt.Sprintf("user-%d", i)
+	}
+	return users
+}
+
+func TestFlagPercentageRolloutIsDeterministic(t *testing.T) {
+	users := testUsers(2000)
+
+	tests := []struct {
+		name       string
+		percentage int
+	}{
+		{name: "yüzde 10", percentage: 10},
+		{name: "yüzde 25", percentage: 25},
+		{name: "yüzde 50", percentage: 50},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flag := Flag{Name: FlagNewTransferPath, Enabled: true, Percentage: tt.percentage}
+			// Aynı tanımla yeniden oluşturulan flag aynı sonucu vermeli
+			again := Flag{Name: FlagNewTransferPath, Enabled: true, Percentage: tt.percentage}
+			wider := Flag{Name: FlagNewTransferPath, Enabled: true, Percentage: tt.percentage + 20}
+
+			enabled := 0
+			for _, user := range users {
+				got := flag.EnabledFor(user)
+				for i := 0; i < 3; i++ {
+					if flag.EnabledFor(user) != got || again.EnabledFor(user) != got {
+						t.Fatalf("%s için sonuç değişti", user)
+					}
+				}
+				if got {
+					enabled++
+					if !wider.EnabledFor(user) {
+						t.Errorf("%s oran artınca kapandı", user)
+					}
+				}
+			}
+
+			// Dağıtım oranın etrafında olmalı; fnv kovaları 2000 kullanıcıda %5 içinde kalır
+			want := len(users) * tt.percentage / 100
+			if diff := enabled - want; diff < -len(users)/20 || diff > len(users)/20 {
+				t.Errorf("açık kullanıcı = %d, beklenen yaklaşık %d", enabled, want)
+			}
+		})
+	}
+}
+
+func TestFlagRolloutDiffersPerFlag(t *testing.T) {
+	users := testUsers(1000)
+	first := Flag{Name: FlagNewTransferPath, Enabled: true, Percentage: 50}
+	second := Flag{Name: FlagParallelBatch, Enabled: true, Percentage: 50}
+
+	same := 0
+	for _, user := range users {
+		if first.EnabledFor(user) == second.EnabledFor(user) {
+			same++
+		}
+	}
+	if same == len(users) {
+		t.Error("farklı flag'ler aynı kullanıcı grubuna açıldı")
+	}
+}
+
+func TestFlagEnabledFor(t *testing.T) {
+	tests := []struct {
+		name   string
+		flag   Flag
+		userID string
+		want   bool
+	}{
+		{name: "kapalı flag listedeki kullanıcıya da kapalı", flag: Flag{Name: "f", Percentage: 100, Users: []string{"u1"}}, userID: "u1"},
+		{name: "listedeki kullanıcı yüzde sıfırda açık", flag: Flag{Name: "f", Enabled: true, Users: []string{"u1"}}, userID: "u1", want: true},
+		{name: "yüzde yüz herkese açık", flag: Flag{Name: "f", Enabled: true, Percentage: 100}, userID: "u2", want: true},
+		{name: "yüzde sıfır kapalı", flag: Flag{Name: "f", Enabled: true}, userID: "u2"},
+		{name: "kullanıcısız yüzdelik kapalı", flag: Flag{Name: "f", Enabled: true, Percentage: 99}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.flag.EnabledFor(tt.userID); got != tt.want {
+				t.Errorf("EnabledFor(%q) = %v, beklenen %v", tt.userID, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDefaults(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		want    []Flag
+		wantErr error
+	}{
+		{name: "boş", spec: ""},
+		{
+			name: "bool ve yüzde",
+			spec: "new_transfer_path=25, parallel_batch=on",
+			want: []Flag{
+				{Name: FlagNewTransferPath, Enabled: true, Percentage: 25},
+				{Name: FlagParallelBatch, Enabled: true, Percentage: 100},
+			},
+		},
+		{name: "kapalı", spec: "parallel_batch=false", want: []Flag{{Name: FlagParallelBatch}}},
+		{name: "aralık dışı yüzde", spec: "parallel_batch=150", wantErr: domain.ErrInvalidFeatureFlag},
+		{name: "değersiz", spec: "parallel_batch", wantErr: domain.ErrInvalidFeatureFlag},
+		{name: "geçersiz değer", spec: "parallel_batch=maybe", wantErr: domain.ErrInvalidFeatureFlag},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flags, err := ParseDefaults(tt.spec)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("hata = %v, beklenen %v", err, tt.wantErr)
+			}
+			if len(flags) != len(tt.want) {
+				t.Fatalf("flag'ler = %+v, beklenen %+v", flags, tt.want)
+			}
+			for i := range tt.want {
+				got := flags[i]
+				if got.Name != tt.want[i].Name || got.Enabled != tt.want[i].Enabled || got.Percentage != tt.want[i].Percentage {
+					t.Errorf("flag %d = %+v, beklenen %+v", i, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
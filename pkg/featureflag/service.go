@@ -0,0 +1,209 @@
+package featureflag
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"transaction-api-w-go/pkg/domain"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// DefaultRefreshInterval override'ların store'dan yeniden okunma aralığı;
+	// diğer instance'larda yapılan değişiklikler en geç bu süre sonunda görülür
+	DefaultRefreshInterval = 10 * time.Second
+
+	keyPrefix = "feature_flag:"
+)
+
+// Store çalışma zamanı override'larının paylaşıldığı depodur (ör. cache.RedisCache).
+// Kayıt yoksa Get domain.ErrCacheMiss döndürmelidir.
+type Store interface {
+	Get(ctx context.Context, key string, dest interface{}) error
+	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error
+	Delete(ctx context.Context, key string) error
+}
+
+type cachedOverride struct {
+	flag      *Flag
+	fetchedAt time.Time
+}
+
+// Service varsayılan flag'leri config'den alır, admin override'larını store'da tutar
+// ve kullanıcı bazında değerlendirme yapar. Store bağlı değilse override'lar
+// yalnızca bu instance'ın belleğinde yaşar.
+type Service struct {
+	mu              sync.RWMutex
+	defaults        map[string]Flag
+	overrides       map[string]cachedOverride
+	store           Store
+	refreshInterval time.Duration
+}
+
+func NewService(defaults []Flag) *Service {
+	s := &Service{
+		defaults:        make(map[string]Flag),
+		overrides:       make(map[string]cachedOverride),
+		refreshInterval: DefaultRefreshInterval,
+	}
+	for _, flag := range builtinFlags() {
+		s.defaults[flag.Name] = flag
+	}
+	for _, flag := range defaults {
+		if builtin, ok := s.defaults[flag.Name]; ok && flag.Description == "" {
+			flag.Description = builtin.Description
+		}
+		s.defaults[flag.Name] = flag
+	}
+	return s
+}
+
+// SetStore override'ların instance'lar arasında paylaşılacağı store'u bağlar
+func (s *Service) SetStore(store Store) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.store = store
+	s.overrides = make(map[string]cachedOverride)
+}
+
+// IsEnabled flag'in kullanıcı için açık olup olmadığını döndürür; bilinmeyen flag'ler kapalıdır.
+// Nil servis üzerinde çağrılabilir, bu durumda tüm flag'ler kapalı kabul edilir.
+func (s *Service) IsEnabled(ctx context.Context, name, userID string) bool {
+	if s == nil {
+		return false
+	}
+	flag, err := s.Get(ctx, name)
+	if err != nil {
+		return false
+	}
+	return flag.EnabledFor(userID)
+}
+
+// Get override varsa onu, yoksa varsayılan tanımı döndürür
+func (s *Service) Get(ctx context.Context, name string) (Flag, error) {
+	s.mu.RLock()
+	flag, ok := s.defaults[name]
+	s.mu.RUnlock()
+	if !ok {
+		return Flag{}, domain.ErrFeatureFlagNotFound
+	}
+
+	if override := s.override(ctx, name); override != nil {
+		return *override, nil
+	}
+	return flag, nil
+}
+
+// List tüm flag'leri ada göre sıralı döndürür
+func (s *Service) List(ctx context.Context) []Flag {
+	s.mu.RLock()
+	names := make([]string, 0, len(s.defaults))
+	for name := range s.defaults {
+		names = append(names, name)
+	}
+	s.mu.RUnlock()
+	sort.Strings(names)
+
+	flags := make([]Flag, 0, len(names))
+	for _, name := range names {
+		if flag, err := s.Get(ctx, name); err == nil {
+			flags = append(flags, flag)
+		}
+	}
+	return flags
+}
+
+// Set flag için çalışma zamanı override'ı yazar
+func (s *Service) Set(ctx context.Context, flag Flag) (Flag, error) {
+	s.mu.RLock()
+	current, ok := s.defaults[flag.Name]
+	store := s.store
+	s.mu.RUnlock()
+	if !ok {
+		return Flag{}, domain.ErrFeatureFlagNotFound
+	}
+	if err := flag.Validate(); err != nil {
+		return Flag{}, err
+	}
+
+	if flag.Description == "" {
+		flag.Description = current.Description
+	}
+	flag.Overridden = true
+
+	if store != nil {
+		if err := store.Set(ctx, keyPrefix+flag.Name, flag, 0); err != nil {
+			return Flag{}, err
+		}
+	}
+
+	s.mu.Lock()
+	s.overrides[flag.Name] = cachedOverride{flag: &flag, fetchedAt: time.Now()}
+	s.mu.Unlock()
+
+	log.Info().
+		Str("flag", flag.Name).
+		Bool("enabled", flag.Enabled).
+		Int("percentage", flag.Percentage).
+		Int("users", len(flag.Users)).
+		Msg("Feature flag güncellendi")
+	return flag, nil
+}
+
+// Reset override'ı siler; flag config'deki varsayılanına döner
+func (s *Service) Reset(ctx context.Context, name string) (Flag, error) {
+	s.mu.RLock()
+	flag, ok := s.defaults[name]
+	store := s.store
+	s.mu.RUnlock()
+	if !ok {
+		return Flag{}, domain.ErrFeatureFlagNotFound
+	}
+
+	if store != nil {
+		if err := store.Delete(ctx, keyPrefix+name); err != nil {
+			return Flag{}, err
+		}
+	}
+
+	s.mu.Lock()
+	s.overrides[name] = cachedOverride{fetchedAt: time.Now()}
+	s.mu.Unlock()
+
+	log.Info().Str("flag", name).Msg("Feature flag varsayılana döndürüldü")
+	return flag, nil
+}
+
+// override yerel kopya tazeyse onu kullanır, değilse store'dan okur. Store'a
+// ulaşılamazsa son bilinen değer korunur; böylece Redis kesintisi flag'leri çevirmez.
+func (s *Service) override(ctx context.Context, name string) *Flag {
+	s.mu.RLock()
+	cached, ok := s.overrides[name]
+	store := s.store
+	s.mu.RUnlock()
+
+	if store == nil || (ok && time.Since(cached.fetchedAt) < s.refreshInterval) {
+		return cached.flag
+	}
+
+	var flag Flag
+	err := store.Get(ctx, keyPrefix+name, &flag)
+	switch {
+	case err == nil:
+		cached = cachedOverride{flag: &flag, fetchedAt: time.Now()}
+	case errors.Is(err, domain.ErrCacheMiss):
+		cached = cachedOverride{fetchedAt: time.Now()}
+	default:
+		log.Warn().Err(err).Str("flag", name).Msg("Feature flag override'ı okunamadı, son bilinen değer kullanılıyor")
+		return cached.flag
+	}
+
+	s.mu.Lock()
+	s.overrides[name] = cached
+	s.mu.Unlock()
+	return cached.flag
+}
@@ -0,0 +1,122 @@
+package featureflag
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"transaction-api-w-go/pkg/domain"
+)
+
+// memoryStore override'ları JSON olarak bellekte tutar; instance'lar arası paylaşılan Redis'in yerine geçer
+type memoryStore struct {
+	mu     sync.Mutex
+	values map[string][]byte
+	err    error
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{values: make(map[string][]byte)}
+}
+
+func (s *memoryStore) Get(ctx context.Context, key string, dest interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	data, ok := s.values[key]
+	if !ok {
+		return domain.ErrCacheMiss
+	}
+	return json.Unmarshal(data, dest)
+}
+
+func (s *memoryStore) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values[key] = data
+	return nil
+}
+
+func (s *memoryStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.values, key)
+	return nil
+}
+
+func TestServiceOverridesSharedThroughStore(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryStore()
+
+	admin := NewService(nil)
+	admin.SetStore(store)
+	replica := NewService(nil)
+	replica.SetStore(store)
+	// Diğer instance override'ı her okumada store'dan tazeler
+	replica.refreshInterval = 0
+
+	tests := []struct {
+		name     string
+		apply    func() error
+		storeErr error
+		want     bool
+	}{
+		{name: "varsayılan kapalı", apply: func() error { return nil }},
+		{
+			name: "override diğer instance'a yansır",
+			apply: func() error {
+				_, err := admin.Set(ctx, Flag{Name: FlagParallelBatch, Enabled: true, Percentage: 100})
+				return err
+			},
+			want: true,
+		},
+		{name: "store kesintisinde son bilinen değer korunur", apply: func() error { return nil }, storeErr: errors.New("redis down"), want: true},
+		{
+			name: "reset varsayılana döndürür",
+			apply: func() error {
+				_, err := admin.Reset(ctx, FlagParallelBatch)
+				return err
+			},
+		},
+		{
+			name: "bilinmeyen flag reddedilir",
+			apply: func() error {
+				if _, err := admin.Set(ctx, Flag{Name: "unknown", Enabled: true}); !errors.Is(err, domain.ErrFeatureFlagNotFound) {
+					t.Errorf("Set = %v, beklenen ErrFeatureFlagNotFound", err)
+				}
+				return nil
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.apply(); err != nil {
+				t.Fatalf("uygulanamadı: %v", err)
+			}
+			store.mu.Lock()
+			store.err = tt.storeErr
+			store.mu.Unlock()
+
+			if got := replica.IsEnabled(ctx, FlagParallelBatch, "user-1"); got != tt.want {
+				t.Errorf("IsEnabled = %v, beklenen %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNilServiceDisablesFlags(t *testing.T) {
+	var svc *Service
+	if svc.IsEnabled(context.Background(), FlagNewTransferPath, "user-1") {
+		t.Error("nil servis flag'i açık döndü")
+	}
+}
@@ -0,0 +1,80 @@
+package i18n
+
+import (
+	"errors"
+
+	"transaction-api-w-go/pkg/domain"
+)
+
+// Code dilden bağımsız, istemcilerin güvenle eşleyebileceği sabit hata/mesaj kodudur
+type Code string
+
+const (
+	CodeInternalError              Code = "internal_error"
+	CodeMissingAuthorization       Code = "missing_authorization"
+	CodeInvalidAuthorizationFormat Code = "invalid_authorization_format"
+	CodeInvalidToken               Code = "invalid_token"
+	CodeInvalidTokenClaims         Code = "invalid_token_claims"
+	CodeInvalidCredentials         Code = "invalid_credentials"
+	CodeUserNotFound               Code = "user_not_found"
+	CodeInvalidUserID              Code = "invalid_user_id"
+	CodeInvalidTransactionID       Code = "invalid_transaction_id"
+	CodeInvalidDateFormat          Code = "invalid_date_format"
+	CodeInvalidQueryParam          Code = "invalid_query_param"
+	CodeInvalidPagination          Code = "invalid_pagination"
+	CodeReferenceIDRequired        Code = "reference_id_required"
+	CodeTransactionNotFound        Code = "transaction_not_found"
+	CodeBalanceNotFound            Code = "balance_not_found"
+	CodeBalanceHistoryNotFound     Code = "balance_history_not_found"
+	CodeInsufficientBalance        Code = "insufficient_balance"
+	CodeMinimumBalanceBreached     Code = "minimum_balance_breached"
+	CodeInvalidAmount              Code = "invalid_amount"
+	CodeUserUpdated                Code = "user_updated"
+	CodeUserDeleted                Code = "user_deleted"
+)
+
+// errorCodes domain sentinel hatalarını kodlara eşler
+var errorCodes = []struct {
+	err  error
+	code Code
+}{
+	{domain.ErrInvalidCredentials, CodeInvalidCredentials},
+	{domain.ErrInvalidToken, CodeInvalidToken},
+	{domain.ErrInvalidTokenClaims, CodeInvalidTokenClaims},
+	{domain.ErrUserNotFound, CodeUserNotFound},
+	{domain.ErrTransactionNotFound, CodeTransactionNotFound},
+	{domain.ErrBalanceNotFound, CodeBalanceNotFound},
+	{domain.ErrNoBalanceAtTime, CodeBalanceHistoryNotFound},
+	{domain.ErrMinimumBalanceBreached, CodeMinimumBalanceBreached},
+	{domain.ErrInsufficientBalance, CodeInsufficientBalance},
+	{domain.ErrInvalidAmount, CodeInvalidAmount},
+	{domain.ErrInvalidPagination, CodeInvalidPagination},
+}
+
+// Error kod ve format argümanlarını taşıyan, yanıtta dile göre çevrilen hatadır
+type Error struct {
+	Code Code
+	Args []interface{}
+}
+
+func NewError(code Code, args ...interface{}) *Error {
+	return &Error{Code: code, Args: args}
+}
+
+func (e *Error) Error() string {
+	return Translate(DefaultLanguage, e.Code, e.Args...)
+}
+
+// CodeOf hatanın kodunu döndürür; bilinmeyen hatalar için ok false olur
+func CodeOf(err error) (Code, bool) {
+	var coded *Error
+	if errors.As(err, &coded) {
+		return coded.Code, true
+	}
+	for _, entry := range errorCodes {
+		if errors.Is(err, entry.err) {
+			return entry.code, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,145 @@
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultLanguage Accept-Language başlığı yoksa veya desteklenmiyorsa kullanılan dil
+const DefaultLanguage = "en"
+
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+var catalogs = loadCatalogs()
+
+func loadCatalogs() map[string]map[Code]string {
+	entries, err := localeFiles.ReadDir("locales")
+	if err != nil {
+		panic(err)
+	}
+
+	result := make(map[string]map[Code]string, len(entries))
+	for _, entry := range entries {
+		data, err := localeFiles.ReadFile(path.Join("locales", entry.Name()))
+		if err != nil {
+			panic(err)
+		}
+
+		catalog := make(map[Code]string)
+		if err := json.Unmarshal(data, &catalog); err != nil {
+			panic(fmt.Sprintf("i18n: invalid catalog %s: %v", entry.Name(), err))
+		}
+		result[strings.TrimSuffix(entry.Name(), ".json")] = catalog
+	}
+	return result
+}
+
+// Supported dilin bir katalogu olup olmadığını döndürür
+func Supported(lang string) bool {
+	_, ok := catalogs[lang]
+	return ok
+}
+
+// Translate kodu verilen dile çevirir. Dilde karşılığı olmayan kodlar varsayılan dile,
+// orada da yoksa kodun kendisine düşer.
+func Translate(lang string, code Code, args ...interface{}) string {
+	message, ok := catalogs[lang][code]
+	if !ok {
+		message, ok = catalogs[DefaultLanguage][code]
+	}
+	if !ok {
+		return string(code)
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(message, args...)
+	}
+	return message
+}
+
+// LanguageFromHeader Accept-Language başlığından desteklenen en yüksek öncelikli dili seçer
+func LanguageFromHeader(header string) string {
+	type candidate struct {
+		lang string
+		q    float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		tag := strings.ToLower(strings.TrimSpace(fields[0]))
+		if tag == "" {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if parsed, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+
+		// "tr-TR" gibi bölgesel etiketler temel dile indirgenir
+		if idx := strings.Index(tag, "-"); idx > 0 {
+			tag = tag[:idx]
+		}
+		if q > 0 && Supported(tag) {
+			candidates = append(candidates, candidate{lang: tag, q: q})
+		}
+	}
+
+	if len(candidates) == 0 {
+		return DefaultLanguage
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].q > candidates[j].q
+	})
+	return candidates[0].lang
+}
+
+// Language isteğin Accept-Language başlığına göre yanıt dilini döndürür
+func Language(c *gin.Context) string {
+	return LanguageFromHeader(c.GetHeader("Accept-Language"))
+}
+
+// Localize bilinen hataları isteğin diline çevirir; bilinmeyen hatalar olduğu gibi döner
+func Localize(c *gin.Context, err error) string {
+	var coded *Error
+	if errors.As(err, &coded) {
+		return Translate(Language(c), coded.Code, coded.Args...)
+	}
+	if code, ok := CodeOf(err); ok {
+		return Translate(Language(c), code)
+	}
+	return err.Error()
+}
+
+// Respond kod ve çevrilmiş mesajla hata yanıtı yazar
+func Respond(c *gin.Context, status int, code Code, args ...interface{}) {
+	c.JSON(status, gin.H{"error": Translate(Language(c), code, args...), "code": code})
+}
+
+// RespondError hatayı isteğin diline çevirerek yazar; kodu biliniyorsa yanıta ekler
+func RespondError(c *gin.Context, status int, err error) {
+	body := gin.H{"error": Localize(c, err)}
+	if code, ok := CodeOf(err); ok {
+		body["code"] = code
+	}
+	c.JSON(status, body)
+}
+
+// Message kodu isteğin diline çevirir; başarı mesajları için kullanılır
+func Message(c *gin.Context, code Code, args ...interface{}) string {
+	return Translate(Language(c), code, args...)
+}
@@ -0,0 +1,115 @@
+package i18n
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"transaction-api-w-go/pkg/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestTranslate(t *testing.T) {
+	tests := []struct {
+		name string
+		lang string
+		code Code
+		args []interface{}
+		want string
+	}{
+		{name: "ingilizce", lang: "en", code: CodeUserNotFound, want: "User not found"},
+		{name: "türkçe", lang: "tr", code: CodeUserNotFound, want: "Kullanıcı bulunamadı"},
+		{name: "argümanlı türkçe", lang: "tr", code: CodeInvalidQueryParam, args: []interface{}{"limit"}, want: "Geçersiz limit"},
+		{name: "desteklenmeyen dil ingilizceye düşer", lang: "de", code: CodeInsufficientBalance, want: "Insufficient balance"},
+		{name: "bilinmeyen kod kendisini döner", lang: "tr", code: Code("no_such_code"), want: "no_such_code"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Translate(tt.lang, tt.code, tt.args...); got != tt.want {
+				t.Errorf("Translate(%s, %s) = %q, beklenen %q", tt.lang, tt.code, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCatalogsCoverSameCodes(t *testing.T) {
+	for lang, catalog := range catalogs {
+		for code := range catalogs[DefaultLanguage] {
+			if _, ok := catalog[code]; !ok {
+				t.Errorf("%s katalogunda %s eksik", lang, code)
+			}
+		}
+		for code := range catalog {
+			if _, ok := catalogs[DefaultLanguage][code]; !ok {
+				t.Errorf("%s katalogundaki %s varsayılan dilde yok", lang, code)
+			}
+		}
+	}
+}
+
+func TestLanguageFromHeader(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   string
+	}{
+		{name: "başlık yok", header: "", want: "en"},
+		{name: "bölgesel etiket", header: "tr-TR", want: "tr"},
+		{name: "öncelik sırası", header: "en;q=0.5, tr;q=0.9", want: "tr"},
+		{name: "desteklenmeyen dil atlanır", header: "de-DE, tr;q=0.3", want: "tr"},
+		{name: "sıfır öncelik reddedilir", header: "tr;q=0", want: "en"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LanguageFromHeader(tt.header); got != tt.want {
+				t.Errorf("LanguageFromHeader(%q) = %s, beklenen %s", tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRespondError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		err            error
+		wantMessage    string
+		wantCode       Code
+	}{
+		{name: "domain hatası ingilizce", err: fmt.Errorf("lookup: %w", domain.ErrUserNotFound), wantMessage: "User not found", wantCode: CodeUserNotFound},
+		{name: "domain hatası türkçe", acceptLanguage: "tr-TR,tr;q=0.9", err: domain.ErrUserNotFound, wantMessage: "Kullanıcı bulunamadı", wantCode: CodeUserNotFound},
+		{name: "kodlu hata türkçe", acceptLanguage: "tr", err: NewError(CodeInvalidQueryParam, "offset"), wantMessage: "Geçersiz offset", wantCode: CodeInvalidQueryParam},
+		{name: "bilinmeyen hata olduğu gibi döner", acceptLanguage: "tr", err: fmt.Errorf("boom"), wantMessage: "boom"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.acceptLanguage != "" {
+				c.Request.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+
+			RespondError(c, http.StatusNotFound, tt.err)
+
+			var body struct {
+				Error string `json:"error"`
+				Code  Code   `json:"code"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("yanıt çözülemedi: %v", err)
+			}
+			if body.Error != tt.wantMessage || body.Code != tt.wantCode {
+				t.Errorf("yanıt = %+v, beklenen %q/%q", body, tt.wantMessage, tt.wantCode)
+			}
+		})
+	}
+}
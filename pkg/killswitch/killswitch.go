@@ -0,0 +1,146 @@
+// Package killswitch olay anlarında zamanlayıcı, batch ve worker gibi otomatik işlemleri API'yi
+// durdurmadan cluster genelinde duraklatan anahtarı sağlar.
+package killswitch
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"transaction-api-w-go/pkg/clock"
+	"transaction-api-w-go/pkg/domain"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// DefaultRefreshInterval durumun store'dan yeniden okunma aralığı; başka bir instance'ta
+	// çekilen anahtar en geç bu süre sonunda görülür
+	DefaultRefreshInterval = 2 * time.Second
+
+	storeKey = "automation:kill_switch"
+)
+
+// Store anahtar durumunun instance'lar arasında paylaşıldığı depodur (ör. cache.RedisCache).
+// Kayıt yoksa Get domain.ErrCacheMiss döndürmelidir.
+type Store interface {
+	Get(ctx context.Context, key string, dest interface{}) error
+	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error
+}
+
+// State anahtarın durumu ve son değişikliğin kaydıdır
+type State struct {
+	Engaged   bool      `json:"engaged"`
+	Reason    string    `json:"reason,omitempty"`
+	ChangedBy string    `json:"changed_by,omitempty"`
+	ChangedAt time.Time `json:"changed_at,omitempty"`
+}
+
+// Switch otomatik işlemlerin duraklatılıp duraklatılmadığını tutar. Store bağlı değilse durum
+// yalnızca bu instance'ın belleğinde yaşar.
+type Switch struct {
+	mu              sync.RWMutex
+	state           State
+	fetchedAt       time.Time
+	store           Store
+	refreshInterval time.Duration
+	clock           clock.Clock
+}
+
+// New anahtarı store'da kayıt yokken geçerli olacak başlangıç durumuyla oluşturur
+func New(engaged bool) *Switch {
+	return NewWithClock(engaged, clock.Real())
+}
+
+func NewWithClock(engaged bool, clk clock.Clock) *Switch {
+	return &Switch{
+		state:           State{Engaged: engaged},
+		refreshInterval: DefaultRefreshInterval,
+		clock:           clk,
+	}
+}
+
+// SetStore durumun instance'lar arasında paylaşılacağı store'u bağlar
+func (s *Switch) SetStore(store Store) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.store = store
+	s.fetchedAt = time.Time{}
+}
+
+// Engaged otomatik işlemlerin duraklatılmış olup olmadığını döner. Nil anahtar üzerinde
+// çağrılabilir, bu durumda işlemler hiçbir zaman duraklatılmaz.
+func (s *Switch) Engaged(ctx context.Context) bool {
+	if s == nil {
+		return false
+	}
+	return s.Status(ctx).Engaged
+}
+
+// Status yerel kopya tazeyse onu, değilse store'daki durumu döner. Store'a ulaşılamazsa son
+// bilinen durum korunur; böylece Redis kesintisi anahtarı kendiliğinden çevirmez.
+func (s *Switch) Status(ctx context.Context) State {
+	now := s.clock.Now()
+
+	s.mu.RLock()
+	state, store, fetchedAt := s.state, s.store, s.fetchedAt
+	s.mu.RUnlock()
+
+	if store == nil || now.Sub(fetchedAt) < s.refreshInterval {
+		return state
+	}
+
+	var stored State
+	err := store.Get(ctx, storeKey, &stored)
+	switch {
+	case err == nil:
+		state = stored
+	case errors.Is(err, domain.ErrCacheMiss):
+		// Kimse anahtara dokunmadıysa başlangıç durumu geçerlidir
+	default:
+		log.Warn().Err(err).Msg("Kill switch state could not be refreshed, using last known state")
+	}
+
+	s.mu.Lock()
+	s.state, s.fetchedAt = state, now
+	s.mu.Unlock()
+	return state
+}
+
+// Engage otomatik işlemleri tüm instance'larda duraklatır
+func (s *Switch) Engage(ctx context.Context, reason, actor string) (State, error) {
+	return s.set(ctx, State{Engaged: true, Reason: reason, ChangedBy: actor})
+}
+
+// Release otomatik işlemleri yeniden başlatır
+func (s *Switch) Release(ctx context.Context, reason, actor string) (State, error) {
+	return s.set(ctx, State{Engaged: false, Reason: reason, ChangedBy: actor})
+}
+
+func (s *Switch) set(ctx context.Context, state State) (State, error) {
+	now := s.clock.Now()
+	state.ChangedAt = now
+
+	s.mu.RLock()
+	store := s.store
+	s.mu.RUnlock()
+
+	if store != nil {
+		// Süresiz yazılır; anahtar yalnızca açıkça bırakıldığında kalkar
+		if err := store.Set(ctx, storeKey, state, 0); err != nil {
+			return State{}, err
+		}
+	}
+
+	s.mu.Lock()
+	s.state, s.fetchedAt = state, now
+	s.mu.Unlock()
+
+	log.Warn().
+		Bool("engaged", state.Engaged).
+		Str("reason", state.Reason).
+		Str("changed_by", state.ChangedBy).
+		Msg("Automation kill switch changed")
+	return state, nil
+}
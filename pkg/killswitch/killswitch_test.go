@@ -0,0 +1,84 @@
+package killswitch
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"transaction-api-w-go/pkg/cache"
+	"transaction-api-w-go/pkg/clock"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+func TestSwitchSharedAcrossInstances(t *testing.T) {
+	tests := []struct {
+		name        string
+		initial     bool
+		action      string
+		wantEngaged bool
+	}{
+		{name: "çekilen anahtar diğer instance'ta görülür", action: "engage", wantEngaged: true},
+		{name: "bırakılan anahtar diğer instance'ta kalkar", initial: true, action: "release", wantEngaged: false},
+		{name: "kayıt yokken başlangıç durumu geçerli", initial: true, wantEngaged: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			server := miniredis.RunT(t)
+			redisCache, err := cache.NewRedisCache(cache.CacheConfig{Addrs: []string{server.Addr()}}, nil)
+			if err != nil {
+				t.Fatalf("NewRedisCache: %v", err)
+			}
+			t.Cleanup(func() { redisCache.Close() })
+
+			clk := clock.NewFake(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+			operator := NewWithClock(tt.initial, clk)
+			operator.SetStore(redisCache)
+			worker := NewWithClock(tt.initial, clk)
+			worker.SetStore(redisCache)
+
+			// worker durumu bir kez okuyup yerel kopyasını tazeler
+			if got := worker.Engaged(ctx); got != tt.initial {
+				t.Fatalf("başlangıçta Engaged = %v, beklenen %v", got, tt.initial)
+			}
+
+			switch tt.action {
+			case "engage":
+				_, err = operator.Engage(ctx, "incident", "ops")
+			case "release":
+				_, err = operator.Release(ctx, "resolved", "ops")
+			}
+			if err != nil {
+				t.Fatalf("%s: %v", tt.action, err)
+			}
+
+			// Yerel kopya yenileme aralığı dolana kadar kullanılır
+			if got := worker.Engaged(ctx); got != tt.initial {
+				t.Errorf("yenileme aralığı dolmadan Engaged = %v, beklenen %v", got, tt.initial)
+			}
+			clk.Advance(DefaultRefreshInterval)
+			if got := worker.Engaged(ctx); got != tt.wantEngaged {
+				t.Errorf("yenileme sonrası Engaged = %v, beklenen %v", got, tt.wantEngaged)
+			}
+
+			// Redis kesintisi anahtarı kendiliğinden çevirmez, yazma ise hata döner
+			server.Close()
+			clk.Advance(DefaultRefreshInterval)
+			if got := worker.Engaged(ctx); got != tt.wantEngaged {
+				t.Errorf("redis kesintisinde Engaged = %v, son bilinen durum %v korunmalı", got, tt.wantEngaged)
+			}
+			if _, err := operator.Engage(ctx, "incident", "ops"); err == nil {
+				t.Error("store'a yazılamazken Engage hata dönmeli")
+			}
+		})
+	}
+}
+
+func TestNilSwitchNeverEngaged(t *testing.T) {
+	var sw *Switch
+	if sw.Engaged(context.Background()) {
+		t.Error("nil anahtar işlemleri duraklatmamalı")
+	}
+}
@@ -0,0 +1,106 @@
+package loadbalancer
+
+import (
+	"hash/crc32"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultVirtualNodes her backend için hash ring üzerine yerleştirilen sanal node sayısı
+const DefaultVirtualNodes = 100
+
+// KeyedStrategy bir anahtara göre backend seçebilen stratejiler (sticky session)
+type KeyedStrategy interface {
+	LoadBalancingStrategy
+	SelectBackendForKey(backends []*Backend, key string) *Backend
+}
+
+// ConsistentHashStrategy anahtarları backend'lerden oluşan bir hash ring üzerine eşler.
+// Aynı anahtar aynı backend'e gider; bir backend eklenip çıkarıldığında yalnızca o
+// backend'in ring üzerindeki aralığındaki anahtarlar yer değiştirir.
+type ConsistentHashStrategy struct {
+	virtualNodes int
+	fallback     *RoundRobinStrategy
+
+	ring      []uint32
+	owners    map[uint32]string
+	signature string
+	mu        sync.Mutex
+}
+
+func NewConsistentHashStrategy(virtualNodes int) *ConsistentHashStrategy {
+	if virtualNodes <= 0 {
+		virtualNodes = DefaultVirtualNodes
+	}
+	return &ConsistentHashStrategy{
+		virtualNodes: virtualNodes,
+		fallback:     NewRoundRobinStrategy(),
+		owners:       make(map[uint32]string),
+	}
+}
+
+// SelectBackend anahtar olmadan çağrıldığında round robin ile seçer
+func (ch *ConsistentHashStrategy) SelectBackend(backends []*Backend) *Backend {
+	return ch.fallback.SelectBackend(backends)
+}
+
+func (ch *ConsistentHashStrategy) SelectBackendForKey(backends []*Backend, key string) *Backend {
+	if len(backends) == 0 {
+		return nil
+	}
+	if key == "" {
+		return ch.SelectBackend(backends)
+	}
+
+	byID := make(map[string]*Backend, len(backends))
+	for _, backend := range backends {
+		byID[backend.ID] = backend
+	}
+
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+
+	ch.rebuild(backends)
+
+	hash := crc32.ChecksumIEEE([]byte(key))
+	idx := sort.Search(len(ch.ring), func(i int) bool { return ch.ring[i] >= hash })
+	if idx == len(ch.ring) {
+		idx = 0
+	}
+
+	return byID[ch.owners[ch.ring[idx]]]
+}
+
+// rebuild backend kümesi değiştiyse ring'i yeniden oluşturur; ch.mu tutulurken çağrılmalıdır
+func (ch *ConsistentHashStrategy) rebuild(backends []*Backend) {
+	ids := make([]string, 0, len(backends))
+	for _, backend := range backends {
+		ids = append(ids, backend.ID)
+	}
+	sort.Strings(ids)
+
+	signature := strings.Join(ids, ",")
+	if signature == ch.signature {
+		return
+	}
+
+	ring := make([]uint32, 0, len(ids)*ch.virtualNodes)
+	owners := make(map[uint32]string, len(ids)*ch.virtualNodes)
+	for _, id := range ids {
+		for i := 0; i < ch.virtualNodes; i++ {
+			hash := crc32.ChecksumIEEE([]byte(id + "#" + strconv.Itoa(i)))
+			if _, taken := owners[hash]; taken {
+				continue
+			}
+			owners[hash] = id
+			ring = append(ring, hash)
+		}
+	}
+	sort.Slice(ring, func(i, j int) bool { return ring[i] < ring[j] })
+
+	ch.ring = ring
+	ch.owners = owners
+	ch.signature = signature
+}
@@ -0,0 +1,97 @@
+package loadbalancer
+
+import (
+	"fmt"
+	"testing"
+)
+
+func newTestBackends(ids ...string) []*Backend {
+	backends := make([]*Backend, 0, len(ids))
+	for _, id := range ids {
+		backends = append(backends, &Backend{ID: id, IsActive: true, Health: 1})
+	}
+	return backends
+}
+
+func TestConsistentHashStrategySameKeySameBackend(t *testing.T) {
+	tests := []struct {
+		name     string
+		backends []*Backend
+		keys     []string
+	}{
+		{name: "tek backend", backends: newTestBackends("a"), keys: []string{"user-1", "user-2"}},
+		{name: "birden çok backend", backends: newTestBackends("a", "b", "c"), keys: []string{"user-1", "user-2", "user-3", "user-4"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lb := NewLoadBalancer(NewConsistentHashStrategy(DefaultVirtualNodes), NewHealthChecker(0), DefaultLoadBalancerConfig())
+			t.Cleanup(lb.Close)
+			for _, backend := range tt.backends {
+				lb.AddBackend(backend)
+			}
+
+			for _, key := range tt.keys {
+				first, err := lb.GetBackendForKey(key)
+				if err != nil {
+					t.Fatalf("GetBackendForKey(%s): %v", key, err)
+				}
+				for i := 0; i < 10; i++ {
+					got, err := lb.GetBackendForKey(key)
+					if err != nil {
+						t.Fatalf("GetBackendForKey(%s): %v", key, err)
+					}
+					if got.ID != first.ID {
+						t.Fatalf("%s anahtarı %s yerine %s backend'ine gitti", key, first.ID, got.ID)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestConsistentHashStrategyRemoveBackend(t *testing.T) {
+	tests := []struct {
+		name     string
+		backends []string
+		remove   string
+		keys     int
+	}{
+		{name: "dört backend'den biri çıkarılır", backends: []string{"a", "b", "c", "d"}, remove: "b", keys: 2000},
+		{name: "beş backend'den biri çıkarılır", backends: []string{"a", "b", "c", "d", "e"}, remove: "e", keys: 2000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strategy := NewConsistentHashStrategy(DefaultVirtualNodes)
+			before := newTestBackends(tt.backends...)
+			after := make([]*Backend, 0, len(before)-1)
+			for _, backend := range before {
+				if backend.ID != tt.remove {
+					after = append(after, backend)
+				}
+			}
+
+			moved := 0
+			for i := 0; i < tt.keys; i++ {
+				key := fmt.Sprintf("user-%d", i)
+				owner := strategy.SelectBackendForKey(before, key).ID
+				next := strategy.SelectBackendForKey(after, key).ID
+
+				if owner != tt.remove {
+					if next != owner {
+						t.Fatalf("%s anahtarı çıkarılmayan %s backend'inden %s'e taşındı", key, owner, next)
+					}
+					continue
+				}
+				moved++
+			}
+
+			// Yalnızca çıkarılan backend'in anahtarları taşınır; bu da kabaca 1/n oranıdır
+			limit := 2 * tt.keys / len(tt.backends)
+			if moved == 0 || moved > limit {
+				t.Errorf("%d anahtar taşındı, beklenen 0 < n <= %d", moved, limit)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package loadbalancer
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/rand"
 	"sync"
@@ -17,15 +18,148 @@ type Backend struct {
 	Latency   time.Duration `json:"latency"`
 	LastCheck time.Time     `json:"last_check"`
 	mu        sync.RWMutex  `json:"-"`
+
+	// Yönlendirme metadata'sı; bölge bazlı veya tag filtreli seçimde kullanılır
+	Region   string            `json:"region,omitempty"`
+	Tags     []string          `json:"tags,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// BackendPredicate GetBackendFiltered'da hangi backend'lerin seçime gireceğini belirler
+type BackendPredicate func(backend *Backend) bool
+
+// HasTag backend verilen tag'e sahipse true döner
+func (b *Backend) HasTag(tag string) bool {
+	for _, t := range b.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
 }
 
+// WithTag yalnızca verilen tag'e sahip backend'leri seçer (ör. "primary")
+func WithTag(tag string) BackendPredicate {
+	return func(backend *Backend) bool {
+		return backend.HasTag(tag)
+	}
+}
+
+// InRegion yalnızca verilen bölgedeki backend'leri seçer
+func InRegion(region string) BackendPredicate {
+	return func(backend *Backend) bool {
+		return backend.Region == region
+	}
+}
+
+// WithMetadata metadata'sında key=value bulunan backend'leri seçer
+func WithMetadata(key, value string) BackendPredicate {
+	return func(backend *Backend) bool {
+		v, ok := backend.Metadata[key]
+		return ok && v == value
+	}
+}
+
+var (
+	ErrNoBackends         = errors.New("no load balancer backends configured")
+	ErrNoActiveBackends   = errors.New("no active backends available")
+	ErrHealthCheckTimeout = errors.New("health check timeout")
+	ErrUnknownStrategy    = errors.New("unknown load balancing strategy")
+)
+
+const (
+	StrategyRoundRobin         = "round_robin"
+	StrategyWeightedRoundRobin = "weighted_round_robin"
+	StrategyLeastConnections   = "least_connections"
+	StrategyHealthWeighted     = "health_weighted"
+	StrategyConsistentHash     = "consistent_hash"
+)
+
+// NewStrategy ada göre varsayılan ayarlarla strateji oluşturur
+func NewStrategy(name string) (LoadBalancingStrategy, error) {
+	switch name {
+	case StrategyRoundRobin:
+		return NewRoundRobinStrategy(), nil
+	case StrategyWeightedRoundRobin:
+		return NewWeightedRoundRobinStrategy(), nil
+	case StrategyLeastConnections:
+		return NewLeastConnectionsStrategy(), nil
+	case StrategyHealthWeighted:
+		return NewHealthWeightedStrategy(DefaultHealthFloor, false), nil
+	case StrategyConsistentHash:
+		return NewConsistentHashStrategy(DefaultVirtualNodes), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownStrategy, name)
+	}
+}
+
+// StrategyName stratejinin NewStrategy'de kullanılan adını döner; bilinmeyen tiplerde "custom"
+func StrategyName(strategy LoadBalancingStrategy) string {
+	switch strategy.(type) {
+	case *RoundRobinStrategy:
+		return StrategyRoundRobin
+	case *WeightedRoundRobinStrategy:
+		return StrategyWeightedRoundRobin
+	case *LeastConnectionsStrategy:
+		return StrategyLeastConnections
+	case *HealthWeightedStrategy:
+		return StrategyHealthWeighted
+	case *ConsistentHashStrategy:
+		return StrategyConsistentHash
+	default:
+		return "custom"
+	}
+}
+
+const (
+	StatusHealthy  = "healthy"
+	StatusDegraded = "degraded"
+)
+
 type LoadBalancer struct {
 	backends    []*Backend
 	strategy    LoadBalancingStrategy
 	healthCheck HealthChecker
+	config      LoadBalancerConfig
+	store       BackendStore
 	mu          sync.RWMutex
 	ctx         context.Context
 	cancel      context.CancelFunc
+	// done health check goroutine'i sonlandığında kapanır
+	done chan struct{}
+	// configChanged health check aralığı değiştiğinde ticker'ın yeniden kurulmasını tetikler
+	configChanged chan struct{}
+}
+
+const (
+	DefaultHealthCheckInterval = 30 * time.Second
+	DefaultHealthCheckTimeout  = 5 * time.Second
+)
+
+// LoadBalancerConfig health check zamanlamasını belirler; sıfır değerlerde varsayılanlar kullanılır
+type LoadBalancerConfig struct {
+	// HealthCheckInterval periyodik health check'ler arasındaki süre
+	HealthCheckInterval time.Duration `json:"health_check_interval"`
+	// HealthCheckTimeout tek bir backend kontrolünün süresi; aşılırsa backend pasife alınır
+	HealthCheckTimeout time.Duration `json:"health_check_timeout"`
+}
+
+func DefaultLoadBalancerConfig() LoadBalancerConfig {
+	return LoadBalancerConfig{
+		HealthCheckInterval: DefaultHealthCheckInterval,
+		HealthCheckTimeout:  DefaultHealthCheckTimeout,
+	}
+}
+
+// withDefaults sıfır veya negatif alanları varsayılanlarla doldurur
+func (c LoadBalancerConfig) withDefaults() LoadBalancerConfig {
+	if c.HealthCheckInterval <= 0 {
+		c.HealthCheckInterval = DefaultHealthCheckInterval
+	}
+	if c.HealthCheckTimeout <= 0 {
+		c.HealthCheckTimeout = DefaultHealthCheckTimeout
+	}
+	return c
 }
 
 type LoadBalancingStrategy interface {
@@ -50,18 +184,44 @@ type LeastConnectionsStrategy struct {
 	mu sync.Mutex
 }
 
+// HealthWeightedStrategy health skoru HealthFloor altındaki backend'leri atlar, kalanlar
+// arasında health skoruyla orantılı (smooth weighted round robin) seçim yapar.
+// UseWeights açıkken backend Weight değeriyle de çarpılarak weighted round robin ile birleşir.
+type HealthWeightedStrategy struct {
+	HealthFloor float64
+	UseWeights  bool
+
+	currentWeights map[string]int
+	mu             sync.Mutex
+}
+
+// DefaultHealthFloor bu değerin altındaki backend'ler health-weighted seçimde atlanır
+const DefaultHealthFloor = 0.2
+
+// healthRecoveryRate başarılı her health check'te skorun 1.0'a yaklaşma oranı
+const healthRecoveryRate = 0.5
+
 type HealthCheckerImpl struct {
 	timeout time.Duration
 }
 
-func NewLoadBalancer(strategy LoadBalancingStrategy, healthCheck HealthChecker) *LoadBalancer {
-	ctx, cancel := context.WithCancel(context.Background())
+func NewLoadBalancer(strategy LoadBalancingStrategy, healthCheck HealthChecker, config LoadBalancerConfig) *LoadBalancer {
+	return NewLoadBalancerWithContext(context.Background(), strategy, healthCheck, config)
+}
+
+// NewLoadBalancerWithContext health check goroutine'ini parent context'e bağlar
+func NewLoadBalancerWithContext(parent context.Context, strategy LoadBalancingStrategy, healthCheck HealthChecker, config LoadBalancerConfig) *LoadBalancer {
+	ctx, cancel := context.WithCancel(parent)
 
 	lb := &LoadBalancer{
 		strategy:    strategy,
 		healthCheck: healthCheck,
+		config:      config.withDefaults(),
 		ctx:         ctx,
 		cancel:      cancel,
+		done:        make(chan struct{}),
+
+		configChanged: make(chan struct{}, 1),
 	}
 
 	go lb.startHealthMonitoring()
@@ -78,7 +238,11 @@ func (lb *LoadBalancer) AddBackend(backend *Backend) {
 func (lb *LoadBalancer) RemoveBackend(backendID string) {
 	lb.mu.Lock()
 	defer lb.mu.Unlock()
+	lb.removeBackend(backendID)
+}
 
+// removeBackend lb.mu yazma kilidi tutulurken çağrılmalıdır
+func (lb *LoadBalancer) removeBackend(backendID string) {
 	for i, backend := range lb.backends {
 		if backend.ID == backendID {
 			lb.backends = append(lb.backends[:i], lb.backends[i+1:]...)
@@ -91,49 +255,223 @@ func (lb *LoadBalancer) GetBackend() (*Backend, error) {
 	lb.mu.RLock()
 	defer lb.mu.RUnlock()
 
-	activeBackends := make([]*Backend, 0)
-	for _, backend := range lb.backends {
-		if backend.IsActive {
-			activeBackends = append(activeBackends, backend)
+	if len(lb.backends) == 0 {
+		return nil, ErrNoBackends
+	}
+
+	activeBackends := lb.activeBackends()
+	if len(activeBackends) == 0 {
+		return nil, ErrNoActiveBackends
+	}
+
+	backend := lb.strategy.SelectBackend(activeBackends)
+	if backend == nil {
+		return nil, ErrNoActiveBackends
+	}
+	return backend, nil
+}
+
+// GetBackendFiltered strateji seçimini yalnızca predicate'i sağlayan aktif backend'ler
+// arasında yapar. Eşleşen aktif backend yoksa ErrNoActiveBackends döner.
+func (lb *LoadBalancer) GetBackendFiltered(predicate BackendPredicate) (*Backend, error) {
+	lb.mu.RLock()
+	defer lb.mu.RUnlock()
+
+	if len(lb.backends) == 0 {
+		return nil, ErrNoBackends
+	}
+
+	candidates := make([]*Backend, 0, len(lb.backends))
+	for _, backend := range lb.activeBackends() {
+		if predicate == nil || predicate(backend) {
+			candidates = append(candidates, backend)
 		}
 	}
+	if len(candidates) == 0 {
+		return nil, ErrNoActiveBackends
+	}
+
+	backend := lb.strategy.SelectBackend(candidates)
+	if backend == nil {
+		return nil, ErrNoActiveBackends
+	}
+	return backend, nil
+}
 
+// GetBackendForKey anahtarı (ör. kullanıcı id) her zaman aynı backend'e yönlendirir.
+// Strateji anahtar tabanlı seçimi desteklemiyorsa normal seçime döner.
+func (lb *LoadBalancer) GetBackendForKey(key string) (*Backend, error) {
+	lb.mu.RLock()
+	defer lb.mu.RUnlock()
+
+	if len(lb.backends) == 0 {
+		return nil, ErrNoBackends
+	}
+
+	activeBackends := lb.activeBackends()
 	if len(activeBackends) == 0 {
-		return nil, fmt.Errorf("no active backends available")
+		return nil, ErrNoActiveBackends
+	}
+
+	var backend *Backend
+	if keyed, ok := lb.strategy.(KeyedStrategy); ok {
+		backend = keyed.SelectBackendForKey(activeBackends, key)
+	} else {
+		backend = lb.strategy.SelectBackend(activeBackends)
 	}
 
-	return lb.strategy.SelectBackend(activeBackends), nil
+	if backend == nil {
+		return nil, ErrNoActiveBackends
+	}
+	return backend, nil
+}
+
+// activeBackends aktif backend'leri döner; lb.mu okuma kilidi tutulurken çağrılmalıdır
+func (lb *LoadBalancer) activeBackends() []*Backend {
+	active := make([]*Backend, 0, len(lb.backends))
+	for _, backend := range lb.backends {
+		if backend.active() {
+			active = append(active, backend)
+		}
+	}
+	return active
+}
+
+// ActiveBackendCount aktif backend sayısını döner
+func (lb *LoadBalancer) ActiveBackendCount() int {
+	lb.mu.RLock()
+	defer lb.mu.RUnlock()
+	return len(lb.activeBackends())
+}
+
+// Status load balancer'ın genel durumunu ve degraded ise nedenini döner.
+// Hiç backend yoksa veya hiçbiri aktif değilse durum degraded olur; ilk aktif
+// backend eklendiğinde tekrar healthy'ye döner.
+func (lb *LoadBalancer) Status() (string, string) {
+	lb.mu.RLock()
+	defer lb.mu.RUnlock()
+
+	if len(lb.backends) == 0 {
+		return StatusDegraded, ErrNoBackends.Error()
+	}
+	if len(lb.activeBackends()) == 0 {
+		return StatusDegraded, ErrNoActiveBackends.Error()
+	}
+	return StatusHealthy, ""
+}
+
+func (b *Backend) health() float64 {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.Health
+}
+
+func (b *Backend) active() bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.IsActive
+}
+
+// Config kullanılan health check ayarlarını döner
+func (lb *LoadBalancer) Config() LoadBalancerConfig {
+	lb.mu.RLock()
+	defer lb.mu.RUnlock()
+	return lb.config
+}
+
+// UpdateConfig health check ayarlarını çalışma zamanında değiştirir; yeni aralık bir
+// sonraki tick'i beklemeden uygulanır. Sıfır alanlar varsayılanlara döner.
+func (lb *LoadBalancer) UpdateConfig(config LoadBalancerConfig) {
+	lb.mu.Lock()
+	lb.config = config.withDefaults()
+	lb.mu.Unlock()
+
+	select {
+	case lb.configChanged <- struct{}{}:
+	default:
+	}
+}
+
+// SetStrategy backend seçim stratejisini çalışma zamanında değiştirir
+func (lb *LoadBalancer) SetStrategy(strategy LoadBalancingStrategy) {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+	lb.strategy = strategy
+}
+
+// StrategyName kullanılan stratejinin adını döner
+func (lb *LoadBalancer) StrategyName() string {
+	lb.mu.RLock()
+	defer lb.mu.RUnlock()
+	return StrategyName(lb.strategy)
 }
 
 func (lb *LoadBalancer) startHealthMonitoring() {
-	ticker := time.NewTicker(30 * time.Second) // Check every 30 seconds
+	defer close(lb.done)
+
+	ticker := time.NewTicker(lb.Config().HealthCheckInterval)
 	defer ticker.Stop()
 
 	for {
 		select {
 		case <-lb.ctx.Done():
 			return
+		case <-lb.configChanged:
+			ticker.Reset(lb.Config().HealthCheckInterval)
 		case <-ticker.C:
 			lb.performHealthCheck()
 		}
 	}
 }
 
+// CheckHealthNow periyodik ticker'ı beklemeden tüm backend'leri kontrol eder ve
+// kontroller bitince (en fazla HealthCheckTimeout sonra) güncel backend listesini döner
+func (lb *LoadBalancer) CheckHealthNow() []*Backend {
+	lb.performHealthCheck()
+	return lb.GetBackends()
+}
+
+// performHealthCheck backend'leri paralel kontrol eder ve tümü bitene kadar bekler
 func (lb *LoadBalancer) performHealthCheck() {
 	lb.mu.RLock()
 	backends := make([]*Backend, len(lb.backends))
 	copy(backends, lb.backends)
 	lb.mu.RUnlock()
 
+	var wg sync.WaitGroup
 	for _, backend := range backends {
-		go lb.checkBackendHealth(backend)
+		wg.Add(1)
+		go func(backend *Backend) {
+			defer wg.Done()
+			lb.checkBackendHealth(backend)
+		}(backend)
+	}
+	wg.Wait()
+}
+
+// runHealthCheck HealthChecker'ı HealthCheckTimeout ile sınırlar; süre aşılırsa
+// kontrol arka planda bitse bile sonuç beklenmez
+func (lb *LoadBalancer) runHealthCheck(backend *Backend) error {
+	result := make(chan error, 1)
+	go func() {
+		result <- lb.healthCheck.CheckHealth(backend)
+	}()
+
+	timer := time.NewTimer(lb.Config().HealthCheckTimeout)
+	defer timer.Stop()
+
+	select {
+	case err := <-result:
+		return err
+	case <-timer.C:
+		return ErrHealthCheckTimeout
 	}
 }
 
 func (lb *LoadBalancer) checkBackendHealth(backend *Backend) {
 	start := time.Now()
 
-	err := lb.healthCheck.CheckHealth(backend)
+	err := lb.runHealthCheck(backend)
 	latency := time.Since(start)
 
 	backend.mu.Lock()
@@ -142,12 +480,14 @@ func (lb *LoadBalancer) checkBackendHealth(backend *Backend) {
 	backend.LastCheck = time.Now()
 	backend.Latency = latency
 
+	// Health skoru yeni başarısızlıktan sonra kademeli olarak toparlanır; böylece
+	// health-weighted seçimde yakın zamanda düşmüş backend'ler daha az trafik alır
 	if err != nil {
 		backend.IsActive = false
 		backend.Health = 0.0
 	} else {
 		backend.IsActive = true
-		backend.Health = 1.0
+		backend.Health = backend.Health*(1-healthRecoveryRate) + healthRecoveryRate
 	}
 }
 
@@ -176,6 +516,7 @@ func (lb *LoadBalancer) GetStats() map[string]interface{} {
 	totalHealth := 0.0
 
 	for _, backend := range lb.backends {
+		backend.mu.RLock()
 		if backend.IsActive {
 			stats["active_backends"] = stats["active_backends"].(int) + 1
 		} else {
@@ -184,6 +525,7 @@ func (lb *LoadBalancer) GetStats() map[string]interface{} {
 
 		totalLatency += backend.Latency
 		totalHealth += backend.Health
+		backend.mu.RUnlock()
 	}
 
 	if len(lb.backends) > 0 {
@@ -194,6 +536,11 @@ func (lb *LoadBalancer) GetStats() map[string]interface{} {
 	return stats
 }
 
+// Done health check goroutine'i durduğunda kapanan kanalı döner
+func (lb *LoadBalancer) Done() <-chan struct{} {
+	return lb.done
+}
+
 func (lb *LoadBalancer) Close() {
 	lb.cancel()
 }
@@ -206,7 +553,8 @@ func (rr *RoundRobinStrategy) SelectBackend(backends []*Backend) *Backend {
 		return nil
 	}
 
-	backend := backends[rr.current]
+	// Backend listesi küçülmüş olabilir
+	backend := backends[rr.current%len(backends)]
 	rr.current = (rr.current + 1) % len(backends)
 
 	return backend
@@ -226,12 +574,12 @@ func (wrr *WeightedRoundRobinStrategy) SelectBackend(backends []*Backend) *Backe
 	}
 
 	if totalWeight == 0 {
-		backend := backends[wrr.current]
+		backend := backends[wrr.current%len(backends)]
 		wrr.current = (wrr.current + 1) % len(backends)
 		return backend
 	}
 
-	backend := backends[wrr.current]
+	backend := backends[wrr.current%len(backends)]
 	wrr.current = (wrr.current + 1) % len(backends)
 
 	return backend
@@ -258,6 +606,51 @@ func (lc *LeastConnectionsStrategy) SelectBackend(backends []*Backend) *Backend
 	return bestBackend
 }
 
+func (hw *HealthWeightedStrategy) SelectBackend(backends []*Backend) *Backend {
+	hw.mu.Lock()
+	defer hw.mu.Unlock()
+
+	if hw.currentWeights == nil {
+		hw.currentWeights = make(map[string]int)
+	}
+
+	var best *Backend
+	total := 0
+	for _, backend := range backends {
+		weight := hw.effectiveWeight(backend)
+		if weight <= 0 {
+			continue
+		}
+
+		total += weight
+		hw.currentWeights[backend.ID] += weight
+		if best == nil || hw.currentWeights[backend.ID] > hw.currentWeights[best.ID] {
+			best = backend
+		}
+	}
+
+	if best == nil {
+		return nil
+	}
+
+	hw.currentWeights[best.ID] -= total
+	return best
+}
+
+// effectiveWeight health skorunu (ve istenirse backend ağırlığını) tam sayı ağırlığa çevirir
+func (hw *HealthWeightedStrategy) effectiveWeight(backend *Backend) int {
+	health := backend.health()
+	if health < hw.HealthFloor || health <= 0 {
+		return 0
+	}
+
+	weight := health * 100
+	if hw.UseWeights && backend.Weight > 0 {
+		weight *= float64(backend.Weight)
+	}
+	return int(weight)
+}
+
 func NewHealthChecker(timeout time.Duration) *HealthCheckerImpl {
 	return &HealthCheckerImpl{
 		timeout: timeout,
@@ -270,7 +663,7 @@ func (hc *HealthCheckerImpl) CheckHealth(backend *Backend) error {
 
 	select {
 	case <-ctx.Done():
-		return fmt.Errorf("health check timeout")
+		return ErrHealthCheckTimeout
 	case <-time.After(time.Duration(rand.Intn(100)) * time.Millisecond):
 		if rand.Float64() < 0.05 {
 			return fmt.Errorf("simulated health check failure")
@@ -294,3 +687,14 @@ func NewWeightedRoundRobinStrategy() *WeightedRoundRobinStrategy {
 func NewLeastConnectionsStrategy() *LeastConnectionsStrategy {
 	return &LeastConnectionsStrategy{}
 }
+
+func NewHealthWeightedStrategy(healthFloor float64, useWeights bool) *HealthWeightedStrategy {
+	if healthFloor < 0 {
+		healthFloor = DefaultHealthFloor
+	}
+	return &HealthWeightedStrategy{
+		HealthFloor:    healthFloor,
+		UseWeights:     useWeights,
+		currentWeights: make(map[string]int),
+	}
+}
@@ -0,0 +1,237 @@
+package loadbalancer
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHealthWeightedStrategyDistribution(t *testing.T) {
+	tests := []struct {
+		name       string
+		useWeights bool
+		backends   []*Backend
+		rounds     int
+		want       map[string]int
+	}{
+		{
+			name: "degraded backend orantılı olarak daha az trafik alır",
+			backends: []*Backend{
+				{ID: "healthy", Health: 1.0},
+				{ID: "degraded", Health: 0.5},
+			},
+			rounds: 300,
+			want:   map[string]int{"healthy": 200, "degraded": 100},
+		},
+		{
+			name: "health floor altındaki backend atlanır",
+			backends: []*Backend{
+				{ID: "healthy", Health: 1.0},
+				{ID: "failing", Health: 0.1},
+			},
+			rounds: 100,
+			want:   map[string]int{"healthy": 100},
+		},
+		{
+			name:       "ağırlıklar health ile birleşir",
+			useWeights: true,
+			backends: []*Backend{
+				{ID: "small", Health: 1.0, Weight: 1},
+				{ID: "large", Health: 1.0, Weight: 3},
+				{ID: "large-degraded", Health: 0.5, Weight: 2},
+			},
+			rounds: 500,
+			want:   map[string]int{"small": 100, "large": 300, "large-degraded": 100},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strategy := NewHealthWeightedStrategy(DefaultHealthFloor, tt.useWeights)
+
+			got := make(map[string]int)
+			for i := 0; i < tt.rounds; i++ {
+				backend := strategy.SelectBackend(tt.backends)
+				if backend == nil {
+					t.Fatalf("%d. seçimde backend dönmedi", i)
+				}
+				got[backend.ID]++
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("seçim dağılımı = %v, beklenen %v", got, tt.want)
+			}
+			for id, want := range tt.want {
+				if got[id] != want {
+					t.Errorf("%s seçim sayısı = %d, beklenen %d", id, got[id], want)
+				}
+			}
+		})
+	}
+}
+
+func TestHealthWeightedStrategyAllBelowFloor(t *testing.T) {
+	strategy := NewHealthWeightedStrategy(DefaultHealthFloor, false)
+	backends := []*Backend{{ID: "a", Health: 0.1}, {ID: "b"}}
+
+	if backend := strategy.SelectBackend(backends); backend != nil {
+		t.Fatalf("SelectBackend = %s, beklenen nil", backend.ID)
+	}
+}
+
+// countingHealthChecker yapılan kontrolleri sayar; delay verilirse her kontrol o kadar sürer
+type countingHealthChecker struct {
+	checks atomic.Int64
+	delay  time.Duration
+}
+
+func (c *countingHealthChecker) CheckHealth(backend *Backend) error {
+	c.checks.Add(1)
+	time.Sleep(c.delay)
+	return nil
+}
+
+func TestLoadBalancerHealthCheckInterval(t *testing.T) {
+	const window = 500 * time.Millisecond
+
+	tests := []struct {
+		name      string
+		interval  time.Duration
+		minChecks int64
+		maxChecks int64
+	}{
+		{name: "kısa aralık", interval: 20 * time.Millisecond, minChecks: 10, maxChecks: 26},
+		{name: "uzun aralık", interval: 200 * time.Millisecond, minChecks: 1, maxChecks: 3},
+		{name: "varsayılan aralık pencerede kontrol yapmaz", maxChecks: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := &countingHealthChecker{}
+			lb := NewLoadBalancer(NewRoundRobinStrategy(), checker, LoadBalancerConfig{HealthCheckInterval: tt.interval})
+			defer lb.Close()
+			lb.AddBackend(&Backend{ID: "a", IsActive: true})
+
+			time.Sleep(window)
+			if got := checker.checks.Load(); got < tt.minChecks || got > tt.maxChecks {
+				t.Errorf("%v içinde %d kontrol, beklenen %d-%d", window, got, tt.minChecks, tt.maxChecks)
+			}
+		})
+	}
+}
+
+func TestLoadBalancerCheckHealthNow(t *testing.T) {
+	tests := []struct {
+		name       string
+		delay      time.Duration
+		timeout    time.Duration
+		wantActive bool
+	}{
+		{name: "zamanında biten kontrol backend'i aktif tutar", timeout: time.Second, wantActive: true},
+		{name: "zaman aşımı backend'i pasife alır", delay: 300 * time.Millisecond, timeout: 50 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := &countingHealthChecker{delay: tt.delay}
+			lb := NewLoadBalancer(NewRoundRobinStrategy(), checker, LoadBalancerConfig{HealthCheckInterval: time.Hour, HealthCheckTimeout: tt.timeout})
+			defer lb.Close()
+			lb.AddBackend(&Backend{ID: "a", IsActive: !tt.wantActive})
+
+			start := time.Now()
+			backends := lb.CheckHealthNow()
+			if elapsed := time.Since(start); elapsed > tt.timeout+100*time.Millisecond {
+				t.Errorf("CheckHealthNow %v sürdü, timeout %v", elapsed, tt.timeout)
+			}
+			if got := checker.checks.Load(); got != 1 {
+				t.Errorf("kontrol sayısı = %d, beklenen 1", got)
+			}
+			if len(backends) != 1 || backends[0].active() != tt.wantActive {
+				t.Errorf("backend aktif = %v, beklenen %v", backends[0].active(), tt.wantActive)
+			}
+		})
+	}
+}
+
+func TestLoadBalancerGetBackendFiltered(t *testing.T) {
+	backends := func() []*Backend {
+		return []*Backend{
+			{ID: "eu-primary", IsActive: true, Region: "eu", Tags: []string{"primary"}, Metadata: map[string]string{"tier": "gold"}},
+			{ID: "eu-replica", IsActive: true, Region: "eu", Tags: []string{"replica"}},
+			{ID: "us-primary", IsActive: true, Region: "us", Tags: []string{"primary", "canary"}},
+			{ID: "us-primary-down", IsActive: false, Region: "us", Tags: []string{"primary"}},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		predicate BackendPredicate
+		noBackend bool
+		wantIDs   []string
+		wantErr   error
+	}{
+		{name: "tag ile", predicate: WithTag("primary"), wantIDs: []string{"eu-primary", "us-primary"}},
+		{name: "bölge ile", predicate: InRegion("eu"), wantIDs: []string{"eu-primary", "eu-replica"}},
+		{name: "metadata ile", predicate: WithMetadata("tier", "gold"), wantIDs: []string{"eu-primary"}},
+		{name: "predicate olmadan tüm aktifler", wantIDs: []string{"eu-primary", "eu-replica", "us-primary"}},
+		{name: "yalnızca pasif backend eşleşir", predicate: func(b *Backend) bool { return b.ID == "us-primary-down" }, wantErr: ErrNoActiveBackends},
+		{name: "eşleşen tag yok", predicate: WithTag("archive"), wantErr: ErrNoActiveBackends},
+		{name: "hiç backend yok", predicate: WithTag("primary"), noBackend: true, wantErr: ErrNoBackends},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lb := NewLoadBalancer(NewRoundRobinStrategy(), &countingHealthChecker{}, LoadBalancerConfig{HealthCheckInterval: time.Hour})
+			defer lb.Close()
+			if !tt.noBackend {
+				for _, backend := range backends() {
+					lb.AddBackend(backend)
+				}
+			}
+
+			// Round robin eşleşen her backend'i sırayla döndürmeli, eşleşmeyenleri hiç döndürmemeli
+			got := make(map[string]int)
+			for i := 0; i < 2*len(tt.wantIDs)+1; i++ {
+				backend, err := lb.GetBackendFiltered(tt.predicate)
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("GetBackendFiltered = %v, beklenen %v", err, tt.wantErr)
+				}
+				if err != nil {
+					return
+				}
+				got[backend.ID]++
+			}
+			if len(got) != len(tt.wantIDs) {
+				t.Fatalf("seçilen backend'ler = %v, beklenen %v", got, tt.wantIDs)
+			}
+			for _, id := range tt.wantIDs {
+				if got[id] == 0 {
+					t.Errorf("%s hiç seçilmedi: %v", id, got)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadBalancerStopsWithRootContext(t *testing.T) {
+	root, cancelRoot := context.WithCancel(context.Background())
+	defer cancelRoot()
+
+	// Aynı köke bağlı birden fazla load balancer tek iptalle durur
+	balancers := make([]*LoadBalancer, 3)
+	for i := range balancers {
+		balancers[i] = NewLoadBalancerWithContext(root, NewRoundRobinStrategy(), &countingHealthChecker{}, LoadBalancerConfig{HealthCheckInterval: 10 * time.Millisecond})
+		defer balancers[i].Close()
+	}
+
+	cancelRoot()
+	for i, lb := range balancers {
+		select {
+		case <-lb.Done():
+		case <-time.After(time.Second):
+			t.Fatalf("%d. load balancer'ın health check goroutine'i durmadı", i+1)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package loadbalancer
+
+import (
+	"context"
+	"fmt"
+)
+
+// BackendStore backend listesinin yeniden başlatmalarda korunması için kalıcı depodur
+type BackendStore interface {
+	LoadBackends(ctx context.Context) ([]*Backend, error)
+	SaveBackend(ctx context.Context, backend *Backend) error
+	DeleteBackend(ctx context.Context, backendID string) error
+}
+
+// SetStore backend değişikliklerinin yazılacağı kalıcı depoyu bağlar
+func (lb *LoadBalancer) SetStore(store BackendStore) {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+	lb.store = store
+}
+
+// Restore store'daki backend'leri belleğe yükler; aynı id'li backend'ler store'daki
+// kayıtla değiştirilir. Yüklenen backend'ler ilk health check'e kadar aktif kabul edilir.
+func (lb *LoadBalancer) Restore(ctx context.Context) (int, error) {
+	lb.mu.RLock()
+	store := lb.store
+	lb.mu.RUnlock()
+
+	if store == nil {
+		return 0, nil
+	}
+
+	backends, err := store.LoadBackends(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load backends: %w", err)
+	}
+
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+	for _, backend := range backends {
+		backend.IsActive = true
+		backend.Health = 1.0
+		lb.upsertBackend(backend)
+	}
+	return len(backends), nil
+}
+
+// RegisterBackend backend'i önce store'a yazar, ardından belleğe ekler. Store yoksa
+// yalnızca bellekte tutulur; aynı id'li backend varsa yerine geçer.
+func (lb *LoadBalancer) RegisterBackend(ctx context.Context, backend *Backend) error {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+
+	if lb.store != nil {
+		if err := lb.store.SaveBackend(ctx, backend); err != nil {
+			return fmt.Errorf("failed to persist backend %s: %w", backend.ID, err)
+		}
+	}
+	lb.upsertBackend(backend)
+	return nil
+}
+
+// DeregisterBackend backend'i store'dan ve bellekten kaldırır
+func (lb *LoadBalancer) DeregisterBackend(ctx context.Context, backendID string) error {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+
+	if lb.store != nil {
+		if err := lb.store.DeleteBackend(ctx, backendID); err != nil {
+			return fmt.Errorf("failed to delete backend %s: %w", backendID, err)
+		}
+	}
+	lb.removeBackend(backendID)
+	return nil
+}
+
+// upsertBackend lb.mu yazma kilidi tutulurken çağrılmalıdır
+func (lb *LoadBalancer) upsertBackend(backend *Backend) {
+	for i, existing := range lb.backends {
+		if existing.ID == backend.ID {
+			lb.backends[i] = backend
+			return
+		}
+	}
+	lb.backends = append(lb.backends, backend)
+}
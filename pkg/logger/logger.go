@@ -4,6 +4,8 @@ import (
 	"os"
 	"time"
 
+	"transaction-api-w-go/pkg/domain"
+
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 )
@@ -30,3 +32,27 @@ func Info(msg string) {
 func Error(err error, msg string) {
 	log.Error().Err(err).Msg(msg)
 }
+
+// structuredLogger domain.Logger arayüzünü global zerolog logger'ı üzerinden uygular
+type structuredLogger struct{}
+
+// Structured anahtar/değer çiftleriyle log atan bileşenler (cache, worker vb.) için logger döndürür
+func Structured() domain.Logger {
+	return structuredLogger{}
+}
+
+func (structuredLogger) Info(msg string, keysAndValues ...interface{}) {
+	log.Info().Fields(keysAndValues).Msg(msg)
+}
+
+func (structuredLogger) Error(msg string, keysAndValues ...interface{}) {
+	log.Error().Fields(keysAndValues).Msg(msg)
+}
+
+func (structuredLogger) Warn(msg string, keysAndValues ...interface{}) {
+	log.Warn().Fields(keysAndValues).Msg(msg)
+}
+
+func (structuredLogger) Debug(msg string, keysAndValues ...interface{}) {
+	log.Debug().Fields(keysAndValues).Msg(msg)
+}
@@ -31,6 +31,40 @@ var (
 		[]string{"type", "status"},
 	)
 
+	// SLO metrikleri: başarı oranı transaction_successes_total / transaction_attempts_total
+	// ile hesaplanır (bkz. monitoring/prometheus/rules/slo.yml)
+	TransactionAttemptsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "transaction_attempts_total",
+			Help: "Total transaction attempts",
+		},
+		[]string{"type"},
+	)
+
+	TransactionSuccessesTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "transaction_successes_total",
+			Help: "Total successful transactions",
+		},
+		[]string{"type"},
+	)
+
+	TransactionFailuresTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "transaction_failures_total",
+			Help: "Total failed transactions by reason",
+		},
+		[]string{"type", "reason"},
+	)
+
+	HttpServerErrorsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "http_server_errors_total",
+			Help: "Total HTTP 5xx responses per endpoint",
+		},
+		[]string{"method", "path"},
+	)
+
 	TransactionAmount = promauto.NewHistogramVec(
 		prometheus.HistogramOpts{
 			Name:    "transaction_amount",
@@ -71,6 +105,38 @@ var (
 		[]string{"query_type"},
 	)
 
+	BulkheadRejectionsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "bulkhead_rejections_total",
+			Help: "Total requests rejected because the operation bulkhead was full",
+		},
+		[]string{"operation"},
+	)
+
+	WebhookDeliveriesTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "webhook_deliveries_total",
+			Help: "Total webhook delivery attempts by resulting delivery status",
+		},
+		[]string{"event", "status"},
+	)
+
+	HttpRequestsInFlight = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "http_requests_in_flight",
+			Help: "HTTP requests currently being served",
+		},
+	)
+
+	// Kapanışta bekleyen isteklerin sonucu: completed süre içinde biten, aborted bağlantısı kesilen
+	ShutdownDrainedRequestsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "http_shutdown_drained_requests_total",
+			Help: "Requests in flight at shutdown by drain result",
+		},
+		[]string{"result"},
+	)
+
 	DatabaseConnections = promauto.NewGauge(
 		prometheus.GaugeOpts{
 			Name: "database_connections",
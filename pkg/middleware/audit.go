@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"transaction-api-w-go/pkg/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// auditRecordTimeout istek iptal edilse bile audit kaydına tanınan süre
+const auditRecordTimeout = 5 * time.Second
+
+// AuditRecorder admin işlemlerinin yazıldığı depodur (ör. repository.AuditRepository)
+type AuditRecorder interface {
+	Record(ctx context.Context, entry *domain.AuditLog) error
+}
+
+// AuditMiddleware değişiklik yapan (GET/HEAD/OPTIONS dışındaki) istekleri handler
+// çalıştıktan sonra yanıt koduyla birlikte kaydeder. Başarısız denemeler de kaydedilir.
+// Kayıt hatası isteği etkilemez, yalnızca loglanır. recorder nil ise hiçbir şey yapmaz.
+func AuditMiddleware(recorder AuditRecorder) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if recorder == nil || !isMutation(c.Request.Method) {
+			return
+		}
+
+		entry := NewAuditLog(c)
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(c.Request.Context()), auditRecordTimeout)
+		defer cancel()
+
+		if err := recorder.Record(ctx, entry); err != nil {
+			log.Error().
+				Err(err).
+				Str("actor_id", entry.ActorID).
+				Str("action", entry.Action).
+				Str("request_id", entry.RequestID).
+				Msg("Audit kaydı yazılamadı")
+		}
+	}
+}
+
+// NewAuditLog isteğin aktörünü, route şablonunu ve parametrelerini audit kaydına çevirir
+func NewAuditLog(c *gin.Context) *domain.AuditLog {
+	route := c.FullPath()
+	if route == "" {
+		route = c.Request.URL.Path
+	}
+
+	targets := make([]string, 0, len(c.Params))
+	for _, param := range c.Params {
+		targets = append(targets, param.Key+"="+param.Value)
+	}
+
+	return &domain.AuditLog{
+		ID:         uuid.New(),
+		ActorID:    c.GetString("user_id"),
+		ActorRole:  c.GetString(RoleKey),
+		Action:     c.Request.Method + " " + route,
+		Target:     strings.Join(targets, ","),
+		Path:       c.Request.URL.Path,
+		StatusCode: c.Writer.Status(),
+		RequestID:  c.GetString(RequestIDKey),
+		ClientIP:   c.ClientIP(),
+		Timestamp:  time.Now(),
+	}
+}
+
+func isMutation(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	default:
+		return true
+	}
+}
@@ -42,7 +42,9 @@ func AuthMiddleware(secretKey string) gin.HandlerFunc {
 		if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
 			c.Set("user_id", claims["user_id"])
 			c.Set("email", claims["email"])
-			c.Set("role", claims["role"])
+			// Rol claim'i yoksa (eski token'lar) boş kalır ve admin yetkisi verilmez
+			role, _ := claims["role"].(string)
+			c.Set(RoleKey, role)
 			c.Next()
 		} else {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token claims"})
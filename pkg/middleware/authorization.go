@@ -4,14 +4,23 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+
+	"transaction-api-w-go/pkg/domain"
 )
 
+// RoleKey AuthMiddleware'in token'daki rol claim'ini context'e yazdığı anahtardır
+const RoleKey = "role"
+
+// HasRole isteği yapan kullanıcının token'ındaki rolü kontrol eder;
+// rol kontrolü yapan her yer (middleware ve handler'lar) aynı kaynağı okur
+func HasRole(c *gin.Context, role domain.Role) bool {
+	return c.GetString(RoleKey) == string(role)
+}
+
 func RoleMiddleware(allowedRoles ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		userRole := "admin"
-
 		for _, role := range allowedRoles {
-			if role == userRole {
+			if HasRole(c, domain.Role(role)) {
 				c.Next()
 				return
 			}
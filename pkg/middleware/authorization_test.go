@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const testSecret = "test-secret"
+
+func signToken(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(testSecret))
+	if err != nil {
+		t.Fatalf("token imzalanamadı: %v", err)
+	}
+	return token
+}
+
+func TestRoleMiddlewareUsesTokenRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name   string
+		claims jwt.MapClaims
+		want   int
+	}{
+		{
+			name:   "admin rolü geçer",
+			claims: jwt.MapClaims{"user_id": "u1", "role": "admin", "exp": time.Now().Add(time.Hour).Unix()},
+			want:   http.StatusOK,
+		},
+		{
+			name:   "user rolü reddedilir",
+			claims: jwt.MapClaims{"user_id": "u1", "role": "user", "exp": time.Now().Add(time.Hour).Unix()},
+			want:   http.StatusForbidden,
+		},
+		{
+			name:   "rol claim'i olmayan token reddedilir",
+			claims: jwt.MapClaims{"user_id": "u1", "exp": time.Now().Add(time.Hour).Unix()},
+			want:   http.StatusForbidden,
+		},
+		{
+			name:   "string olmayan rol reddedilir",
+			claims: jwt.MapClaims{"user_id": "u1", "role": 1, "exp": time.Now().Add(time.Hour).Unix()},
+			want:   http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.GET("/admin", AuthMiddleware(testSecret), RoleMiddleware("admin"), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+			req.Header.Set("Authorization", "Bearer "+signToken(t, tt.claims))
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, beklenen %d", w.Code, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"net/http"
+
+	"transaction-api-w-go/pkg/bulkhead"
+	"transaction-api-w-go/pkg/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BulkheadMiddleware route'u verilen bölmenin eşzamanlılık sınırıyla çalıştırır. Bölme doluysa
+// istek kuyruğa alınmadan 503 ile reddedilir; b nil ise sınırlama yapılmaz.
+func BulkheadMiddleware(b *bulkhead.Bulkhead) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if b == nil {
+			c.Next()
+			return
+		}
+
+		if !b.TryAcquire() {
+			metrics.BulkheadRejectionsTotal.WithLabelValues(b.Name()).Inc()
+			c.Header("Retry-After", "1")
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+				"error":     "service busy, retry later",
+				"operation": b.Name(),
+			})
+			return
+		}
+		defer b.Release()
+
+		c.Next()
+	}
+}
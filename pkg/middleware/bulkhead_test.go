@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"transaction-api-w-go/pkg/bulkhead"
+	"transaction-api-w-go/pkg/metrics"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestBulkheadMiddlewareRejectsWhenSaturated(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		limit        int
+		inFlight     int
+		nilBulkhead  bool
+		wantStatus   int
+		wantRejected float64
+	}{
+		{name: "boş bölme kabul eder", limit: 2, wantStatus: http.StatusOK},
+		{name: "sınırın altında kabul eder", limit: 2, inFlight: 1, wantStatus: http.StatusOK},
+		{name: "dolu bölmede N. istek hemen reddedilir", limit: 2, inFlight: 2, wantStatus: http.StatusServiceUnavailable, wantRejected: 1},
+		{name: "bulkhead yoksa sınır uygulanmaz", nilBulkhead: true, inFlight: 3, wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b *bulkhead.Bulkhead
+			if !tt.nilBulkhead {
+				b = bulkhead.New(bulkhead.OperationBatch, tt.limit)
+			}
+
+			// Süren batch istekleri release kapanana kadar bölmede yer tutar
+			release := make(chan struct{})
+			started := make(chan struct{}, tt.inFlight)
+			engine := gin.New()
+			engine.POST("/batch", BulkheadMiddleware(b), func(c *gin.Context) {
+				if c.Query("block") != "" {
+					started <- struct{}{}
+					<-release
+				}
+				c.Status(http.StatusOK)
+			})
+
+			var wg sync.WaitGroup
+			for i := 0; i < tt.inFlight; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					engine.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/batch?block=1", nil))
+				}()
+				<-started
+			}
+			defer func() {
+				close(release)
+				wg.Wait()
+			}()
+
+			before := testutil.ToFloat64(metrics.BulkheadRejectionsTotal.WithLabelValues(bulkhead.OperationBatch))
+			w := httptest.NewRecorder()
+			start := time.Now()
+			engine.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/batch", nil))
+			if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+				t.Errorf("istek %v sürdü, kuyruğa alınmadan dönmeliydi", elapsed)
+			}
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, beklenen %d", w.Code, tt.wantStatus)
+			}
+			if tt.wantStatus == http.StatusServiceUnavailable && w.Header().Get("Retry-After") == "" {
+				t.Errorf("Retry-After başlığı yok")
+			}
+			rejected := testutil.ToFloat64(metrics.BulkheadRejectionsTotal.WithLabelValues(bulkhead.OperationBatch)) - before
+			if rejected != tt.wantRejected {
+				t.Errorf("ret metriği %v arttı, beklenen %v", rejected, tt.wantRejected)
+			}
+		})
+	}
+}
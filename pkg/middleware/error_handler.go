@@ -3,6 +3,8 @@ package middleware
 import (
 	"net/http"
 
+	"transaction-api-w-go/pkg/metrics"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -40,5 +42,9 @@ func ErrorHandlerMiddleware() gin.HandlerFunc {
 				Code:    statusCode,
 			})
 		}
+
+		if c.Writer.Status() >= http.StatusInternalServerError {
+			metrics.HttpServerErrorsTotal.WithLabelValues(c.Request.Method, routeLabel(c)).Inc()
+		}
 	}
 }
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"strconv"
 	"time"
 
 	"transaction-api-w-go/pkg/metrics"
@@ -11,15 +12,25 @@ import (
 func MetricsMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		start := time.Now()
-		path := c.Request.URL.Path
 		method := c.Request.Method
 
 		c.Next()
 
 		duration := time.Since(start).Seconds()
-		status := c.Writer.Status()
+		status := strconv.Itoa(c.Writer.Status())
+		path := routeLabel(c)
 
-		metrics.HttpRequestsTotal.WithLabelValues(method, path, string(status)).Inc()
+		metrics.HttpRequestsTotal.WithLabelValues(method, path, status).Inc()
 		metrics.HttpRequestDuration.WithLabelValues(method, path).Observe(duration)
 	}
 }
+
+// routeLabel eşleşen route şablonunu (ör. /api/v1/transactions/:id) döner; böylece
+// farklı id'lere yapılan istekler aynı metrik serisinde toplanır. Eşleşmeyen
+// istekler için sabit bir etiket kullanılır.
+func routeLabel(c *gin.Context) string {
+	if path := c.FullPath(); path != "" {
+		return path
+	}
+	return "unmatched"
+}
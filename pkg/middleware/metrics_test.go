@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"transaction-api-w-go/pkg/metrics"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMetricsMiddlewareUsesRouteTemplate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		route      string
+		paths      []string
+		wantLabel  string
+		wantStatus string
+		wantCount  float64
+	}{
+		{
+			name:       "farklı id'ler tek seride toplanır",
+			route:      "/metrics-test/transactions/:id",
+			paths:      []string{"/metrics-test/transactions/1", "/metrics-test/transactions/2"},
+			wantLabel:  "/metrics-test/transactions/:id",
+			wantStatus: "200",
+			wantCount:  2,
+		},
+		{
+			name:       "eşleşmeyen istekler sabit etiket alır",
+			route:      "/metrics-test/known",
+			paths:      []string{"/metrics-test/missing/a", "/metrics-test/missing/b"},
+			wantLabel:  "unmatched",
+			wantStatus: "404",
+			wantCount:  2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.Use(MetricsMiddleware())
+			r.GET(tt.route, func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			counter := metrics.HttpRequestsTotal.WithLabelValues(http.MethodGet, tt.wantLabel, tt.wantStatus)
+			beforeCount := testutil.ToFloat64(counter)
+			beforeSeries := testutil.CollectAndCount(metrics.HttpRequestsTotal)
+
+			for _, path := range tt.paths {
+				r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+			}
+
+			if got := testutil.CollectAndCount(metrics.HttpRequestsTotal) - beforeSeries; got != 0 {
+				t.Errorf("beklenen seri dışında %d yeni seri oluştu", got)
+			}
+			if got := testutil.ToFloat64(counter) - beforeCount; got != tt.wantCount {
+				t.Errorf("%s sayacı %v arttı, beklenen %v", tt.wantLabel, got, tt.wantCount)
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	RequestIDHeader = "X-Request-ID"
+	// RequestIDKey istek id'sinin gin context'inde tutulduğu anahtar
+	RequestIDKey = "request_id"
+)
+
+// RequestIDMiddleware istemcinin gönderdiği X-Request-ID'yi kullanır, yoksa yeni bir id
+// üretir; id context'e yazılır ve yanıt header'ında geri döner
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if requestID == "" || len(requestID) > 64 {
+			requestID = uuid.New().String()
+		}
+
+		c.Set(RequestIDKey, requestID)
+		c.Writer.Header().Set(RequestIDHeader, requestID)
+		c.Next()
+	}
+}
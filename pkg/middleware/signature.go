@@ -0,0 +1,203 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"transaction-api-w-go/pkg/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	SignatureHeader          = "X-Signature"
+	SignatureTimestampHeader = "X-Signature-Timestamp"
+	SignatureNonceHeader     = "X-Signature-Nonce"
+
+	// DefaultSignatureWindow imzalı isteğin zaman damgası için kabul edilen en büyük sapma
+	DefaultSignatureWindow = 5 * time.Minute
+
+	nonceKeyPrefix = "request_nonce:"
+)
+
+// NonceStore kullanılmış nonce'ları saklar; SetNX anahtar zaten varsa false döndürmelidir.
+// cache.RedisCache bu arayüzü karşılar.
+type NonceStore interface {
+	SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error)
+}
+
+// SignatureConfig hangi route'ların imza gerektirdiğini ve doğrulama parametrelerini tanımlar.
+// Secret boşsa imza kontrolü tamamen kapalıdır.
+type SignatureConfig struct {
+	Secret string
+	Window time.Duration
+	// Routes "METHOD /path" biçiminde gin route şablonlarıdır, ör. "POST /api/v1/transactions/transfer"
+	Routes []string
+	Nonces NonceStore
+}
+
+// Enabled imza kontrolünün en az bir route için açık olup olmadığını döndürür
+func (c SignatureConfig) Enabled() bool {
+	return c.Secret != "" && len(c.Routes) > 0
+}
+
+// ParseSignedRoutes "POST /a,DELETE /b" biçimindeki config değerini route listesine çevirir
+func ParseSignedRoutes(spec string) []string {
+	var routes []string
+	for _, route := range strings.Split(spec, ",") {
+		route = strings.Join(strings.Fields(route), " ")
+		if route == "" {
+			continue
+		}
+		method, path, ok := strings.Cut(route, " ")
+		if !ok {
+			continue
+		}
+		routes = append(routes, strings.ToUpper(method)+" "+path)
+	}
+	return routes
+}
+
+// SignRequest istemcilerin kullanması gereken imzayı üretir:
+// hex(HMAC-SHA256(secret, METHOD \n PATH \n TIMESTAMP \n NONCE \n BODY))
+func SignRequest(secret, method, path string, body []byte, timestamp int64, nonce string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strings.ToUpper(method)))
+	mac.Write([]byte{'\n'})
+	mac.Write([]byte(path))
+	mac.Write([]byte{'\n'})
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte{'\n'})
+	mac.Write([]byte(nonce))
+	mac.Write([]byte{'\n'})
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// RequestSignatureMiddleware yapılandırılmış route'larda imzayı, zaman damgasını ve
+// nonce'ın daha önce kullanılmamış olduğunu doğrular. Idempotency'den farklı olarak
+// aynı imzalı isteğin tekrar gönderilmesini (replay) reddeder.
+func RequestSignatureMiddleware(config SignatureConfig) gin.HandlerFunc {
+	if config.Window <= 0 {
+		config.Window = DefaultSignatureWindow
+	}
+	if config.Nonces == nil {
+		config.Nonces = NewMemoryNonceStore()
+	}
+
+	routes := make(map[string]bool, len(config.Routes))
+	for _, route := range config.Routes {
+		routes[route] = true
+	}
+
+	return func(c *gin.Context) {
+		if config.Secret == "" || !routes[c.Request.Method+" "+c.FullPath()] {
+			c.Next()
+			return
+		}
+
+		if err := verifySignature(c, config); err != nil {
+			log.Warn().
+				Err(err).
+				Str("method", c.Request.Method).
+				Str("path", c.Request.URL.Path).
+				Str("ip", c.ClientIP()).
+				Msg("İmzalı istek reddedildi")
+
+			c.JSON(signatureErrorStatus(err), gin.H{"error": err.Error()})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// signatureErrorStatus imza hatalarını 401'e, nonce deposuna ulaşılamamasını 503'e eşler
+func signatureErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrInvalidSignature),
+		errors.Is(err, domain.ErrSignatureExpired),
+		errors.Is(err, domain.ErrNonceReused):
+		return http.StatusUnauthorized
+	default:
+		return http.StatusServiceUnavailable
+	}
+}
+
+func verifySignature(c *gin.Context, config SignatureConfig) error {
+	signature := c.GetHeader(SignatureHeader)
+	nonce := c.GetHeader(SignatureNonceHeader)
+	timestamp, err := strconv.ParseInt(c.GetHeader(SignatureTimestampHeader), 10, 64)
+	if signature == "" || nonce == "" || err != nil {
+		return domain.ErrInvalidSignature
+	}
+
+	skew := time.Since(time.Unix(timestamp, 0))
+	if skew > config.Window || skew < -config.Window {
+		return domain.ErrSignatureExpired
+	}
+
+	// Gövde imza için okunur ve sonraki handler'lar için geri konur
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return domain.ErrInvalidSignature
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	expected := SignRequest(config.Secret, c.Request.Method, c.Request.URL.Path, body, timestamp, nonce)
+	if !hmac.Equal([]byte(expected), []byte(strings.ToLower(signature))) {
+		return domain.ErrInvalidSignature
+	}
+
+	// Nonce, zaman damgası penceresinin her iki yönünü de kapsayacak kadar saklanır
+	fresh, err := config.Nonces.SetNX(c.Request.Context(), nonceKeyPrefix+nonce, timestamp, 2*config.Window)
+	if err != nil {
+		return err
+	}
+	if !fresh {
+		return domain.ErrNonceReused
+	}
+	return nil
+}
+
+// MemoryNonceStore Redis yapılandırılmadığında kullanılan tek instance'lık nonce deposu
+type MemoryNonceStore struct {
+	mu     sync.Mutex
+	nonces map[string]time.Time
+}
+
+func NewMemoryNonceStore() *MemoryNonceStore {
+	return &MemoryNonceStore{
+		nonces: make(map[string]time.Time),
+	}
+}
+
+func (s *MemoryNonceStore) SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for k, expiresAt := range s.nonces {
+		if now.After(expiresAt) {
+			delete(s.nonces, k)
+		}
+	}
+
+	if _, exists := s.nonces[key]; exists {
+		return false, nil
+	}
+	s.nonces[key] = now.Add(expiration)
+	return true, nil
+}
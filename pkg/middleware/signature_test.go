@@ -0,0 +1,141 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const transferPath = "/api/v1/transactions/transfer"
+
+// failingNonceStore nonce deposuna ulaşılamayan durumu taklit eder
+type failingNonceStore struct{}
+
+func (failingNonceStore) SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error) {
+	return false, errors.New("redis down")
+}
+
+// signedRequest gövdeyi verilen zaman damgası ve nonce ile imzalar
+func signedRequest(path string, body []byte, timestamp time.Time, nonce string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(body))
+	ts := timestamp.Unix()
+	req.Header.Set(SignatureHeader, SignRequest(testSecret, http.MethodPost, path, body, ts, nonce))
+	req.Header.Set(SignatureTimestampHeader, strconv.FormatInt(ts, 10))
+	req.Header.Set(SignatureNonceHeader, nonce)
+	return req
+}
+
+func TestRequestSignatureMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	body := []byte(`{"to_user_id":"u2","amount":100}`)
+
+	tests := []struct {
+		name     string
+		nonces   NonceStore
+		requests func() []*http.Request
+		// want her isteğin beklenen durum kodudur
+		want []int
+	}{
+		{
+			name:     "geçerli imzalı istek",
+			requests: func() []*http.Request { return []*http.Request{signedRequest(transferPath, body, time.Now(), "n1")} },
+			want:     []int{http.StatusOK},
+		},
+		{
+			name: "süresi geçmiş zaman damgası",
+			requests: func() []*http.Request {
+				return []*http.Request{signedRequest(transferPath, body, time.Now().Add(-2*time.Minute), "n1")}
+			},
+			want: []int{http.StatusUnauthorized},
+		},
+		{
+			name: "tekrar gönderilen nonce",
+			requests: func() []*http.Request {
+				now := time.Now()
+				return []*http.Request{signedRequest(transferPath, body, now, "n1"), signedRequest(transferPath, body, now, "n1")}
+			},
+			want: []int{http.StatusOK, http.StatusUnauthorized},
+		},
+		{
+			name: "gövdesi değiştirilmiş istek",
+			requests: func() []*http.Request {
+				req := signedRequest(transferPath, body, time.Now(), "n1")
+				req.Body = io.NopCloser(bytes.NewReader([]byte(`{"to_user_id":"u2","amount":9999}`)))
+				return []*http.Request{req}
+			},
+			want: []int{http.StatusUnauthorized},
+		},
+		{
+			name: "imzasız istek",
+			requests: func() []*http.Request {
+				return []*http.Request{httptest.NewRequest(http.MethodPost, transferPath, bytes.NewReader(body))}
+			},
+			want: []int{http.StatusUnauthorized},
+		},
+		{
+			name: "imza gerektirmeyen route",
+			requests: func() []*http.Request {
+				return []*http.Request{httptest.NewRequest(http.MethodPost, "/api/v1/transactions/credit", bytes.NewReader(body))}
+			},
+			want: []int{http.StatusOK},
+		},
+		{
+			name:     "nonce deposuna ulaşılamıyor",
+			nonces:   failingNonceStore{},
+			requests: func() []*http.Request { return []*http.Request{signedRequest(transferPath, body, time.Now(), "n1")} },
+			want:     []int{http.StatusServiceUnavailable},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.Use(RequestSignatureMiddleware(SignatureConfig{
+				Secret: testSecret,
+				Window: time.Minute,
+				Routes: ParseSignedRoutes("POST " + transferPath),
+				Nonces: tt.nonces,
+			}))
+			handler := func(c *gin.Context) {
+				// Handler imza için okunan gövdeyi yine okuyabilmeli
+				received, _ := io.ReadAll(c.Request.Body)
+				if len(received) == 0 {
+					c.Status(http.StatusBadRequest)
+					return
+				}
+				c.Status(http.StatusOK)
+			}
+			r.POST(transferPath, handler)
+			r.POST("/api/v1/transactions/credit", handler)
+
+			for i, req := range tt.requests() {
+				w := httptest.NewRecorder()
+				r.ServeHTTP(w, req)
+				if w.Code != tt.want[i] {
+					t.Errorf("istek %d: status = %d, beklenen %d (%s)", i, w.Code, tt.want[i], w.Body.String())
+				}
+			}
+		})
+	}
+}
+
+func TestParseSignedRoutes(t *testing.T) {
+	got := ParseSignedRoutes(" post  /a , DELETE /b,, invalid")
+	want := []string{"POST /a", "DELETE /b"}
+	if len(got) != len(want) {
+		t.Fatalf("route'lar = %q, beklenen %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("route %d = %q, beklenen %q", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package payment
+
+import (
+	"context"
+	"time"
+
+	"transaction-api-w-go/pkg/circuitbreaker"
+)
+
+// BreakerName ödeme ağ geçidi circuit breaker'ının adı
+const BreakerName = "payment_gateway"
+
+// DefaultBreakerConfig ağ geçidi için circuit breaker ayarları
+func DefaultBreakerConfig() circuitbreaker.Config {
+	return circuitbreaker.Config{
+		FailureThreshold:    5,
+		SuccessThreshold:    2,
+		Timeout:             30 * time.Second,
+		HalfOpenMaxRequests: 1,
+		WindowSize:          time.Minute,
+		MinRequestCount:     5,
+	}
+}
+
+// CircuitBreakerGateway ağ geçidi çağrılarını breaker üzerinden çalıştırır; sağlayıcı kesintisinde
+// talimatlar zaman aşımını beklemeden reddedilir ve işlem hemen başarısız sayılır
+type CircuitBreakerGateway struct {
+	gateway PaymentGateway
+	breaker *circuitbreaker.CircuitBreaker
+}
+
+func NewCircuitBreakerGateway(gateway PaymentGateway, breaker *circuitbreaker.CircuitBreaker) *CircuitBreakerGateway {
+	return &CircuitBreakerGateway{
+		gateway: gateway,
+		breaker: breaker,
+	}
+}
+
+// CircuitBreaker kullanılan breaker'ı döner
+func (g *CircuitBreakerGateway) CircuitBreaker() *circuitbreaker.CircuitBreaker {
+	return g.breaker
+}
+
+func (g *CircuitBreakerGateway) Deposit(ctx context.Context, deposit *Deposit) (*Result, error) {
+	return g.execute(func() (*Result, error) {
+		return g.gateway.Deposit(ctx, deposit)
+	})
+}
+
+func (g *CircuitBreakerGateway) Withdraw(ctx context.Context, withdrawal *Withdrawal) (*Result, error) {
+	return g.execute(func() (*Result, error) {
+		return g.gateway.Withdraw(ctx, withdrawal)
+	})
+}
+
+func (g *CircuitBreakerGateway) GetStatus(ctx context.Context, reference string) (*Result, error) {
+	return g.execute(func() (*Result, error) {
+		return g.gateway.GetStatus(ctx, reference)
+	})
+}
+
+func (g *CircuitBreakerGateway) execute(call func() (*Result, error)) (*Result, error) {
+	var result *Result
+	err := g.breaker.Execute(func() error {
+		var err error
+		result, err = call()
+		return err
+	})
+	return result, err
+}
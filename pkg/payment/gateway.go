@@ -0,0 +1,93 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"transaction-api-w-go/pkg/domain"
+
+	"github.com/google/uuid"
+)
+
+// Status ödeme ağ geçidindeki bir talimatın durumu
+type Status string
+
+const (
+	StatusPending Status = "pending"
+	StatusSettled Status = "settled"
+	StatusFailed  Status = "failed"
+)
+
+// ErrPaymentNotFound ağ geçidi verilen referansla bir talimat bulamadığında döner
+var ErrPaymentNotFound = errors.New("payment not found")
+
+// IsFinal durum artık değişmeyecekse true döner
+func (s Status) IsFinal() bool {
+	return s == StatusSettled || s == StatusFailed
+}
+
+// Withdrawal dış hesaba gönderilecek ödeme talimatı; TransactionID ağ geçidinde idempotency anahtarı olarak kullanılır
+type Withdrawal struct {
+	TransactionID uuid.UUID
+	Amount        float64
+	Description   string
+	Destination   domain.ExternalAccount
+}
+
+// Deposit dış hesaptan çekilecek ödeme talimatı
+type Deposit struct {
+	TransactionID uuid.UUID
+	Amount        float64
+	Description   string
+	Source        domain.ExternalAccount
+}
+
+// Result ağ geçidinin talimat için verdiği referans ve güncel durum
+type Result struct {
+	Reference string
+	Status    Status
+	Reason    string
+}
+
+// PaymentGateway sistem dışı hesaplarla para alışverişi yapan sağlayıcıyı soyutlar. Deposit ve
+// Withdraw talimatı kabul eder ama sonuçlanmasını beklemez; sonuç hemen settled/failed olarak
+// dönebilir ya da pending ise daha sonra GetStatus ile sorgulanır.
+type PaymentGateway interface {
+	Deposit(ctx context.Context, deposit *Deposit) (*Result, error)
+	Withdraw(ctx context.Context, withdrawal *Withdrawal) (*Result, error)
+	GetStatus(ctx context.Context, reference string) (*Result, error)
+}
+
+// StubGateway gerçek bir sağlayıcı bağlanana kadar kullanılan ağ geçididir: her talimatı kabul eder
+// ve sonucu pending olarak bildirir. Talimatlar hiçbir yere gönderilmez.
+type StubGateway struct{}
+
+func NewStubGateway() *StubGateway {
+	return &StubGateway{}
+}
+
+func (g *StubGateway) Deposit(ctx context.Context, deposit *Deposit) (*Result, error) {
+	return &Result{
+		Reference: stubReference(deposit.TransactionID),
+		Status:    StatusPending,
+	}, nil
+}
+
+func (g *StubGateway) Withdraw(ctx context.Context, withdrawal *Withdrawal) (*Result, error) {
+	return &Result{
+		Reference: stubReference(withdrawal.TransactionID),
+		Status:    StatusPending,
+	}, nil
+}
+
+func (g *StubGateway) GetStatus(ctx context.Context, reference string) (*Result, error) {
+	if reference == "" {
+		return nil, fmt.Errorf("%w: empty reference", ErrPaymentNotFound)
+	}
+	return &Result{Reference: reference, Status: StatusPending}, nil
+}
+
+func stubReference(transactionID uuid.UUID) string {
+	return "stub-" + transactionID.String()
+}
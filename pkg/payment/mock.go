@@ -0,0 +1,125 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"transaction-api-w-go/pkg/clock"
+
+	"github.com/google/uuid"
+)
+
+// ErrGatewayUnavailable sandbox ağ geçidi kapalıyken döner
+var ErrGatewayUnavailable = errors.New("payment gateway unavailable")
+
+// MockRejectedCents sandbox'ta kuruş kısmı bu değer olan talimatlar sonuçlanırken reddedilir;
+// gerçek sağlayıcıların test kartı/hesabı kuralına benzer şekilde başarısız akış elle tetiklenebilir
+const MockRejectedCents = 13
+
+type mockPayment struct {
+	result    Result
+	settlesAt time.Time
+}
+
+// MockGateway bellekte çalışan sandbox ağ geçididir. Talimatlar önce pending döner ve settleAfter
+// süresi dolduğunda GetStatus'ta sonuçlanır; settleAfter sıfırsa talimat anında sonuçlanır.
+// SetAvailable(false) ile kesinti canlandırılabilir.
+type MockGateway struct {
+	mu          sync.Mutex
+	payments    map[string]*mockPayment
+	settleAfter time.Duration
+	clock       clock.Clock
+	unavailable bool
+}
+
+func NewMockGateway(settleAfter time.Duration) *MockGateway {
+	return NewMockGatewayWithClock(settleAfter, clock.Real())
+}
+
+// NewMockGatewayWithClock sonuçlanma zamanını verilen saate göre hesaplar
+func NewMockGatewayWithClock(settleAfter time.Duration, clk clock.Clock) *MockGateway {
+	return &MockGateway{
+		payments:    make(map[string]*mockPayment),
+		settleAfter: settleAfter,
+		clock:       clk,
+	}
+}
+
+// SetAvailable false verildiğinde tüm çağrılar ErrGatewayUnavailable ile döner
+func (g *MockGateway) SetAvailable(available bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.unavailable = !available
+}
+
+func (g *MockGateway) Deposit(ctx context.Context, deposit *Deposit) (*Result, error) {
+	return g.submit("dep", deposit.TransactionID, deposit.Amount)
+}
+
+func (g *MockGateway) Withdraw(ctx context.Context, withdrawal *Withdrawal) (*Result, error) {
+	return g.submit("wdr", withdrawal.TransactionID, withdrawal.Amount)
+}
+
+func (g *MockGateway) GetStatus(ctx context.Context, reference string) (*Result, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.unavailable {
+		return nil, ErrGatewayUnavailable
+	}
+	payment, ok := g.payments[reference]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrPaymentNotFound, reference)
+	}
+	g.settle(payment)
+
+	result := payment.result
+	return &result, nil
+}
+
+// submit talimatı kaydeder; aynı işlem için tekrar gönderilen talimat yeni kayıt açmaz
+func (g *MockGateway) submit(prefix string, transactionID uuid.UUID, amount float64) (*Result, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.unavailable {
+		return nil, ErrGatewayUnavailable
+	}
+
+	reference := prefix + "-" + transactionID.String()
+	payment, ok := g.payments[reference]
+	if !ok {
+		payment = &mockPayment{
+			result:    Result{Reference: reference, Status: StatusPending},
+			settlesAt: g.clock.Now().Add(g.settleAfter),
+		}
+		if mockCents(amount) == MockRejectedCents {
+			payment.result.Reason = "rejected by sandbox"
+		}
+		g.payments[reference] = payment
+	}
+	g.settle(payment)
+
+	result := payment.result
+	return &result, nil
+}
+
+// settle süresi dolan pending talimatı sonuçlandırır
+func (g *MockGateway) settle(payment *mockPayment) {
+	if payment.result.Status != StatusPending || g.clock.Now().Before(payment.settlesAt) {
+		return
+	}
+	if payment.result.Reason != "" {
+		payment.result.Status = StatusFailed
+		return
+	}
+	payment.result.Status = StatusSettled
+}
+
+func mockCents(amount float64) int {
+	return int(math.Round(amount*100)) % 100
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -9,6 +10,7 @@ import (
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ScheduledTransactionRepositoryImpl struct {
@@ -20,12 +22,24 @@ func NewScheduledTransactionRepository(db *gorm.DB) domain.ScheduledTransactionR
 }
 
 func (r *ScheduledTransactionRepositoryImpl) Create(ctx context.Context, scheduledTransaction *domain.ScheduledTransaction) error {
-	return r.db.WithContext(ctx).Create(scheduledTransaction).Error
+	return dbFromContext(ctx, r.db).Create(scheduledTransaction).Error
+}
+
+// CreateMany tüm kayıtları tek bir veritabanı transaction'ı içinde oluşturur; biri başarısız olursa hiçbiri kaydedilmez
+func (r *ScheduledTransactionRepositoryImpl) CreateMany(ctx context.Context, scheduledTransactions []*domain.ScheduledTransaction) error {
+	return dbFromContext(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		for _, scheduledTransaction := range scheduledTransactions {
+			if err := tx.Create(scheduledTransaction).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 }
 
 func (r *ScheduledTransactionRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*domain.ScheduledTransaction, error) {
 	var scheduledTransaction domain.ScheduledTransaction
-	err := r.db.WithContext(ctx).Where("id = ?", id).First(&scheduledTransaction).Error
+	err := dbFromContext(ctx, r.db).Where("id = ?", id).First(&scheduledTransaction).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, domain.ErrScheduledTransactionNotFound
@@ -37,7 +51,7 @@ func (r *ScheduledTransactionRepositoryImpl) GetByID(ctx context.Context, id uui
 
 func (r *ScheduledTransactionRepositoryImpl) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.ScheduledTransaction, error) {
 	var scheduledTransactions []*domain.ScheduledTransaction
-	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("scheduled_at ASC").Find(&scheduledTransactions).Error
+	err := dbFromContext(ctx, r.db).Where("user_id = ?", userID).Order("scheduled_at ASC").Find(&scheduledTransactions).Error
 	if err != nil {
 		return nil, err
 	}
@@ -46,7 +60,7 @@ func (r *ScheduledTransactionRepositoryImpl) GetByUserID(ctx context.Context, us
 
 func (r *ScheduledTransactionRepositoryImpl) GetPendingScheduledTransactions(ctx context.Context) ([]*domain.ScheduledTransaction, error) {
 	var scheduledTransactions []*domain.ScheduledTransaction
-	err := r.db.WithContext(ctx).
+	err := dbFromContext(ctx, r.db).
 		Where("status = ? AND scheduled_at <= ?", "pending", time.Now()).
 		Order("scheduled_at ASC").
 		Find(&scheduledTransactions).Error
@@ -56,12 +70,89 @@ func (r *ScheduledTransactionRepositoryImpl) GetPendingScheduledTransactions(ctx
 	return scheduledTransactions, nil
 }
 
+func (r *ScheduledTransactionRepositoryImpl) GetRetryableScheduledTransactions(ctx context.Context, now time.Time) ([]*domain.ScheduledTransaction, error) {
+	var scheduledTransactions []*domain.ScheduledTransaction
+	err := dbFromContext(ctx, r.db).
+		Where("status = ? AND retry_count < max_retries AND next_retry_at IS NOT NULL AND next_retry_at <= ?", "failed", now).
+		Order("next_retry_at ASC").
+		Find(&scheduledTransactions).Error
+	if err != nil {
+		return nil, err
+	}
+	return scheduledTransactions, nil
+}
+
+func (r *ScheduledTransactionRepositoryImpl) GetExpiredLeaseScheduledTransactions(ctx context.Context, now time.Time) ([]*domain.ScheduledTransaction, error) {
+	var scheduledTransactions []*domain.ScheduledTransaction
+	err := dbFromContext(ctx, r.db).
+		Where("status = ? AND lease_expires_at IS NOT NULL AND lease_expires_at < ?", domain.ScheduledStatusExecuting, now).
+		Order("lease_expires_at ASC").
+		Find(&scheduledTransactions).Error
+	if err != nil {
+		return nil, err
+	}
+	return scheduledTransactions, nil
+}
+
+// ClaimScheduledTransaction kirayı tek bir koşullu UPDATE ile alır; aynı satırı okuyan birden fazla
+// zamanlayıcıdan yalnızca biri satırı güncelleyebildiği için işlem tek bir kez çalıştırılır
+func (r *ScheduledTransactionRepositoryImpl) ClaimScheduledTransaction(ctx context.Context, id uuid.UUID, owner string, now, leaseUntil time.Time) (bool, error) {
+	result := dbFromContext(ctx, r.db).
+		Model(&domain.ScheduledTransaction{}).
+		Where("id = ?", id).
+		Where("((status = ? AND scheduled_at <= ?) OR "+
+			"(status = ? AND retry_count < max_retries AND next_retry_at IS NOT NULL AND next_retry_at <= ?) OR "+
+			"(status = ? AND lease_expires_at IS NOT NULL AND lease_expires_at < ?))",
+			"pending", now,
+			"failed", now,
+			domain.ScheduledStatusExecuting, now).
+		Updates(map[string]interface{}{
+			"status":           domain.ScheduledStatusExecuting,
+			"lease_owner":      owner,
+			"lease_expires_at": leaseUntil,
+			"updated_at":       now,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
 func (r *ScheduledTransactionRepositoryImpl) Update(ctx context.Context, scheduledTransaction *domain.ScheduledTransaction) error {
-	return r.db.WithContext(ctx).Save(scheduledTransaction).Error
+	return dbFromContext(ctx, r.db).Save(scheduledTransaction).Error
+}
+
+// UpdateModifiable tüm alanları koşullu bir UPDATE ile yazar; okuma ile yazma arasında işlem
+// çalıştırılmaya alınmışsa satır güncellenmez
+func (r *ScheduledTransactionRepositoryImpl) UpdateModifiable(ctx context.Context, scheduledTransaction *domain.ScheduledTransaction) (bool, error) {
+	result := dbFromContext(ctx, r.db).
+		Model(scheduledTransaction).
+		Where("status IN ?", domain.ModifiableScheduledStatuses).
+		Select("*").
+		Omit("id", "user_id", "created_at").
+		Updates(scheduledTransaction)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
 }
 
+// Delete satırı silmez, deleted_at'i doldurur; silinen işlemler listelerden ve zamanlayıcıdan çıkar
 func (r *ScheduledTransactionRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
-	return r.db.WithContext(ctx).Where("id = ?", id).Delete(&domain.ScheduledTransaction{}).Error
+	return dbFromContext(ctx, r.db).Where("id = ?", id).Delete(&domain.ScheduledTransaction{}).Error
+}
+
+// GetByIDIncludingDeleted soft-delete ile silinmiş işlemleri de döner; denetim amaçlıdır
+func (r *ScheduledTransactionRepositoryImpl) GetByIDIncludingDeleted(ctx context.Context, id uuid.UUID) (*domain.ScheduledTransaction, error) {
+	var scheduledTransaction domain.ScheduledTransaction
+	err := dbFromContext(ctx, r.db).Unscoped().Where("id = ?", id).First(&scheduledTransaction).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.ErrScheduledTransactionNotFound
+		}
+		return nil, err
+	}
+	return &scheduledTransaction, nil
 }
 
 type BatchTransactionRepositoryImpl struct {
@@ -73,12 +164,12 @@ func NewBatchTransactionRepository(db *gorm.DB) domain.BatchTransactionRepositor
 }
 
 func (r *BatchTransactionRepositoryImpl) Create(ctx context.Context, batchTransaction *domain.BatchTransaction) error {
-	return r.db.WithContext(ctx).Create(batchTransaction).Error
+	return dbFromContext(ctx, r.db).Create(batchTransaction).Error
 }
 
 func (r *BatchTransactionRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*domain.BatchTransaction, error) {
 	var batchTransaction domain.BatchTransaction
-	err := r.db.WithContext(ctx).Where("id = ?", id).First(&batchTransaction).Error
+	err := dbFromContext(ctx, r.db).Where("id = ?", id).First(&batchTransaction).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, domain.ErrBatchTransactionNotFound
@@ -90,7 +181,7 @@ func (r *BatchTransactionRepositoryImpl) GetByID(ctx context.Context, id uuid.UU
 
 func (r *BatchTransactionRepositoryImpl) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.BatchTransaction, error) {
 	var batchTransactions []*domain.BatchTransaction
-	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at DESC").Find(&batchTransactions).Error
+	err := dbFromContext(ctx, r.db).Where("user_id = ?", userID).Order("created_at DESC").Find(&batchTransactions).Error
 	if err != nil {
 		return nil, err
 	}
@@ -98,11 +189,30 @@ func (r *BatchTransactionRepositoryImpl) GetByUserID(ctx context.Context, userID
 }
 
 func (r *BatchTransactionRepositoryImpl) Update(ctx context.Context, batchTransaction *domain.BatchTransaction) error {
-	return r.db.WithContext(ctx).Save(batchTransaction).Error
+	return dbFromContext(ctx, r.db).Save(batchTransaction).Error
 }
 
+// Delete batch'i ve kalemlerini tek bir transaction'da soft-delete ile siler; kalemler batch'siz kalmaz
 func (r *BatchTransactionRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
-	return r.db.WithContext(ctx).Where("id = ?", id).Delete(&domain.BatchTransaction{}).Error
+	return dbFromContext(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("batch_id = ?", id).Delete(&domain.BatchTransactionItem{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", id).Delete(&domain.BatchTransaction{}).Error
+	})
+}
+
+// GetByIDIncludingDeleted soft-delete ile silinmiş batch'leri de döner; denetim amaçlıdır
+func (r *BatchTransactionRepositoryImpl) GetByIDIncludingDeleted(ctx context.Context, id uuid.UUID) (*domain.BatchTransaction, error) {
+	var batchTransaction domain.BatchTransaction
+	err := dbFromContext(ctx, r.db).Unscoped().Where("id = ?", id).First(&batchTransaction).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.ErrBatchTransactionNotFound
+		}
+		return nil, err
+	}
+	return &batchTransaction, nil
 }
 
 type BatchTransactionItemRepositoryImpl struct {
@@ -114,12 +224,12 @@ func NewBatchTransactionItemRepository(db *gorm.DB) domain.BatchTransactionItemR
 }
 
 func (r *BatchTransactionItemRepositoryImpl) Create(ctx context.Context, item *domain.BatchTransactionItem) error {
-	return r.db.WithContext(ctx).Create(item).Error
+	return dbFromContext(ctx, r.db).Create(item).Error
 }
 
 func (r *BatchTransactionItemRepositoryImpl) GetByBatchID(ctx context.Context, batchID uuid.UUID) ([]*domain.BatchTransactionItem, error) {
 	var items []*domain.BatchTransactionItem
-	err := r.db.WithContext(ctx).Where("batch_id = ?", batchID).Order("created_at ASC").Find(&items).Error
+	err := dbFromContext(ctx, r.db).Where("batch_id = ?", batchID).Order("created_at ASC").Find(&items).Error
 	if err != nil {
 		return nil, err
 	}
@@ -127,11 +237,11 @@ func (r *BatchTransactionItemRepositoryImpl) GetByBatchID(ctx context.Context, b
 }
 
 func (r *BatchTransactionItemRepositoryImpl) Update(ctx context.Context, item *domain.BatchTransactionItem) error {
-	return r.db.WithContext(ctx).Save(item).Error
+	return dbFromContext(ctx, r.db).Save(item).Error
 }
 
 func (r *BatchTransactionItemRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
-	return r.db.WithContext(ctx).Where("id = ?", id).Delete(&domain.BatchTransactionItem{}).Error
+	return dbFromContext(ctx, r.db).Where("id = ?", id).Delete(&domain.BatchTransactionItem{}).Error
 }
 
 type TransactionLimitRepositoryImpl struct {
@@ -143,29 +253,130 @@ func NewTransactionLimitRepository(db *gorm.DB) domain.TransactionLimitRepositor
 }
 
 func (r *TransactionLimitRepositoryImpl) Create(ctx context.Context, limit *domain.TransactionLimit) error {
-	return r.db.WithContext(ctx).Create(limit).Error
+	return dbFromContext(ctx, r.db).Create(limit).Error
 }
 
 func (r *TransactionLimitRepositoryImpl) GetByUserIDAndCurrency(ctx context.Context, userID uuid.UUID, currency domain.Currency) (*domain.TransactionLimit, error) {
 	var limit domain.TransactionLimit
-	err := r.db.WithContext(ctx).
+	err := dbFromContext(ctx, r.db).
 		Where("user_id = ? AND currency = ?", userID, currency).
 		First(&limit).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("transaction limit not found for user %s and currency %s", userID, currency)
+			return nil, fmt.Errorf("%w for user %s and currency %s", domain.ErrTransactionLimitNotFound, userID, currency)
 		}
 		return nil, err
 	}
 	return &limit, nil
 }
 
+func (r *TransactionLimitRepositoryImpl) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.TransactionLimit, error) {
+	var limits []*domain.TransactionLimit
+	err := dbFromContext(ctx, r.db).
+		Where("user_id = ?", userID).
+		Order("currency ASC").
+		Find(&limits).Error
+	return limits, err
+}
+
+func (r *TransactionLimitRepositoryImpl) UpdateLocked(ctx context.Context, userID uuid.UUID, currency domain.Currency, seed *domain.TransactionLimit, reservation *domain.LimitReservation, fn func(limit *domain.TransactionLimit) error) error {
+	return dbFromContext(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if reservation != nil {
+			var count int64
+			err := tx.Model(&domain.LimitReservation{}).
+				Where("transaction_id = ?", reservation.TransactionID).
+				Count(&count).Error
+			if err != nil {
+				return err
+			}
+			if count > 0 {
+				return nil
+			}
+		}
+
+		if seed != nil {
+			// Eşzamanlı ilk kullanımda yalnızca bir seed yazılır, diğerleri mevcut satırı kilitler
+			err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "user_id"}, {Name: "currency"}},
+				DoNothing: true,
+			}).Create(seed).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		var limit domain.TransactionLimit
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("user_id = ? AND currency = ?", userID, currency).
+			First(&limit).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return domain.ErrTransactionLimitNotFound
+			}
+			return err
+		}
+
+		if err := fn(&limit); err != nil {
+			return err
+		}
+		if err := tx.Save(&limit).Error; err != nil {
+			return err
+		}
+
+		if reservation != nil {
+			reservation.LimitID = limit.ID
+			return tx.Create(reservation).Error
+		}
+		return nil
+	})
+}
+
+func (r *TransactionLimitRepositoryImpl) RefundLocked(ctx context.Context, transactionID uuid.UUID, fn func(limit *domain.TransactionLimit, reservation *domain.LimitReservation) error) error {
+	return dbFromContext(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var reservation domain.LimitReservation
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("transaction_id = ?", transactionID).
+			First(&reservation).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return domain.ErrLimitReservationNotFound
+			}
+			return err
+		}
+		if reservation.IsRefunded() {
+			return nil
+		}
+
+		var limit domain.TransactionLimit
+		err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", reservation.LimitID).
+			First(&limit).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return domain.ErrTransactionLimitNotFound
+			}
+			return err
+		}
+
+		if err := fn(&limit, &reservation); err != nil {
+			return err
+		}
+		if err := tx.Save(&limit).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		reservation.RefundedAt = &now
+		return tx.Save(&reservation).Error
+	})
+}
+
 func (r *TransactionLimitRepositoryImpl) Update(ctx context.Context, limit *domain.TransactionLimit) error {
-	return r.db.WithContext(ctx).Save(limit).Error
+	return dbFromContext(ctx, r.db).Save(limit).Error
 }
 
 func (r *TransactionLimitRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
-	return r.db.WithContext(ctx).Where("id = ?", id).Delete(&domain.TransactionLimit{}).Error
+	return dbFromContext(ctx, r.db).Where("id = ?", id).Delete(&domain.TransactionLimit{}).Error
 }
 
 type MultiCurrencyBalanceRepositoryImpl struct {
@@ -177,17 +388,28 @@ func NewMultiCurrencyBalanceRepository(db *gorm.DB) domain.MultiCurrencyBalanceR
 }
 
 func (r *MultiCurrencyBalanceRepositoryImpl) Create(ctx context.Context, balance *domain.MultiCurrencyBalance) error {
-	return r.db.WithContext(ctx).Create(balance).Error
+	return dbFromContext(ctx, r.db).Create(balance).Error
+}
+
+func (r *MultiCurrencyBalanceRepositoryImpl) CreateMany(ctx context.Context, balances []*domain.MultiCurrencyBalance) error {
+	return dbFromContext(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		for _, balance := range balances {
+			if err := tx.Create(balance).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 }
 
 func (r *MultiCurrencyBalanceRepositoryImpl) GetByUserIDAndCurrency(ctx context.Context, userID uuid.UUID, currency domain.Currency) (*domain.MultiCurrencyBalance, error) {
 	var balance domain.MultiCurrencyBalance
-	err := r.db.WithContext(ctx).
+	err := dbFromContext(ctx, r.db).
 		Where("user_id = ? AND currency = ?", userID, currency).
 		First(&balance).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("balance not found for user %s and currency %s", userID, currency)
+			return nil, fmt.Errorf("%w for user %s and currency %s", domain.ErrBalanceNotFound, userID, currency)
 		}
 		return nil, err
 	}
@@ -196,7 +418,7 @@ func (r *MultiCurrencyBalanceRepositoryImpl) GetByUserIDAndCurrency(ctx context.
 
 func (r *MultiCurrencyBalanceRepositoryImpl) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.MultiCurrencyBalance, error) {
 	var balances []*domain.MultiCurrencyBalance
-	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Find(&balances).Error
+	err := dbFromContext(ctx, r.db).Where("user_id = ?", userID).Find(&balances).Error
 	if err != nil {
 		return nil, err
 	}
@@ -204,9 +426,54 @@ func (r *MultiCurrencyBalanceRepositoryImpl) GetByUserID(ctx context.Context, us
 }
 
 func (r *MultiCurrencyBalanceRepositoryImpl) Update(ctx context.Context, balance *domain.MultiCurrencyBalance) error {
-	return r.db.WithContext(ctx).Save(balance).Error
+	return dbFromContext(ctx, r.db).Save(balance).Error
 }
 
 func (r *MultiCurrencyBalanceRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
-	return r.db.WithContext(ctx).Where("id = ?", id).Delete(&domain.MultiCurrencyBalance{}).Error
+	return dbFromContext(ctx, r.db).Where("id = ?", id).Delete(&domain.MultiCurrencyBalance{}).Error
+}
+
+type MultiCurrencyTransactionRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewMultiCurrencyTransactionRepository(db *gorm.DB) domain.MultiCurrencyTransactionRepository {
+	return &MultiCurrencyTransactionRepositoryImpl{db: db}
+}
+
+func (r *MultiCurrencyTransactionRepositoryImpl) ApplyConversion(ctx context.Context, record *domain.MultiCurrencyTransaction, from, to *domain.MultiCurrencyBalance) error {
+	return dbFromContext(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(from).Error; err != nil {
+			return err
+		}
+		if err := tx.Save(to).Error; err != nil {
+			return err
+		}
+		return tx.Create(record).Error
+	})
+}
+
+func (r *MultiCurrencyTransactionRepositoryImpl) ListByUserID(ctx context.Context, userID uuid.UUID, filter domain.MultiCurrencyTransactionFilter) ([]*domain.MultiCurrencyTransaction, int64, error) {
+	query := dbFromContext(ctx, r.db).Model(&domain.MultiCurrencyTransaction{}).Where("user_id = ?", userID)
+	if filter.Currency != nil {
+		query = query.Where("(from_currency = ? OR to_currency = ?)", *filter.Currency, *filter.Currency)
+	}
+	if filter.Type != nil {
+		query = query.Where("type = ?", *filter.Type)
+	}
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var records []*domain.MultiCurrencyTransaction
+	err := query.Order("created_at DESC, id DESC").
+		Limit(filter.Limit).
+		Offset(filter.Offset).
+		Find(&records).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return records, total, nil
 }
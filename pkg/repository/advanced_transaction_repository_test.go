@@ -0,0 +1,212 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"transaction-api-w-go/pkg/database/databasetest"
+	"transaction-api-w-go/pkg/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func createTestUser(t *testing.T, db *gorm.DB) *domain.User {
+	t.Helper()
+	user := &domain.User{
+		ID:        uuid.New(),
+		Password:  "x",
+		FirstName: "Test",
+		LastName:  "User",
+		Role:      domain.RoleUser,
+		LimitTier: domain.LimitTierBasic,
+	}
+	user.Email = user.ID.String() + "@example.com"
+	if err := NewUserRepository(db).Create(context.Background(), user); err != nil {
+		t.Fatalf("kullanıcı oluşturulamadı: %v", err)
+	}
+	return user
+}
+
+func TestScheduledTransactionSoftDelete(t *testing.T) {
+	tests := []struct {
+		name    string
+		deleted bool
+	}{
+		{name: "silinmemiş işlem listelenir"},
+		{name: "silinen işlem listeden çıkar ama denetim için okunur", deleted: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := databasetest.Open(t)
+			ctx := context.Background()
+			repo := NewScheduledTransactionRepository(db)
+
+			user := createTestUser(t, db)
+
+			scheduledTransaction, err := domain.NewScheduledTransaction(user.ID, domain.ScheduledTransactionRequest{
+				Type:        domain.TransactionTypeCredit,
+				Amount:      10,
+				Currency:    domain.CurrencyTRY,
+				ScheduledAt: time.Now().Add(time.Hour),
+			})
+			if err != nil {
+				t.Fatalf("NewScheduledTransaction: %v", err)
+			}
+			// Zamanı gelmiş işlem zamanlayıcının sorgusuna da girer
+			scheduledTransaction.ScheduledAt = time.Now().Add(-time.Minute)
+			if err := repo.Create(ctx, scheduledTransaction); err != nil {
+				t.Fatalf("zamanlanmış işlem yazılamadı: %v", err)
+			}
+
+			if tt.deleted {
+				if err := repo.Delete(ctx, scheduledTransaction.ID); err != nil {
+					t.Fatalf("Delete: %v", err)
+				}
+			}
+
+			listed, err := repo.GetByUserID(ctx, user.ID)
+			if err != nil {
+				t.Fatalf("GetByUserID: %v", err)
+			}
+			wantListed := 1
+			if tt.deleted {
+				wantListed = 0
+			}
+			if len(listed) != wantListed {
+				t.Errorf("listede %d işlem var, beklenen %d", len(listed), wantListed)
+			}
+			pending, err := repo.GetPendingScheduledTransactions(ctx)
+			if err != nil {
+				t.Fatalf("GetPendingScheduledTransactions: %v", err)
+			}
+			if len(pending) != wantListed {
+				t.Errorf("zamanlayıcıya %d işlem döndü, beklenen %d", len(pending), wantListed)
+			}
+
+			_, err = repo.GetByID(ctx, scheduledTransaction.ID)
+			if tt.deleted && !errors.Is(err, domain.ErrScheduledTransactionNotFound) {
+				t.Errorf("GetByID = %v, beklenen ErrScheduledTransactionNotFound", err)
+			} else if !tt.deleted && err != nil {
+				t.Errorf("GetByID: %v", err)
+			}
+
+			audited, err := repo.GetByIDIncludingDeleted(ctx, scheduledTransaction.ID)
+			if err != nil {
+				t.Fatalf("GetByIDIncludingDeleted: %v", err)
+			}
+			if audited.DeletedAt.Valid != tt.deleted {
+				t.Errorf("DeletedAt.Valid = %v, beklenen %v", audited.DeletedAt.Valid, tt.deleted)
+			}
+		})
+	}
+}
+
+func TestMultiCurrencyTransactionHistory(t *testing.T) {
+	db := databasetest.Open(t)
+	ctx := context.Background()
+	balances := NewMultiCurrencyBalanceRepository(db)
+	repo := NewMultiCurrencyTransactionRepository(db)
+	user := createTestUser(t, db)
+	other := createTestUser(t, db)
+
+	openBalance := func(userID uuid.UUID, currency domain.Currency, amount float64) *domain.MultiCurrencyBalance {
+		balance, err := domain.NewMultiCurrencyBalance(userID, currency, amount)
+		if err != nil {
+			t.Fatalf("NewMultiCurrencyBalance: %v", err)
+		}
+		if err := balances.Create(ctx, balance); err != nil {
+			t.Fatalf("bakiye açılamadı: %v", err)
+		}
+		return balance
+	}
+	try := openBalance(user.ID, domain.CurrencyTRY, 1000)
+	usd := openBalance(user.ID, domain.CurrencyUSD, 100)
+	eur := openBalance(user.ID, domain.CurrencyEUR, 0)
+
+	start := time.Now().Add(-time.Hour).Truncate(time.Second)
+	convert := func(userID uuid.UUID, txType domain.MultiCurrencyTransactionType, from, to *domain.MultiCurrencyBalance, rate, amount, fee float64, at time.Duration) *domain.MultiCurrencyTransaction {
+		record, err := domain.NewMultiCurrencyTransaction(userID, txType, &domain.ExchangeRate{
+			FromCurrency: from.Currency,
+			ToCurrency:   to.Currency,
+			Rate:         rate,
+			Source:       "test",
+		}, amount, fee)
+		if err != nil {
+			t.Fatalf("NewMultiCurrencyTransaction: %v", err)
+		}
+		record.CreatedAt = start.Add(at)
+		from.Amount -= record.FromAmount
+		to.Amount += record.ToAmount
+		if err := repo.ApplyConversion(ctx, record, from, to); err != nil {
+			t.Fatalf("ApplyConversion: %v", err)
+		}
+		return record
+	}
+	first := convert(user.ID, domain.MultiCurrencyTransactionConversion, try, usd, 0.03, 300, 10, time.Minute)
+	second := convert(user.ID, domain.MultiCurrencyTransactionConversion, usd, eur, 0.9, 50, 0, 2*time.Minute)
+	third := convert(user.ID, domain.MultiCurrencyTransactionTransfer, try, eur, 0.028, 100, 1, 3*time.Minute)
+	// Başka kullanıcının kayıtları listeye girmez
+	convert(other.ID, domain.MultiCurrencyTransactionConversion,
+		openBalance(other.ID, domain.CurrencyTRY, 100), openBalance(other.ID, domain.CurrencyUSD, 0), 0.03, 50, 0, 4*time.Minute)
+
+	eurCurrency := domain.CurrencyEUR
+	transferType := domain.MultiCurrencyTransactionTransfer
+	conversionType := domain.MultiCurrencyTransactionConversion
+
+	tests := []struct {
+		name      string
+		filter    domain.MultiCurrencyTransactionFilter
+		wantIDs   []uuid.UUID
+		wantTotal int64
+	}{
+		{name: "tüm geçmiş en yeniden başlar", filter: domain.MultiCurrencyTransactionFilter{Limit: 10}, wantIDs: []uuid.UUID{third.ID, second.ID, first.ID}, wantTotal: 3},
+		{name: "para birimine giren ve çıkanlar", filter: domain.MultiCurrencyTransactionFilter{Currency: &eurCurrency, Limit: 10}, wantIDs: []uuid.UUID{third.ID, second.ID}, wantTotal: 2},
+		{name: "yalnızca dönüşümler", filter: domain.MultiCurrencyTransactionFilter{Type: &conversionType, Limit: 10}, wantIDs: []uuid.UUID{second.ID, first.ID}, wantTotal: 2},
+		{name: "yalnızca transferler", filter: domain.MultiCurrencyTransactionFilter{Type: &transferType, Limit: 10}, wantIDs: []uuid.UUID{third.ID}, wantTotal: 1},
+		{name: "sayfalama toplamı değiştirmez", filter: domain.MultiCurrencyTransactionFilter{Limit: 1, Offset: 1}, wantIDs: []uuid.UUID{second.ID}, wantTotal: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records, total, err := repo.ListByUserID(ctx, user.ID, tt.filter)
+			if err != nil {
+				t.Fatalf("ListByUserID: %v", err)
+			}
+			if total != tt.wantTotal {
+				t.Errorf("toplam = %d, beklenen %d", total, tt.wantTotal)
+			}
+			if len(records) != len(tt.wantIDs) {
+				t.Fatalf("%d kayıt döndü, beklenen %d", len(records), len(tt.wantIDs))
+			}
+			for i, record := range records {
+				if record.ID != tt.wantIDs[i] {
+					t.Errorf("%d. kayıt = %s, beklenen %s", i, record.ID, tt.wantIDs[i])
+				}
+			}
+		})
+	}
+
+	// Kayıtla birlikte iki bakiye de güncellenir; kur ve ücret kayıtta saklanır
+	if first.ToAmount != 8.7 || first.Rate != 0.03 || first.Fee != 10 {
+		t.Errorf("ilk dönüşüm = %v @ %v, ücret %v; beklenen 8.7 @ 0.03, ücret 10", first.ToAmount, first.Rate, first.Fee)
+	}
+	wantBalances := map[domain.Currency]float64{
+		domain.CurrencyTRY: 1000 - 300 - 100,
+		domain.CurrencyUSD: 100 + 8.7 - 50,
+		domain.CurrencyEUR: 45 + 2.772,
+	}
+	for currency, want := range wantBalances {
+		balance, err := balances.GetByUserIDAndCurrency(ctx, user.ID, currency)
+		if err != nil {
+			t.Fatalf("%s bakiyesi okunamadı: %v", currency, err)
+		}
+		if math.Abs(balance.Amount-want) > 1e-9 {
+			t.Errorf("%s bakiyesi = %v, beklenen %v", currency, balance.Amount, want)
+		}
+	}
+}
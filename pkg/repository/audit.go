@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+
+	"transaction-api-w-go/pkg/domain"
+
+	"gorm.io/gorm"
+)
+
+type AuditRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditRepository(db *gorm.DB) *AuditRepository {
+	return &AuditRepository{
+		db: db,
+	}
+}
+
+func (r *AuditRepository) Record(ctx context.Context, entry *domain.AuditLog) error {
+	return dbFromContext(ctx, r.db).Create(entry).Error
+}
+
+// List filtreye uyan kayıtları en yeniden eskiye döner; toplam sayı sayfalamadan bağımsızdır
+func (r *AuditRepository) List(ctx context.Context, filter domain.AuditLogFilter) ([]domain.AuditLog, int64, error) {
+	filter.Normalize()
+
+	query := dbFromContext(ctx, r.db).Model(&domain.AuditLog{})
+	if filter.ActorID != "" {
+		query = query.Where("actor_id = ?", filter.ActorID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.Target != "" {
+		query = query.Where("target = ?", filter.Target)
+	}
+	if filter.From != nil {
+		query = query.Where("timestamp >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("timestamp <= ?", *filter.To)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var entries []domain.AuditLog
+	err := query.Order("timestamp DESC").Limit(filter.Limit).Offset(filter.Offset).Find(&entries).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
@@ -1,58 +1,155 @@
 package repository
 
 import (
+	"context"
 	"errors"
 	"time"
 
 	"transaction-api-w-go/pkg/domain"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type BalanceRepository struct {
 	db *gorm.DB
+	// baseCurrency para birimi belirtmeyen eski çağrıların (GetByUserID, SetMinimumBalance) kullandığı bakiye
+	baseCurrency string
 }
 
 func NewBalanceRepository(db *gorm.DB) *BalanceRepository {
 	return &BalanceRepository{
-		db: db,
+		db:           db,
+		baseCurrency: string(domain.CurrencyTRY),
 	}
 }
 
-func (r *BalanceRepository) Create(balance *domain.Balance) error {
-	return r.db.Create(balance).Error
+// SetBaseCurrency para birimi belirtmeyen çağrıların hangi bakiyeyi göreceğini ayarlar
+func (r *BalanceRepository) SetBaseCurrency(currency string) {
+	if currency != "" {
+		r.baseCurrency = currency
+	}
+}
+
+func (r *BalanceRepository) Create(ctx context.Context, balance *domain.Balance) error {
+	db, cancel := withQueryTimeout(ctx, r.db)
+	defer cancel()
+	return db.Create(balance).Error
+}
+
+// GetByUserID kullanıcının temel para birimindeki bakiyesini döner. Kullanıcının birden fazla para
+// biriminde bakiyesi olabileceğinden yeni kod GetByUserIDAndCurrency kullanmalıdır.
+func (r *BalanceRepository) GetByUserID(ctx context.Context, userID string) (*domain.Balance, error) {
+	return r.GetByUserIDAndCurrency(ctx, userID, r.baseCurrency)
 }
 
-func (r *BalanceRepository) GetByUserID(userID string) (*domain.Balance, error) {
+// GetByUserIDAndCurrency kullanıcının verilen para birimindeki bakiyesini döner
+func (r *BalanceRepository) GetByUserIDAndCurrency(ctx context.Context, userID, currency string) (*domain.Balance, error) {
+	db, cancel := withQueryTimeout(ctx, r.db)
+	defer cancel()
+
+	var balance domain.Balance
+	if err := db.Where("user_id = ? AND currency = ?", userID, currency).First(&balance).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrBalanceNotFound
+		}
+		return nil, err
+	}
+	return &balance, nil
+}
+
+// GetForUpdate bakiyeyi satır kilidiyle (SELECT ... FOR UPDATE) okur. TxManager.WithTx içinde
+// çağrılmalıdır; kilit transaction bitene kadar tutulur.
+func (r *BalanceRepository) GetForUpdate(ctx context.Context, id uuid.UUID) (*domain.Balance, error) {
+	db, cancel := withQueryTimeout(ctx, r.db)
+	defer cancel()
+
 	var balance domain.Balance
-	if err := r.db.Where("user_id = ?", userID).First(&balance).Error; err != nil {
+	if err := db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&balance).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("hesap bulunamadı")
+			return nil, domain.ErrBalanceNotFound
 		}
 		return nil, err
 	}
 	return &balance, nil
 }
 
-func (r *BalanceRepository) Update(balance *domain.Balance) error {
-	return r.db.Save(balance).Error
+// GetOrCreate kullanıcının verilen para birimindeki bakiyesini döner; bakiye yoksa sıfır bakiye açar.
+// Ekleme çakışmada hiçbir şey yapmadığı için aynı kullanıcıya eşzamanlı ilk işlemler tek bakiye oluşturur.
+func (r *BalanceRepository) GetOrCreate(ctx context.Context, userID, currency string) (*domain.Balance, error) {
+	balance, err := r.GetByUserIDAndCurrency(ctx, userID, currency)
+	if !errors.Is(err, domain.ErrBalanceNotFound) {
+		return balance, err
+	}
+
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, domain.ErrUserNotFound
+	}
+
+	db, cancel := withQueryTimeout(ctx, r.db)
+	now := time.Now()
+	err = db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "currency"}},
+		DoNothing: true,
+	}).Create(&domain.Balance{
+		ID:        uuid.New(),
+		UserID:    uid,
+		Currency:  currency,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}).Error
+	cancel()
+	if err != nil {
+		return nil, err
+	}
+	return r.GetByUserIDAndCurrency(ctx, userID, currency)
+}
+
+func (r *BalanceRepository) Update(ctx context.Context, balance *domain.Balance) error {
+	db, cancel := withQueryTimeout(ctx, r.db)
+	defer cancel()
+	return db.Save(balance).Error
 }
 
-func (r *BalanceRepository) GetHistory(userID string) ([]domain.BalanceHistory, error) {
+// SetMinimumBalance kullanıcının bakiyesi için minimum bakiye sınırını günceller
+func (r *BalanceRepository) SetMinimumBalance(ctx context.Context, userID string, minimum float64) error {
+	db, cancel := withQueryTimeout(ctx, r.db)
+	defer cancel()
+
+	result := db.Model(&domain.Balance{}).Where("user_id = ? AND currency = ?", userID, r.baseCurrency).
+		Updates(map[string]interface{}{"minimum_balance": minimum, "updated_at": time.Now()})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrBalanceNotFound
+	}
+	return nil
+}
+
+func (r *BalanceRepository) GetHistory(ctx context.Context, userID string) ([]domain.BalanceHistory, error) {
+	db, cancel := withQueryTimeout(ctx, r.db)
+	defer cancel()
+
 	var history []domain.BalanceHistory
-	if err := r.db.Where("user_id = ?", userID).Order("timestamp DESC").Find(&history).Error; err != nil {
+	if err := db.Where("user_id = ?", userID).Order("timestamp DESC").Find(&history).Error; err != nil {
 		return nil, err
 	}
 	return history, nil
 }
 
-func (r *BalanceRepository) GetBalanceAtTime(userID string, timestamp time.Time) (*domain.BalanceHistory, error) {
+func (r *BalanceRepository) GetBalanceAtTime(ctx context.Context, userID string, timestamp time.Time) (*domain.BalanceHistory, error) {
+	db, cancel := withQueryTimeout(ctx, r.db)
+	defer cancel()
+
 	var history domain.BalanceHistory
-	if err := r.db.Where("user_id = ? AND timestamp <= ?", userID, timestamp).
+	if err := db.Where("user_id = ? AND timestamp <= ?", userID, timestamp).
 		Order("timestamp DESC").
 		First(&history).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("belirtilen zamanda bakiye kaydı bulunamadı")
+			return nil, domain.ErrNoBalanceAtTime
 		}
 		return nil, err
 	}
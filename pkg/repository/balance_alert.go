@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"transaction-api-w-go/pkg/domain"
+
+	"gorm.io/gorm"
+)
+
+type BalanceAlertRepository struct {
+	db *gorm.DB
+}
+
+func NewBalanceAlertRepository(db *gorm.DB) *BalanceAlertRepository {
+	return &BalanceAlertRepository{
+		db: db,
+	}
+}
+
+func (r *BalanceAlertRepository) Create(ctx context.Context, rule *domain.BalanceAlertRule) error {
+	return dbFromContext(ctx, r.db).Create(rule).Error
+}
+
+func (r *BalanceAlertRepository) GetByID(ctx context.Context, id string) (*domain.BalanceAlertRule, error) {
+	var rule domain.BalanceAlertRule
+	if err := dbFromContext(ctx, r.db).Where("id = ?", id).First(&rule).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrAlertRuleNotFound
+		}
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func (r *BalanceAlertRepository) ListByUserID(ctx context.Context, userID string) ([]*domain.BalanceAlertRule, error) {
+	var rules []*domain.BalanceAlertRule
+	if err := dbFromContext(ctx, r.db).
+		Where("user_id = ?", userID).
+		Order("created_at ASC").
+		Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func (r *BalanceAlertRepository) ListActiveByUserID(ctx context.Context, userID string) ([]*domain.BalanceAlertRule, error) {
+	var rules []*domain.BalanceAlertRule
+	if err := dbFromContext(ctx, r.db).
+		Where("user_id = ? AND is_active = ?", userID, true).
+		Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func (r *BalanceAlertRepository) Update(ctx context.Context, rule *domain.BalanceAlertRule) error {
+	return dbFromContext(ctx, r.db).Save(rule).Error
+}
+
+func (r *BalanceAlertRepository) Delete(ctx context.Context, id string) error {
+	return dbFromContext(ctx, r.db).Where("id = ?", id).Delete(&domain.BalanceAlertRule{}).Error
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"transaction-api-w-go/pkg/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type BalanceHoldRepository struct {
+	db *gorm.DB
+}
+
+func NewBalanceHoldRepository(db *gorm.DB) *BalanceHoldRepository {
+	return &BalanceHoldRepository{
+		db: db,
+	}
+}
+
+func (r *BalanceHoldRepository) Create(ctx context.Context, hold *domain.BalanceHold) error {
+	return dbFromContext(ctx, r.db).Create(hold).Error
+}
+
+func (r *BalanceHoldRepository) GetByID(ctx context.Context, id string) (*domain.BalanceHold, error) {
+	var hold domain.BalanceHold
+	if err := dbFromContext(ctx, r.db).Where("id = ?", id).First(&hold).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrHoldNotFound
+		}
+		return nil, err
+	}
+	return &hold, nil
+}
+
+func (r *BalanceHoldRepository) GetActiveByUserID(ctx context.Context, userID string) ([]*domain.BalanceHold, error) {
+	var holds []*domain.BalanceHold
+	if err := dbFromContext(ctx, r.db).
+		Where("user_id = ? AND status = ?", userID, domain.HoldStatusActive).
+		Order("created_at DESC").
+		Find(&holds).Error; err != nil {
+		return nil, err
+	}
+	return holds, nil
+}
+
+// GetActiveByTransactionID işlem için ayrılmış aktif blokajı döndürür
+func (r *BalanceHoldRepository) GetActiveByTransactionID(ctx context.Context, transactionID uuid.UUID) (*domain.BalanceHold, error) {
+	var hold domain.BalanceHold
+	if err := dbFromContext(ctx, r.db).
+		Where("transaction_id = ? AND status = ?", transactionID, domain.HoldStatusActive).
+		First(&hold).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrHoldNotFound
+		}
+		return nil, err
+	}
+	return &hold, nil
+}
+
+// SumActiveByUserID kullanıcının aktif blokajlarının toplamını döndürür
+func (r *BalanceHoldRepository) SumActiveByUserID(ctx context.Context, userID string) (float64, error) {
+	var total float64
+	if err := dbFromContext(ctx, r.db).
+		Model(&domain.BalanceHold{}).
+		Where("user_id = ? AND status = ?", userID, domain.HoldStatusActive).
+		Select("COALESCE(SUM(amount), 0)").
+		Scan(&total).Error; err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+func (r *BalanceHoldRepository) Update(ctx context.Context, hold *domain.BalanceHold) error {
+	return dbFromContext(ctx, r.db).Save(hold).Error
+}
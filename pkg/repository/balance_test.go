@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"transaction-api-w-go/pkg/database/databasetest"
+	"transaction-api-w-go/pkg/domain"
+
+	"github.com/google/uuid"
+)
+
+func TestBalanceRepositoryGetByUserIDAndCurrency(t *testing.T) {
+	tests := []struct {
+		name         string
+		baseCurrency string
+		// lookup boşsa temel para birimiyle GetByUserID kullanılır
+		lookup      string
+		getOrCreate bool
+		wantAmount  float64
+		wantErr     error
+	}{
+		{name: "TRY bakiyesi", lookup: "TRY", wantAmount: 100},
+		{name: "USD bakiyesi", lookup: "USD", wantAmount: 5},
+		{name: "açılmamış para birimi", lookup: "EUR", wantErr: domain.ErrBalanceNotFound},
+		{name: "eski çağrı temel para birimini görür", wantAmount: 100},
+		{name: "temel para birimi değiştirilebilir", baseCurrency: "USD", wantAmount: 5},
+		{name: "GetOrCreate var olan bakiyeyi döner", lookup: "USD", getOrCreate: true, wantAmount: 5},
+		{name: "GetOrCreate eksik para biriminde sıfır bakiye açar", lookup: "EUR", getOrCreate: true, wantAmount: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := databasetest.Open(t)
+			ctx := context.Background()
+			repo := NewBalanceRepository(db)
+			if tt.baseCurrency != "" {
+				repo.SetBaseCurrency(tt.baseCurrency)
+			}
+
+			user := createTestUser(t, db)
+			now := time.Now()
+			for currency, amount := range map[string]float64{"TRY": 100, "USD": 5} {
+				err := repo.Create(ctx, &domain.Balance{
+					ID:        uuid.New(),
+					UserID:    user.ID,
+					Amount:    amount,
+					Currency:  currency,
+					CreatedAt: now,
+					UpdatedAt: now,
+				})
+				if err != nil {
+					t.Fatalf("%s bakiyesi açılamadı: %v", currency, err)
+				}
+			}
+
+			var balance *domain.Balance
+			var err error
+			switch {
+			case tt.getOrCreate:
+				balance, err = repo.GetOrCreate(ctx, user.ID.String(), tt.lookup)
+			case tt.lookup != "":
+				balance, err = repo.GetByUserIDAndCurrency(ctx, user.ID.String(), tt.lookup)
+			default:
+				balance, err = repo.GetByUserID(ctx, user.ID.String())
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("hata = %v, beklenen %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			wantCurrency := tt.lookup
+			if wantCurrency == "" {
+				wantCurrency = "TRY"
+				if tt.baseCurrency != "" {
+					wantCurrency = tt.baseCurrency
+				}
+			}
+			if balance.Currency != wantCurrency || balance.Amount != tt.wantAmount {
+				t.Errorf("bakiye = %v %s, beklenen %v %s", balance.Amount, balance.Currency, tt.wantAmount, wantCurrency)
+			}
+
+			// Diğer para birimlerindeki bakiyeler etkilenmez
+			var count int64
+			if err := db.Model(&domain.Balance{}).Where("user_id = ?", user.ID).Count(&count).Error; err != nil {
+				t.Fatalf("bakiyeler sayılamadı: %v", err)
+			}
+			wantCount := int64(2)
+			if tt.getOrCreate && tt.lookup == "EUR" {
+				wantCount = 3
+			}
+			if count != wantCount {
+				t.Errorf("%d bakiye kaydı var, beklenen %d", count, wantCount)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"transaction-api-w-go/pkg/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PostgresCheckpointStore struct {
+	db *gorm.DB
+}
+
+func NewPostgresCheckpointStore(db *gorm.DB) *PostgresCheckpointStore {
+	return &PostgresCheckpointStore{
+		db: db,
+	}
+}
+
+func (s *PostgresCheckpointStore) Get(ctx context.Context, projectionName string) (*domain.ProjectionCheckpoint, error) {
+	var checkpoint domain.ProjectionCheckpoint
+	err := dbFromContext(ctx, s.db).Where("projection_name = ?", projectionName).First(&checkpoint).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &checkpoint, nil
+}
+
+// Save checkpoint'i upsert eder
+func (s *PostgresCheckpointStore) Save(ctx context.Context, checkpoint *domain.ProjectionCheckpoint) error {
+	checkpoint.UpdatedAt = time.Now()
+	return dbFromContext(ctx, s.db).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "projection_name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"last_timestamp", "last_event_id", "processed_count", "updated_at"}),
+	}).Create(checkpoint).Error
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+
+	"transaction-api-w-go/pkg/domain"
+
+	"gorm.io/gorm"
+)
+
+// txContextKey WithTx'in açtığı veritabanı transaction'ının context'te saklandığı anahtar
+type txContextKey struct{}
+
+// dbFromContext context'te WithTx ile açılmış bir transaction varsa sorguyu onun üzerinde,
+// yoksa repository'nin kendi bağlantısı üzerinde çalıştırır
+func dbFromContext(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txContextKey{}).(*gorm.DB); ok && tx != nil {
+		return tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
+
+// withQueryTimeout isteğin context'ini (ve deadline'ını) sorguya bağlar; context'in
+// deadline'ı yoksa domain.DefaultQueryTimeout uygulanır. cancel her zaman çağrılmalıdır.
+func withQueryTimeout(ctx context.Context, db *gorm.DB) (*gorm.DB, context.CancelFunc) {
+	ctx, cancel := domain.WithDefaultTimeout(ctx, domain.DefaultQueryTimeout)
+	return dbFromContext(ctx, db), cancel
+}
+
+// TxManager birden fazla repository çağrısını tek bir veritabanı transaction'ında birleştirir
+type TxManager struct {
+	db *gorm.DB
+}
+
+func NewTxManager(db *gorm.DB) *TxManager {
+	return &TxManager{db: db}
+}
+
+// WithTx bir transaction açar ve fn'e transaction'ı taşıyan context'i verir; bu context'le
+// çağrılan tüm repository'ler aynı transaction'da çalışır. fn hata dönerse veya panik olursa
+// tüm yazımlar geri alınır. Zaten bir transaction içindeyken çağrılırsa savepoint kullanılır,
+// böylece iç blok dış transaction'ı bozmadan geri alınabilir.
+func (m *TxManager) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return dbFromContext(ctx, m.db).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txContextKey{}, tx))
+	})
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"transaction-api-w-go/pkg/database/databasetest"
+	"transaction-api-w-go/pkg/domain"
+
+	"github.com/google/uuid"
+)
+
+func TestTxManagerWithTx(t *testing.T) {
+	errComposed := errors.New("bileşik işlem başarısız")
+
+	tests := []struct {
+		name      string
+		fail      error
+		panics    bool
+		wantErr   error
+		wantSaved bool
+	}{
+		{name: "başarılı işlem yazılır", wantSaved: true},
+		{name: "hata tüm yazımları geri alır", fail: errComposed, wantErr: errComposed},
+		{name: "panik tüm yazımları geri alır", panics: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := databasetest.Open(t)
+			userRepo := NewUserRepository(db)
+			balanceRepo := NewBalanceRepository(db)
+			transactionRepo := NewTransactionRepository(db)
+			txManager := NewTxManager(db)
+
+			now := time.Now()
+			user := &domain.User{
+				ID:        uuid.New(),
+				Password:  "x",
+				FirstName: "Test",
+				LastName:  "User",
+				Role:      domain.RoleUser,
+				LimitTier: domain.LimitTierBasic,
+				CreatedAt: now,
+				UpdatedAt: now,
+			}
+			user.Email = user.ID.String() + "@example.com"
+			balance := &domain.Balance{ID: uuid.New(), UserID: user.ID, Amount: 10, Currency: string(domain.CurrencyTRY), CreatedAt: now, UpdatedAt: now}
+			transaction := &domain.Transaction{
+				ID:        uuid.New(),
+				UserID:    user.ID,
+				Type:      domain.TransactionTypeCredit,
+				Amount:    10,
+				Status:    string(domain.TransactionStateCompleted),
+				CreatedAt: now,
+				UpdatedAt: now,
+			}
+
+			var err error
+			func() {
+				defer func() {
+					if r := recover(); r != nil && !tt.panics {
+						panic(r)
+					}
+				}()
+				err = txManager.WithTx(context.Background(), func(ctx context.Context) error {
+					if err := userRepo.Create(ctx, user); err != nil {
+						return err
+					}
+					if err := balanceRepo.Create(ctx, balance); err != nil {
+						return err
+					}
+					if err := transactionRepo.Create(ctx, transaction); err != nil {
+						return err
+					}
+					if tt.panics {
+						panic("bileşik işlem paniği")
+					}
+					return tt.fail
+				})
+			}()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("WithTx = %v, beklenen %v", err, tt.wantErr)
+			}
+
+			for _, table := range []string{"users", "balances", "transactions"} {
+				var count int64
+				if err := db.Table(table).Count(&count).Error; err != nil {
+					t.Fatalf("%s sayılamadı: %v", table, err)
+				}
+				want := int64(0)
+				if tt.wantSaved {
+					want = 1
+				}
+				if count != want {
+					t.Errorf("%s kayıt sayısı = %d, beklenen %d", table, count, want)
+				}
+			}
+		})
+	}
+}
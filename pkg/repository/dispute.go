@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"transaction-api-w-go/pkg/domain"
+
+	"gorm.io/gorm"
+)
+
+type DisputeRepository struct {
+	db *gorm.DB
+}
+
+func NewDisputeRepository(db *gorm.DB) *DisputeRepository {
+	return &DisputeRepository{
+		db: db,
+	}
+}
+
+// CreateForTransaction itirazı kaydeder ve işlemi aynı veritabanı transaction'ında disputed olarak işaretler.
+// Eşzamanlı bir istek aynı işlem için itiraz açtıysa ErrDisputeAlreadyOpen döner.
+func (r *DisputeRepository) CreateForTransaction(ctx context.Context, dispute *domain.Dispute) error {
+	return dbFromContext(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(dispute).Error; err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				return domain.ErrDisputeAlreadyOpen
+			}
+			return err
+		}
+		return tx.Model(&domain.Transaction{}).
+			Where("id = ?", dispute.TransactionID).
+			Update("disputed", true).Error
+	})
+}
+
+// Resolve itirazı günceller ve işlemin disputed işaretini kaldırır
+func (r *DisputeRepository) Resolve(ctx context.Context, dispute *domain.Dispute) error {
+	return dbFromContext(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(dispute).Error; err != nil {
+			return err
+		}
+		return tx.Model(&domain.Transaction{}).
+			Where("id = ?", dispute.TransactionID).
+			Update("disputed", false).Error
+	})
+}
+
+func (r *DisputeRepository) GetByID(ctx context.Context, id string) (*domain.Dispute, error) {
+	var dispute domain.Dispute
+	if err := dbFromContext(ctx, r.db).Where("id = ?", id).First(&dispute).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrDisputeNotFound
+		}
+		return nil, err
+	}
+	return &dispute, nil
+}
+
+func (r *DisputeRepository) HasOpenDispute(ctx context.Context, transactionID string) (bool, error) {
+	var count int64
+	if err := dbFromContext(ctx, r.db).
+		Model(&domain.Dispute{}).
+		Where("transaction_id = ? AND status = ?", transactionID, domain.DisputeStatusOpen).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *DisputeRepository) ListByStatus(ctx context.Context, status domain.DisputeStatus) ([]*domain.Dispute, error) {
+	var disputes []*domain.Dispute
+	if err := dbFromContext(ctx, r.db).
+		Where("status = ?", status).
+		Order("opened_at ASC").
+		Find(&disputes).Error; err != nil {
+		return nil, err
+	}
+	return disputes, nil
+}
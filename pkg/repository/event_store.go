@@ -2,13 +2,14 @@ package repository
 
 import (
 	"context"
-	"database/sql"
 	"encoding/json"
 	"fmt"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"transaction-api-w-go/pkg/domain"
 )
@@ -28,16 +29,51 @@ func (EventStoreModel) TableName() string {
 	return "event_store"
 }
 
+// DeadLetterEventModel çözümlenemeyen event'in ham halini ve hata nedenini saklar
+type DeadLetterEventModel struct {
+	ID          uuid.UUID        `json:"id" gorm:"primaryKey;type:uuid"`
+	Type        domain.EventType `json:"type" gorm:"type:varchar(100);not null"`
+	AggregateID uuid.UUID        `json:"aggregate_id" gorm:"type:uuid;not null;index"`
+	Version     int64            `json:"version" gorm:"not null"`
+	Timestamp   time.Time        `json:"timestamp" gorm:"not null"`
+	Data        json.RawMessage  `json:"data" gorm:"type:jsonb"`
+	Metadata    json.RawMessage  `json:"metadata" gorm:"type:jsonb"`
+	Error       string           `json:"error" gorm:"type:text;not null"`
+	CreatedAt   time.Time        `json:"created_at" gorm:"not null"`
+}
+
+func (DeadLetterEventModel) TableName() string {
+	return "event_dead_letters"
+}
+
+// DeserializationMode çözümlenemeyen event'lerin okuma sırasında nasıl ele alınacağını belirler
+type DeserializationMode string
+
+const (
+	// DeserializationStrict ilk bozuk event'te okumayı hatayla sonlandırır
+	DeserializationStrict DeserializationMode = "strict"
+	// DeserializationLenient bozuk event'i loglayıp dead-letter tablosuna yazar ve kalanlarla devam eder
+	DeserializationLenient DeserializationMode = "lenient"
+)
+
 type PostgresEventStore struct {
-	db *gorm.DB
+	db   *gorm.DB
+	mode DeserializationMode
 }
 
 func NewPostgresEventStore(db *gorm.DB) domain.EventStore {
-	return &PostgresEventStore{db: db}
+	return NewPostgresEventStoreWithMode(db, DeserializationStrict)
+}
+
+func NewPostgresEventStoreWithMode(db *gorm.DB, mode DeserializationMode) domain.EventStore {
+	if mode != DeserializationLenient {
+		mode = DeserializationStrict
+	}
+	return &PostgresEventStore{db: db, mode: mode}
 }
 
 func (es *PostgresEventStore) SaveEvents(ctx context.Context, aggregateID uuid.UUID, events []domain.Event, expectedVersion int64) error {
-	return es.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+	return dbFromContext(ctx, es.db).Transaction(func(tx *gorm.DB) error {
 		// Optimistic concurrency control
 		var currentVersion int64
 		err := tx.Model(&EventStoreModel{}).
@@ -54,22 +90,9 @@ func (es *PostgresEventStore) SaveEvents(ctx context.Context, aggregateID uuid.U
 		}
 
 		for i, event := range events {
-			eventModel := EventStoreModel{
-				ID:          event.GetID(),
-				Type:        event.GetType(),
-				AggregateID: event.GetAggregateID(),
-				Version:     expectedVersion + int64(i) + 1,
-				Timestamp:   event.GetTimestamp(),
-				Data:        event.GetData(),
-				CreatedAt:   time.Now(),
-			}
-
-			if event.GetMetadata() != nil {
-				metadata, err := json.Marshal(event.GetMetadata())
-				if err != nil {
-					return fmt.Errorf("failed to marshal metadata: %w", err)
-				}
-				eventModel.Metadata = metadata
+			eventModel, err := newEventStoreModel(event, expectedVersion+int64(i)+1)
+			if err != nil {
+				return err
 			}
 
 			if err := tx.Create(&eventModel).Error; err != nil {
@@ -81,10 +104,95 @@ func (es *PostgresEventStore) SaveEvents(ctx context.Context, aggregateID uuid.U
 	})
 }
 
+// appendBatchSize AppendEvents'in tek INSERT ifadesinde yazdığı en fazla satır sayısı
+const appendBatchSize = 500
+
+func (es *PostgresEventStore) AppendEvents(ctx context.Context, appends []domain.EventAppend) error {
+	if len(appends) == 0 {
+		return nil
+	}
+
+	aggregateIDs := make([]uuid.UUID, 0, len(appends))
+	for _, req := range appends {
+		aggregateIDs = append(aggregateIDs, req.AggregateID)
+	}
+
+	return dbFromContext(ctx, es.db).Transaction(func(tx *gorm.DB) error {
+		// Tüm aggregate'lerin mevcut sürümleri tek sorguda okunur
+		var rows []struct {
+			AggregateID uuid.UUID
+			Version     int64
+		}
+		err := tx.Model(&EventStoreModel{}).
+			Select("aggregate_id, MAX(version) AS version").
+			Where("aggregate_id IN ?", aggregateIDs).
+			Group("aggregate_id").
+			Scan(&rows).Error
+		if err != nil {
+			return fmt.Errorf("failed to get current versions: %w", err)
+		}
+
+		versions := make(map[uuid.UUID]int64, len(rows))
+		for _, row := range rows {
+			versions[row.AggregateID] = row.Version
+		}
+
+		var models []EventStoreModel
+		for _, req := range appends {
+			currentVersion := versions[req.AggregateID]
+			if req.ExpectedVersion != domain.AnyVersion && req.ExpectedVersion != currentVersion {
+				return fmt.Errorf("concurrent modification detected for aggregate %s: expected version %d, got %d",
+					req.AggregateID, req.ExpectedVersion, currentVersion)
+			}
+
+			for _, event := range req.Events {
+				currentVersion++
+				eventModel, err := newEventStoreModel(event, currentVersion)
+				if err != nil {
+					return err
+				}
+				models = append(models, eventModel)
+			}
+			// Aynı aggregate çağrıda birden fazla kez geçerse sürümler ardışık devam eder
+			versions[req.AggregateID] = currentVersion
+		}
+
+		if len(models) == 0 {
+			return nil
+		}
+		if err := tx.CreateInBatches(models, appendBatchSize).Error; err != nil {
+			return fmt.Errorf("failed to append events: %w", err)
+		}
+		return nil
+	})
+}
+
+func newEventStoreModel(event domain.Event, version int64) (EventStoreModel, error) {
+	eventModel := EventStoreModel{
+		ID:          event.GetID(),
+		Type:        event.GetType(),
+		AggregateID: event.GetAggregateID(),
+		Version:     version,
+		Timestamp:   event.GetTimestamp(),
+		Data:        event.GetData(),
+		CreatedAt:   time.Now(),
+	}
+
+	if event.GetMetadata() != nil {
+		metadata, err := json.Marshal(event.GetMetadata())
+		if err != nil {
+			return EventStoreModel{}, fmt.Errorf("failed to marshal metadata: %w", err)
+		}
+		eventModel.Metadata = metadata
+	}
+
+	return eventModel, nil
+}
+
 func (es *PostgresEventStore) GetEvents(ctx context.Context, aggregateID uuid.UUID) ([]domain.Event, error) {
 	var eventModels []EventStoreModel
 
-	err := es.db.WithContext(ctx).
+	err := dbFromContext(ctx, es.db).
 		Where("aggregate_id = ?", aggregateID).
 		Order("version ASC").
 		Find(&eventModels).Error
@@ -93,22 +201,13 @@ func (es *PostgresEventStore) GetEvents(ctx context.Context, aggregateID uuid.UU
 		return nil, fmt.Errorf("failed to get events: %w", err)
 	}
 
-	events := make([]domain.Event, len(eventModels))
-	for i, model := range eventModels {
-		event, err := es.deserializeEvent(model)
-		if err != nil {
-			return nil, fmt.Errorf("failed to deserialize event: %w", err)
-		}
-		events[i] = event
-	}
-
-	return events, nil
+	return es.deserializeEvents(ctx, eventModels)
 }
 
 func (es *PostgresEventStore) GetEventsByType(ctx context.Context, eventType domain.EventType, limit, offset int) ([]domain.Event, error) {
 	var eventModels []EventStoreModel
 
-	err := es.db.WithContext(ctx).
+	err := dbFromContext(ctx, es.db).
 		Where("type = ?", eventType).
 		Order("timestamp ASC").
 		Limit(limit).
@@ -119,22 +218,13 @@ func (es *PostgresEventStore) GetEventsByType(ctx context.Context, eventType dom
 		return nil, fmt.Errorf("failed to get events by type: %w", err)
 	}
 
-	events := make([]domain.Event, len(eventModels))
-	for i, model := range eventModels {
-		event, err := es.deserializeEvent(model)
-		if err != nil {
-			return nil, fmt.Errorf("failed to deserialize event: %w", err)
-		}
-		events[i] = event
-	}
-
-	return events, nil
+	return es.deserializeEvents(ctx, eventModels)
 }
 
 func (es *PostgresEventStore) GetEventsByTimeRange(ctx context.Context, startTime, endTime time.Time) ([]domain.Event, error) {
 	var eventModels []EventStoreModel
 
-	err := es.db.WithContext(ctx).
+	err := dbFromContext(ctx, es.db).
 		Where("timestamp BETWEEN ? AND ?", startTime, endTime).
 		Order("timestamp ASC").
 		Find(&eventModels).Error
@@ -143,22 +233,13 @@ func (es *PostgresEventStore) GetEventsByTimeRange(ctx context.Context, startTim
 		return nil, fmt.Errorf("failed to get events by time range: %w", err)
 	}
 
-	events := make([]domain.Event, len(eventModels))
-	for i, model := range eventModels {
-		event, err := es.deserializeEvent(model)
-		if err != nil {
-			return nil, fmt.Errorf("failed to deserialize event: %w", err)
-		}
-		events[i] = event
-	}
-
-	return events, nil
+	return es.deserializeEvents(ctx, eventModels)
 }
 
 func (es *PostgresEventStore) GetAllEvents(ctx context.Context, limit, offset int) ([]domain.Event, error) {
 	var eventModels []EventStoreModel
 
-	err := es.db.WithContext(ctx).
+	err := dbFromContext(ctx, es.db).
 		Order("timestamp ASC").
 		Limit(limit).
 		Offset(offset).
@@ -168,22 +249,13 @@ func (es *PostgresEventStore) GetAllEvents(ctx context.Context, limit, offset in
 		return nil, fmt.Errorf("failed to get all events: %w", err)
 	}
 
-	events := make([]domain.Event, len(eventModels))
-	for i, model := range eventModels {
-		event, err := es.deserializeEvent(model)
-		if err != nil {
-			return nil, fmt.Errorf("failed to deserialize event: %w", err)
-		}
-		events[i] = event
-	}
-
-	return events, nil
+	return es.deserializeEvents(ctx, eventModels)
 }
 
 func (es *PostgresEventStore) GetEventCount(ctx context.Context, aggregateID uuid.UUID) (int64, error) {
 	var count int64
 
-	err := es.db.WithContext(ctx).
+	err := dbFromContext(ctx, es.db).
 		Model(&EventStoreModel{}).
 		Where("aggregate_id = ?", aggregateID).
 		Count(&count).Error
@@ -195,6 +267,153 @@ func (es *PostgresEventStore) GetEventCount(ctx context.Context, aggregateID uui
 	return count, nil
 }
 
+func (es *PostgresEventStore) CountEventsByType(ctx context.Context, eventType domain.EventType) (int64, error) {
+	var count int64
+
+	err := dbFromContext(ctx, es.db).
+		Model(&EventStoreModel{}).
+		Where("type = ?", eventType).
+		Count(&count).Error
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to count events by type: %w", err)
+	}
+
+	return count, nil
+}
+
+func (es *PostgresEventStore) CountAllEvents(ctx context.Context) (int64, error) {
+	var count int64
+
+	err := dbFromContext(ctx, es.db).
+		Model(&EventStoreModel{}).
+		Count(&count).Error
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to count events: %w", err)
+	}
+
+	return count, nil
+}
+
+type eventTypeCount struct {
+	Type  domain.EventType
+	Count int64
+}
+
+func (es *PostgresEventStore) CountEventsGroupedByType(ctx context.Context) (map[domain.EventType]int64, error) {
+	var rows []eventTypeCount
+
+	err := dbFromContext(ctx, es.db).
+		Model(&EventStoreModel{}).
+		Select("type, COUNT(*) AS count").
+		Group("type").
+		Scan(&rows).Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to count events by type: %w", err)
+	}
+
+	return eventTypeCountMap(rows), nil
+}
+
+func (es *PostgresEventStore) CountAggregatesByFirstEventType(ctx context.Context) (map[domain.EventType]int64, error) {
+	var rows []eventTypeCount
+
+	// Her aggregate'in en küçük versiyonlu event'i idx_aggregate_version üzerinden seçilir;
+	// sayım tamamen veritabanında yapılır
+	err := dbFromContext(ctx, es.db).Raw(`
+		SELECT e.type AS type, COUNT(DISTINCT e.aggregate_id) AS count
+		FROM event_store e
+		JOIN (
+			SELECT aggregate_id, MIN(version) AS version
+			FROM event_store
+			GROUP BY aggregate_id
+		) AS first_events ON first_events.aggregate_id = e.aggregate_id AND first_events.version = e.version
+		GROUP BY e.type`).
+		Scan(&rows).Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to count aggregates by first event type: %w", err)
+	}
+
+	return eventTypeCountMap(rows), nil
+}
+
+func eventTypeCountMap(rows []eventTypeCount) map[domain.EventType]int64 {
+	counts := make(map[domain.EventType]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Type] = row.Count
+	}
+	return counts
+}
+
+func (es *PostgresEventStore) GetEventsAfter(ctx context.Context, startTime, endTime time.Time, after *domain.EventCursor, limit int) ([]domain.Event, error) {
+	var eventModels []EventStoreModel
+
+	query := dbFromContext(ctx, es.db).Where("timestamp BETWEEN ? AND ?", startTime, endTime)
+	if after != nil {
+		query = query.Where("(timestamp > ?) OR (timestamp = ? AND id > ?)", after.Timestamp, after.Timestamp, after.ID)
+	}
+
+	err := query.
+		Order("timestamp ASC").
+		Order("id ASC").
+		Limit(limit).
+		Find(&eventModels).Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get events after cursor: %w", err)
+	}
+
+	return es.deserializeEvents(ctx, eventModels)
+}
+
+// deserializeEvents model'leri event'lere çevirir; lenient modda çözümlenemeyen event'ler
+// dead-letter tablosuna yazılıp atlanır, böylece tek bir bozuk kayıt tüm okumayı durdurmaz
+func (es *PostgresEventStore) deserializeEvents(ctx context.Context, models []EventStoreModel) ([]domain.Event, error) {
+	events := make([]domain.Event, 0, len(models))
+	for _, model := range models {
+		event, err := es.deserializeEvent(model)
+		if err != nil {
+			if es.mode != DeserializationLenient {
+				return nil, fmt.Errorf("failed to deserialize event %s: %w", model.ID, err)
+			}
+			es.deadLetter(ctx, model, err)
+			continue
+		}
+		events = append(events, event)
+	}
+
+	return events, nil
+}
+
+func (es *PostgresEventStore) deadLetter(ctx context.Context, model EventStoreModel, cause error) {
+	log.Error().
+		Err(cause).
+		Str("event_id", model.ID.String()).
+		Str("event_type", string(model.Type)).
+		Str("aggregate_id", model.AggregateID.String()).
+		Msg("Skipping undeserializable event")
+
+	deadLetter := DeadLetterEventModel{
+		ID:          model.ID,
+		Type:        model.Type,
+		AggregateID: model.AggregateID,
+		Version:     model.Version,
+		Timestamp:   model.Timestamp,
+		Data:        model.Data,
+		Metadata:    model.Metadata,
+		Error:       cause.Error(),
+		CreatedAt:   time.Now(),
+	}
+	// Aynı event her okumada tekrar karşılaşılır; ilk kayıt korunur
+	err := dbFromContext(ctx, es.db).Clauses(clause.OnConflict{DoNothing: true}).Create(&deadLetter).Error
+	if err != nil {
+		log.Error().Err(err).Str("event_id", model.ID.String()).Msg("Failed to write dead-letter event")
+	}
+}
+
 func (es *PostgresEventStore) deserializeEvent(model EventStoreModel) (domain.Event, error) {
 	baseEvent := domain.BaseEvent{
 		ID:          model.ID,
@@ -282,7 +501,7 @@ func (r *EventRepository) GetTransaction(ctx context.Context, id uuid.UUID) (*do
 	}
 
 	if len(events) == 0 {
-		return nil, sql.ErrNoRows
+		return nil, domain.ErrTransactionNotFound
 	}
 
 	transaction := &domain.EventSourcedTransaction{}
@@ -300,7 +519,7 @@ func (r *EventRepository) GetBalance(ctx context.Context, id uuid.UUID) (*domain
 	}
 
 	if len(events) == 0 {
-		return nil, sql.ErrNoRows
+		return nil, domain.ErrBalanceNotFound
 	}
 
 	balance := &domain.EventSourcedBalance{}
@@ -0,0 +1,210 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"transaction-api-w-go/pkg/database/databasetest"
+	"transaction-api-w-go/pkg/domain"
+
+	"github.com/google/uuid"
+)
+
+func TestPostgresEventStoreStatisticsMatchBruteForce(t *testing.T) {
+	transactionStream := func() []domain.Event {
+		transaction := &domain.Transaction{ID: uuid.New(), UserID: uuid.New(), Type: domain.TransactionTypeCredit, Amount: 10}
+		return []domain.Event{domain.NewTransactionCreatedEvent(transaction)}
+	}
+	balanceStream := func() []domain.Event {
+		balance := &domain.Balance{ID: uuid.New(), UserID: uuid.New(), Amount: 100, Currency: string(domain.CurrencyTRY)}
+		credit := domain.NewBalanceUpdatedEvent(balance, 100, 50, "credit", uuid.New())
+		debit := domain.NewBalanceUpdatedEvent(balance, 150, -20, "debit", uuid.New())
+		// BalanceUpdatedEvent Data alanını doldurmaz; data kolonu boş geçilemez
+		credit.Data, debit.Data = json.RawMessage(`{}`), json.RawMessage(`{}`)
+		return []domain.Event{domain.NewBalanceCreatedEvent(balance), credit, debit}
+	}
+
+	tests := []struct {
+		name    string
+		streams []func() []domain.Event
+	}{
+		{name: "boş store"},
+		{name: "tek aggregate", streams: []func() []domain.Event{transactionStream}},
+		{name: "karışık aggregate'ler", streams: []func() []domain.Event{transactionStream, balanceStream, transactionStream, balanceStream, balanceStream}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			store := NewPostgresEventStore(databasetest.Open(t))
+
+			for _, stream := range tt.streams {
+				events := stream()
+				if err := store.SaveEvents(ctx, events[0].GetAggregateID(), events, 0); err != nil {
+					t.Fatalf("SaveEvents: %v", err)
+				}
+			}
+
+			// Karşılaştırma için tüm event'ler belleğe alınıp sayılır
+			all, err := store.GetAllEvents(ctx, 1000, 0)
+			if err != nil {
+				t.Fatalf("GetAllEvents: %v", err)
+			}
+			wantByType := make(map[domain.EventType]int64)
+			firstEvents := make(map[uuid.UUID]domain.Event)
+			for _, event := range all {
+				wantByType[event.GetType()]++
+				if first, ok := firstEvents[event.GetAggregateID()]; !ok || event.GetVersion() < first.GetVersion() {
+					firstEvents[event.GetAggregateID()] = event
+				}
+			}
+			wantByFirst := make(map[domain.EventType]int64)
+			for _, event := range firstEvents {
+				wantByFirst[event.GetType()]++
+			}
+
+			byType, err := store.CountEventsGroupedByType(ctx)
+			if err != nil {
+				t.Fatalf("CountEventsGroupedByType: %v", err)
+			}
+			if !reflect.DeepEqual(byType, wantByType) {
+				t.Errorf("tipe göre sayım = %v, beklenen %v", byType, wantByType)
+			}
+
+			byFirst, err := store.CountAggregatesByFirstEventType(ctx)
+			if err != nil {
+				t.Fatalf("CountAggregatesByFirstEventType: %v", err)
+			}
+			if !reflect.DeepEqual(byFirst, wantByFirst) {
+				t.Errorf("ilk event tipine göre aggregate sayımı = %v, beklenen %v", byFirst, wantByFirst)
+			}
+		})
+	}
+}
+
+func BenchmarkPostgresEventStoreAppend(b *testing.B) {
+	const aggregatesPerOp = 20
+
+	newAppends := func() []domain.EventAppend {
+		appends := make([]domain.EventAppend, aggregatesPerOp)
+		for i := range appends {
+			transaction := &domain.Transaction{ID: uuid.New(), UserID: uuid.New(), Type: domain.TransactionTypeCredit, Amount: 10}
+			appends[i] = domain.EventAppend{AggregateID: transaction.ID, Events: []domain.Event{domain.NewTransactionCreatedEvent(transaction)}}
+		}
+		return appends
+	}
+
+	benchmarks := []struct {
+		name   string
+		append func(ctx context.Context, store domain.EventStore, appends []domain.EventAppend) error
+	}{
+		{
+			name: "event başına SaveEvents",
+			append: func(ctx context.Context, store domain.EventStore, appends []domain.EventAppend) error {
+				for _, a := range appends {
+					if err := store.SaveEvents(ctx, a.AggregateID, a.Events, a.ExpectedVersion); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+		{
+			name: "toplu AppendEvents",
+			append: func(ctx context.Context, store domain.EventStore, appends []domain.EventAppend) error {
+				return store.AppendEvents(ctx, appends)
+			},
+		},
+	}
+
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			ctx := context.Background()
+			store := NewPostgresEventStore(databasetest.Open(b))
+
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				appends := newAppends()
+				b.StartTimer()
+				if err := bm.append(ctx, store, appends); err != nil {
+					b.Fatalf("append: %v", err)
+				}
+			}
+			b.ReportMetric(float64(b.N*aggregatesPerOp)/b.Elapsed().Seconds(), "events/s")
+		})
+	}
+}
+
+func TestPostgresEventStoreDeserializationModes(t *testing.T) {
+	tests := []struct {
+		name           string
+		mode           DeserializationMode
+		wantErr        bool
+		wantEvents     int
+		wantDeadLetter int64
+	}{
+		{name: "strict modda okuma durur", mode: DeserializationStrict, wantErr: true},
+		{name: "lenient modda bozuk event dead-letter'a alınır", mode: DeserializationLenient, wantEvents: 2, wantDeadLetter: 1},
+		{name: "bilinmeyen mod strict davranır", mode: "bilinmeyen", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := databasetest.Open(t)
+			ctx := context.Background()
+			store := NewPostgresEventStoreWithMode(db, tt.mode)
+
+			aggregateID := uuid.New()
+			now := time.Now().Truncate(time.Second)
+			corruptID := uuid.New()
+			for version, data := range []string{`{"amount":10}`, `{"amount":"bozuk"}`, `{"amount":30}`} {
+				model := &EventStoreModel{
+					ID:          uuid.New(),
+					Type:        domain.EventTransactionCreated,
+					AggregateID: aggregateID,
+					Version:     int64(version + 1),
+					Timestamp:   now,
+					Data:        json.RawMessage(data),
+					CreatedAt:   now,
+				}
+				if version == 1 {
+					model.ID = corruptID
+				}
+				if err := db.Create(model).Error; err != nil {
+					t.Fatalf("event yazılamadı: %v", err)
+				}
+			}
+
+			// Aynı bozuk event iki kez okunsa da dead-letter tablosuna bir kez yazılır
+			for i := 0; i < 2; i++ {
+				events, err := store.GetEvents(ctx, aggregateID)
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("GetEvents hatası = %v, hata bekleniyor: %v", err, tt.wantErr)
+				}
+				if len(events) != tt.wantEvents {
+					t.Fatalf("event sayısı = %d, beklenen %d", len(events), tt.wantEvents)
+				}
+				for _, event := range events {
+					if event.GetID() == corruptID {
+						t.Fatalf("bozuk event okumada döndü")
+					}
+				}
+			}
+
+			var deadLetters []DeadLetterEventModel
+			if err := db.Find(&deadLetters).Error; err != nil {
+				t.Fatalf("dead-letter kayıtları okunamadı: %v", err)
+			}
+			if int64(len(deadLetters)) != tt.wantDeadLetter {
+				t.Fatalf("dead-letter sayısı = %d, beklenen %d", len(deadLetters), tt.wantDeadLetter)
+			}
+			if tt.wantDeadLetter > 0 && (deadLetters[0].ID != corruptID || deadLetters[0].Error == "") {
+				t.Errorf("dead-letter = %s %q, beklenen %s ve hata nedeni", deadLetters[0].ID, deadLetters[0].Error, corruptID)
+			}
+		})
+	}
+}
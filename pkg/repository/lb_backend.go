@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"transaction-api-w-go/pkg/loadbalancer"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// LoadBalancerBackendModel admin endpoint'leriyle eklenen backend'lerin kalıcı kaydı
+type LoadBalancerBackendModel struct {
+	ID        string          `json:"id" gorm:"primaryKey;type:varchar(100)"`
+	URL       string          `json:"url" gorm:"type:varchar(255);not null"`
+	Weight    int             `json:"weight" gorm:"not null;default:0"`
+	Region    string          `json:"region" gorm:"type:varchar(50)"`
+	Tags      json.RawMessage `json:"tags" gorm:"type:jsonb"`
+	Metadata  json.RawMessage `json:"metadata" gorm:"type:jsonb"`
+	CreatedAt time.Time       `json:"created_at" gorm:"not null"`
+	UpdatedAt time.Time       `json:"updated_at" gorm:"not null"`
+}
+
+func (LoadBalancerBackendModel) TableName() string {
+	return "ha_load_balancer_backends"
+}
+
+type PostgresBackendStore struct {
+	db *gorm.DB
+}
+
+func NewPostgresBackendStore(db *gorm.DB) *PostgresBackendStore {
+	return &PostgresBackendStore{
+		db: db,
+	}
+}
+
+func (s *PostgresBackendStore) LoadBackends(ctx context.Context) ([]*loadbalancer.Backend, error) {
+	var models []LoadBalancerBackendModel
+	if err := s.db.WithContext(ctx).Order("created_at ASC").Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	backends := make([]*loadbalancer.Backend, 0, len(models))
+	for _, model := range models {
+		backend := &loadbalancer.Backend{
+			ID:     model.ID,
+			URL:    model.URL,
+			Weight: model.Weight,
+			Region: model.Region,
+		}
+		if len(model.Tags) > 0 {
+			if err := json.Unmarshal(model.Tags, &backend.Tags); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal tags of backend %s: %w", model.ID, err)
+			}
+		}
+		if len(model.Metadata) > 0 {
+			if err := json.Unmarshal(model.Metadata, &backend.Metadata); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal metadata of backend %s: %w", model.ID, err)
+			}
+		}
+		backends = append(backends, backend)
+	}
+	return backends, nil
+}
+
+// SaveBackend backend'i upsert eder; health ve latency gibi çalışma zamanı alanları saklanmaz
+func (s *PostgresBackendStore) SaveBackend(ctx context.Context, backend *loadbalancer.Backend) error {
+	tags, err := json.Marshal(backend.Tags)
+	if err != nil {
+		return fmt.Errorf("failed to marshal tags: %w", err)
+	}
+	metadata, err := json.Marshal(backend.Metadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+
+	now := time.Now()
+	model := &LoadBalancerBackendModel{
+		ID:        backend.ID,
+		URL:       backend.URL,
+		Weight:    backend.Weight,
+		Region:    backend.Region,
+		Tags:      tags,
+		Metadata:  metadata,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	return s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"url", "weight", "region", "tags", "metadata", "updated_at"}),
+	}).Create(model).Error
+}
+
+func (s *PostgresBackendStore) DeleteBackend(ctx context.Context, backendID string) error {
+	return s.db.WithContext(ctx).Delete(&LoadBalancerBackendModel{}, "id = ?", backendID).Error
+}
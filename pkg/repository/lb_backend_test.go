@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"transaction-api-w-go/pkg/database/databasetest"
+	"transaction-api-w-go/pkg/loadbalancer"
+)
+
+// noopHealthChecker her backend'i sağlıklı sayar
+type noopHealthChecker struct{}
+
+func (noopHealthChecker) CheckHealth(backend *loadbalancer.Backend) error { return nil }
+
+func TestPostgresBackendStoreSurvivesRestart(t *testing.T) {
+	primary := &loadbalancer.Backend{ID: "eu-1", URL: "http://eu-1:8080", Weight: 2, Region: "eu", Tags: []string{"primary"}, Metadata: map[string]string{"rack": "a"}}
+	replica := &loadbalancer.Backend{ID: "eu-2", URL: "http://eu-2:8080", Weight: 1, Region: "eu", Tags: []string{"replica"}}
+	updated := &loadbalancer.Backend{ID: "eu-1", URL: "http://eu-1:9090", Weight: 5, Region: "eu", Tags: []string{"primary", "canary"}}
+
+	tests := []struct {
+		name       string
+		register   []*loadbalancer.Backend
+		deregister []string
+		want       []*loadbalancer.Backend
+	}{
+		{name: "eklenen backend'ler geri yüklenir", register: []*loadbalancer.Backend{primary, replica}, want: []*loadbalancer.Backend{primary, replica}},
+		{name: "silinen backend geri gelmez", register: []*loadbalancer.Backend{primary, replica}, deregister: []string{"eu-2"}, want: []*loadbalancer.Backend{primary}},
+		{name: "güncellenen backend son haliyle yüklenir", register: []*loadbalancer.Backend{primary, updated}, want: []*loadbalancer.Backend{updated}},
+		{name: "boş store"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			db := databasetest.Open(t)
+			newLoadBalancer := func() *loadbalancer.LoadBalancer {
+				lb := loadbalancer.NewLoadBalancer(loadbalancer.NewRoundRobinStrategy(), noopHealthChecker{}, loadbalancer.LoadBalancerConfig{HealthCheckInterval: time.Hour})
+				lb.SetStore(NewPostgresBackendStore(db))
+				return lb
+			}
+
+			before := newLoadBalancer()
+			for _, backend := range tt.register {
+				if err := before.RegisterBackend(ctx, backend); err != nil {
+					t.Fatalf("RegisterBackend(%s): %v", backend.ID, err)
+				}
+			}
+			for _, id := range tt.deregister {
+				if err := before.DeregisterBackend(ctx, id); err != nil {
+					t.Fatalf("DeregisterBackend(%s): %v", id, err)
+				}
+			}
+			before.Close()
+
+			// Yeniden başlatma: açılışta migration'lar tekrar çalışır, yeni load balancer yalnızca
+			// veritabanını paylaşır
+			databasetest.Migrate(t, db)
+			after := newLoadBalancer()
+			defer after.Close()
+			restored, err := after.Restore(ctx)
+			if err != nil {
+				t.Fatalf("Restore: %v", err)
+			}
+			if restored != len(tt.want) {
+				t.Fatalf("geri yüklenen = %d, beklenen %d", restored, len(tt.want))
+			}
+
+			got := make(map[string]*loadbalancer.Backend)
+			for _, backend := range after.GetBackends() {
+				got[backend.ID] = backend
+			}
+			for _, want := range tt.want {
+				backend, ok := got[want.ID]
+				if !ok {
+					t.Fatalf("%s geri yüklenmedi", want.ID)
+				}
+				if backend.URL != want.URL || backend.Weight != want.Weight || backend.Region != want.Region {
+					t.Errorf("%s = %s/%d/%s, beklenen %s/%d/%s", want.ID, backend.URL, backend.Weight, backend.Region, want.URL, want.Weight, want.Region)
+				}
+				if !reflect.DeepEqual(backend.Tags, want.Tags) || len(backend.Metadata) != len(want.Metadata) || backend.Metadata["rack"] != want.Metadata["rack"] {
+					t.Errorf("%s tag/metadata = %v/%v, beklenen %v/%v", want.ID, backend.Tags, backend.Metadata, want.Tags, want.Metadata)
+				}
+				if !backend.IsActive {
+					t.Errorf("%s geri yüklemeden sonra aktif değil", want.ID)
+				}
+			}
+			if after.ActiveBackendCount() != len(tt.want) {
+				t.Errorf("aktif backend = %d, beklenen %d", after.ActiveBackendCount(), len(tt.want))
+			}
+		})
+	}
+}
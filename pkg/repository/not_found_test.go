@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"transaction-api-w-go/pkg/database/databasetest"
+	"transaction-api-w-go/pkg/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func TestRepositoriesReturnNotFound(t *testing.T) {
+	missing := uuid.New()
+
+	tests := []struct {
+		name    string
+		lookup  func(ctx context.Context, db *gorm.DB) error
+		wantErr error
+	}{
+		{
+			name: "kullanıcı",
+			lookup: func(ctx context.Context, db *gorm.DB) error {
+				_, err := NewUserRepository(db).GetByID(ctx, missing.String())
+				return err
+			},
+			wantErr: domain.ErrUserNotFound,
+		},
+		{
+			name: "işlem",
+			lookup: func(ctx context.Context, db *gorm.DB) error {
+				_, err := NewTransactionRepository(db).GetByUUID(ctx, missing)
+				return err
+			},
+			wantErr: domain.ErrTransactionNotFound,
+		},
+		{
+			name: "bakiye",
+			lookup: func(ctx context.Context, db *gorm.DB) error {
+				_, err := NewBalanceRepository(db).GetByUserID(ctx, missing.String())
+				return err
+			},
+			wantErr: domain.ErrBalanceNotFound,
+		},
+		{
+			name: "para birimi bakiyesi",
+			lookup: func(ctx context.Context, db *gorm.DB) error {
+				_, err := NewMultiCurrencyBalanceRepository(db).GetByUserIDAndCurrency(ctx, missing, domain.CurrencyUSD)
+				return err
+			},
+			wantErr: domain.ErrBalanceNotFound,
+		},
+		{
+			name: "blokaj",
+			lookup: func(ctx context.Context, db *gorm.DB) error {
+				_, err := NewBalanceHoldRepository(db).GetByID(ctx, missing.String())
+				return err
+			},
+			wantErr: domain.ErrHoldNotFound,
+		},
+		{
+			name: "bakiye alarmı",
+			lookup: func(ctx context.Context, db *gorm.DB) error {
+				_, err := NewBalanceAlertRepository(db).GetByID(ctx, missing.String())
+				return err
+			},
+			wantErr: domain.ErrAlertRuleNotFound,
+		},
+		{
+			name: "itiraz",
+			lookup: func(ctx context.Context, db *gorm.DB) error {
+				_, err := NewDisputeRepository(db).GetByID(ctx, missing.String())
+				return err
+			},
+			wantErr: domain.ErrDisputeNotFound,
+		},
+		{
+			name: "dekont",
+			lookup: func(ctx context.Context, db *gorm.DB) error {
+				_, err := NewReceiptRepository(db).GetByTransactionID(ctx, missing.String())
+				return err
+			},
+			wantErr: domain.ErrReceiptNotFound,
+		},
+		{
+			name: "mutabakat raporu",
+			lookup: func(ctx context.Context, db *gorm.DB) error {
+				_, err := NewReconciliationRepository(db).GetLatestReport(ctx)
+				return err
+			},
+			wantErr: domain.ErrReportNotFound,
+		},
+		{
+			name: "zamanlanmış işlem",
+			lookup: func(ctx context.Context, db *gorm.DB) error {
+				_, err := NewScheduledTransactionRepository(db).GetByID(ctx, missing)
+				return err
+			},
+			wantErr: domain.ErrScheduledTransactionNotFound,
+		},
+		{
+			name: "batch",
+			lookup: func(ctx context.Context, db *gorm.DB) error {
+				_, err := NewBatchTransactionRepository(db).GetByID(ctx, missing)
+				return err
+			},
+			wantErr: domain.ErrBatchTransactionNotFound,
+		},
+		{
+			name: "işlem limiti",
+			lookup: func(ctx context.Context, db *gorm.DB) error {
+				_, err := NewTransactionLimitRepository(db).GetByUserIDAndCurrency(ctx, missing, domain.CurrencyTRY)
+				return err
+			},
+			wantErr: domain.ErrTransactionLimitNotFound,
+		},
+		{
+			name: "webhook teslimatı",
+			lookup: func(ctx context.Context, db *gorm.DB) error {
+				_, err := NewWebhookDeliveryRepository(db).GetByID(ctx, missing)
+				return err
+			},
+			wantErr: domain.ErrWebhookDeliveryNotFound,
+		},
+		{
+			name: "event sourced işlem",
+			lookup: func(ctx context.Context, db *gorm.DB) error {
+				_, err := NewEventRepository(NewPostgresEventStore(db)).GetTransaction(ctx, missing)
+				return err
+			},
+			wantErr: domain.ErrTransactionNotFound,
+		},
+		{
+			name: "event sourced bakiye",
+			lookup: func(ctx context.Context, db *gorm.DB) error {
+				_, err := NewEventRepository(NewPostgresEventStore(db)).GetBalance(ctx, missing)
+				return err
+			},
+			wantErr: domain.ErrBalanceNotFound,
+		},
+	}
+
+	db := databasetest.Open(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.lookup(context.Background(), db)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("hata = %v, beklenen %v", err, tt.wantErr)
+			}
+			if !errors.Is(err, domain.ErrNotFound) {
+				t.Errorf("hata = %v, ErrNotFound'u sarmalı", err)
+			}
+		})
+	}
+}
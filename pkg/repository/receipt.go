@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"transaction-api-w-go/pkg/domain"
+
+	"gorm.io/gorm"
+)
+
+type ReceiptRepository struct {
+	db *gorm.DB
+}
+
+func NewReceiptRepository(db *gorm.DB) *ReceiptRepository {
+	return &ReceiptRepository{
+		db: db,
+	}
+}
+
+// Create makbuzu kaydeder; (user_id, sequence) tekil index'i eşzamanlı yazımların
+// zinciri çatallamasını engeller
+func (r *ReceiptRepository) Create(ctx context.Context, receipt *domain.TransactionReceipt) error {
+	return dbFromContext(ctx, r.db).Create(receipt).Error
+}
+
+func (r *ReceiptRepository) GetByTransactionID(ctx context.Context, transactionID string) (*domain.TransactionReceipt, error) {
+	var receipt domain.TransactionReceipt
+	if err := dbFromContext(ctx, r.db).Where("transaction_id = ?", transactionID).First(&receipt).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrReceiptNotFound
+		}
+		return nil, err
+	}
+	return &receipt, nil
+}
+
+// GetLatestByUserID zincirin son halkasını döndürür; kullanıcının makbuzu yoksa nil, nil döner
+func (r *ReceiptRepository) GetLatestByUserID(ctx context.Context, userID string) (*domain.TransactionReceipt, error) {
+	var receipt domain.TransactionReceipt
+	if err := dbFromContext(ctx, r.db).
+		Where("user_id = ?", userID).
+		Order("sequence DESC").
+		First(&receipt).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &receipt, nil
+}
+
+func (r *ReceiptRepository) ListByUserID(ctx context.Context, userID string) ([]*domain.TransactionReceipt, error) {
+	var receipts []*domain.TransactionReceipt
+	if err := dbFromContext(ctx, r.db).
+		Where("user_id = ?", userID).
+		Order("sequence ASC").
+		Find(&receipts).Error; err != nil {
+		return nil, err
+	}
+	return receipts, nil
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"transaction-api-w-go/pkg/domain"
+
+	"gorm.io/gorm"
+)
+
+// ledgerDeltasQuery tamamlanmış işlemlerin (arşivlenenler dahil) kullanıcı bazında bakiye etkisini üretir.
+// Transfer'ler ve transfer ücretleri (FEE) gönderen için borç, counterparty (alıcı veya ücret hesabı)
+// için alacak olarak sayılır.
+const ledgerDeltasQuery = `
+	SELECT user_id, CASE WHEN type = 'CREDIT' THEN amount ELSE -amount END AS delta
+	FROM (
+		SELECT user_id, counterparty_id, type, amount, status FROM transactions
+		UNION ALL
+		SELECT user_id, counterparty_id, type, amount, status FROM transactions_archive
+	) all_tx
+	WHERE status = 'completed'
+	UNION ALL
+	SELECT counterparty_id AS user_id, amount AS delta
+	FROM (
+		SELECT counterparty_id, type, amount, status FROM transactions
+		UNION ALL
+		SELECT counterparty_id, type, amount, status FROM transactions_archive
+	) all_transfers
+	WHERE status = 'completed' AND type IN ('TRANSFER', 'FEE') AND counterparty_id IS NOT NULL`
+
+type ReconciliationRepository struct {
+	db *gorm.DB
+}
+
+func NewReconciliationRepository(db *gorm.DB) *ReconciliationRepository {
+	return &ReconciliationRepository{
+		db: db,
+	}
+}
+
+// FindDiscrepancies saklanan bakiyesi işlem geçmişinden hesaplanan bakiyeden tolerance'tan
+// fazla sapan kullanıcıları ve kontrol edilen toplam kullanıcı sayısını döner
+func (r *ReconciliationRepository) FindDiscrepancies(ctx context.Context, tolerance float64) (domain.BalanceDiscrepancies, int64, error) {
+	var checked int64
+	if err := dbFromContext(ctx, r.db).Model(&domain.Balance{}).Count(&checked).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var discrepancies domain.BalanceDiscrepancies
+	err := dbFromContext(ctx, r.db).Raw(`
+		SELECT b.user_id AS user_id,
+			COALESCE(SUM(l.delta), 0) AS expected,
+			b.amount AS actual,
+			b.amount - COALESCE(SUM(l.delta), 0) AS difference
+		FROM balances b
+		LEFT JOIN (`+ledgerDeltasQuery+`) l ON l.user_id = b.user_id
+		GROUP BY b.user_id, b.amount
+		HAVING ABS(b.amount - COALESCE(SUM(l.delta), 0)) > ?`, tolerance).
+		Scan(&discrepancies).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return discrepancies, checked, nil
+}
+
+func (r *ReconciliationRepository) SaveReport(ctx context.Context, report *domain.ReconciliationReport) error {
+	return dbFromContext(ctx, r.db).Create(report).Error
+}
+
+func (r *ReconciliationRepository) GetLatestReport(ctx context.Context) (*domain.ReconciliationReport, error) {
+	var report domain.ReconciliationReport
+	if err := dbFromContext(ctx, r.db).Order("run_at DESC").First(&report).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrReportNotFound
+		}
+		return nil, err
+	}
+	return &report, nil
+}
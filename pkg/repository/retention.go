@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"transaction-api-w-go/pkg/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type RetentionRepository struct {
+	db *gorm.DB
+}
+
+func NewRetentionRepository(db *gorm.DB) *RetentionRepository {
+	return &RetentionRepository{
+		db: db,
+	}
+}
+
+// PurgeTransactions cutoff'tan eski ve terminal durumdaki işlemlerden en fazla batchSize
+// kadarını arşivler veya siler. Bekleyen ve itiraz edilmiş işlemlere ve itiraz kaydı olan işlemlere
+// dokunulmaz. Makbuzlar yerinde kalır ve zincir doğrulaması arşivdeki işleme bakar; bu yüzden makbuzu
+// olan işlemler arşivlenir ama silme modunda korunur. Soft-delete ile silinmiş işlemler de kapsanır ve
+// satırlar kalıcı olarak silinir.
+func (r *RetentionRepository) PurgeTransactions(ctx context.Context, cutoff time.Time, batchSize int, mode domain.RetentionMode) (int64, error) {
+	var affected int64
+	err := dbFromContext(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		query := tx.Unscoped().Where("created_at < ? AND status IN ? AND disputed = ?", cutoff, domain.TerminalTransactionStates, false).
+			Where("NOT EXISTS (SELECT 1 FROM disputes d WHERE d.transaction_id = transactions.id)")
+		if mode != domain.RetentionModeArchive {
+			query = query.Where("NOT EXISTS (SELECT 1 FROM transaction_receipts r WHERE r.transaction_id = transactions.id)")
+		}
+
+		var transactions []*domain.Transaction
+		if err := query.Order("created_at ASC").
+			Limit(batchSize).
+			Find(&transactions).Error; err != nil {
+			return err
+		}
+		if len(transactions) == 0 {
+			return nil
+		}
+
+		if mode == domain.RetentionModeArchive {
+			if err := tx.Table("transactions_archive").Create(&transactions).Error; err != nil {
+				return err
+			}
+		}
+
+		ids := make([]string, 0, len(transactions))
+		for _, transaction := range transactions {
+			ids = append(ids, transaction.ID.String())
+		}
+
+		result := tx.Unscoped().Where("id IN ?", ids).Delete(&domain.Transaction{})
+		affected = result.RowsAffected
+		return result.Error
+	})
+	return affected, err
+}
+
+// PurgeEvents cutoff'tan eski event'lerden yalnızca aggregate'i bu versiyonu kapsayan bir
+// snapshot'a sahip olanları arşivler veya siler; böylece aggregate'ler yeniden kurulabilir kalır.
+func (r *RetentionRepository) PurgeEvents(ctx context.Context, cutoff time.Time, batchSize int, mode domain.RetentionMode) (int64, error) {
+	var affected int64
+	err := dbFromContext(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var events []*EventStoreModel
+		if err := tx.Where("timestamp < ?", cutoff).
+			Where("EXISTS (SELECT 1 FROM aggregate_snapshots s WHERE s.aggregate_id = event_store.aggregate_id AND s.version >= event_store.version)").
+			Order("timestamp ASC").
+			Limit(batchSize).
+			Find(&events).Error; err != nil {
+			return err
+		}
+		if len(events) == 0 {
+			return nil
+		}
+
+		if mode == domain.RetentionModeArchive {
+			if err := tx.Table("event_store_archive").Create(&events).Error; err != nil {
+				return err
+			}
+		}
+
+		ids := make([]uuid.UUID, 0, len(events))
+		for _, event := range events {
+			ids = append(ids, event.ID)
+		}
+
+		result := tx.Where("id IN ?", ids).Delete(&EventStoreModel{})
+		affected = result.RowsAffected
+		return result.Error
+	})
+	return affected, err
+}
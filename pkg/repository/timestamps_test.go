@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"transaction-api-w-go/pkg/database/databasetest"
+	"transaction-api-w-go/pkg/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func TestUpdateRefreshesUpdatedAt(t *testing.T) {
+	// stale çağıranın constructor'da verdiği ve güncellemede yenilemediği zaman damgasıdır
+	stale := time.Date(2024, 1, 1, 12, 0, 0, 123456789, time.FixedZone("UTC+3", 3*60*60))
+
+	type entity struct {
+		save   func(ctx context.Context) error
+		reload func(ctx context.Context) (createdAt, updatedAt time.Time)
+	}
+
+	tests := []struct {
+		name   string
+		create func(t *testing.T, db *gorm.DB, userID uuid.UUID) entity
+	}{
+		{
+			name: "işlem",
+			create: func(t *testing.T, db *gorm.DB, userID uuid.UUID) entity {
+				repo := NewTransactionRepository(db)
+				transaction := &domain.Transaction{
+					ID:        uuid.New(),
+					UserID:    userID,
+					Type:      domain.TransactionTypeCredit,
+					Amount:    10,
+					Status:    string(domain.TransactionStatePending),
+					CreatedAt: stale,
+					UpdatedAt: stale,
+				}
+				if err := repo.Create(context.Background(), transaction); err != nil {
+					t.Fatalf("işlem yazılamadı: %v", err)
+				}
+				return entity{
+					save: func(ctx context.Context) error {
+						transaction.Status = string(domain.TransactionStateCompleted)
+						return repo.Update(ctx, transaction)
+					},
+					reload: func(ctx context.Context) (time.Time, time.Time) {
+						var stored domain.Transaction
+						if err := db.First(&stored, "id = ?", transaction.ID).Error; err != nil {
+							t.Fatalf("işlem okunamadı: %v", err)
+						}
+						return stored.CreatedAt, stored.UpdatedAt
+					},
+				}
+			},
+		},
+		{
+			name: "zamanlanmış işlem",
+			create: func(t *testing.T, db *gorm.DB, userID uuid.UUID) entity {
+				repo := NewScheduledTransactionRepository(db)
+				scheduledTransaction, err := domain.NewScheduledTransaction(userID, domain.ScheduledTransactionRequest{
+					Type:        domain.TransactionTypeCredit,
+					Amount:      10,
+					Currency:    domain.CurrencyTRY,
+					ScheduledAt: time.Now().Add(time.Hour),
+				})
+				if err != nil {
+					t.Fatalf("NewScheduledTransaction: %v", err)
+				}
+				scheduledTransaction.CreatedAt = stale
+				scheduledTransaction.UpdatedAt = stale
+				if err := repo.Create(context.Background(), scheduledTransaction); err != nil {
+					t.Fatalf("zamanlanmış işlem yazılamadı: %v", err)
+				}
+				return entity{
+					save: func(ctx context.Context) error {
+						scheduledTransaction.Amount = 20
+						return repo.Update(ctx, scheduledTransaction)
+					},
+					reload: func(ctx context.Context) (time.Time, time.Time) {
+						stored, err := repo.GetByID(ctx, scheduledTransaction.ID)
+						if err != nil {
+							t.Fatalf("zamanlanmış işlem okunamadı: %v", err)
+						}
+						return stored.CreatedAt, stored.UpdatedAt
+					},
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := databasetest.Open(t)
+			ctx := context.Background()
+			userID := uuid.New()
+			user := &domain.User{
+				ID:        userID,
+				Email:     userID.String() + "@example.com",
+				Password:  "x",
+				FirstName: "Test",
+				LastName:  "User",
+				Role:      domain.RoleUser,
+				LimitTier: domain.LimitTierBasic,
+				CreatedAt: stale,
+				UpdatedAt: stale,
+			}
+			if err := NewUserRepository(db).Create(ctx, user); err != nil {
+				t.Fatalf("kullanıcı oluşturulamadı: %v", err)
+			}
+
+			e := tt.create(t, db, userID)
+
+			// Oluşturmada verilen zaman damgaları UTC ve veritabanı hassasiyetinde saklanır
+			wantCreated := stale.UTC().Truncate(time.Second)
+			createdAt, updatedAt := e.reload(ctx)
+			if !createdAt.Equal(wantCreated) || !updatedAt.Equal(wantCreated) {
+				t.Errorf("oluşturma sonrası CreatedAt/UpdatedAt = %v/%v, beklenen %v", createdAt, updatedAt, wantCreated)
+			}
+
+			before := time.Now().Add(-2 * time.Second)
+			if err := e.save(ctx); err != nil {
+				t.Fatalf("güncelleme: %v", err)
+			}
+
+			createdAt, updatedAt = e.reload(ctx)
+			if !updatedAt.After(before) {
+				t.Errorf("güncelleme sonrası UpdatedAt = %v, %v sonrası olmalı", updatedAt, before)
+			}
+			if !createdAt.Equal(wantCreated) {
+				t.Errorf("güncelleme CreatedAt'i değiştirdi: %v, beklenen %v", createdAt, wantCreated)
+			}
+		})
+	}
+}
@@ -2,10 +2,14 @@ package repository
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
+	"strings"
+	"time"
 
 	"transaction-api-w-go/pkg/domain"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
@@ -20,32 +24,413 @@ func NewTransactionRepository(db *gorm.DB) *TransactionRepository {
 }
 
 func (r *TransactionRepository) Create(ctx context.Context, transaction *domain.Transaction) error {
-	return r.db.WithContext(ctx).Create(transaction).Error
+	return translateCreateError(dbFromContext(ctx, r.db).Create(transaction).Error)
+}
+
+// translateCreateError (user_id, reference_id) tekillik ihlalini ErrTransactionAlreadyExists'e çevirir
+func translateCreateError(err error) error {
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return domain.ErrTransactionAlreadyExists
+	}
+	return err
 }
 
 func (r *TransactionRepository) GetByID(ctx context.Context, id uint) (*domain.Transaction, error) {
 	var transaction domain.Transaction
-	if err := r.db.WithContext(ctx).First(&transaction, id).Error; err != nil {
+	if err := dbFromContext(ctx, r.db).First(&transaction, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrTransactionNotFound
+		}
+		return nil, err
+	}
+	return &transaction, nil
+}
+
+// GetByUUID işlemi uuid ile getirir
+func (r *TransactionRepository) GetByUUID(ctx context.Context, id uuid.UUID) (*domain.Transaction, error) {
+	var transaction domain.Transaction
+	if err := dbFromContext(ctx, r.db).Where("id = ?", id).First(&transaction).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("işlem bulunamadı")
+			return nil, domain.ErrTransactionNotFound
 		}
 		return nil, err
 	}
 	return &transaction, nil
 }
 
+// GetByUUIDIncludingDeleted soft-delete ile silinmiş işlemleri de döner; denetim amaçlıdır
+func (r *TransactionRepository) GetByUUIDIncludingDeleted(ctx context.Context, id uuid.UUID) (*domain.Transaction, error) {
+	var transaction domain.Transaction
+	if err := dbFromContext(ctx, r.db).Unscoped().Where("id = ?", id).First(&transaction).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrTransactionNotFound
+		}
+		return nil, err
+	}
+	return &transaction, nil
+}
+
+// GetByUUIDs verilen id'lere sahip işlemleri döndürür; bulunamayan id'ler sonuçta yer almaz
+func (r *TransactionRepository) GetByUUIDs(ctx context.Context, ids []uuid.UUID) ([]*domain.Transaction, error) {
+	var transactions []*domain.Transaction
+	if len(ids) == 0 {
+		return transactions, nil
+	}
+	if err := dbFromContext(ctx, r.db).Where("id IN ?", ids).Find(&transactions).Error; err != nil {
+		return nil, err
+	}
+	return transactions, nil
+}
+
+// GetArchivedByUUIDs retention ile transactions_archive'a taşınmış işlemlerden verilen id'lere
+// sahip olanları döndürür; bulunamayan id'ler sonuçta yer almaz
+func (r *TransactionRepository) GetArchivedByUUIDs(ctx context.Context, ids []uuid.UUID) ([]*domain.Transaction, error) {
+	var transactions []*domain.Transaction
+	if len(ids) == 0 {
+		return transactions, nil
+	}
+	if err := dbFromContext(ctx, r.db).Table("transactions_archive").Where("id IN ?", ids).Find(&transactions).Error; err != nil {
+		return nil, err
+	}
+	return transactions, nil
+}
+
 func (r *TransactionRepository) GetByUserID(ctx context.Context, userID uint) ([]*domain.Transaction, error) {
 	var transactions []*domain.Transaction
-	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Find(&transactions).Error; err != nil {
+	if err := dbFromContext(ctx, r.db).Where("user_id = ?", userID).Find(&transactions).Error; err != nil {
+		return nil, err
+	}
+	return transactions, nil
+}
+
+// GetByUserIDAndReferenceID reference id tekilliği altında oluşturulmuş işlemi getirir
+func (r *TransactionRepository) GetByUserIDAndReferenceID(ctx context.Context, userID, referenceID string) (*domain.Transaction, error) {
+	var transaction domain.Transaction
+	if err := dbFromContext(ctx, r.db).Where("user_id = ? AND reference_id = ? AND reference_unique = ?", userID, referenceID, true).First(&transaction).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrTransactionNotFound
+		}
+		return nil, err
+	}
+	return &transaction, nil
+}
+
+func (r *TransactionRepository) ListByReferenceID(ctx context.Context, userID, referenceID string) ([]*domain.Transaction, error) {
+	var transactions []*domain.Transaction
+	if err := dbFromContext(ctx, r.db).
+		Where("user_id = ? AND reference_id = ?", userID, referenceID).
+		Order("created_at DESC").
+		Find(&transactions).Error; err != nil {
+		return nil, err
+	}
+	return transactions, nil
+}
+
+func (r *TransactionRepository) GetByUserIDWithFilter(ctx context.Context, userID string, filter domain.TransactionFilter) ([]*domain.Transaction, error) {
+	query := applyTransactionFilter(dbFromContext(ctx, r.db).Where("user_id = ?", userID), filter)
+
+	var transactions []*domain.Transaction
+	if err := query.Order("created_at DESC").Find(&transactions).Error; err != nil {
+		return nil, err
+	}
+	return transactions, nil
+}
+
+// Search filtreleri uygular ve sonuçları (created_at, id) üzerinden cursor ile sayfalar.
+// userID boş ise tüm kullanıcıların işlemleri aranır.
+func (r *TransactionRepository) Search(ctx context.Context, userID string, filter domain.TransactionFilter) (*domain.TransactionSearchResult, error) {
+	query := dbFromContext(ctx, r.db).Model(&domain.Transaction{})
+	if userID != "" {
+		query = query.Where("user_id = ?", userID)
+	}
+	query = applyTransactionFilter(query, filter)
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	desc := filter.SortOrder != domain.SortOrderAsc
+	if filter.Cursor != "" {
+		createdAt, id, err := decodeTransactionCursor(filter.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		if desc {
+			query = query.Where("(created_at < ? OR (created_at = ? AND id < ?))", createdAt, createdAt, id)
+		} else {
+			query = query.Where("(created_at > ? OR (created_at = ? AND id > ?))", createdAt, createdAt, id)
+		}
+	}
+
+	if desc {
+		query = query.Order("created_at DESC").Order("id DESC")
+	} else {
+		query = query.Order("created_at ASC").Order("id ASC")
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = domain.DefaultSearchLimit
+	}
+	if limit > domain.MaxSearchLimit {
+		limit = domain.MaxSearchLimit
+	}
+
+	var transactions []*domain.Transaction
+	if err := query.Limit(limit + 1).Find(&transactions).Error; err != nil {
+		return nil, err
+	}
+
+	result := &domain.TransactionSearchResult{Transactions: transactions, Limit: limit, Total: total}
+	if len(transactions) > limit {
+		result.Transactions = transactions[:limit]
+		last := result.Transactions[limit-1]
+		result.NextCursor = encodeTransactionCursor(last.CreatedAt, last.ID.String())
+	}
+	return result, nil
+}
+
+func applyTransactionFilter(query *gorm.DB, filter domain.TransactionFilter) *gorm.DB {
+	if filter.Category != "" {
+		query = query.Where("category = ?", filter.Category)
+	}
+	if filter.Tag != "" {
+		query = query.Where("JSON_CONTAINS(tags, JSON_QUOTE(?))", filter.Tag)
+	}
+	if filter.ReferenceID != "" {
+		query = query.Where("reference_id = ?", filter.ReferenceID)
+	}
+	if filter.CounterpartyID != "" {
+		query = query.Where("counterparty_id = ?", filter.CounterpartyID)
+	}
+	if filter.MinAmount != nil {
+		query = query.Where("amount >= ?", *filter.MinAmount)
+	}
+	if filter.MaxAmount != nil {
+		query = query.Where("amount <= ?", *filter.MaxAmount)
+	}
+	if filter.StartDate != nil {
+		query = query.Where("created_at >= ?", *filter.StartDate)
+	}
+	if filter.EndDate != nil {
+		query = query.Where("created_at <= ?", *filter.EndDate)
+	}
+	return query
+}
+
+func encodeTransactionCursor(createdAt time.Time, id string) string {
+	raw := createdAt.UTC().Format(time.RFC3339Nano) + "|" + id
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeTransactionCursor(cursor string) (time.Time, string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, "", domain.ErrInvalidCursor
+	}
+
+	parts := strings.SplitN(string(raw), "|", 2)
+	if len(parts) != 2 {
+		return time.Time{}, "", domain.ErrInvalidCursor
+	}
+
+	createdAt, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return time.Time{}, "", domain.ErrInvalidCursor
+	}
+	return createdAt, parts[1], nil
+}
+
+// unsettledStates bakiyeye henüz yansımamış ama sonuçlanması beklenen işlem durumları
+var unsettledStates = []domain.TransactionState{
+	domain.TransactionStatePending,
+	domain.TransactionStateHeld,
+	domain.TransactionStatePendingSettlement,
+}
+
+// pendingDebitStates kullanılabilir bakiyeden düşülen borç durumları. Ödeme ağ geçidinde sonuç bekleyen
+// çekimlerin tutarı bakiye blokajıyla ayrıldığı için burada tekrar sayılmaz.
+var pendingDebitStates = []domain.TransactionState{domain.TransactionStatePending, domain.TransactionStateHeld}
+
+// SumPendingDebits kullanıcının henüz tamamlanmamış veya incelemede bekleyen borç ve transfer
+// işlemlerinin toplamını döndürür
+func (r *TransactionRepository) SumPendingDebits(ctx context.Context, userID string) (float64, error) {
+	var total float64
+	if err := dbFromContext(ctx, r.db).
+		Model(&domain.Transaction{}).
+		Where("user_id = ? AND status IN ? AND type IN ?", userID, pendingDebitStates,
+			[]domain.TransactionType{domain.TransactionTypeDebit, domain.TransactionTypeTransfer}).
+		Select("COALESCE(SUM(amount), 0)").
+		Scan(&total).Error; err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+// SumCompletedSince kullanıcının since'den beri tamamlanan işlemlerinin alacak ve borç toplamlarını döndürür.
+// Gelen transfer'ler ve ücret hesabına aktarılan ücretler alacak, giden transfer'ler ve ödenen ücretler
+// borç olarak sayılır.
+func (r *TransactionRepository) SumCompletedSince(ctx context.Context, userID string, since time.Time) (credits, debits float64, err error) {
+	var totals struct {
+		Credits float64
+		Debits  float64
+	}
+	transfers := []domain.TransactionType{domain.TransactionTypeTransfer, domain.TransactionTypeFee}
+	err = dbFromContext(ctx, r.db).
+		Model(&domain.Transaction{}).
+		Where("status = ? AND created_at >= ? AND (user_id = ? OR counterparty_id = ?)",
+			domain.TransactionStateCompleted, since, userID, userID).
+		Select(`COALESCE(SUM(CASE WHEN (type = ? AND user_id = ?) OR (type IN ? AND counterparty_id = ?) THEN amount ELSE 0 END), 0) AS credits,
+			COALESCE(SUM(CASE WHEN type IN ? AND user_id = ? THEN amount ELSE 0 END), 0) AS debits`,
+			domain.TransactionTypeCredit, userID, transfers, userID,
+			[]domain.TransactionType{domain.TransactionTypeDebit, domain.TransactionTypeTransfer, domain.TransactionTypeFee}, userID).
+		Scan(&totals).Error
+	if err != nil {
+		return 0, 0, err
+	}
+	return totals.Credits, totals.Debits, nil
+}
+
+// CountPending kullanıcının bekleyen ve incelemedeki işlem sayısını döndürür
+func (r *TransactionRepository) CountPending(ctx context.Context, userID string) (int64, error) {
+	var count int64
+	if err := dbFromContext(ctx, r.db).
+		Model(&domain.Transaction{}).
+		Where("user_id = ? AND status IN ?", userID, unsettledStates).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// ListByStatus verilen durumdaki işlemleri en eskiden başlayarak döndürür
+func (r *TransactionRepository) ListByStatus(ctx context.Context, status domain.TransactionState, limit int) ([]*domain.Transaction, error) {
+	var transactions []*domain.Transaction
+	err := dbFromContext(ctx, r.db).
+		Where("status = ?", status).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&transactions).Error
+	if err != nil {
 		return nil, err
 	}
 	return transactions, nil
 }
 
+// ResolveHeld incelemedeki işlemi transaction.Status'a geçirir ve verilen bakiyeleri aynı veritabanı
+// transaction'ında yazar. Durum geçişi koşullu UPDATE ile yapılır; işlem artık held değilse
+// (ör. eşzamanlı başka bir inceleme sonuçlandırdıysa) hiçbir şey yazılmaz ve ErrTransactionNotHeld döner.
+// fee nil değilse ücret işlemi ve ücret hesabının bakiyesi de yazılır.
+func (r *TransactionRepository) ResolveHeld(ctx context.Context, transaction *domain.Transaction, fee *domain.TransferFee, balances ...*domain.Balance) error {
+	return dbFromContext(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&domain.Transaction{}).
+			Where("id = ? AND status = ?", transaction.ID, domain.TransactionStateHeld).
+			Updates(map[string]interface{}{
+				"status":            transaction.Status,
+				"status_reason":     transaction.StatusReason,
+				"gateway_reference": transaction.GatewayReference,
+				"balance_after":     transaction.BalanceAfter,
+				"updated_at":        transaction.UpdatedAt,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrTransactionNotHeld
+		}
+
+		for _, balance := range balances {
+			if err := tx.Save(balance).Error; err != nil {
+				return err
+			}
+		}
+		if fee == nil {
+			return nil
+		}
+		if err := tx.Create(fee.Transaction).Error; err != nil {
+			return err
+		}
+		return tx.Save(fee.Balance).Error
+	})
+}
+
+// CreateWithHold işlemi ve tutarını ayıran bakiye blokajını aynı veritabanı transaction'ında yazar
+func (r *TransactionRepository) CreateWithHold(ctx context.Context, transaction *domain.Transaction, hold *domain.BalanceHold) error {
+	err := dbFromContext(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(transaction).Error; err != nil {
+			return err
+		}
+		return tx.Create(hold).Error
+	})
+	return translateCreateError(err)
+}
+
+// SettleExternal ödeme ağ geçidinde sonuçlanan işlemi transaction.Status'a geçirir; varsa blokajı
+// ve bakiyeyi aynı veritabanı transaction'ında yazar. Durum geçişi koşullu UPDATE ile yapılır; işlem
+// artık pending_settlement değilse (ör. eşzamanlı bir mutabakat sonuçlandırdıysa) hiçbir şey yazılmaz
+// ve ErrTransactionNotPendingSettlement döner. Bakiye satırı olduğu gibi yazıldığından çağıran bakiyeyi
+// aynı transaction'da kilitleyip tutarı kilit altında güncellemiş olmalıdır.
+func (r *TransactionRepository) SettleExternal(ctx context.Context, transaction *domain.Transaction, hold *domain.BalanceHold, balance *domain.Balance) error {
+	return dbFromContext(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&domain.Transaction{}).
+			Where("id = ? AND status = ?", transaction.ID, domain.TransactionStatePendingSettlement).
+			Updates(map[string]interface{}{
+				"status":            transaction.Status,
+				"status_reason":     transaction.StatusReason,
+				"gateway_reference": transaction.GatewayReference,
+				"balance_after":     transaction.BalanceAfter,
+				"updated_at":        transaction.UpdatedAt,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrTransactionNotPendingSettlement
+		}
+
+		if hold != nil {
+			if err := tx.Save(hold).Error; err != nil {
+				return err
+			}
+		}
+		if balance != nil {
+			return tx.Save(balance).Error
+		}
+		return nil
+	})
+}
+
 func (r *TransactionRepository) Update(ctx context.Context, transaction *domain.Transaction) error {
-	return r.db.WithContext(ctx).Save(transaction).Error
+	return dbFromContext(ctx, r.db).Save(transaction).Error
+}
+
+// Delete işlemi soft-delete ile siler; kalıcı silme yalnızca retention tarafından yapılır
+func (r *TransactionRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return dbFromContext(ctx, r.db).Where("id = ?", id).Delete(&domain.Transaction{}).Error
 }
 
-func (r *TransactionRepository) Delete(ctx context.Context, id uint) error {
-	return r.db.WithContext(ctx).Delete(&domain.Transaction{}, id).Error
+// ApplyTransfer transfer işlemini ve iki bakiye güncellemesini tek bir veritabanı
+// transaction'ında yazar; herhangi bir adım başarısız olursa hiçbiri kalıcı olmaz.
+// fee nil değilse ücret işlemi ve ücret hesabının bakiyesi de aynı transaction'da yazılır.
+func (r *TransactionRepository) ApplyTransfer(ctx context.Context, transaction *domain.Transaction, from, to *domain.Balance, fee *domain.TransferFee) error {
+	err := dbFromContext(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(transaction).Error; err != nil {
+			return err
+		}
+		if err := tx.Save(from).Error; err != nil {
+			return err
+		}
+		if err := tx.Save(to).Error; err != nil {
+			return err
+		}
+		if fee == nil {
+			return nil
+		}
+		if err := tx.Create(fee.Transaction).Error; err != nil {
+			return err
+		}
+		return tx.Save(fee.Balance).Error
+	})
+	return translateCreateError(err)
 }
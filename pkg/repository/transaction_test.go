@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"transaction-api-w-go/pkg/database/databasetest"
+	"transaction-api-w-go/pkg/domain"
+
+	"github.com/google/uuid"
+)
+
+func TestTransactionRepositorySumCompletedSinceCountsFees(t *testing.T) {
+	db := databasetest.Open(t)
+	ctx := context.Background()
+	userRepo := NewUserRepository(db)
+	repo := NewTransactionRepository(db)
+	now := time.Now()
+
+	newUser := func() uuid.UUID {
+		user := &domain.User{
+			ID:        uuid.New(),
+			Password:  "x",
+			FirstName: "Test",
+			LastName:  "User",
+			Role:      domain.RoleUser,
+			LimitTier: domain.LimitTierBasic,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		user.Email = user.ID.String() + "@example.com"
+		if err := userRepo.Create(ctx, user); err != nil {
+			t.Fatalf("kullanıcı oluşturulamadı: %v", err)
+		}
+		return user.ID
+	}
+	sender, recipient, feeAccount := newUser(), newUser(), newUser()
+
+	create := func(userID uuid.UUID, transactionType domain.TransactionType, amount float64, counterparty *uuid.UUID, status domain.TransactionState) {
+		err := repo.Create(ctx, &domain.Transaction{
+			ID:             uuid.New(),
+			UserID:         userID,
+			Type:           transactionType,
+			Amount:         amount,
+			CounterpartyID: counterparty,
+			Status:         string(status),
+			CreatedAt:      now,
+			UpdatedAt:      now,
+		})
+		if err != nil {
+			t.Fatalf("işlem yazılamadı: %v", err)
+		}
+	}
+	create(sender, domain.TransactionTypeCredit, 500, nil, domain.TransactionStateCompleted)
+	create(sender, domain.TransactionTypeTransfer, 100, &recipient, domain.TransactionStateCompleted)
+	create(sender, domain.TransactionTypeFee, 2, &feeAccount, domain.TransactionStateCompleted)
+	// Tamamlanmamış ücret toplamlara girmez
+	create(sender, domain.TransactionTypeFee, 5, &feeAccount, domain.TransactionStateFailed)
+
+	tests := []struct {
+		name        string
+		userID      uuid.UUID
+		wantCredits float64
+		wantDebits  float64
+	}{
+		{name: "gönderen transfer ve ücret öder", userID: sender, wantCredits: 500, wantDebits: 102},
+		{name: "alıcı yalnızca tutarı alır", userID: recipient, wantCredits: 100},
+		{name: "ücret hesabı ücreti alır", userID: feeAccount, wantCredits: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			credits, debits, err := repo.SumCompletedSince(ctx, tt.userID.String(), now.Add(-time.Hour))
+			if err != nil {
+				t.Fatalf("SumCompletedSince: %v", err)
+			}
+			if credits != tt.wantCredits || debits != tt.wantDebits {
+				t.Errorf("alacak/borç = %v/%v, beklenen %v/%v", credits, debits, tt.wantCredits, tt.wantDebits)
+			}
+		})
+	}
+}
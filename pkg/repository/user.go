@@ -1,10 +1,13 @@
 package repository
 
 import (
+	"context"
 	"errors"
+	"time"
 
 	"transaction-api-w-go/pkg/domain"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
@@ -13,7 +16,7 @@ type UserRepository struct {
 }
 
 var (
-	ErrUserNotFound = errors.New("kullanıcı bulunamadı")
+	ErrUserNotFound = domain.ErrUserNotFound
 )
 
 func NewUserRepository(db *gorm.DB) *UserRepository {
@@ -22,43 +25,91 @@ func NewUserRepository(db *gorm.DB) *UserRepository {
 	}
 }
 
-func (r *UserRepository) Create(user *domain.User) error {
-	return r.db.Create(user).Error
+func (r *UserRepository) Create(ctx context.Context, user *domain.User) error {
+	db, cancel := withQueryTimeout(ctx, r.db)
+	defer cancel()
+	return db.Create(user).Error
 }
 
-func (r *UserRepository) GetByID(id string) (*domain.User, error) {
+func (r *UserRepository) GetByID(ctx context.Context, id string) (*domain.User, error) {
+	db, cancel := withQueryTimeout(ctx, r.db)
+	defer cancel()
+
 	var user domain.User
-	if err := r.db.First(&user, "id = ?", id).Error; err != nil {
+	if err := db.First(&user, "id = ?", id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("kullanıcı bulunamadı")
+			return nil, domain.ErrUserNotFound
 		}
 		return nil, err
 	}
 	return &user, nil
 }
 
-func (r *UserRepository) GetByEmail(email string) (*domain.User, error) {
+func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
+	db, cancel := withQueryTimeout(ctx, r.db)
+	defer cancel()
+
 	var user domain.User
-	if err := r.db.First(&user, "email = ?", email).Error; err != nil {
+	if err := db.First(&user, "email = ?", email).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("kullanıcı bulunamadı")
+			return nil, domain.ErrUserNotFound
 		}
 		return nil, err
 	}
 	return &user, nil
 }
 
-func (r *UserRepository) Update(user *domain.User) error {
-	return r.db.Save(user).Error
+func (r *UserRepository) Update(ctx context.Context, user *domain.User) error {
+	db, cancel := withQueryTimeout(ctx, r.db)
+	defer cancel()
+	return db.Save(user).Error
+}
+
+// GetLimitTier kullanıcının limit seviyesini döndürür; seviye atanmamışsa basic kabul edilir
+func (r *UserRepository) GetLimitTier(ctx context.Context, userID uuid.UUID) (domain.LimitTier, error) {
+	db, cancel := withQueryTimeout(ctx, r.db)
+	defer cancel()
+
+	var user domain.User
+	if err := db.Select("id", "limit_tier").First(&user, "id = ?", userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", domain.ErrUserNotFound
+		}
+		return "", err
+	}
+	if user.LimitTier == "" {
+		return domain.LimitTierBasic, nil
+	}
+	return user.LimitTier, nil
+}
+
+func (r *UserRepository) SetLimitTier(ctx context.Context, id string, tier domain.LimitTier) error {
+	db, cancel := withQueryTimeout(ctx, r.db)
+	defer cancel()
+
+	result := db.Model(&domain.User{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"limit_tier": tier, "updated_at": time.Now()})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrUserNotFound
+	}
+	return nil
 }
 
-func (r *UserRepository) Delete(id string) error {
-	return r.db.Delete(&domain.User{}, "id = ?", id).Error
+func (r *UserRepository) Delete(ctx context.Context, id string) error {
+	db, cancel := withQueryTimeout(ctx, r.db)
+	defer cancel()
+	return db.Delete(&domain.User{}, "id = ?", id).Error
 }
 
-func (r *UserRepository) List() ([]domain.User, error) {
+func (r *UserRepository) List(ctx context.Context) ([]domain.User, error) {
+	db, cancel := withQueryTimeout(ctx, r.db)
+	defer cancel()
+
 	var users []domain.User
-	if err := r.db.Find(&users).Error; err != nil {
+	if err := db.Find(&users).Error; err != nil {
 		return nil, err
 	}
 	return users, nil
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"transaction-api-w-go/pkg/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type WebhookDeliveryRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookDeliveryRepository(db *gorm.DB) *WebhookDeliveryRepository {
+	return &WebhookDeliveryRepository{
+		db: db,
+	}
+}
+
+func (r *WebhookDeliveryRepository) Create(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	return dbFromContext(ctx, r.db).Create(delivery).Error
+}
+
+func (r *WebhookDeliveryRepository) Update(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	return dbFromContext(ctx, r.db).Save(delivery).Error
+}
+
+func (r *WebhookDeliveryRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.WebhookDelivery, error) {
+	var delivery domain.WebhookDelivery
+	if err := dbFromContext(ctx, r.db).Where("id = ?", id).First(&delivery).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrWebhookDeliveryNotFound
+		}
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// List teslimatları en yeniden eskiye döner
+func (r *WebhookDeliveryRepository) List(ctx context.Context, filter domain.WebhookDeliveryFilter) ([]*domain.WebhookDelivery, error) {
+	filter.Normalize()
+
+	query := dbFromContext(ctx, r.db).Model(&domain.WebhookDelivery{})
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+
+	var deliveries []*domain.WebhookDelivery
+	if err := query.Order("created_at DESC").Limit(filter.Limit).Find(&deliveries).Error; err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// ListDue zamanı gelmiş pending ve retrying teslimatları en eskiden başlayarak döner
+func (r *WebhookDeliveryRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]*domain.WebhookDelivery, error) {
+	var deliveries []*domain.WebhookDelivery
+	err := dbFromContext(ctx, r.db).
+		Where("status IN ? AND next_attempt_at <= ?",
+			[]domain.WebhookDeliveryStatus{domain.WebhookDeliveryPending, domain.WebhookDeliveryRetrying}, now).
+		Order("next_attempt_at ASC").
+		Limit(limit).
+		Find(&deliveries).Error
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+func (r *WebhookDeliveryRepository) CreateAttempt(ctx context.Context, attempt *domain.WebhookDeliveryAttempt) error {
+	return dbFromContext(ctx, r.db).Create(attempt).Error
+}
+
+func (r *WebhookDeliveryRepository) ListAttempts(ctx context.Context, deliveryID uuid.UUID) ([]domain.WebhookDeliveryAttempt, error) {
+	var attempts []domain.WebhookDeliveryAttempt
+	err := dbFromContext(ctx, r.db).
+		Where("delivery_id = ?", deliveryID).
+		Order("attempt ASC, created_at ASC").
+		Find(&attempts).Error
+	if err != nil {
+		return nil, err
+	}
+	return attempts, nil
+}
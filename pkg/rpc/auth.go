@@ -0,0 +1,45 @@
+package rpc
+
+import (
+	"context"
+	"strings"
+
+	"transaction-api-w-go/pkg/server/middleware"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type contextKey string
+
+const userIDKey contextKey = "user_id"
+
+// AuthInterceptor "authorization: Bearer <token>" metadata'sını REST API ile aynı JWT
+// doğrulamasından geçirir ve user_id'yi context'e yazar
+func AuthInterceptor(jwtSecret string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		md, ok := metadata.FromIncomingContext(ctx)
+		if !ok || len(md.Get("authorization")) == 0 {
+			return nil, status.Error(codes.Unauthenticated, "authorization metadata is missing")
+		}
+
+		parts := strings.Split(md.Get("authorization")[0], " ")
+		if len(parts) != 2 || parts[0] != "Bearer" {
+			return nil, status.Error(codes.Unauthenticated, "invalid authorization format")
+		}
+
+		userID, err := middleware.ParseUserID(jwtSecret, parts[1])
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+
+		return handler(context.WithValue(ctx, userIDKey, userID), req)
+	}
+}
+
+func userIDFromContext(ctx context.Context) string {
+	userID, _ := ctx.Value(userIDKey).(string)
+	return userID
+}
@@ -0,0 +1,59 @@
+// Package rpc çekirdek işlem operasyonlarını (credit, debit, transfer, bakiye ve işlem
+// sorgulama) gRPC üzerinden sunar. Mesajlar JSON codec ile taşınır; istemciler
+// grpc.CallContentSubtype("json") kullanmalıdır.
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+)
+
+type Server struct {
+	grpcServer *grpc.Server
+	port       int
+}
+
+func NewServer(port int, jwtSecret string, transactionServer *TransactionServer) *Server {
+	grpcServer := grpc.NewServer(grpc.UnaryInterceptor(AuthInterceptor(jwtSecret)))
+	grpcServer.RegisterService(&serviceDesc, transactionServer)
+
+	return &Server{
+		grpcServer: grpcServer,
+		port:       port,
+	}
+}
+
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", s.port))
+	if err != nil {
+		return err
+	}
+	return s.Serve(listener)
+}
+
+// Serve sunucuyu verilen listener üzerinde çalıştırır
+func (s *Server) Serve(listener net.Listener) error {
+	log.Info().Str("addr", listener.Addr().String()).Msg("gRPC server listening")
+	return s.grpcServer.Serve(listener)
+}
+
+// Shutdown devam eden çağrıların bitmesini bekler; ctx dolarsa bağlantıları zorla kapatır
+func (s *Server) Shutdown(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.grpcServer.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.grpcServer.Stop()
+		return ctx.Err()
+	}
+}
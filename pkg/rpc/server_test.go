@@ -0,0 +1,147 @@
+package rpc
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"transaction-api-w-go/pkg/database/databasetest"
+	"transaction-api-w-go/pkg/domain"
+	"transaction-api-w-go/pkg/repository"
+	"transaction-api-w-go/pkg/service"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const testJWTSecret = "test-secret"
+
+// startTestServer bellek içi veritabanına bağlı servislerle gRPC sunucusunu bufconn üzerinde başlatır
+// ve amount bakiyeli bir kullanıcıyla ona bağlı bir istemci döner
+func startTestServer(t *testing.T, amount float64) (*grpc.ClientConn, string, *repository.BalanceRepository) {
+	t.Helper()
+	db := databasetest.Open(t)
+	transactionRepo := repository.NewTransactionRepository(db)
+	balanceRepo := repository.NewBalanceRepository(db)
+	holdRepo := repository.NewBalanceHoldRepository(db)
+	userRepo := repository.NewUserRepository(db)
+	txManager := repository.NewTxManager(db)
+
+	ctx := context.Background()
+	now := time.Now()
+	user := &domain.User{ID: uuid.New(), Password: "x", FirstName: "Test", LastName: "User", Role: domain.RoleUser, LimitTier: domain.LimitTierBasic, CreatedAt: now, UpdatedAt: now}
+	user.Email = user.ID.String() + "@example.com"
+	if err := userRepo.Create(ctx, user); err != nil {
+		t.Fatalf("kullanıcı oluşturulamadı: %v", err)
+	}
+	balance := &domain.Balance{ID: uuid.New(), UserID: user.ID, Amount: amount, Currency: string(domain.CurrencyTRY), CreatedAt: now, UpdatedAt: now}
+	if err := balanceRepo.Create(ctx, balance); err != nil {
+		t.Fatalf("bakiye oluşturulamadı: %v", err)
+	}
+
+	server := NewServer(0, testJWTSecret, NewTransactionServer(
+		service.NewTransactionService(transactionRepo, balanceRepo, holdRepo, userRepo, txManager),
+		service.NewBalanceService(balanceRepo, holdRepo, transactionRepo, txManager),
+	))
+	listener := bufconn.Listen(1 << 20)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(func() { _ = server.Shutdown(context.Background()) })
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(grpc.CallContentSubtype(codecName)),
+	)
+	if err != nil {
+		t.Fatalf("istemci oluşturulamadı: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn, user.ID.String(), balanceRepo
+}
+
+func signToken(t *testing.T, secret, userID string) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id": userID,
+		"exp":     time.Now().Add(time.Hour).Unix(),
+	}).SignedString([]byte(secret))
+	if err != nil {
+		t.Fatalf("token imzalanamadı: %v", err)
+	}
+	return token
+}
+
+func TestCreditRPC(t *testing.T) {
+	tests := []struct {
+		name          string
+		authorization func(t *testing.T, userID string) string
+		amount        float64
+		wantCode      codes.Code
+		wantBalance   float64
+	}{
+		{
+			name:          "geçerli token ile credit",
+			authorization: func(t *testing.T, userID string) string { return "Bearer " + signToken(t, testJWTSecret, userID) },
+			amount:        25,
+			wantCode:      codes.OK,
+			wantBalance:   125,
+		},
+		{
+			name:        "token yok",
+			amount:      25,
+			wantCode:    codes.Unauthenticated,
+			wantBalance: 100,
+		},
+		{
+			name:          "başka secret ile imzalı token",
+			authorization: func(t *testing.T, userID string) string { return "Bearer " + signToken(t, "other-secret", userID) },
+			amount:        25,
+			wantCode:      codes.Unauthenticated,
+			wantBalance:   100,
+		},
+		{
+			name:          "geçersiz tutar",
+			authorization: func(t *testing.T, userID string) string { return "Bearer " + signToken(t, testJWTSecret, userID) },
+			wantCode:      codes.InvalidArgument,
+			wantBalance:   100,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn, userID, balances := startTestServer(t, 100)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if tt.authorization != nil {
+				ctx = metadata.AppendToOutgoingContext(ctx, "authorization", tt.authorization(t, userID))
+			}
+
+			var transaction domain.Transaction
+			err := conn.Invoke(ctx, "/"+ServiceName+"/Credit", &domain.TransactionRequest{Amount: tt.amount, Description: "grpc"}, &transaction)
+			if got := status.Code(err); got != tt.wantCode {
+				t.Fatalf("kod = %s (%v), beklenen %s", got, err, tt.wantCode)
+			}
+			if tt.wantCode == codes.OK {
+				if transaction.UserID.String() != userID || transaction.Amount != tt.amount || transaction.Type != domain.TransactionTypeCredit {
+					t.Errorf("işlem = %s/%v/%s, beklenen %s için %v alacak", transaction.UserID, transaction.Amount, transaction.Type, userID, tt.amount)
+				}
+			}
+
+			balance, err := balances.GetByUserIDAndCurrency(context.Background(), userID, string(domain.CurrencyTRY))
+			if err != nil {
+				t.Fatalf("bakiye okunamadı: %v", err)
+			}
+			if balance.Amount != tt.wantBalance {
+				t.Errorf("bakiye = %v, beklenen %v", balance.Amount, tt.wantBalance)
+			}
+		})
+	}
+}
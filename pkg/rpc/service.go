@@ -0,0 +1,139 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+
+	"transaction-api-w-go/pkg/domain"
+	"transaction-api-w-go/pkg/service"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ServiceName gRPC servisinin tam adı
+const ServiceName = "transactionapi.v1.TransactionService"
+
+type GetBalanceRequest struct{}
+
+type GetTransactionRequest struct {
+	ID string `json:"id"`
+}
+
+// TransactionServer REST handler'larıyla aynı servis katmanını gRPC üzerinden sunar
+type TransactionServer struct {
+	transactionService *service.TransactionService
+	balanceService     *service.BalanceService
+}
+
+func NewTransactionServer(transactionService *service.TransactionService, balanceService *service.BalanceService) *TransactionServer {
+	return &TransactionServer{
+		transactionService: transactionService,
+		balanceService:     balanceService,
+	}
+}
+
+func (s *TransactionServer) Credit(ctx context.Context, req *domain.TransactionRequest) (*domain.Transaction, error) {
+	if err := binding.Validator.ValidateStruct(req); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	transaction, err := s.transactionService.Credit(ctx, userIDFromContext(ctx), req)
+	return transaction, toStatus(err)
+}
+
+func (s *TransactionServer) Debit(ctx context.Context, req *domain.TransactionRequest) (*domain.Transaction, error) {
+	if err := binding.Validator.ValidateStruct(req); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	transaction, err := s.transactionService.Debit(ctx, userIDFromContext(ctx), req)
+	return transaction, toStatus(err)
+}
+
+func (s *TransactionServer) Transfer(ctx context.Context, req *domain.TransferRequest) (*domain.Transaction, error) {
+	if err := binding.Validator.ValidateStruct(req); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	transaction, err := s.transactionService.Transfer(ctx, userIDFromContext(ctx), req)
+	return transaction, toStatus(err)
+}
+
+func (s *TransactionServer) GetBalance(ctx context.Context, _ *GetBalanceRequest) (*domain.Balance, error) {
+	balance, err := s.balanceService.GetCurrentBalance(ctx, userIDFromContext(ctx))
+	return balance, toStatus(err)
+}
+
+func (s *TransactionServer) GetTransaction(ctx context.Context, req *GetTransactionRequest) (*domain.Transaction, error) {
+	id, err := uuid.Parse(req.ID)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid transaction id")
+	}
+	transaction, err := s.transactionService.GetUserTransaction(ctx, userIDFromContext(ctx), id)
+	return transaction, toStatus(err)
+}
+
+// toStatus servis hatalarını gRPC durum kodlarına eşler
+func toStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	code := codes.Internal
+	switch {
+	case errors.Is(err, domain.ErrTransactionNotFound), errors.Is(err, domain.ErrBalanceNotFound),
+		errors.Is(err, domain.ErrUserNotFound):
+		code = codes.NotFound
+	case errors.Is(err, domain.ErrTransactionAlreadyExists):
+		code = codes.AlreadyExists
+	case errors.Is(err, domain.ErrInsufficientBalance), errors.Is(err, domain.ErrTransactionDenied),
+		errors.Is(err, domain.ErrExternalTransferRejected):
+		code = codes.FailedPrecondition
+	case errors.Is(err, domain.ErrScreeningUnavailable), errors.Is(err, domain.ErrPaymentGatewayUnavailable):
+		code = codes.Unavailable
+	case errors.Is(err, domain.ErrInvalidAmount), errors.Is(err, domain.ErrMetadataTooLarge),
+		errors.Is(err, domain.ErrInvalidMetadata), errors.Is(err, domain.ErrInvalidTransferDestination),
+		errors.Is(err, domain.ErrInvalidExternalAccount):
+		code = codes.InvalidArgument
+	case errors.Is(err, context.DeadlineExceeded):
+		code = codes.DeadlineExceeded
+	case errors.Is(err, context.Canceled):
+		code = codes.Canceled
+	}
+	return status.Error(code, err.Error())
+}
+
+// unaryHandler grpc.MethodDesc.Handler imzasına uyan, isteği çözüp interceptor zincirinden geçiren handler üretir
+func unaryHandler[Req any, Resp any](call func(*TransactionServer, context.Context, *Req) (Resp, error), method string) func(interface{}, context.Context, func(interface{}) error, grpc.UnaryServerInterceptor) (interface{}, error) {
+	return func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+		req := new(Req)
+		if err := dec(req); err != nil {
+			return nil, err
+		}
+
+		server := srv.(*TransactionServer)
+		if interceptor == nil {
+			return call(server, ctx, req)
+		}
+
+		info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/" + ServiceName + "/" + method}
+		return interceptor(ctx, req, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return call(server, ctx, req.(*Req))
+		})
+	}
+}
+
+// serviceDesc .proto'dan üretilmiş kodun yerine geçen elle yazılmış servis tanımıdır
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: ServiceName,
+	HandlerType: (*interface{})(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: "Credit", Handler: unaryHandler((*TransactionServer).Credit, "Credit")},
+		{MethodName: "Debit", Handler: unaryHandler((*TransactionServer).Debit, "Debit")},
+		{MethodName: "Transfer", Handler: unaryHandler((*TransactionServer).Transfer, "Transfer")},
+		{MethodName: "GetBalance", Handler: unaryHandler((*TransactionServer).GetBalance, "GetBalance")},
+		{MethodName: "GetTransaction", Handler: unaryHandler((*TransactionServer).GetTransaction, "GetTransaction")},
+	},
+	Streams: []grpc.StreamDesc{},
+}
@@ -1,6 +1,7 @@
 package server
 
 import (
+	"errors"
 	"net/http"
 
 	"transaction-api-w-go/pkg/domain"
@@ -46,7 +47,7 @@ func (h *AdvancedTransactionHandler) CreateScheduledTransaction(c *gin.Context)
 
 	scheduledTransaction, err := h.scheduledService.CreateScheduledTransaction(c.Request.Context(), userID, req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(scheduledErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -55,6 +56,35 @@ func (h *AdvancedTransactionHandler) CreateScheduledTransaction(c *gin.Context)
 	})
 }
 
+func (h *AdvancedTransactionHandler) CreateScheduledTransactionsBulk(c *gin.Context) {
+	var req domain.BulkScheduledTransactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userIDStr := c.GetString("user_id")
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	scheduledTransactions, itemErrors, err := h.scheduledService.CreateScheduledTransactions(c.Request.Context(), userID, req.Items)
+	if err != nil {
+		c.JSON(scheduledErrorStatus(err), gin.H{
+			"error":       err.Error(),
+			"item_errors": itemErrors,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"scheduled_transactions": scheduledTransactions,
+		"count":                  len(scheduledTransactions),
+	})
+}
+
 func (h *AdvancedTransactionHandler) GetScheduledTransaction(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := uuid.Parse(idStr)
@@ -82,15 +112,19 @@ func (h *AdvancedTransactionHandler) GetUserScheduledTransactions(c *gin.Context
 		return
 	}
 
+	limit, offset, err := domain.ParsePageParams(c.Query("limit"), c.Query("offset"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	scheduledTransactions, err := h.scheduledService.GetUserScheduledTransactions(c.Request.Context(), userID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"scheduled_transactions": scheduledTransactions,
-	})
+	c.JSON(http.StatusOK, domain.Paginate(scheduledTransactions, limit, offset))
 }
 
 func (h *AdvancedTransactionHandler) UpdateScheduledTransaction(c *gin.Context) {
@@ -109,7 +143,7 @@ func (h *AdvancedTransactionHandler) UpdateScheduledTransaction(c *gin.Context)
 
 	err = h.scheduledService.UpdateScheduledTransaction(c.Request.Context(), id, req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(scheduledErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -137,8 +171,82 @@ func (h *AdvancedTransactionHandler) CancelScheduledTransaction(c *gin.Context)
 	})
 }
 
+func (h *AdvancedTransactionHandler) PauseScheduledTransaction(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid scheduled transaction ID"})
+		return
+	}
+
+	scheduledTransaction, err := h.scheduledService.PauseScheduledTransaction(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(scheduledErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"scheduled_transaction": scheduledTransaction,
+	})
+}
+
+func (h *AdvancedTransactionHandler) ResumeScheduledTransaction(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid scheduled transaction ID"})
+		return
+	}
+
+	scheduledTransaction, err := h.scheduledService.ResumeScheduledTransaction(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(scheduledErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"scheduled_transaction": scheduledTransaction,
+	})
+}
+
+func scheduledErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrScheduledTransactionNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrInvalidScheduledStatus):
+		return http.StatusConflict
+	case errors.Is(err, domain.ErrInvalidAmount), errors.Is(err, domain.ErrInvalidScheduledTime),
+		errors.Is(err, domain.ErrInvalidTimezone), errors.Is(err, domain.ErrInvalidRecurringConfig),
+		errors.Is(err, domain.ErrInvalidExecutionWindow), errors.Is(err, domain.ErrTooManyBulkItems),
+		errors.Is(err, domain.ErrBulkValidationFailed), errors.Is(err, domain.ErrInvalidTransferDestination),
+		errors.Is(err, domain.ErrInvalidExternalAccount):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func batchErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrInvalidAmount), errors.Is(err, domain.ErrInvalidBatchItems),
+		errors.Is(err, domain.ErrBatchSizeExceeded), errors.Is(err, domain.ErrInvalidBatchSplit),
+		errors.Is(err, domain.ErrInvalidAllocationWeight):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrAutomationPaused):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
 func (h *AdvancedTransactionHandler) ExecuteScheduledTransactions(c *gin.Context) {
 	err := h.scheduledService.ExecuteScheduledTransactions(c.Request.Context())
+	if errors.Is(err, domain.ErrAutomationPaused) {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -165,7 +273,7 @@ func (h *AdvancedTransactionHandler) CreateBatchTransaction(c *gin.Context) {
 
 	batchTransaction, err := h.batchService.CreateBatchTransaction(c.Request.Context(), userID, req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(batchErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -201,15 +309,19 @@ func (h *AdvancedTransactionHandler) GetBatchTransactionItems(c *gin.Context) {
 		return
 	}
 
+	limit, offset, err := domain.ParsePageParams(c.Query("limit"), c.Query("offset"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	items, err := h.batchService.GetBatchTransactionItems(c.Request.Context(), batchID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"batch_items": items,
-	})
+	c.JSON(http.StatusOK, domain.Paginate(items, limit, offset))
 }
 
 func (h *AdvancedTransactionHandler) ProcessBatchTransaction(c *gin.Context) {
@@ -222,7 +334,7 @@ func (h *AdvancedTransactionHandler) ProcessBatchTransaction(c *gin.Context) {
 
 	err = h.batchService.ProcessBatchTransaction(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(batchErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -297,6 +409,26 @@ func (h *AdvancedTransactionHandler) GetTransactionLimit(c *gin.Context) {
 	})
 }
 
+func (h *AdvancedTransactionHandler) ListTransactionLimits(c *gin.Context) {
+	userIDStr := c.GetString("user_id")
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	limits, err := h.limitService.ListTransactionLimits(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"transaction_limits": limits,
+		"count":              len(limits),
+	})
+}
+
 func (h *AdvancedTransactionHandler) UpdateTransactionLimit(c *gin.Context) {
 	userIDStr := c.GetString("user_id")
 	userID, err := uuid.Parse(userIDStr)
@@ -347,6 +479,25 @@ func (h *AdvancedTransactionHandler) ResetTransactionLimits(c *gin.Context) {
 	})
 }
 
+// ReleaseTransactionLimit geri alınan veya başarısız olan işlemin limit kullanımını iade eder
+func (h *AdvancedTransactionHandler) ReleaseTransactionLimit(c *gin.Context) {
+	transactionID, err := uuid.Parse(c.Param("transaction_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid transaction ID"})
+		return
+	}
+
+	if err := h.limitService.ReleaseTransactionLimit(c.Request.Context(), transactionID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":        "Transaction limit usage released",
+		"transaction_id": transactionID,
+	})
+}
+
 func (h *AdvancedTransactionHandler) CreateMultiCurrencyBalance(c *gin.Context) {
 	var req struct {
 		Currency      domain.Currency `json:"currency" binding:"required"`
@@ -406,15 +557,62 @@ func (h *AdvancedTransactionHandler) GetAllBalances(c *gin.Context) {
 		return
 	}
 
+	limit, offset, err := domain.ParsePageParams(c.Query("limit"), c.Query("offset"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	balances, err := h.multiCurrencyService.GetAllBalances(c.Request.Context(), userID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"multi_currency_balances": balances,
-	})
+	c.JSON(http.StatusOK, domain.Paginate(balances, limit, offset))
+}
+
+// GetMultiCurrencyTransactions kullanıcının dönüşüm ve para birimleri arası transfer geçmişini
+// döner; currency verilirse yalnızca o para birimini etkileyen işlemler listelenir
+func (h *AdvancedTransactionHandler) GetMultiCurrencyTransactions(c *gin.Context) {
+	userIDStr := c.GetString("user_id")
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	limit, offset, err := domain.ParsePageParams(c.Query("limit"), c.Query("offset"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	filter := domain.MultiCurrencyTransactionFilter{Limit: limit, Offset: offset}
+	if currencyStr := c.Query("currency"); currencyStr != "" {
+		currency := domain.Currency(currencyStr)
+		if !currency.IsSupported() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrCurrencyNotSupported.Error()})
+			return
+		}
+		filter.Currency = &currency
+	}
+	if typeStr := c.Query("type"); typeStr != "" {
+		txType := domain.MultiCurrencyTransactionType(typeStr)
+		if !txType.IsValid() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidTransactionType.Error()})
+			return
+		}
+		filter.Type = &txType
+	}
+
+	transactions, total, err := h.multiCurrencyService.GetTransactionHistory(c.Request.Context(), userID, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewPage(transactions, limit, offset, total))
 }
 
 func (h *AdvancedTransactionHandler) ConvertCurrency(c *gin.Context) {
@@ -0,0 +1,112 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"transaction-api-w-go/pkg/database/databasetest"
+	"transaction-api-w-go/pkg/domain"
+	"transaction-api-w-go/pkg/repository"
+	"transaction-api-w-go/pkg/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func TestListTransactionLimits(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		currencies     []domain.Currency
+		wantCurrencies []domain.Currency
+	}{
+		{name: "limit tanımlı değil"},
+		{name: "tek para birimi", currencies: []domain.Currency{domain.CurrencyTRY}, wantCurrencies: []domain.Currency{domain.CurrencyTRY}},
+		{
+			name:           "birden fazla para birimi",
+			currencies:     []domain.Currency{domain.CurrencyUSD, domain.CurrencyTRY, domain.CurrencyEUR},
+			wantCurrencies: []domain.Currency{domain.CurrencyEUR, domain.CurrencyTRY, domain.CurrencyUSD},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			limitService := service.NewTransactionLimitService(repository.NewTransactionLimitRepository(databasetest.Open(t)), nil)
+			userID, otherUser := uuid.New(), uuid.New()
+
+			createLimit := func(userID uuid.UUID, currency domain.Currency, daily float64) {
+				t.Helper()
+				req := domain.TransactionLimitRequest{Currency: currency, DailyLimit: daily, WeeklyLimit: daily * 7, MonthlyLimit: daily * 30, SingleLimit: daily}
+				if _, err := limitService.CreateTransactionLimit(ctx, userID, req); err != nil {
+					t.Fatalf("limit oluşturulamadı: %v", err)
+				}
+			}
+			for i, currency := range tt.currencies {
+				createLimit(userID, currency, float64(100*(i+1)))
+			}
+			// Başka kullanıcının limitleri listede görünmez
+			createLimit(otherUser, domain.CurrencyGBP, 50)
+
+			engine := gin.New()
+			engine.GET("/limits", func(c *gin.Context) {
+				c.Set("user_id", userID.String())
+				c.Next()
+			}, NewAdvancedTransactionHandler(nil, nil, limitService, nil).ListTransactionLimits)
+
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/limits", nil))
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, beklenen 200: %s", w.Code, w.Body.String())
+			}
+
+			var body struct {
+				TransactionLimits []struct {
+					UserID     uuid.UUID       `json:"user_id"`
+					Currency   domain.Currency `json:"currency"`
+					DailyLimit float64         `json:"daily_limit"`
+				} `json:"transaction_limits"`
+				Count int `json:"count"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("yanıt çözülemedi: %v", err)
+			}
+			if body.Count != len(tt.wantCurrencies) || len(body.TransactionLimits) != len(tt.wantCurrencies) {
+				t.Fatalf("limit sayısı = %d (count %d), beklenen %d", len(body.TransactionLimits), body.Count, len(tt.wantCurrencies))
+			}
+			for i, limit := range body.TransactionLimits {
+				if limit.UserID != userID || limit.Currency != tt.wantCurrencies[i] {
+					t.Errorf("%d. limit = %s/%s, beklenen %s/%s", i+1, limit.UserID, limit.Currency, userID, tt.wantCurrencies[i])
+				}
+				if limit.DailyLimit <= 0 {
+					t.Errorf("%s günlük limiti = %v", limit.Currency, limit.DailyLimit)
+				}
+			}
+		})
+	}
+}
+
+func TestErrorStatusMapsNotFoundTo404(t *testing.T) {
+	tests := []struct {
+		name     string
+		classify func(error) int
+		err      error
+	}{
+		{name: "zamanlanmış işlem", classify: scheduledErrorStatus, err: domain.ErrScheduledTransactionNotFound},
+		{name: "batch", classify: batchErrorStatus, err: domain.ErrBatchTransactionNotFound},
+		{name: "webhook teslimatı", classify: webhookErrorStatus, err: domain.ErrWebhookDeliveryNotFound},
+		{name: "özellik bayrağı", classify: featureFlagErrorStatus, err: domain.ErrFeatureFlagNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.classify(tt.err); got != http.StatusNotFound {
+				t.Errorf("status = %d, beklenen 404", got)
+			}
+		})
+	}
+}
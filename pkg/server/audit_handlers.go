@@ -0,0 +1,81 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"transaction-api-w-go/pkg/domain"
+	"transaction-api-w-go/pkg/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuditLogStore admin işlemlerinin yazıldığı ve sorgulandığı depodur
+type AuditLogStore interface {
+	middleware.AuditRecorder
+	List(ctx context.Context, filter domain.AuditLogFilter) ([]domain.AuditLog, int64, error)
+}
+
+type AuditHandler struct {
+	store AuditLogStore
+}
+
+func NewAuditHandler(store AuditLogStore) *AuditHandler {
+	return &AuditHandler{
+		store: store,
+	}
+}
+
+// ListAuditLogs actor_id, action, target ve from/to (RFC3339) ile filtrelenmiş audit kayıtlarını döner
+func (h *AuditHandler) ListAuditLogs(c *gin.Context) {
+	filter := domain.AuditLogFilter{
+		ActorID: c.Query("actor_id"),
+		Action:  c.Query("action"),
+		Target:  c.Query("target"),
+	}
+
+	for _, param := range []struct {
+		name string
+		dest **time.Time
+	}{{"from", &filter.From}, {"to", &filter.To}} {
+		value := c.Query(param.name)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + param.name + " parameter, expected RFC3339"})
+			return
+		}
+		*param.dest = &parsed
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(domain.DefaultAuditLogLimit)))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit parameter"})
+		return
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid offset parameter"})
+		return
+	}
+	filter.Limit = limit
+	filter.Offset = offset
+	filter.Normalize()
+
+	entries, total, err := h.store.List(c.Request.Context(), filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"audit_logs": entries,
+		"total":      total,
+		"limit":      filter.Limit,
+		"offset":     filter.Offset,
+	})
+}
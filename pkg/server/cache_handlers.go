@@ -4,6 +4,8 @@ import (
 	"net/http"
 	"strconv"
 
+	"transaction-api-w-go/pkg/cache"
+	"transaction-api-w-go/pkg/domain"
 	"transaction-api-w-go/pkg/service"
 
 	"github.com/gin-gonic/gin"
@@ -64,6 +66,32 @@ func (h *CacheHandler) GetCacheTTL(c *gin.Context) {
 	})
 }
 
+func (h *CacheHandler) ListCacheKeys(c *gin.Context) {
+	pattern := c.DefaultQuery("pattern", "*")
+
+	limit := cache.DefaultKeyScanLimit
+	if limitStr := c.Query("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit parameter"})
+			return
+		}
+		limit = parsed
+	}
+
+	keys, err := h.cacheService.ListKeys(c.Request.Context(), pattern, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"pattern": pattern,
+		"keys":    keys,
+		"count":   len(keys),
+	})
+}
+
 func (h *CacheHandler) CheckCacheExists(c *gin.Context) {
 	key := c.Param("key")
 	if key == "" {
@@ -83,6 +111,26 @@ func (h *CacheHandler) CheckCacheExists(c *gin.Context) {
 	})
 }
 
+// ExpireCacheKey verilen anahtarı (desen değil) hemen siler; eski veriyi ayıklarken kullanılır
+func (h *CacheHandler) ExpireCacheKey(c *gin.Context) {
+	key := c.Param("key")
+	if key == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Key parameter is required"})
+		return
+	}
+
+	existed, err := h.cacheService.ExpireKey(c.Request.Context(), key)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"key":     key,
+		"existed": existed,
+	})
+}
+
 func (h *CacheHandler) IncrementCacheKey(c *gin.Context) {
 	key := c.Param("key")
 	if key == "" {
@@ -278,6 +326,38 @@ func (h *CacheHandler) InvalidateAggregateEvents(c *gin.Context) {
 	})
 }
 
+func (h *CacheHandler) InvalidateBatch(c *gin.Context) {
+	var request struct {
+		EntityType string      `json:"entity_type" binding:"required"`
+		IDs        []uuid.UUID `json:"ids" binding:"required,min=1,max=1000"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !cache.IsKnownEntityType(request.EntityType) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":       domain.ErrUnknownEntityType.Error(),
+			"entity_type": request.EntityType,
+			"known_types": cache.KnownEntityTypes,
+		})
+		return
+	}
+
+	result, err := h.cacheService.InvalidateBatch(c.Request.Context(), request.EntityType, request.IDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Batch invalidation completed successfully",
+		"result":  result,
+	})
+}
+
 func (h *CacheHandler) GetCachedUser(c *gin.Context) {
 	userIDStr := c.Param("user_id")
 	userID, err := uuid.Parse(userIDStr)
@@ -0,0 +1,76 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"transaction-api-w-go/pkg/cache"
+	"transaction-api-w-go/pkg/service"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+)
+
+func TestExpireCacheKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name        string
+		key         string
+		wantExisted bool
+		// wantRemaining silme sonrası Redis'te kalması gereken anahtarlar
+		wantRemaining []string
+	}{
+		{name: "var olan anahtar silinir", key: "user:1", wantExisted: true, wantRemaining: []string{"user:10", "user:2"}},
+		{name: "olmayan anahtar", key: "user:3", wantRemaining: []string{"user:1", "user:10", "user:2"}},
+		{name: "desen olarak yorumlanmaz", key: "user:*", wantRemaining: []string{"user:1", "user:10", "user:2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := miniredis.RunT(t)
+			redisCache, err := cache.NewRedisCache(cache.CacheConfig{Addrs: []string{server.Addr()}}, nil)
+			if err != nil {
+				t.Fatalf("NewRedisCache: %v", err)
+			}
+			t.Cleanup(func() { redisCache.Close() })
+			for _, key := range []string{"user:1", "user:10", "user:2"} {
+				server.Set(key, "{}")
+			}
+
+			handler := NewCacheHandler(service.NewCacheService(redisCache, nil, nil, nil, nil, nil))
+			r := gin.New()
+			r.DELETE("/cache/key/:key", handler.ExpireCacheKey)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/cache/key/"+tt.key, nil))
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, beklenen 200; body: %s", w.Code, w.Body.String())
+			}
+
+			var body struct {
+				Key     string `json:"key"`
+				Existed *bool  `json:"existed"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("yanıt çözülemedi: %v", err)
+			}
+			if body.Key != tt.key || body.Existed == nil || *body.Existed != tt.wantExisted {
+				t.Errorf("yanıt = %s, beklenen key %q existed %v", w.Body.String(), tt.key, tt.wantExisted)
+			}
+
+			remaining := server.Keys()
+			if len(remaining) != len(tt.wantRemaining) {
+				t.Fatalf("kalan anahtarlar = %v, beklenen %v", remaining, tt.wantRemaining)
+			}
+			for i := range remaining {
+				if remaining[i] != tt.wantRemaining[i] {
+					t.Errorf("kalan anahtarlar = %v, beklenen %v", remaining, tt.wantRemaining)
+					break
+				}
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package server
 
 import (
+	"encoding/json"
 	"net/http"
 	"strconv"
 	"time"
@@ -10,6 +11,15 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// exportBatchSize export sırasında veritabanından tek seferde okunan event sayısı
+	exportBatchSize = 500
+	// exportWriteTimeout her batch yazılmadan önce yazma deadline'ı bu kadar uzatılır;
+	// sunucunun genel WriteTimeout'u uzun süren export'ları kesmez
+	exportWriteTimeout = 30 * time.Second
 )
 
 type EventHandler struct {
@@ -32,76 +42,43 @@ func (h *EventHandler) GetEventsByAggregate(c *gin.Context) {
 		return
 	}
 
-	events, err := h.eventStore.GetEvents(c.Request.Context(), aggregateID)
+	limit, offset, err := domain.ParsePageParams(c.Query("limit"), c.Query("offset"))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	eventResponses := make([]gin.H, len(events))
-	for i, event := range events {
-		eventResponses[i] = gin.H{
-			"id":           event.GetID(),
-			"type":         event.GetType(),
-			"aggregate_id": event.GetAggregateID(),
-			"version":      event.GetVersion(),
-			"timestamp":    event.GetTimestamp(),
-			"data":         event.GetData(),
-			"metadata":     event.GetMetadata(),
-		}
+	events, err := h.eventStore.GetEvents(c.Request.Context(), aggregateID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"aggregate_id": aggregateID,
-		"events":       eventResponses,
-		"count":        len(events),
-	})
+	c.JSON(http.StatusOK, domain.Paginate(eventResponses(events), limit, offset))
 }
 
 func (h *EventHandler) GetEventsByType(c *gin.Context) {
 	eventType := domain.EventType(c.Param("event_type"))
 
-	limitStr := c.DefaultQuery("limit", "100")
-	offsetStr := c.DefaultQuery("offset", "0")
-
-	limit, err := strconv.Atoi(limitStr)
+	limit, offset, err := domain.ParsePageParams(c.Query("limit"), c.Query("offset"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit parameter"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	offset, err := strconv.Atoi(offsetStr)
+	events, err := h.eventStore.GetEventsByType(c.Request.Context(), eventType, limit, offset)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid offset parameter"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	events, err := h.eventStore.GetEventsByType(c.Request.Context(), eventType, limit, offset)
+	total, err := h.eventStore.CountEventsByType(c.Request.Context(), eventType)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	eventResponses := make([]gin.H, len(events))
-	for i, event := range events {
-		eventResponses[i] = gin.H{
-			"id":           event.GetID(),
-			"type":         event.GetType(),
-			"aggregate_id": event.GetAggregateID(),
-			"version":      event.GetVersion(),
-			"timestamp":    event.GetTimestamp(),
-			"data":         event.GetData(),
-			"metadata":     event.GetMetadata(),
-		}
-	}
-
-	c.JSON(http.StatusOK, gin.H{
-		"event_type": eventType,
-		"events":     eventResponses,
-		"count":      len(events),
-		"limit":      limit,
-		"offset":     offset,
-	})
+	c.JSON(http.StatusOK, domain.NewPage(eventResponses(events), limit, offset, total))
 }
 
 func (h *EventHandler) GetEventsByTimeRange(c *gin.Context) {
@@ -125,74 +102,154 @@ func (h *EventHandler) GetEventsByTimeRange(c *gin.Context) {
 		return
 	}
 
-	events, err := h.eventStore.GetEventsByTimeRange(c.Request.Context(), startTime, endTime)
+	limit, offset, err := domain.ParsePageParams(c.Query("limit"), c.Query("offset"))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	eventResponses := make([]gin.H, len(events))
-	for i, event := range events {
-		eventResponses[i] = gin.H{
-			"id":           event.GetID(),
-			"type":         event.GetType(),
-			"aggregate_id": event.GetAggregateID(),
-			"version":      event.GetVersion(),
-			"timestamp":    event.GetTimestamp(),
-			"data":         event.GetData(),
-			"metadata":     event.GetMetadata(),
-		}
+	events, err := h.eventStore.GetEventsByTimeRange(c.Request.Context(), startTime, endTime)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"start_time": startTime,
-		"end_time":   endTime,
-		"events":     eventResponses,
-		"count":      len(events),
-	})
+	c.JSON(http.StatusOK, domain.Paginate(eventResponses(events), limit, offset))
 }
 
 func (h *EventHandler) GetAllEvents(c *gin.Context) {
-	limitStr := c.DefaultQuery("limit", "100")
-	offsetStr := c.DefaultQuery("offset", "0")
-
-	limit, err := strconv.Atoi(limitStr)
+	limit, offset, err := domain.ParsePageParams(c.Query("limit"), c.Query("offset"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit parameter"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	offset, err := strconv.Atoi(offsetStr)
+	events, err := h.eventStore.GetAllEvents(c.Request.Context(), limit, offset)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid offset parameter"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	events, err := h.eventStore.GetAllEvents(c.Request.Context(), limit, offset)
+	total, err := h.eventStore.CountAllEvents(c.Request.Context())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	eventResponses := make([]gin.H, len(events))
-	for i, event := range events {
-		eventResponses[i] = gin.H{
-			"id":           event.GetID(),
-			"type":         event.GetType(),
-			"aggregate_id": event.GetAggregateID(),
-			"version":      event.GetVersion(),
-			"timestamp":    event.GetTimestamp(),
-			"data":         event.GetData(),
-			"metadata":     event.GetMetadata(),
+	c.JSON(http.StatusOK, domain.NewPage(eventResponses(events), limit, offset, total))
+}
+
+// ExportEvents [from, to] aralığındaki event'leri NDJSON (satır başına bir JSON) olarak
+// stream eder. Event'ler keyset sayfalama ile batch'ler halinde okunduğu için bellek
+// kullanımı toplam event sayısından bağımsızdır; cursor verilirse export oradan devam eder.
+func (h *EventHandler) ExportEvents(c *gin.Context) {
+	if format := c.DefaultQuery("format", "ndjson"); format != "ndjson" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unsupported export format. Supported formats: ndjson"})
+		return
+	}
+
+	// TIMESTAMP kolonu 1970-01-01 00:00:01'den önceki değerleri kabul etmez
+	from := time.Unix(1, 0).UTC()
+	to := time.Now().UTC()
+	if value := c.Query("from"); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from format. Use RFC3339 format"})
+			return
 		}
+		from = parsed
+	}
+	if value := c.Query("to"); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to format. Use RFC3339 format"})
+			return
+		}
+		to = parsed
+	}
+	if from.After(to) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from must be before to"})
+		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"events": eventResponses,
-		"count":  len(events),
-		"limit":  limit,
-		"offset": offset,
-	})
+	var cursor *domain.EventCursor
+	if value := c.Query("cursor"); value != "" {
+		decoded, err := domain.DecodeEventCursor(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		cursor = decoded
+	}
+
+	ctx := c.Request.Context()
+	controller := http.NewResponseController(c.Writer)
+	encoder := json.NewEncoder(c.Writer)
+
+	c.Header("Content-Type", "application/x-ndjson")
+	c.Header("Content-Disposition", "attachment; filename=events.ndjson")
+	c.Status(http.StatusOK)
+
+	exported := 0
+	for {
+		events, err := h.eventStore.GetEventsAfter(ctx, from, to, cursor, exportBatchSize)
+		if err != nil {
+			if !c.Writer.Written() {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+			// Başlıklar gönderildiği için durum kodu değiştirilemez; istemci eksik export'u
+			// son satırdaki cursor ile devam ettirebilir
+			log.Error().Err(err).Int("exported", exported).Msg("Event export yarıda kesildi")
+			return
+		}
+		if len(events) == 0 {
+			break
+		}
+
+		controller.SetWriteDeadline(time.Now().Add(exportWriteTimeout))
+		for _, event := range events {
+			line := eventResponse(event)
+			line["cursor"] = domain.CursorAfter(event).Encode()
+			if err := encoder.Encode(line); err != nil {
+				log.Warn().Err(err).Int("exported", exported).Msg("Event export istemci tarafından kesildi")
+				return
+			}
+			exported++
+		}
+		controller.Flush()
+
+		if len(events) < exportBatchSize {
+			break
+		}
+		cursor = domain.CursorAfter(events[len(events)-1])
+	}
+
+	log.Info().
+		Time("from", from).
+		Time("to", to).
+		Int("exported", exported).
+		Msg("Event export tamamlandı")
+}
+
+func eventResponses(events []domain.Event) []gin.H {
+	responses := make([]gin.H, len(events))
+	for i, event := range events {
+		responses[i] = eventResponse(event)
+	}
+	return responses
+}
+
+func eventResponse(event domain.Event) gin.H {
+	return gin.H{
+		"id":           event.GetID(),
+		"type":         event.GetType(),
+		"aggregate_id": event.GetAggregateID(),
+		"version":      event.GetVersion(),
+		"timestamp":    event.GetTimestamp(),
+		"data":         event.GetData(),
+		"metadata":     event.GetMetadata(),
+	}
 }
 
 func (h *EventHandler) ReplayEventsForAggregate(c *gin.Context) {
@@ -303,8 +360,9 @@ func (h *EventHandler) ReplayAllEvents(c *gin.Context) {
 	})
 }
 
+// GetReplayStatistics her çağrıda yeniden hesaplama yapmaz; önbellekteki özeti döndürür
 func (h *EventHandler) GetReplayStatistics(c *gin.Context) {
-	stats, err := h.eventReplayService.GetReplayStatistics(c.Request.Context())
+	stats, err := h.eventReplayService.GetCachedStatistics(c.Request.Context())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -315,6 +373,21 @@ func (h *EventHandler) GetReplayStatistics(c *gin.Context) {
 		"total_aggregates":      stats.TotalAggregates,
 		"event_type_counts":     stats.EventTypeCounts,
 		"aggregate_type_counts": stats.AggregateTypeCounts,
+		"computed_at":           stats.ComputedAt,
+	})
+}
+
+// GetEventStatistics event sayılarının önbellekli özetini döndürür
+func (h *EventHandler) GetEventStatistics(c *gin.Context) {
+	stats, err := h.eventReplayService.GetCachedStatistics(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"statistics":        stats,
+		"cache_ttl_seconds": int(service.EventStatisticsCacheTTL.Seconds()),
 	})
 }
 
@@ -0,0 +1,209 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"transaction-api-w-go/pkg/database/databasetest"
+	"transaction-api-w-go/pkg/domain"
+	"transaction-api-w-go/pkg/repository"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func TestEventHandlerPaginationEnvelope(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db := databasetest.Open(t)
+	eventStore := repository.NewPostgresEventStore(db)
+	ctx := context.Background()
+
+	// Bir aggregate'e üç, diğerine iki event yazılır
+	aggregates := []uuid.UUID{uuid.New(), uuid.New()}
+	for i, count := range []int{3, 2} {
+		events := make([]domain.Event, count)
+		for j := range events {
+			events[j] = domain.NewTransactionCreatedEvent(&domain.Transaction{ID: aggregates[i], UserID: uuid.New(), Type: domain.TransactionTypeCredit, Amount: 10})
+		}
+		if err := eventStore.SaveEvents(ctx, aggregates[i], events, 0); err != nil {
+			t.Fatalf("SaveEvents: %v", err)
+		}
+	}
+
+	handler := NewEventHandler(nil, eventStore)
+	engine := gin.New()
+	engine.GET("/events", handler.GetAllEvents)
+	engine.GET("/events/aggregate/:aggregate_id", handler.GetEventsByAggregate)
+	engine.GET("/events/type/:event_type", handler.GetEventsByType)
+
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+		wantData   int
+		wantLimit  int
+		wantOffset int
+		wantTotal  int64
+	}{
+		{name: "tüm event'ler", path: "/events?limit=2&offset=1", wantStatus: http.StatusOK, wantData: 2, wantLimit: 2, wantOffset: 1, wantTotal: 5},
+		{name: "aggregate event'leri", path: "/events/aggregate/" + aggregates[0].String() + "?limit=2&offset=2", wantStatus: http.StatusOK, wantData: 1, wantLimit: 2, wantOffset: 2, wantTotal: 3},
+		{name: "tipe göre event'ler", path: "/events/type/" + string(domain.EventTransactionCreated), wantStatus: http.StatusOK, wantData: 5, wantLimit: domain.DefaultPageLimit, wantTotal: 5},
+		{name: "eşleşme yoksa boş data", path: "/events/aggregate/" + uuid.NewString(), wantStatus: http.StatusOK, wantLimit: domain.DefaultPageLimit},
+		{name: "geçersiz limit", path: "/events?limit=0", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, beklenen %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var body struct {
+				Data []json.RawMessage `json:"data"`
+				Page *struct {
+					Limit  int   `json:"limit"`
+					Offset int   `json:"offset"`
+					Total  int64 `json:"total"`
+				} `json:"page"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("yanıt çözülemedi: %v", err)
+			}
+			if body.Data == nil || body.Page == nil {
+				t.Fatalf("zarf eksik: %s", w.Body.String())
+			}
+			if len(body.Data) != tt.wantData {
+				t.Errorf("data uzunluğu = %d, beklenen %d", len(body.Data), tt.wantData)
+			}
+			if body.Page.Limit != tt.wantLimit || body.Page.Offset != tt.wantOffset || body.Page.Total != tt.wantTotal {
+				t.Errorf("page = %+v, beklenen %d/%d/%d", *body.Page, tt.wantLimit, tt.wantOffset, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestEventHandlerExportEvents(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db := databasetest.Open(t)
+	eventStore := repository.NewPostgresEventStore(db)
+	ctx := context.Background()
+
+	// Her saniyeye iki event düşer; aynı zaman damgalı event'ler cursor'ın id ile sıralamasını sınar
+	base := time.Now().UTC().Truncate(time.Second).Add(-time.Hour)
+	aggregateID := uuid.New()
+	events := make([]domain.Event, 2*exportBatchSize+200)
+	for i := range events {
+		event := domain.NewTransactionCreatedEvent(&domain.Transaction{ID: aggregateID, UserID: uuid.New(), Type: domain.TransactionTypeCredit, Amount: float64(i)})
+		event.Timestamp = base.Add(time.Duration(i/2) * time.Second)
+		events[i] = event
+	}
+	if err := eventStore.AppendEvents(ctx, []domain.EventAppend{{AggregateID: aggregateID, Events: events}}); err != nil {
+		t.Fatalf("AppendEvents: %v", err)
+	}
+
+	engine := gin.New()
+	engine.GET("/events/export", NewEventHandler(nil, eventStore).ExportEvents)
+
+	type exportLine struct {
+		ID        uuid.UUID `json:"id"`
+		Timestamp time.Time `json:"timestamp"`
+		Cursor    string    `json:"cursor"`
+	}
+	export := func(t *testing.T, query url.Values) (int, []exportLine) {
+		t.Helper()
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/events/export?"+query.Encode(), nil))
+		if w.Code != http.StatusOK {
+			return w.Code, nil
+		}
+		if ct := w.Header().Get("Content-Type"); ct != "application/x-ndjson" {
+			t.Errorf("Content-Type = %s, beklenen application/x-ndjson", ct)
+		}
+
+		var lines []exportLine
+		scanner := bufio.NewScanner(w.Body)
+		for scanner.Scan() {
+			var line exportLine
+			if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+				t.Fatalf("%d. satır çözülemedi: %v: %s", len(lines)+1, err, scanner.Text())
+			}
+			lines = append(lines, line)
+		}
+		if err := scanner.Err(); err != nil {
+			t.Fatalf("yanıt okunamadı: %v", err)
+		}
+		return w.Code, lines
+	}
+
+	from := base.Add(100 * time.Second)
+	to := base.Add(599 * time.Second)
+	rangeQuery := url.Values{"from": {from.Format(time.RFC3339)}, "to": {to.Format(time.RFC3339)}}
+
+	// Aralığın tamamı iki tam batch'tir; sonraki test ortasından devam eder
+	_, full := export(t, rangeQuery)
+	if len(full) <= 700 {
+		t.Fatalf("aralık export'u %d satır döndü", len(full))
+	}
+	resume := url.Values{"from": rangeQuery["from"], "to": rangeQuery["to"], "cursor": {full[699].Cursor}}
+
+	tests := []struct {
+		name       string
+		query      url.Values
+		wantStatus int
+		wantLines  int
+		wantFirst  time.Time
+	}{
+		{name: "aralık iki batch'te eksiksiz akar", query: rangeQuery, wantStatus: http.StatusOK, wantLines: 1000, wantFirst: from},
+		{name: "cursor'dan devam edilir", query: resume, wantStatus: http.StatusOK, wantLines: 300, wantFirst: full[700].Timestamp},
+		{name: "parametresiz tüm event'ler", query: url.Values{}, wantStatus: http.StatusOK, wantLines: len(events), wantFirst: base},
+		{name: "desteklenmeyen format", query: url.Values{"format": {"csv"}}, wantStatus: http.StatusBadRequest},
+		{name: "geçersiz cursor", query: url.Values{"cursor": {"bozuk"}}, wantStatus: http.StatusBadRequest},
+		{name: "ters aralık", query: url.Values{"from": rangeQuery["to"], "to": rangeQuery["from"]}, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, lines := export(t, tt.query)
+			if status != tt.wantStatus {
+				t.Fatalf("status = %d, beklenen %d", status, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			if len(lines) != tt.wantLines {
+				t.Fatalf("satır sayısı = %d, beklenen %d", len(lines), tt.wantLines)
+			}
+			if !lines[0].Timestamp.Equal(tt.wantFirst) {
+				t.Errorf("ilk event zamanı = %v, beklenen %v", lines[0].Timestamp, tt.wantFirst)
+			}
+
+			seen := make(map[uuid.UUID]bool, len(lines))
+			for i, line := range lines {
+				if seen[line.ID] {
+					t.Fatalf("%s iki kez export edildi", line.ID)
+				}
+				seen[line.ID] = true
+				if i > 0 && line.Timestamp.Before(lines[i-1].Timestamp) {
+					t.Fatalf("%d. satır zaman sırasını bozuyor", i+1)
+				}
+				if from, ok := tt.query["from"]; ok && line.Timestamp.Format(time.RFC3339) < from[0] {
+					t.Fatalf("%v aralığın dışında", line.Timestamp)
+				}
+			}
+		})
+	}
+}
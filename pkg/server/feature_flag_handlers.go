@@ -0,0 +1,102 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+
+	"transaction-api-w-go/pkg/domain"
+	"transaction-api-w-go/pkg/featureflag"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FeatureFlagHandler struct {
+	flags *featureflag.Service
+}
+
+func NewFeatureFlagHandler(flags *featureflag.Service) *FeatureFlagHandler {
+	return &FeatureFlagHandler{
+		flags: flags,
+	}
+}
+
+// UpdateFeatureFlagRequest percentage verilmezse flag açıkken herkese (100), kapalıyken 0 kabul edilir
+type UpdateFeatureFlagRequest struct {
+	Enabled    bool     `json:"enabled"`
+	Percentage *int     `json:"percentage"`
+	Users      []string `json:"users"`
+}
+
+func featureFlagErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrFeatureFlagNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrInvalidFeatureFlag):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func (h *FeatureFlagHandler) ListFlags(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"flags": h.flags.List(c.Request.Context()),
+	})
+}
+
+func (h *FeatureFlagHandler) GetFlag(c *gin.Context) {
+	flag, err := h.flags.Get(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		c.JSON(featureFlagErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	response := gin.H{"flag": flag}
+	if userID := c.Query("user_id"); userID != "" {
+		response["user_id"] = userID
+		response["enabled_for_user"] = flag.EnabledFor(userID)
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *FeatureFlagHandler) UpdateFlag(c *gin.Context) {
+	var req UpdateFeatureFlagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	flag := featureflag.Flag{
+		Name:    c.Param("name"),
+		Enabled: req.Enabled,
+		Users:   req.Users,
+	}
+	switch {
+	case req.Percentage != nil:
+		flag.Percentage = *req.Percentage
+	case req.Enabled:
+		flag.Percentage = 100
+	}
+
+	updated, err := h.flags.Set(c.Request.Context(), flag)
+	if err != nil {
+		c.JSON(featureFlagErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"flag": updated,
+	})
+}
+
+func (h *FeatureFlagHandler) ResetFlag(c *gin.Context) {
+	flag, err := h.flags.Reset(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		c.JSON(featureFlagErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"flag": flag,
+	})
+}
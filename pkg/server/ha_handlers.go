@@ -1,10 +1,14 @@
 package server
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"time"
 
+	"transaction-api-w-go/pkg/bulkhead"
 	"transaction-api-w-go/pkg/circuitbreaker"
 	"transaction-api-w-go/pkg/database"
 	"transaction-api-w-go/pkg/fallback"
@@ -14,18 +18,25 @@ import (
 )
 
 type HAHandler struct {
+	// ctx uygulamanın kök context'i; çalışma zamanında oluşturulan breaker'lar buna bağlanır
+	ctx             context.Context
 	dbCluster       *database.DatabaseCluster
 	loadBalancer    *loadbalancer.LoadBalancer
 	circuitBreakers map[string]*circuitbreaker.CircuitBreaker
 	fallbackManager *fallback.FallbackManager
+	// bulkheads nil ise bulkhead istatistikleri boş döner
+	bulkheads *bulkhead.Registry
 }
 
+// NewHAHandler dbCluster nil olabilir; bu durumda veritabanı endpoint'leri 503 döner
 func NewHAHandler(
+	ctx context.Context,
 	dbCluster *database.DatabaseCluster,
 	loadBalancer *loadbalancer.LoadBalancer,
 	fallbackManager *fallback.FallbackManager,
 ) *HAHandler {
 	return &HAHandler{
+		ctx:             ctx,
 		dbCluster:       dbCluster,
 		loadBalancer:    loadBalancer,
 		circuitBreakers: make(map[string]*circuitbreaker.CircuitBreaker),
@@ -33,7 +44,45 @@ func NewHAHandler(
 	}
 }
 
+// RegisterCircuitBreaker uygulamanın kendi oluşturduğu breaker'ı (ör. Redis cache)
+// HA endpoint'leri üzerinden izlenebilir ve yönetilebilir yapar; route'lar kurulmadan önce çağrılmalıdır
+func (h *HAHandler) RegisterCircuitBreaker(name string, breaker *circuitbreaker.CircuitBreaker) {
+	h.circuitBreakers[name] = breaker
+}
+
+// RegisterBulkheads işlem sınıfı bulkhead'lerini HA endpoint'lerinden izlenebilir yapar
+func (h *HAHandler) RegisterBulkheads(registry *bulkhead.Registry) {
+	h.bulkheads = registry
+}
+
+// clusterStatus cluster yapılandırılmamışsa boş sağlık ve istatistik döner
+func (h *HAHandler) clusterStatus() (map[string]database.HealthCheckResult, map[string]interface{}) {
+	if h.dbCluster == nil {
+		return map[string]database.HealthCheckResult{}, map[string]interface{}{
+			"configured":           false,
+			"slave_count":          0,
+			"active_slaves":        0,
+			"read_replica_count":   0,
+			"active_read_replicas": 0,
+			"failover_enabled":     false,
+		}
+	}
+	return h.dbCluster.GetHealthStatus(), h.dbCluster.GetClusterStats()
+}
+
+func (h *HAHandler) requireCluster(c *gin.Context) bool {
+	if h.dbCluster == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Database cluster is not configured"})
+		return false
+	}
+	return true
+}
+
 func (h *HAHandler) GetDatabaseHealth(c *gin.Context) {
+	if !h.requireCluster(c) {
+		return
+	}
+
 	healthStatus := h.dbCluster.GetHealthStatus()
 	clusterStats := h.dbCluster.GetClusterStats()
 
@@ -45,6 +94,10 @@ func (h *HAHandler) GetDatabaseHealth(c *gin.Context) {
 }
 
 func (h *HAHandler) GetDatabaseNodeHealth(c *gin.Context) {
+	if !h.requireCluster(c) {
+		return
+	}
+
 	nodeName := c.Param("node")
 	healthStatus := h.dbCluster.GetHealthStatus()
 
@@ -80,9 +133,12 @@ func (h *HAHandler) GetLoadBalancerStats(c *gin.Context) {
 
 func (h *HAHandler) AddLoadBalancerBackend(c *gin.Context) {
 	var req struct {
-		ID     string `json:"id" binding:"required"`
-		URL    string `json:"url" binding:"required"`
-		Weight int    `json:"weight"`
+		ID       string            `json:"id" binding:"required"`
+		URL      string            `json:"url" binding:"required"`
+		Weight   int               `json:"weight"`
+		Region   string            `json:"region"`
+		Tags     []string          `json:"tags"`
+		Metadata map[string]string `json:"metadata"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -96,9 +152,15 @@ func (h *HAHandler) AddLoadBalancerBackend(c *gin.Context) {
 		Weight:   req.Weight,
 		IsActive: true,
 		Health:   1.0,
+		Region:   req.Region,
+		Tags:     req.Tags,
+		Metadata: req.Metadata,
 	}
 
-	h.loadBalancer.AddBackend(backend)
+	if err := h.loadBalancer.RegisterBackend(c.Request.Context(), backend); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
 
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "Backend added successfully",
@@ -108,13 +170,29 @@ func (h *HAHandler) AddLoadBalancerBackend(c *gin.Context) {
 
 func (h *HAHandler) RemoveLoadBalancerBackend(c *gin.Context) {
 	backendID := c.Param("id")
-	h.loadBalancer.RemoveBackend(backendID)
+	if err := h.loadBalancer.DeregisterBackend(c.Request.Context(), backendID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Backend removed successfully",
 	})
 }
 
+// CheckLoadBalancerHealth periyodik kontrolü beklemeden tüm backend'lerin health check'ini çalıştırır
+func (h *HAHandler) CheckLoadBalancerHealth(c *gin.Context) {
+	backends := h.loadBalancer.CheckHealthNow()
+	status, reason := h.loadBalancer.Status()
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":    status,
+		"reason":    reason,
+		"backends":  backends,
+		"timestamp": time.Now(),
+	})
+}
+
 func (h *HAHandler) GetCircuitBreakerStats(c *gin.Context) {
 	breakerName := c.Param("name")
 
@@ -133,6 +211,24 @@ func (h *HAHandler) GetCircuitBreakerStats(c *gin.Context) {
 	})
 }
 
+func (h *HAHandler) GetCircuitBreakerHistory(c *gin.Context) {
+	breakerName := c.Param("name")
+
+	breaker, exists := h.circuitBreakers[breakerName]
+	if !exists {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Circuit breaker not found",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"name":        breakerName,
+		"transitions": breaker.GetHistory(),
+		"timestamp":   time.Now(),
+	})
+}
+
 func (h *HAHandler) GetAllCircuitBreakers(c *gin.Context) {
 	allStats := make(map[string]interface{})
 
@@ -161,7 +257,7 @@ func (h *HAHandler) CreateCircuitBreaker(c *gin.Context) {
 		req.Config = circuitbreaker.DefaultConfig()
 	}
 
-	breaker := circuitbreaker.NewCircuitBreaker(req.Name, req.Config)
+	breaker := circuitbreaker.NewCircuitBreakerWithContext(h.ctx, req.Name, req.Config)
 	h.circuitBreakers[req.Name] = breaker
 
 	c.JSON(http.StatusCreated, gin.H{
@@ -228,6 +324,14 @@ func (h *HAHandler) ResetCircuitBreaker(c *gin.Context) {
 	})
 }
 
+// GetBulkheadStats işlem sınıflarının doluluk ve reddetme sayılarını döner
+func (h *HAHandler) GetBulkheadStats(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"bulkheads": h.bulkheads.GetStats(),
+		"timestamp": time.Now(),
+	})
+}
+
 func (h *HAHandler) GetFallbackStats(c *gin.Context) {
 	stats := h.fallbackManager.GetStats()
 
@@ -281,10 +385,10 @@ func (h *HAHandler) TestFallback(c *gin.Context) {
 }
 
 func (h *HAHandler) GetSystemHealth(c *gin.Context) {
-	dbHealth := h.dbCluster.GetHealthStatus()
-	dbStats := h.dbCluster.GetClusterStats()
+	dbHealth, dbStats := h.clusterStatus()
 
 	lbStats := h.loadBalancer.GetStats()
+	lbStatus, lbReason := h.loadBalancer.Status()
 
 	cbStats := make(map[string]interface{})
 	for name, breaker := range h.circuitBreakers {
@@ -296,13 +400,13 @@ func (h *HAHandler) GetSystemHealth(c *gin.Context) {
 	systemStatus := "healthy"
 
 	for _, health := range dbHealth {
-		if health.Status != "healthy" {
+		if health.Status == "unhealthy" {
 			systemStatus = "degraded"
 			break
 		}
 	}
 
-	if lbStats["active_backends"].(int) == 0 {
+	if lbStatus != loadbalancer.StatusHealthy {
 		systemStatus = "degraded"
 	}
 
@@ -320,7 +424,9 @@ func (h *HAHandler) GetSystemHealth(c *gin.Context) {
 			"stats":  dbStats,
 		},
 		"load_balancer": gin.H{
-			"stats": lbStats,
+			"status": lbStatus,
+			"reason": lbReason,
+			"stats":  lbStats,
 		},
 		"circuit_breakers": cbStats,
 		"fallback": gin.H{
@@ -331,6 +437,8 @@ func (h *HAHandler) GetSystemHealth(c *gin.Context) {
 }
 
 func (h *HAHandler) GetHAConfig(c *gin.Context) {
+	lbConfig := h.loadBalancer.Config()
+
 	config := gin.H{
 		"database": gin.H{
 			"replication_enabled":   true,
@@ -338,8 +446,9 @@ func (h *HAHandler) GetHAConfig(c *gin.Context) {
 			"health_check_interval": "30s",
 		},
 		"load_balancer": gin.H{
-			"strategy":              "round_robin",
-			"health_check_interval": "30s",
+			"strategy":              h.loadBalancer.StrategyName(),
+			"health_check_interval": lbConfig.HealthCheckInterval.String(),
+			"health_check_timeout":  lbConfig.HealthCheckTimeout.String(),
 		},
 		"circuit_breaker": gin.H{
 			"default_config": circuitbreaker.DefaultConfig(),
@@ -359,9 +468,112 @@ func (h *HAHandler) GetHAConfig(c *gin.Context) {
 	})
 }
 
+// circuitBreakerConfigPatch UpdateHAConfig ile gelen breaker ayarları; gönderilmeyen alanlar korunur.
+// Süreler "30s" gibi time.ParseDuration formatında verilir.
+type circuitBreakerConfigPatch struct {
+	FailureThreshold    *int    `json:"failure_threshold"`
+	SuccessThreshold    *int    `json:"success_threshold"`
+	Timeout             *string `json:"timeout"`
+	HalfOpenMaxRequests *int    `json:"half_open_max_requests"`
+	WindowSize          *string `json:"window_size"`
+	MinRequestCount     *int    `json:"min_request_count"`
+}
+
+// loadBalancerConfigPatch UpdateHAConfig ile gelen load balancer ayarları
+type loadBalancerConfigPatch struct {
+	Strategy            *string `json:"strategy"`
+	HealthCheckInterval *string `json:"health_check_interval"`
+	HealthCheckTimeout  *string `json:"health_check_timeout"`
+}
+
+// decodeConfigPatch serbest config map'ini patch struct'ına çevirir; bilinmeyen alanlar hata döner
+func decodeConfigPatch(raw map[string]interface{}, patch interface{}) error {
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(patch)
+}
+
+func applyDuration(field string, value *string, target *time.Duration) error {
+	if value == nil {
+		return nil
+	}
+	duration, err := time.ParseDuration(*value)
+	if err != nil {
+		return fmt.Errorf("%s: %v", field, err)
+	}
+	*target = duration
+	return nil
+}
+
+func applyPositiveDuration(field string, value *string, target *time.Duration) error {
+	var duration time.Duration
+	if err := applyDuration(field, value, &duration); err != nil || value == nil {
+		return err
+	}
+	if duration <= 0 {
+		return fmt.Errorf("%s must be positive", field)
+	}
+	*target = duration
+	return nil
+}
+
+// apply gönderilen alanları mevcut ayarların üzerine yazar; değer doğrulaması
+// CircuitBreaker.UpdateConfig içinde yapılır
+func (p circuitBreakerConfigPatch) apply(config circuitbreaker.Config) (circuitbreaker.Config, error) {
+	if p.FailureThreshold != nil {
+		config.FailureThreshold = *p.FailureThreshold
+	}
+	if p.SuccessThreshold != nil {
+		config.SuccessThreshold = *p.SuccessThreshold
+	}
+	if p.HalfOpenMaxRequests != nil {
+		config.HalfOpenMaxRequests = *p.HalfOpenMaxRequests
+	}
+	if p.MinRequestCount != nil {
+		config.MinRequestCount = *p.MinRequestCount
+	}
+	if err := applyDuration("timeout", p.Timeout, &config.Timeout); err != nil {
+		return config, err
+	}
+	if err := applyDuration("window_size", p.WindowSize, &config.WindowSize); err != nil {
+		return config, err
+	}
+	return config, nil
+}
+
+func (p loadBalancerConfigPatch) apply(config loadbalancer.LoadBalancerConfig) (loadbalancer.LoadBalancerConfig, error) {
+	if err := applyPositiveDuration("health_check_interval", p.HealthCheckInterval, &config.HealthCheckInterval); err != nil {
+		return config, err
+	}
+	if err := applyPositiveDuration("health_check_timeout", p.HealthCheckTimeout, &config.HealthCheckTimeout); err != nil {
+		return config, err
+	}
+	return config, nil
+}
+
+func circuitBreakerConfigResponse(name string, config circuitbreaker.Config) gin.H {
+	return gin.H{
+		"name":                   name,
+		"failure_threshold":      config.FailureThreshold,
+		"success_threshold":      config.SuccessThreshold,
+		"timeout":                config.Timeout.String(),
+		"half_open_max_requests": config.HalfOpenMaxRequests,
+		"window_size":            config.WindowSize.String(),
+		"min_request_count":      config.MinRequestCount,
+	}
+}
+
+// UpdateHAConfig çalışma zamanında bir bileşenin ayarlarını değiştirir.
+// Desteklenen bileşenler: "circuit_breaker" (name zorunlu) ve "load_balancer".
 func (h *HAHandler) UpdateHAConfig(c *gin.Context) {
 	var req struct {
 		Component string                 `json:"component" binding:"required"`
+		Name      string                 `json:"name"`
 		Config    map[string]interface{} `json:"config" binding:"required"`
 	}
 
@@ -370,15 +582,93 @@ func (h *HAHandler) UpdateHAConfig(c *gin.Context) {
 		return
 	}
 
+	var applied gin.H
+	switch req.Component {
+	case "circuit_breaker":
+		if req.Name == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "name is required for circuit_breaker"})
+			return
+		}
+
+		breaker, exists := h.circuitBreakers[req.Name]
+		if !exists {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Circuit breaker not found",
+			})
+			return
+		}
+
+		var patch circuitBreakerConfigPatch
+		if err := decodeConfigPatch(req.Config, &patch); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		config, err := patch.apply(breaker.Config())
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if err := breaker.UpdateConfig(config); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		applied = circuitBreakerConfigResponse(req.Name, breaker.Config())
+
+	case "load_balancer":
+		var patch loadBalancerConfigPatch
+		if err := decodeConfigPatch(req.Config, &patch); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		// Strateji ve süreler uygulanmadan önce doğrulanır; hatalı istek kısmi değişiklik bırakmaz
+		var strategy loadbalancer.LoadBalancingStrategy
+		if patch.Strategy != nil {
+			var err error
+			strategy, err = loadbalancer.NewStrategy(*patch.Strategy)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+		}
+
+		config, err := patch.apply(h.loadBalancer.Config())
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if strategy != nil {
+			h.loadBalancer.SetStrategy(strategy)
+		}
+		h.loadBalancer.UpdateConfig(config)
+
+		lbConfig := h.loadBalancer.Config()
+		applied = gin.H{
+			"strategy":              h.loadBalancer.StrategyName(),
+			"health_check_interval": lbConfig.HealthCheckInterval.String(),
+			"health_check_timeout":  lbConfig.HealthCheckTimeout.String(),
+		}
+
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("unsupported component: %s", req.Component),
+		})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"message":   "Configuration updated successfully",
 		"component": req.Component,
+		"config":    applied,
 		"timestamp": time.Now(),
 	})
 }
 
 func (h *HAHandler) GetHAMetrics(c *gin.Context) {
-	dbStats := h.dbCluster.GetClusterStats()
+	_, dbStats := h.clusterStatus()
 
 	lbStats := h.loadBalancer.GetStats()
 
@@ -0,0 +1,269 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"transaction-api-w-go/pkg/circuitbreaker"
+	"transaction-api-w-go/pkg/database/databasetest"
+	"transaction-api-w-go/pkg/domain"
+	"transaction-api-w-go/pkg/fallback"
+	"transaction-api-w-go/pkg/loadbalancer"
+	"transaction-api-w-go/pkg/middleware"
+	"transaction-api-w-go/pkg/repository"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const testJWTSecret = "test-secret"
+
+func TestForceCircuitBreakerOpenWritesAuditLog(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		role       string
+		breaker    string
+		wantStatus int
+		wantAudit  bool
+		wantOpen   bool
+	}{
+		{name: "admin breaker'ı açar ve kaydedilir", role: "admin", breaker: "redis", wantStatus: http.StatusOK, wantAudit: true, wantOpen: true},
+		{name: "olmayan breaker denemesi de kaydedilir", role: "admin", breaker: "missing", wantStatus: http.StatusNotFound, wantAudit: true},
+		{name: "yetkisiz istek kaydedilmez", role: "user", breaker: "redis", wantStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auditRepo := repository.NewAuditRepository(databasetest.Open(t))
+			breaker := circuitbreaker.NewCircuitBreaker("redis", circuitbreaker.DefaultConfig())
+			handler := NewHAHandler(context.Background(), nil, nil, nil)
+			handler.RegisterCircuitBreaker("redis", breaker)
+
+			engine := gin.New()
+			engine.Use(middleware.RequestIDMiddleware())
+			ha := engine.Group("/api/v1/ha")
+			ha.Use(middleware.AuthMiddleware(testJWTSecret), middleware.RoleMiddleware("admin"), middleware.AuditMiddleware(auditRepo))
+			ha.POST("/circuitbreakers/:name/open", handler.ForceCircuitBreakerOpen)
+
+			token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+				"user_id": "actor-1",
+				"role":    tt.role,
+				"exp":     time.Now().Add(time.Hour).Unix(),
+			}).SignedString([]byte(testJWTSecret))
+			if err != nil {
+				t.Fatalf("token imzalanamadı: %v", err)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/ha/circuitbreakers/"+tt.breaker+"/open", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			req.Header.Set(middleware.RequestIDHeader, "req-1")
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, beklenen %d", w.Code, tt.wantStatus)
+			}
+			if open := breaker.GetState() == circuitbreaker.StateOpen; open != tt.wantOpen {
+				t.Errorf("breaker açık = %v, beklenen %v", open, tt.wantOpen)
+			}
+
+			entries, total, err := auditRepo.List(context.Background(), domain.AuditLogFilter{})
+			if err != nil {
+				t.Fatalf("audit kayıtları okunamadı: %v", err)
+			}
+			if !tt.wantAudit {
+				if total != 0 {
+					t.Errorf("audit kaydı sayısı = %d, beklenen 0", total)
+				}
+				return
+			}
+			if total != 1 {
+				t.Fatalf("audit kaydı sayısı = %d, beklenen 1", total)
+			}
+
+			entry := entries[0]
+			if entry.ActorID != "actor-1" || entry.ActorRole != "admin" {
+				t.Errorf("aktör = %s/%s, beklenen actor-1/admin", entry.ActorID, entry.ActorRole)
+			}
+			if entry.Action != "POST /api/v1/ha/circuitbreakers/:name/open" {
+				t.Errorf("action = %q", entry.Action)
+			}
+			if entry.Target != "name="+tt.breaker {
+				t.Errorf("target = %q, beklenen %q", entry.Target, "name="+tt.breaker)
+			}
+			if entry.StatusCode != tt.wantStatus || entry.RequestID != "req-1" {
+				t.Errorf("status/request id = %d/%s, beklenen %d/req-1", entry.StatusCode, entry.RequestID, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestGetSystemHealthLoadBalancerBackends(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name        string
+		initial     []*loadbalancer.Backend
+		wantInitial string
+		wantReason  string
+		add         *loadbalancer.Backend
+		wantAfter   string
+	}{
+		{
+			name:        "backend yokken degraded, ilk backend ile healthy",
+			wantInitial: "degraded",
+			wantReason:  loadbalancer.ErrNoBackends.Error(),
+			add:         &loadbalancer.Backend{ID: "b1", IsActive: true, Health: 1},
+			wantAfter:   "healthy",
+		},
+		{
+			name:        "yalnız pasif backend varken degraded",
+			initial:     []*loadbalancer.Backend{{ID: "b1"}},
+			wantInitial: "degraded",
+			wantReason:  loadbalancer.ErrNoActiveBackends.Error(),
+			add:         &loadbalancer.Backend{ID: "b2", IsActive: true, Health: 1},
+			wantAfter:   "healthy",
+		},
+		{
+			name:        "pasif backend eklemek toparlamaz",
+			wantInitial: "degraded",
+			wantReason:  loadbalancer.ErrNoBackends.Error(),
+			add:         &loadbalancer.Backend{ID: "b1"},
+			wantAfter:   "degraded",
+		},
+	}
+
+	type systemHealth struct {
+		SystemStatus string `json:"system_status"`
+		LoadBalancer struct {
+			Status string `json:"status"`
+			Reason string `json:"reason"`
+		} `json:"load_balancer"`
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lb := loadbalancer.NewLoadBalancer(loadbalancer.NewRoundRobinStrategy(), loadbalancer.NewHealthChecker(time.Second), loadbalancer.DefaultLoadBalancerConfig())
+			t.Cleanup(lb.Close)
+			for _, backend := range tt.initial {
+				lb.AddBackend(backend)
+			}
+			fallbackManager := fallback.NewFallbackManager(fallback.DefaultConfig(), nil)
+			t.Cleanup(fallbackManager.Close)
+			handler := NewHAHandler(context.Background(), nil, lb, fallbackManager)
+
+			engine := gin.New()
+			engine.GET("/health", handler.GetSystemHealth)
+
+			get := func() systemHealth {
+				w := httptest.NewRecorder()
+				engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health", nil))
+				if w.Code != http.StatusOK {
+					t.Fatalf("status = %d, beklenen 200", w.Code)
+				}
+				var body systemHealth
+				if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+					t.Fatalf("yanıt çözülemedi: %v", err)
+				}
+				return body
+			}
+
+			before := get()
+			if before.SystemStatus != tt.wantInitial || before.LoadBalancer.Status != tt.wantInitial {
+				t.Fatalf("başlangıç durumu = %s/%s, beklenen %s", before.SystemStatus, before.LoadBalancer.Status, tt.wantInitial)
+			}
+			if before.LoadBalancer.Reason != tt.wantReason {
+				t.Errorf("neden = %q, beklenen %q", before.LoadBalancer.Reason, tt.wantReason)
+			}
+
+			lb.AddBackend(tt.add)
+
+			if after := get(); after.SystemStatus != tt.wantAfter || after.LoadBalancer.Status != tt.wantAfter {
+				t.Errorf("AddBackend sonrası durum = %s/%s, beklenen %s", after.SystemStatus, after.LoadBalancer.Status, tt.wantAfter)
+			}
+		})
+	}
+}
+
+func TestUpdateHAConfig(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		body         string
+		wantStatus   int
+		wantTripped  int
+		wantStrategy string
+	}{
+		{name: "eşik düşürülünce breaker daha erken açılır", body: `{"component":"circuit_breaker","name":"redis","config":{"failure_threshold":2}}`, wantStatus: http.StatusOK, wantTripped: 2},
+		{name: "eşik yükseltilince breaker daha geç açılır", body: `{"component":"circuit_breaker","name":"redis","config":{"failure_threshold":5}}`, wantStatus: http.StatusOK, wantTripped: 5},
+		{name: "geçersiz eşik uygulanmaz", body: `{"component":"circuit_breaker","name":"redis","config":{"failure_threshold":0}}`, wantStatus: http.StatusBadRequest, wantTripped: 3},
+		{name: "bilinmeyen alan", body: `{"component":"circuit_breaker","name":"redis","config":{"threshold":1}}`, wantStatus: http.StatusBadRequest, wantTripped: 3},
+		{name: "olmayan breaker", body: `{"component":"circuit_breaker","name":"missing","config":{"failure_threshold":1}}`, wantStatus: http.StatusNotFound, wantTripped: 3},
+		{name: "bilinmeyen bileşen", body: `{"component":"cache","config":{"ttl":"1s"}}`, wantStatus: http.StatusBadRequest, wantTripped: 3},
+		{
+			name:         "load balancer stratejisi ve aralığı",
+			body:         `{"component":"load_balancer","config":{"strategy":"least_connections","health_check_interval":"1s"}}`,
+			wantStatus:   http.StatusOK,
+			wantTripped:  3,
+			wantStrategy: loadbalancer.StrategyLeastConnections,
+		},
+		{name: "bilinmeyen strateji kısmi değişiklik bırakmaz", body: `{"component":"load_balancer","config":{"strategy":"random","health_check_interval":"1s"}}`, wantStatus: http.StatusBadRequest, wantTripped: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			breaker := circuitbreaker.NewCircuitBreaker("redis", circuitbreaker.Config{
+				FailureThreshold:    3,
+				SuccessThreshold:    1,
+				Timeout:             time.Hour,
+				HalfOpenMaxRequests: 1,
+			})
+			defer breaker.Close()
+			lb := loadbalancer.NewLoadBalancer(loadbalancer.NewRoundRobinStrategy(), nil, loadbalancer.DefaultLoadBalancerConfig())
+			defer lb.Close()
+
+			handler := NewHAHandler(context.Background(), nil, lb, nil)
+			handler.RegisterCircuitBreaker("redis", breaker)
+			engine := gin.New()
+			engine.PUT("/config", handler.UpdateHAConfig)
+
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/config", strings.NewReader(tt.body)))
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, beklenen %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+
+			tripped := 0
+			for breaker.GetState() != circuitbreaker.StateOpen && tripped < 10 {
+				_ = breaker.Execute(func() error { return errors.New("redis kullanılamıyor") })
+				tripped++
+			}
+			if tripped != tt.wantTripped {
+				t.Errorf("breaker %d hatada açıldı, beklenen %d", tripped, tt.wantTripped)
+			}
+
+			wantStrategy := tt.wantStrategy
+			wantInterval := loadbalancer.DefaultHealthCheckInterval
+			if wantStrategy == "" {
+				wantStrategy = loadbalancer.StrategyRoundRobin
+			} else {
+				wantInterval = time.Second
+			}
+			if got := lb.StrategyName(); got != wantStrategy {
+				t.Errorf("strateji = %s, beklenen %s", got, wantStrategy)
+			}
+			if got := lb.Config().HealthCheckInterval; got != wantInterval {
+				t.Errorf("health check aralığı = %v, beklenen %v", got, wantInterval)
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"transaction-api-w-go/pkg/domain"
+	"transaction-api-w-go/pkg/i18n"
 	"transaction-api-w-go/pkg/service"
 
 	"github.com/gin-gonic/gin"
@@ -34,7 +35,7 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		UpdatedAt: time.Now(),
 	}
 
-	if err := h.authService.Register(user); err != nil {
+	if err := h.authService.Register(c.Request.Context(), user); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -45,9 +46,9 @@ func (h *AuthHandler) Register(c *gin.Context) {
 func (h *AuthHandler) Login(c *gin.Context) {
 	req := c.MustGet("validated_data").(*domain.LoginRequest)
 
-	token, err := h.authService.Login(req.Email, req.Password)
+	token, err := h.authService.Login(c.Request.Context(), req.Email, req.Password)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
+		i18n.RespondError(c, http.StatusUnauthorized, err)
 		return
 	}
 
@@ -57,9 +58,9 @@ func (h *AuthHandler) Login(c *gin.Context) {
 func (h *AuthHandler) RefreshToken(c *gin.Context) {
 	req := c.MustGet("validated_data").(*domain.RefreshTokenRequest)
 
-	token, err := h.authService.RefreshToken(req.RefreshToken)
+	token, err := h.authService.RefreshToken(c.Request.Context(), req.RefreshToken)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid refresh token"})
+		i18n.RespondError(c, http.StatusUnauthorized, err)
 		return
 	}
 
@@ -1,9 +1,12 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"time"
 
+	"transaction-api-w-go/pkg/domain"
+	"transaction-api-w-go/pkg/i18n"
 	"transaction-api-w-go/pkg/service"
 
 	"github.com/gin-gonic/gin"
@@ -21,7 +24,7 @@ func NewBalanceHandler(balanceService *service.BalanceService) *BalanceHandler {
 
 func (h *BalanceHandler) GetCurrentBalance(c *gin.Context) {
 	userID := c.GetString("user_id")
-	balance, err := h.balanceService.GetCurrentBalance(userID)
+	balance, err := h.balanceService.GetCurrentBalance(c.Request.Context(), userID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -30,9 +33,20 @@ func (h *BalanceHandler) GetCurrentBalance(c *gin.Context) {
 	c.JSON(http.StatusOK, balance)
 }
 
+func (h *BalanceHandler) GetSummary(c *gin.Context) {
+	userID := c.GetString("user_id")
+	summary, err := h.balanceService.GetSummary(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"summary": summary})
+}
+
 func (h *BalanceHandler) GetHistoricalBalance(c *gin.Context) {
 	userID := c.GetString("user_id")
-	history, err := h.balanceService.GetHistoricalBalance(userID)
+	history, err := h.balanceService.GetHistoricalBalance(c.Request.Context(), userID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -47,11 +61,11 @@ func (h *BalanceHandler) GetBalanceAtTime(c *gin.Context) {
 
 	timestamp, err := time.Parse(time.RFC3339, timestampStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "geçersiz tarih formatı"})
+		i18n.Respond(c, http.StatusBadRequest, i18n.CodeInvalidDateFormat)
 		return
 	}
 
-	balance, err := h.balanceService.GetBalanceAtTime(userID, timestamp)
+	balance, err := h.balanceService.GetBalanceAtTime(c.Request.Context(), userID, timestamp)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -59,3 +73,92 @@ func (h *BalanceHandler) GetBalanceAtTime(c *gin.Context) {
 
 	c.JSON(http.StatusOK, balance)
 }
+
+func (h *BalanceHandler) AuthorizeHold(c *gin.Context) {
+	req := c.MustGet("validated_data").(*domain.BalanceHoldRequest)
+
+	userID := c.GetString("user_id")
+	hold, err := h.balanceService.AuthorizeHold(c.Request.Context(), userID, *req)
+	if err != nil {
+		c.JSON(holdErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"hold": hold})
+}
+
+func (h *BalanceHandler) CaptureHold(c *gin.Context) {
+	userID := c.GetString("user_id")
+	transaction, err := h.balanceService.CaptureHold(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		c.JSON(holdErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"transaction": transaction})
+}
+
+func (h *BalanceHandler) ReleaseHold(c *gin.Context) {
+	userID := c.GetString("user_id")
+	hold, err := h.balanceService.ReleaseHold(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		c.JSON(holdErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"hold": hold})
+}
+
+func (h *BalanceHandler) GetActiveHolds(c *gin.Context) {
+	userID := c.GetString("user_id")
+	holds, err := h.balanceService.GetActiveHolds(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"holds": holds})
+}
+
+// SetMinimumBalance (admin) kullanıcının minimum bakiyesini ayarlar; negatif değer overdraft sınırıdır
+func (h *BalanceHandler) SetMinimumBalance(c *gin.Context) {
+	var req struct {
+		MinimumBalance *float64 `json:"minimum_balance" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	balance, err := h.balanceService.SetMinimumBalance(c.Request.Context(), c.Param("id"), *req.MinimumBalance)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidAmount):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, domain.ErrBalanceNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"user_id":         c.Param("id"),
+		"minimum_balance": balance.MinimumBalance,
+		"amount":          balance.Amount,
+	})
+}
+
+func holdErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrHoldNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrHoldNotActive), errors.Is(err, domain.ErrHoldReservedForPayment):
+		return http.StatusConflict
+	case errors.Is(err, domain.ErrInsufficientBalance), errors.Is(err, domain.ErrInvalidAmount):
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"transaction-api-w-go/pkg/domain"
+	"transaction-api-w-go/pkg/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BalanceAlertHandler struct {
+	alertService *service.BalanceAlertService
+}
+
+func NewBalanceAlertHandler(alertService *service.BalanceAlertService) *BalanceAlertHandler {
+	return &BalanceAlertHandler{
+		alertService: alertService,
+	}
+}
+
+func (h *BalanceAlertHandler) CreateRule(c *gin.Context) {
+	var req domain.BalanceAlertRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rule, err := h.alertService.CreateRule(c.Request.Context(), c.GetString("user_id"), req)
+	if err != nil {
+		c.JSON(alertErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"rule": rule})
+}
+
+func (h *BalanceAlertHandler) ListRules(c *gin.Context) {
+	rules, err := h.alertService.ListRules(c.Request.Context(), c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"rules": rules})
+}
+
+func (h *BalanceAlertHandler) GetRule(c *gin.Context) {
+	rule, err := h.alertService.GetRule(c.Request.Context(), c.GetString("user_id"), c.Param("id"))
+	if err != nil {
+		c.JSON(alertErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"rule": rule})
+}
+
+func (h *BalanceAlertHandler) UpdateRule(c *gin.Context) {
+	var req domain.BalanceAlertRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rule, err := h.alertService.UpdateRule(c.Request.Context(), c.GetString("user_id"), c.Param("id"), req)
+	if err != nil {
+		c.JSON(alertErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"rule": rule})
+}
+
+func (h *BalanceAlertHandler) DeleteRule(c *gin.Context) {
+	if err := h.alertService.DeleteRule(c.Request.Context(), c.GetString("user_id"), c.Param("id")); err != nil {
+		c.JSON(alertErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Alert rule deleted"})
+}
+
+func alertErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrAlertRuleNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrInvalidAlertRule):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
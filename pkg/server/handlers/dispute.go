@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"transaction-api-w-go/pkg/domain"
+	"transaction-api-w-go/pkg/i18n"
+	"transaction-api-w-go/pkg/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type DisputeHandler struct {
+	disputeService *service.DisputeService
+}
+
+func NewDisputeHandler(disputeService *service.DisputeService) *DisputeHandler {
+	return &DisputeHandler{
+		disputeService: disputeService,
+	}
+}
+
+func (h *DisputeHandler) OpenDispute(c *gin.Context) {
+	transactionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		i18n.Respond(c, http.StatusBadRequest, i18n.CodeInvalidTransactionID)
+		return
+	}
+
+	var req domain.DisputeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	dispute, err := h.disputeService.OpenDispute(c.Request.Context(), c.GetString("user_id"), transactionID, req)
+	if err != nil {
+		c.JSON(disputeErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dispute)
+}
+
+func (h *DisputeHandler) ResolveDispute(c *gin.Context) {
+	adminID, err := uuid.Parse(c.GetString("user_id"))
+	if err != nil {
+		i18n.Respond(c, http.StatusBadRequest, i18n.CodeInvalidUserID)
+		return
+	}
+
+	var req domain.ResolveDisputeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	dispute, err := h.disputeService.ResolveDispute(c.Request.Context(), adminID, c.Param("id"), req)
+	if err != nil {
+		c.JSON(disputeErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dispute)
+}
+
+func (h *DisputeHandler) GetOpenDisputes(c *gin.Context) {
+	disputes, err := h.disputeService.GetOpenDisputes(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"disputes": disputes})
+}
+
+func disputeErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrDisputeAlreadyOpen), errors.Is(err, domain.ErrDisputeNotOpen):
+		return http.StatusConflict
+	case errors.Is(err, domain.ErrInvalidDisputeStatus), errors.Is(err, domain.ErrInvalidDisputeReason):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -1,13 +1,18 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
+	"time"
 
 	"transaction-api-w-go/pkg/domain"
+	"transaction-api-w-go/pkg/i18n"
+	"transaction-api-w-go/pkg/middleware"
 	"transaction-api-w-go/pkg/service"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 type TransactionHandler struct {
@@ -24,62 +29,203 @@ func (h *TransactionHandler) Credit(c *gin.Context) {
 	req := c.MustGet("validated_data").(*domain.TransactionRequest)
 
 	userID := c.GetString("user_id")
-	transaction, err := h.transactionService.Credit(c.Request.Context(), userID, req.Amount, req.Description)
+	transaction, err := h.transactionService.Credit(c.Request.Context(), userID, req)
+	if errors.Is(err, domain.ErrTransactionAlreadyExists) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "transaction": transaction})
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(transactionErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, transaction)
+	c.JSON(transactionResultStatus(transaction), transaction)
 }
 
 func (h *TransactionHandler) Debit(c *gin.Context) {
 	req := c.MustGet("validated_data").(*domain.TransactionRequest)
 
 	userID := c.GetString("user_id")
-	transaction, err := h.transactionService.Debit(c.Request.Context(), userID, req.Amount, req.Description)
+	transaction, err := h.transactionService.Debit(c.Request.Context(), userID, req)
+	if errors.Is(err, domain.ErrTransactionAlreadyExists) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "transaction": transaction})
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(transactionErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, transaction)
+	c.JSON(transactionResultStatus(transaction), transaction)
 }
 
 func (h *TransactionHandler) Transfer(c *gin.Context) {
 	req := c.MustGet("validated_data").(*domain.TransferRequest)
 
 	fromUserID := c.GetString("user_id")
-	transaction, err := h.transactionService.Transfer(c.Request.Context(), fromUserID, req.ToUserID.String(), req.Amount, req.Description)
+	transaction, err := h.transactionService.Transfer(c.Request.Context(), fromUserID, req)
+	if errors.Is(err, domain.ErrTransactionAlreadyExists) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "transaction": transaction})
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(transactionErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, transaction)
+	c.JSON(transactionResultStatus(transaction), transaction)
+}
+
+// Preview işlemin geçip geçmeyeceğini nedenleriyle döndürür; reddedilecek işlemler de 200 döner
+func (h *TransactionHandler) Preview(c *gin.Context) {
+	req := c.MustGet("validated_data").(*domain.TransactionPreviewRequest)
+
+	preview, err := h.transactionService.Preview(c.Request.Context(), c.GetString("user_id"), req)
+	if err != nil {
+		c.JSON(transactionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, preview)
 }
 
 func (h *TransactionHandler) GetHistory(c *gin.Context) {
-	userIDStr := c.GetString("user_id")
-	userID, err := strconv.ParseUint(userIDStr, 10, 64)
+	userID, err := uuid.Parse(c.GetString("user_id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Geçersiz user ID"})
+		i18n.Respond(c, http.StatusBadRequest, i18n.CodeInvalidUserID)
 		return
 	}
-	transactions, err := h.transactionService.GetHistory(c.Request.Context(), uint(userID))
+
+	filter := domain.TransactionFilter{
+		Category: c.Query("category"),
+		Tag:      c.Query("tag"),
+	}
+
+	limit, offset, err := domain.ParsePageParams(c.Query("limit"), c.Query("offset"))
+	if err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	transactions, err := h.transactionService.GetHistory(c.Request.Context(), userID.String(), filter)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, transactions)
+	c.JSON(http.StatusOK, domain.Paginate(transactions, limit, offset))
+}
+
+func (h *TransactionHandler) Search(c *gin.Context) {
+	userID, err := uuid.Parse(c.GetString("user_id"))
+	if err != nil {
+		i18n.Respond(c, http.StatusBadRequest, i18n.CodeInvalidUserID)
+		return
+	}
+
+	filter, err := parseSearchFilter(c)
+	if err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	// Destek ekibi (admin) başka kullanıcıların işlemlerini de arayabilir
+	searchUserID := userID.String()
+	if middleware.HasRole(c, domain.RoleAdmin) {
+		searchUserID = c.Query("user_id")
+		if searchUserID != "" {
+			if _, err := uuid.Parse(searchUserID); err != nil {
+				i18n.Respond(c, http.StatusBadRequest, i18n.CodeInvalidUserID)
+				return
+			}
+		}
+	}
+
+	result, err := h.transactionService.Search(c.Request.Context(), searchUserID, filter)
+	if err != nil {
+		c.JSON(transactionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	page := domain.NewPage(result.Transactions, result.Limit, 0, result.Total)
+	page.Page.NextCursor = result.NextCursor
+	c.JSON(http.StatusOK, page)
+}
+
+func parseSearchFilter(c *gin.Context) (domain.TransactionFilter, error) {
+	filter := domain.TransactionFilter{
+		Category:    c.Query("category"),
+		Tag:         c.Query("tag"),
+		ReferenceID: c.Query("reference_id"),
+		SortOrder:   domain.SortOrder(c.DefaultQuery("sort", string(domain.SortOrderDesc))),
+		Cursor:      c.Query("cursor"),
+	}
+
+	if counterparty := c.Query("counterparty_id"); counterparty != "" {
+		if _, err := uuid.Parse(counterparty); err != nil {
+			return filter, i18n.NewError(i18n.CodeInvalidQueryParam, "counterparty_id")
+		}
+		filter.CounterpartyID = counterparty
+	}
+
+	for param, target := range map[string]**float64{"min_amount": &filter.MinAmount, "max_amount": &filter.MaxAmount} {
+		if value := c.Query(param); value != "" {
+			amount, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return filter, i18n.NewError(i18n.CodeInvalidQueryParam, param)
+			}
+			*target = &amount
+		}
+	}
+
+	for param, target := range map[string]**time.Time{"start_date": &filter.StartDate, "end_date": &filter.EndDate} {
+		if value := c.Query(param); value != "" {
+			date, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return filter, i18n.NewError(i18n.CodeInvalidQueryParam, param)
+			}
+			*target = &date
+		}
+	}
+
+	if limit := c.Query("limit"); limit != "" {
+		parsed, err := strconv.Atoi(limit)
+		if err != nil || parsed <= 0 {
+			return filter, i18n.NewError(i18n.CodeInvalidQueryParam, "limit")
+		}
+		filter.Limit = parsed
+	}
+
+	return filter, nil
+}
+
+func (h *TransactionHandler) GetByReferenceID(c *gin.Context) {
+	userID, err := uuid.Parse(c.GetString("user_id"))
+	if err != nil {
+		i18n.Respond(c, http.StatusBadRequest, i18n.CodeInvalidUserID)
+		return
+	}
+
+	referenceID := c.Param("reference_id")
+	if referenceID == "" {
+		i18n.Respond(c, http.StatusBadRequest, i18n.CodeReferenceIDRequired)
+		return
+	}
+
+	transactions, err := h.transactionService.GetByReferenceID(c.Request.Context(), userID.String(), referenceID)
+	if err != nil {
+		c.JSON(transactionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"transactions": transactions})
 }
 
 func (h *TransactionHandler) GetByID(c *gin.Context) {
 	transactionIDStr := c.Param("id")
 	transactionID, err := strconv.ParseUint(transactionIDStr, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Geçersiz transaction ID"})
+		i18n.Respond(c, http.StatusBadRequest, i18n.CodeInvalidTransactionID)
 		return
 	}
 	transaction, err := h.transactionService.GetByID(c.Request.Context(), uint(transactionID))
@@ -90,3 +236,209 @@ func (h *TransactionHandler) GetByID(c *gin.Context) {
 
 	c.JSON(http.StatusOK, transaction)
 }
+
+// GetReceipt işlemin hash zincirli makbuzunu döndürür
+func (h *TransactionHandler) GetReceipt(c *gin.Context) {
+	transactionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		i18n.Respond(c, http.StatusBadRequest, i18n.CodeInvalidTransactionID)
+		return
+	}
+
+	receipt, err := h.transactionService.GetReceipt(c.Request.Context(), c.GetString("user_id"), transactionID)
+	if err != nil {
+		c.JSON(transactionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"receipt": receipt,
+	})
+}
+
+// GetEvents kullanıcının tarafı olduğu işlemin event zaman çizelgesini döndürür. Admin
+// metadata'sı (ör. inceleyen kişi) kullanıcıya gösterilmez.
+func (h *TransactionHandler) GetEvents(c *gin.Context) {
+	transactionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		i18n.Respond(c, http.StatusBadRequest, i18n.CodeInvalidTransactionID)
+		return
+	}
+
+	events, err := h.transactionService.GetTransactionEvents(c.Request.Context(), c.GetString("user_id"), transactionID)
+	if err != nil {
+		c.JSON(transactionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	timeline := make([]gin.H, len(events))
+	for i, event := range events {
+		timeline[i] = gin.H{
+			"id":        event.GetID(),
+			"type":      event.GetType(),
+			"version":   event.GetVersion(),
+			"timestamp": event.GetTimestamp(),
+			"data":      event.GetData(),
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"transaction_id": transactionID,
+		"events":         timeline,
+	})
+}
+
+// RepublishEvents işlemin kayıtlı event'lerini bakiyeleri değiştirmeden abonelere yeniden iletir
+func (h *TransactionHandler) RepublishEvents(c *gin.Context) {
+	transactionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		i18n.Respond(c, http.StatusBadRequest, i18n.CodeInvalidTransactionID)
+		return
+	}
+
+	republished, err := h.transactionService.RepublishTransactionEvents(c.Request.Context(), transactionID)
+	if err != nil {
+		c.JSON(transactionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"transaction_id":     transactionID,
+		"events_republished": republished,
+	})
+}
+
+// VerifyReceipts kullanıcının tüm makbuz zincirini yeniden hesaplayarak doğrular
+func (h *TransactionHandler) VerifyReceipts(c *gin.Context) {
+	verification, err := h.transactionService.VerifyReceipts(c.Request.Context(), c.GetString("user_id"))
+	if err != nil {
+		c.JSON(transactionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"verification": verification,
+	})
+}
+
+// ListHeld inceleme kuyruğundaki işlemleri en eskiden başlayarak döndürür
+func (h *TransactionHandler) ListHeld(c *gin.Context) {
+	limit := 0
+	if value := c.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			i18n.Respond(c, http.StatusBadRequest, i18n.CodeInvalidQueryParam, "limit")
+			return
+		}
+		limit = parsed
+	}
+
+	transactions, err := h.transactionService.ListHeldTransactions(c.Request.Context(), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"transactions": transactions})
+}
+
+// ApproveHeld incelemedeki işlemi tamamlar ve bakiyelere uygular
+func (h *TransactionHandler) ApproveHeld(c *gin.Context) {
+	reviewerID, transactionID, ok := reviewIDs(c)
+	if !ok {
+		return
+	}
+
+	transaction, err := h.transactionService.ApproveHeldTransaction(c.Request.Context(), reviewerID, transactionID)
+	if err != nil {
+		c.JSON(reviewErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, transaction)
+}
+
+// RejectHeld incelemedeki işlemi nedeniyle birlikte reddeder
+func (h *TransactionHandler) RejectHeld(c *gin.Context) {
+	reviewerID, transactionID, ok := reviewIDs(c)
+	if !ok {
+		return
+	}
+
+	var req domain.RejectHeldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	transaction, err := h.transactionService.RejectHeldTransaction(c.Request.Context(), reviewerID, transactionID, req.Reason)
+	if err != nil {
+		c.JSON(reviewErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, transaction)
+}
+
+// reviewIDs incelemeyi yapan admin'in ve işlemin id'lerini çözer; hata durumunda yanıtı yazar
+func reviewIDs(c *gin.Context) (reviewerID, transactionID uuid.UUID, ok bool) {
+	reviewerID, err := uuid.Parse(c.GetString("user_id"))
+	if err != nil {
+		i18n.Respond(c, http.StatusBadRequest, i18n.CodeInvalidUserID)
+		return uuid.Nil, uuid.Nil, false
+	}
+	transactionID, err = uuid.Parse(c.Param("id"))
+	if err != nil {
+		i18n.Respond(c, http.StatusBadRequest, i18n.CodeInvalidTransactionID)
+		return uuid.Nil, uuid.Nil, false
+	}
+	return reviewerID, transactionID, true
+}
+
+func reviewErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrTransactionNotHeld):
+		return http.StatusConflict
+	case errors.Is(err, domain.ErrInsufficientBalance), errors.Is(err, domain.ErrExternalTransferRejected):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, domain.ErrPaymentGatewayUnavailable):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// transactionResultStatus inceleme kuyruğuna alınan (held) ve ödeme ağ geçidinde sonuç bekleyen
+// (pending_settlement) işlemler için 202 döner
+func transactionResultStatus(transaction *domain.Transaction) int {
+	if transaction.IsHeld() || transaction.Status == string(domain.TransactionStatePendingSettlement) {
+		return http.StatusAccepted
+	}
+	return http.StatusOK
+}
+
+func transactionErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrMetadataTooLarge), errors.Is(err, domain.ErrInvalidMetadata),
+		errors.Is(err, domain.ErrInvalidCursor), errors.Is(err, domain.ErrInvalidSearchFilter),
+		errors.Is(err, domain.ErrInvalidTransferDestination), errors.Is(err, domain.ErrInvalidExternalAccount):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrMinimumBalanceBreached), errors.Is(err, domain.ErrTransactionDenied),
+		errors.Is(err, domain.ErrExternalTransferRejected):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, domain.ErrTransactionLimitExceeded), errors.Is(err, domain.ErrDailyLimitExceeded),
+		errors.Is(err, domain.ErrDailyCountExceeded), errors.Is(err, domain.ErrWeeklyLimitExceeded),
+		errors.Is(err, domain.ErrWeeklyCountExceeded), errors.Is(err, domain.ErrMonthlyLimitExceeded),
+		errors.Is(err, domain.ErrMonthlyCountExceeded):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, domain.ErrScreeningUnavailable), errors.Is(err, domain.ErrPaymentGatewayUnavailable),
+		errors.Is(err, domain.ErrNoEventSubscribers):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
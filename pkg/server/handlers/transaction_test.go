@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"transaction-api-w-go/pkg/domain"
+)
+
+func TestErrorStatusMapsNotFoundTo404(t *testing.T) {
+	classifiers := []struct {
+		name     string
+		classify func(error) int
+	}{
+		{name: "işlem", classify: transactionErrorStatus},
+		{name: "inceleme", classify: reviewErrorStatus},
+		{name: "itiraz", classify: disputeErrorStatus},
+	}
+
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{name: "işlem bulunamadı", err: domain.ErrTransactionNotFound},
+		{name: "bakiye bulunamadı", err: domain.ErrBalanceNotFound},
+		{name: "kullanıcı bulunamadı", err: domain.ErrUserNotFound},
+		{name: "itiraz bulunamadı", err: domain.ErrDisputeNotFound},
+		{name: "dekont bulunamadı", err: domain.ErrReceiptNotFound},
+		// Repository'ler varlık hatasını bağlam ekleyerek sarabilir
+		{name: "sarılmış bakiye hatası", err: fmt.Errorf("%w for user x and currency USD", domain.ErrBalanceNotFound)},
+	}
+
+	for _, classifier := range classifiers {
+		for _, tt := range tests {
+			t.Run(classifier.name+"/"+tt.name, func(t *testing.T) {
+				if got := classifier.classify(tt.err); got != http.StatusNotFound {
+					t.Errorf("status = %d, beklenen 404", got)
+				}
+			})
+		}
+	}
+
+	// Bulunamadı dışındaki hatalar 404'e düşmez
+	if got := transactionErrorStatus(domain.ErrFeeAccountNotFound); got == http.StatusNotFound {
+		t.Errorf("ErrFeeAccountNotFound yapılandırma hatasıdır, 404 dönmemeli")
+	}
+}
+
+func TestEntityErrorStatusMapsNotFoundTo404(t *testing.T) {
+	tests := []struct {
+		name     string
+		classify func(error) int
+		err      error
+	}{
+		{name: "blokaj", classify: holdErrorStatus, err: domain.ErrHoldNotFound},
+		{name: "bakiye alarmı", classify: alertErrorStatus, err: domain.ErrAlertRuleNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.classify(tt.err); got != http.StatusNotFound {
+				t.Errorf("status = %d, beklenen 404", got)
+			}
+		})
+	}
+}
@@ -1,9 +1,11 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
 	"transaction-api-w-go/pkg/domain"
+	"transaction-api-w-go/pkg/i18n"
 	"transaction-api-w-go/pkg/service"
 
 	"github.com/gin-gonic/gin"
@@ -21,7 +23,7 @@ func NewUserHandler(userService *service.UserService) *UserHandler {
 }
 
 func (h *UserHandler) GetUsers(c *gin.Context) {
-	users, err := h.userService.List()
+	users, err := h.userService.List(c.Request.Context())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -32,7 +34,7 @@ func (h *UserHandler) GetUsers(c *gin.Context) {
 
 func (h *UserHandler) GetUser(c *gin.Context) {
 	userID := c.Param("id")
-	user, err := h.userService.GetByID(userID)
+	user, err := h.userService.GetByID(c.Request.Context(), userID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -50,20 +52,44 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 	}
 
 	req.ID = uuid.MustParse(userID)
-	if err := h.userService.Update(&req); err != nil {
+	if err := h.userService.Update(c.Request.Context(), &req); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Kullanıcı başarıyla güncellendi"})
+	c.JSON(http.StatusOK, gin.H{"message": i18n.Message(c, i18n.CodeUserUpdated)})
+}
+
+func (h *UserHandler) SetLimitTier(c *gin.Context) {
+	var req struct {
+		Tier domain.LimitTier `json:"tier" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.userService.SetLimitTier(c.Request.Context(), c.Param("id"), req.Tier); err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidLimitTier):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, domain.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"user_id": c.Param("id"), "limit_tier": req.Tier})
 }
 
 func (h *UserHandler) DeleteUser(c *gin.Context) {
 	userID := c.Param("id")
-	if err := h.userService.Delete(userID); err != nil {
+	if err := h.userService.Delete(c.Request.Context(), userID); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Kullanıcı başarıyla silindi"})
+	c.JSON(http.StatusOK, gin.H{"message": i18n.Message(c, i18n.CodeUserDeleted)})
 }
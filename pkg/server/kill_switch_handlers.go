@@ -0,0 +1,58 @@
+package server
+
+import (
+	"net/http"
+
+	"transaction-api-w-go/pkg/killswitch"
+
+	"github.com/gin-gonic/gin"
+)
+
+type KillSwitchHandler struct {
+	killSwitch *killswitch.Switch
+}
+
+func NewKillSwitchHandler(killSwitch *killswitch.Switch) *KillSwitchHandler {
+	return &KillSwitchHandler{
+		killSwitch: killSwitch,
+	}
+}
+
+// UpdateKillSwitchRequest engaged true ise zamanlayıcı, batch ve worker işlemleri tüm instance'larda duraklatılır
+type UpdateKillSwitchRequest struct {
+	Engaged *bool  `json:"engaged" binding:"required"`
+	Reason  string `json:"reason"`
+}
+
+func (h *KillSwitchHandler) GetKillSwitch(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"kill_switch": h.killSwitch.Status(c.Request.Context()),
+	})
+}
+
+func (h *KillSwitchHandler) UpdateKillSwitch(c *gin.Context) {
+	var req UpdateKillSwitchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var (
+		state killswitch.State
+		err   error
+	)
+	actor := c.GetString("user_id")
+	if *req.Engaged {
+		state, err = h.killSwitch.Engage(c.Request.Context(), req.Reason, actor)
+	} else {
+		state, err = h.killSwitch.Release(c.Request.Context(), req.Reason, actor)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"kill_switch": state,
+	})
+}
@@ -4,46 +4,30 @@ import (
 	"net/http"
 	"strings"
 
+	"transaction-api-w-go/pkg/i18n"
+
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
 )
 
 func AuthMiddleware(jwtSecret string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "yetkilendirme başlığı eksik"})
+			i18n.Respond(c, http.StatusUnauthorized, i18n.CodeMissingAuthorization)
 			c.Abort()
 			return
 		}
 
 		parts := strings.Split(authHeader, " ")
 		if len(parts) != 2 || parts[0] != "Bearer" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "geçersiz yetkilendirme formatı"})
-			c.Abort()
-			return
-		}
-
-		token, err := jwt.Parse(parts[1], func(token *jwt.Token) (interface{}, error) {
-			return []byte(jwtSecret), nil
-		})
-
-		if err != nil || !token.Valid {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "geçersiz token"})
-			c.Abort()
-			return
-		}
-
-		claims, ok := token.Claims.(jwt.MapClaims)
-		if !ok {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "geçersiz token claims"})
+			i18n.Respond(c, http.StatusUnauthorized, i18n.CodeInvalidAuthorizationFormat)
 			c.Abort()
 			return
 		}
 
-		userID, ok := claims["user_id"].(string)
-		if !ok {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "geçersiz user_id claim"})
+		userID, err := ParseUserID(jwtSecret, parts[1])
+		if err != nil {
+			i18n.RespondError(c, http.StatusUnauthorized, err)
 			c.Abort()
 			return
 		}
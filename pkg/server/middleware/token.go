@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"transaction-api-w-go/pkg/domain"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ParseUserID access token'ı doğrular ve user_id claim'ini döndürür.
+// HTTP middleware'i ve gRPC interceptor'ı aynı doğrulamayı kullanır.
+func ParseUserID(jwtSecret, tokenString string) (string, error) {
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		return []byte(jwtSecret), nil
+	})
+	if err != nil || !token.Valid {
+		return "", domain.ErrInvalidToken
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return "", domain.ErrInvalidTokenClaims
+	}
+
+	userID, ok := claims["user_id"].(string)
+	if !ok {
+		return "", domain.ErrInvalidTokenClaims
+	}
+	return userID, nil
+}
@@ -0,0 +1,104 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// specDocument testlerin okuduğu OpenAPI alanlarıdır
+type specDocument struct {
+	OpenAPI    string                                `json:"openapi"`
+	Paths      map[string]map[string]json.RawMessage `json:"paths"`
+	Components map[string]map[string]json.RawMessage `json:"components"`
+}
+
+func serveSpec(t *testing.T) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.GET("/openapi.json", ServeSpec)
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, beklenen 200", w.Code)
+	}
+	return w
+}
+
+func TestServeSpecContainsEndpoints(t *testing.T) {
+	w := serveSpec(t)
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+		t.Errorf("Content-Type = %s, beklenen application/json", ct)
+	}
+
+	var doc specDocument
+	if err := json.Unmarshal(w.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("spec geçerli JSON değil: %v", err)
+	}
+	if !strings.HasPrefix(doc.OpenAPI, "3.") {
+		t.Errorf("openapi = %q, beklenen 3.x", doc.OpenAPI)
+	}
+
+	tests := []struct {
+		name   string
+		path   string
+		method string
+	}{
+		{name: "credit", path: "/api/v1/transactions/credit", method: "post"},
+		{name: "giriş", path: "/api/v1/auth/login", method: "post"},
+		{name: "güncel bakiye", path: "/api/v1/balances/current", method: "get"},
+		{name: "zamanlanmış işlemler", path: "/api/v1/advanced/scheduled", method: "post"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			operations, ok := doc.Paths[tt.path]
+			if !ok {
+				t.Fatalf("%s spec'te yok", tt.path)
+			}
+			if _, ok := operations[tt.method]; !ok {
+				t.Errorf("%s için %s tanımı yok", tt.path, tt.method)
+			}
+		})
+	}
+}
+
+func TestSpecReferencesResolve(t *testing.T) {
+	var doc specDocument
+	if err := json.Unmarshal(Spec(), &doc); err != nil {
+		t.Fatalf("spec geçerli JSON değil: %v", err)
+	}
+
+	ref := regexp.MustCompile(`"\$ref":\s*"#/components/([^/]+)/([^"]+)"`)
+	for _, match := range ref.FindAllStringSubmatch(string(Spec()), -1) {
+		if _, ok := doc.Components[match[1]][match[2]]; !ok {
+			t.Errorf("#/components/%s/%s tanımlı değil", match[1], match[2])
+		}
+	}
+}
+
+func TestServeDocs(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.GET("/docs", ServeDocs)
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, beklenen 200", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), `url: "/openapi.json"`) {
+		t.Error("Swagger UI /openapi.json'u yüklemiyor")
+	}
+	if csp := w.Header().Get("Content-Security-Policy"); !strings.Contains(csp, "https://unpkg.com") {
+		t.Errorf("CSP = %q, Swagger UI CDN'ine izin vermiyor", csp)
+	}
+}
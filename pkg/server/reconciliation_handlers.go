@@ -0,0 +1,49 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+
+	"transaction-api-w-go/pkg/domain"
+	"transaction-api-w-go/pkg/worker"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReconciliationHandler struct {
+	job *worker.ReconciliationJob
+}
+
+func NewReconciliationHandler(job *worker.ReconciliationJob) *ReconciliationHandler {
+	return &ReconciliationHandler{
+		job: job,
+	}
+}
+
+func (h *ReconciliationHandler) GetLatestReport(c *gin.Context) {
+	report, err := h.job.LatestReport(c.Request.Context())
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, domain.ErrReportNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"report": report,
+	})
+}
+
+func (h *ReconciliationHandler) RunReconciliation(c *gin.Context) {
+	report, err := h.job.RunOnce(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"report": report,
+	})
+}
@@ -2,13 +2,19 @@ package server
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"sync/atomic"
 	"time"
 
+	"transaction-api-w-go/pkg/bulkhead"
 	"transaction-api-w-go/pkg/domain"
+	"transaction-api-w-go/pkg/killswitch"
+	"transaction-api-w-go/pkg/metrics"
 	"transaction-api-w-go/pkg/middleware"
 	"transaction-api-w-go/pkg/server/handlers"
+	"transaction-api-w-go/pkg/server/openapi"
 
 	"github.com/gin-gonic/gin"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -16,18 +22,34 @@ import (
 	"golang.org/x/time/rate"
 )
 
+// DefaultDrainTimeout kapanışta süren isteklerin tamamlanması için varsayılan bekleme süresi
+const DefaultDrainTimeout = 25 * time.Second
+
 type Server struct {
 	engine             *gin.Engine
 	server             *http.Server
+	inFlight           atomic.Int64
+	drainTimeout       time.Duration
 	limiter            *rate.Limiter
 	authHandler        *handlers.AuthHandler
 	userHandler        *handlers.UserHandler
 	transactionHandler *handlers.TransactionHandler
 	balanceHandler     *handlers.BalanceHandler
+	alertHandler       *handlers.BalanceAlertHandler
+	disputeHandler     *handlers.DisputeHandler
 	eventHandler       *EventHandler
 	cacheHandler       *CacheHandler
 	advancedHandler    *AdvancedTransactionHandler
 	haHandler          *HAHandler
+	workerHandler      *WorkerHandler
+	reconcileHandler   *ReconciliationHandler
+	flagHandler        *FeatureFlagHandler
+	auditHandler       *AuditHandler
+	webhookHandler     *WebhookHandler
+	killSwitchHandler  *KillSwitchHandler
+	audit              middleware.AuditRecorder
+	signing            middleware.SignatureConfig
+	bulkheads          *bulkhead.Registry
 	jwtSecret          string
 }
 
@@ -45,8 +67,9 @@ func NewServer(port int) *Server {
 			WriteTimeout: 15 * time.Second,
 			IdleTimeout:  60 * time.Second,
 		},
-		limiter:   limiter,
-		jwtSecret: "your-secret-key",
+		limiter:      limiter,
+		jwtSecret:    "your-secret-key",
+		drainTimeout: DefaultDrainTimeout,
 	}
 
 	server.setupMiddleware()
@@ -55,6 +78,17 @@ func NewServer(port int) *Server {
 }
 
 func (s *Server) setupMiddleware() {
+	// Kapanışta kaç isteğin beklendiği bilinsin diye sayaç tüm middleware'lerden önce çalışır
+	s.engine.Use(func(c *gin.Context) {
+		s.inFlight.Add(1)
+		metrics.HttpRequestsInFlight.Inc()
+		defer func() {
+			s.inFlight.Add(-1)
+			metrics.HttpRequestsInFlight.Dec()
+		}()
+		c.Next()
+	})
+	s.engine.Use(middleware.RequestIDMiddleware())
 	s.engine.Use(middleware.ErrorHandlerMiddleware())
 	s.engine.Use(middleware.PerformanceMiddleware())
 	s.engine.Use(middleware.MetricsMiddleware())
@@ -62,7 +96,7 @@ func (s *Server) setupMiddleware() {
 	s.engine.Use(func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Signature, X-Signature-Timestamp, X-Signature-Nonce, X-Request-ID")
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
@@ -106,8 +140,48 @@ func (s *Server) setupMiddleware() {
 	})
 }
 
+// SetRequestSigning imzalı istek (replay korumalı) gerektiren route'ları yapılandırır;
+// route'lar SetHandlers içinde kurulduğu için ondan önce çağrılmalıdır
+func (s *Server) SetRequestSigning(config middleware.SignatureConfig) {
+	s.signing = config
+}
+
+// SetAuditLog admin işlemlerinin kaydedileceği depoyu bağlar ve audit sorgu endpoint'ini açar;
+// route'lar SetHandlers içinde kurulduğu için ondan önce çağrılmalıdır
+func (s *Server) SetAuditLog(store AuditLogStore) {
+	s.audit = store
+	s.auditHandler = NewAuditHandler(store)
+}
+
+// SetBulkheads pahalı işlem route'larını (batch, replay, export) sınıf başına eşzamanlılık
+// sınırıyla çalıştırır; route'lar SetHandlers içinde kurulduğu için ondan önce çağrılmalıdır
+func (s *Server) SetBulkheads(registry *bulkhead.Registry) {
+	s.bulkheads = registry
+}
+
+// SetWebhookDeliveries webhook teslimatlarını görüntüleme ve yeniden gönderme endpoint'lerini açar;
+// route'lar SetHandlers içinde kurulduğu için ondan önce çağrılmalıdır
+func (s *Server) SetWebhookDeliveries(manager WebhookDeliveryManager) {
+	s.webhookHandler = NewWebhookHandler(manager)
+}
+
+// SetKillSwitch otomatik işlemleri duraklatan anahtarın admin endpoint'lerini açar;
+// route'lar SetHandlers içinde kurulduğu için ondan önce çağrılmalıdır
+func (s *Server) SetKillSwitch(killSwitch *killswitch.Switch) {
+	s.killSwitchHandler = NewKillSwitchHandler(killSwitch)
+}
+
 func (s *Server) setupRoutes() {
+	// audit admin gruplarındaki değişiklik yapan istekleri kaydeder
+	audit := middleware.AuditMiddleware(s.audit)
+	// Sınır tanımlanmamış sınıflar için middleware isteği doğrudan geçirir
+	batchBulkhead := middleware.BulkheadMiddleware(s.bulkheads.Get(bulkhead.OperationBatch))
+	replayBulkhead := middleware.BulkheadMiddleware(s.bulkheads.Get(bulkhead.OperationReplay))
+	exportBulkhead := middleware.BulkheadMiddleware(s.bulkheads.Get(bulkhead.OperationExport))
+
 	s.engine.GET("/metrics", gin.WrapH(promhttp.Handler()))
+	s.engine.GET("/openapi.json", openapi.ServeSpec)
+	s.engine.GET("/docs", openapi.ServeDocs)
 
 	auth := s.engine.Group("/api/v1/auth")
 	{
@@ -118,14 +192,19 @@ func (s *Server) setupRoutes() {
 
 	api := s.engine.Group("/api/v1")
 	api.Use(middleware.AuthMiddleware(s.jwtSecret))
+	if s.signing.Enabled() {
+		api.Use(middleware.RequestSignatureMiddleware(s.signing))
+	}
 	{
 		users := api.Group("/users")
-		users.Use(middleware.RoleMiddleware("admin"))
+		users.Use(middleware.RoleMiddleware("admin"), audit)
 		{
 			users.GET("", s.userHandler.GetUsers)
 			users.GET("/:id", s.userHandler.GetUser)
 			users.PUT("/:id", middleware.ValidationMiddleware(&domain.User{}), s.userHandler.UpdateUser)
 			users.DELETE("/:id", s.userHandler.DeleteUser)
+			users.PUT("/:id/limit-tier", s.userHandler.SetLimitTier)
+			users.PUT("/:id/minimum-balance", s.balanceHandler.SetMinimumBalance)
 		}
 
 		transactions := api.Group("/transactions")
@@ -133,100 +212,166 @@ func (s *Server) setupRoutes() {
 			transactions.POST("/credit", middleware.ValidationMiddleware(&domain.TransactionRequest{}), s.transactionHandler.Credit)
 			transactions.POST("/debit", middleware.ValidationMiddleware(&domain.TransactionRequest{}), s.transactionHandler.Debit)
 			transactions.POST("/transfer", middleware.ValidationMiddleware(&domain.TransferRequest{}), s.transactionHandler.Transfer)
+			transactions.POST("/preview", middleware.ValidationMiddleware(&domain.TransactionPreviewRequest{}), s.transactionHandler.Preview)
 			transactions.GET("/history", s.transactionHandler.GetHistory)
+			transactions.GET("/search", s.transactionHandler.Search)
+			transactions.GET("/by-reference/:reference_id", s.transactionHandler.GetByReferenceID)
+			transactions.GET("/receipts/verify", s.transactionHandler.VerifyReceipts)
 			transactions.GET("/:id", s.transactionHandler.GetByID)
+			transactions.POST("/:id/dispute", s.disputeHandler.OpenDispute)
+			transactions.GET("/:id/receipt", s.transactionHandler.GetReceipt)
+			transactions.GET("/:id/events", s.transactionHandler.GetEvents)
+		}
+
+		reviews := api.Group("/reviews")
+		reviews.Use(middleware.RoleMiddleware("admin"), audit) // İnceleme kuyruğunu yalnızca admin'ler sonuçlandırabilir
+		{
+			reviews.GET("", s.transactionHandler.ListHeld)
+			reviews.POST("/:id/approve", s.transactionHandler.ApproveHeld)
+			reviews.POST("/:id/reject", s.transactionHandler.RejectHeld)
+		}
+
+		disputes := api.Group("/disputes")
+		disputes.Use(middleware.RoleMiddleware("admin"), audit) // İtirazları yalnızca admin'ler çözebilir
+		{
+			disputes.GET("", s.disputeHandler.GetOpenDisputes)
+			disputes.POST("/:id/resolve", s.disputeHandler.ResolveDispute)
+		}
+
+		admin := api.Group("/admin")
+		admin.Use(middleware.RoleMiddleware("admin"), audit)
+		{
+			admin.POST("/transactions/:id/republish", s.transactionHandler.RepublishEvents)
 		}
 
 		balances := api.Group("/balances")
 		{
 			balances.GET("/current", s.balanceHandler.GetCurrentBalance)
+			balances.GET("/summary", s.balanceHandler.GetSummary)
 			balances.GET("/historical", s.balanceHandler.GetHistoricalBalance)
 			balances.GET("/at-time", s.balanceHandler.GetBalanceAtTime)
+
+			holds := balances.Group("/holds")
+			{
+				holds.POST("", middleware.ValidationMiddleware(&domain.BalanceHoldRequest{}), s.balanceHandler.AuthorizeHold)
+				holds.GET("", s.balanceHandler.GetActiveHolds)
+				holds.POST("/:id/capture", s.balanceHandler.CaptureHold)
+				holds.POST("/:id/release", s.balanceHandler.ReleaseHold)
+			}
+
+			alerts := balances.Group("/alerts")
+			{
+				alerts.POST("", s.alertHandler.CreateRule)
+				alerts.GET("", s.alertHandler.ListRules)
+				alerts.GET("/:id", s.alertHandler.GetRule)
+				alerts.PUT("/:id", s.alertHandler.UpdateRule)
+				alerts.DELETE("/:id", s.alertHandler.DeleteRule)
+			}
 		}
 
 		advanced := api.Group("/advanced")
 		{
-			scheduled := advanced.Group("/scheduled")
-			{
-				scheduled.POST("", s.advancedHandler.CreateScheduledTransaction)
-				scheduled.GET("", s.advancedHandler.GetUserScheduledTransactions)
-				scheduled.GET("/:id", s.advancedHandler.GetScheduledTransaction)
-				scheduled.PUT("/:id", s.advancedHandler.UpdateScheduledTransaction)
-				scheduled.DELETE("/:id", s.advancedHandler.CancelScheduledTransaction)
-				scheduled.POST("/execute", s.advancedHandler.ExecuteScheduledTransactions)
+			if s.advancedHandler.scheduledService != nil {
+				scheduled := advanced.Group("/scheduled")
+				{
+					scheduled.POST("", s.advancedHandler.CreateScheduledTransaction)
+					scheduled.POST("/bulk", batchBulkhead, s.advancedHandler.CreateScheduledTransactionsBulk)
+					scheduled.GET("", s.advancedHandler.GetUserScheduledTransactions)
+					scheduled.GET("/:id", s.advancedHandler.GetScheduledTransaction)
+					scheduled.PUT("/:id", s.advancedHandler.UpdateScheduledTransaction)
+					scheduled.DELETE("/:id", s.advancedHandler.CancelScheduledTransaction)
+					scheduled.POST("/:id/pause", s.advancedHandler.PauseScheduledTransaction)
+					scheduled.POST("/:id/resume", s.advancedHandler.ResumeScheduledTransaction)
+					scheduled.POST("/execute", batchBulkhead, s.advancedHandler.ExecuteScheduledTransactions)
+				}
 			}
 
-			batch := advanced.Group("/batch")
-			{
-				batch.POST("", s.advancedHandler.CreateBatchTransaction)
-				batch.GET("/:id", s.advancedHandler.GetBatchTransaction)
-				batch.GET("/:batch_id/items", s.advancedHandler.GetBatchTransactionItems)
-				batch.POST("/:id/process", s.advancedHandler.ProcessBatchTransaction)
-				batch.DELETE("/:id", s.advancedHandler.CancelBatchTransaction)
+			if s.advancedHandler.batchService != nil {
+				batch := advanced.Group("/batch")
+				{
+					batch.POST("", batchBulkhead, s.advancedHandler.CreateBatchTransaction)
+					batch.GET("/:id", s.advancedHandler.GetBatchTransaction)
+					batch.GET("/:batch_id/items", s.advancedHandler.GetBatchTransactionItems)
+					batch.POST("/:id/process", batchBulkhead, s.advancedHandler.ProcessBatchTransaction)
+					batch.DELETE("/:id", s.advancedHandler.CancelBatchTransaction)
+				}
 			}
 
 			limits := advanced.Group("/limits")
 			{
 				limits.POST("", s.advancedHandler.CreateTransactionLimit)
+				limits.GET("", s.advancedHandler.ListTransactionLimits)
 				limits.GET("/:currency", s.advancedHandler.GetTransactionLimit)
 				limits.PUT("/:currency", s.advancedHandler.UpdateTransactionLimit)
 				limits.POST("/:currency/reset", s.advancedHandler.ResetTransactionLimits)
+				limits.POST("/release/:transaction_id", middleware.RoleMiddleware("admin"), audit, s.advancedHandler.ReleaseTransactionLimit)
 			}
 
-			multiCurrency := advanced.Group("/multi-currency")
-			{
-				multiCurrency.POST("/balance", s.advancedHandler.CreateMultiCurrencyBalance)
-				multiCurrency.GET("/balance/:currency", s.advancedHandler.GetMultiCurrencyBalance)
-				multiCurrency.GET("/balances", s.advancedHandler.GetAllBalances)
-				multiCurrency.POST("/convert", s.advancedHandler.ConvertCurrency)
-				multiCurrency.POST("/transfer", s.advancedHandler.TransferBetweenCurrencies)
+			if s.advancedHandler.multiCurrencyService != nil {
+				multiCurrency := advanced.Group("/multi-currency")
+				{
+					multiCurrency.POST("/balance", s.advancedHandler.CreateMultiCurrencyBalance)
+					multiCurrency.GET("/balance/:currency", s.advancedHandler.GetMultiCurrencyBalance)
+					multiCurrency.GET("/balances", s.advancedHandler.GetAllBalances)
+					multiCurrency.GET("/transactions", s.advancedHandler.GetMultiCurrencyTransactions)
+					multiCurrency.POST("/convert", s.advancedHandler.ConvertCurrency)
+					multiCurrency.POST("/transfer", s.advancedHandler.TransferBetweenCurrencies)
+				}
 			}
 		}
 
 		events := api.Group("/events")
-		events.Use(middleware.RoleMiddleware("admin")) // Sadece admin'ler event'leri görebilir
+		events.Use(middleware.RoleMiddleware("admin"), audit) // Sadece admin'ler event'leri görebilir
 		{
 			events.GET("/aggregate/:aggregate_id", s.eventHandler.GetEventsByAggregate)
 			events.GET("/type/:event_type", s.eventHandler.GetEventsByType)
 			events.GET("/time-range", s.eventHandler.GetEventsByTimeRange)
+			events.GET("/export", exportBulkhead, s.eventHandler.ExportEvents)
+			events.GET("/statistics", s.eventHandler.GetEventStatistics)
 			events.GET("", s.eventHandler.GetAllEvents)
 			events.GET("/count/:aggregate_id", s.eventHandler.GetEventCount)
 
-			events.POST("/replay/aggregate/:aggregate_id", s.eventHandler.ReplayEventsForAggregate)
-			events.POST("/replay/type/:event_type", s.eventHandler.ReplayEventsByType)
-			events.POST("/replay/time-range", s.eventHandler.ReplayEventsByTimeRange)
-			events.POST("/replay/all", s.eventHandler.ReplayAllEvents)
+			events.POST("/replay/aggregate/:aggregate_id", replayBulkhead, s.eventHandler.ReplayEventsForAggregate)
+			events.POST("/replay/type/:event_type", replayBulkhead, s.eventHandler.ReplayEventsByType)
+			events.POST("/replay/time-range", replayBulkhead, s.eventHandler.ReplayEventsByTimeRange)
+			events.POST("/replay/all", replayBulkhead, s.eventHandler.ReplayAllEvents)
 			events.GET("/replay/statistics", s.eventHandler.GetReplayStatistics)
 		}
 
-		cache := api.Group("/cache")
-		cache.Use(middleware.RoleMiddleware("admin")) // Sadece admin'ler cache'i yönetebilir
-		{
-			cache.GET("/stats", s.cacheHandler.GetCacheStats)
-			cache.DELETE("/flush", s.cacheHandler.FlushAllCache)
-			cache.GET("/ttl/:key", s.cacheHandler.GetCacheTTL)
-			cache.GET("/exists/:key", s.cacheHandler.CheckCacheExists)
-			cache.POST("/increment/:key", s.cacheHandler.IncrementCacheKey)
-
-			cache.POST("/warmup/users", s.cacheHandler.WarmupUsers)
-			cache.POST("/warmup/transactions", s.cacheHandler.WarmupTransactions)
-			cache.POST("/warmup/balances", s.cacheHandler.WarmupBalances)
-			cache.POST("/warmup/aggregate-events", s.cacheHandler.WarmupAggregateEvents)
-
-			cache.DELETE("/invalidate/user/:user_id", s.cacheHandler.InvalidateUser)
-			cache.DELETE("/invalidate/transaction/:transaction_id", s.cacheHandler.InvalidateTransaction)
-			cache.DELETE("/invalidate/balance/:user_id", s.cacheHandler.InvalidateBalance)
-			cache.DELETE("/invalidate/aggregate-events/:aggregate_id", s.cacheHandler.InvalidateAggregateEvents)
-
-			cache.GET("/user/:user_id", s.cacheHandler.GetCachedUser)
-			cache.GET("/transaction/:transaction_id", s.cacheHandler.GetCachedTransaction)
-			cache.GET("/balance/:user_id", s.cacheHandler.GetCachedBalance)
-			cache.GET("/user/:user_id/transactions", s.cacheHandler.GetCachedUserTransactions)
-			cache.GET("/aggregate-events/:aggregate_id", s.cacheHandler.GetCachedAggregateEvents)
+		// Redis bağlı değilse cache yönetim route'ları kaydedilmez
+		if s.cacheHandler != nil {
+			cache := api.Group("/cache")
+			cache.Use(middleware.RoleMiddleware("admin"), audit) // Sadece admin'ler cache'i yönetebilir
+			{
+				cache.GET("/stats", s.cacheHandler.GetCacheStats)
+				cache.GET("/keys", s.cacheHandler.ListCacheKeys)
+				cache.DELETE("/flush", s.cacheHandler.FlushAllCache)
+				cache.GET("/ttl/:key", s.cacheHandler.GetCacheTTL)
+				cache.GET("/exists/:key", s.cacheHandler.CheckCacheExists)
+				cache.POST("/increment/:key", s.cacheHandler.IncrementCacheKey)
+				cache.DELETE("/key/:key", s.cacheHandler.ExpireCacheKey)
+
+				cache.POST("/warmup/users", s.cacheHandler.WarmupUsers)
+				cache.POST("/warmup/transactions", s.cacheHandler.WarmupTransactions)
+				cache.POST("/warmup/balances", s.cacheHandler.WarmupBalances)
+				cache.POST("/warmup/aggregate-events", s.cacheHandler.WarmupAggregateEvents)
+
+				cache.POST("/invalidate/batch", s.cacheHandler.InvalidateBatch)
+				cache.DELETE("/invalidate/user/:user_id", s.cacheHandler.InvalidateUser)
+				cache.DELETE("/invalidate/transaction/:transaction_id", s.cacheHandler.InvalidateTransaction)
+				cache.DELETE("/invalidate/balance/:user_id", s.cacheHandler.InvalidateBalance)
+				cache.DELETE("/invalidate/aggregate-events/:aggregate_id", s.cacheHandler.InvalidateAggregateEvents)
+
+				cache.GET("/user/:user_id", s.cacheHandler.GetCachedUser)
+				cache.GET("/transaction/:transaction_id", s.cacheHandler.GetCachedTransaction)
+				cache.GET("/balance/:user_id", s.cacheHandler.GetCachedBalance)
+				cache.GET("/user/:user_id/transactions", s.cacheHandler.GetCachedUserTransactions)
+				cache.GET("/aggregate-events/:aggregate_id", s.cacheHandler.GetCachedAggregateEvents)
+			}
 		}
 
 		ha := api.Group("/ha")
-		ha.Use(middleware.RoleMiddleware("admin")) // Sadece admin'ler HA'yı yönetebilir
+		ha.Use(middleware.RoleMiddleware("admin"), audit) // Sadece admin'ler HA'yı yönetebilir
 		{
 			ha.GET("/health", s.haHandler.GetSystemHealth)
 			ha.GET("/metrics", s.haHandler.GetHAMetrics)
@@ -238,31 +383,134 @@ func (s *Server) setupRoutes() {
 			ha.GET("/loadbalancer/stats", s.haHandler.GetLoadBalancerStats)
 			ha.POST("/loadbalancer/backends", s.haHandler.AddLoadBalancerBackend)
 			ha.DELETE("/loadbalancer/backends/:id", s.haHandler.RemoveLoadBalancerBackend)
+			ha.POST("/loadbalancer/health-check", s.haHandler.CheckLoadBalancerHealth)
 
 			ha.GET("/circuitbreakers", s.haHandler.GetAllCircuitBreakers)
 			ha.GET("/circuitbreakers/:name", s.haHandler.GetCircuitBreakerStats)
+			ha.GET("/circuitbreakers/:name/history", s.haHandler.GetCircuitBreakerHistory)
 			ha.POST("/circuitbreakers", s.haHandler.CreateCircuitBreaker)
 			ha.POST("/circuitbreakers/:name/open", s.haHandler.ForceCircuitBreakerOpen)
 			ha.POST("/circuitbreakers/:name/close", s.haHandler.ForceCircuitBreakerClose)
 			ha.POST("/circuitbreakers/:name/reset", s.haHandler.ResetCircuitBreaker)
 
 			ha.GET("/fallback/stats", s.haHandler.GetFallbackStats)
+			ha.GET("/bulkheads", s.haHandler.GetBulkheadStats)
 			ha.POST("/fallback/test", s.haHandler.TestFallback)
 
 			ha.GET("/config", s.haHandler.GetHAConfig)
 			ha.PUT("/config", s.haHandler.UpdateHAConfig)
 		}
+
+		if s.workerHandler != nil {
+			workers := api.Group("/workers")
+			workers.Use(middleware.RoleMiddleware("admin")) // Sadece admin'ler worker istatistiklerini görebilir
+			{
+				workers.GET("/stats", s.workerHandler.GetWorkerStats)
+			}
+		}
+
+		reconciliation := api.Group("/reconciliation")
+		reconciliation.Use(middleware.RoleMiddleware("admin"), audit) // Mutabakat raporları yalnızca admin'lere açık
+		{
+			reconciliation.GET("/reports/latest", s.reconcileHandler.GetLatestReport)
+			reconciliation.POST("/run", s.reconcileHandler.RunReconciliation)
+		}
+
+		featureFlags := api.Group("/feature-flags")
+		featureFlags.Use(middleware.RoleMiddleware("admin"), audit) // Flag'leri çalışma zamanında yalnızca admin'ler değiştirebilir
+		{
+			featureFlags.GET("", s.flagHandler.ListFlags)
+			featureFlags.GET("/:name", s.flagHandler.GetFlag)
+			featureFlags.PUT("/:name", s.flagHandler.UpdateFlag)
+			featureFlags.DELETE("/:name", s.flagHandler.ResetFlag)
+		}
+
+		if s.webhookHandler != nil {
+			webhooks := api.Group("/webhooks/deliveries")
+			webhooks.Use(middleware.RoleMiddleware("admin"), audit) // Teslimatları yalnızca admin'ler görüp yeniden gönderebilir
+			{
+				webhooks.GET("", s.webhookHandler.ListDeliveries)
+				webhooks.GET("/:id", s.webhookHandler.GetDelivery)
+				webhooks.POST("/:id/redeliver", s.webhookHandler.Redeliver)
+			}
+		}
+
+		if s.killSwitchHandler != nil {
+			killSwitch := api.Group("/kill-switch")
+			killSwitch.Use(middleware.RoleMiddleware("admin"), audit) // Otomasyonu yalnızca admin'ler durdurup başlatabilir
+			{
+				killSwitch.GET("", s.killSwitchHandler.GetKillSwitch)
+				killSwitch.PUT("", s.killSwitchHandler.UpdateKillSwitch)
+			}
+		}
+
+		if s.auditHandler != nil {
+			auditLogs := api.Group("/audit-logs")
+			auditLogs.Use(middleware.RoleMiddleware("admin"))
+			{
+				auditLogs.GET("", s.auditHandler.ListAuditLogs)
+			}
+		}
 	}
 }
 
+// Start sunucuyu çalıştırır; Shutdown ile kapatıldığında hata dönmez, böylece çağıran taraf
+// istekler boşaltılırken süreci sonlandırmaz
 func (s *Server) Start() error {
 	log.Info().Str("addr", s.server.Addr).Msg("Starting HTTP server")
-	return s.server.ListenAndServe()
+	if err := s.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// SetDrainTimeout kapanışta süren isteklerin tamamlanması için beklenecek süreyi ayarlar
+func (s *Server) SetDrainTimeout(timeout time.Duration) {
+	if timeout > 0 {
+		s.drainTimeout = timeout
+	}
 }
 
+// Shutdown yeni bağlantı kabulünü durdurur ve süren isteklerin bitmesini drain süresi (ctx daha önce
+// biterse ctx) boyunca bekler. Süre dolduğunda kalan bağlantılar kapatılır ve yarıda kalan istek
+// sayısı loglanır.
 func (s *Server) Shutdown(ctx context.Context) error {
-	log.Info().Msg("Shutting down HTTP server")
-	return s.server.Shutdown(ctx)
+	drainCtx, cancel := context.WithTimeout(ctx, s.drainTimeout)
+	defer cancel()
+
+	start := time.Now()
+	pending := s.inFlight.Load()
+	log.Info().
+		Int64("in_flight", pending).
+		Dur("drain_timeout", s.drainTimeout).
+		Msg("Shutting down HTTP server, draining in-flight requests")
+
+	err := s.server.Shutdown(drainCtx)
+	remaining := s.inFlight.Load()
+	if remaining > pending {
+		remaining = pending
+	}
+	completed := pending - remaining
+	metrics.ShutdownDrainedRequestsTotal.WithLabelValues("completed").Add(float64(completed))
+
+	if err != nil {
+		metrics.ShutdownDrainedRequestsTotal.WithLabelValues("aborted").Add(float64(remaining))
+		log.Warn().Err(err).
+			Int64("completed", completed).
+			Int64("aborted", remaining).
+			Dur("elapsed", time.Since(start)).
+			Msg("HTTP drain timed out, closing remaining connections")
+		if closeErr := s.server.Close(); closeErr != nil {
+			log.Error().Err(closeErr).Msg("Failed to close remaining HTTP connections")
+		}
+		return err
+	}
+
+	log.Info().
+		Int64("completed", completed).
+		Dur("elapsed", time.Since(start)).
+		Msg("HTTP server drained")
+	return nil
 }
 
 func (s *Server) GetEngine() *gin.Engine {
@@ -278,18 +526,28 @@ func (s *Server) SetHandlers(
 	userHandler *handlers.UserHandler,
 	transactionHandler *handlers.TransactionHandler,
 	balanceHandler *handlers.BalanceHandler,
+	alertHandler *handlers.BalanceAlertHandler,
+	disputeHandler *handlers.DisputeHandler,
 	eventHandler *EventHandler,
 	cacheHandler *CacheHandler,
 	advancedHandler *AdvancedTransactionHandler,
 	haHandler *HAHandler,
+	workerHandler *WorkerHandler,
+	reconcileHandler *ReconciliationHandler,
+	flagHandler *FeatureFlagHandler,
 ) {
 	s.authHandler = authHandler
 	s.userHandler = userHandler
 	s.transactionHandler = transactionHandler
 	s.balanceHandler = balanceHandler
+	s.alertHandler = alertHandler
+	s.disputeHandler = disputeHandler
 	s.eventHandler = eventHandler
 	s.cacheHandler = cacheHandler
 	s.advancedHandler = advancedHandler
 	s.haHandler = haHandler
+	s.workerHandler = workerHandler
+	s.reconcileHandler = reconcileHandler
+	s.flagHandler = flagHandler
 	s.setupRoutes()
 }
@@ -0,0 +1,89 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestServerShutdownDrainsInFlightRequests(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		drainTimeout time.Duration
+		releaseAfter time.Duration
+		wantErr      error
+		wantComplete bool
+	}{
+		{name: "drain süresinde biten istek tamamlanır", drainTimeout: 2 * time.Second, releaseAfter: 100 * time.Millisecond, wantComplete: true},
+		{name: "drain süresini aşan istek kesilir", drainTimeout: 100 * time.Millisecond, wantErr: context.DeadlineExceeded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := NewServer(0)
+			srv.SetDrainTimeout(tt.drainTimeout)
+
+			started := make(chan struct{})
+			release := make(chan struct{})
+			t.Cleanup(func() { close(release) })
+			srv.engine.GET("/slow", func(c *gin.Context) {
+				close(started)
+				<-release
+				c.String(http.StatusOK, "done")
+			})
+
+			ln, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatalf("dinlenemedi: %v", err)
+			}
+			go func() { _ = srv.server.Serve(ln) }()
+
+			type response struct {
+				body string
+				err  error
+			}
+			responses := make(chan response, 1)
+			go func() {
+				resp, err := http.Get("http://" + ln.Addr().String() + "/slow")
+				if err != nil {
+					responses <- response{err: err}
+					return
+				}
+				defer resp.Body.Close()
+				body, err := io.ReadAll(resp.Body)
+				responses <- response{body: string(body), err: err}
+			}()
+			<-started
+
+			shutdown := make(chan error, 1)
+			go func() { shutdown <- srv.Shutdown(context.Background()) }()
+			if tt.releaseAfter > 0 {
+				time.Sleep(tt.releaseAfter)
+				release <- struct{}{}
+			}
+
+			if err := <-shutdown; !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Shutdown = %v, beklenen %v", err, tt.wantErr)
+			}
+			got := <-responses
+			if tt.wantComplete {
+				if got.err != nil || got.body != "done" {
+					t.Fatalf("yanıt = %q (%v), beklenen tamamlanmış istek", got.body, got.err)
+				}
+			} else if got.err == nil {
+				t.Fatalf("drain süresini aşan istek kesilmeliydi, yanıt %q", got.body)
+			}
+			if n := srv.inFlight.Load(); tt.wantComplete && n != 0 {
+				t.Errorf("süren istek sayısı = %d, beklenen 0", n)
+			}
+		})
+	}
+}
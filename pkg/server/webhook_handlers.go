@@ -0,0 +1,104 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"transaction-api-w-go/pkg/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// WebhookDeliveryManager webhook teslimatlarını listeleyen ve dead-letter'dakileri yeniden gönderen servistir
+type WebhookDeliveryManager interface {
+	ListDeliveries(ctx context.Context, filter domain.WebhookDeliveryFilter) ([]*domain.WebhookDelivery, error)
+	GetDelivery(ctx context.Context, id uuid.UUID) (*domain.WebhookDelivery, error)
+	Redeliver(ctx context.Context, id uuid.UUID) (*domain.WebhookDelivery, error)
+}
+
+type WebhookHandler struct {
+	manager WebhookDeliveryManager
+}
+
+func NewWebhookHandler(manager WebhookDeliveryManager) *WebhookHandler {
+	return &WebhookHandler{
+		manager: manager,
+	}
+}
+
+// ListDeliveries teslimatları status (pending, retrying, delivered, dead_letter) ve limit ile döner
+func (h *WebhookHandler) ListDeliveries(c *gin.Context) {
+	filter := domain.WebhookDeliveryFilter{Status: domain.WebhookDeliveryStatus(c.Query("status"))}
+	switch filter.Status {
+	case "", domain.WebhookDeliveryPending, domain.WebhookDeliveryRetrying,
+		domain.WebhookDeliveryDelivered, domain.WebhookDeliveryDeadLetter:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status parameter"})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(domain.DefaultWebhookDeliveryLimit)))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit parameter"})
+		return
+	}
+	filter.Limit = limit
+	filter.Normalize()
+
+	deliveries, err := h.manager.ListDeliveries(c.Request.Context(), filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"deliveries": deliveries,
+		"limit":      filter.Limit,
+	})
+}
+
+// GetDelivery teslimatı deneme geçmişiyle birlikte döner
+func (h *WebhookHandler) GetDelivery(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid delivery ID"})
+		return
+	}
+
+	delivery, err := h.manager.GetDelivery(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(webhookErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, delivery)
+}
+
+// Redeliver dead-letter'daki teslimatı hemen yeniden gönderir ve denemenin sonucunu döner
+func (h *WebhookHandler) Redeliver(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid delivery ID"})
+		return
+	}
+
+	delivery, err := h.manager.Redeliver(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(webhookErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, delivery)
+}
+
+func webhookErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrWebhookDeliveryNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrWebhookNotRedeliverable):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
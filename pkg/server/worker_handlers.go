@@ -0,0 +1,45 @@
+package server
+
+import (
+	"net/http"
+	"time"
+
+	"transaction-api-w-go/pkg/worker"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WorkerHandler struct {
+	workerPool     *worker.TransactionWorkerPool
+	batchProcessor *worker.BatchProcessor
+}
+
+func NewWorkerHandler(workerPool *worker.TransactionWorkerPool, batchProcessor *worker.BatchProcessor) *WorkerHandler {
+	return &WorkerHandler{
+		workerPool:     workerPool,
+		batchProcessor: batchProcessor,
+	}
+}
+
+func (h *WorkerHandler) GetWorkerStats(c *gin.Context) {
+	response := gin.H{
+		"timestamp": time.Now(),
+	}
+
+	if h.workerPool != nil {
+		response["worker_pool"] = gin.H{
+			"workers":      h.workerPool.WorkerCount(),
+			"queue_length": h.workerPool.QueueLength(),
+			"stats":        h.workerPool.GetStats().Snapshot(),
+		}
+	}
+
+	if h.batchProcessor != nil {
+		response["batch_processor"] = gin.H{
+			"queue_length": h.batchProcessor.QueueLength(),
+			"stats":        h.batchProcessor.GetStats(),
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
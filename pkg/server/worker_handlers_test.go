@@ -0,0 +1,144 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"transaction-api-w-go/pkg/domain"
+	"transaction-api-w-go/pkg/worker"
+
+	"github.com/gin-gonic/gin"
+)
+
+// statsTransactionService worker pool'un kullandığı metodları uygular; 0 id'li işlem başarısız olur
+type statsTransactionService struct {
+	domain.TransactionService
+	stats *domain.TransactionStats
+}
+
+func (s *statsTransactionService) ProcessTransaction(ctx context.Context, transactionID uint) error {
+	if transactionID == 0 {
+		return errors.New("missing transaction")
+	}
+	return nil
+}
+
+func (s *statsTransactionService) GetStats() *domain.TransactionStats {
+	return s.stats
+}
+
+// statsBalanceService batch processor için her işlemi başarılı sayar
+type statsBalanceService struct {
+	domain.BalanceService
+}
+
+func (statsBalanceService) AddFunds(ctx context.Context, userID uint, amount float64) error {
+	return nil
+}
+
+func TestGetWorkerStats(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	type statsBody struct {
+		TotalProcessed     *uint64  `json:"total_processed"`
+		TotalFailed        *uint64  `json:"total_failed"`
+		TotalAmount        *float64 `json:"total_amount"`
+		AverageProcessTime *float64 `json:"average_process_time"`
+	}
+	type workerStatsResponse struct {
+		Timestamp  *time.Time `json:"timestamp"`
+		WorkerPool *struct {
+			Workers     int       `json:"workers"`
+			QueueLength int       `json:"queue_length"`
+			Stats       statsBody `json:"stats"`
+		} `json:"worker_pool"`
+		BatchProcessor *struct {
+			QueueLength int       `json:"queue_length"`
+			Stats       statsBody `json:"stats"`
+		} `json:"batch_processor"`
+	}
+
+	tests := []struct {
+		name            string
+		process         bool
+		wantPoolSuccess uint64
+		wantPoolFailed  uint64
+		wantPoolAmount  float64
+		wantBatch       uint64
+		wantBatchAmount float64
+	}{
+		{name: "işlem yokken sıfır"},
+		{
+			name:            "işlemlerden sonra güncellenir",
+			process:         true,
+			wantPoolSuccess: 1,
+			wantPoolFailed:  1,
+			wantPoolAmount:  10,
+			wantBatch:       2,
+			wantBatchAmount: 10,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool := worker.NewTransactionWorkerPool(2, &statsTransactionService{stats: &domain.TransactionStats{}}, nil)
+			pool.Start()
+			t.Cleanup(pool.Stop)
+			batch := worker.NewBatchProcessor(statsBalanceService{})
+			batch.Start()
+			t.Cleanup(batch.Stop)
+
+			if tt.process {
+				<-pool.SubmitJobWithResult(worker.TransactionJob{TransactionID: 1, Amount: 10})
+				<-pool.SubmitJobWithResult(worker.TransactionJob{TransactionID: 0, Amount: 5})
+
+				batch.SubmitJob(worker.BatchJob{Operation: worker.BatchOperationAdd, UserIDs: []uint{1, 2}, Amount: 5})
+				deadline := time.Now().Add(5 * time.Second)
+				for batch.GetStats().TotalProcessed < tt.wantBatch && time.Now().Before(deadline) {
+					time.Sleep(time.Millisecond)
+				}
+			}
+
+			engine := gin.New()
+			engine.GET("/api/v1/workers/stats", NewWorkerHandler(pool, batch).GetWorkerStats)
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/workers/stats", nil))
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, beklenen 200", w.Code)
+			}
+			var body workerStatsResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("yanıt çözülemedi: %v", err)
+			}
+
+			if body.Timestamp == nil || body.WorkerPool == nil || body.BatchProcessor == nil {
+				t.Fatalf("eksik alanlar: %s", w.Body.String())
+			}
+			for name, stats := range map[string]statsBody{"worker_pool": body.WorkerPool.Stats, "batch_processor": body.BatchProcessor.Stats} {
+				if stats.TotalProcessed == nil || stats.TotalFailed == nil || stats.TotalAmount == nil || stats.AverageProcessTime == nil {
+					t.Fatalf("%s istatistik alanları eksik: %s", name, w.Body.String())
+				}
+			}
+			if body.WorkerPool.Workers != 2 {
+				t.Errorf("workers = %d, beklenen 2", body.WorkerPool.Workers)
+			}
+
+			poolStats := body.WorkerPool.Stats
+			if *poolStats.TotalProcessed != tt.wantPoolSuccess || *poolStats.TotalFailed != tt.wantPoolFailed || *poolStats.TotalAmount != tt.wantPoolAmount {
+				t.Errorf("worker pool = %d/%d/%v, beklenen %d/%d/%v",
+					*poolStats.TotalProcessed, *poolStats.TotalFailed, *poolStats.TotalAmount, tt.wantPoolSuccess, tt.wantPoolFailed, tt.wantPoolAmount)
+			}
+			batchStats := body.BatchProcessor.Stats
+			if *batchStats.TotalProcessed != tt.wantBatch || *batchStats.TotalAmount != tt.wantBatchAmount {
+				t.Errorf("batch processor = %d/%v, beklenen %d/%v",
+					*batchStats.TotalProcessed, *batchStats.TotalAmount, tt.wantBatch, tt.wantBatchAmount)
+			}
+		})
+	}
+}
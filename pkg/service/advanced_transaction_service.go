@@ -2,11 +2,17 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
 	"sync"
 	"time"
 
+	"transaction-api-w-go/pkg/clock"
 	"transaction-api-w-go/pkg/domain"
+	"transaction-api-w-go/pkg/featureflag"
+	"transaction-api-w-go/pkg/killswitch"
+	"transaction-api-w-go/pkg/payment"
 
 	"github.com/google/uuid"
 )
@@ -16,7 +22,18 @@ type ScheduledTransactionServiceImpl struct {
 	transactionRepo domain.TransactionRepository
 	balanceRepo     domain.BalanceRepository
 	logger          domain.Logger
+	clock           clock.Clock
 	mu              sync.RWMutex
+
+	// leaseOwner bu örneği diğer zamanlayıcılardan ayırır; işlemler bu adla kiralanır
+	leaseOwner    string
+	leaseDuration time.Duration
+
+	// gateway nil ise dış hesaba zamanlanmış transferler başarısız olur ve yeniden denenir
+	gateway payment.PaymentGateway
+
+	// killSwitch çekiliyken zamanı gelen işlemler çalıştırılmaz, bir sonraki turda yeniden ele alınır
+	killSwitch *killswitch.Switch
 }
 
 func NewScheduledTransactionService(
@@ -24,15 +41,49 @@ func NewScheduledTransactionService(
 	transactionRepo domain.TransactionRepository,
 	balanceRepo domain.BalanceRepository,
 	logger domain.Logger,
+) domain.ScheduledTransactionService {
+	return NewScheduledTransactionServiceWithClock(scheduledRepo, transactionRepo, balanceRepo, logger, clock.Real())
+}
+
+// NewScheduledTransactionServiceWithClock yeniden deneme zamanlarını verilen saate göre hesaplar;
+// testlerde clock.Fake ilerletilerek backoff beklemeden tetiklenebilir
+func NewScheduledTransactionServiceWithClock(
+	scheduledRepo domain.ScheduledTransactionRepository,
+	transactionRepo domain.TransactionRepository,
+	balanceRepo domain.BalanceRepository,
+	logger domain.Logger,
+	clk clock.Clock,
 ) domain.ScheduledTransactionService {
 	return &ScheduledTransactionServiceImpl{
 		scheduledRepo:   scheduledRepo,
 		transactionRepo: transactionRepo,
 		balanceRepo:     balanceRepo,
-		logger:          logger,
+		logger:          domain.LoggerOrNop(logger),
+		clock:           clk,
+		leaseOwner:      defaultLeaseOwner(),
+		leaseDuration:   domain.DefaultScheduledLeaseDuration,
 	}
 }
 
+// defaultLeaseOwner aynı hostta çalışan örnekler de ayrışsın diye host adına rastgele bir ek koyar
+func defaultLeaseOwner() string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "scheduler"
+	}
+	return fmt.Sprintf("%s-%s", host, uuid.NewString()[:8])
+}
+
+// SetPaymentGateway dış hesaba zamanlanmış transferlerin iletileceği ödeme ağ geçidini bağlar
+func (s *ScheduledTransactionServiceImpl) SetPaymentGateway(gateway payment.PaymentGateway) {
+	s.gateway = gateway
+}
+
+// SetKillSwitch zamanlanmış işlemleri cluster genelinde duraklatan anahtarı bağlar
+func (s *ScheduledTransactionServiceImpl) SetKillSwitch(sw *killswitch.Switch) {
+	s.killSwitch = sw
+}
+
 func (s *ScheduledTransactionServiceImpl) CreateScheduledTransaction(ctx context.Context, userID uuid.UUID, req domain.ScheduledTransactionRequest) (*domain.ScheduledTransaction, error) {
 	scheduledTransaction, err := domain.NewScheduledTransaction(userID, req)
 	if err != nil {
@@ -52,6 +103,42 @@ func (s *ScheduledTransactionServiceImpl) CreateScheduledTransaction(ctx context
 	return scheduledTransaction, nil
 }
 
+// CreateScheduledTransactions önce tüm kalemleri doğrular; geçersiz kalem varsa hiçbirini
+// oluşturmadan kalem bazlı hataları döndürür, aksi halde hepsini tek transaction'da kaydeder
+func (s *ScheduledTransactionServiceImpl) CreateScheduledTransactions(ctx context.Context, userID uuid.UUID, reqs []domain.ScheduledTransactionRequest) ([]*domain.ScheduledTransaction, []domain.BulkItemError, error) {
+	if len(reqs) == 0 {
+		return nil, nil, domain.ErrBulkValidationFailed
+	}
+	if len(reqs) > domain.MaxBulkScheduledTransactions {
+		return nil, nil, domain.ErrTooManyBulkItems
+	}
+
+	scheduledTransactions := make([]*domain.ScheduledTransaction, 0, len(reqs))
+	var itemErrors []domain.BulkItemError
+	for i, req := range reqs {
+		scheduledTransaction, err := domain.NewScheduledTransaction(userID, req)
+		if err != nil {
+			itemErrors = append(itemErrors, domain.BulkItemError{Index: i, Error: err.Error()})
+			continue
+		}
+		scheduledTransactions = append(scheduledTransactions, scheduledTransaction)
+	}
+
+	if len(itemErrors) > 0 {
+		return nil, itemErrors, domain.ErrBulkValidationFailed
+	}
+
+	if err := s.scheduledRepo.CreateMany(ctx, scheduledTransactions); err != nil {
+		return nil, nil, err
+	}
+
+	s.logger.Info("Scheduled transactions created in bulk",
+		"user_id", userID,
+		"count", len(scheduledTransactions))
+
+	return scheduledTransactions, nil, nil
+}
+
 func (s *ScheduledTransactionServiceImpl) GetScheduledTransaction(ctx context.Context, id uuid.UUID) (*domain.ScheduledTransaction, error) {
 	return s.scheduledRepo.GetByID(ctx, id)
 }
@@ -60,11 +147,32 @@ func (s *ScheduledTransactionServiceImpl) GetUserScheduledTransactions(ctx conte
 	return s.scheduledRepo.GetByUserID(ctx, userID)
 }
 
+// UpdateScheduledTransaction yalnızca pending veya paused durumdaki işlemleri günceller; yeni
+// çalıştırma zamanı gelecekte olmalıdır
 func (s *ScheduledTransactionServiceImpl) UpdateScheduledTransaction(ctx context.Context, id uuid.UUID, req domain.ScheduledTransactionRequest) error {
 	scheduledTransaction, err := s.scheduledRepo.GetByID(ctx, id)
 	if err != nil {
 		return err
 	}
+	if !scheduledTransaction.IsModifiable() {
+		return domain.ErrInvalidScheduledStatus
+	}
+	if req.ScheduledAt.Before(s.clock.Now()) {
+		return domain.ErrInvalidScheduledTime
+	}
+
+	timezone := req.Timezone
+	if timezone == "" {
+		timezone = scheduledTransaction.Timezone
+	}
+	if _, err := domain.LoadTimezone(timezone); err != nil {
+		return err
+	}
+	if req.ExecutionWindow != nil {
+		if err := req.ExecutionWindow.Validate(); err != nil {
+			return err
+		}
+	}
 
 	scheduledTransaction.Type = req.Type
 	scheduledTransaction.Amount = req.Amount
@@ -72,16 +180,25 @@ func (s *ScheduledTransactionServiceImpl) UpdateScheduledTransaction(ctx context
 	scheduledTransaction.Description = req.Description
 	scheduledTransaction.ReferenceID = req.ReferenceID
 	scheduledTransaction.ToUserID = req.ToUserID
-	scheduledTransaction.ScheduledAt = req.ScheduledAt
+	scheduledTransaction.ScheduledAt = req.ScheduledAt.UTC()
+	scheduledTransaction.Timezone = timezone
 	scheduledTransaction.RecurringType = req.RecurringType
 	scheduledTransaction.RecurringConfig = req.RecurringConfig
+	scheduledTransaction.ExecutionWindow = req.ExecutionWindow
 	scheduledTransaction.UpdatedAt = time.Now()
 
 	if req.MaxRetries != nil {
 		scheduledTransaction.MaxRetries = *req.MaxRetries
 	}
 
-	return s.scheduledRepo.Update(ctx, scheduledTransaction)
+	updated, err := s.scheduledRepo.UpdateModifiable(ctx, scheduledTransaction)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return domain.ErrInvalidScheduledStatus
+	}
+	return nil
 }
 
 func (s *ScheduledTransactionServiceImpl) CancelScheduledTransaction(ctx context.Context, id uuid.UUID) error {
@@ -94,13 +211,77 @@ func (s *ScheduledTransactionServiceImpl) CancelScheduledTransaction(ctx context
 	return s.scheduledRepo.Update(ctx, scheduledTransaction)
 }
 
+func (s *ScheduledTransactionServiceImpl) PauseScheduledTransaction(ctx context.Context, id uuid.UUID) (*domain.ScheduledTransaction, error) {
+	scheduledTransaction, err := s.scheduledRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := scheduledTransaction.Pause(); err != nil {
+		return nil, err
+	}
+
+	if err := s.scheduledRepo.Update(ctx, scheduledTransaction); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Scheduled transaction paused", "id", id)
+	return scheduledTransaction, nil
+}
+
+func (s *ScheduledTransactionServiceImpl) ResumeScheduledTransaction(ctx context.Context, id uuid.UUID) (*domain.ScheduledTransaction, error) {
+	scheduledTransaction, err := s.scheduledRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := scheduledTransaction.Resume(s.clock.Now()); err != nil {
+		return nil, err
+	}
+
+	if err := s.scheduledRepo.Update(ctx, scheduledTransaction); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Scheduled transaction resumed",
+		"id", id,
+		"scheduled_at", scheduledTransaction.ScheduledAt)
+	return scheduledTransaction, nil
+}
+
+// ExecuteScheduledTransactions zamanı gelen bekleyen işlemleri ve backoff süresi dolan başarısız
+// işlemlerin yeniden denemelerini çalıştırır
 func (s *ScheduledTransactionServiceImpl) ExecuteScheduledTransactions(ctx context.Context) error {
+	if s.killSwitch.Engaged(ctx) {
+		return domain.ErrAutomationPaused
+	}
+
 	pendingTransactions, err := s.scheduledRepo.GetPendingScheduledTransactions(ctx)
 	if err != nil {
 		return err
 	}
 
+	now := s.clock.Now()
 	for _, scheduledTransaction := range pendingTransactions {
+		// Anahtar tur ortasında çekilirse henüz kiralanmamış işlemlere dokunulmaz
+		if s.killSwitch.Engaged(ctx) {
+			return domain.ErrAutomationPaused
+		}
+
+		deferred, err := s.deferToExecutionWindow(ctx, scheduledTransaction, now)
+		if err != nil {
+			s.logger.Error("Failed to apply execution window",
+				"id", scheduledTransaction.ID,
+				"error", err)
+			continue
+		}
+		if deferred {
+			continue
+		}
+		if !s.claimScheduledTransaction(ctx, scheduledTransaction, now) {
+			continue
+		}
+
 		if err := s.executeScheduledTransaction(ctx, scheduledTransaction); err != nil {
 			s.logger.Error("Failed to execute scheduled transaction",
 				"id", scheduledTransaction.ID,
@@ -109,9 +290,123 @@ func (s *ScheduledTransactionServiceImpl) ExecuteScheduledTransactions(ctx conte
 		}
 	}
 
+	if err := s.retryFailedScheduledTransactions(ctx, now); err != nil {
+		return err
+	}
+	return s.reclaimExpiredLeases(ctx, now)
+}
+
+// claimScheduledTransaction işlemi bu örnek adına kiralar; başka bir zamanlayıcı işlemi
+// önce aldıysa veya işlem artık uygun değilse false döner ve işlem atlanır
+func (s *ScheduledTransactionServiceImpl) claimScheduledTransaction(ctx context.Context, scheduledTransaction *domain.ScheduledTransaction, now time.Time) bool {
+	leaseUntil := now.Add(s.leaseDuration)
+	claimed, err := s.scheduledRepo.ClaimScheduledTransaction(ctx, scheduledTransaction.ID, s.leaseOwner, now, leaseUntil)
+	if err != nil {
+		s.logger.Error("Failed to claim scheduled transaction",
+			"id", scheduledTransaction.ID,
+			"error", err)
+		return false
+	}
+	if !claimed {
+		s.logger.Debug("Scheduled transaction already claimed",
+			"id", scheduledTransaction.ID)
+		return false
+	}
+
+	scheduledTransaction.ApplyLease(s.leaseOwner, leaseUntil)
+	return true
+}
+
+// reclaimExpiredLeases kirası dolmuş işlemleri yeniden çalıştırır; bunlar çalıştırma
+// sırasında çöken bir örneğin executing durumunda bıraktığı işlemlerdir
+func (s *ScheduledTransactionServiceImpl) reclaimExpiredLeases(ctx context.Context, now time.Time) error {
+	expired, err := s.scheduledRepo.GetExpiredLeaseScheduledTransactions(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	for _, scheduledTransaction := range expired {
+		previousOwner := ""
+		if scheduledTransaction.LeaseOwner != nil {
+			previousOwner = *scheduledTransaction.LeaseOwner
+		}
+		if !s.claimScheduledTransaction(ctx, scheduledTransaction, now) {
+			continue
+		}
+
+		s.logger.Warn("Reclaiming scheduled transaction with expired lease",
+			"id", scheduledTransaction.ID,
+			"previous_owner", previousOwner)
+
+		if err := s.executeScheduledTransaction(ctx, scheduledTransaction); err != nil {
+			s.logger.Error("Failed to execute reclaimed scheduled transaction",
+				"id", scheduledTransaction.ID,
+				"error", err)
+		}
+	}
+
+	return nil
+}
+
+// retryFailedScheduledTransactions NextRetryAt'i dolan başarısız işlemleri yeniden çalıştırır;
+// tekrar başarısız olanlar executeScheduledTransaction içinde bir sonraki backoff'a kurulur
+func (s *ScheduledTransactionServiceImpl) retryFailedScheduledTransactions(ctx context.Context, now time.Time) error {
+	retryable, err := s.scheduledRepo.GetRetryableScheduledTransactions(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	for _, scheduledTransaction := range retryable {
+		if !scheduledTransaction.IsRetryDue(now) {
+			continue
+		}
+		if !s.claimScheduledTransaction(ctx, scheduledTransaction, now) {
+			continue
+		}
+
+		s.logger.Info("Retrying scheduled transaction",
+			"id", scheduledTransaction.ID,
+			"retry_count", scheduledTransaction.RetryCount)
+
+		if err := s.executeScheduledTransaction(ctx, scheduledTransaction); err != nil {
+			s.logger.Error("Scheduled transaction retry failed",
+				"id", scheduledTransaction.ID,
+				"retry_count", scheduledTransaction.RetryCount,
+				"next_retry_at", scheduledTransaction.NextRetryAt,
+				"error", err)
+		}
+	}
+
 	return nil
 }
 
+// deferToExecutionWindow işlem çalışma penceresi dışındaysa bir sonraki geçerli
+// zamana erteler ve true döndürür
+func (s *ScheduledTransactionServiceImpl) deferToExecutionWindow(ctx context.Context, scheduledTransaction *domain.ScheduledTransaction, now time.Time) (bool, error) {
+	if scheduledTransaction.ExecutionWindow == nil {
+		return false, nil
+	}
+
+	next, err := scheduledTransaction.ExecutionWindow.NextSlot(now, scheduledTransaction.Location())
+	if err != nil {
+		return false, err
+	}
+	if !next.After(now) {
+		return false, nil
+	}
+
+	scheduledTransaction.ScheduledAt = next
+	scheduledTransaction.UpdatedAt = now
+	if err := s.scheduledRepo.Update(ctx, scheduledTransaction); err != nil {
+		return false, err
+	}
+
+	s.logger.Info("Scheduled transaction deferred to execution window",
+		"id", scheduledTransaction.ID,
+		"scheduled_at", next)
+	return true, nil
+}
+
 func (s *ScheduledTransactionServiceImpl) executeScheduledTransaction(ctx context.Context, scheduledTransaction *domain.ScheduledTransaction) error {
 	transaction, err := domain.NewTransaction(scheduledTransaction.UserID, scheduledTransaction.Amount, scheduledTransaction.Description)
 	if err != nil {
@@ -131,6 +426,8 @@ func (s *ScheduledTransactionServiceImpl) executeScheduledTransaction(ctx contex
 	case domain.TransactionTypeTransfer:
 		if scheduledTransaction.ToUserID != nil {
 			err = s.processTransferTransaction(ctx, transaction, *scheduledTransaction.ToUserID)
+		} else if scheduledTransaction.ExternalAccount != nil {
+			err = s.processExternalTransferTransaction(ctx, transaction, scheduledTransaction.ExternalAccount)
 		} else {
 			err = fmt.Errorf("transfer transaction requires to_user_id")
 		}
@@ -139,16 +436,33 @@ func (s *ScheduledTransactionServiceImpl) executeScheduledTransaction(ctx contex
 	}
 
 	if err != nil {
-		scheduledTransaction.IncrementRetry()
-		if scheduledTransaction.CanRetry() {
-			scheduledTransaction.UpdateStatus("failed")
-		} else {
-			scheduledTransaction.UpdateStatus("cancelled")
+		if !scheduledTransaction.RecordFailure(s.clock.Now()) {
+			s.logger.Warn("Scheduled transaction retries exhausted, cancelling",
+				"id", scheduledTransaction.ID,
+				"retry_count", scheduledTransaction.RetryCount)
 		}
 		s.scheduledRepo.Update(ctx, scheduledTransaction)
 		return err
 	}
 
+	scheduledTransaction.NextRetryAt = nil
+
+	next, ok, err := scheduledTransaction.NextOccurrence()
+	if err != nil {
+		s.logger.Warn("Invalid recurring config, completing series",
+			"id", scheduledTransaction.ID,
+			"error", err)
+	}
+	if ok {
+		// Seri devam ediyor: bir sonraki çalışma zamanı işlemin timezone'unda hesaplandı
+		scheduledTransaction.ScheduledAt = next
+		scheduledTransaction.OccurrenceCount++
+		scheduledTransaction.RetryCount = 0
+		scheduledTransaction.UpdateStatus("pending")
+		return s.scheduledRepo.Update(ctx, scheduledTransaction)
+	}
+
+	scheduledTransaction.OccurrenceCount++
 	scheduledTransaction.UpdateStatus("completed")
 	return s.scheduledRepo.Update(ctx, scheduledTransaction)
 }
@@ -222,6 +536,60 @@ func (s *ScheduledTransactionServiceImpl) processTransferTransaction(ctx context
 	return s.transactionRepo.Create(ctx, transaction)
 }
 
+// processExternalTransferTransaction transferi pending_settlement durumunda kaydedip ödeme ağ geçidine
+// iletir. Bakiye ağ geçidi transferi sonuçlandırana kadar değişmez. Ağ geçidine ulaşılamazsa kayıt failed
+// olur ve hata döner; zamanlanmış işlem normal yeniden deneme akışıyla tekrar çalıştırılır.
+func (s *ScheduledTransactionServiceImpl) processExternalTransferTransaction(ctx context.Context, transaction *domain.Transaction, account *domain.ExternalAccount) error {
+	if s.gateway == nil {
+		return domain.ErrPaymentGatewayUnavailable
+	}
+
+	balance, err := s.balanceRepo.GetByUserID(ctx, uint(transaction.UserID.ID()))
+	if err != nil {
+		return err
+	}
+	if err := balance.CheckWithdrawal(balance.GetAmount(), transaction.Amount); err != nil {
+		return err
+	}
+
+	transaction.ExternalAccount = account
+	transaction.BalanceAfter = balance.GetAmount() - transaction.Amount
+	if err := transaction.UpdateState(domain.TransactionStatePendingSettlement); err != nil {
+		return err
+	}
+	if err := s.transactionRepo.Create(ctx, transaction); err != nil {
+		return err
+	}
+
+	result, err := s.gateway.Withdraw(ctx, &payment.Withdrawal{
+		TransactionID: transaction.ID,
+		Amount:        transaction.Amount,
+		Description:   transaction.Description,
+		Destination:   *account,
+	})
+	switch {
+	case err != nil:
+		transaction.StatusReason = "payment gateway unavailable"
+		err = fmt.Errorf("%w: %v", domain.ErrPaymentGatewayUnavailable, err)
+	case result.Status == payment.StatusFailed:
+		transaction.GatewayReference = result.Reference
+		transaction.StatusReason = result.Reason
+		err = fmt.Errorf("%w: %s", domain.ErrExternalTransferRejected, result.Reason)
+	default:
+		transaction.GatewayReference = result.Reference
+		transaction.UpdatedAt = time.Now()
+		return s.transactionRepo.Update(ctx, transaction)
+	}
+
+	transaction.UpdateState(domain.TransactionStateFailed)
+	if updateErr := s.transactionRepo.Update(ctx, transaction); updateErr != nil {
+		s.logger.Error("Failed to mark external transfer as failed",
+			"transaction_id", transaction.ID,
+			"error", updateErr)
+	}
+	return err
+}
+
 type BatchTransactionServiceImpl struct {
 	batchRepo       domain.BatchTransactionRepository
 	batchItemRepo   domain.BatchTransactionItemRepository
@@ -229,8 +597,13 @@ type BatchTransactionServiceImpl struct {
 	balanceRepo     domain.BalanceRepository
 	logger          domain.Logger
 	mu              sync.RWMutex
+	flags           *featureflag.Service
+	killSwitch      *killswitch.Switch
 }
 
+// parallelBatchWorkers parallel_batch flag'i açıkken aynı anda işlenen kalem sayısı
+const parallelBatchWorkers = 4
+
 func NewBatchTransactionService(
 	batchRepo domain.BatchTransactionRepository,
 	batchItemRepo domain.BatchTransactionItemRepository,
@@ -243,11 +616,27 @@ func NewBatchTransactionService(
 		batchItemRepo:   batchItemRepo,
 		transactionRepo: transactionRepo,
 		balanceRepo:     balanceRepo,
-		logger:          logger,
+		logger:          domain.LoggerOrNop(logger),
 	}
 }
 
+// SetFeatureFlags kademeli açılan özellikleri (ör. paralel batch işleme) kontrol eden servisi bağlar
+func (s *BatchTransactionServiceImpl) SetFeatureFlags(flags *featureflag.Service) {
+	s.flags = flags
+}
+
+// SetKillSwitch batch işlemeyi cluster genelinde duraklatan anahtarı bağlar
+func (s *BatchTransactionServiceImpl) SetKillSwitch(sw *killswitch.Switch) {
+	s.killSwitch = sw
+}
+
 func (s *BatchTransactionServiceImpl) CreateBatchTransaction(ctx context.Context, userID uuid.UUID, req domain.BatchTransactionRequest) (*domain.BatchTransaction, error) {
+	// Kalemler çağıranın dizisini paylaştığı için bölüştürme kopya üzerinde yapılır
+	req.Items = append([]domain.BatchItem(nil), req.Items...)
+	if err := req.ResolveAmounts(); err != nil {
+		return nil, err
+	}
+
 	batchTransaction, err := domain.NewBatchTransaction(userID, req)
 	if err != nil {
 		return nil, err
@@ -304,6 +693,11 @@ func (s *BatchTransactionServiceImpl) ProcessBatchTransaction(ctx context.Contex
 		return fmt.Errorf("batch transaction is not in pending status")
 	}
 
+	// Batch pending durumda bırakılır; anahtar kalkınca yeniden işlenebilir
+	if s.killSwitch.Engaged(ctx) {
+		return domain.ErrAutomationPaused
+	}
+
 	batchTransaction.UpdateStatus("processing")
 	s.batchRepo.Update(ctx, batchTransaction)
 
@@ -315,14 +709,18 @@ func (s *BatchTransactionServiceImpl) ProcessBatchTransaction(ctx context.Contex
 	successCount := 0
 	failedCount := 0
 
-	for _, item := range items {
-		if err := s.processBatchItem(ctx, batchTransaction, item); err != nil {
-			failedCount++
-			s.logger.Error("Failed to process batch item",
-				"item_id", item.ID,
-				"error", err)
-		} else {
-			successCount++
+	if s.flags.IsEnabled(ctx, featureflag.FlagParallelBatch, batchTransaction.UserID.String()) {
+		successCount, failedCount = s.processItemsParallel(ctx, batchTransaction, items)
+	} else {
+		for _, item := range items {
+			if err := s.processBatchItem(ctx, batchTransaction, item); err != nil {
+				failedCount++
+				s.logger.Error("Failed to process batch item",
+					"item_id", item.ID,
+					"error", err)
+			} else {
+				successCount++
+			}
 		}
 	}
 
@@ -337,6 +735,41 @@ func (s *BatchTransactionServiceImpl) ProcessBatchTransaction(ctx context.Contex
 	return s.batchRepo.Update(ctx, batchTransaction)
 }
 
+// processItemsParallel kalemleri sınırlı sayıda goroutine ile işler; bakiye
+// güncellemeleri processCredit/DebitTransaction içinde sıraya alınır
+func (s *BatchTransactionServiceImpl) processItemsParallel(ctx context.Context, batchTransaction *domain.BatchTransaction, items []*domain.BatchTransactionItem) (successCount, failedCount int) {
+	var (
+		wg      sync.WaitGroup
+		countMu sync.Mutex
+		sem     = make(chan struct{}, parallelBatchWorkers)
+	)
+
+	for _, item := range items {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(item *domain.BatchTransactionItem) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			err := s.processBatchItem(ctx, batchTransaction, item)
+
+			countMu.Lock()
+			defer countMu.Unlock()
+			if err != nil {
+				failedCount++
+				s.logger.Error("Failed to process batch item",
+					"item_id", item.ID,
+					"error", err)
+			} else {
+				successCount++
+			}
+		}(item)
+	}
+
+	wg.Wait()
+	return successCount, failedCount
+}
+
 func (s *BatchTransactionServiceImpl) CancelBatchTransaction(ctx context.Context, id uuid.UUID) error {
 	batchTransaction, err := s.batchRepo.GetByID(ctx, id)
 	if err != nil {
@@ -396,6 +829,9 @@ func (s *BatchTransactionServiceImpl) processBatchItem(ctx context.Context, batc
 }
 
 func (s *BatchTransactionServiceImpl) processCreditTransaction(ctx context.Context, transaction *domain.Transaction) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	balance, err := s.balanceRepo.GetByUserID(ctx, uint(transaction.UserID.ID()))
 	if err != nil {
 		return err
@@ -415,6 +851,9 @@ func (s *BatchTransactionServiceImpl) processCreditTransaction(ctx context.Conte
 }
 
 func (s *BatchTransactionServiceImpl) processDebitTransaction(ctx context.Context, transaction *domain.Transaction) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	balance, err := s.balanceRepo.GetByUserID(ctx, uint(transaction.UserID.ID()))
 	if err != nil {
 		return err
@@ -432,3 +871,246 @@ func (s *BatchTransactionServiceImpl) processDebitTransaction(ctx context.Contex
 	transaction.UpdateState(domain.TransactionStateCompleted)
 	return s.transactionRepo.Create(ctx, transaction)
 }
+
+type TransactionLimitServiceImpl struct {
+	limitRepo       domain.TransactionLimitRepository
+	tiers           domain.LimitTierResolver
+	tierDefinitions map[domain.LimitTier]domain.LimitTierDefinition
+	logger          domain.Logger
+	clock           clock.Clock
+}
+
+func NewTransactionLimitService(
+	limitRepo domain.TransactionLimitRepository,
+	logger domain.Logger,
+) *TransactionLimitServiceImpl {
+	return &TransactionLimitServiceImpl{
+		limitRepo:       limitRepo,
+		tierDefinitions: domain.DefaultLimitTiers(),
+		logger:          domain.LoggerOrNop(logger),
+		clock:           clock.Real(),
+	}
+}
+
+// SetClock dönem sıfırlamalarında kullanılan saati değiştirir; testlerde clock.Fake ile
+// günlük/haftalık sıfırlamalar beklemeden tetiklenebilir
+func (s *TransactionLimitServiceImpl) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// SetLimitTiers kullanıcıya özel limit tanımlı olmayan para birimlerinde seviye limitlerini devreye alır
+func (s *TransactionLimitServiceImpl) SetLimitTiers(tiers domain.LimitTierResolver, definitions map[domain.LimitTier]domain.LimitTierDefinition) {
+	s.tiers = tiers
+	if definitions != nil {
+		s.tierDefinitions = definitions
+	}
+}
+
+func (s *TransactionLimitServiceImpl) CreateTransactionLimit(ctx context.Context, userID uuid.UUID, req domain.TransactionLimitRequest) (*domain.TransactionLimit, error) {
+	// Seviyeden türetilmiş kullanım kaydı varsa yeni kayıt açılmaz, kayıt kullanıcıya özel limite çevrilir
+	existing, err := s.limitRepo.GetByUserIDAndCurrency(ctx, userID, req.Currency)
+	if err == nil && !existing.IsOverride() {
+		if err := existing.Apply(req); err != nil {
+			return nil, err
+		}
+		if err := s.limitRepo.Update(ctx, existing); err != nil {
+			return nil, fmt.Errorf("failed to create transaction limit: %w", err)
+		}
+		return existing, nil
+	}
+
+	limit, err := domain.NewTransactionLimit(userID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.limitRepo.Create(ctx, limit); err != nil {
+		return nil, fmt.Errorf("failed to create transaction limit: %w", err)
+	}
+
+	s.logger.Info("Transaction limit created", "user_id", userID, "currency", req.Currency)
+	return limit, nil
+}
+
+// GetTransactionLimit para birimindeki etkin limiti döndürür (kullanıcıya özel → seviye)
+func (s *TransactionLimitServiceImpl) GetTransactionLimit(ctx context.Context, userID uuid.UUID, currency domain.Currency) (*domain.TransactionLimit, error) {
+	limit, _, err := s.effectiveLimit(ctx, userID, currency)
+	if err != nil {
+		return nil, err
+	}
+	if limit == nil {
+		return nil, domain.ErrTransactionLimitNotFound
+	}
+	return limit, nil
+}
+
+// ListTransactionLimits kullanıcının tüm para birimlerindeki limitlerini döndürür
+func (s *TransactionLimitServiceImpl) ListTransactionLimits(ctx context.Context, userID uuid.UUID) ([]*domain.TransactionLimit, error) {
+	limits, err := s.limitRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, limit := range limits {
+		if limit.IsOverride() {
+			continue
+		}
+		if definition, ok, err := s.tierDefinition(ctx, userID); err == nil && ok {
+			limit.ApplyTier(definition)
+		}
+	}
+	return limits, nil
+}
+
+func (s *TransactionLimitServiceImpl) UpdateTransactionLimit(ctx context.Context, userID uuid.UUID, currency domain.Currency, req domain.TransactionLimitRequest) error {
+	limit, err := s.limitRepo.GetByUserIDAndCurrency(ctx, userID, currency)
+	if err != nil {
+		return err
+	}
+
+	if err := limit.Apply(req); err != nil {
+		return err
+	}
+	return s.limitRepo.Update(ctx, limit)
+}
+
+// CheckTransactionLimit tutarın kullanıcının etkin limitine sığıp sığmadığını kontrol eder;
+// ne kullanıcıya özel limit ne de seviye tanımlıysa işlem sınırsız kabul edilir
+func (s *TransactionLimitServiceImpl) CheckTransactionLimit(ctx context.Context, userID uuid.UUID, currency domain.Currency, amount float64) error {
+	limit, _, err := s.effectiveLimit(ctx, userID, currency)
+	if err != nil || limit == nil {
+		return err
+	}
+
+	if err := limit.CheckSingleLimit(amount); err != nil {
+		return err
+	}
+	return limit.CheckDailyLimit(amount, s.clock.Now())
+}
+
+func (s *TransactionLimitServiceImpl) PreviewTransactionLimit(ctx context.Context, userID uuid.UUID, currency domain.Currency, amount float64) ([]error, error) {
+	limit, _, err := s.effectiveLimit(ctx, userID, currency)
+	if err != nil || limit == nil {
+		return nil, err
+	}
+	return limit.Violations(amount, s.clock.Now()), nil
+}
+
+// ReserveTransactionLimit limit kontrolünü ve kullanımın işlenmesini limit kaydının satır kilidi
+// altında tek adımda yapar; eşzamanlı işlemler kontrolü birlikte geçip limiti aşamaz
+func (s *TransactionLimitServiceImpl) ReserveTransactionLimit(ctx context.Context, userID uuid.UUID, currency domain.Currency, amount float64, transactionID uuid.UUID) error {
+	return s.updateUsageLocked(ctx, userID, currency, amount, transactionID, func(limit *domain.TransactionLimit) error {
+		return limit.ReserveDailyUsage(amount, s.clock.Now())
+	})
+}
+
+// UpdateTransactionUsage kullanımı kontrol etmeden işler; limit kontrolü gereken yollarda
+// ReserveTransactionLimit kullanılmalıdır
+func (s *TransactionLimitServiceImpl) UpdateTransactionUsage(ctx context.Context, userID uuid.UUID, currency domain.Currency, amount float64, transactionID uuid.UUID) error {
+	return s.updateUsageLocked(ctx, userID, currency, amount, transactionID, func(limit *domain.TransactionLimit) error {
+		limit.UpdateDailyUsage(amount, s.clock.Now())
+		return nil
+	})
+}
+
+// ReleaseTransactionLimit işlem için düşülen limit kullanımını iade eder; işlem başına idempotenttir
+// ve limit kontrolüne takılmamış (rezervasyonu olmayan) işlemler için hiçbir şey yapmaz
+func (s *TransactionLimitServiceImpl) ReleaseTransactionLimit(ctx context.Context, transactionID uuid.UUID) error {
+	err := s.limitRepo.RefundLocked(ctx, transactionID, func(limit *domain.TransactionLimit, reservation *domain.LimitReservation) error {
+		limit.RefundDailyUsage(reservation.Amount, reservation.ReservedAt)
+		return nil
+	})
+	if errors.Is(err, domain.ErrLimitReservationNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to release transaction limit: %w", err)
+	}
+
+	s.logger.Info("Transaction limit usage released", "transaction_id", transactionID)
+	return nil
+}
+
+func (s *TransactionLimitServiceImpl) updateUsageLocked(ctx context.Context, userID uuid.UUID, currency domain.Currency, amount float64, transactionID uuid.UUID, fn func(limit *domain.TransactionLimit) error) error {
+	limit, persisted, err := s.effectiveLimit(ctx, userID, currency)
+	if err != nil || limit == nil {
+		return err
+	}
+
+	var seed *domain.TransactionLimit
+	if !persisted {
+		seed = limit
+	}
+	definition := limit.TierDefinition()
+
+	reservation := domain.NewLimitReservation(transactionID, userID, currency, amount)
+	reservation.ReservedAt = s.clock.Now()
+	return s.limitRepo.UpdateLocked(ctx, userID, currency, seed, reservation, func(locked *domain.TransactionLimit) error {
+		// Kilitli satır seviyeden türetilmişse limit değerleri çözülen seviyeyle eşitlenir
+		if !locked.IsOverride() && definition.Tier != "" {
+			locked.ApplyTier(definition)
+		}
+		return fn(locked)
+	})
+}
+
+func (s *TransactionLimitServiceImpl) ResetTransactionLimits(ctx context.Context, userID uuid.UUID, currency domain.Currency) error {
+	limit, persisted, err := s.effectiveLimit(ctx, userID, currency)
+	if err != nil {
+		return err
+	}
+	if limit == nil {
+		return domain.ErrTransactionLimitNotFound
+	}
+	if !persisted {
+		return nil
+	}
+
+	limit.ResetUsage(s.clock.Now())
+	return s.limitRepo.Update(ctx, limit)
+}
+
+// effectiveLimit sırasıyla kullanıcıya özel limiti, ardından kullanıcının seviye limitini çözer.
+// persisted false ise dönen kayıt seviyeden yeni üretilmiştir ve henüz kaydedilmemiştir;
+// limit nil ise kullanıcı bu para biriminde sınırsızdır
+func (s *TransactionLimitServiceImpl) effectiveLimit(ctx context.Context, userID uuid.UUID, currency domain.Currency) (limit *domain.TransactionLimit, persisted bool, err error) {
+	limit, err = s.limitRepo.GetByUserIDAndCurrency(ctx, userID, currency)
+	if err != nil && !errors.Is(err, domain.ErrTransactionLimitNotFound) {
+		return nil, false, err
+	}
+	persisted = err == nil
+	if persisted && limit.IsOverride() {
+		return limit, true, nil
+	}
+
+	definition, ok, err := s.tierDefinition(ctx, userID)
+	if err != nil {
+		return nil, false, err
+	}
+	if !ok {
+		return nil, false, nil
+	}
+
+	if persisted {
+		limit.ApplyTier(definition)
+		return limit, true, nil
+	}
+	return domain.NewTierLimit(userID, currency, definition), false, nil
+}
+
+func (s *TransactionLimitServiceImpl) tierDefinition(ctx context.Context, userID uuid.UUID) (domain.LimitTierDefinition, bool, error) {
+	if s.tiers == nil {
+		return domain.LimitTierDefinition{}, false, nil
+	}
+
+	tier, err := s.tiers.GetLimitTier(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return domain.LimitTierDefinition{}, false, nil
+		}
+		return domain.LimitTierDefinition{}, false, fmt.Errorf("failed to resolve limit tier: %w", err)
+	}
+
+	definition, ok := s.tierDefinitions[tier]
+	return definition, ok, nil
+}
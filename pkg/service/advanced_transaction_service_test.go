@@ -0,0 +1,1175 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"transaction-api-w-go/pkg/clock"
+	"transaction-api-w-go/pkg/domain"
+	"transaction-api-w-go/pkg/killswitch"
+	"transaction-api-w-go/pkg/repository"
+
+	"github.com/google/uuid"
+)
+
+// memoryLimitRepository limit kayıtlarını bellekte tutar. UpdateLocked ve RefundLocked tek bir mutex
+// altında çalışır; veritabanındaki satır kilidinin eşzamanlı işlemleri sıraya dizmesini taklit eder.
+type memoryLimitRepository struct {
+	mu           sync.Mutex
+	limits       map[string][]byte
+	reservations map[uuid.UUID]*domain.LimitReservation
+}
+
+func newMemoryLimitRepository() *memoryLimitRepository {
+	return &memoryLimitRepository{
+		limits:       make(map[string][]byte),
+		reservations: make(map[uuid.UUID]*domain.LimitReservation),
+	}
+}
+
+func limitKey(userID uuid.UUID, currency domain.Currency) string {
+	return userID.String() + "/" + string(currency)
+}
+
+// load kaydın bir kopyasını döner; kopya JSON üzerinden alınır, kayıttaki mutex kopyalanmaz
+func (r *memoryLimitRepository) load(key string) (*domain.TransactionLimit, error) {
+	data, ok := r.limits[key]
+	if !ok {
+		return nil, domain.ErrTransactionLimitNotFound
+	}
+	var limit domain.TransactionLimit
+	if err := json.Unmarshal(data, &limit); err != nil {
+		return nil, err
+	}
+	return &limit, nil
+}
+
+func (r *memoryLimitRepository) store(limit *domain.TransactionLimit) error {
+	data, err := json.Marshal(limit)
+	if err != nil {
+		return err
+	}
+	r.limits[limitKey(limit.UserID, limit.Currency)] = data
+	return nil
+}
+
+func (r *memoryLimitRepository) Create(ctx context.Context, limit *domain.TransactionLimit) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if limit.ID == uuid.Nil {
+		limit.ID = uuid.New()
+	}
+	return r.store(limit)
+}
+
+func (r *memoryLimitRepository) GetByUserIDAndCurrency(ctx context.Context, userID uuid.UUID, currency domain.Currency) (*domain.TransactionLimit, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.load(limitKey(userID, currency))
+}
+
+func (r *memoryLimitRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.TransactionLimit, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var limits []*domain.TransactionLimit
+	for key := range r.limits {
+		limit, err := r.load(key)
+		if err != nil {
+			return nil, err
+		}
+		if limit.UserID == userID {
+			limits = append(limits, limit)
+		}
+	}
+	return limits, nil
+}
+
+func (r *memoryLimitRepository) UpdateLocked(ctx context.Context, userID uuid.UUID, currency domain.Currency, seed *domain.TransactionLimit, reservation *domain.LimitReservation, fn func(limit *domain.TransactionLimit) error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if reservation != nil {
+		if _, ok := r.reservations[reservation.TransactionID]; ok {
+			return nil
+		}
+	}
+	key := limitKey(userID, currency)
+	if _, ok := r.limits[key]; !ok && seed != nil {
+		if seed.ID == uuid.Nil {
+			seed.ID = uuid.New()
+		}
+		if err := r.store(seed); err != nil {
+			return err
+		}
+	}
+
+	limit, err := r.load(key)
+	if err != nil {
+		return err
+	}
+	if err := fn(limit); err != nil {
+		return err
+	}
+	if err := r.store(limit); err != nil {
+		return err
+	}
+	if reservation != nil {
+		reservation.LimitID = limit.ID
+		r.reservations[reservation.TransactionID] = reservation
+	}
+	return nil
+}
+
+func (r *memoryLimitRepository) RefundLocked(ctx context.Context, transactionID uuid.UUID, fn func(limit *domain.TransactionLimit, reservation *domain.LimitReservation) error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	reservation, ok := r.reservations[transactionID]
+	if !ok {
+		return domain.ErrLimitReservationNotFound
+	}
+	if reservation.IsRefunded() {
+		return nil
+	}
+	limit, err := r.load(limitKey(reservation.UserID, reservation.Currency))
+	if err != nil {
+		return err
+	}
+	if err := fn(limit, reservation); err != nil {
+		return err
+	}
+	if err := r.store(limit); err != nil {
+		return err
+	}
+	now := time.Now()
+	reservation.RefundedAt = &now
+	return nil
+}
+
+func (r *memoryLimitRepository) Update(ctx context.Context, limit *domain.TransactionLimit) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.store(limit)
+}
+
+func (r *memoryLimitRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for key := range r.limits {
+		limit, err := r.load(key)
+		if err != nil {
+			return err
+		}
+		if limit.ID == id {
+			delete(r.limits, key)
+		}
+	}
+	return nil
+}
+
+// newTestLimitService kullanıcıya yalnızca günlük tutarı sınırlayan bir limit tanımlar
+func newTestLimitService(t *testing.T, userID uuid.UUID, dailyLimit float64) (*TransactionLimitServiceImpl, *memoryLimitRepository) {
+	t.Helper()
+	repo := newMemoryLimitRepository()
+	if err := repo.Create(context.Background(), newTestLimit(userID, dailyLimit)); err != nil {
+		t.Fatalf("limit oluşturulamadı: %v", err)
+	}
+	return NewTransactionLimitService(repo, nil), repo
+}
+
+// newTestLimit kullanıcıya özel, yalnızca günlük tutarı sınırlayan bir TRY limiti döner
+func newTestLimit(userID uuid.UUID, dailyLimit float64) *domain.TransactionLimit {
+	now := time.Now()
+	return &domain.TransactionLimit{
+		ID:                uuid.New(),
+		UserID:            userID,
+		Currency:          domain.CurrencyTRY,
+		DailyLimit:        dailyLimit,
+		WeeklyLimit:       dailyLimit * 100,
+		MonthlyLimit:      dailyLimit * 100,
+		SingleLimit:       dailyLimit,
+		DailyCountLimit:   1000,
+		WeeklyCountLimit:  1000,
+		MonthlyCountLimit: 1000,
+		IsActive:          true,
+		LastResetDate:     now,
+		WeeklyResetDate:   now,
+		MonthlyResetDate:  now,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+}
+
+func dailyAmount(t *testing.T, repo *memoryLimitRepository, userID uuid.UUID) float64 {
+	t.Helper()
+	limit, err := repo.GetByUserIDAndCurrency(context.Background(), userID, domain.CurrencyTRY)
+	if err != nil {
+		t.Fatalf("limit okunamadı: %v", err)
+	}
+	return limit.DailyAmount
+}
+
+func TestReserveTransactionLimitConcurrent(t *testing.T) {
+	tests := []struct {
+		name       string
+		dailyLimit float64
+		// tier kullanıcıya özel limit yerine seviye limitini kullanır; satır ilk rezervasyonda seed edilir
+		tier         bool
+		amount       float64
+		workers      int
+		wantAccepted int
+	}{
+		{name: "limit tam dolar", dailyLimit: 100, amount: 10, workers: 25, wantAccepted: 10},
+		{name: "limit tam bölünmez", dailyLimit: 95, amount: 10, workers: 25, wantAccepted: 9},
+		{name: "tek işlemlik limit", dailyLimit: 50, amount: 50, workers: 10, wantAccepted: 1},
+		{name: "istek sayısı limitin altında", dailyLimit: 100, amount: 10, workers: 5, wantAccepted: 5},
+		{name: "seviye limiti eşzamanlı ilk kullanımda bir kez seed edilir", tier: true, dailyLimit: 1000, amount: 100, workers: 25, wantAccepted: 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			ctx := context.Background()
+			userID := uuid.MustParse(env.createUserWithoutBalance(t))
+			repo := repository.NewTransactionLimitRepository(env.db)
+			svc := NewTransactionLimitService(repo, nil)
+			if tt.tier {
+				svc.SetLimitTiers(env.userRepo, domain.DefaultLimitTiers())
+			} else if err := repo.Create(ctx, newTestLimit(userID, tt.dailyLimit)); err != nil {
+				t.Fatalf("limit oluşturulamadı: %v", err)
+			}
+
+			// Bellek içi sunucuda satır kilidi yok; tek bağlantı UpdateLocked transaction'larını sıraya
+			// dizer, rezervasyonlar yine seed, kilitli okuma ve yazım adımları arasında iç içe geçer
+			sqlDB, err := env.db.DB()
+			if err != nil {
+				t.Fatalf("bağlantı havuzu alınamadı: %v", err)
+			}
+			sqlDB.SetMaxOpenConns(1)
+
+			var (
+				wg       sync.WaitGroup
+				mu       sync.Mutex
+				accepted int
+			)
+			start := make(chan struct{})
+			for i := 0; i < tt.workers; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					<-start
+					err := svc.ReserveTransactionLimit(ctx, userID, domain.CurrencyTRY, tt.amount, uuid.New())
+					switch {
+					case err == nil:
+						mu.Lock()
+						accepted++
+						mu.Unlock()
+					case !errors.Is(err, domain.ErrDailyLimitExceeded):
+						t.Errorf("ReserveTransactionLimit = %v, beklenen nil veya ErrDailyLimitExceeded", err)
+					}
+				}()
+			}
+			close(start)
+			wg.Wait()
+
+			if accepted != tt.wantAccepted {
+				t.Errorf("kabul edilen = %d, beklenen %d", accepted, tt.wantAccepted)
+			}
+			limits, err := repo.GetByUserID(ctx, userID)
+			if err != nil || len(limits) != 1 {
+				t.Fatalf("limit kayıtları = %d, %v; beklenen tek kayıt", len(limits), err)
+			}
+			if got := limits[0].DailyAmount; got > tt.dailyLimit || got != float64(accepted)*tt.amount {
+				t.Errorf("günlük kullanım = %v, kabul edilen %d işlem ve limit %v ile tutarsız", got, accepted, tt.dailyLimit)
+			}
+			var reservations int64
+			if err := env.db.Model(&domain.LimitReservation{}).Where("limit_id = ?", limits[0].ID).Count(&reservations).Error; err != nil {
+				t.Fatalf("rezervasyonlar sayılamadı: %v", err)
+			}
+			if reservations != int64(accepted) {
+				t.Errorf("rezervasyon = %d, beklenen %d", reservations, accepted)
+			}
+		})
+	}
+}
+
+func TestReserveTransactionLimitIdempotentPerTransaction(t *testing.T) {
+	tests := []struct {
+		name      string
+		reserves  int
+		wantUsage float64
+	}{
+		{name: "tek rezervasyon", reserves: 1, wantUsage: 10},
+		{name: "aynı işlem tekrar rezerve edilir", reserves: 3, wantUsage: 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userID := uuid.New()
+			svc, repo := newTestLimitService(t, userID, 100)
+			transactionID := uuid.New()
+
+			for i := 0; i < tt.reserves; i++ {
+				if err := svc.ReserveTransactionLimit(context.Background(), userID, domain.CurrencyTRY, 10, transactionID); err != nil {
+					t.Fatalf("ReserveTransactionLimit: %v", err)
+				}
+			}
+			if got := dailyAmount(t, repo, userID); got != tt.wantUsage {
+				t.Errorf("günlük kullanım = %v, beklenen %v", got, tt.wantUsage)
+			}
+		})
+	}
+}
+
+func TestReleaseTransactionLimit(t *testing.T) {
+	tests := []struct {
+		name      string
+		reserve   bool
+		releases  int
+		wantUsage float64
+	}{
+		{name: "iade edilmeyen rezervasyon", reserve: true, wantUsage: 20},
+		{name: "rezervasyon iade edilir", reserve: true, releases: 1, wantUsage: 10},
+		{name: "tekrarlanan iade bir kez uygulanır", reserve: true, releases: 3, wantUsage: 10},
+		{name: "rezervasyonu olmayan işlem", releases: 1, wantUsage: 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userID := uuid.New()
+			svc, repo := newTestLimitService(t, userID, 100)
+			ctx := context.Background()
+
+			// Başka bir işlemin kullanımı iadeden etkilenmemeli
+			if err := svc.ReserveTransactionLimit(ctx, userID, domain.CurrencyTRY, 10, uuid.New()); err != nil {
+				t.Fatalf("ReserveTransactionLimit: %v", err)
+			}
+			transactionID := uuid.New()
+			if tt.reserve {
+				if err := svc.ReserveTransactionLimit(ctx, userID, domain.CurrencyTRY, 10, transactionID); err != nil {
+					t.Fatalf("ReserveTransactionLimit: %v", err)
+				}
+			}
+
+			for i := 0; i < tt.releases; i++ {
+				if err := svc.ReleaseTransactionLimit(ctx, transactionID); err != nil {
+					t.Fatalf("ReleaseTransactionLimit: %v", err)
+				}
+			}
+			if got := dailyAmount(t, repo, userID); got != tt.wantUsage {
+				t.Errorf("günlük kullanım = %v, beklenen %v", got, tt.wantUsage)
+			}
+		})
+	}
+}
+
+// memoryBalanceRepository zamanlanmış ve toplu işlem servislerinin kullandığı uint anahtarlı bakiye
+// arayüzünü bellekte karşılar; servisler kullanıcıyı uint(userID.ID()) ile arar
+type memoryBalanceRepository struct {
+	mu       sync.Mutex
+	users    map[uint]uuid.UUID
+	balances map[uint]float64
+}
+
+func newMemoryBalanceRepository() *memoryBalanceRepository {
+	return &memoryBalanceRepository{users: make(map[uint]uuid.UUID), balances: make(map[uint]float64)}
+}
+
+func (r *memoryBalanceRepository) set(userID uuid.UUID, amount float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.users[uint(userID.ID())] = userID
+	r.balances[uint(userID.ID())] = amount
+}
+
+func (r *memoryBalanceRepository) amount(userID uuid.UUID) float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.balances[uint(userID.ID())]
+}
+
+func (r *memoryBalanceRepository) Create(ctx context.Context, balance *domain.Balance) error {
+	r.set(balance.UserID, balance.GetAmount())
+	return nil
+}
+
+func (r *memoryBalanceRepository) GetByID(ctx context.Context, id uint) (*domain.Balance, error) {
+	return r.GetByUserID(ctx, id)
+}
+
+func (r *memoryBalanceRepository) GetByUserID(ctx context.Context, userID uint) (*domain.Balance, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	amount, ok := r.balances[userID]
+	if !ok {
+		return nil, domain.ErrBalanceNotFound
+	}
+	return &domain.Balance{ID: uuid.New(), UserID: r.users[userID], Amount: amount, Currency: string(domain.CurrencyTRY)}, nil
+}
+
+func (r *memoryBalanceRepository) Update(ctx context.Context, balance *domain.Balance) error {
+	r.set(balance.UserID, balance.GetAmount())
+	return nil
+}
+
+func (r *memoryBalanceRepository) Delete(ctx context.Context, id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.balances, id)
+	return nil
+}
+
+func (r *memoryBalanceRepository) CreateHistory(ctx context.Context, history *domain.BalanceHistory) error {
+	return nil
+}
+
+func (r *memoryBalanceRepository) GetHistoryByUserID(ctx context.Context, userID uint) ([]*domain.BalanceHistory, error) {
+	return nil, nil
+}
+
+// memoryTransactionRepository servislerin yazdığı işlem kayıtlarını sırasıyla saklar
+type memoryTransactionRepository struct {
+	mu           sync.Mutex
+	transactions []*domain.Transaction
+}
+
+func (r *memoryTransactionRepository) Create(ctx context.Context, transaction *domain.Transaction) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.transactions = append(r.transactions, transaction)
+	return nil
+}
+
+func (r *memoryTransactionRepository) GetByID(ctx context.Context, id uint) (*domain.Transaction, error) {
+	return nil, domain.ErrTransactionNotFound
+}
+
+func (r *memoryTransactionRepository) GetByUserID(ctx context.Context, userID uint) ([]*domain.Transaction, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var transactions []*domain.Transaction
+	for _, transaction := range r.transactions {
+		if uint(transaction.UserID.ID()) == userID {
+			transactions = append(transactions, transaction)
+		}
+	}
+	return transactions, nil
+}
+
+func (r *memoryTransactionRepository) Update(ctx context.Context, transaction *domain.Transaction) error {
+	return nil
+}
+
+func (r *memoryTransactionRepository) Delete(ctx context.Context, id uint) error {
+	return nil
+}
+
+func (r *memoryTransactionRepository) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.transactions)
+}
+
+// scheduledTestEnv zamanlanmış işlemleri bellek içi veritabanında, bakiye ve işlem kayıtlarını
+// bellekte tutar
+type scheduledTestEnv struct {
+	*testEnv
+	scheduledRepo domain.ScheduledTransactionRepository
+	balances      *memoryBalanceRepository
+	transactions  *memoryTransactionRepository
+	clock         *clock.Fake
+}
+
+func newScheduledTestEnv(t *testing.T) *scheduledTestEnv {
+	t.Helper()
+	env := newTestEnv(t)
+	return &scheduledTestEnv{
+		testEnv:       env,
+		scheduledRepo: repository.NewScheduledTransactionRepository(env.db),
+		balances:      newMemoryBalanceRepository(),
+		transactions:  &memoryTransactionRepository{},
+		clock:         clock.NewFake(time.Now()),
+	}
+}
+
+func (e *scheduledTestEnv) scheduledService() *ScheduledTransactionServiceImpl {
+	return NewScheduledTransactionServiceWithClock(e.scheduledRepo, e.transactions, e.balances, nil, e.clock).(*ScheduledTransactionServiceImpl)
+}
+
+// createUser kullanıcıyı veritabanında oluşturur ve bakiyesini bellek içi repository'ye yazar
+func (e *scheduledTestEnv) createUser(t *testing.T, amount float64) uuid.UUID {
+	t.Helper()
+	userID := uuid.MustParse(e.testEnv.createUser(t, 0))
+	e.balances.set(userID, amount)
+	return userID
+}
+
+// createDue zamanı dt kadar önce gelmiş bir zamanlanmış işlem yazar; servis geçmiş zamanlı işlem
+// oluşturmaya izin vermediği için kayıt doğrudan repository'ye eklenir
+func (e *scheduledTestEnv) createDue(t *testing.T, userID uuid.UUID, req domain.ScheduledTransactionRequest, dt time.Duration) *domain.ScheduledTransaction {
+	t.Helper()
+	req.ScheduledAt = time.Now().Add(time.Hour)
+	scheduledTransaction, err := domain.NewScheduledTransaction(userID, req)
+	if err != nil {
+		t.Fatalf("NewScheduledTransaction: %v", err)
+	}
+	scheduledTransaction.ScheduledAt = time.Now().Add(-dt).UTC().Truncate(time.Second)
+	if err := e.scheduledRepo.Create(context.Background(), scheduledTransaction); err != nil {
+		t.Fatalf("zamanlanmış işlem yazılamadı: %v", err)
+	}
+	return scheduledTransaction
+}
+
+func (e *scheduledTestEnv) scheduled(t *testing.T, id uuid.UUID) *domain.ScheduledTransaction {
+	t.Helper()
+	scheduledTransaction, err := e.scheduledRepo.GetByID(context.Background(), id)
+	if err != nil {
+		t.Fatalf("zamanlanmış işlem okunamadı: %v", err)
+	}
+	return scheduledTransaction
+}
+
+func TestScheduledTransactionPauseResume(t *testing.T) {
+	daily := domain.RecurringDaily
+
+	tests := []struct {
+		name            string
+		recurringType   *string
+		overdue         time.Duration
+		wantCredits     int
+		wantStatus      string
+		wantOccurrences int
+	}{
+		{name: "tek seferlik işlem devam edince çalışır", overdue: time.Minute, wantCredits: 1, wantStatus: "completed", wantOccurrences: 1},
+		{
+			name:          "seri kaçırılan çalışmaları atlayıp sıradakini bekler",
+			recurringType: &daily,
+			overdue:       49 * time.Hour,
+			wantStatus:    "pending",
+			// Duraklatma süresince kaçırılan iki gün ve bugünkü çalışma atlanır
+			wantOccurrences: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newScheduledTestEnv(t)
+			svc := env.scheduledService()
+			ctx := context.Background()
+			userID := env.createUser(t, 100)
+
+			scheduledTransaction := env.createDue(t, userID, domain.ScheduledTransactionRequest{
+				Type:          domain.TransactionTypeCredit,
+				Amount:        10,
+				Currency:      domain.CurrencyTRY,
+				RecurringType: tt.recurringType,
+			}, tt.overdue)
+
+			if _, err := svc.PauseScheduledTransaction(ctx, scheduledTransaction.ID); err != nil {
+				t.Fatalf("PauseScheduledTransaction: %v", err)
+			}
+			if _, err := svc.PauseScheduledTransaction(ctx, scheduledTransaction.ID); !errors.Is(err, domain.ErrInvalidScheduledStatus) {
+				t.Errorf("ikinci PauseScheduledTransaction = %v, beklenen ErrInvalidScheduledStatus", err)
+			}
+			if err := svc.ExecuteScheduledTransactions(ctx); err != nil {
+				t.Fatalf("ExecuteScheduledTransactions: %v", err)
+			}
+			if got := env.scheduled(t, scheduledTransaction.ID).Status; got != "paused" || env.transactions.count() != 0 {
+				t.Fatalf("duraklatılmış işlem çalıştı: durum %q, %d işlem", got, env.transactions.count())
+			}
+
+			resumed, err := svc.ResumeScheduledTransaction(ctx, scheduledTransaction.ID)
+			if err != nil {
+				t.Fatalf("ResumeScheduledTransaction: %v", err)
+			}
+			if tt.recurringType != nil && !resumed.ScheduledAt.After(env.clock.Now()) {
+				t.Errorf("ScheduledAt = %v, devam eden seri gelecekte olmalı", resumed.ScheduledAt)
+			}
+			if err := svc.ExecuteScheduledTransactions(ctx); err != nil {
+				t.Fatalf("ExecuteScheduledTransactions: %v", err)
+			}
+
+			stored := env.scheduled(t, scheduledTransaction.ID)
+			if stored.Status != tt.wantStatus {
+				t.Errorf("Status = %q, beklenen %q", stored.Status, tt.wantStatus)
+			}
+			if stored.OccurrenceCount != tt.wantOccurrences {
+				t.Errorf("OccurrenceCount = %d, beklenen %d", stored.OccurrenceCount, tt.wantOccurrences)
+			}
+			if got := env.transactions.count(); got != tt.wantCredits {
+				t.Errorf("işlem sayısı = %d, beklenen %d", got, tt.wantCredits)
+			}
+			if got, want := env.balances.amount(userID), 100+10*float64(tt.wantCredits); got != want {
+				t.Errorf("bakiye = %v, beklenen %v", got, want)
+			}
+		})
+	}
+}
+
+func TestScheduledTransactionExecutionWindow(t *testing.T) {
+	// Gerçek saatten önceki son cumartesi; bekleyen işlemler gerçek saate göre seçilir
+	now := time.Now().UTC()
+	saturday := time.Date(now.Year(), now.Month(), now.Day(), 12, 0, 0, 0, time.UTC)
+	for saturday.Weekday() != time.Saturday || !saturday.Before(now.Add(-time.Hour)) {
+		saturday = saturday.AddDate(0, 0, -1)
+	}
+	monday := time.Date(saturday.Year(), saturday.Month(), saturday.Day()+2, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		window        *domain.ExecutionWindow
+		wantExecuted  bool
+		wantScheduled time.Time
+	}{
+		{
+			name:          "hafta sonu gelen işlem pazartesiye ertelenir",
+			window:        &domain.ExecutionWindow{Start: "09:00", End: "17:00", Weekdays: []int{1, 2, 3, 4, 5}},
+			wantScheduled: monday,
+		},
+		{
+			name:         "pencere içindeki işlem çalışır",
+			window:       &domain.ExecutionWindow{Start: "09:00", End: "17:00"},
+			wantExecuted: true,
+		},
+		{
+			name:         "penceresiz işlem çalışır",
+			wantExecuted: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newScheduledTestEnv(t)
+			env.clock = clock.NewFake(saturday)
+			svc := env.scheduledService()
+			userID := env.createUser(t, 100)
+
+			scheduledTransaction := env.createDue(t, userID, domain.ScheduledTransactionRequest{
+				Type:            domain.TransactionTypeCredit,
+				Amount:          10,
+				Currency:        domain.CurrencyTRY,
+				ExecutionWindow: tt.window,
+			}, time.Since(saturday.Add(-2*time.Hour)))
+
+			if err := svc.ExecuteScheduledTransactions(context.Background()); err != nil {
+				t.Fatalf("ExecuteScheduledTransactions: %v", err)
+			}
+
+			stored := env.scheduled(t, scheduledTransaction.ID)
+			if executed := env.transactions.count() == 1; executed != tt.wantExecuted {
+				t.Fatalf("çalıştı = %v, beklenen %v (durum %q)", executed, tt.wantExecuted, stored.Status)
+			}
+			if tt.wantExecuted {
+				return
+			}
+			if stored.Status != "pending" {
+				t.Errorf("Status = %q, beklenen pending", stored.Status)
+			}
+			if !stored.ScheduledAt.Equal(tt.wantScheduled) {
+				t.Errorf("ScheduledAt = %v, beklenen %v", stored.ScheduledAt, tt.wantScheduled)
+			}
+			if got := env.balances.amount(userID); got != 100 {
+				t.Errorf("bakiye = %v, beklenen 100", got)
+			}
+		})
+	}
+}
+
+func TestCreateScheduledTransactionsBulk(t *testing.T) {
+	valid := func() domain.ScheduledTransactionRequest {
+		return domain.ScheduledTransactionRequest{
+			Type:        domain.TransactionTypeCredit,
+			Amount:      10,
+			Currency:    domain.CurrencyTRY,
+			ScheduledAt: time.Now().Add(time.Hour),
+		}
+	}
+	invalidAmount := valid()
+	invalidAmount.Amount = 0
+	pastTime := valid()
+	pastTime.ScheduledAt = time.Now().Add(-time.Hour)
+
+	tooMany := make([]domain.ScheduledTransactionRequest, domain.MaxBulkScheduledTransactions+1)
+	for i := range tooMany {
+		tooMany[i] = valid()
+	}
+
+	tests := []struct {
+		name          string
+		reqs          []domain.ScheduledTransactionRequest
+		wantErr       error
+		wantCreated   int
+		wantItemIndex []int
+	}{
+		{name: "hepsi geçerli", reqs: []domain.ScheduledTransactionRequest{valid(), valid(), valid()}, wantCreated: 3},
+		{
+			name:          "geçersiz öğeler raporlanır ve hiçbiri oluşturulmaz",
+			reqs:          []domain.ScheduledTransactionRequest{valid(), invalidAmount, pastTime},
+			wantErr:       domain.ErrBulkValidationFailed,
+			wantItemIndex: []int{1, 2},
+		},
+		{name: "boş liste", wantErr: domain.ErrBulkValidationFailed},
+		{name: "üst sınırı aşan liste", reqs: tooMany, wantErr: domain.ErrTooManyBulkItems},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newScheduledTestEnv(t)
+			svc := env.scheduledService()
+			ctx := context.Background()
+			userID := env.createUser(t, 100)
+
+			created, itemErrors, err := svc.CreateScheduledTransactions(ctx, userID, tt.reqs)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CreateScheduledTransactions = %v, beklenen %v", err, tt.wantErr)
+			}
+			if len(created) != tt.wantCreated {
+				t.Errorf("oluşturulan = %d, beklenen %d", len(created), tt.wantCreated)
+			}
+			if len(itemErrors) != len(tt.wantItemIndex) {
+				t.Fatalf("öğe hataları = %+v, beklenen indeksler %v", itemErrors, tt.wantItemIndex)
+			}
+			for i, index := range tt.wantItemIndex {
+				if itemErrors[i].Index != index || itemErrors[i].Error == "" {
+					t.Errorf("öğe hatası %d = %+v, beklenen indeks %d", i, itemErrors[i], index)
+				}
+			}
+
+			stored, err := env.scheduledRepo.GetByUserID(ctx, userID)
+			if err != nil {
+				t.Fatalf("GetByUserID: %v", err)
+			}
+			if len(stored) != tt.wantCreated {
+				t.Errorf("kayıtlı işlem sayısı = %d, beklenen %d", len(stored), tt.wantCreated)
+			}
+		})
+	}
+}
+
+// staticTierResolver kullanıcı seviyelerini sabit bir tablodan döndürür; tabloda olmayan kullanıcı bulunamaz
+type staticTierResolver map[uuid.UUID]domain.LimitTier
+
+func (r staticTierResolver) GetLimitTier(ctx context.Context, userID uuid.UUID) (domain.LimitTier, error) {
+	tier, ok := r[userID]
+	if !ok {
+		return "", domain.ErrUserNotFound
+	}
+	return tier, nil
+}
+
+func TestTransactionLimitServiceEffectiveLimit(t *testing.T) {
+	tests := []struct {
+		name        string
+		tier        domain.LimitTier
+		noResolver  bool
+		override    float64
+		usageTier   domain.LimitTier
+		amount      float64
+		wantErr     bool
+		wantTier    domain.LimitTier
+		wantSingle  float64
+		wantMissing bool
+	}{
+		{name: "seviye ve override yoksa sınırsız", noResolver: true, amount: 1e9, wantMissing: true},
+		{name: "bulunamayan kullanıcı sınırsız", amount: 1e9, wantMissing: true},
+		{name: "basic seviye tek işlem limitine sığar", tier: domain.LimitTierBasic, amount: 400, wantTier: domain.LimitTierBasic, wantSingle: 500},
+		{name: "basic seviye tek işlem limitini aşar", tier: domain.LimitTierBasic, amount: 600, wantErr: true, wantTier: domain.LimitTierBasic, wantSingle: 500},
+		{name: "premium seviye daha yüksek limit verir", tier: domain.LimitTierPremium, amount: 600, wantTier: domain.LimitTierPremium, wantSingle: 50000},
+		{name: "override seviyenin önüne geçer", tier: domain.LimitTierPremium, override: 100, amount: 200, wantErr: true, wantSingle: 100},
+		{name: "override seviyeden yüksek olabilir", tier: domain.LimitTierBasic, override: 2000, amount: 1500, wantSingle: 2000},
+		{name: "kayıtlı kullanım güncel seviyeyi izler", tier: domain.LimitTierPremium, usageTier: domain.LimitTierBasic, amount: 600, wantTier: domain.LimitTierPremium, wantSingle: 50000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := newMemoryLimitRepository()
+			svc := NewTransactionLimitService(repo, nil)
+			userID := uuid.New()
+
+			if !tt.noResolver {
+				resolver := staticTierResolver{}
+				if tt.tier != "" {
+					resolver[userID] = tt.tier
+				}
+				svc.SetLimitTiers(resolver, nil)
+			}
+			if tt.override > 0 {
+				req := domain.TransactionLimitRequest{Currency: domain.CurrencyTRY, DailyLimit: tt.override * 10, WeeklyLimit: tt.override * 10, MonthlyLimit: tt.override * 10, SingleLimit: tt.override}
+				if _, err := svc.CreateTransactionLimit(ctx, userID, req); err != nil {
+					t.Fatalf("CreateTransactionLimit: %v", err)
+				}
+			}
+			if tt.usageTier != "" {
+				usage := domain.NewTierLimit(userID, domain.CurrencyTRY, domain.DefaultLimitTiers()[tt.usageTier])
+				if err := repo.Create(ctx, usage); err != nil {
+					t.Fatalf("kullanım kaydı oluşturulamadı: %v", err)
+				}
+			}
+
+			if err := svc.CheckTransactionLimit(ctx, userID, domain.CurrencyTRY, tt.amount); (err != nil) != tt.wantErr {
+				t.Fatalf("CheckTransactionLimit(%v) = %v, hata bekleniyor: %v", tt.amount, err, tt.wantErr)
+			}
+
+			limit, err := svc.GetTransactionLimit(ctx, userID, domain.CurrencyTRY)
+			if tt.wantMissing {
+				if !errors.Is(err, domain.ErrTransactionLimitNotFound) {
+					t.Fatalf("GetTransactionLimit = %v, beklenen ErrTransactionLimitNotFound", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetTransactionLimit: %v", err)
+			}
+			if limit.Tier != tt.wantTier || limit.SingleLimit != tt.wantSingle {
+				t.Errorf("etkin limit = %q/%v, beklenen %q/%v", limit.Tier, limit.SingleLimit, tt.wantTier, tt.wantSingle)
+			}
+		})
+	}
+}
+
+func TestTransactionLimitResetWithFakeClock(t *testing.T) {
+	tests := []struct {
+		name    string
+		advance time.Duration
+		wantErr bool
+	}{
+		{name: "aynı gün içinde limit dolu kalır", advance: time.Hour, wantErr: true},
+		{name: "24 saat dolmadan sıfırlanmaz", advance: 24*time.Hour - time.Minute, wantErr: true},
+		{name: "24 saat dolunca sıfırlanır", advance: 24 * time.Hour},
+		{name: "günler sonra da sıfırlanmış olur", advance: 3 * 24 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			userID := uuid.New()
+			svc, repo := newTestLimitService(t, userID, 100)
+			clk := clock.NewFake(time.Now())
+			svc.SetClock(clk)
+
+			if err := svc.ReserveTransactionLimit(ctx, userID, domain.CurrencyTRY, 100, uuid.New()); err != nil {
+				t.Fatalf("ReserveTransactionLimit: %v", err)
+			}
+
+			clk.Advance(tt.advance)
+			err := svc.ReserveTransactionLimit(ctx, userID, domain.CurrencyTRY, 60, uuid.New())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("sonraki rezervasyon hatası = %v, hata bekleniyor: %v", err, tt.wantErr)
+			}
+
+			limit, err := repo.GetByUserIDAndCurrency(ctx, userID, domain.CurrencyTRY)
+			if err != nil {
+				t.Fatalf("limit okunamadı: %v", err)
+			}
+			wantUsed := 60.0
+			if tt.wantErr {
+				wantUsed = 100
+			}
+			if limit.DailyAmount != wantUsed {
+				t.Errorf("günlük kullanım = %v, beklenen %v", limit.DailyAmount, wantUsed)
+			}
+		})
+	}
+}
+
+func TestScheduledTransactionAutomaticRetry(t *testing.T) {
+	tests := []struct {
+		name             string
+		advance          time.Duration
+		topUp            bool
+		wantStatus       string
+		wantRetries      int
+		wantTransactions int
+		wantNextRetry    time.Duration
+	}{
+		{name: "backoff dolmadan yeniden denenmez", advance: 30 * time.Second, topUp: true, wantStatus: "failed", wantRetries: 1, wantNextRetry: time.Minute},
+		{name: "backoff dolunca yeniden denenir ve tamamlanır", advance: time.Minute, topUp: true, wantStatus: "completed", wantRetries: 1, wantTransactions: 1},
+		{name: "yeniden deneme tekrar başarısız olursa backoff uzar", advance: time.Minute, wantStatus: "failed", wantRetries: 2, wantNextRetry: time.Minute + 2*time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newScheduledTestEnv(t)
+			start := time.Now().UTC().Truncate(time.Second)
+			env.clock = clock.NewFake(start)
+			svc := env.scheduledService()
+			ctx := context.Background()
+			userID := env.createUser(t, 5)
+
+			scheduledTransaction := env.createDue(t, userID, domain.ScheduledTransactionRequest{
+				Type:     domain.TransactionTypeDebit,
+				Amount:   10,
+				Currency: domain.CurrencyTRY,
+			}, time.Minute)
+
+			if err := svc.ExecuteScheduledTransactions(ctx); err != nil {
+				t.Fatalf("ExecuteScheduledTransactions: %v", err)
+			}
+			failed := env.scheduled(t, scheduledTransaction.ID)
+			if failed.Status != "failed" || failed.RetryCount != 1 || failed.NextRetryAt == nil {
+				t.Fatalf("ilk çalıştırma = %q/%d/%v, beklenen failed/1 ve NextRetryAt", failed.Status, failed.RetryCount, failed.NextRetryAt)
+			}
+
+			if tt.topUp {
+				env.balances.set(userID, 100)
+			}
+			env.clock.Advance(tt.advance)
+			if err := svc.ExecuteScheduledTransactions(ctx); err != nil {
+				t.Fatalf("ExecuteScheduledTransactions: %v", err)
+			}
+
+			stored := env.scheduled(t, scheduledTransaction.ID)
+			if stored.Status != tt.wantStatus || stored.RetryCount != tt.wantRetries {
+				t.Errorf("durum = %q/%d, beklenen %q/%d", stored.Status, stored.RetryCount, tt.wantStatus, tt.wantRetries)
+			}
+			if got := env.transactions.count(); got != tt.wantTransactions {
+				t.Errorf("işlem sayısı = %d, beklenen %d", got, tt.wantTransactions)
+			}
+			if tt.wantNextRetry == 0 {
+				if stored.NextRetryAt != nil {
+					t.Errorf("NextRetryAt = %v, beklenen nil", stored.NextRetryAt)
+				}
+				return
+			}
+			if stored.NextRetryAt == nil || !stored.NextRetryAt.Equal(start.Add(tt.wantNextRetry)) {
+				t.Errorf("NextRetryAt = %v, beklenen %v", stored.NextRetryAt, start.Add(tt.wantNextRetry))
+			}
+		})
+	}
+}
+
+func TestScheduledTransactionLeasePreventsDoubleExecution(t *testing.T) {
+	tests := []struct {
+		name          string
+		schedulers    int
+		due           int
+		leaseExpired  bool
+		leaseActive   bool
+		wantExecuted  int
+		wantCompleted int
+	}{
+		{name: "tek zamanlayıcı", schedulers: 1, due: 5, wantExecuted: 5, wantCompleted: 5},
+		{name: "paralel zamanlayıcılar her işlemi bir kez çalıştırır", schedulers: 8, due: 5, wantExecuted: 5, wantCompleted: 5},
+		{name: "kirası dolan işlem bir kez geri alınır", schedulers: 8, due: 1, leaseExpired: true, wantExecuted: 1, wantCompleted: 1},
+		{name: "kirası süren işlem çalıştırılmaz", schedulers: 8, due: 1, leaseActive: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newScheduledTestEnv(t)
+			ctx := context.Background()
+			userID := env.createUser(t, 100)
+
+			// Bellek içi sunucuda satır kilidi yok; eşzamanlı koşullu UPDATE'ler MySQL'deki gibi
+			// sıralanmaz. Tek bağlantı her sorguyu atomik yapar, zamanlayıcılar yine sorgular arasında
+			// iç içe geçer ve aynı bekleyen satırı okur.
+			sqlDB, err := env.db.DB()
+			if err != nil {
+				t.Fatalf("bağlantı havuzu alınamadı: %v", err)
+			}
+			sqlDB.SetMaxOpenConns(1)
+
+			var ids []uuid.UUID
+			for i := 0; i < tt.due; i++ {
+				scheduledTransaction := env.createDue(t, userID, domain.ScheduledTransactionRequest{
+					Type:     domain.TransactionTypeCredit,
+					Amount:   10,
+					Currency: domain.CurrencyTRY,
+				}, time.Minute)
+				ids = append(ids, scheduledTransaction.ID)
+
+				if tt.leaseExpired || tt.leaseActive {
+					// Çalıştırma sırasında çöken bir örneğin bıraktığı kira
+					expiresAt := env.clock.Now().Add(-time.Minute)
+					if tt.leaseActive {
+						expiresAt = env.clock.Now().Add(time.Minute)
+					}
+					err := env.db.Model(&domain.ScheduledTransaction{}).Where("id = ?", scheduledTransaction.ID).
+						Updates(map[string]interface{}{"status": domain.ScheduledStatusExecuting, "lease_owner": "çöken-örnek", "lease_expires_at": expiresAt}).Error
+					if err != nil {
+						t.Fatalf("kira yazılamadı: %v", err)
+					}
+				}
+			}
+
+			// Her zamanlayıcı ayrı bir örneği temsil eder; yalnızca veritabanını paylaşırlar
+			var wg sync.WaitGroup
+			start := make(chan struct{})
+			for i := 0; i < tt.schedulers; i++ {
+				svc := env.scheduledService()
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					<-start
+					if err := svc.ExecuteScheduledTransactions(ctx); err != nil {
+						t.Errorf("ExecuteScheduledTransactions: %v", err)
+					}
+				}()
+			}
+			close(start)
+			wg.Wait()
+
+			if got := env.transactions.count(); got != tt.wantExecuted {
+				t.Errorf("çalıştırılan işlem = %d, beklenen %d", got, tt.wantExecuted)
+			}
+			if got, want := env.balances.amount(userID), 100+10*float64(tt.wantExecuted); got != want {
+				t.Errorf("bakiye = %v, beklenen %v", got, want)
+			}
+			completed := 0
+			for _, id := range ids {
+				if stored := env.scheduled(t, id); stored.Status == "completed" {
+					completed++
+					if stored.OccurrenceCount != 1 {
+						t.Errorf("%s OccurrenceCount = %d, beklenen 1", id, stored.OccurrenceCount)
+					}
+				}
+			}
+			if completed != tt.wantCompleted {
+				t.Errorf("tamamlanan = %d, beklenen %d", completed, tt.wantCompleted)
+			}
+		})
+	}
+}
+
+func TestUpdateScheduledTransactionGuards(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      string
+		scheduledAt time.Duration
+		wantErr     error
+	}{
+		{name: "bekleyen işlem güncellenir", status: "pending", scheduledAt: time.Hour},
+		{name: "duraklatılmış işlem güncellenir", status: "paused", scheduledAt: time.Hour},
+		{name: "tamamlanmış işlem güncellenemez", status: "completed", scheduledAt: time.Hour, wantErr: domain.ErrInvalidScheduledStatus},
+		{name: "çalışmakta olan işlem güncellenemez", status: domain.ScheduledStatusExecuting, scheduledAt: time.Hour, wantErr: domain.ErrInvalidScheduledStatus},
+		{name: "iptal edilmiş işlem güncellenemez", status: "cancelled", scheduledAt: time.Hour, wantErr: domain.ErrInvalidScheduledStatus},
+		{name: "geçmiş zaman reddedilir", status: "pending", scheduledAt: -time.Minute, wantErr: domain.ErrInvalidScheduledTime},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newScheduledTestEnv(t)
+			svc := env.scheduledService()
+			ctx := context.Background()
+			userID := env.createUser(t, 100)
+
+			scheduledTransaction := env.createDue(t, userID, domain.ScheduledTransactionRequest{
+				Type:     domain.TransactionTypeCredit,
+				Amount:   10,
+				Currency: domain.CurrencyTRY,
+			}, -time.Hour)
+			scheduledTransaction.Status = tt.status
+			if err := env.scheduledRepo.Update(ctx, scheduledTransaction); err != nil {
+				t.Fatalf("durum yazılamadı: %v", err)
+			}
+
+			err := svc.UpdateScheduledTransaction(ctx, scheduledTransaction.ID, domain.ScheduledTransactionRequest{
+				Type:        domain.TransactionTypeCredit,
+				Amount:      25,
+				Currency:    domain.CurrencyTRY,
+				ScheduledAt: env.clock.Now().Add(tt.scheduledAt),
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("UpdateScheduledTransaction = %v, beklenen %v", err, tt.wantErr)
+			}
+
+			stored := env.scheduled(t, scheduledTransaction.ID)
+			wantAmount := 25.0
+			if tt.wantErr != nil {
+				wantAmount = 10
+			}
+			if stored.Amount != wantAmount || stored.Status != tt.status {
+				t.Errorf("tutar/durum = %v/%q, beklenen %v/%q", stored.Amount, stored.Status, wantAmount, tt.status)
+			}
+		})
+	}
+}
+
+func TestKillSwitchHaltsAutomation(t *testing.T) {
+	tests := []struct {
+		name string
+		// setup servisi anahtarla kurar; işi bir kez çalıştıran ve kaydın durumunu okuyan fonksiyonları döner
+		setup func(t *testing.T, env *scheduledTestEnv, sw *killswitch.Switch, userID uuid.UUID) (process func() error, status func() string)
+	}{
+		{
+			name: "zamanlanmış işlemler",
+			setup: func(t *testing.T, env *scheduledTestEnv, sw *killswitch.Switch, userID uuid.UUID) (func() error, func() string) {
+				svc := env.scheduledService()
+				svc.SetKillSwitch(sw)
+				scheduledTransaction := env.createDue(t, userID, domain.ScheduledTransactionRequest{
+					Type:     domain.TransactionTypeCredit,
+					Amount:   10,
+					Currency: domain.CurrencyTRY,
+				}, time.Minute)
+				return func() error { return svc.ExecuteScheduledTransactions(context.Background()) },
+					func() string { return env.scheduled(t, scheduledTransaction.ID).Status }
+			},
+		},
+		{
+			name: "batch işleme",
+			setup: func(t *testing.T, env *scheduledTestEnv, sw *killswitch.Switch, userID uuid.UUID) (func() error, func() string) {
+				batchRepo := repository.NewBatchTransactionRepository(env.db)
+				svc := NewBatchTransactionService(batchRepo, repository.NewBatchTransactionItemRepository(env.db), env.transactions, env.balances, nil).(*BatchTransactionServiceImpl)
+				svc.SetKillSwitch(sw)
+				batch, err := svc.CreateBatchTransaction(context.Background(), userID, domain.BatchTransactionRequest{
+					Type:     domain.TransactionTypeCredit,
+					Currency: domain.CurrencyTRY,
+					Items:    []domain.BatchItem{{Amount: 4}, {Amount: 6}},
+				})
+				if err != nil {
+					t.Fatalf("CreateBatchTransaction: %v", err)
+				}
+				return func() error { return svc.ProcessBatchTransaction(context.Background(), batch.ID) },
+					func() string {
+						stored, err := batchRepo.GetByID(context.Background(), batch.ID)
+						if err != nil {
+							t.Fatalf("batch okunamadı: %v", err)
+						}
+						return stored.Status
+					}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newScheduledTestEnv(t)
+			ctx := context.Background()
+			userID := env.createUser(t, 100)
+			sw := killswitch.NewWithClock(false, env.clock)
+			process, status := tt.setup(t, env, sw, userID)
+
+			if _, err := sw.Engage(ctx, "incident", "ops"); err != nil {
+				t.Fatalf("Engage: %v", err)
+			}
+			if err := process(); !errors.Is(err, domain.ErrAutomationPaused) {
+				t.Fatalf("anahtar çekiliyken = %v, beklenen ErrAutomationPaused", err)
+			}
+			if got := status(); got != "pending" {
+				t.Errorf("anahtar çekiliyken durum = %q, beklenen pending", got)
+			}
+			if env.transactions.count() != 0 || env.balances.amount(userID) != 100 {
+				t.Fatalf("anahtar çekiliyken %d işlem yazıldı, bakiye %v", env.transactions.count(), env.balances.amount(userID))
+			}
+
+			// Anahtar bırakılınca bekleyen iş kaldığı yerden işlenir
+			if _, err := sw.Release(ctx, "resolved", "ops"); err != nil {
+				t.Fatalf("Release: %v", err)
+			}
+			if err := process(); err != nil {
+				t.Fatalf("anahtar bırakıldıktan sonra: %v", err)
+			}
+			if got := status(); got != "completed" {
+				t.Errorf("durum = %q, beklenen completed", got)
+			}
+			if got := env.balances.amount(userID); got != 110 {
+				t.Errorf("bakiye = %v, beklenen 110", got)
+			}
+		})
+	}
+}
@@ -1,20 +1,27 @@
 package service
 
 import (
-	"errors"
+	"context"
 	"time"
 
 	"transaction-api-w-go/pkg/domain"
 	"transaction-api-w-go/pkg/repository"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/rs/zerolog/log"
 	"golang.org/x/crypto/bcrypt"
 )
 
+// RegistrationSubscriber kayıt tamamlandıktan sonra yayınlanan user.created event'ini işler
+type RegistrationSubscriber interface {
+	HandleUserCreated(ctx context.Context, event *domain.UserCreatedEvent) error
+}
+
 type AuthService struct {
 	userRepo      *repository.UserRepository
 	jwtSecret     []byte
 	refreshSecret []byte
+	subscribers   []RegistrationSubscriber
 }
 
 func NewAuthService(userRepo *repository.UserRepository, jwtSecret, refreshSecret string) *AuthService {
@@ -25,24 +32,51 @@ func NewAuthService(userRepo *repository.UserRepository, jwtSecret, refreshSecre
 	}
 }
 
-func (s *AuthService) Register(user *domain.User) error {
+func (s *AuthService) Register(ctx context.Context, user *domain.User) error {
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
 	if err != nil {
 		return err
 	}
 	user.Password = string(hashedPassword)
 
-	return s.userRepo.Create(user)
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		return err
+	}
+	s.publishUserCreated(ctx, user)
+	return nil
+}
+
+// Subscribe kayıt sonrası çalışacak bir abone ekler
+func (s *AuthService) Subscribe(subscriber RegistrationSubscriber) {
+	s.subscribers = append(s.subscribers, subscriber)
 }
 
-func (s *AuthService) Login(email, password string) (*domain.TokenResponse, error) {
-	user, err := s.userRepo.GetByEmail(email)
+// publishUserCreated event'i abonelere sırayla iletir. Kullanıcı zaten kaydedildiği için abone
+// hataları kaydı başarısız kılmaz, yalnızca loglanır.
+func (s *AuthService) publishUserCreated(ctx context.Context, user *domain.User) {
+	if len(s.subscribers) == 0 {
+		return
+	}
+
+	event := domain.NewUserCreatedEvent(user)
+	for _, subscriber := range s.subscribers {
+		if err := subscriber.HandleUserCreated(ctx, event); err != nil {
+			log.Error().Err(err).
+				Str("user_id", user.ID.String()).
+				Msg("Registration subscriber failed")
+		}
+	}
+}
+
+func (s *AuthService) Login(ctx context.Context, email, password string) (*domain.TokenResponse, error) {
+	// Kullanıcı yok ve şifre yanlış durumları aynı hatayı döner; e-posta varlığı sızdırılmaz
+	user, err := s.userRepo.GetByEmail(ctx, email)
 	if err != nil {
-		return nil, errors.New("kullanıcı bulunamadı")
+		return nil, domain.ErrInvalidCredentials
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
-		return nil, errors.New("geçersiz şifre")
+		return nil, domain.ErrInvalidCredentials
 	}
 
 	accessToken, err := s.generateAccessToken(user)
@@ -63,28 +97,28 @@ func (s *AuthService) Login(email, password string) (*domain.TokenResponse, erro
 	}, nil
 }
 
-func (s *AuthService) RefreshToken(refreshToken string) (*domain.TokenResponse, error) {
+func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string) (*domain.TokenResponse, error) {
 	token, err := jwt.Parse(refreshToken, func(token *jwt.Token) (interface{}, error) {
 		return s.refreshSecret, nil
 	})
 
 	if err != nil || !token.Valid {
-		return nil, errors.New("geçersiz refresh token")
+		return nil, domain.ErrInvalidToken
 	}
 
 	claims, ok := token.Claims.(jwt.MapClaims)
 	if !ok {
-		return nil, errors.New("geçersiz token claims")
+		return nil, domain.ErrInvalidTokenClaims
 	}
 
 	userID, ok := claims["user_id"].(string)
 	if !ok {
-		return nil, errors.New("geçersiz user_id claim")
+		return nil, domain.ErrInvalidTokenClaims
 	}
 
-	user, err := s.userRepo.GetByID(userID)
+	user, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
-		return nil, errors.New("kullanıcı bulunamadı")
+		return nil, err
 	}
 
 	accessToken, err := s.generateAccessToken(user)
@@ -109,6 +143,7 @@ func (s *AuthService) generateAccessToken(user *domain.User) (string, error) {
 	claims := jwt.MapClaims{
 		"user_id": user.ID.String(),
 		"email":   user.Email,
+		"role":    string(user.Role),
 		"exp":     time.Now().Add(time.Hour).Unix(),
 	}
 
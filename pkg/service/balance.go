@@ -1,6 +1,9 @@
 package service
 
 import (
+	"context"
+	"math"
+	"sync"
 	"time"
 
 	"transaction-api-w-go/pkg/domain"
@@ -11,59 +14,353 @@ import (
 )
 
 type BalanceService struct {
-	balanceRepo *repository.BalanceRepository
+	balanceRepo     *repository.BalanceRepository
+	holdRepo        *repository.BalanceHoldRepository
+	transactionRepo *repository.TransactionRepository
+	// txManager blokaj adımlarını kilitli bakiyeyle aynı veritabanı transaction'ında çalıştırır
+	txManager *repository.TxManager
+	limitRepo domain.TransactionLimitRepository
+	alerts    *BalanceAlertService
+	receipts  *ReceiptService
+
+	summaryMu    sync.Mutex
+	summaryCache map[string]cachedSummary
+
+	// defaultCurrency bakiyesi olmayan kullanıcıya ilk blokajda açılan bakiyenin para birimi
+	defaultCurrency string
 }
 
-func NewBalanceService(balanceRepo *repository.BalanceRepository) *BalanceService {
+// BalanceSummaryCacheTTL özet yanıtının kısa süreli önbellekte tutulma süresi
+const BalanceSummaryCacheTTL = 15 * time.Second
+
+type cachedSummary struct {
+	summary   *domain.BalanceSummary
+	expiresAt time.Time
+}
+
+func NewBalanceService(
+	balanceRepo *repository.BalanceRepository,
+	holdRepo *repository.BalanceHoldRepository,
+	transactionRepo *repository.TransactionRepository,
+	txManager *repository.TxManager,
+) *BalanceService {
 	return &BalanceService{
-		balanceRepo: balanceRepo,
+		balanceRepo:     balanceRepo,
+		holdRepo:        holdRepo,
+		transactionRepo: transactionRepo,
+		txManager:       txManager,
+		summaryCache:    make(map[string]cachedSummary),
+		defaultCurrency: string(domain.CurrencyTRY),
 	}
 }
 
-func (s *BalanceService) GetCurrentBalance(userID string) (*domain.Balance, error) {
+// SetDefaultCurrency ilk blokajda açılan bakiyelerin para birimini ayarlar
+func (s *BalanceService) SetDefaultCurrency(currency string) {
+	if currency != "" {
+		s.defaultCurrency = currency
+	}
+}
+
+// SetLimitRepository özet yanıtına limit kullanımını eklemek için limit repository'sini bağlar
+func (s *BalanceService) SetLimitRepository(limitRepo domain.TransactionLimitRepository) {
+	s.limitRepo = limitRepo
+}
+
+func (s *BalanceService) GetCurrentBalance(ctx context.Context, userID string) (*domain.Balance, error) {
 	start := time.Now()
 	defer func() {
 		duration := time.Since(start).Seconds()
 		metrics.DatabaseQueryDuration.WithLabelValues("get_current_balance").Observe(duration)
 	}()
 
-	balance, err := s.balanceRepo.GetByUserID(userID)
+	balance, err := s.balanceRepo.GetByUserIDAndCurrency(ctx, userID, s.defaultCurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	available, err := availableAmount(ctx, s.holdRepo, s.transactionRepo, balance)
 	if err != nil {
 		return nil, err
 	}
+	balance.Ledger = balance.Amount
+	balance.Available = available
 
 	metrics.BalanceTotal.WithLabelValues(userID).Set(balance.Amount)
 	return balance, nil
 }
 
-func (s *BalanceService) GetHistoricalBalance(userID string) ([]domain.BalanceHistory, error) {
+// SetBalanceAlerts blokaj capture'ından sonra eşik kurallarını değerlendirecek servisi bağlar
+func (s *BalanceService) SetBalanceAlerts(alerts *BalanceAlertService) {
+	s.alerts = alerts
+}
+
+// SetReceipts blokaj capture edildiğinde oluşan işlem için makbuz üretecek servisi bağlar
+func (s *BalanceService) SetReceipts(receipts *ReceiptService) {
+	s.receipts = receipts
+}
+
+// GetSummary güncel bakiyeyi, bugünkü alacak/borç toplamlarını, bekleyen işlem sayısını ve
+// limit kullanımını tek bir özette birleştirir. Sonuç BalanceSummaryCacheTTL boyunca önbellekte tutulur.
+func (s *BalanceService) GetSummary(ctx context.Context, userID string) (*domain.BalanceSummary, error) {
+	s.summaryMu.Lock()
+	cached, ok := s.summaryCache[userID]
+	s.summaryMu.Unlock()
+	if ok && time.Now().Before(cached.expiresAt) {
+		metrics.CacheHits.Inc()
+		return cached.summary, nil
+	}
+	metrics.CacheMisses.Inc()
+
+	start := time.Now()
+	defer func() {
+		duration := time.Since(start).Seconds()
+		metrics.DatabaseQueryDuration.WithLabelValues("get_balance_summary").Observe(duration)
+	}()
+
+	balance, err := s.GetCurrentBalance(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	credits, debits, err := s.transactionRepo.SumCompletedSince(ctx, userID, dayStart)
+	if err != nil {
+		return nil, err
+	}
+
+	pending, err := s.transactionRepo.CountPending(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &domain.BalanceSummary{
+		UserID:       balance.UserID,
+		Currency:     balance.Currency,
+		Ledger:       balance.Ledger,
+		Available:    balance.Available,
+		TodayCredits: credits,
+		TodayDebits:  debits,
+		PendingCount: pending,
+		LimitUsage:   s.limitUsage(ctx, balance),
+		GeneratedAt:  now,
+	}
+
+	s.summaryMu.Lock()
+	s.summaryCache[userID] = cachedSummary{summary: summary, expiresAt: now.Add(BalanceSummaryCacheTTL)}
+	s.summaryMu.Unlock()
+
+	return summary, nil
+}
+
+// limitUsage kullanıcının bakiye para birimindeki limitini okur; limit tanımlı değilse nil döner
+func (s *BalanceService) limitUsage(ctx context.Context, balance *domain.Balance) *domain.LimitUsage {
+	if s.limitRepo == nil {
+		return nil
+	}
+
+	limit, err := s.limitRepo.GetByUserIDAndCurrency(ctx, balance.UserID, domain.Currency(balance.Currency))
+	if err != nil || !limit.IsActive {
+		return nil
+	}
+
+	remaining := limit.DailyLimit - limit.DailyAmount
+	if remaining < 0 {
+		remaining = 0
+	}
+	return &domain.LimitUsage{
+		DailyLimit:     limit.DailyLimit,
+		DailyUsed:      limit.DailyAmount,
+		DailyRemaining: remaining,
+		MonthlyLimit:   limit.MonthlyLimit,
+		MonthlyUsed:    limit.MonthlyAmount,
+	}
+}
+
+func (s *BalanceService) GetHistoricalBalance(ctx context.Context, userID string) ([]domain.BalanceHistory, error) {
 	start := time.Now()
 	defer func() {
 		duration := time.Since(start).Seconds()
 		metrics.DatabaseQueryDuration.WithLabelValues("get_historical_balance").Observe(duration)
 	}()
 
-	return s.balanceRepo.GetHistory(userID)
+	return s.balanceRepo.GetHistory(ctx, userID)
 }
 
-func (s *BalanceService) GetBalanceAtTime(userID string, timestamp time.Time) (*domain.BalanceHistory, error) {
+func (s *BalanceService) GetBalanceAtTime(ctx context.Context, userID string, timestamp time.Time) (*domain.BalanceHistory, error) {
 	start := time.Now()
 	defer func() {
 		duration := time.Since(start).Seconds()
 		metrics.DatabaseQueryDuration.WithLabelValues("get_balance_at_time").Observe(duration)
 	}()
 
-	return s.balanceRepo.GetBalanceAtTime(userID, timestamp)
+	return s.balanceRepo.GetBalanceAtTime(ctx, userID, timestamp)
 }
 
-func (s *BalanceService) CreateInitialBalance(userID string) error {
+func (s *BalanceService) CreateInitialBalance(ctx context.Context, userID string) error {
 	balance := &domain.Balance{
 		ID:        uuid.New(),
 		UserID:    uuid.MustParse(userID),
 		Amount:    0,
+		Currency:  s.defaultCurrency,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
 
-	return s.balanceRepo.Create(balance)
+	return s.balanceRepo.Create(ctx, balance)
+}
+
+// SetMinimumBalance kullanıcının minimum bakiyesini ayarlar; negatif değer bu tutara kadar
+// overdraft'a izin verir. Mevcut bakiye yeni sınırın altındaysa yalnızca yeni borçlar engellenir.
+func (s *BalanceService) SetMinimumBalance(ctx context.Context, userID string, minimum float64) (*domain.Balance, error) {
+	if math.IsNaN(minimum) || math.IsInf(minimum, 0) {
+		return nil, domain.ErrInvalidAmount
+	}
+	if err := s.balanceRepo.SetMinimumBalance(ctx, userID, minimum); err != nil {
+		return nil, err
+	}
+	return s.balanceRepo.GetByUserIDAndCurrency(ctx, userID, s.defaultCurrency)
+}
+
+// AuthorizeHold kullanılabilir bakiyeden blokaj düşer; gerçek bakiye capture edilene kadar değişmez.
+// Kullanılabilir bakiye kilitli bakiye satırı üzerinden hesaplandığından eşzamanlı blokaj ve borçlar
+// bakiyeyi aşamaz.
+func (s *BalanceService) AuthorizeHold(ctx context.Context, userID string, req domain.BalanceHoldRequest) (*domain.BalanceHold, error) {
+	// Bakiyesi olmayan kullanıcı bulunamadı yerine yetersiz bakiye hatası alır
+	balance, err := s.balanceRepo.GetOrCreate(ctx, userID, s.defaultCurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	hold, err := domain.NewBalanceHold(balance.UserID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		if err := lockBalances(ctx, s.balanceRepo, balance); err != nil {
+			return err
+		}
+		available, err := availableAmount(ctx, s.holdRepo, s.transactionRepo, balance)
+		if err != nil {
+			return err
+		}
+		if err := balance.CheckWithdrawal(available, req.Amount); err != nil {
+			return err
+		}
+		return s.holdRepo.Create(ctx, hold)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return hold, nil
+}
+
+// CaptureHold aktif blokajı borç işlemine dönüştürür
+func (s *BalanceService) CaptureHold(ctx context.Context, userID, holdID string) (*domain.Transaction, error) {
+	balance, err := s.balanceRepo.GetByUserIDAndCurrency(ctx, userID, s.defaultCurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	var transaction *domain.Transaction
+	err = s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		// Blokaj bakiye kilitlendikten sonra okunur; eşzamanlı capture/release aynı blokajı iki kez işleyemez
+		if err := lockBalances(ctx, s.balanceRepo, balance); err != nil {
+			return err
+		}
+		hold, err := s.getUserHold(ctx, userID, holdID)
+		if err != nil {
+			return err
+		}
+		if !hold.IsActive() {
+			return domain.ErrHoldNotActive
+		}
+		if hold.IsReservedForPayment() {
+			return domain.ErrHoldReservedForPayment
+		}
+
+		if err := balance.Subtract(hold.Amount); err != nil {
+			return err
+		}
+
+		transaction = &domain.Transaction{
+			ID:           uuid.New(),
+			UserID:       hold.UserID,
+			Type:         domain.TransactionTypeDebit,
+			Amount:       hold.Amount,
+			Description:  hold.Description,
+			ReferenceID:  hold.ReferenceID,
+			BalanceAfter: balance.GetAmount(),
+			Status:       string(domain.TransactionStateCompleted),
+			CreatedAt:    time.Now(),
+			UpdatedAt:    time.Now(),
+		}
+
+		if err := s.transactionRepo.Create(ctx, transaction); err != nil {
+			return err
+		}
+		if err := s.balanceRepo.Update(ctx, balance); err != nil {
+			return err
+		}
+
+		if err := hold.Capture(transaction.ID); err != nil {
+			return err
+		}
+		return s.holdRepo.Update(ctx, hold)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if s.alerts != nil {
+		s.alerts.Evaluate(ctx, balance)
+	}
+	attachReceipt(ctx, s.receipts, transaction)
+
+	return transaction, nil
+}
+
+// ReleaseHold aktif blokajı serbest bırakır
+func (s *BalanceService) ReleaseHold(ctx context.Context, userID, holdID string) (*domain.BalanceHold, error) {
+	balance, err := s.balanceRepo.GetByUserIDAndCurrency(ctx, userID, s.defaultCurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	var hold *domain.BalanceHold
+	err = s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		if err := lockBalances(ctx, s.balanceRepo, balance); err != nil {
+			return err
+		}
+		if hold, err = s.getUserHold(ctx, userID, holdID); err != nil {
+			return err
+		}
+		if hold.IsReservedForPayment() {
+			return domain.ErrHoldReservedForPayment
+		}
+
+		if err := hold.Release(); err != nil {
+			return err
+		}
+		return s.holdRepo.Update(ctx, hold)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return hold, nil
+}
+
+func (s *BalanceService) GetActiveHolds(ctx context.Context, userID string) ([]*domain.BalanceHold, error) {
+	return s.holdRepo.GetActiveByUserID(ctx, userID)
+}
+
+func (s *BalanceService) getUserHold(ctx context.Context, userID, holdID string) (*domain.BalanceHold, error) {
+	hold, err := s.holdRepo.GetByID(ctx, holdID)
+	if err != nil {
+		return nil, err
+	}
+	if hold.UserID.String() != userID {
+		return nil, domain.ErrHoldNotFound
+	}
+	return hold, nil
 }
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+
+	"transaction-api-w-go/pkg/domain"
+	"transaction-api-w-go/pkg/repository"
+	"transaction-api-w-go/pkg/webhook"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// balanceAlertEvent webhook isteklerinde X-Webhook-Event başlığına yazılan olay adı
+const balanceAlertEvent = "balance.threshold_crossed"
+
+type BalanceAlertService struct {
+	repo       *repository.BalanceAlertRepository
+	dispatcher *webhook.Dispatcher
+}
+
+func NewBalanceAlertService(repo *repository.BalanceAlertRepository, dispatcher *webhook.Dispatcher) *BalanceAlertService {
+	return &BalanceAlertService{
+		repo:       repo,
+		dispatcher: dispatcher,
+	}
+}
+
+func (s *BalanceAlertService) CreateRule(ctx context.Context, userID string, req domain.BalanceAlertRuleRequest) (*domain.BalanceAlertRule, error) {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	rule, err := domain.NewBalanceAlertRule(uid, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(ctx, rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+func (s *BalanceAlertService) ListRules(ctx context.Context, userID string) ([]*domain.BalanceAlertRule, error) {
+	return s.repo.ListByUserID(ctx, userID)
+}
+
+func (s *BalanceAlertService) GetRule(ctx context.Context, userID, ruleID string) (*domain.BalanceAlertRule, error) {
+	rule, err := s.repo.GetByID(ctx, ruleID)
+	if err != nil {
+		return nil, err
+	}
+	if rule.UserID.String() != userID {
+		return nil, domain.ErrAlertRuleNotFound
+	}
+	return rule, nil
+}
+
+func (s *BalanceAlertService) UpdateRule(ctx context.Context, userID, ruleID string, req domain.BalanceAlertRuleRequest) (*domain.BalanceAlertRule, error) {
+	rule, err := s.GetRule(ctx, userID, ruleID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := rule.Update(req); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(ctx, rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+func (s *BalanceAlertService) DeleteRule(ctx context.Context, userID, ruleID string) error {
+	if _, err := s.GetRule(ctx, userID, ruleID); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, ruleID)
+}
+
+// Evaluate bakiye değişikliğinden sonra kullanıcının aktif kurallarını değerlendirir ve eşiği
+// yeni aşılan kurallar için webhook gönderir. Hatalar loglanır; bakiye işlemini başarısız kılmaz.
+func (s *BalanceAlertService) Evaluate(ctx context.Context, balance *domain.Balance) {
+	userID := balance.UserID.String()
+	amount := balance.GetAmount()
+
+	rules, err := s.repo.ListActiveByUserID(ctx, userID)
+	if err != nil {
+		log.Error().Err(err).Str("user_id", userID).Msg("Failed to load balance alert rules")
+		return
+	}
+
+	for _, rule := range rules {
+		fired, changed := rule.Evaluate(amount)
+		if !changed {
+			continue
+		}
+
+		if err := s.repo.Update(ctx, rule); err != nil {
+			log.Error().Err(err).Str("rule_id", rule.ID.String()).Msg("Failed to update balance alert rule")
+			continue
+		}
+
+		if fired {
+			go s.notify(rule.WebhookURL, rule.Notification(amount))
+		}
+	}
+}
+
+// notify bildirimi kalıcı bir teslimat olarak kuyruğa alır; başarısız gönderimler dispatcher
+// tarafından yeniden denenir
+func (s *BalanceAlertService) notify(url string, notification domain.BalanceAlertNotification) {
+	if s.dispatcher == nil {
+		return
+	}
+
+	// İstek bağlamı yanıt dönünce iptal edileceği için bildirim bağımsız bir context ile gönderilir
+	if _, err := s.dispatcher.Enqueue(context.Background(), url, balanceAlertEvent, notification); err != nil {
+		log.Error().Err(err).
+			Str("rule_id", notification.RuleID.String()).
+			Msg("Failed to enqueue balance alert webhook")
+	}
+}
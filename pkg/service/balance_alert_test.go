@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"transaction-api-w-go/pkg/domain"
+	"transaction-api-w-go/pkg/repository"
+	"transaction-api-w-go/pkg/webhook"
+
+	"github.com/google/uuid"
+)
+
+// newTestAlertService gelen bildirimleri kanala yazan bir webhook alıcısıyla alarm servisi kurar
+func newTestAlertService(t *testing.T, env *testEnv) (*BalanceAlertService, string, <-chan domain.BalanceAlertNotification) {
+	t.Helper()
+	received := make(chan domain.BalanceAlertNotification, 16)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var notification domain.BalanceAlertNotification
+		if err := json.NewDecoder(r.Body).Decode(&notification); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		received <- notification
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(receiver.Close)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	dispatcher := webhook.NewDispatcher(ctx, repository.NewWebhookDeliveryRepository(env.db), webhook.NewNotifier(time.Second), 1)
+	return NewBalanceAlertService(repository.NewBalanceAlertRepository(env.db), dispatcher), receiver.URL, received
+}
+
+func TestBalanceAlertServiceEvaluate(t *testing.T) {
+	tests := []struct {
+		name      string
+		direction domain.BalanceAlertDirection
+		inactive  bool
+		balances  []float64
+		// fires her bakiye değişikliğinde bildirim beklenip beklenmediğidir
+		fires []bool
+	}{
+		{
+			name:      "aşağı yönde eşik geçilir",
+			direction: domain.BalanceAlertBelow,
+			balances:  []float64{150, 90, 80, 105, 112, 95},
+			fires:     []bool{false, true, false, false, false, true},
+		},
+		{
+			name:      "yukarı yönde eşik geçilir",
+			direction: domain.BalanceAlertAbove,
+			balances:  []float64{50, 120, 130, 95, 89, 101},
+			fires:     []bool{false, true, false, false, false, true},
+		},
+		{
+			name:      "pasif kural tetiklenmez",
+			direction: domain.BalanceAlertBelow,
+			inactive:  true,
+			balances:  []float64{150, 90},
+			fires:     []bool{false, false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			svc, receiverURL, received := newTestAlertService(t, env)
+			ctx := context.Background()
+			userID := env.createUser(t, 0)
+
+			active := !tt.inactive
+			rule, err := svc.CreateRule(ctx, userID, domain.BalanceAlertRuleRequest{
+				Direction:  tt.direction,
+				Threshold:  100,
+				Hysteresis: 10,
+				WebhookURL: receiverURL,
+				IsActive:   &active,
+			})
+			if err != nil {
+				t.Fatalf("CreateRule: %v", err)
+			}
+
+			for i, amount := range tt.balances {
+				svc.Evaluate(ctx, &domain.Balance{UserID: uuid.MustParse(userID), Amount: amount})
+
+				if !tt.fires[i] {
+					continue
+				}
+				select {
+				case notification := <-received:
+					if notification.RuleID != rule.ID || notification.Direction != tt.direction || notification.Balance != amount {
+						t.Errorf("bildirim = %+v, beklenen %s kuralı için %v bakiye", notification, rule.ID, amount)
+					}
+				case <-time.After(5 * time.Second):
+					t.Fatalf("%v bakiyesinde bildirim gelmedi", amount)
+				}
+			}
+
+			select {
+			case notification := <-received:
+				t.Errorf("beklenmeyen bildirim: %+v", notification)
+			case <-time.After(100 * time.Millisecond):
+			}
+		})
+	}
+}
+
+func TestBalanceAlertServiceRuleCRUD(t *testing.T) {
+	env := newTestEnv(t)
+	svc, receiverURL, _ := newTestAlertService(t, env)
+	ctx := context.Background()
+	userID := env.createUser(t, 0)
+	otherUserID := env.createUser(t, 0)
+
+	rule, err := svc.CreateRule(ctx, userID, domain.BalanceAlertRuleRequest{Direction: domain.BalanceAlertBelow, Threshold: 100, WebhookURL: receiverURL})
+	if err != nil {
+		t.Fatalf("CreateRule: %v", err)
+	}
+	// Tetiklenmiş kural güncellenince yeniden kurulur
+	svc.Evaluate(ctx, &domain.Balance{UserID: uuid.MustParse(userID), Amount: 50})
+
+	tests := []struct {
+		name    string
+		run     func() error
+		wantErr error
+	}{
+		{
+			name: "geçersiz kural reddedilir",
+			run: func() error {
+				_, err := svc.CreateRule(ctx, userID, domain.BalanceAlertRuleRequest{Direction: "sideways", WebhookURL: receiverURL})
+				return err
+			},
+			wantErr: domain.ErrInvalidAlertRule,
+		},
+		{
+			name: "kurallar listelenir",
+			run: func() error {
+				rules, err := svc.ListRules(ctx, userID)
+				if err == nil && (len(rules) != 1 || rules[0].ID != rule.ID) {
+					t.Errorf("kurallar = %v, beklenen yalnızca %s", rules, rule.ID)
+				}
+				return err
+			},
+		},
+		{
+			name: "başka kullanıcının kuralı görünmez",
+			run: func() error {
+				_, err := svc.GetRule(ctx, otherUserID, rule.ID.String())
+				return err
+			},
+			wantErr: domain.ErrAlertRuleNotFound,
+		},
+		{
+			name: "güncelleme eşiği değiştirir ve tetiklenmeyi sıfırlar",
+			run: func() error {
+				if _, err := svc.UpdateRule(ctx, userID, rule.ID.String(), domain.BalanceAlertRuleRequest{Direction: domain.BalanceAlertAbove, Threshold: 500, WebhookURL: receiverURL}); err != nil {
+					return err
+				}
+				updated, err := svc.GetRule(ctx, userID, rule.ID.String())
+				if err == nil && (updated.Direction != domain.BalanceAlertAbove || updated.Threshold != 500 || updated.Triggered) {
+					t.Errorf("güncellenen kural = %+v", updated)
+				}
+				return err
+			},
+		},
+		{
+			name: "başka kullanıcı silemez",
+			run: func() error {
+				return svc.DeleteRule(ctx, otherUserID, rule.ID.String())
+			},
+			wantErr: domain.ErrAlertRuleNotFound,
+		},
+		{
+			name: "sahibi siler",
+			run: func() error {
+				if err := svc.DeleteRule(ctx, userID, rule.ID.String()); err != nil {
+					return err
+				}
+				_, err := svc.GetRule(ctx, userID, rule.ID.String())
+				return err
+			},
+			wantErr: domain.ErrAlertRuleNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.run(); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("hata = %v, beklenen %v", err, tt.wantErr)
+			}
+		})
+	}
+}
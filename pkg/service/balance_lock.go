@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"sort"
+
+	"transaction-api-w-go/pkg/domain"
+	"transaction-api-w-go/pkg/repository"
+
+	"github.com/google/uuid"
+)
+
+// availableAmount kayıtlı bakiyeden aktif blokajları ve bekleyen borçları düşer. Sonuç bir borcu veya
+// blokajı onaylamak için kullanılıyorsa bakiye önce lockBalances ile kilitlenmiş olmalıdır; aksi halde
+// eşzamanlı bir borç kontrol ile yazım arasına girebilir.
+func availableAmount(ctx context.Context, holdRepo *repository.BalanceHoldRepository, transactionRepo *repository.TransactionRepository, balance *domain.Balance) (float64, error) {
+	userID := balance.UserID.String()
+
+	held, err := holdRepo.SumActiveByUserID(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	pending, err := transactionRepo.SumPendingDebits(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	return balance.Amount - held - pending, nil
+}
+
+// lockBalances bakiyeleri satır kilidiyle yeniden okur ve kayıtlı tutarları verilen bakiyelere yazar.
+// TxManager.WithTx içinde çağrılmalıdır; kilitler transaction bitene kadar tutulur. Eşzamanlı iki
+// transferin birbirini beklememesi için kilitler her zaman bakiye id sırasıyla alınır. nil bakiyeler
+// atlanır, aynı bakiye birden fazla verilirse bir kez kilitlenir.
+func lockBalances(ctx context.Context, balanceRepo *repository.BalanceRepository, balances ...*domain.Balance) error {
+	byID := make(map[uuid.UUID][]*domain.Balance, len(balances))
+	ids := make([]uuid.UUID, 0, len(balances))
+	for _, balance := range balances {
+		if balance == nil {
+			continue
+		}
+		if _, ok := byID[balance.ID]; !ok {
+			ids = append(ids, balance.ID)
+		}
+		byID[balance.ID] = append(byID[balance.ID], balance)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i].String() < ids[j].String() })
+
+	for _, id := range ids {
+		locked, err := balanceRepo.GetForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		for _, balance := range byID[id] {
+			balance.Amount = locked.Amount
+			balance.MinimumBalance = locked.MinimumBalance
+			balance.UpdatedAt = locked.UpdatedAt
+		}
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"transaction-api-w-go/pkg/domain"
+)
+
+func TestLockBalancesRefreshesAmounts(t *testing.T) {
+	tests := []struct {
+		name      string
+		duplicate bool
+		withNil   bool
+	}{
+		{name: "tek bakiye"},
+		{name: "aynı bakiye iki kez", duplicate: true},
+		{name: "nil bakiye atlanır", withNil: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			ctx := context.Background()
+			userID := env.createUser(t, 100)
+			otherID := env.createUser(t, 50)
+
+			stale, err := env.balanceRepo.GetByUserIDAndCurrency(ctx, userID, string(domain.CurrencyTRY))
+			if err != nil {
+				t.Fatalf("bakiye okunamadı: %v", err)
+			}
+			other, err := env.balanceRepo.GetByUserIDAndCurrency(ctx, otherID, string(domain.CurrencyTRY))
+			if err != nil {
+				t.Fatalf("bakiye okunamadı: %v", err)
+			}
+			// Kilitten önce okunan kopya, başka bir işlemin yazdığı tutarı görmez
+			if err := env.db.Model(&domain.Balance{}).Where("id = ?", stale.ID).Update("amount", 70).Error; err != nil {
+				t.Fatalf("bakiye güncellenemedi: %v", err)
+			}
+
+			balances := []*domain.Balance{stale, other}
+			var duplicate *domain.Balance
+			if tt.duplicate {
+				duplicate, err = env.balanceRepo.GetByUserIDAndCurrency(ctx, userID, string(domain.CurrencyTRY))
+				if err != nil {
+					t.Fatalf("bakiye okunamadı: %v", err)
+				}
+				balances = append(balances, duplicate)
+			}
+			if tt.withNil {
+				balances = append(balances, nil)
+			}
+
+			err = env.txManager.WithTx(ctx, func(ctx context.Context) error {
+				return lockBalances(ctx, env.balanceRepo, balances...)
+			})
+			if err != nil {
+				t.Fatalf("lockBalances: %v", err)
+			}
+			if stale.Amount != 70 {
+				t.Errorf("kilit sonrası tutar = %v, beklenen 70", stale.Amount)
+			}
+			if duplicate != nil && duplicate.Amount != 70 {
+				t.Errorf("ikinci kopyanın tutarı = %v, beklenen 70", duplicate.Amount)
+			}
+			if other.Amount != 50 {
+				t.Errorf("diğer bakiye = %v, beklenen 50", other.Amount)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"transaction-api-w-go/pkg/domain"
+	"transaction-api-w-go/pkg/repository"
+)
+
+// BalanceProvisioner kayıt olan kullanıcıya varsayılan bakiyesini ve yapılandırılmış ek para
+// birimlerindeki bakiyelerini açan RegistrationSubscriber'dır. Var olan bakiyeler yeniden
+// oluşturulmaz; event tekrar işlense de sonuç aynıdır.
+type BalanceProvisioner struct {
+	balanceRepo       *repository.BalanceRepository
+	multiCurrencyRepo domain.MultiCurrencyBalanceRepository
+	defaultCurrency   domain.Currency
+	currencies        []domain.Currency
+}
+
+// NewBalanceProvisioner desteklenmeyen bir para birimi verilirse hata döner. multiCurrencyRepo nil
+// ise yalnızca varsayılan bakiye açılır.
+func NewBalanceProvisioner(balanceRepo *repository.BalanceRepository, multiCurrencyRepo domain.MultiCurrencyBalanceRepository, defaultCurrency string, currencies []string) (*BalanceProvisioner, error) {
+	provisioner := &BalanceProvisioner{
+		balanceRepo:       balanceRepo,
+		multiCurrencyRepo: multiCurrencyRepo,
+		defaultCurrency:   domain.Currency(defaultCurrency),
+	}
+	if !provisioner.defaultCurrency.IsSupported() {
+		return nil, fmt.Errorf("%w: %s", domain.ErrCurrencyNotSupported, defaultCurrency)
+	}
+
+	seen := make(map[domain.Currency]bool, len(currencies))
+	for _, code := range currencies {
+		currency := domain.Currency(code)
+		if !currency.IsSupported() {
+			return nil, fmt.Errorf("%w: %s", domain.ErrCurrencyNotSupported, code)
+		}
+		if seen[currency] {
+			continue
+		}
+		seen[currency] = true
+		provisioner.currencies = append(provisioner.currencies, currency)
+	}
+	return provisioner, nil
+}
+
+func (p *BalanceProvisioner) HandleUserCreated(ctx context.Context, event *domain.UserCreatedEvent) error {
+	if err := p.createDefaultBalance(ctx, event); err != nil {
+		return err
+	}
+	return p.createCurrencyBalances(ctx, event)
+}
+
+func (p *BalanceProvisioner) createDefaultBalance(ctx context.Context, event *domain.UserCreatedEvent) error {
+	_, err := p.balanceRepo.GetOrCreate(ctx, event.UserID.String(), string(p.defaultCurrency))
+	return err
+}
+
+// createCurrencyBalances eksik para birimi bakiyelerini tek seferde ekler
+func (p *BalanceProvisioner) createCurrencyBalances(ctx context.Context, event *domain.UserCreatedEvent) error {
+	if p.multiCurrencyRepo == nil || len(p.currencies) == 0 {
+		return nil
+	}
+
+	existing, err := p.multiCurrencyRepo.GetByUserID(ctx, event.UserID)
+	if err != nil {
+		return err
+	}
+	opened := make(map[domain.Currency]bool, len(existing))
+	for _, balance := range existing {
+		opened[balance.Currency] = true
+	}
+
+	var balances []*domain.MultiCurrencyBalance
+	for _, currency := range p.currencies {
+		if opened[currency] {
+			continue
+		}
+		balance, err := domain.NewMultiCurrencyBalance(event.UserID, currency, 0)
+		if err != nil {
+			return err
+		}
+		balances = append(balances, balance)
+	}
+	if len(balances) == 0 {
+		return nil
+	}
+	return p.multiCurrencyRepo.CreateMany(ctx, balances)
+}
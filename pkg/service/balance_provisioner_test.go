@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+
+	"transaction-api-w-go/pkg/domain"
+	"transaction-api-w-go/pkg/repository"
+
+	"github.com/google/uuid"
+)
+
+func TestRegisterProvisionsBalances(t *testing.T) {
+	tests := []struct {
+		name           string
+		currencies     []string
+		wantCurrencies []domain.Currency
+	}{
+		{name: "yalnızca varsayılan bakiye açılır"},
+		{
+			name:           "ek para birimleri bir kez açılır",
+			currencies:     []string{"USD", "EUR", "USD"},
+			wantCurrencies: []domain.Currency{domain.CurrencyEUR, domain.CurrencyUSD},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			ctx := context.Background()
+			multiCurrencyRepo := repository.NewMultiCurrencyBalanceRepository(env.db)
+
+			provisioner, err := NewBalanceProvisioner(env.balanceRepo, multiCurrencyRepo, string(domain.CurrencyTRY), tt.currencies)
+			if err != nil {
+				t.Fatalf("NewBalanceProvisioner: %v", err)
+			}
+			auth := NewAuthService(env.userRepo, "secret", "refresh-secret")
+			auth.Subscribe(provisioner)
+
+			user := &domain.User{
+				ID:        uuid.New(),
+				Password:  "password",
+				FirstName: "Test",
+				LastName:  "User",
+				Role:      domain.RoleUser,
+				LimitTier: domain.LimitTierBasic,
+			}
+			user.Email = user.ID.String() + "@example.com"
+			if err := auth.Register(ctx, user); err != nil {
+				t.Fatalf("Register: %v", err)
+			}
+
+			// Event tekrar işlense de bakiyeler çoğalmaz
+			if err := provisioner.HandleUserCreated(ctx, domain.NewUserCreatedEvent(user)); err != nil {
+				t.Fatalf("HandleUserCreated: %v", err)
+			}
+
+			balance, err := env.balanceRepo.GetByUserID(ctx, user.ID.String())
+			if err != nil {
+				t.Fatalf("varsayılan bakiye açılmadı: %v", err)
+			}
+			if balance.Amount != 0 || balance.Currency != string(domain.CurrencyTRY) {
+				t.Errorf("bakiye = %v %s, beklenen 0 TRY", balance.Amount, balance.Currency)
+			}
+			var defaultBalances int64
+			if err := env.db.Model(&domain.Balance{}).Where("user_id = ?", user.ID).Count(&defaultBalances).Error; err != nil {
+				t.Fatalf("bakiyeler sayılamadı: %v", err)
+			}
+			if defaultBalances != 1 {
+				t.Errorf("%d varsayılan bakiye açıldı, beklenen 1", defaultBalances)
+			}
+
+			opened, err := multiCurrencyRepo.GetByUserID(ctx, user.ID)
+			if err != nil {
+				t.Fatalf("para birimi bakiyeleri okunamadı: %v", err)
+			}
+			var got []domain.Currency
+			for _, b := range opened {
+				got = append(got, b.Currency)
+			}
+			sort.Slice(got, func(i, j int) bool { return got[i] < got[j] })
+			if len(got) != len(tt.wantCurrencies) {
+				t.Fatalf("açılan para birimleri = %v, beklenen %v", got, tt.wantCurrencies)
+			}
+			for i := range got {
+				if got[i] != tt.wantCurrencies[i] {
+					t.Errorf("açılan para birimleri = %v, beklenen %v", got, tt.wantCurrencies)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestNewBalanceProvisionerRejectsUnsupportedCurrency(t *testing.T) {
+	tests := []struct {
+		name            string
+		defaultCurrency string
+		currencies      []string
+	}{
+		{name: "desteklenmeyen varsayılan para birimi", defaultCurrency: "XYZ"},
+		{name: "desteklenmeyen ek para birimi", defaultCurrency: "TRY", currencies: []string{"USD", "XYZ"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewBalanceProvisioner(nil, nil, tt.defaultCurrency, tt.currencies)
+			if !errors.Is(err, domain.ErrCurrencyNotSupported) {
+				t.Errorf("NewBalanceProvisioner = %v, beklenen ErrCurrencyNotSupported", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,264 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"transaction-api-w-go/pkg/domain"
+
+	"github.com/google/uuid"
+)
+
+func TestBalanceServiceAvailableVsLedger(t *testing.T) {
+	tests := []struct {
+		name          string
+		hold          float64
+		pendingDebit  float64
+		pendingStatus domain.TransactionState
+		wantAvailable float64
+	}{
+		{name: "bekleyen hareket yok", wantAvailable: 100},
+		{name: "aktif blokaj", hold: 30, wantAvailable: 70},
+		{name: "bekleyen borç", pendingDebit: 20, pendingStatus: domain.TransactionStatePending, wantAvailable: 80},
+		{name: "incelemedeki borç", pendingDebit: 20, pendingStatus: domain.TransactionStateHeld, wantAvailable: 80},
+		{name: "başarısız borç düşülmez", pendingDebit: 20, pendingStatus: domain.TransactionStateFailed, wantAvailable: 100},
+		{name: "blokaj ve bekleyen borç", hold: 30, pendingDebit: 20, pendingStatus: domain.TransactionStatePending, wantAvailable: 50},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			svc := env.balanceService()
+			ctx := context.Background()
+			userID := env.createUser(t, 100)
+
+			if tt.hold > 0 {
+				if _, err := svc.AuthorizeHold(ctx, userID, domain.BalanceHoldRequest{Amount: tt.hold}); err != nil {
+					t.Fatalf("AuthorizeHold: %v", err)
+				}
+			}
+			if tt.pendingDebit > 0 {
+				env.createTransaction(t, userID, domain.TransactionTypeDebit, tt.pendingDebit, tt.pendingStatus)
+			}
+
+			balance, err := svc.GetCurrentBalance(ctx, userID)
+			if err != nil {
+				t.Fatalf("GetCurrentBalance: %v", err)
+			}
+			if balance.Ledger != 100 {
+				t.Errorf("Ledger = %v, beklenen 100; bekleyen hareketler kayıtlı bakiyeyi değiştirmemeli", balance.Ledger)
+			}
+			if balance.Available != tt.wantAvailable {
+				t.Errorf("Available = %v, beklenen %v", balance.Available, tt.wantAvailable)
+			}
+		})
+	}
+}
+
+func TestBalanceServiceAuthorizeHoldChecksAvailable(t *testing.T) {
+	tests := []struct {
+		name    string
+		holds   []float64
+		wantErr []bool
+	}{
+		{name: "kullanılabilir bakiyeye sığan blokajlar", holds: []float64{60, 40}, wantErr: []bool{false, false}},
+		{name: "ikinci blokaj kalan bakiyeyi aşar", holds: []float64{60, 50}, wantErr: []bool{false, true}},
+		{name: "tek blokaj bakiyeyi aşar", holds: []float64{101}, wantErr: []bool{true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			svc := env.balanceService()
+			ctx := context.Background()
+			userID := env.createUser(t, 100)
+
+			for i, amount := range tt.holds {
+				_, err := svc.AuthorizeHold(ctx, userID, domain.BalanceHoldRequest{Amount: amount})
+				if (err != nil) != tt.wantErr[i] {
+					t.Fatalf("AuthorizeHold(%v) = %v, hata bekleniyor: %v", amount, err, tt.wantErr[i])
+				}
+			}
+			if got := env.balanceAmount(t, userID); got != 100 {
+				t.Errorf("bakiye = %v, beklenen 100; blokaj kayıtlı bakiyeyi değiştirmemeli", got)
+			}
+		})
+	}
+}
+
+func TestBalanceServiceHoldLifecycle(t *testing.T) {
+	tests := []struct {
+		name          string
+		capture       bool
+		wantStatus    domain.HoldStatus
+		wantLedger    float64
+		wantAvailable float64
+	}{
+		{name: "blokaj tahsil edilir", capture: true, wantStatus: domain.HoldStatusCaptured, wantLedger: 70, wantAvailable: 70},
+		{name: "blokaj serbest bırakılır", wantStatus: domain.HoldStatusReleased, wantLedger: 100, wantAvailable: 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			svc := env.balanceService()
+			ctx := context.Background()
+			userID := env.createUser(t, 100)
+			otherUser := env.createUser(t, 100)
+
+			hold, err := svc.AuthorizeHold(ctx, userID, domain.BalanceHoldRequest{Amount: 30, ReferenceID: "CARD-1"})
+			if err != nil {
+				t.Fatalf("AuthorizeHold: %v", err)
+			}
+			holdID := hold.ID.String()
+
+			// Başka kullanıcının blokajı işlenemez
+			if _, err := svc.CaptureHold(ctx, otherUser, holdID); !errors.Is(err, domain.ErrHoldNotFound) {
+				t.Errorf("başka kullanıcıyla CaptureHold = %v, beklenen ErrHoldNotFound", err)
+			}
+
+			if tt.capture {
+				transaction, err := svc.CaptureHold(ctx, userID, holdID)
+				if err != nil {
+					t.Fatalf("CaptureHold: %v", err)
+				}
+				if transaction.Type != domain.TransactionTypeDebit || transaction.Amount != 30 || transaction.ReferenceID != "CARD-1" {
+					t.Errorf("işlem = %s %v %q, beklenen 30 tutarında CARD-1 borcu", transaction.Type, transaction.Amount, transaction.ReferenceID)
+				}
+			} else if _, err := svc.ReleaseHold(ctx, userID, holdID); err != nil {
+				t.Fatalf("ReleaseHold: %v", err)
+			}
+
+			// Kapanan blokaj ne tahsil edilebilir ne de serbest bırakılabilir
+			if _, err := svc.CaptureHold(ctx, userID, holdID); !errors.Is(err, domain.ErrHoldNotActive) {
+				t.Errorf("ikinci CaptureHold = %v, beklenen ErrHoldNotActive", err)
+			}
+			if _, err := svc.ReleaseHold(ctx, userID, holdID); !errors.Is(err, domain.ErrHoldNotActive) {
+				t.Errorf("ikinci ReleaseHold = %v, beklenen ErrHoldNotActive", err)
+			}
+
+			stored, err := env.holdRepo.GetByID(ctx, holdID)
+			if err != nil {
+				t.Fatalf("blokaj okunamadı: %v", err)
+			}
+			if stored.Status != tt.wantStatus {
+				t.Errorf("blokaj durumu = %q, beklenen %q", stored.Status, tt.wantStatus)
+			}
+
+			balance, err := svc.GetCurrentBalance(ctx, userID)
+			if err != nil {
+				t.Fatalf("GetCurrentBalance: %v", err)
+			}
+			if balance.Ledger != tt.wantLedger || balance.Available != tt.wantAvailable {
+				t.Errorf("Ledger/Available = %v/%v, beklenen %v/%v", balance.Ledger, balance.Available, tt.wantLedger, tt.wantAvailable)
+			}
+		})
+	}
+}
+
+func TestBalanceServiceGetSummary(t *testing.T) {
+	type activity struct {
+		txType    domain.TransactionType
+		amount    float64
+		status    domain.TransactionState
+		yesterday bool
+	}
+
+	tests := []struct {
+		name          string
+		activity      []activity
+		dailyUsed     float64
+		wantCredits   float64
+		wantDebits    float64
+		wantPending   int64
+		wantAvailable float64
+		wantLimit     bool
+	}{
+		{name: "hareket yokken sıfır", wantAvailable: 100},
+		{
+			name: "bugünkü hareketler yansır",
+			activity: []activity{
+				{txType: domain.TransactionTypeCredit, amount: 50, status: domain.TransactionStateCompleted},
+				{txType: domain.TransactionTypeDebit, amount: 20, status: domain.TransactionStateCompleted},
+				{txType: domain.TransactionTypeDebit, amount: 10, status: domain.TransactionStatePending},
+			},
+			wantCredits:   50,
+			wantDebits:    20,
+			wantPending:   1,
+			wantAvailable: 90,
+		},
+		{
+			name: "dünkü hareket sayılmaz",
+			activity: []activity{
+				{txType: domain.TransactionTypeCredit, amount: 40, status: domain.TransactionStateCompleted, yesterday: true},
+				{txType: domain.TransactionTypeCredit, amount: 5, status: domain.TransactionStateCompleted},
+			},
+			wantCredits:   5,
+			wantAvailable: 100,
+		},
+		{name: "limit kullanımı eklenir", dailyUsed: 300, wantAvailable: 100, wantLimit: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			svc := env.balanceService()
+			ctx := context.Background()
+			userID := env.createUser(t, 100)
+
+			for _, a := range tt.activity {
+				transaction := env.createTransaction(t, userID, a.txType, a.amount, a.status)
+				if a.yesterday {
+					if err := env.db.Model(transaction).Update("created_at", time.Now().AddDate(0, 0, -1)).Error; err != nil {
+						t.Fatalf("işlem tarihi güncellenemedi: %v", err)
+					}
+				}
+			}
+			if tt.wantLimit {
+				_, limits := newTestLimitService(t, uuid.MustParse(userID), 1000)
+				limit, err := limits.GetByUserIDAndCurrency(ctx, uuid.MustParse(userID), domain.CurrencyTRY)
+				if err != nil {
+					t.Fatalf("limit okunamadı: %v", err)
+				}
+				limit.DailyAmount = tt.dailyUsed
+				if err := limits.Update(ctx, limit); err != nil {
+					t.Fatalf("limit güncellenemedi: %v", err)
+				}
+				svc.SetLimitRepository(limits)
+			}
+
+			summary, err := svc.GetSummary(ctx, userID)
+			if err != nil {
+				t.Fatalf("GetSummary: %v", err)
+			}
+			if summary.Ledger != 100 || summary.Available != tt.wantAvailable {
+				t.Errorf("ledger/available = %v/%v, beklenen 100/%v", summary.Ledger, summary.Available, tt.wantAvailable)
+			}
+			if summary.TodayCredits != tt.wantCredits || summary.TodayDebits != tt.wantDebits {
+				t.Errorf("bugünkü alacak/borç = %v/%v, beklenen %v/%v", summary.TodayCredits, summary.TodayDebits, tt.wantCredits, tt.wantDebits)
+			}
+			if summary.PendingCount != tt.wantPending {
+				t.Errorf("bekleyen işlem = %d, beklenen %d", summary.PendingCount, tt.wantPending)
+			}
+			if tt.wantLimit {
+				if summary.LimitUsage == nil || summary.LimitUsage.DailyUsed != tt.dailyUsed || summary.LimitUsage.DailyRemaining != 1000-tt.dailyUsed {
+					t.Errorf("limit kullanımı = %+v, beklenen %v kullanım", summary.LimitUsage, tt.dailyUsed)
+				}
+			} else if summary.LimitUsage != nil {
+				t.Errorf("limit tanımlı değilken kullanım = %+v", summary.LimitUsage)
+			}
+
+			// TTL içinde yeni hareket önbellekteki özeti değiştirmez
+			env.createTransaction(t, userID, domain.TransactionTypeCredit, 7, domain.TransactionStateCompleted)
+			cached, err := svc.GetSummary(ctx, userID)
+			if err != nil {
+				t.Fatalf("GetSummary: %v", err)
+			}
+			if cached.TodayCredits != tt.wantCredits || !cached.GeneratedAt.Equal(summary.GeneratedAt) {
+				t.Errorf("önbellekteki özet değişti: %+v", cached)
+			}
+		})
+	}
+}
@@ -2,10 +2,12 @@ package service
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"transaction-api-w-go/pkg/cache"
 	"transaction-api-w-go/pkg/domain"
+	"transaction-api-w-go/pkg/metrics"
 	"transaction-api-w-go/pkg/repository"
 
 	"github.com/google/uuid"
@@ -13,14 +15,15 @@ import (
 
 // CacheService cache işlemlerini yöneten service
 type CacheService struct {
-	cache           *cache.RedisCache
-	invalidator     *cache.CacheInvalidator
-	warmuper        *cache.CacheWarmuper
-	keyGen          *cache.CacheKeyGenerator
-	userRepo        domain.UserRepository
-	transactionRepo domain.TransactionRepository
-	balanceRepo     domain.BalanceRepository
-	logger          domain.Logger
+	cache            *cache.RedisCache
+	invalidator      *cache.CacheInvalidator
+	batchInvalidator *cache.BatchInvalidator
+	warmuper         *cache.CacheWarmuper
+	keyGen           *cache.CacheKeyGenerator
+	userRepo         domain.UserRepository
+	transactionRepo  domain.TransactionRepository
+	balanceRepo      domain.BalanceRepository
+	logger           domain.Logger
 }
 
 func NewCacheService(
@@ -35,130 +38,85 @@ func NewCacheService(
 	warmuper := cache.NewCacheWarmuper(redisCache, userRepo, transactionRepo, balanceRepo, eventRepo, logger)
 
 	return &CacheService{
-		cache:           redisCache,
-		invalidator:     invalidator,
-		warmuper:        warmuper,
-		keyGen:          cache.NewCacheKeyGenerator(),
-		userRepo:        userRepo,
-		transactionRepo: transactionRepo,
-		balanceRepo:     balanceRepo,
-		logger:          logger,
-	}
-}
-
-func (s *CacheService) GetUser(ctx context.Context, userID uuid.UUID) (*domain.User, error) {
-	key := s.keyGen.UserKey(userID)
-	var user domain.User
-
-	err := s.cache.Get(ctx, key, &user)
+		cache:            redisCache,
+		invalidator:      invalidator,
+		batchInvalidator: cache.NewBatchInvalidator(invalidator, logger),
+		warmuper:         warmuper,
+		keyGen:           cache.NewCacheKeyGenerator(),
+		userRepo:         userRepo,
+		transactionRepo:  transactionRepo,
+		balanceRepo:      balanceRepo,
+		logger:           domain.LoggerOrNop(logger),
+	}
+}
+
+// getOrLoad cache-aside desenini tek yerde uygular: önce cache'e bakar, miss durumunda
+// loader'ı çağırır ve sonucu ttl süresiyle cache'e yazar. Cache hataları loglanır ama
+// isteği başarısız kılmaz.
+func getOrLoad[T any](ctx context.Context, s *CacheService, key string, ttl time.Duration, loader func() (T, error)) (T, error) {
+	var cached T
+	err := s.cache.Get(ctx, key, &cached)
 	if err == nil {
-		s.logger.Debug("User found in cache", "user_id", userID)
-		return &user, nil
+		metrics.CacheHits.Inc()
+		s.logger.Debug("Cache hit", "key", key)
+		return cached, nil
 	}
 
-	if err != domain.ErrCacheMiss {
-		s.logger.Error("Cache error", "error", err)
+	metrics.CacheMisses.Inc()
+	if !errors.Is(err, domain.ErrCacheMiss) {
+		s.logger.Error("Cache error", "key", key, "error", err)
 	}
 
-	userFromDB, err := s.userRepo.GetByID(ctx, uint(userID.ID()))
+	loaded, err := loader()
 	if err != nil {
-		return nil, err
+		var zero T
+		return zero, err
 	}
 
-	if err := s.cache.Set(ctx, key, userFromDB, 30*time.Minute); err != nil {
-		s.logger.Error("Failed to cache user", "error", err)
+	if err := s.cache.Set(ctx, key, loaded, ttl); err != nil {
+		s.logger.Error("Failed to cache value", "key", key, "error", err)
 	}
 
-	return userFromDB, nil
+	return loaded, nil
 }
 
-func (s *CacheService) GetTransaction(ctx context.Context, transactionID uuid.UUID) (*domain.Transaction, error) {
-	key := s.keyGen.TransactionKey(transactionID)
-	var transaction domain.Transaction
-
-	err := s.cache.Get(ctx, key, &transaction)
-	if err == nil {
-		s.logger.Debug("Transaction found in cache", "transaction_id", transactionID)
-		return &transaction, nil
-	}
-
-	if err != domain.ErrCacheMiss {
-		s.logger.Error("Cache error", "error", err)
-	}
-
-	transactionFromDB, err := s.transactionRepo.GetByID(ctx, uint(transactionID.ID()))
-	if err != nil {
-		return nil, err
-	}
-
-	if err := s.cache.Set(ctx, key, transactionFromDB, 30*time.Minute); err != nil {
-		s.logger.Error("Failed to cache transaction", "error", err)
-	}
+func (s *CacheService) GetUser(ctx context.Context, userID uuid.UUID) (*domain.User, error) {
+	return getOrLoad(ctx, s, s.keyGen.UserKey(userID), 30*time.Minute, func() (*domain.User, error) {
+		return s.userRepo.GetByID(ctx, uint(userID.ID()))
+	})
+}
 
-	return transactionFromDB, nil
+func (s *CacheService) GetTransaction(ctx context.Context, transactionID uuid.UUID) (*domain.Transaction, error) {
+	return getOrLoad(ctx, s, s.keyGen.TransactionKey(transactionID), 30*time.Minute, func() (*domain.Transaction, error) {
+		return s.transactionRepo.GetByID(ctx, uint(transactionID.ID()))
+	})
 }
 
 func (s *CacheService) GetBalance(ctx context.Context, userID uuid.UUID) (*domain.Balance, error) {
-	key := s.keyGen.BalanceKey(userID)
-	var balance domain.Balance
-
-	err := s.cache.Get(ctx, key, &balance)
-	if err == nil {
-		s.logger.Debug("Balance found in cache", "user_id", userID)
-		return &balance, nil
-	}
-
-	if err != domain.ErrCacheMiss {
-		s.logger.Error("Cache error", "error", err)
-	}
-
-	balanceFromDB, err := s.balanceRepo.GetByUserID(ctx, uint(userID.ID()))
-	if err != nil {
-		return nil, err
-	}
-
-	if err := s.cache.Set(ctx, key, balanceFromDB, 15*time.Minute); err != nil {
-		s.logger.Error("Failed to cache balance", "error", err)
-	}
-
-	return balanceFromDB, nil
+	return getOrLoad(ctx, s, s.keyGen.BalanceKey(userID), 15*time.Minute, func() (*domain.Balance, error) {
+		return s.balanceRepo.GetByUserID(ctx, uint(userID.ID()))
+	})
 }
 
 func (s *CacheService) GetUserTransactions(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*domain.Transaction, error) {
 	key := s.keyGen.UserTransactionsKey(userID, limit, offset)
-	var transactions []*domain.Transaction
-
-	err := s.cache.Get(ctx, key, &transactions)
-	if err == nil {
-		s.logger.Debug("User transactions found in cache", "user_id", userID)
-		return transactions, nil
-	}
-
-	if err != domain.ErrCacheMiss {
-		s.logger.Error("Cache error", "error", err)
-	}
+	return getOrLoad(ctx, s, key, 10*time.Minute, func() ([]*domain.Transaction, error) {
+		transactionsFromDB, err := s.transactionRepo.GetByUserID(ctx, uint(userID.ID()))
+		if err != nil {
+			return nil, err
+		}
 
-	transactionsFromDB, err := s.transactionRepo.GetByUserID(ctx, uint(userID.ID()))
-	if err != nil {
-		return nil, err
-	}
-
-	start := offset
-	end := start + limit
-	if end > len(transactionsFromDB) {
-		end = len(transactionsFromDB)
-	}
-	if start > len(transactionsFromDB) {
-		start = len(transactionsFromDB)
-	}
-
-	paginatedTransactions := transactionsFromDB[start:end]
-
-	if err := s.cache.Set(ctx, key, paginatedTransactions, 10*time.Minute); err != nil {
-		s.logger.Error("Failed to cache user transactions", "error", err)
-	}
+		start := offset
+		end := start + limit
+		if end > len(transactionsFromDB) {
+			end = len(transactionsFromDB)
+		}
+		if start > len(transactionsFromDB) {
+			start = len(transactionsFromDB)
+		}
 
-	return paginatedTransactions, nil
+		return transactionsFromDB[start:end], nil
+	})
 }
 
 func (s *CacheService) GetAggregateEvents(ctx context.Context, aggregateID uuid.UUID) ([]domain.Event, error) {
@@ -225,6 +183,10 @@ func (s *CacheService) InvalidateAggregateEvents(ctx context.Context, aggregateI
 	return s.invalidator.InvalidateAggregateEvents(ctx, aggregateID)
 }
 
+func (s *CacheService) InvalidateBatch(ctx context.Context, entityType string, entityIDs []uuid.UUID) (*cache.BatchInvalidationResult, error) {
+	return s.batchInvalidator.InvalidateByEntityType(ctx, entityType, entityIDs)
+}
+
 func (s *CacheService) WarmupUsers(ctx context.Context, userIDs []uuid.UUID) error {
 	return s.warmuper.WarmupUsers(ctx, userIDs)
 }
@@ -253,6 +215,15 @@ func (s *CacheService) GetTTL(ctx context.Context, key string) (time.Duration, e
 	return s.cache.GetTTL(ctx, key)
 }
 
+func (s *CacheService) ListKeys(ctx context.Context, pattern string, limit int) ([]cache.CacheKeyInfo, error) {
+	return s.cache.ScanKeys(ctx, pattern, limit)
+}
+
+// ExpireKey tek bir anahtarı hemen siler; anahtar yoksa false döner
+func (s *CacheService) ExpireKey(ctx context.Context, key string) (bool, error) {
+	return s.cache.Expire(ctx, key)
+}
+
 func (s *CacheService) Exists(ctx context.Context, key string) (bool, error) {
 	return s.cache.Exists(ctx, key)
 }
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"transaction-api-w-go/pkg/cache"
+	"transaction-api-w-go/pkg/domain"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+)
+
+func newTestCacheService(t *testing.T, balances *memoryBalanceRepository) (*CacheService, *miniredis.Miniredis) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	redisCache, err := cache.NewRedisCache(cache.CacheConfig{Addrs: []string{server.Addr()}}, nil)
+	if err != nil {
+		t.Fatalf("NewRedisCache: %v", err)
+	}
+	t.Cleanup(func() { redisCache.Close() })
+	return NewCacheService(redisCache, nil, &memoryTransactionRepository{}, balances, nil, nil), server
+}
+
+func TestGetOrLoad(t *testing.T) {
+	errLoad := errors.New("load failed")
+
+	tests := []struct {
+		name       string
+		cached     string
+		loadErr    error
+		want       string
+		wantErr    error
+		wantLoads  int
+		wantCached bool
+	}{
+		{name: "miss yükler ve cache'e yazar", want: "loaded", wantLoads: 1, wantCached: true},
+		{name: "hit yüklemez", cached: `"cached"`, want: "cached", wantCached: true},
+		{name: "yükleme hatası cache'e yazılmaz", loadErr: errLoad, wantErr: errLoad, wantLoads: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, server := newTestCacheService(t, newMemoryBalanceRepository())
+			if tt.cached != "" {
+				server.Set("key", tt.cached)
+			}
+
+			loads := 0
+			got, err := getOrLoad(context.Background(), svc, "key", time.Minute, func() (string, error) {
+				loads++
+				return "loaded", tt.loadErr
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("getOrLoad = %v, beklenen %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("değer = %q, beklenen %q", got, tt.want)
+			}
+			if loads != tt.wantLoads {
+				t.Errorf("loader %d kez çağrıldı, beklenen %d", loads, tt.wantLoads)
+			}
+			if server.Exists("key") != tt.wantCached {
+				t.Errorf("cache'te var: %v, beklenen %v", server.Exists("key"), tt.wantCached)
+			}
+			if tt.wantLoads > 0 && tt.wantCached && server.TTL("key") != time.Minute {
+				t.Errorf("TTL = %v, beklenen %v", server.TTL("key"), time.Minute)
+			}
+		})
+	}
+}
+
+func TestCacheServiceGetBalanceCachesResult(t *testing.T) {
+	balances := newMemoryBalanceRepository()
+	svc, _ := newTestCacheService(t, balances)
+	ctx := context.Background()
+	userID := uuid.New()
+	balances.set(userID, 100)
+
+	first, err := svc.GetBalance(ctx, userID)
+	if err != nil {
+		t.Fatalf("GetBalance: %v", err)
+	}
+	// Repository'deki değişiklik TTL dolana kadar cache'teki değeri değiştirmemeli
+	balances.set(userID, 250)
+	second, err := svc.GetBalance(ctx, userID)
+	if err != nil {
+		t.Fatalf("GetBalance: %v", err)
+	}
+	if first.Amount != 100 || second.Amount != 100 {
+		t.Errorf("bakiyeler = %v/%v, beklenen 100/100", first.Amount, second.Amount)
+	}
+
+	if err := svc.invalidator.InvalidateBalance(ctx, userID); err != nil {
+		t.Fatalf("InvalidateBalance: %v", err)
+	}
+	third, err := svc.GetBalance(ctx, userID)
+	if err != nil {
+		t.Fatalf("GetBalance: %v", err)
+	}
+	if third.Amount != 250 {
+		t.Errorf("invalidation sonrası bakiye = %v, beklenen 250", third.Amount)
+	}
+}
+
+func TestCacheServiceNilLogger(t *testing.T) {
+	tests := []struct {
+		name      string
+		cached    string
+		redisDown bool
+	}{
+		{name: "hit debug kaydı", cached: `"cached"`},
+		{name: "miss sonrası yazma", cached: ""},
+		{name: "redis hatası error kaydı", redisDown: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, server := newTestCacheService(t, newMemoryBalanceRepository())
+			if _, ok := svc.logger.(domain.NopLogger); !ok {
+				t.Fatalf("logger = %T, beklenen domain.NopLogger", svc.logger)
+			}
+			if tt.cached != "" {
+				server.Set("key", tt.cached)
+			}
+			if tt.redisDown {
+				server.Close()
+			}
+
+			defer func() {
+				if r := recover(); r != nil {
+					t.Fatalf("nil logger ile panik: %v", r)
+				}
+			}()
+			got, err := getOrLoad(context.Background(), svc, "key", time.Minute, func() (string, error) {
+				return "loaded", nil
+			})
+			if err != nil {
+				t.Fatalf("getOrLoad: %v", err)
+			}
+			want := "loaded"
+			if tt.cached != "" {
+				want = "cached"
+			}
+			if got != want {
+				t.Errorf("değer = %q, beklenen %q", got, want)
+			}
+		})
+	}
+}
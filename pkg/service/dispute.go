@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+
+	"transaction-api-w-go/pkg/domain"
+	"transaction-api-w-go/pkg/repository"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+type DisputeService struct {
+	disputeRepo     *repository.DisputeRepository
+	transactionRepo *repository.TransactionRepository
+	eventStore      domain.EventStore
+	// limits nil ise kabul edilen itirazlarda limit kullanımı iade edilmez
+	limits domain.TransactionLimitService
+}
+
+func NewDisputeService(
+	disputeRepo *repository.DisputeRepository,
+	transactionRepo *repository.TransactionRepository,
+	eventStore domain.EventStore,
+) *DisputeService {
+	return &DisputeService{
+		disputeRepo:     disputeRepo,
+		transactionRepo: transactionRepo,
+		eventStore:      eventStore,
+	}
+}
+
+// SetLimits kabul edilen itirazda işlemin limit kullanımını iade edecek servisi bağlar
+func (s *DisputeService) SetLimits(limits domain.TransactionLimitService) {
+	s.limits = limits
+}
+
+// OpenDispute kullanıcının kendi işlemine itiraz açar; aynı işlem için tek bir açık itiraz olabilir
+func (s *DisputeService) OpenDispute(ctx context.Context, userID string, transactionID uuid.UUID, req domain.DisputeRequest) (*domain.Dispute, error) {
+	transaction, err := s.transactionRepo.GetByUUID(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	if transaction.UserID.String() != userID {
+		return nil, domain.ErrTransactionNotFound
+	}
+
+	open, err := s.disputeRepo.HasOpenDispute(ctx, transactionID.String())
+	if err != nil {
+		return nil, err
+	}
+	if open {
+		return nil, domain.ErrDisputeAlreadyOpen
+	}
+
+	dispute, err := domain.NewDispute(transaction, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.disputeRepo.CreateForTransaction(ctx, dispute); err != nil {
+		return nil, err
+	}
+
+	s.emit(ctx, domain.NewTransactionDisputeEvent(domain.EventTransactionDisputed, dispute))
+	return dispute, nil
+}
+
+// ResolveDispute açık itirazı kabul veya ret ile kapatır. Kabul edilen itirazda işlem geri alınmış
+// sayılır ve limit kullanımı iade edilir.
+func (s *DisputeService) ResolveDispute(ctx context.Context, adminID uuid.UUID, disputeID string, req domain.ResolveDisputeRequest) (*domain.Dispute, error) {
+	dispute, err := s.disputeRepo.GetByID(ctx, disputeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := dispute.Resolve(req.Status, req.Resolution, adminID); err != nil {
+		return nil, err
+	}
+
+	if err := s.disputeRepo.Resolve(ctx, dispute); err != nil {
+		return nil, err
+	}
+	if dispute.Status == domain.DisputeStatusAccepted {
+		s.releaseLimit(ctx, dispute.TransactionID)
+	}
+
+	s.emit(ctx, domain.NewTransactionDisputeEvent(domain.EventDisputeResolved, dispute))
+	return dispute, nil
+}
+
+func (s *DisputeService) GetOpenDisputes(ctx context.Context) ([]*domain.Dispute, error) {
+	return s.disputeRepo.ListByStatus(ctx, domain.DisputeStatusOpen)
+}
+
+// releaseLimit geri alınan işlemin limit kullanımını iade eder; iade edilemezse itiraz sonucu değişmez
+func (s *DisputeService) releaseLimit(ctx context.Context, transactionID uuid.UUID) {
+	if s.limits == nil {
+		return
+	}
+	if err := s.limits.ReleaseTransactionLimit(ctx, transactionID); err != nil {
+		log.Error().Err(err).Str("transaction_id", transactionID.String()).Msg("Failed to release transaction limit")
+	}
+}
+
+// emit event'i işlemin event akışının sonuna ekler; event yazılamazsa itiraz işlemi geri alınmaz
+func (s *DisputeService) emit(ctx context.Context, event domain.Event) {
+	if s.eventStore == nil {
+		return
+	}
+
+	err := s.eventStore.AppendEvents(ctx, []domain.EventAppend{{
+		AggregateID:     event.GetAggregateID(),
+		Events:          []domain.Event{event},
+		ExpectedVersion: domain.AnyVersion,
+	}})
+	if err != nil {
+		log.Error().
+			Err(err).
+			Str("event_type", string(event.GetType())).
+			Str("aggregate_id", event.GetAggregateID().String()).
+			Msg("Failed to save dispute event")
+	}
+}
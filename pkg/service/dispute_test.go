@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"transaction-api-w-go/pkg/domain"
+	"transaction-api-w-go/pkg/repository"
+
+	"github.com/google/uuid"
+)
+
+func TestResolveDisputeLimitUsage(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    domain.DisputeStatus
+		wantUsage float64
+	}{
+		{name: "kabul edilen itirazda kullanım iade edilir", status: domain.DisputeStatusAccepted, wantUsage: 0},
+		{name: "reddedilen itirazda kullanım kalır", status: domain.DisputeStatusRejected, wantUsage: 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			ctx := context.Background()
+			userID := env.createUser(t, 100)
+			limits, repo := newTestLimitService(t, uuid.MustParse(userID), 100)
+			svc := NewDisputeService(repository.NewDisputeRepository(env.db), env.transactionRepo, nil)
+			svc.SetLimits(limits)
+
+			transaction := env.createTransaction(t, userID, domain.TransactionTypeDebit, 10, domain.TransactionStateCompleted)
+			if err := limits.ReserveTransactionLimit(ctx, transaction.UserID, domain.CurrencyTRY, transaction.Amount, transaction.ID); err != nil {
+				t.Fatalf("ReserveTransactionLimit: %v", err)
+			}
+
+			dispute, err := svc.OpenDispute(ctx, userID, transaction.ID, domain.DisputeRequest{Reason: "tanımadığım işlem"})
+			if err != nil {
+				t.Fatalf("OpenDispute: %v", err)
+			}
+			resolved, err := svc.ResolveDispute(ctx, uuid.New(), dispute.ID.String(), domain.ResolveDisputeRequest{Status: tt.status})
+			if err != nil {
+				t.Fatalf("ResolveDispute: %v", err)
+			}
+			if resolved.Status != tt.status {
+				t.Errorf("durum = %s, beklenen %s", resolved.Status, tt.status)
+			}
+			if got := dailyAmount(t, repo, transaction.UserID); got != tt.wantUsage {
+				t.Errorf("günlük kullanım = %v, beklenen %v", got, tt.wantUsage)
+			}
+		})
+	}
+}
+
+func TestDisputeOpenAndResolve(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     domain.DisputeStatus
+		resolution string
+	}{
+		{name: "itiraz kabul edilir", status: domain.DisputeStatusAccepted, resolution: "iade edildi"},
+		{name: "itiraz reddedilir", status: domain.DisputeStatusRejected, resolution: "işlem doğrulandı"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			ctx := context.Background()
+			userID := env.createUser(t, 100)
+			otherUser := env.createUser(t, 100)
+			eventStore := repository.NewPostgresEventStore(env.db)
+			svc := NewDisputeService(repository.NewDisputeRepository(env.db), env.transactionRepo, eventStore)
+
+			transaction := env.createTransaction(t, userID, domain.TransactionTypeDebit, 10, domain.TransactionStateCompleted)
+			disputed := func() bool {
+				t.Helper()
+				stored, err := env.transactionRepo.GetByUUID(ctx, transaction.ID)
+				if err != nil {
+					t.Fatalf("GetByUUID: %v", err)
+				}
+				return stored.Disputed
+			}
+			eventCount := func() int64 {
+				t.Helper()
+				count, err := eventStore.GetEventCount(ctx, transaction.ID)
+				if err != nil {
+					t.Fatalf("GetEventCount: %v", err)
+				}
+				return count
+			}
+
+			if _, err := svc.OpenDispute(ctx, userID, transaction.ID, domain.DisputeRequest{}); !errors.Is(err, domain.ErrInvalidDisputeReason) {
+				t.Errorf("gerekçesiz OpenDispute = %v, beklenen ErrInvalidDisputeReason", err)
+			}
+			if _, err := svc.OpenDispute(ctx, otherUser, transaction.ID, domain.DisputeRequest{Reason: "x"}); !errors.Is(err, domain.ErrTransactionNotFound) {
+				t.Errorf("başka kullanıcının OpenDispute = %v, beklenen ErrTransactionNotFound", err)
+			}
+
+			dispute, err := svc.OpenDispute(ctx, userID, transaction.ID, domain.DisputeRequest{Reason: "tanımadığım işlem"})
+			if err != nil {
+				t.Fatalf("OpenDispute: %v", err)
+			}
+			if dispute.Status != domain.DisputeStatusOpen || dispute.OpenedAt.IsZero() {
+				t.Errorf("açılan itiraz = %+v", dispute)
+			}
+			if !disputed() {
+				t.Error("işlem disputed olarak işaretlenmedi")
+			}
+			if got := eventCount(); got != 1 {
+				t.Errorf("event sayısı = %d, beklenen 1", got)
+			}
+			if _, err := svc.OpenDispute(ctx, userID, transaction.ID, domain.DisputeRequest{Reason: "tekrar"}); !errors.Is(err, domain.ErrDisputeAlreadyOpen) {
+				t.Errorf("ikinci OpenDispute = %v, beklenen ErrDisputeAlreadyOpen", err)
+			}
+			open, err := svc.GetOpenDisputes(ctx)
+			if err != nil || len(open) != 1 || open[0].ID != dispute.ID {
+				t.Fatalf("GetOpenDisputes = %v, %v", open, err)
+			}
+
+			adminID := uuid.New()
+			resolved, err := svc.ResolveDispute(ctx, adminID, dispute.ID.String(), domain.ResolveDisputeRequest{Status: tt.status, Resolution: tt.resolution})
+			if err != nil {
+				t.Fatalf("ResolveDispute: %v", err)
+			}
+			if resolved.Status != tt.status || resolved.Resolution != tt.resolution || resolved.ResolvedAt == nil {
+				t.Errorf("çözülen itiraz = %+v", resolved)
+			}
+			if resolved.ResolvedBy == nil || *resolved.ResolvedBy != adminID {
+				t.Errorf("ResolvedBy = %v, beklenen %s", resolved.ResolvedBy, adminID)
+			}
+			if disputed() {
+				t.Error("itiraz çözülünce işlemin disputed işareti kalkmadı")
+			}
+			if got := eventCount(); got != 2 {
+				t.Errorf("event sayısı = %d, beklenen 2", got)
+			}
+			if _, err := svc.ResolveDispute(ctx, adminID, dispute.ID.String(), domain.ResolveDisputeRequest{Status: tt.status}); !errors.Is(err, domain.ErrDisputeNotOpen) {
+				t.Errorf("ikinci ResolveDispute = %v, beklenen ErrDisputeNotOpen", err)
+			}
+			if open, err := svc.GetOpenDisputes(ctx); err != nil || len(open) != 0 {
+				t.Errorf("çözüm sonrası açık itirazlar = %v, %v", open, err)
+			}
+		})
+	}
+}
@@ -2,7 +2,9 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"transaction-api-w-go/pkg/domain"
@@ -15,16 +17,40 @@ type EventReplayService struct {
 	eventStore domain.EventStore
 	eventRepo  *repository.EventRepository
 	logger     domain.Logger
+
+	statsStore StatisticsStore
+	statsMu    sync.Mutex
+	statsCache *ReplayStatistics
+}
+
+const (
+	// EventStatisticsCacheTTL istatistiklerin önbellekte tutulma süresi; yeni yazılan
+	// event'ler istatistiklere en geç bu süre sonunda yansır
+	EventStatisticsCacheTTL = 30 * time.Second
+
+	eventStatisticsKey = "event_statistics"
+)
+
+// StatisticsStore istatistiklerin instance'lar arasında paylaşıldığı önbellektir (ör. cache.RedisCache)
+type StatisticsStore interface {
+	Get(ctx context.Context, key string, dest interface{}) error
+	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error
 }
 
 func NewEventReplayService(eventStore domain.EventStore, eventRepo *repository.EventRepository, logger domain.Logger) *EventReplayService {
 	return &EventReplayService{
 		eventStore: eventStore,
 		eventRepo:  eventRepo,
-		logger:     logger,
+		logger:     domain.LoggerOrNop(logger),
 	}
 }
 
+// SetStatisticsStore istatistiklerin Redis gibi paylaşılan bir önbellekte tutulmasını sağlar;
+// bağlanmazsa yalnızca instance içi önbellek kullanılır
+func (s *EventReplayService) SetStatisticsStore(store StatisticsStore) {
+	s.statsStore = store
+}
+
 func (s *EventReplayService) ReplayEventsForAggregate(ctx context.Context, aggregateID uuid.UUID) error {
 	s.logger.Info("Starting event replay for aggregate", "aggregate_id", aggregateID)
 
@@ -204,36 +230,81 @@ func (s *EventReplayService) groupEventsByAggregate(events []domain.Event) map[u
 func (s *EventReplayService) GetReplayStatistics(ctx context.Context) (*ReplayStatistics, error) {
 	stats := &ReplayStatistics{}
 
-	allEvents, err := s.eventStore.GetAllEvents(ctx, 1000000, 0) // Büyük limit
+	// Sayımlar veritabanında yapılır; event'ler belleğe yüklenmez
+	eventTypeCounts, err := s.eventStore.CountEventsGroupedByType(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get all events for statistics: %w", err)
+		return nil, fmt.Errorf("failed to count events for statistics: %w", err)
 	}
-	stats.TotalEvents = int64(len(allEvents))
-
-	eventTypeCounts := make(map[domain.EventType]int64)
-	for _, event := range allEvents {
-		eventTypeCounts[event.GetType()]++
+	for _, count := range eventTypeCounts {
+		stats.TotalEvents += count
 	}
 	stats.EventTypeCounts = eventTypeCounts
 
-	aggregateGroups := s.groupEventsByAggregate(allEvents)
-	stats.TotalAggregates = int64(len(aggregateGroups))
+	firstEventCounts, err := s.eventStore.CountAggregatesByFirstEventType(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count aggregates for statistics: %w", err)
+	}
 
 	aggregateTypeCounts := make(map[string]int64)
-	for _, events := range aggregateGroups {
-		if len(events) > 0 {
-			aggregateType := s.determineAggregateType(events[0].GetType())
-			aggregateTypeCounts[aggregateType]++
-		}
+	for eventType, count := range firstEventCounts {
+		aggregateTypeCounts[s.determineAggregateType(eventType)] += count
+		stats.TotalAggregates += count
 	}
 	stats.AggregateTypeCounts = aggregateTypeCounts
 
 	return stats, nil
 }
 
+// GetCachedStatistics istatistikleri önce instance içi önbellekten, sonra paylaşılan
+// store'dan okur; ikisi de EventStatisticsCacheTTL'den eskiyse yeniden hesaplar
+func (s *EventReplayService) GetCachedStatistics(ctx context.Context) (*ReplayStatistics, error) {
+	now := time.Now()
+
+	s.statsMu.Lock()
+	cached := s.statsCache
+	s.statsMu.Unlock()
+	if cached != nil && now.Sub(cached.ComputedAt) < EventStatisticsCacheTTL {
+		return cached, nil
+	}
+
+	if s.statsStore != nil {
+		var stored ReplayStatistics
+		err := s.statsStore.Get(ctx, eventStatisticsKey, &stored)
+		if err == nil && now.Sub(stored.ComputedAt) < EventStatisticsCacheTTL {
+			s.storeStatistics(&stored)
+			return &stored, nil
+		}
+		if err != nil && !errors.Is(err, domain.ErrCacheMiss) {
+			s.logger.Warn("Failed to read cached event statistics", "error", err)
+		}
+	}
+
+	stats, err := s.GetReplayStatistics(ctx)
+	if err != nil {
+		return nil, err
+	}
+	stats.ComputedAt = now
+	s.storeStatistics(stats)
+
+	if s.statsStore != nil {
+		if err := s.statsStore.Set(ctx, eventStatisticsKey, stats, EventStatisticsCacheTTL); err != nil {
+			s.logger.Warn("Failed to cache event statistics", "error", err)
+		}
+	}
+	return stats, nil
+}
+
+func (s *EventReplayService) storeStatistics(stats *ReplayStatistics) {
+	s.statsMu.Lock()
+	s.statsCache = stats
+	s.statsMu.Unlock()
+}
+
 type ReplayStatistics struct {
 	TotalEvents         int64                      `json:"total_events"`
 	TotalAggregates     int64                      `json:"total_aggregates"`
 	EventTypeCounts     map[domain.EventType]int64 `json:"event_type_counts"`
 	AggregateTypeCounts map[string]int64           `json:"aggregate_type_counts"`
+	// ComputedAt istatistiklerin hesaplandığı an; önbellekten dönen değerin yaşını gösterir
+	ComputedAt time.Time `json:"computed_at"`
 }
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"transaction-api-w-go/pkg/domain"
+	"transaction-api-w-go/pkg/repository"
+
+	"github.com/google/uuid"
+)
+
+// memoryStatisticsStore Redis'in yerine geçen paylaşılan önbellektir; değerler JSON olarak saklanır
+type memoryStatisticsStore struct {
+	mu     sync.Mutex
+	values map[string][]byte
+}
+
+func (s *memoryStatisticsStore) Get(ctx context.Context, key string, dest interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, ok := s.values[key]
+	if !ok {
+		return domain.ErrCacheMiss
+	}
+	return json.Unmarshal(data, dest)
+}
+
+func (s *memoryStatisticsStore) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.values == nil {
+		s.values = make(map[string][]byte)
+	}
+	s.values[key] = data
+	return nil
+}
+
+// expire önbellekteki kayıtları TTL dolmuş gibi siler
+func (s *memoryStatisticsStore) expire() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values = nil
+}
+
+func TestEventReplayServiceCachedStatistics(t *testing.T) {
+	tests := []struct {
+		name   string
+		shared bool
+	}{
+		{name: "instance içi önbellek"},
+		{name: "paylaşılan önbellek", shared: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			ctx := context.Background()
+			eventStore := repository.NewPostgresEventStore(env.db)
+			newService := func() *EventReplayService {
+				return NewEventReplayService(eventStore, repository.NewEventRepository(eventStore), domain.NopLogger{})
+			}
+			writeEvent := func() {
+				t.Helper()
+				transaction := &domain.Transaction{ID: uuid.New(), UserID: uuid.New(), Type: domain.TransactionTypeCredit, Amount: 10}
+				if err := eventStore.SaveEvents(ctx, transaction.ID, []domain.Event{domain.NewTransactionCreatedEvent(transaction)}, 0); err != nil {
+					t.Fatalf("SaveEvents: %v", err)
+				}
+			}
+
+			store := &memoryStatisticsStore{}
+			writer := newService()
+			reader := writer
+			if tt.shared {
+				writer.SetStatisticsStore(store)
+				reader = newService()
+				reader.SetStatisticsStore(store)
+			}
+
+			writeEvent()
+			writeEvent()
+			first, err := writer.GetCachedStatistics(ctx)
+			if err != nil {
+				t.Fatalf("GetCachedStatistics: %v", err)
+			}
+			if first.TotalEvents != 2 || first.TotalAggregates != 2 {
+				t.Fatalf("event/aggregate = %d/%d, beklenen 2/2", first.TotalEvents, first.TotalAggregates)
+			}
+
+			// TTL dolmadan yazılan event önbellekteki özeti değiştirmez
+			writeEvent()
+			cached, err := reader.GetCachedStatistics(ctx)
+			if err != nil {
+				t.Fatalf("GetCachedStatistics: %v", err)
+			}
+			if cached.TotalEvents != 2 || !cached.ComputedAt.Equal(first.ComputedAt) {
+				t.Errorf("TTL içinde istatistik = %d event (%v), beklenen önbellekteki 2 event (%v)", cached.TotalEvents, cached.ComputedAt, first.ComputedAt)
+			}
+
+			// TTL dolunca yeni event istatistiklere yansır
+			reader.statsMu.Lock()
+			reader.statsCache.ComputedAt = reader.statsCache.ComputedAt.Add(-EventStatisticsCacheTTL)
+			reader.statsMu.Unlock()
+			store.expire()
+
+			fresh, err := reader.GetCachedStatistics(ctx)
+			if err != nil {
+				t.Fatalf("GetCachedStatistics: %v", err)
+			}
+			if fresh.TotalEvents != 3 || fresh.TotalAggregates != 3 {
+				t.Errorf("TTL sonrası event/aggregate = %d/%d, beklenen 3/3", fresh.TotalEvents, fresh.TotalAggregates)
+			}
+			if got := fresh.EventTypeCounts[domain.EventTransactionCreated]; got != 3 {
+				t.Errorf("%s sayısı = %d, beklenen 3", domain.EventTransactionCreated, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,392 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"transaction-api-w-go/pkg/domain"
+	"transaction-api-w-go/pkg/payment"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// DefaultSettlementBatchSize tek mutabakat turunda ağ geçidine sorulan en fazla işlem sayısı
+const DefaultSettlementBatchSize = 100
+
+// transferExternal dış hesaba giden transferi ağ geçidi üzerinden çekim olarak işler. Dış transferlerden
+// ücret alınmaz.
+func (s *TransactionService) transferExternal(ctx context.Context, fromUserID string, req *domain.TransferRequest) (*domain.Transaction, error) {
+	return s.withdrawExternal(ctx, fromUserID, domain.TransactionTypeTransfer, &domain.TransactionRequest{
+		Amount:          req.Amount,
+		Description:     req.Description,
+		ReferenceID:     req.ReferenceID,
+		Category:        req.Category,
+		Tags:            req.Tags,
+		Metadata:        req.Metadata,
+		ExternalAccount: req.ExternalAccount,
+	})
+}
+
+// withdrawExternal dış hesaba giden çekimi pending_settlement durumunda, tutarı ayıran bir bakiye
+// blokajıyla birlikte kaydeder ve ödeme ağ geçidine iletir. Bakiye ağ geçidi çekimi sonuçlandırana kadar
+// değişmez; çekim başarısız olursa blokaj serbest bırakılır.
+func (s *TransactionService) withdrawExternal(ctx context.Context, userID string, txType domain.TransactionType, req *domain.TransactionRequest) (*domain.Transaction, error) {
+	if s.gateway == nil {
+		return nil, domain.ErrPaymentGatewayUnavailable
+	}
+	if err := req.ExternalAccount.Validate(); err != nil {
+		return nil, err
+	}
+
+	amount := req.Amount
+	balance, err := s.getOrCreateBalance(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkAvailable(ctx, balance, amount); err != nil {
+		return nil, err
+	}
+
+	transaction := s.newExternalTransaction(userID, txType, req)
+	transaction.BalanceAfter = balance.Amount - amount
+
+	if err := s.reserveLimit(ctx, transaction, balance); err != nil {
+		return nil, err
+	}
+	if screened, stop, err := s.screenTransaction(ctx, transaction); stop {
+		if err != nil {
+			s.releaseLimit(ctx, transaction.ID)
+		}
+		return screened, err
+	}
+
+	// Blokaj, kullanılabilir bakiye kilit altında yeniden kontrol edildikten sonra yazılır
+	hold := newPaymentHold(transaction)
+	s.markReference(transaction)
+	err = s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		if err := lockBalances(ctx, s.balanceRepo, balance); err != nil {
+			return err
+		}
+		if err := s.checkAvailable(ctx, balance, amount); err != nil {
+			return err
+		}
+		transaction.BalanceAfter = balance.Amount - amount
+		return s.transactionRepo.CreateWithHold(ctx, transaction, hold)
+	})
+	if err != nil {
+		s.releaseLimit(ctx, transaction.ID)
+		return s.existingReference(ctx, transaction, err)
+	}
+	s.emit(ctx, domain.NewTransactionStateChangedEvent(transaction,
+		domain.TransactionStatePending, domain.TransactionStatePendingSettlement, ""))
+
+	return transaction, s.submitWithdrawal(ctx, transaction)
+}
+
+// depositExternal dış hesaptan çekilecek yatırmayı pending_settlement durumunda kaydeder ve ödeme ağ
+// geçidine iletir. Bakiye ancak ağ geçidi yatırmayı sonuçlandırdığında artar.
+func (s *TransactionService) depositExternal(ctx context.Context, userID string, req *domain.TransactionRequest) (*domain.Transaction, error) {
+	if s.gateway == nil {
+		return nil, domain.ErrPaymentGatewayUnavailable
+	}
+	if err := req.ExternalAccount.Validate(); err != nil {
+		return nil, err
+	}
+
+	balance, err := s.getOrCreateBalance(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	transaction := s.newExternalTransaction(userID, domain.TransactionTypeCredit, req)
+	transaction.BalanceAfter = balance.Amount + req.Amount
+
+	if err := s.reserveLimit(ctx, transaction, balance); err != nil {
+		return nil, err
+	}
+	if screened, stop, err := s.screenTransaction(ctx, transaction); stop {
+		if err != nil {
+			s.releaseLimit(ctx, transaction.ID)
+		}
+		return screened, err
+	}
+	if existing, err := s.createTransaction(ctx, transaction); err != nil {
+		s.releaseLimit(ctx, transaction.ID)
+		return existing, err
+	}
+	s.emit(ctx, domain.NewTransactionStateChangedEvent(transaction,
+		domain.TransactionStatePending, domain.TransactionStatePendingSettlement, ""))
+
+	return transaction, s.submitDeposit(ctx, transaction)
+}
+
+func (s *TransactionService) newExternalTransaction(userID string, txType domain.TransactionType, req *domain.TransactionRequest) *domain.Transaction {
+	return &domain.Transaction{
+		ID:              uuid.New(),
+		UserID:          uuid.MustParse(userID),
+		Type:            txType,
+		Amount:          req.Amount,
+		Description:     req.Description,
+		ReferenceID:     req.ReferenceID,
+		Category:        req.Category,
+		Tags:            req.Tags,
+		Metadata:        req.Metadata,
+		ExternalAccount: req.ExternalAccount,
+		Status:          string(domain.TransactionStatePendingSettlement),
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
+	}
+}
+
+// newPaymentHold çekim tutarını ödeme sonuçlanana kadar ayıran blokajı oluşturur
+func newPaymentHold(transaction *domain.Transaction) *domain.BalanceHold {
+	transactionID := transaction.ID
+	return &domain.BalanceHold{
+		ID:            uuid.New(),
+		UserID:        transaction.UserID,
+		Amount:        transaction.Amount,
+		Status:        domain.HoldStatusActive,
+		Description:   "External payment " + transaction.ExternalAccount.Reference(),
+		ReferenceID:   transaction.ReferenceID,
+		TransactionID: &transactionID,
+		CreatedAt:     transaction.CreatedAt,
+		UpdatedAt:     transaction.CreatedAt,
+	}
+}
+
+// approveExternalTransaction incelemedeki dış ödemeyi pending_settlement durumuna alır ve ağ geçidine
+// iletir. Durum geçişi ağ geçidine gönderimden önce yazılır; böylece eşzamanlı iki onay aynı ödemeyi
+// iki kez göndermez.
+func (s *TransactionService) approveExternalTransaction(ctx context.Context, reviewerID uuid.UUID, transaction *domain.Transaction) (*domain.Transaction, error) {
+	if s.gateway == nil {
+		return nil, domain.ErrPaymentGatewayUnavailable
+	}
+	if transaction.Type == domain.TransactionTypeCredit {
+		if err := transaction.UpdateState(domain.TransactionStatePendingSettlement); err != nil {
+			return nil, err
+		}
+		if err := s.transactionRepo.ResolveHeld(ctx, transaction, nil); err != nil {
+			return nil, err
+		}
+		s.emitReview(ctx, transaction, reviewerID, transaction.StatusReason)
+		return transaction, s.submitDeposit(ctx, transaction)
+	}
+
+	balance, err := s.balanceRepo.GetByUserIDAndCurrency(ctx, transaction.UserID.String(), s.defaultCurrency)
+	if err != nil {
+		return nil, err
+	}
+	if err := transaction.UpdateState(domain.TransactionStatePendingSettlement); err != nil {
+		return nil, err
+	}
+
+	// Blokaj ve durum geçişi aynı transaction'da yazılır; onay yarışı kaybedilirse blokaj da geri alınır
+	hold := newPaymentHold(transaction)
+	err = s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		if err := lockBalances(ctx, s.balanceRepo, balance); err != nil {
+			return err
+		}
+		available, err := availableAmount(ctx, s.holdRepo, s.transactionRepo, balance)
+		if err != nil {
+			return err
+		}
+		// İncelemedeki işlemin kendisi bekleyen borçlara dahil olduğu için tutarı geri eklenir
+		if err := balance.CheckWithdrawal(available+transaction.Amount, transaction.Amount); err != nil {
+			return err
+		}
+		if err := s.holdRepo.Create(ctx, hold); err != nil {
+			return err
+		}
+		transaction.BalanceAfter = balance.Amount - transaction.Amount
+		return s.transactionRepo.ResolveHeld(ctx, transaction, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.emitReview(ctx, transaction, reviewerID, transaction.StatusReason)
+
+	return transaction, s.submitWithdrawal(ctx, transaction)
+}
+
+func (s *TransactionService) submitWithdrawal(ctx context.Context, transaction *domain.Transaction) error {
+	return s.submitPayment(ctx, transaction, func() (*payment.Result, error) {
+		return s.gateway.Withdraw(ctx, &payment.Withdrawal{
+			TransactionID: transaction.ID,
+			Amount:        transaction.Amount,
+			Description:   transaction.Description,
+			Destination:   *transaction.ExternalAccount,
+		})
+	})
+}
+
+func (s *TransactionService) submitDeposit(ctx context.Context, transaction *domain.Transaction) error {
+	return s.submitPayment(ctx, transaction, func() (*payment.Result, error) {
+		return s.gateway.Deposit(ctx, &payment.Deposit{
+			TransactionID: transaction.ID,
+			Amount:        transaction.Amount,
+			Description:   transaction.Description,
+			Source:        *transaction.ExternalAccount,
+		})
+	})
+}
+
+// submitPayment talimatı ağ geçidine iletir ve verilen referansı kaydeder. Ağ geçidi sonucu hemen
+// bildirirse işlem o anda sonuçlandırılır; pending ise mutabakat job'u sonucu daha sonra sorar.
+// Ağ geçidine ulaşılamazsa veya talimat reddedilirse işlem failed olur ve blokaj serbest kalır.
+func (s *TransactionService) submitPayment(ctx context.Context, transaction *domain.Transaction, submit func() (*payment.Result, error)) error {
+	result, err := submit()
+	if err != nil {
+		if settleErr := s.settleExternal(ctx, transaction, &payment.Result{
+			Status: payment.StatusFailed,
+			Reason: "payment gateway unavailable",
+		}); settleErr != nil {
+			log.Error().Err(settleErr).Str("transaction_id", transaction.ID.String()).Msg("Failed to mark external payment as failed")
+		}
+		return fmt.Errorf("%w: %v", domain.ErrPaymentGatewayUnavailable, err)
+	}
+
+	log.Info().
+		Str("transaction_id", transaction.ID.String()).
+		Str("gateway_reference", result.Reference).
+		Str("gateway_status", string(result.Status)).
+		Msg("External payment submitted to payment gateway")
+
+	if !result.Status.IsFinal() {
+		transaction.GatewayReference = result.Reference
+		transaction.UpdatedAt = time.Now()
+		return s.transactionRepo.Update(ctx, transaction)
+	}
+	if err := s.settleExternal(ctx, transaction, result); err != nil {
+		return err
+	}
+	if result.Status == payment.StatusFailed {
+		return fmt.Errorf("%w: %s", domain.ErrExternalTransferRejected, result.Reason)
+	}
+	return nil
+}
+
+// settleExternal ağ geçidinin bildirdiği nihai sonucu uygular. Başarılı çekimde blokaj yakalanır ve
+// bakiye düşülür, başarılı yatırmada bakiye artırılır; başarısız ödemede blokaj serbest bırakılır ve
+// bakiye değişmez ve işlemin limit kullanımı iade edilir. Sonuç henüz nihai değilse hiçbir şey yapılmaz.
+func (s *TransactionService) settleExternal(ctx context.Context, transaction *domain.Transaction, result *payment.Result) error {
+	if !result.Status.IsFinal() {
+		return nil
+	}
+
+	hold, err := s.holdRepo.GetActiveByTransactionID(ctx, transaction.ID)
+	if errors.Is(err, domain.ErrHoldNotFound) {
+		hold, err = nil, nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var balance *domain.Balance
+	if result.Status == payment.StatusSettled {
+		if balance, err = s.balanceRepo.GetByUserIDAndCurrency(ctx, transaction.UserID.String(), s.defaultCurrency); err != nil {
+			return err
+		}
+		if hold != nil {
+			if err := hold.Capture(transaction.ID); err != nil {
+				return err
+			}
+		}
+		err = transaction.UpdateState(domain.TransactionStateCompleted)
+	} else {
+		if hold != nil {
+			if err := hold.Release(); err != nil {
+				return err
+			}
+		}
+		transaction.StatusReason = result.Reason
+		err = transaction.UpdateState(domain.TransactionStateFailed)
+	}
+	if err != nil {
+		return err
+	}
+
+	if result.Reference != "" {
+		transaction.GatewayReference = result.Reference
+	}
+	// Bakiye kilit altında yeniden okunur; mutabakat ile eşzamanlı bir borç veya transferin yazdığı
+	// tutar üzerine eski kopya yazılmaz
+	err = s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		if balance != nil {
+			if err := lockBalances(ctx, s.balanceRepo, balance); err != nil {
+				return err
+			}
+			if transaction.Type == domain.TransactionTypeCredit {
+				balance.Amount += transaction.Amount
+			} else {
+				balance.Amount -= transaction.Amount
+			}
+			balance.UpdatedAt = time.Now()
+			transaction.BalanceAfter = balance.Amount
+		}
+		return s.transactionRepo.SettleExternal(ctx, transaction, hold, balance)
+	})
+	if err != nil {
+		return err
+	}
+	if result.Status != payment.StatusSettled {
+		s.releaseLimit(ctx, transaction.ID)
+	}
+
+	s.emit(ctx, domain.NewTransactionStateChangedEvent(transaction,
+		domain.TransactionStatePendingSettlement, domain.TransactionState(transaction.Status), transaction.StatusReason))
+	if balance != nil {
+		s.evaluateAlerts(ctx, balance)
+		attachReceipt(ctx, s.receipts, transaction)
+	}
+	return nil
+}
+
+// ReconcileExternalPayments ağ geçidinde sonuç bekleyen ödemelerin durumunu sorar ve sonuçlananları
+// uygular; sonuçlandırılan ödeme sayısını döner. Tek bir ödemenin sorgusu başarısız olursa diğerlerine
+// devam edilir.
+func (s *TransactionService) ReconcileExternalPayments(ctx context.Context, limit int) (int, error) {
+	if s.gateway == nil {
+		return 0, nil
+	}
+	if limit <= 0 {
+		limit = DefaultSettlementBatchSize
+	}
+
+	transactions, err := s.transactionRepo.ListByStatus(ctx, domain.TransactionStatePendingSettlement, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	settled := 0
+	for _, transaction := range transactions {
+		// Referansı olmayan ödeme henüz ağ geçidine iletilmemiştir; gönderen istek sonucu kendisi yazar
+		if transaction.GatewayReference == "" {
+			continue
+		}
+
+		result, err := s.gateway.GetStatus(ctx, transaction.GatewayReference)
+		if err != nil {
+			log.Warn().Err(err).
+				Str("transaction_id", transaction.ID.String()).
+				Str("gateway_reference", transaction.GatewayReference).
+				Msg("Failed to query payment status")
+			continue
+		}
+		if !result.Status.IsFinal() {
+			continue
+		}
+
+		if err := s.settleExternal(ctx, transaction, result); err != nil {
+			if !errors.Is(err, domain.ErrTransactionNotPendingSettlement) {
+				log.Error().Err(err).Str("transaction_id", transaction.ID.String()).Msg("Failed to settle external payment")
+			}
+			continue
+		}
+		settled++
+	}
+	return settled, nil
+}
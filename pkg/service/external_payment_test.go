@@ -0,0 +1,336 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"transaction-api-w-go/pkg/circuitbreaker"
+	"transaction-api-w-go/pkg/clock"
+	"transaction-api-w-go/pkg/domain"
+	"transaction-api-w-go/pkg/payment"
+
+	"github.com/google/uuid"
+)
+
+func TestSettleExternalLimitUsage(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      payment.Status
+		wantState   domain.TransactionState
+		wantUsage   float64
+		wantBalance float64
+	}{
+		{name: "başarısız ödemede kullanım iade edilir", status: payment.StatusFailed, wantState: domain.TransactionStateFailed, wantUsage: 0, wantBalance: 100},
+		{name: "başarılı ödemede kullanım kalır", status: payment.StatusSettled, wantState: domain.TransactionStateCompleted, wantUsage: 10, wantBalance: 90},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			ctx := context.Background()
+			userID := env.createUser(t, 100)
+			limits, repo := newTestLimitService(t, uuid.MustParse(userID), 100)
+			svc := env.transactionService()
+			svc.SetLimits(limits)
+
+			transaction := env.createTransaction(t, userID, domain.TransactionTypeDebit, 10, domain.TransactionStatePendingSettlement)
+			if err := limits.ReserveTransactionLimit(ctx, transaction.UserID, domain.CurrencyTRY, transaction.Amount, transaction.ID); err != nil {
+				t.Fatalf("ReserveTransactionLimit: %v", err)
+			}
+
+			if err := svc.settleExternal(ctx, transaction, &payment.Result{Reference: "gw-1", Status: tt.status, Reason: "reddedildi"}); err != nil {
+				t.Fatalf("settleExternal: %v", err)
+			}
+			if transaction.Status != string(tt.wantState) {
+				t.Errorf("durum = %s, beklenen %s", transaction.Status, tt.wantState)
+			}
+			if got := dailyAmount(t, repo, transaction.UserID); got != tt.wantUsage {
+				t.Errorf("günlük kullanım = %v, beklenen %v", got, tt.wantUsage)
+			}
+			if got := env.balanceAmount(t, userID); got != tt.wantBalance {
+				t.Errorf("bakiye = %v, beklenen %v", got, tt.wantBalance)
+			}
+		})
+	}
+}
+
+func TestSettleExternalConcurrentWithDebit(t *testing.T) {
+	tests := []struct {
+		name        string
+		settleType  domain.TransactionType
+		initial     float64
+		pairs       int
+		wantBalance float64
+	}{
+		{name: "yatırma mutabakatı eşzamanlı çekimle", settleType: domain.TransactionTypeCredit, initial: 100, pairs: 10, wantBalance: 100 + 10*20 - 10*5},
+		{name: "çekim mutabakatı eşzamanlı çekimle", settleType: domain.TransactionTypeDebit, initial: 300, pairs: 10, wantBalance: 300 - 10*20 - 10*5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			ctx := context.Background()
+			userID := env.createUser(t, tt.initial)
+			svc := env.transactionService()
+
+			// Bellek içi sunucuda satır kilidi yok; tek bağlantı her transaction'ı atomik yapar, mutabakatın
+			// kilit dışındaki okuması yine eşzamanlı çekimlerin arasına girer
+			sqlDB, err := env.db.DB()
+			if err != nil {
+				t.Fatalf("bağlantı havuzu alınamadı: %v", err)
+			}
+			sqlDB.SetMaxOpenConns(1)
+
+			pending := make([]*domain.Transaction, tt.pairs)
+			for i := range pending {
+				pending[i] = env.createTransaction(t, userID, tt.settleType, 20, domain.TransactionStatePendingSettlement)
+			}
+
+			var wg sync.WaitGroup
+			start := make(chan struct{})
+			for _, transaction := range pending {
+				wg.Add(2)
+				go func(transaction *domain.Transaction) {
+					defer wg.Done()
+					<-start
+					if err := svc.settleExternal(ctx, transaction, &payment.Result{Reference: "gw-" + transaction.ID.String(), Status: payment.StatusSettled}); err != nil {
+						t.Errorf("settleExternal: %v", err)
+					}
+				}(transaction)
+				go func() {
+					defer wg.Done()
+					<-start
+					if _, err := svc.Debit(ctx, userID, &domain.TransactionRequest{Amount: 5}); err != nil {
+						t.Errorf("Debit: %v", err)
+					}
+				}()
+			}
+			close(start)
+			wg.Wait()
+
+			if got := env.balanceAmount(t, userID); got != tt.wantBalance {
+				t.Errorf("bakiye = %v, beklenen %v; eşzamanlı yazımlar kaybolmamalı", got, tt.wantBalance)
+			}
+		})
+	}
+}
+
+func TestTransactionServiceExternalTransferPending(t *testing.T) {
+	validIBAN := &domain.ExternalAccount{IBAN: "TR33 0006 1005 1978 6457 8413 26", HolderName: "Ayşe Yılmaz"}
+
+	tests := []struct {
+		name          string
+		account       *domain.ExternalAccount
+		toUser        bool
+		noGateway     bool
+		amount        float64
+		wantErr       error
+		wantReference string
+	}{
+		{name: "IBAN'a transfer ödeme bekler", account: validIBAN, amount: 30, wantReference: "TR330006100519786457841326"},
+		{name: "banka hesabına transfer ödeme bekler", account: &domain.ExternalAccount{BankCode: "0062", AccountNumber: "12345678", HolderName: "Ali Veli"}, amount: 30, wantReference: "0062/12345678"},
+		{name: "geçersiz IBAN", account: &domain.ExternalAccount{IBAN: "TR00 0000", HolderName: "Ali Veli"}, amount: 30, wantErr: domain.ErrInvalidExternalAccount},
+		{name: "ağ geçidi yoksa reddedilir", account: validIBAN, noGateway: true, amount: 30, wantErr: domain.ErrPaymentGatewayUnavailable},
+		{name: "bakiye yetersiz", account: validIBAN, amount: 130, wantErr: domain.ErrInsufficientBalance},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			ctx := context.Background()
+			userID := env.createUser(t, 100)
+			svc := env.transactionService()
+			if !tt.noGateway {
+				svc.SetPaymentGateway(payment.NewStubGateway())
+			}
+
+			transaction, err := svc.Transfer(ctx, userID, &domain.TransferRequest{Amount: tt.amount, ExternalAccount: tt.account})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Transfer = %v, beklenen %v", err, tt.wantErr)
+			}
+
+			balance, balanceErr := env.balanceService().GetCurrentBalance(ctx, userID)
+			if balanceErr != nil {
+				t.Fatalf("GetCurrentBalance: %v", balanceErr)
+			}
+			if balance.Ledger != 100 {
+				t.Errorf("Ledger = %v, beklenen 100; ödeme sonuçlanmadan bakiye değişmemeli", balance.Ledger)
+			}
+			if tt.wantErr != nil {
+				if balance.Available != 100 {
+					t.Errorf("Available = %v, beklenen 100", balance.Available)
+				}
+				return
+			}
+			if balance.Available != 100-tt.amount {
+				t.Errorf("Available = %v, beklenen %v; tutar blokajla ayrılmalı", balance.Available, 100-tt.amount)
+			}
+
+			stored, err := env.transactionRepo.GetByUUID(ctx, transaction.ID)
+			if err != nil {
+				t.Fatalf("işlem okunamadı: %v", err)
+			}
+			if stored.Status != string(domain.TransactionStatePendingSettlement) || stored.Type != domain.TransactionTypeTransfer {
+				t.Errorf("işlem = %s/%s, beklenen transfer/pending_settlement", stored.Type, stored.Status)
+			}
+			if stored.CounterpartyID != nil || stored.ExternalAccount == nil || stored.ExternalAccount.Reference() != tt.wantReference {
+				t.Errorf("hedef = %v/%v, beklenen yalnızca dış hesap %s", stored.CounterpartyID, stored.ExternalAccount, tt.wantReference)
+			}
+			if stored.GatewayReference != "stub-"+transaction.ID.String() {
+				t.Errorf("GatewayReference = %q, beklenen stub referansı", stored.GatewayReference)
+			}
+		})
+	}
+}
+
+func TestScheduledExternalTransferPending(t *testing.T) {
+	tests := []struct {
+		name       string
+		noGateway  bool
+		wantStatus domain.TransactionState
+	}{
+		{name: "zamanı gelen dış transfer ödeme bekler", wantStatus: domain.TransactionStatePendingSettlement},
+		{name: "ağ geçidi yoksa çalıştırılmaz", noGateway: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newScheduledTestEnv(t)
+			svc := env.scheduledService()
+			if !tt.noGateway {
+				svc.SetPaymentGateway(payment.NewStubGateway())
+			}
+			userID := env.createUser(t, 100)
+			account := &domain.ExternalAccount{IBAN: "TR330006100519786457841326", HolderName: "Ayşe Yılmaz"}
+
+			scheduledTransaction := env.createDue(t, userID, domain.ScheduledTransactionRequest{
+				Type:            domain.TransactionTypeTransfer,
+				Amount:          40,
+				Currency:        domain.CurrencyTRY,
+				ExternalAccount: account,
+			}, time.Minute)
+			if stored := env.scheduled(t, scheduledTransaction.ID); stored.ExternalAccount == nil || stored.ExternalAccount.Reference() != account.Reference() {
+				t.Fatalf("kayıtlı dış hesap = %v, beklenen %s", stored.ExternalAccount, account.Reference())
+			}
+
+			if err := svc.ExecuteScheduledTransactions(context.Background()); err != nil {
+				t.Fatalf("ExecuteScheduledTransactions: %v", err)
+			}
+			if got := env.balances.amount(userID); got != 100 {
+				t.Errorf("bakiye = %v, beklenen 100; ödeme sonuçlanmadan bakiye değişmemeli", got)
+			}
+			if tt.wantStatus == "" {
+				if env.transactions.count() != 0 {
+					t.Errorf("%d işlem yazıldı, beklenen 0", env.transactions.count())
+				}
+				return
+			}
+
+			if env.transactions.count() != 1 {
+				t.Fatalf("%d işlem yazıldı, beklenen 1", env.transactions.count())
+			}
+			transaction := env.transactions.transactions[0]
+			if transaction.Status != string(tt.wantStatus) || transaction.ExternalAccount == nil {
+				t.Errorf("işlem = %s/%v, beklenen %s ve dış hesap", transaction.Status, transaction.ExternalAccount, tt.wantStatus)
+			}
+			if transaction.GatewayReference != "stub-"+transaction.ID.String() {
+				t.Errorf("GatewayReference = %q, beklenen stub referansı", transaction.GatewayReference)
+			}
+		})
+	}
+}
+
+func TestTransactionServiceExternalPayments(t *testing.T) {
+	account := &domain.ExternalAccount{IBAN: "TR330006100519786457841326", HolderName: "Ayşe Yılmaz"}
+
+	tests := []struct {
+		name          string
+		deposit       bool
+		amount        float64
+		settleAfter   time.Duration
+		unavailable   bool
+		wantErr       error
+		wantPending   bool
+		wantStatus    domain.TransactionState
+		wantLedger    float64
+		wantAvailable float64
+	}{
+		{name: "yatırma anında sonuçlanır", deposit: true, amount: 50, wantStatus: domain.TransactionStateCompleted, wantLedger: 150, wantAvailable: 150},
+		{name: "yatırma mutabakatla sonuçlanır", deposit: true, amount: 50, settleAfter: time.Minute, wantPending: true, wantStatus: domain.TransactionStateCompleted, wantLedger: 150, wantAvailable: 150},
+		{name: "çekim mutabakatla sonuçlanır", amount: 30, settleAfter: time.Minute, wantPending: true, wantStatus: domain.TransactionStateCompleted, wantLedger: 70, wantAvailable: 70},
+		{name: "reddedilen çekim blokajı geri alır", amount: 10.13, wantErr: domain.ErrExternalTransferRejected, wantStatus: domain.TransactionStateFailed, wantLedger: 100, wantAvailable: 100},
+		{name: "mutabakatta reddedilen çekim blokajı geri alır", amount: 10.13, settleAfter: time.Minute, wantPending: true, wantStatus: domain.TransactionStateFailed, wantLedger: 100, wantAvailable: 100},
+		{name: "ağ geçidi kapalıyken çekim blokajı geri alır", amount: 30, unavailable: true, wantErr: domain.ErrPaymentGatewayUnavailable, wantStatus: domain.TransactionStateFailed, wantLedger: 100, wantAvailable: 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			ctx := context.Background()
+			userID := env.createUser(t, 100)
+
+			clk := clock.NewFake(time.Now())
+			mock := payment.NewMockGatewayWithClock(tt.settleAfter, clk)
+			mock.SetAvailable(!tt.unavailable)
+			breaker := circuitbreaker.NewCircuitBreaker("payment_gateway", payment.DefaultBreakerConfig())
+			defer breaker.Close()
+			svc := env.transactionService()
+			svc.SetPaymentGateway(payment.NewCircuitBreakerGateway(mock, breaker))
+
+			req := &domain.TransactionRequest{Amount: tt.amount, ExternalAccount: account}
+			var (
+				transaction *domain.Transaction
+				err         error
+			)
+			if tt.deposit {
+				transaction, err = svc.Credit(ctx, userID, req)
+			} else {
+				transaction, err = svc.Debit(ctx, userID, req)
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("işlem = %v, beklenen %v", err, tt.wantErr)
+			}
+
+			if tt.wantPending {
+				stored, err := env.transactionRepo.GetByUUID(ctx, transaction.ID)
+				if err != nil {
+					t.Fatalf("işlem okunamadı: %v", err)
+				}
+				if stored.Status != string(domain.TransactionStatePendingSettlement) {
+					t.Fatalf("durum = %s, beklenen pending_settlement", stored.Status)
+				}
+				if got := env.balanceAmount(t, userID); got != 100 {
+					t.Errorf("bakiye = %v, beklenen 100; sonuçlanmadan değişmemeli", got)
+				}
+
+				clk.Advance(tt.settleAfter)
+				settled, err := svc.ReconcileExternalPayments(ctx, DefaultSettlementBatchSize)
+				if err != nil || settled != 1 {
+					t.Fatalf("ReconcileExternalPayments = %d, %v; beklenen 1", settled, err)
+				}
+			}
+
+			stored, err := env.transactionRepo.GetByUUID(ctx, transaction.ID)
+			if err != nil {
+				t.Fatalf("işlem okunamadı: %v", err)
+			}
+			if stored.Status != string(tt.wantStatus) {
+				t.Errorf("durum = %s, beklenen %s", stored.Status, tt.wantStatus)
+			}
+			balance, err := env.balanceService().GetCurrentBalance(ctx, userID)
+			if err != nil {
+				t.Fatalf("GetCurrentBalance: %v", err)
+			}
+			if math.Abs(balance.Ledger-tt.wantLedger) > 1e-9 || math.Abs(balance.Available-tt.wantAvailable) > 1e-9 {
+				t.Errorf("bakiye = %v/%v, beklenen %v/%v", balance.Ledger, balance.Available, tt.wantLedger, tt.wantAvailable)
+			}
+			if _, err := env.holdRepo.GetActiveByTransactionID(ctx, transaction.ID); !errors.Is(err, domain.ErrHoldNotFound) {
+				t.Errorf("aktif blokaj sorgusu = %v, beklenen ErrHoldNotFound", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"sync"
+
+	"transaction-api-w-go/pkg/domain"
+	"transaction-api-w-go/pkg/repository"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// maxReceiptAttempts başka bir instance aynı sequence'ı önce yazdığında yapılacak deneme sayısı
+const maxReceiptAttempts = 3
+
+type ReceiptService struct {
+	receiptRepo     *repository.ReceiptRepository
+	transactionRepo *repository.TransactionRepository
+	// mu aynı instance içindeki makbuz üretimini sıraya alır; instance'lar arası
+	// çakışmalar (user_id, sequence) tekil index'i ve yeniden deneme ile çözülür
+	mu sync.Mutex
+}
+
+func NewReceiptService(receiptRepo *repository.ReceiptRepository, transactionRepo *repository.TransactionRepository) *ReceiptService {
+	return &ReceiptService{
+		receiptRepo:     receiptRepo,
+		transactionRepo: transactionRepo,
+	}
+}
+
+// Issue tamamlanan işlem için kullanıcının zincirine yeni bir makbuz ekler
+func (s *ReceiptService) Issue(ctx context.Context, transaction *domain.Transaction) (*domain.TransactionReceipt, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var err error
+	for attempt := 0; attempt < maxReceiptAttempts; attempt++ {
+		var previous *domain.TransactionReceipt
+		previous, err = s.receiptRepo.GetLatestByUserID(ctx, transaction.UserID.String())
+		if err != nil {
+			return nil, err
+		}
+
+		receipt := domain.NewTransactionReceipt(transaction, previous)
+		if err = s.receiptRepo.Create(ctx, receipt); err == nil {
+			return receipt, nil
+		}
+	}
+	return nil, err
+}
+
+// GetForTransaction işlemin makbuzunu döndürür; başka kullanıcıya ait makbuzlar bulunamadı olarak döner
+func (s *ReceiptService) GetForTransaction(ctx context.Context, userID, transactionID string) (*domain.TransactionReceipt, error) {
+	receipt, err := s.receiptRepo.GetByTransactionID(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	if receipt.UserID.String() != userID {
+		return nil, domain.ErrReceiptNotFound
+	}
+	return receipt, nil
+}
+
+// Verify kullanıcının tüm makbuz zincirini hash'leri yeniden hesaplayarak ve
+// işlem kayıtlarıyla karşılaştırarak doğrular
+func (s *ReceiptService) Verify(ctx context.Context, userID string) (*domain.ReceiptChainVerification, error) {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	receipts, err := s.receiptRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uuid.UUID, 0, len(receipts))
+	for _, receipt := range receipts {
+		ids = append(ids, receipt.TransactionID)
+	}
+	transactions, err := s.transactionRepo.GetByUUIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uuid.UUID]*domain.Transaction, len(transactions))
+	for _, transaction := range transactions {
+		byID[transaction.ID] = transaction
+	}
+
+	// Retention eski işlemleri arşive taşır; makbuzları yerinde kaldığı için işlem arşivden okunur
+	var archivedIDs []uuid.UUID
+	for _, id := range ids {
+		if _, ok := byID[id]; !ok {
+			archivedIDs = append(archivedIDs, id)
+		}
+	}
+	archived, err := s.transactionRepo.GetArchivedByUUIDs(ctx, archivedIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, transaction := range archived {
+		byID[transaction.ID] = transaction
+	}
+
+	result := domain.VerifyReceiptChain(uid, receipts, byID)
+	if !result.Valid {
+		log.Warn().
+			Str("user_id", userID).
+			Int("breaks", len(result.Breaks)).
+			Msg("Makbuz zinciri doğrulanamadı")
+	}
+	return result, nil
+}
+
+// attachReceipt makbuz servisi bağlıysa işlem için makbuz üretip işleme ekler. İşlem
+// zaten kaydedildiği için makbuz hatası işlemi başarısız saymaz, yalnızca loglanır.
+func attachReceipt(ctx context.Context, receipts *ReceiptService, transaction *domain.Transaction) {
+	if receipts == nil || transaction == nil {
+		return
+	}
+	receipt, err := receipts.Issue(ctx, transaction)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Str("transaction_id", transaction.ID.String()).
+			Msg("İşlem makbuzu oluşturulamadı")
+		return
+	}
+	transaction.Receipt = receipt
+}
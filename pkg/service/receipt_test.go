@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"transaction-api-w-go/pkg/domain"
+	"transaction-api-w-go/pkg/repository"
+
+	"github.com/google/uuid"
+)
+
+func TestReceiptServiceVerifyDetectsTampering(t *testing.T) {
+	tests := []struct {
+		name       string
+		tamper     func(t *testing.T, env *testEnv, transactions []*domain.Transaction)
+		wantValid  bool
+		wantBreaks []domain.ReceiptChainBreak
+	}{
+		{name: "değiştirilmemiş zincir geçerli", tamper: func(*testing.T, *testEnv, []*domain.Transaction) {}, wantValid: true},
+		{
+			name: "arşivlenen işlemlerle zincir geçerli kalır",
+			tamper: func(t *testing.T, env *testEnv, transactions []*domain.Transaction) {
+				// Bellek içi veritabanı boş tabloya karşı NOT EXISTS'i yanlış değerlendirdiğinden başka bir
+				// kullanıcının işlemine itiraz açılır
+				otherID := env.createUser(t, 0)
+				disputed := env.createTransaction(t, otherID, domain.TransactionTypeCredit, 10, domain.TransactionStateCompleted)
+				now := time.Now()
+				if err := env.db.Exec("INSERT INTO disputes (id, transaction_id, user_id, reason, status, opened_at, created_at, updated_at) VALUES (?, ?, ?, 'x', ?, ?, ?, ?)",
+					uuid.NewString(), disputed.ID.String(), otherID, domain.DisputeStatusOpen, now, now, now).Error; err != nil {
+					t.Fatalf("itiraz yazılamadı: %v", err)
+				}
+
+				purged, err := repository.NewRetentionRepository(env.db).PurgeTransactions(context.Background(), time.Now().Add(time.Hour), len(transactions), domain.RetentionModeArchive)
+				if err != nil || purged != int64(len(transactions)) {
+					t.Fatalf("PurgeTransactions = %d, %v; beklenen %d", purged, err, len(transactions))
+				}
+			},
+			wantValid: true,
+		},
+		{
+			name: "veritabanında değiştirilen tutar zinciri bozar",
+			tamper: func(t *testing.T, env *testEnv, transactions []*domain.Transaction) {
+				if err := env.db.Model(transactions[1]).Update("amount", 5000).Error; err != nil {
+					t.Fatalf("tutar değiştirilemedi: %v", err)
+				}
+			},
+			wantBreaks: []domain.ReceiptChainBreak{{Sequence: 2, Reason: domain.ReceiptBreakTransactionModified}},
+		},
+		{
+			name: "makbuzda değiştirilen tutar hash'i bozar",
+			tamper: func(t *testing.T, env *testEnv, transactions []*domain.Transaction) {
+				if err := env.db.Model(&domain.TransactionReceipt{}).Where("transaction_id = ?", transactions[0].ID).Update("amount", 5000).Error; err != nil {
+					t.Fatalf("makbuz değiştirilemedi: %v", err)
+				}
+			},
+			wantBreaks: []domain.ReceiptChainBreak{
+				{Sequence: 1, Reason: domain.ReceiptBreakHashMismatch},
+				{Sequence: 1, Reason: domain.ReceiptBreakTransactionModified},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			receipts := NewReceiptService(repository.NewReceiptRepository(env.db), env.transactionRepo)
+			svc := env.transactionService()
+			svc.SetReceipts(receipts)
+			ctx := context.Background()
+			userID := env.createUser(t, 100)
+
+			var transactions []*domain.Transaction
+			for _, amount := range []float64{10, 20, 30} {
+				transaction, err := svc.Credit(ctx, userID, &domain.TransactionRequest{Amount: amount})
+				if err != nil {
+					t.Fatalf("Credit: %v", err)
+				}
+				if transaction.Receipt == nil {
+					t.Fatal("tamamlanan işleme makbuz eklenmedi")
+				}
+				transactions = append(transactions, transaction)
+			}
+			if transactions[1].Receipt.PreviousHash != transactions[0].Receipt.Hash {
+				t.Fatal("makbuz önceki makbuzun hash'ine zincirlenmedi")
+			}
+
+			tt.tamper(t, env, transactions)
+
+			result, err := receipts.Verify(ctx, userID)
+			if err != nil {
+				t.Fatalf("Verify: %v", err)
+			}
+			if result.Valid != tt.wantValid || result.Receipts != len(transactions) {
+				t.Errorf("Valid/Receipts = %v/%d, beklenen %v/%d (%+v)", result.Valid, result.Receipts, tt.wantValid, len(transactions), result.Breaks)
+			}
+			if result.MissingTransactions != 0 {
+				t.Errorf("MissingTransactions = %d, beklenen 0; arşivdeki işlemler de karşılaştırılmalı", result.MissingTransactions)
+			}
+			if len(result.Breaks) != len(tt.wantBreaks) {
+				t.Fatalf("kopmalar = %+v, beklenen %+v", result.Breaks, tt.wantBreaks)
+			}
+			for i, want := range tt.wantBreaks {
+				got := result.Breaks[i]
+				if got.Sequence != want.Sequence || got.Reason != want.Reason {
+					t.Errorf("kopma %d = %+v, beklenen %+v", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestReceiptServiceGetForTransactionChecksOwner(t *testing.T) {
+	env := newTestEnv(t)
+	receipts := NewReceiptService(repository.NewReceiptRepository(env.db), env.transactionRepo)
+	svc := env.transactionService()
+	svc.SetReceipts(receipts)
+	ctx := context.Background()
+	owner := env.createUser(t, 100)
+	other := env.createUser(t, 100)
+
+	transaction, err := svc.Credit(ctx, owner, &domain.TransactionRequest{Amount: 10})
+	if err != nil {
+		t.Fatalf("Credit: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		userID  string
+		wantErr error
+	}{
+		{name: "sahibi görür", userID: owner},
+		{name: "başka kullanıcı göremez", userID: other, wantErr: domain.ErrReceiptNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			receipt, err := receipts.GetForTransaction(ctx, tt.userID, transaction.ID.String())
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetForTransaction = %v, beklenen %v", err, tt.wantErr)
+			}
+			if err == nil && receipt.Hash != transaction.Receipt.Hash {
+				t.Errorf("makbuz hash'i = %s, beklenen %s", receipt.Hash, transaction.Receipt.Hash)
+			}
+		})
+	}
+}
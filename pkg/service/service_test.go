@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"transaction-api-w-go/pkg/database/databasetest"
+	"transaction-api-w-go/pkg/domain"
+	"transaction-api-w-go/pkg/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// testEnv servis testlerinin paylaştığı, bellek içi veritabanına bağlı repository'lerdir
+type testEnv struct {
+	db              *gorm.DB
+	transactionRepo *repository.TransactionRepository
+	balanceRepo     *repository.BalanceRepository
+	holdRepo        *repository.BalanceHoldRepository
+	userRepo        *repository.UserRepository
+	txManager       *repository.TxManager
+}
+
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+	db := databasetest.Open(t)
+	return &testEnv{
+		db:              db,
+		transactionRepo: repository.NewTransactionRepository(db),
+		balanceRepo:     repository.NewBalanceRepository(db),
+		holdRepo:        repository.NewBalanceHoldRepository(db),
+		userRepo:        repository.NewUserRepository(db),
+		txManager:       repository.NewTxManager(db),
+	}
+}
+
+func (e *testEnv) transactionService() *TransactionService {
+	return NewTransactionService(e.transactionRepo, e.balanceRepo, e.holdRepo, e.userRepo, e.txManager)
+}
+
+func (e *testEnv) balanceService() *BalanceService {
+	return NewBalanceService(e.balanceRepo, e.holdRepo, e.transactionRepo, e.txManager)
+}
+
+// createUser kullanıcıyı varsayılan para biriminde amount bakiyeyle oluşturur
+func (e *testEnv) createUser(t *testing.T, amount float64) string {
+	t.Helper()
+	userID := e.createUserWithoutBalance(t)
+	now := time.Now()
+
+	balance := &domain.Balance{
+		ID:        uuid.New(),
+		UserID:    uuid.MustParse(userID),
+		Amount:    amount,
+		Currency:  string(domain.CurrencyTRY),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := e.balanceRepo.Create(context.Background(), balance); err != nil {
+		t.Fatalf("bakiye oluşturulamadı: %v", err)
+	}
+	return userID
+}
+
+// createUserWithoutBalance hiç bakiyesi olmayan bir kullanıcı oluşturur
+func (e *testEnv) createUserWithoutBalance(t *testing.T) string {
+	t.Helper()
+	now := time.Now()
+
+	user := &domain.User{
+		ID:        uuid.New(),
+		Password:  "x",
+		FirstName: "Test",
+		LastName:  "User",
+		Role:      domain.RoleUser,
+		LimitTier: domain.LimitTierBasic,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	user.Email = user.ID.String() + "@example.com"
+	if err := e.userRepo.Create(context.Background(), user); err != nil {
+		t.Fatalf("kullanıcı oluşturulamadı: %v", err)
+	}
+	return user.ID.String()
+}
+
+// balanceAmount kullanıcının kayıtlı bakiyesini döner
+func (e *testEnv) balanceAmount(t *testing.T, userID string) float64 {
+	t.Helper()
+	balance, err := e.balanceRepo.GetByUserIDAndCurrency(context.Background(), userID, string(domain.CurrencyTRY))
+	if err != nil {
+		t.Fatalf("bakiye okunamadı: %v", err)
+	}
+	return balance.Amount
+}
+
+// createTransaction bakiyeye yansıtmadan verilen durumda bir işlem kaydı yazar
+func (e *testEnv) createTransaction(t *testing.T, userID string, transactionType domain.TransactionType, amount float64, status domain.TransactionState) *domain.Transaction {
+	t.Helper()
+	now := time.Now()
+	transaction := &domain.Transaction{
+		ID:        uuid.New(),
+		UserID:    uuid.MustParse(userID),
+		Type:      transactionType,
+		Amount:    amount,
+		Status:    string(status),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := e.transactionRepo.Create(context.Background(), transaction); err != nil {
+		t.Fatalf("işlem yazılamadı: %v", err)
+	}
+	return transaction
+}
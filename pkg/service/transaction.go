@@ -3,158 +3,831 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"transaction-api-w-go/pkg/domain"
+	"transaction-api-w-go/pkg/featureflag"
 	"transaction-api-w-go/pkg/metrics"
+	"transaction-api-w-go/pkg/payment"
 	"transaction-api-w-go/pkg/repository"
 
 	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
 )
 
 type TransactionService struct {
 	transactionRepo *repository.TransactionRepository
 	balanceRepo     *repository.BalanceRepository
+	holdRepo        *repository.BalanceHoldRepository
 	userRepo        *repository.UserRepository
-	stats           *domain.TransactionStats
+	// txManager bakiye kontrolü ile bakiye yazımını aynı veritabanı transaction'ında birleştirir
+	txManager *repository.TxManager
+	stats     *domain.TransactionStats
+	// uniqueReferences açıkken aynı kullanıcı için boş olmayan reference id tekrar kullanılamaz
+	uniqueReferences bool
+	alerts           *BalanceAlertService
+	flags            *featureflag.Service
+	receipts         *ReceiptService
+	limits           domain.TransactionLimitService
+
+	// fees boşsa veya feeAccountID atanmamışsa işlemler ücretsizdir
+	fees         domain.FeeSchedule
+	feeAccountID uuid.UUID
+
+	// screener nil ise işlemler taranmaz; yalnızca screeningThreshold ve üzerindeki tutarlar taranır.
+	// screeningFailOpen açıksa servise ulaşılamadığında işlem onaylanmış sayılır.
+	screener           domain.TransactionScreener
+	screeningThreshold float64
+	screeningFailOpen  bool
+
+	// eventStore nil ise inceleme kuyruğu olayları yazılmaz
+	eventStore domain.EventStore
+	// subscribers işlem event'leri yeniden yayınlandığında bilgilendirilen projeksiyonlardır
+	subscribers []domain.Projection
+
+	// gateway nil ise dış hesaplara transfer yapılamaz
+	gateway payment.PaymentGateway
+
+	// defaultCurrency ilk işlemde bakiyesi olmayan kullanıcılar için açılan bakiyenin para birimi
+	defaultCurrency string
 }
 
 func NewTransactionService(
 	transactionRepo *repository.TransactionRepository,
 	balanceRepo *repository.BalanceRepository,
+	holdRepo *repository.BalanceHoldRepository,
 	userRepo *repository.UserRepository,
+	txManager *repository.TxManager,
 ) *TransactionService {
 	return &TransactionService{
 		transactionRepo: transactionRepo,
 		balanceRepo:     balanceRepo,
+		holdRepo:        holdRepo,
 		userRepo:        userRepo,
+		txManager:       txManager,
 		stats:           &domain.TransactionStats{},
+		defaultCurrency: string(domain.CurrencyTRY),
 	}
 }
 
-func (s *TransactionService) Credit(ctx context.Context, userID string, amount float64, description string) (*domain.Transaction, error) {
-	balance, err := s.balanceRepo.GetByUserID(userID)
+// SetDefaultCurrency ilk işlemde açılan bakiyelerin para birimini ayarlar
+func (s *TransactionService) SetDefaultCurrency(currency string) {
+	if currency != "" {
+		s.defaultCurrency = currency
+	}
+}
+
+// SetUniqueReferences reference id tekilliği kontrolünü açar veya kapatır
+func (s *TransactionService) SetUniqueReferences(enabled bool) {
+	s.uniqueReferences = enabled
+}
+
+// SetBalanceAlerts bakiye değişikliklerinden sonra eşik kurallarını değerlendirecek servisi bağlar
+func (s *TransactionService) SetBalanceAlerts(alerts *BalanceAlertService) {
+	s.alerts = alerts
+}
+
+// SetFeatureFlags kademeli açılan özellikleri (ör. yeni transfer yolu) kontrol eden servisi bağlar
+func (s *TransactionService) SetFeatureFlags(flags *featureflag.Service) {
+	s.flags = flags
+}
+
+// SetReceipts tamamlanan işlemler için hash zincirli makbuz üretecek servisi bağlar
+func (s *TransactionService) SetReceipts(receipts *ReceiptService) {
+	s.receipts = receipts
+}
+
+// SetLimits işlemlerin limit rezervasyonunu ve önizlemedeki limit kontrolünü yapacak servisi bağlar
+func (s *TransactionService) SetLimits(limits domain.TransactionLimitService) {
+	s.limits = limits
+}
+
+// reserveLimit işlem tutarını kullanıcının işlemin yapıldığı bakiyenin para birimindeki limitinden
+// işlem id'siyle düşer; limit kontrolü ve kullanımın işlenmesi limit kaydının satır kilidi altında tek
+// adımda yapılır. İşlem bakiyeye yansımadan başarısız olursa rezervasyon releaseLimit ile iade edilmelidir;
+// taramada incelemeye bekletilen işlemin rezervasyonu ise inceleme sonuçlanana kadar korunur.
+func (s *TransactionService) reserveLimit(ctx context.Context, transaction *domain.Transaction, balance *domain.Balance) error {
+	if s.limits == nil {
+		return nil
+	}
+	return s.limits.ReserveTransactionLimit(ctx, transaction.UserID, domain.Currency(balance.Currency), transaction.Amount, transaction.ID)
+}
+
+// releaseLimit işlemin limit rezervasyonunu iade eder; rezervasyonu olmayan işlemler için hiçbir şey
+// yapmaz. İade edilemezse işlemin sonucu değişmez, hata loglanır.
+func (s *TransactionService) releaseLimit(ctx context.Context, transactionID uuid.UUID) {
+	if s.limits == nil {
+		return
+	}
+	if err := s.limits.ReleaseTransactionLimit(ctx, transactionID); err != nil {
+		log.Error().Err(err).Str("transaction_id", transactionID.String()).Msg("Failed to release transaction limit")
+	}
+}
+
+// SetFees transfer ücret tarifesini ve ücretlerin aktarılacağı hesabı ayarlar
+func (s *TransactionService) SetFees(schedule domain.FeeSchedule, feeAccountID uuid.UUID) {
+	s.fees = schedule
+	s.feeAccountID = feeAccountID
+}
+
+// SetEventStore tarama ve inceleme sonuçlarının yazılacağı event store'u bağlar
+func (s *TransactionService) SetEventStore(eventStore domain.EventStore) {
+	s.eventStore = eventStore
+}
+
+// AddEventSubscriber işlem event'leri yeniden yayınlandığında çağrılacak projeksiyonu ekler
+func (s *TransactionService) AddEventSubscriber(subscriber domain.Projection) {
+	s.subscribers = append(s.subscribers, subscriber)
+}
+
+// SetPaymentGateway dış hesaplara yapılan transferlerin iletileceği ödeme ağ geçidini bağlar
+func (s *TransactionService) SetPaymentGateway(gateway payment.PaymentGateway) {
+	s.gateway = gateway
+}
+
+// SetScreening eşik üzerindeki işlemleri tamamlanmadan önce dolandırıcılık servisine sorar
+func (s *TransactionService) SetScreening(screener domain.TransactionScreener, threshold float64, failOpen bool) {
+	s.screener = screener
+	s.screeningThreshold = threshold
+	s.screeningFailOpen = failOpen
+}
+
+// screenTransaction eşik üzerindeki işlemi dolandırıcılık servisine sorar. Reddedilen işlem failed,
+// bekletilen işlem inceleme kuyruğuna alınmak üzere held olarak nedeniyle kaydedilir; her iki durumda
+// da bakiyeler değişmez ve stop true döner; dönen işlem çağırana iletilir (reference id tekrar ediyorsa
+// mevcut işlemdir). İncelemedeki borçlar kullanılabilir bakiyeden düşüldüğü için bekletilen tutar
+// inceleme sonuçlanana kadar harcanamaz.
+func (s *TransactionService) screenTransaction(ctx context.Context, transaction *domain.Transaction) (screened *domain.Transaction, stop bool, err error) {
+	if s.screener == nil || transaction.Amount < s.screeningThreshold {
+		return nil, false, nil
+	}
+
+	result, err := s.screener.Screen(ctx, transaction)
 	if err != nil {
-		balance = &domain.Balance{
-			ID:        uuid.New(),
-			UserID:    uuid.MustParse(userID),
-			Amount:    0,
-			Currency:  "TRY",
-			CreatedAt: time.Now(),
-			UpdatedAt: time.Now(),
+		if s.screeningFailOpen {
+			log.Warn().Err(err).
+				Str("transaction_id", transaction.ID.String()).
+				Msg("Fraud screening unavailable, approving transaction (fail-open)")
+			return nil, false, nil
 		}
-		if err := s.balanceRepo.Create(balance); err != nil {
-			return nil, err
+		return transaction, true, fmt.Errorf("%w: %v", domain.ErrScreeningUnavailable, err)
+	}
+
+	switch result.Decision {
+	case domain.ScreeningDeny:
+		transaction.Status = string(domain.TransactionStateFailed)
+		err = fmt.Errorf("%w: %s", domain.ErrTransactionDenied, result.Reason)
+	case domain.ScreeningHold:
+		transaction.Status = string(domain.TransactionStateHeld)
+	default:
+		return nil, false, nil
+	}
+
+	transaction.StatusReason = result.Reason
+	if existing, createErr := s.createTransaction(ctx, transaction); createErr != nil {
+		return existing, true, createErr
+	}
+	s.emit(ctx, domain.NewTransactionStateChangedEvent(transaction,
+		domain.TransactionStatePending, domain.TransactionState(transaction.Status), result.Reason))
+	return transaction, true, err
+}
+
+// transferFee gönderenin limit seviyesine göre transfer ücretini hesaplar; ücret hesabının
+// kendi transferleri ücretsizdir
+func (s *TransactionService) transferFee(ctx context.Context, fromUserID string, amount float64) (float64, error) {
+	if s.fees.IsEmpty() || s.feeAccountID == uuid.Nil || fromUserID == s.feeAccountID.String() {
+		return 0, nil
+	}
+
+	var tier domain.LimitTier
+	if s.userRepo != nil {
+		userID, err := uuid.Parse(fromUserID)
+		if err != nil {
+			return 0, err
 		}
+		if tier, err = s.userRepo.GetLimitTier(ctx, userID); err != nil {
+			return 0, err
+		}
+	}
+	return s.fees.Fee(tier, domain.TransactionTypeTransfer, amount), nil
+}
+
+// feeBalance ücretin aktarılacağı hesabın bakiyesini yükler. Alıcı ücret hesabının kendisiyse
+// aynı bakiye nesnesi kullanılır; aksi halde iki kopya birbirinin güncellemesini ezerdi
+func (s *TransactionService) feeBalance(ctx context.Context, toUserID string, toBalance *domain.Balance) (*domain.Balance, error) {
+	if toUserID == s.feeAccountID.String() {
+		return toBalance, nil
+	}
+	balance, err := s.balanceRepo.GetByUserIDAndCurrency(ctx, s.feeAccountID.String(), s.defaultCurrency)
+	if errors.Is(err, domain.ErrBalanceNotFound) {
+		return nil, domain.ErrFeeAccountNotFound
+	}
+	return balance, err
+}
+
+// newFeeTransaction transfer için gönderenden ücret hesabına giden ücret kaydını oluşturur
+func (s *TransactionService) newFeeTransaction(transfer *domain.Transaction, fee float64) *domain.Transaction {
+	feeAccountID := s.feeAccountID
+	return &domain.Transaction{
+		ID:             uuid.New(),
+		UserID:         transfer.UserID,
+		Type:           domain.TransactionTypeFee,
+		Amount:         fee,
+		Description:    "Transfer fee",
+		Metadata:       domain.Metadata{"transfer_id": transfer.ID.String()},
+		CounterpartyID: &feeAccountID,
+		BalanceAfter:   transfer.BalanceAfter,
+		Status:         string(domain.TransactionStateCompleted),
+		CreatedAt:      transfer.CreatedAt,
+		UpdatedAt:      transfer.UpdatedAt,
+	}
+}
+
+func (s *TransactionService) evaluateAlerts(ctx context.Context, balances ...*domain.Balance) {
+	if s.alerts == nil {
+		return
+	}
+	for _, balance := range balances {
+		s.alerts.Evaluate(ctx, balance)
+	}
+}
+
+// markReference işlemi UNIQUE_REFERENCE_IDS açıkken veritabanındaki (user_id, reference_id) tekillik
+// indeksine dahil eder; tekrar eden istekler böylece yarış olmadan yazma sırasında reddedilir
+func (s *TransactionService) markReference(transaction *domain.Transaction) {
+	transaction.ReferenceUnique = s.uniqueReferences && transaction.ReferenceID != ""
+}
+
+// existingReference yazma aynı reference id nedeniyle reddedildiyse daha önce oluşturulmuş işlemi
+// domain.ErrTransactionAlreadyExists ile birlikte döndürür; diğer hataları olduğu gibi iletir
+func (s *TransactionService) existingReference(ctx context.Context, transaction *domain.Transaction, err error) (*domain.Transaction, error) {
+	if !errors.Is(err, domain.ErrTransactionAlreadyExists) {
+		return nil, err
 	}
+	existing, getErr := s.transactionRepo.GetByUserIDAndReferenceID(ctx, transaction.UserID.String(), transaction.ReferenceID)
+	if getErr != nil {
+		return nil, getErr
+	}
+	return existing, err
+}
 
-	transaction := &domain.Transaction{
+// createTransaction işlemi kaydeder; aynı reference id ile daha önce (eşzamanlı bir istek dahil)
+// oluşturulmuş bir işlem varsa mevcut işlemi döndürür
+func (s *TransactionService) createTransaction(ctx context.Context, transaction *domain.Transaction) (*domain.Transaction, error) {
+	s.markReference(transaction)
+	if err := s.transactionRepo.Create(ctx, transaction); err != nil {
+		return s.existingReference(ctx, transaction, err)
+	}
+	return nil, nil
+}
+
+// checkAvailable bakiyenin aktif blokajlar ve bekleyen borçlar düşüldükten sonra amount kadar borcu
+// karşılayıp karşılamadığını kontrol eder
+func (s *TransactionService) checkAvailable(ctx context.Context, balance *domain.Balance, amount float64) error {
+	available, err := availableAmount(ctx, s.holdRepo, s.transactionRepo, balance)
+	if err != nil {
+		return err
+	}
+	return balance.CheckWithdrawal(available, amount)
+}
+
+func (s *TransactionService) Credit(ctx context.Context, userID string, req *domain.TransactionRequest) (transaction *domain.Transaction, err error) {
+	defer recordTransactionOutcome(domain.TransactionTypeCredit, &err)
+
+	if err := req.Metadata.Validate(); err != nil {
+		return nil, err
+	}
+	if req.ExternalAccount != nil {
+		return s.depositExternal(ctx, userID, req)
+	}
+
+	amount := req.Amount
+	balance, err := s.getOrCreateBalance(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	transaction = &domain.Transaction{
 		ID:           uuid.New(),
 		UserID:       uuid.MustParse(userID),
 		Type:         domain.TransactionTypeCredit,
 		Amount:       amount,
-		Description:  description,
+		Description:  req.Description,
+		ReferenceID:  req.ReferenceID,
+		Category:     req.Category,
+		Tags:         req.Tags,
+		Metadata:     req.Metadata,
 		BalanceAfter: balance.Amount + amount,
+		Status:       string(domain.TransactionStateCompleted),
 		CreatedAt:    time.Now(),
 		UpdatedAt:    time.Now(),
 	}
 
-	if err := s.transactionRepo.Create(ctx, transaction); err != nil {
+	if err := s.reserveLimit(ctx, transaction, balance); err != nil {
 		return nil, err
 	}
+	if screened, stop, err := s.screenTransaction(ctx, transaction); stop {
+		if err != nil {
+			s.releaseLimit(ctx, transaction.ID)
+		}
+		return screened, err
+	}
 
-	balance.Amount += amount
-	if err := s.balanceRepo.Update(balance); err != nil {
-		return nil, err
+	s.markReference(transaction)
+	err = s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		if err := lockBalances(ctx, s.balanceRepo, balance); err != nil {
+			return err
+		}
+		transaction.BalanceAfter = balance.Amount + amount
+		if err := s.transactionRepo.Create(ctx, transaction); err != nil {
+			return err
+		}
+		balance.Amount += amount
+		return s.balanceRepo.Update(ctx, balance)
+	})
+	if err != nil {
+		s.releaseLimit(ctx, transaction.ID)
+		return s.existingReference(ctx, transaction, err)
 	}
+	s.evaluateAlerts(ctx, balance)
+	attachReceipt(ctx, s.receipts, transaction)
 
 	return transaction, nil
 }
 
-func (s *TransactionService) Debit(ctx context.Context, userID string, amount float64, description string) (*domain.Transaction, error) {
-	balance, err := s.balanceRepo.GetByUserID(userID)
+// getOrCreateBalance işlemi yapan kullanıcının bakiyesini yükler; ilk işlemde bakiye yoksa varsayılan
+// para biriminde sıfır bakiye açılır. Böylece bakiyesi olmayan kullanıcının borç ve transferleri
+// bulunamadı yerine yetersiz bakiye hatası alır.
+func (s *TransactionService) getOrCreateBalance(ctx context.Context, userID string) (*domain.Balance, error) {
+	return s.balanceRepo.GetOrCreate(ctx, userID, s.defaultCurrency)
+}
+
+// newZeroBalance bakiyesi olmayan kullanıcının ilk işlemde sahip olacağı, kaydedilmemiş sıfır bakiyedir
+func (s *TransactionService) newZeroBalance(userID string) (*domain.Balance, error) {
+	uid, err := uuid.Parse(userID)
 	if err != nil {
+		return nil, domain.ErrUserNotFound
+	}
+	return &domain.Balance{UserID: uid, Currency: s.defaultCurrency}, nil
+}
+
+// recipientBalance transfer alıcısının bakiyesini yükler. Alıcının bakiyesi yoksa önce kullanıcının
+// var olduğu doğrulanır; olmayan bir kullanıcı adına bakiye açılmaz.
+func (s *TransactionService) recipientBalance(ctx context.Context, toUserID string) (*domain.Balance, error) {
+	balance, err := s.balanceRepo.GetByUserIDAndCurrency(ctx, toUserID, s.defaultCurrency)
+	if !errors.Is(err, domain.ErrBalanceNotFound) {
+		return balance, err
+	}
+	if _, err := s.userRepo.GetByID(ctx, toUserID); err != nil {
 		return nil, err
 	}
+	return s.getOrCreateBalance(ctx, toUserID)
+}
 
-	if balance.Amount < amount {
-		return nil, errors.New("insufficient balance")
+func (s *TransactionService) Debit(ctx context.Context, userID string, req *domain.TransactionRequest) (transaction *domain.Transaction, err error) {
+	defer recordTransactionOutcome(domain.TransactionTypeDebit, &err)
+
+	if err := req.Metadata.Validate(); err != nil {
+		return nil, err
+	}
+	if req.ExternalAccount != nil {
+		return s.withdrawExternal(ctx, userID, domain.TransactionTypeDebit, req)
+	}
+
+	amount := req.Amount
+	balance, err := s.getOrCreateBalance(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkAvailable(ctx, balance, amount); err != nil {
+		return nil, err
 	}
 
-	transaction := &domain.Transaction{
+	transaction = &domain.Transaction{
 		ID:           uuid.New(),
 		UserID:       uuid.MustParse(userID),
 		Type:         domain.TransactionTypeDebit,
 		Amount:       amount,
-		Description:  description,
+		Description:  req.Description,
+		ReferenceID:  req.ReferenceID,
+		Category:     req.Category,
+		Tags:         req.Tags,
+		Metadata:     req.Metadata,
 		BalanceAfter: balance.Amount - amount,
+		Status:       string(domain.TransactionStateCompleted),
 		CreatedAt:    time.Now(),
 		UpdatedAt:    time.Now(),
 	}
 
-	if err := s.transactionRepo.Create(ctx, transaction); err != nil {
+	if err := s.reserveLimit(ctx, transaction, balance); err != nil {
 		return nil, err
 	}
+	if screened, stop, err := s.screenTransaction(ctx, transaction); stop {
+		if err != nil {
+			s.releaseLimit(ctx, transaction.ID)
+		}
+		return screened, err
+	}
 
-	balance.Amount -= amount
-	if err := s.balanceRepo.Update(balance); err != nil {
-		return nil, err
+	// Kullanılabilir bakiye kilit altında yeniden kontrol edilir; eşzamanlı borçlar bakiyeyi aşamaz
+	s.markReference(transaction)
+	err = s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		if err := lockBalances(ctx, s.balanceRepo, balance); err != nil {
+			return err
+		}
+		if err := s.checkAvailable(ctx, balance, amount); err != nil {
+			return err
+		}
+		transaction.BalanceAfter = balance.Amount - amount
+		if err := s.transactionRepo.Create(ctx, transaction); err != nil {
+			return err
+		}
+		balance.Amount -= amount
+		return s.balanceRepo.Update(ctx, balance)
+	})
+	if err != nil {
+		s.releaseLimit(ctx, transaction.ID)
+		return s.existingReference(ctx, transaction, err)
 	}
+	s.evaluateAlerts(ctx, balance)
+	attachReceipt(ctx, s.receipts, transaction)
 
 	return transaction, nil
 }
 
-func (s *TransactionService) Transfer(ctx context.Context, fromUserID, toUserID string, amount float64, description string) (*domain.Transaction, error) {
-	fromBalance, err := s.balanceRepo.GetByUserID(fromUserID)
+func (s *TransactionService) Transfer(ctx context.Context, fromUserID string, req *domain.TransferRequest) (transaction *domain.Transaction, err error) {
+	defer recordTransactionOutcome(domain.TransactionTypeTransfer, &err)
+
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	if err := req.Metadata.Validate(); err != nil {
+		return nil, err
+	}
+	if req.IsExternal() {
+		return s.transferExternal(ctx, fromUserID, req)
+	}
+
+	amount := req.Amount
+	toUserID := req.ToUserID.String()
+	fromBalance, err := s.getOrCreateBalance(ctx, fromUserID)
 	if err != nil {
 		return nil, err
 	}
 
-	if fromBalance.Amount < amount {
-		return nil, errors.New("insufficient balance")
+	fee, err := s.transferFee(ctx, fromUserID, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkAvailable(ctx, fromBalance, amount+fee); err != nil {
+		return nil, err
 	}
 
-	toBalance, err := s.balanceRepo.GetByUserID(toUserID)
+	toBalance, err := s.recipientBalance(ctx, toUserID)
 	if err != nil {
 		return nil, err
 	}
 
-	transaction := &domain.Transaction{
-		ID:           uuid.New(),
-		UserID:       uuid.MustParse(fromUserID),
-		Type:         domain.TransactionTypeTransfer,
-		Amount:       amount,
-		Description:  description,
-		BalanceAfter: fromBalance.Amount - amount,
-		CreatedAt:    time.Now(),
-		UpdatedAt:    time.Now(),
+	var feeBalance *domain.Balance
+	if fee > 0 {
+		if feeBalance, err = s.feeBalance(ctx, toUserID, toBalance); err != nil {
+			return nil, err
+		}
 	}
 
-	if err := s.transactionRepo.Create(ctx, transaction); err != nil {
+	transaction = &domain.Transaction{
+		ID:             uuid.New(),
+		UserID:         uuid.MustParse(fromUserID),
+		Type:           domain.TransactionTypeTransfer,
+		Amount:         amount,
+		Description:    req.Description,
+		ReferenceID:    req.ReferenceID,
+		Category:       req.Category,
+		Tags:           req.Tags,
+		Metadata:       req.Metadata,
+		CounterpartyID: &req.ToUserID,
+		BalanceAfter:   fromBalance.Amount - amount - fee,
+		Status:         string(domain.TransactionStateCompleted),
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+	}
+
+	if err := s.reserveLimit(ctx, transaction, fromBalance); err != nil {
 		return nil, err
 	}
+	if screened, stop, err := s.screenTransaction(ctx, transaction); stop {
+		if err != nil {
+			s.releaseLimit(ctx, transaction.ID)
+		}
+		return screened, err
+	}
+
+	var transferFee *domain.TransferFee
+	if fee > 0 {
+		transferFee = &domain.TransferFee{
+			Transaction: s.newFeeTransaction(transaction, fee),
+			Balance:     feeBalance,
+		}
+	}
+
+	// Kullanılabilir bakiye kilit altında yeniden kontrol edilir; eşzamanlı borçlar bakiyeyi aşamaz
+	newPath := s.flags.IsEnabled(ctx, featureflag.FlagNewTransferPath, fromUserID)
+	s.markReference(transaction)
+	err = s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		if err := lockBalances(ctx, s.balanceRepo, fromBalance, toBalance, feeBalance); err != nil {
+			return err
+		}
+		if err := s.checkAvailable(ctx, fromBalance, amount+fee); err != nil {
+			return err
+		}
+		transaction.BalanceAfter = fromBalance.Amount - amount - fee
+		if transferFee != nil {
+			transferFee.Transaction.BalanceAfter = transaction.BalanceAfter
+		}
+
+		if newPath {
+			return s.applyTransfer(ctx, transaction, fromBalance, toBalance, transferFee)
+		}
+		return s.writeTransfer(ctx, transaction, fromBalance, toBalance, transferFee)
+	})
+	if err != nil {
+		s.releaseLimit(ctx, transaction.ID)
+		return s.existingReference(ctx, transaction, err)
+	}
+	s.evaluateAlerts(ctx, fromBalance, toBalance)
+	attachReceipt(ctx, s.receipts, transaction)
+
+	return transaction, nil
+}
+
+// writeTransfer işlem kaydını, iki bakiyeyi ve varsa ücreti sırayla yazar
+func (s *TransactionService) writeTransfer(ctx context.Context, transaction *domain.Transaction, fromBalance, toBalance *domain.Balance, fee *domain.TransferFee) error {
+	if err := s.transactionRepo.Create(ctx, transaction); err != nil {
+		return err
+	}
+	if fee != nil {
+		if err := s.transactionRepo.Create(ctx, fee.Transaction); err != nil {
+			return err
+		}
+	}
+
+	fromBalance.Amount -= transaction.Amount
+	if fee != nil {
+		fromBalance.Amount -= fee.Transaction.Amount
+	}
+	if err := s.balanceRepo.Update(ctx, fromBalance); err != nil {
+		return err
+	}
 
-	fromBalance.Amount -= amount
-	if err := s.balanceRepo.Update(fromBalance); err != nil {
+	toBalance.Amount += transaction.Amount
+	if err := s.balanceRepo.Update(ctx, toBalance); err != nil {
+		return err
+	}
+
+	if fee != nil {
+		fee.Balance.Amount += fee.Transaction.Amount
+		return s.balanceRepo.Update(ctx, fee.Balance)
+	}
+	return nil
+}
+
+// applyTransfer işlem kaydını, iki bakiyeyi ve varsa ücreti atomik olarak yazar (new_transfer_path)
+func (s *TransactionService) applyTransfer(ctx context.Context, transaction *domain.Transaction, fromBalance, toBalance *domain.Balance, fee *domain.TransferFee) error {
+	fromBalance.Amount -= transaction.Amount
+	toBalance.Amount += transaction.Amount
+	if fee != nil {
+		fromBalance.Amount -= fee.Transaction.Amount
+		fee.Balance.Amount += fee.Transaction.Amount
+	}
+	return s.transactionRepo.ApplyTransfer(ctx, transaction, fromBalance, toBalance, fee)
+}
+
+// Preview işlemin bakiye, para birimi, alıcı ve limit kontrollerinden geçip geçmeyeceğini
+// hiçbir kayıt değiştirmeden raporlar; yalnızca altyapı hataları error olarak döner
+func (s *TransactionService) Preview(ctx context.Context, userID string, req *domain.TransactionPreviewRequest) (*domain.TransactionPreview, error) {
+	req.Type = domain.TransactionType(strings.ToUpper(string(req.Type)))
+	preview := domain.NewTransactionPreview(req)
+
+	switch req.Type {
+	case domain.TransactionTypeCredit, domain.TransactionTypeDebit, domain.TransactionTypeTransfer:
+	default:
+		preview.Reject(domain.ErrInvalidTransactionType)
+		return preview.Finalize(), nil
+	}
+	if req.Amount <= 0 {
+		preview.Reject(domain.ErrInvalidAmount)
+	}
+
+	balance, err := s.balanceRepo.GetByUserIDAndCurrency(ctx, userID, s.defaultCurrency)
+	if errors.Is(err, domain.ErrBalanceNotFound) {
+		// Önizleme bakiye açmaz; ilk işlemde açılacak sıfır bakiye üzerinden hesaplanır
+		balance, err = s.newZeroBalance(userID)
+	}
+	if err != nil {
 		return nil, err
 	}
 
-	toBalance.Amount += amount
-	if err := s.balanceRepo.Update(toBalance); err != nil {
+	if preview.Currency == "" {
+		preview.Currency = domain.Currency(balance.Currency)
+	}
+	switch {
+	case !preview.Currency.IsSupported():
+		preview.Reject(domain.ErrCurrencyNotSupported)
+	case balance.Currency != "" && preview.Currency != domain.Currency(balance.Currency):
+		preview.Reject(domain.ErrCurrencyMismatch)
+	}
+
+	if req.Type == domain.TransactionTypeCredit {
+		return preview.Finalize(), nil
+	}
+
+	available, err := availableAmount(ctx, s.holdRepo, s.transactionRepo, balance)
+	if err != nil {
 		return nil, err
 	}
+	preview.Available = &available
 
-	return transaction, nil
+	required := req.Amount
+	if req.Type == domain.TransactionTypeTransfer {
+		fee, err := s.transferFee(ctx, userID, req.Amount)
+		if err != nil {
+			return nil, err
+		}
+		preview.Fee = fee
+		required += fee
+	}
+	if err := balance.CheckWithdrawal(available, required); err != nil {
+		preview.Reject(err)
+	}
+
+	if req.Type == domain.TransactionTypeTransfer {
+		if err := s.previewRecipient(ctx, userID, req.ToUserID); err != nil {
+			if !errors.Is(err, domain.ErrRecipientRequired) && !errors.Is(err, domain.ErrSelfTransfer) &&
+				!errors.Is(err, domain.ErrUserNotFound) {
+				return nil, err
+			}
+			preview.Reject(err)
+		}
+	}
+
+	if s.limits != nil && preview.Currency.IsSupported() {
+		violations, err := s.limits.PreviewTransactionLimit(ctx, balance.UserID, preview.Currency, req.Amount)
+		if err != nil {
+			return nil, err
+		}
+		for _, violation := range violations {
+			preview.Reject(violation)
+		}
+	}
+
+	return preview.Finalize(), nil
+}
+
+func (s *TransactionService) previewRecipient(ctx context.Context, userID string, toUserID *uuid.UUID) error {
+	if toUserID == nil || *toUserID == uuid.Nil {
+		return domain.ErrRecipientRequired
+	}
+	if toUserID.String() == userID {
+		return domain.ErrSelfTransfer
+	}
+
+	// Bakiyesi henüz olmayan alıcıya transfer ilk işlemde bakiye açar; yalnızca kullanıcının varlığı aranır
+	_, err := s.userRepo.GetByID(ctx, toUserID.String())
+	return err
+}
+
+func (s *TransactionService) GetHistory(ctx context.Context, userID string, filter domain.TransactionFilter) ([]*domain.Transaction, error) {
+	return s.transactionRepo.GetByUserIDWithFilter(ctx, userID, filter)
+}
+
+// GetByReferenceID kullanıcının verilen reference id ile eşleşen işlemlerini döndürür
+func (s *TransactionService) GetByReferenceID(ctx context.Context, userID, referenceID string) ([]*domain.Transaction, error) {
+	transactions, err := s.transactionRepo.ListByReferenceID(ctx, userID, referenceID)
+	if err != nil {
+		return nil, err
+	}
+	if len(transactions) == 0 {
+		return nil, domain.ErrTransactionNotFound
+	}
+	return transactions, nil
 }
 
-func (s *TransactionService) GetHistory(ctx context.Context, userID uint) ([]*domain.Transaction, error) {
-	return s.transactionRepo.GetByUserID(ctx, userID)
+// Search kullanıcının (userID boşsa tüm kullanıcıların) işlemlerini filtreleyerek arar
+func (s *TransactionService) Search(ctx context.Context, userID string, filter domain.TransactionFilter) (*domain.TransactionSearchResult, error) {
+	if filter.MinAmount != nil && filter.MaxAmount != nil && *filter.MinAmount > *filter.MaxAmount {
+		return nil, domain.ErrInvalidSearchFilter
+	}
+	if filter.StartDate != nil && filter.EndDate != nil && filter.StartDate.After(*filter.EndDate) {
+		return nil, domain.ErrInvalidSearchFilter
+	}
+	if filter.SortOrder != "" && filter.SortOrder != domain.SortOrderAsc && filter.SortOrder != domain.SortOrderDesc {
+		return nil, domain.ErrInvalidSearchFilter
+	}
+
+	return s.transactionRepo.Search(ctx, userID, filter)
 }
 
 func (s *TransactionService) GetByID(ctx context.Context, transactionID uint) (*domain.Transaction, error) {
 	return s.transactionRepo.GetByID(ctx, transactionID)
 }
 
+// GetReceipt kullanıcının işlemine ait makbuzu döndürür
+func (s *TransactionService) GetReceipt(ctx context.Context, userID string, transactionID uuid.UUID) (*domain.TransactionReceipt, error) {
+	if s.receipts == nil {
+		return nil, domain.ErrReceiptNotFound
+	}
+	return s.receipts.GetForTransaction(ctx, userID, transactionID.String())
+}
+
+// VerifyReceipts kullanıcının makbuz zincirini baştan doğrular
+func (s *TransactionService) VerifyReceipts(ctx context.Context, userID string) (*domain.ReceiptChainVerification, error) {
+	if s.receipts == nil {
+		return nil, domain.ErrReceiptNotFound
+	}
+	return s.receipts.Verify(ctx, userID)
+}
+
+// GetUserTransaction işlemi uuid ile getirir; başka kullanıcıya ait işlemler bulunamadı olarak döner
+func (s *TransactionService) GetUserTransaction(ctx context.Context, userID string, transactionID uuid.UUID) (*domain.Transaction, error) {
+	transaction, err := s.transactionRepo.GetByUUID(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	if transaction.UserID.String() != userID &&
+		(transaction.CounterpartyID == nil || transaction.CounterpartyID.String() != userID) {
+		return nil, domain.ErrTransactionNotFound
+	}
+	return transaction, nil
+}
+
+// GetTransactionEvents işlemin event zaman çizelgesini sürüm sırasıyla döndürür; kullanıcı yalnızca
+// tarafı olduğu işlemlerin event'lerini görebilir
+func (s *TransactionService) GetTransactionEvents(ctx context.Context, userID string, transactionID uuid.UUID) ([]domain.Event, error) {
+	if _, err := s.GetUserTransaction(ctx, userID, transactionID); err != nil {
+		return nil, err
+	}
+	if s.eventStore == nil {
+		return []domain.Event{}, nil
+	}
+
+	return s.orderedEvents(ctx, transactionID)
+}
+
+// RepublishTransactionEvents işlemin kayıtlı event'lerini sürüm sırasıyla abonelere yeniden iletir ve
+// iletilen event sayısını döner. Event store'a yeni kayıt yazılmaz ve bakiyelere dokunulmaz; event'ler
+// özgün ID'leriyle iletildiğinden aboneler tekrarları ayıklayabilir ve işlem tekrar tekrar çağrılabilir.
+// Kayıtlı abone yoksa ErrNoEventSubscribers döner.
+func (s *TransactionService) RepublishTransactionEvents(ctx context.Context, transactionID uuid.UUID) (int, error) {
+	if _, err := s.transactionRepo.GetByUUID(ctx, transactionID); err != nil {
+		return 0, err
+	}
+	if len(s.subscribers) == 0 {
+		return 0, domain.ErrNoEventSubscribers
+	}
+	if s.eventStore == nil {
+		return 0, nil
+	}
+
+	events, err := s.orderedEvents(ctx, transactionID)
+	if err != nil {
+		return 0, err
+	}
+	for _, event := range events {
+		for _, subscriber := range s.subscribers {
+			if err := subscriber.Handle(ctx, event); err != nil {
+				return 0, fmt.Errorf("subscriber %s failed on event %s: %w", subscriber.Name(), event.GetID(), err)
+			}
+		}
+	}
+
+	log.Info().
+		Str("transaction_id", transactionID.String()).
+		Int("events", len(events)).
+		Int("subscribers", len(s.subscribers)).
+		Msg("Transaction events republished")
+	return len(events), nil
+}
+
+// orderedEvents işlemin event'lerini sürüm, eşitlikte zaman sırasıyla döner
+func (s *TransactionService) orderedEvents(ctx context.Context, transactionID uuid.UUID) ([]domain.Event, error) {
+	events, err := s.eventStore.GetEvents(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		if events[i].GetVersion() != events[j].GetVersion() {
+			return events[i].GetVersion() < events[j].GetVersion()
+		}
+		return events[i].GetTimestamp().Before(events[j].GetTimestamp())
+	})
+	return events, nil
+}
+
 func (s *TransactionService) ProcessTransaction(ctx context.Context, transactionID uint) error {
 	start := time.Now()
 	defer func() {
@@ -188,3 +861,41 @@ func (s *TransactionService) CreateTransaction(ctx context.Context, transaction
 	metrics.TransactionAmount.WithLabelValues("create").Observe(transaction.Amount)
 	return nil
 }
+
+// recordTransactionOutcome SLO metrikleri için işlem denemesini ve sonucunu kaydeder
+func recordTransactionOutcome(txType domain.TransactionType, err *error) {
+	label := string(txType)
+	metrics.TransactionAttemptsTotal.WithLabelValues(label).Inc()
+
+	if *err != nil {
+		metrics.TransactionFailuresTotal.WithLabelValues(label, transactionFailureReason(*err)).Inc()
+		return
+	}
+	metrics.TransactionSuccessesTotal.WithLabelValues(label).Inc()
+}
+
+// transactionFailureReason hatayı düşük kardinaliteli bir metrik etiketine dönüştürür
+func transactionFailureReason(err error) string {
+	switch {
+	case errors.Is(err, domain.ErrInsufficientBalance), errors.Is(err, domain.ErrInsufficientFunds):
+		return "insufficient_balance"
+	case errors.Is(err, domain.ErrTransactionAlreadyExists):
+		return "duplicate_reference"
+	case errors.Is(err, domain.ErrMetadataTooLarge), errors.Is(err, domain.ErrInvalidMetadata):
+		return "invalid_metadata"
+	case errors.Is(err, domain.ErrUserNotFound):
+		return "not_found"
+	case errors.Is(err, domain.ErrTransactionDenied):
+		return "screening_denied"
+	case errors.Is(err, domain.ErrScreeningUnavailable):
+		return "screening_unavailable"
+	case errors.Is(err, domain.ErrInvalidExternalAccount), errors.Is(err, domain.ErrInvalidTransferDestination):
+		return "invalid_destination"
+	case errors.Is(err, domain.ErrPaymentGatewayUnavailable):
+		return "gateway_unavailable"
+	case errors.Is(err, domain.ErrExternalTransferRejected):
+		return "gateway_rejected"
+	default:
+		return "internal"
+	}
+}
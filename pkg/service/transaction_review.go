@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+
+	"transaction-api-w-go/pkg/domain"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// DefaultReviewQueueLimit inceleme kuyruğu listesinde varsayılan olarak dönen işlem sayısı
+const DefaultReviewQueueLimit = 100
+
+// ListHeldTransactions inceleme kuyruğundaki işlemleri en eskiden başlayarak döndürür
+func (s *TransactionService) ListHeldTransactions(ctx context.Context, limit int) ([]*domain.Transaction, error) {
+	if limit <= 0 {
+		limit = DefaultReviewQueueLimit
+	}
+	return s.transactionRepo.ListByStatus(ctx, domain.TransactionStateHeld, limit)
+}
+
+// ApproveHeldTransaction incelemedeki işlemi tamamlar ve bakiyelere uygular. Borç ve transferlerde
+// bakiye onay anında yeniden kontrol edilir; transfer ücreti de bu noktada alınır.
+func (s *TransactionService) ApproveHeldTransaction(ctx context.Context, reviewerID, transactionID uuid.UUID) (*domain.Transaction, error) {
+	transaction, err := s.transactionRepo.GetByUUID(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	if !transaction.IsHeld() {
+		return nil, domain.ErrTransactionNotHeld
+	}
+
+	if transaction.IsExternal() {
+		return s.approveExternalTransaction(ctx, reviewerID, transaction)
+	}
+
+	userID := transaction.UserID.String()
+	balance, err := s.balanceRepo.GetByUserIDAndCurrency(ctx, userID, s.defaultCurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	var toBalance, feeBalance *domain.Balance
+	var feeAmount float64
+	switch transaction.Type {
+	case domain.TransactionTypeCredit, domain.TransactionTypeDebit:
+	case domain.TransactionTypeTransfer:
+		if transaction.CounterpartyID == nil {
+			return nil, domain.ErrInvalidState
+		}
+		if feeAmount, err = s.transferFee(ctx, userID, transaction.Amount); err != nil {
+			return nil, err
+		}
+		toUserID := transaction.CounterpartyID.String()
+		if toBalance, err = s.balanceRepo.GetByUserIDAndCurrency(ctx, toUserID, s.defaultCurrency); err != nil {
+			return nil, err
+		}
+		if feeAmount > 0 {
+			if feeBalance, err = s.feeBalance(ctx, toUserID, toBalance); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		return nil, domain.ErrInvalidState
+	}
+
+	if err := transaction.UpdateState(domain.TransactionStateCompleted); err != nil {
+		return nil, err
+	}
+
+	// Bakiye kilit altında yeniden kontrol edilir; onay ile eşzamanlı borçlar bakiyeyi aşamaz
+	balances := []*domain.Balance{balance}
+	err = s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		if err := lockBalances(ctx, s.balanceRepo, balance, toBalance, feeBalance); err != nil {
+			return err
+		}
+		if transaction.Type == domain.TransactionTypeCredit {
+			balance.Amount += transaction.Amount
+			transaction.BalanceAfter = balance.Amount
+			return s.transactionRepo.ResolveHeld(ctx, transaction, nil, balance)
+		}
+
+		available, err := availableAmount(ctx, s.holdRepo, s.transactionRepo, balance)
+		if err != nil {
+			return err
+		}
+		// İncelemedeki işlemin kendisi bekleyen borçlara dahil olduğu için tutarı geri eklenir
+		if err := balance.CheckWithdrawal(available+transaction.Amount, transaction.Amount+feeAmount); err != nil {
+			return err
+		}
+		balance.Amount -= transaction.Amount + feeAmount
+		transaction.BalanceAfter = balance.Amount
+
+		var fee *domain.TransferFee
+		if toBalance != nil {
+			toBalance.Amount += transaction.Amount
+			balances = append(balances, toBalance)
+		}
+		if feeBalance != nil {
+			feeBalance.Amount += feeAmount
+			fee = &domain.TransferFee{
+				Transaction: s.newFeeTransaction(transaction, feeAmount),
+				Balance:     feeBalance,
+			}
+		}
+		return s.transactionRepo.ResolveHeld(ctx, transaction, fee, balances...)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.emitReview(ctx, transaction, reviewerID, transaction.StatusReason)
+	s.evaluateAlerts(ctx, balances...)
+	attachReceipt(ctx, s.receipts, transaction)
+
+	return transaction, nil
+}
+
+// RejectHeldTransaction incelemedeki işlemi nedeniyle birlikte failed durumuna alır. Held işlemler
+// bakiyeye yansımadığı için geri alınacak bir hareket yoktur; blokajlı tutar kendiliğinden serbest kalır,
+// işlem oluşturulurken düşülen limit kullanımı iade edilir.
+func (s *TransactionService) RejectHeldTransaction(ctx context.Context, reviewerID, transactionID uuid.UUID, reason string) (*domain.Transaction, error) {
+	transaction, err := s.transactionRepo.GetByUUID(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	if !transaction.IsHeld() {
+		return nil, domain.ErrTransactionNotHeld
+	}
+
+	transaction.StatusReason = reason
+	if err := transaction.UpdateState(domain.TransactionStateFailed); err != nil {
+		return nil, err
+	}
+	if err := s.transactionRepo.ResolveHeld(ctx, transaction, nil); err != nil {
+		return nil, err
+	}
+	s.releaseLimit(ctx, transaction.ID)
+
+	s.emitReview(ctx, transaction, reviewerID, reason)
+	return transaction, nil
+}
+
+// emitReview inceleme sonucunu, işlemi sonuçlandıran admin ile birlikte yazar
+func (s *TransactionService) emitReview(ctx context.Context, transaction *domain.Transaction, reviewerID uuid.UUID, reason string) {
+	event := domain.NewTransactionStateChangedEvent(transaction,
+		domain.TransactionStateHeld, domain.TransactionState(transaction.Status), reason)
+	event.Metadata = map[string]interface{}{"reviewer_id": reviewerID.String()}
+	s.emit(ctx, event)
+}
+
+// emit event'i işlemin event akışının sonuna ekler; event yazılamazsa işlem geri alınmaz
+func (s *TransactionService) emit(ctx context.Context, event domain.Event) {
+	if s.eventStore == nil {
+		return
+	}
+
+	err := s.eventStore.AppendEvents(ctx, []domain.EventAppend{{
+		AggregateID:     event.GetAggregateID(),
+		Events:          []domain.Event{event},
+		ExpectedVersion: domain.AnyVersion,
+	}})
+	if err != nil {
+		log.Error().
+			Err(err).
+			Str("event_type", string(event.GetType())).
+			Str("aggregate_id", event.GetAggregateID().String()).
+			Msg("Failed to save transaction event")
+	}
+}
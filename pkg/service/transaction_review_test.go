@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"transaction-api-w-go/pkg/domain"
+	"transaction-api-w-go/pkg/repository"
+
+	"github.com/google/uuid"
+)
+
+func TestHeldTransactionReviewLimitUsage(t *testing.T) {
+	tests := []struct {
+		name       string
+		approve    bool
+		wantStatus domain.TransactionState
+		wantUsage  float64
+	}{
+		{name: "reddedilen işlemin kullanımı iade edilir", wantStatus: domain.TransactionStateFailed, wantUsage: 0},
+		{name: "onaylanan işlemin kullanımı kalır", approve: true, wantStatus: domain.TransactionStateCompleted, wantUsage: 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			ctx := context.Background()
+			userID := env.createUser(t, 100)
+			limits, repo := newTestLimitService(t, uuid.MustParse(userID), 100)
+			svc := env.transactionService()
+			svc.SetLimits(limits)
+
+			held := env.createTransaction(t, userID, domain.TransactionTypeDebit, 10, domain.TransactionStateHeld)
+			if err := limits.ReserveTransactionLimit(ctx, held.UserID, domain.CurrencyTRY, held.Amount, held.ID); err != nil {
+				t.Fatalf("ReserveTransactionLimit: %v", err)
+			}
+
+			var (
+				reviewed *domain.Transaction
+				err      error
+			)
+			if tt.approve {
+				reviewed, err = svc.ApproveHeldTransaction(ctx, uuid.New(), held.ID)
+			} else {
+				reviewed, err = svc.RejectHeldTransaction(ctx, uuid.New(), held.ID, "şüpheli")
+			}
+			if err != nil {
+				t.Fatalf("inceleme: %v", err)
+			}
+			if reviewed.Status != string(tt.wantStatus) {
+				t.Errorf("durum = %s, beklenen %s", reviewed.Status, tt.wantStatus)
+			}
+			if got := dailyAmount(t, repo, held.UserID); got != tt.wantUsage {
+				t.Errorf("günlük kullanım = %v, beklenen %v", got, tt.wantUsage)
+			}
+		})
+	}
+}
+
+func TestHeldTransactionReviewBalances(t *testing.T) {
+	tests := []struct {
+		name            string
+		transactionType domain.TransactionType
+		status          domain.TransactionState
+		approve         bool
+		wantErr         error
+		wantStatus      domain.TransactionState
+		wantLedger      float64
+		wantAvailable   float64
+	}{
+		{name: "onaylanan borç bakiyeden düşülür", transactionType: domain.TransactionTypeDebit, status: domain.TransactionStateHeld, approve: true, wantStatus: domain.TransactionStateCompleted, wantLedger: 40, wantAvailable: 40},
+		{name: "reddedilen borç kullanılabilir bakiyeyi geri açar", transactionType: domain.TransactionTypeDebit, status: domain.TransactionStateHeld, wantStatus: domain.TransactionStateFailed, wantLedger: 100, wantAvailable: 100},
+		{name: "onaylanan alacak bakiyeye eklenir", transactionType: domain.TransactionTypeCredit, status: domain.TransactionStateHeld, approve: true, wantStatus: domain.TransactionStateCompleted, wantLedger: 160, wantAvailable: 160},
+		{name: "reddedilen alacak bakiyeyi değiştirmez", transactionType: domain.TransactionTypeCredit, status: domain.TransactionStateHeld, wantStatus: domain.TransactionStateFailed, wantLedger: 100, wantAvailable: 100},
+		{name: "bekletilmeyen işlem onaylanamaz", transactionType: domain.TransactionTypeDebit, status: domain.TransactionStatePending, approve: true, wantErr: domain.ErrTransactionNotHeld, wantStatus: domain.TransactionStatePending, wantLedger: 100, wantAvailable: 40},
+		{name: "bekletilmeyen işlem reddedilemez", transactionType: domain.TransactionTypeDebit, status: domain.TransactionStateCompleted, wantErr: domain.ErrTransactionNotHeld, wantStatus: domain.TransactionStateCompleted, wantLedger: 100, wantAvailable: 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			ctx := context.Background()
+			userID := env.createUser(t, 100)
+			svc := env.transactionService()
+			eventStore := repository.NewPostgresEventStore(env.db)
+			svc.SetEventStore(eventStore)
+
+			held := env.createTransaction(t, userID, tt.transactionType, 60, tt.status)
+
+			reviewerID := uuid.New()
+			var err error
+			if tt.approve {
+				_, err = svc.ApproveHeldTransaction(ctx, reviewerID, held.ID)
+			} else {
+				_, err = svc.RejectHeldTransaction(ctx, reviewerID, held.ID, "şüpheli")
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("inceleme = %v, beklenen %v", err, tt.wantErr)
+			}
+
+			stored, err := env.transactionRepo.GetByUUID(ctx, held.ID)
+			if err != nil {
+				t.Fatalf("işlem okunamadı: %v", err)
+			}
+			if stored.Status != string(tt.wantStatus) {
+				t.Errorf("durum = %s, beklenen %s", stored.Status, tt.wantStatus)
+			}
+			balance, err := env.balanceService().GetCurrentBalance(ctx, userID)
+			if err != nil {
+				t.Fatalf("GetCurrentBalance: %v", err)
+			}
+			if balance.Ledger != tt.wantLedger || balance.Available != tt.wantAvailable {
+				t.Errorf("bakiye = %v/%v, beklenen %v/%v", balance.Ledger, balance.Available, tt.wantLedger, tt.wantAvailable)
+			}
+
+			events, err := eventStore.GetEvents(ctx, held.ID)
+			if err != nil {
+				t.Fatalf("GetEvents: %v", err)
+			}
+			if tt.wantErr != nil {
+				if len(events) != 0 {
+					t.Errorf("%d event yazıldı, beklenen 0", len(events))
+				}
+				return
+			}
+			if len(events) != 1 {
+				t.Fatalf("%d event yazıldı, beklenen 1", len(events))
+			}
+			changed, ok := events[0].(*domain.TransactionStateChangedEvent)
+			if !ok {
+				t.Fatalf("event = %T, beklenen *domain.TransactionStateChangedEvent", events[0])
+			}
+			if changed.OldState != domain.TransactionStateHeld || changed.NewState != tt.wantStatus {
+				t.Errorf("event durumları = %s -> %s, beklenen held -> %s", changed.OldState, changed.NewState, tt.wantStatus)
+			}
+			if changed.GetMetadata()["reviewer_id"] != reviewerID.String() {
+				t.Errorf("reviewer_id = %v, beklenen %s", changed.GetMetadata()["reviewer_id"], reviewerID)
+			}
+		})
+	}
+}
@@ -0,0 +1,1266 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"transaction-api-w-go/pkg/domain"
+	"transaction-api-w-go/pkg/featureflag"
+	"transaction-api-w-go/pkg/metrics"
+	"transaction-api-w-go/pkg/repository"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestTransactionServiceUniqueReferences(t *testing.T) {
+	tests := []struct {
+		name          string
+		unique        bool
+		references    [2]string
+		sameUser      bool
+		wantDuplicate bool
+	}{
+		{name: "tekrarlanan reference reddedilir", unique: true, references: [2]string{"ref-1", "ref-1"}, sameUser: true, wantDuplicate: true},
+		{name: "boş reference tekrarlanabilir", unique: true, references: [2]string{"", ""}, sameUser: true},
+		{name: "farklı reference'lar", unique: true, references: [2]string{"ref-1", "ref-2"}, sameUser: true},
+		{name: "başka kullanıcının reference'ı", unique: true, references: [2]string{"ref-1", "ref-1"}},
+		{name: "tekillik kapalıyken tekrar serbest", unique: false, references: [2]string{"ref-1", "ref-1"}, sameUser: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			svc := env.transactionService()
+			svc.SetUniqueReferences(tt.unique)
+			ctx := context.Background()
+
+			firstUser := env.createUser(t, 0)
+			secondUser := firstUser
+			if !tt.sameUser {
+				secondUser = env.createUser(t, 0)
+			}
+
+			first, err := svc.Credit(ctx, firstUser, &domain.TransactionRequest{Amount: 10, ReferenceID: tt.references[0]})
+			if err != nil {
+				t.Fatalf("ilk Credit: %v", err)
+			}
+			second, err := svc.Credit(ctx, secondUser, &domain.TransactionRequest{Amount: 10, ReferenceID: tt.references[1]})
+
+			if tt.wantDuplicate {
+				if !errors.Is(err, domain.ErrTransactionAlreadyExists) {
+					t.Fatalf("ikinci Credit = %v, beklenen ErrTransactionAlreadyExists", err)
+				}
+				if second == nil || second.ID != first.ID {
+					t.Fatalf("ikinci Credit mevcut işlemi dönmeli, dönen %+v", second)
+				}
+				if got := env.balanceAmount(t, firstUser); got != 10 {
+					t.Errorf("bakiye = %v, beklenen 10 (tekrar uygulanmamalı)", got)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ikinci Credit: %v", err)
+			}
+			if second.ID == first.ID {
+				t.Fatalf("ikinci Credit yeni bir işlem oluşturmalı")
+			}
+			want := 10.0
+			if tt.sameUser {
+				want = 20
+			}
+			if got := env.balanceAmount(t, firstUser); got != want {
+				t.Errorf("bakiye = %v, beklenen %v", got, want)
+			}
+		})
+	}
+}
+
+func TestTransactionServiceDebitChecksAvailable(t *testing.T) {
+	tests := []struct {
+		name        string
+		hold        float64
+		amount      float64
+		wantErr     error
+		wantBalance float64
+	}{
+		{name: "blokaj yokken kayıtlı bakiye kadar", amount: 100, wantBalance: 0},
+		{name: "kullanılabilir bakiyeye sığan", hold: 40, amount: 60, wantBalance: 40},
+		{name: "kayıtlı bakiyeye sığan ama kullanılabiliri aşan", hold: 40, amount: 70, wantErr: domain.ErrInsufficientBalance, wantBalance: 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			svc := env.transactionService()
+			ctx := context.Background()
+			userID := env.createUser(t, 100)
+
+			if tt.hold > 0 {
+				if _, err := env.balanceService().AuthorizeHold(ctx, userID, domain.BalanceHoldRequest{Amount: tt.hold}); err != nil {
+					t.Fatalf("AuthorizeHold: %v", err)
+				}
+			}
+
+			_, err := svc.Debit(ctx, userID, &domain.TransactionRequest{Amount: tt.amount})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Debit(%v) = %v, beklenen %v", tt.amount, err, tt.wantErr)
+			}
+			if got := env.balanceAmount(t, userID); got != tt.wantBalance {
+				t.Errorf("bakiye = %v, beklenen %v", got, tt.wantBalance)
+			}
+		})
+	}
+}
+
+// recordingProjection kendisine iletilen event'lerin ID'lerini sırasıyla saklar
+type recordingProjection struct {
+	handled []uuid.UUID
+}
+
+func (p *recordingProjection) Name() string {
+	return "recording"
+}
+
+func (p *recordingProjection) Handle(ctx context.Context, event domain.Event) error {
+	p.handled = append(p.handled, event.GetID())
+	return nil
+}
+
+func TestTransactionServiceRepublishTransactionEvents(t *testing.T) {
+	tests := []struct {
+		name        string
+		subscribers int
+		republishes int
+		unknown     bool
+		wantErr     error
+	}{
+		{name: "abonelere iletilir", subscribers: 1, republishes: 1},
+		{name: "tekrar yayınlamak aynı event'leri iletir", subscribers: 1, republishes: 2},
+		{name: "birden fazla abone", subscribers: 2, republishes: 1},
+		{name: "abone yoksa reddedilir", republishes: 1, wantErr: domain.ErrNoEventSubscribers},
+		{name: "bilinmeyen işlem", subscribers: 1, republishes: 1, unknown: true, wantErr: domain.ErrTransactionNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			ctx := context.Background()
+			eventStore := repository.NewPostgresEventStore(env.db)
+			svc := env.transactionService()
+			svc.SetEventStore(eventStore)
+
+			var projections []*recordingProjection
+			for i := 0; i < tt.subscribers; i++ {
+				projection := &recordingProjection{}
+				projections = append(projections, projection)
+				svc.AddEventSubscriber(projection)
+			}
+
+			userID := env.createUser(t, 100)
+			transaction, err := svc.Credit(ctx, userID, &domain.TransactionRequest{Amount: 10})
+			if err != nil {
+				t.Fatalf("Credit: %v", err)
+			}
+			dispute, err := domain.NewDispute(transaction, domain.DisputeRequest{Reason: "tanımadığım işlem"})
+			if err != nil {
+				t.Fatalf("NewDispute: %v", err)
+			}
+			events := []domain.Event{
+				domain.NewTransactionCreatedEvent(transaction),
+				domain.NewTransactionDisputeEvent(domain.EventTransactionDisputed, dispute),
+			}
+			if err := eventStore.SaveEvents(ctx, transaction.ID, events, 0); err != nil {
+				t.Fatalf("SaveEvents: %v", err)
+			}
+
+			transactionID := transaction.ID
+			if tt.unknown {
+				transactionID = uuid.New()
+			}
+			for i := 0; i < tt.republishes; i++ {
+				count, err := svc.RepublishTransactionEvents(ctx, transactionID)
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("RepublishTransactionEvents = %v, beklenen %v", err, tt.wantErr)
+				}
+				if err == nil && count != len(events) {
+					t.Errorf("iletilen event sayısı = %d, beklenen %d", count, len(events))
+				}
+			}
+
+			if got := env.balanceAmount(t, userID); got != 110 {
+				t.Errorf("bakiye = %v, beklenen 110; yeniden yayınlama bakiyeyi değiştirmemeli", got)
+			}
+			if stored, err := eventStore.GetEventCount(ctx, transaction.ID); err != nil || stored != int64(len(events)) {
+				t.Errorf("kayıtlı event sayısı = %d (%v), beklenen %d", stored, err, len(events))
+			}
+
+			var want []uuid.UUID
+			if tt.wantErr == nil {
+				for i := 0; i < tt.republishes; i++ {
+					for _, event := range events {
+						want = append(want, event.GetID())
+					}
+				}
+			}
+			for _, projection := range projections {
+				if len(projection.handled) != len(want) {
+					t.Fatalf("iletilen event'ler = %v, beklenen %v", projection.handled, want)
+				}
+				for i := range want {
+					if projection.handled[i] != want[i] {
+						t.Errorf("%d. event = %s, beklenen %s", i, projection.handled[i], want[i])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestTransactionServiceTransferFee(t *testing.T) {
+	tests := []struct {
+		name          string
+		fees          string
+		newPath       bool
+		senderBalance float64
+		wantErr       error
+		wantFee       float64
+	}{
+		{name: "ücret tutara eklenir", fees: "TRANSFER=flat:1,percent:1", senderBalance: 200, wantFee: 2},
+		{name: "yeni transfer yolunda ücret", fees: "TRANSFER=flat:1,percent:1", newPath: true, senderBalance: 200, wantFee: 2},
+		{name: "ücret tanımı yoksa ücretsiz", senderBalance: 200},
+		{name: "tutar ve ücret bakiyeyi aşar", fees: "TRANSFER=flat:1,percent:1", senderBalance: 101, wantErr: domain.ErrInsufficientBalance},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			ctx := context.Background()
+			svc := env.transactionService()
+
+			schedule, err := domain.ParseFeeSchedule(tt.fees)
+			if err != nil {
+				t.Fatalf("ParseFeeSchedule: %v", err)
+			}
+			feeAccount := env.createUser(t, 0)
+			svc.SetFees(schedule, uuid.MustParse(feeAccount))
+			if tt.newPath {
+				svc.SetFeatureFlags(featureflag.NewService([]featureflag.Flag{
+					{Name: featureflag.FlagNewTransferPath, Enabled: true, Percentage: 100},
+				}))
+			}
+
+			sender := env.createUser(t, tt.senderBalance)
+			recipient := env.createUser(t, 0)
+			transfer, err := svc.Transfer(ctx, sender, &domain.TransferRequest{Amount: 100, ToUserID: uuid.MustParse(recipient)})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Transfer = %v, beklenen %v", err, tt.wantErr)
+			}
+
+			var fees []*domain.Transaction
+			if err := env.db.Where("type = ?", domain.TransactionTypeFee).Find(&fees).Error; err != nil {
+				t.Fatalf("ücret kayıtları okunamadı: %v", err)
+			}
+
+			if tt.wantErr != nil {
+				if got := env.balanceAmount(t, sender); got != tt.senderBalance {
+					t.Errorf("gönderen bakiyesi = %v, beklenen %v", got, tt.senderBalance)
+				}
+				if len(fees) != 0 {
+					t.Errorf("ücret kaydı sayısı = %d, beklenen 0", len(fees))
+				}
+				return
+			}
+
+			if got := env.balanceAmount(t, sender); got != tt.senderBalance-100-tt.wantFee {
+				t.Errorf("gönderen bakiyesi = %v, beklenen %v", got, tt.senderBalance-100-tt.wantFee)
+			}
+			if got := env.balanceAmount(t, recipient); got != 100 {
+				t.Errorf("alıcı bakiyesi = %v, beklenen 100", got)
+			}
+			if got := env.balanceAmount(t, feeAccount); got != tt.wantFee {
+				t.Errorf("ücret hesabı bakiyesi = %v, beklenen %v", got, tt.wantFee)
+			}
+
+			if tt.wantFee == 0 {
+				if len(fees) != 0 {
+					t.Errorf("ücret kaydı sayısı = %d, beklenen 0", len(fees))
+				}
+				return
+			}
+			if len(fees) != 1 {
+				t.Fatalf("ücret kaydı sayısı = %d, beklenen 1", len(fees))
+			}
+			fee := fees[0]
+			if fee.Amount != tt.wantFee || fee.UserID.String() != sender {
+				t.Errorf("ücret kaydı = %v/%s, beklenen %v/%s", fee.Amount, fee.UserID, tt.wantFee, sender)
+			}
+			if fee.CounterpartyID == nil || fee.CounterpartyID.String() != feeAccount {
+				t.Errorf("ücret kaydının karşı tarafı = %v, beklenen %s", fee.CounterpartyID, feeAccount)
+			}
+			if fee.Metadata["transfer_id"] != transfer.ID.String() {
+				t.Errorf("ücret kaydının transfer_id'si = %v, beklenen %s", fee.Metadata["transfer_id"], transfer.ID)
+			}
+		})
+	}
+}
+
+func TestTransactionServiceTransferPathsAgree(t *testing.T) {
+	// transferOutcome bir transferin yazdığı kayıtları kullanıcı rolleriyle ifade eder; iki yol ayrı
+	// veritabanlarında çalıştığından id'ler yerine roller karşılaştırılır
+	type transferOutcome struct {
+		Balances     map[string]float64
+		Transactions []string
+		Events       []string
+	}
+
+	tests := []struct {
+		name             string
+		fees             string
+		recipientBalance bool
+		wantSender       float64
+	}{
+		{name: "ücretsiz transfer", recipientBalance: true, wantSender: 170},
+		{name: "ücretli transfer", fees: "TRANSFER=flat:1,percent:10", recipientBalance: true, wantSender: 165},
+		{name: "bakiyesi olmayan alıcıya transfer", wantSender: 170},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			run := func(newPath bool) transferOutcome {
+				env := newTestEnv(t)
+				ctx := context.Background()
+				svc := env.transactionService()
+				svc.SetEventStore(repository.NewPostgresEventStore(env.db))
+				svc.SetFeatureFlags(featureflag.NewService([]featureflag.Flag{
+					{Name: featureflag.FlagNewTransferPath, Enabled: newPath, Percentage: 100},
+				}))
+
+				schedule, err := domain.ParseFeeSchedule(tt.fees)
+				if err != nil {
+					t.Fatalf("ParseFeeSchedule: %v", err)
+				}
+				roles := map[string]string{
+					"gönderen":     env.createUser(t, 200),
+					"ücret hesabı": env.createUser(t, 0),
+				}
+				if tt.recipientBalance {
+					roles["alıcı"] = env.createUser(t, 0)
+				} else {
+					roles["alıcı"] = env.createUserWithoutBalance(t)
+				}
+				svc.SetFees(schedule, uuid.MustParse(roles["ücret hesabı"]))
+
+				// Aynı gönderenden iki transfer; ikincisi ilkinin yazdığı bakiyeler üzerinden çalışır
+				for _, amount := range []float64{10, 20} {
+					if _, err := svc.Transfer(ctx, roles["gönderen"], &domain.TransferRequest{Amount: amount, ToUserID: uuid.MustParse(roles["alıcı"])}); err != nil {
+						t.Fatalf("Transfer(%v): %v", amount, err)
+					}
+				}
+
+				role := make(map[string]string, len(roles))
+				outcome := transferOutcome{Balances: make(map[string]float64)}
+				for name, id := range roles {
+					role[id] = name
+					outcome.Balances[name] = env.balanceAmount(t, id)
+				}
+
+				var transactions []*domain.Transaction
+				if err := env.db.Order("type ASC, amount ASC").Find(&transactions).Error; err != nil {
+					t.Fatalf("işlemler okunamadı: %v", err)
+				}
+				for _, transaction := range transactions {
+					counterparty := ""
+					if transaction.CounterpartyID != nil {
+						counterparty = role[transaction.CounterpartyID.String()]
+					}
+					outcome.Transactions = append(outcome.Transactions, fmt.Sprintf("%s %s->%s %v (%v) %s",
+						transaction.Type, role[transaction.UserID.String()], counterparty, transaction.Amount, transaction.BalanceAfter, transaction.Status))
+				}
+
+				// Tamamlanan transfer event yazmaz; yollardan biri event üretmeye başlarsa fark burada görülür
+				if err := env.db.Table("event_store").Order("type ASC").Pluck("type", &outcome.Events).Error; err != nil {
+					t.Fatalf("event'ler okunamadı: %v", err)
+				}
+				return outcome
+			}
+
+			legacy, applied := run(false), run(true)
+			if !reflect.DeepEqual(legacy, applied) {
+				t.Errorf("yeni transfer yolu farklı sonuç üretti:\neski: %+v\nyeni: %+v", legacy, applied)
+			}
+			if got := applied.Balances["gönderen"]; got != tt.wantSender {
+				t.Errorf("gönderen bakiyesi = %v, beklenen %v", got, tt.wantSender)
+			}
+			if got := applied.Balances["alıcı"]; got != 30 {
+				t.Errorf("alıcı bakiyesi = %v, beklenen 30", got)
+			}
+		})
+	}
+}
+
+func TestTransactionServiceHistoryByTagAndCategory(t *testing.T) {
+	env := newTestEnv(t)
+	svc := env.transactionService()
+	ctx := context.Background()
+	userID := env.createUser(t, 0)
+	otherUser := env.createUser(t, 0)
+
+	credit := func(userID, category string, tags ...string) *domain.Transaction {
+		transaction, err := svc.Credit(ctx, userID, &domain.TransactionRequest{Amount: 10, Category: category, Tags: tags})
+		if err != nil {
+			t.Fatalf("Credit: %v", err)
+		}
+		return transaction
+	}
+	groceries := credit(userID, "food", "market", "weekly")
+	rent := credit(userID, "housing", "monthly")
+	untagged := credit(userID, "")
+	credit(otherUser, "food", "market")
+
+	tests := []struct {
+		name   string
+		filter domain.TransactionFilter
+		want   []*domain.Transaction
+	}{
+		{name: "etikete göre", filter: domain.TransactionFilter{Tag: "market"}, want: []*domain.Transaction{groceries}},
+		{name: "ikinci etiket", filter: domain.TransactionFilter{Tag: "weekly"}, want: []*domain.Transaction{groceries}},
+		{name: "etiketin bir kısmı eşleşmez", filter: domain.TransactionFilter{Tag: "week"}},
+		{name: "kategoriye göre", filter: domain.TransactionFilter{Category: "housing"}, want: []*domain.Transaction{rent}},
+		{name: "kategori ve etiket birlikte", filter: domain.TransactionFilter{Category: "housing", Tag: "market"}},
+		{name: "filtresiz", want: []*domain.Transaction{untagged, rent, groceries}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := svc.GetHistory(ctx, userID, tt.filter)
+			if err != nil {
+				t.Fatalf("GetHistory: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("işlem sayısı = %d, beklenen %d", len(got), len(tt.want))
+			}
+			want := make(map[uuid.UUID]bool, len(tt.want))
+			for _, transaction := range tt.want {
+				want[transaction.ID] = true
+			}
+			for _, transaction := range got {
+				if !want[transaction.ID] {
+					t.Errorf("beklenmeyen işlem %s (%s %v)", transaction.ID, transaction.Category, transaction.Tags)
+				}
+			}
+		})
+	}
+
+	t.Run("etiketler saklanır", func(t *testing.T) {
+		stored, err := env.transactionRepo.GetByUUID(ctx, groceries.ID)
+		if err != nil {
+			t.Fatalf("GetByUUID: %v", err)
+		}
+		if stored.Category != "food" || !stored.Tags.HasTag("market") || !stored.Tags.HasTag("weekly") || len(stored.Tags) != 2 {
+			t.Errorf("kayıtlı işlem = %s %v, beklenen food [market weekly]", stored.Category, stored.Tags)
+		}
+	})
+}
+
+func TestTransactionServiceMetadata(t *testing.T) {
+	tests := []struct {
+		name     string
+		metadata domain.Metadata
+		wantErr  error
+	}{
+		{name: "metadata olmadan", metadata: nil},
+		{name: "metadata saklanır", metadata: domain.Metadata{
+			domain.MetadataKeyExternalOrderID: "ORD-1",
+			domain.MetadataKeyDeviceID:        "ios-42",
+			"channel":                         "mobile",
+		}},
+		{name: "büyük metadata reddedilir", metadata: domain.Metadata{"note": strings.Repeat("a", domain.MaxMetadataSize)}, wantErr: domain.ErrMetadataTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			svc := env.transactionService()
+			ctx := context.Background()
+			userID := env.createUser(t, 0)
+
+			transaction, err := svc.Credit(ctx, userID, &domain.TransactionRequest{Amount: 10, Metadata: tt.metadata})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Credit = %v, beklenen %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if got := env.balanceAmount(t, userID); got != 0 {
+					t.Errorf("bakiye = %v, reddedilen işlem bakiyeyi değiştirmemeli", got)
+				}
+				return
+			}
+
+			stored, err := env.transactionRepo.GetByUUID(ctx, transaction.ID)
+			if err != nil {
+				t.Fatalf("GetByUUID: %v", err)
+			}
+			if !reflect.DeepEqual(stored.Metadata, tt.metadata) {
+				t.Errorf("metadata = %v, beklenen %v", stored.Metadata, tt.metadata)
+			}
+		})
+	}
+}
+
+func TestTransactionServiceSearch(t *testing.T) {
+	env := newTestEnv(t)
+	svc := env.transactionService()
+	ctx := context.Background()
+	userID := env.createUser(t, 0)
+	otherUser := env.createUser(t, 0)
+	counterparty := uuid.New()
+	base := time.Now().Add(-time.Hour).Truncate(time.Second)
+
+	seed := func(userID string, amount float64, category, referenceID string, counterpartyID *uuid.UUID, createdAt time.Time) *domain.Transaction {
+		transaction := &domain.Transaction{
+			ID:             uuid.New(),
+			UserID:         uuid.MustParse(userID),
+			Type:           domain.TransactionTypeDebit,
+			Amount:         amount,
+			Status:         string(domain.TransactionStateCompleted),
+			Category:       category,
+			ReferenceID:    referenceID,
+			CounterpartyID: counterpartyID,
+			CreatedAt:      createdAt,
+			UpdatedAt:      createdAt,
+		}
+		if err := env.transactionRepo.Create(ctx, transaction); err != nil {
+			t.Fatalf("işlem yazılamadı: %v", err)
+		}
+		return transaction
+	}
+	small := seed(userID, 5, "food", "ORD-1", nil, base)
+	medium := seed(userID, 50, "food", "ORD-10", &counterparty, base.Add(time.Minute))
+	large := seed(userID, 500, "rent", "XORD-1", &counterparty, base.Add(2*time.Minute))
+	seed(otherUser, 50, "food", "ORD-1", &counterparty, base.Add(3*time.Minute))
+
+	amount := func(v float64) *float64 { return &v }
+	at := func(d time.Duration) *time.Time { v := base.Add(d); return &v }
+
+	tests := []struct {
+		name    string
+		filter  domain.TransactionFilter
+		want    []*domain.Transaction
+		wantErr error
+	}{
+		{name: "reference id tam eşleşir", filter: domain.TransactionFilter{ReferenceID: "ORD-1"}, want: []*domain.Transaction{small}},
+		{name: "reference id öneki eşleşmez", filter: domain.TransactionFilter{ReferenceID: "ORD"}},
+		{name: "tutar aralığı", filter: domain.TransactionFilter{MinAmount: amount(10), MaxAmount: amount(100)}, want: []*domain.Transaction{medium}},
+		{
+			name:   "karşı taraf ve kategori birlikte",
+			filter: domain.TransactionFilter{CounterpartyID: counterparty.String(), Category: "food"},
+			want:   []*domain.Transaction{medium},
+		},
+		{
+			name:   "tarih aralığı ve tutar alt sınırı",
+			filter: domain.TransactionFilter{StartDate: at(30 * time.Second), EndDate: at(3 * time.Minute), MinAmount: amount(100)},
+			want:   []*domain.Transaction{large},
+		},
+		{name: "artan sıralama", filter: domain.TransactionFilter{SortOrder: domain.SortOrderAsc}, want: []*domain.Transaction{small, medium, large}},
+		{name: "varsayılan sıralama azalan", want: []*domain.Transaction{large, medium, small}},
+		{name: "ters tutar aralığı", filter: domain.TransactionFilter{MinAmount: amount(100), MaxAmount: amount(10)}, wantErr: domain.ErrInvalidSearchFilter},
+		{name: "ters tarih aralığı", filter: domain.TransactionFilter{StartDate: at(time.Minute), EndDate: at(0)}, wantErr: domain.ErrInvalidSearchFilter},
+		{name: "geçersiz sıralama", filter: domain.TransactionFilter{SortOrder: "sideways"}, wantErr: domain.ErrInvalidSearchFilter},
+		{name: "geçersiz cursor", filter: domain.TransactionFilter{Cursor: "!!"}, wantErr: domain.ErrInvalidCursor},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := svc.Search(ctx, userID, tt.filter)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Search = %v, beklenen %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if len(result.Transactions) != len(tt.want) || result.Total != int64(len(tt.want)) {
+				t.Fatalf("işlem sayısı = %d (toplam %d), beklenen %d", len(result.Transactions), result.Total, len(tt.want))
+			}
+			for i, transaction := range result.Transactions {
+				if transaction.ID != tt.want[i].ID {
+					t.Errorf("%d. işlem = %s (%v), beklenen %s (%v)", i, transaction.ID, transaction.Amount, tt.want[i].ID, tt.want[i].Amount)
+				}
+			}
+		})
+	}
+
+	t.Run("cursor ile sayfalama", func(t *testing.T) {
+		filter := domain.TransactionFilter{SortOrder: domain.SortOrderAsc, Limit: 2}
+		var got []*domain.Transaction
+		for page := 0; page < 3; page++ {
+			result, err := svc.Search(ctx, userID, filter)
+			if err != nil {
+				t.Fatalf("Search: %v", err)
+			}
+			if result.Total != 3 {
+				t.Errorf("Total = %d, beklenen 3", result.Total)
+			}
+			got = append(got, result.Transactions...)
+			if result.NextCursor == "" {
+				break
+			}
+			filter.Cursor = result.NextCursor
+		}
+		want := []*domain.Transaction{small, medium, large}
+		if len(got) != len(want) {
+			t.Fatalf("işlem sayısı = %d, beklenen %d", len(got), len(want))
+		}
+		for i := range want {
+			if got[i].ID != want[i].ID {
+				t.Errorf("%d. işlem = %s, beklenen %s", i, got[i].ID, want[i].ID)
+			}
+		}
+	})
+}
+
+func TestTransactionServiceGetByReferenceID(t *testing.T) {
+	env := newTestEnv(t)
+	svc := env.transactionService()
+	ctx := context.Background()
+	userID := env.createUser(t, 100)
+	otherUser := env.createUser(t, 100)
+
+	credit, err := svc.Credit(ctx, userID, &domain.TransactionRequest{Amount: 10, ReferenceID: "ORD-1"})
+	if err != nil {
+		t.Fatalf("Credit: %v", err)
+	}
+	debit, err := svc.Debit(ctx, userID, &domain.TransactionRequest{Amount: 5, ReferenceID: "ORD-1"})
+	if err != nil {
+		t.Fatalf("Debit: %v", err)
+	}
+	if _, err := svc.Credit(ctx, otherUser, &domain.TransactionRequest{Amount: 10, ReferenceID: "ORD-2"}); err != nil {
+		t.Fatalf("Credit: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		userID      string
+		referenceID string
+		want        []*domain.Transaction
+		wantErr     error
+	}{
+		{name: "eşleşen işlemler", userID: userID, referenceID: "ORD-1", want: []*domain.Transaction{credit, debit}},
+		{name: "bilinmeyen reference id", userID: userID, referenceID: "ORD-404", wantErr: domain.ErrTransactionNotFound},
+		{name: "başka kullanıcının reference id'si", userID: userID, referenceID: "ORD-2", wantErr: domain.ErrTransactionNotFound},
+		{name: "önek eşleşmez", userID: userID, referenceID: "ORD", wantErr: domain.ErrTransactionNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := svc.GetByReferenceID(ctx, tt.userID, tt.referenceID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetByReferenceID = %v, beklenen %v", err, tt.wantErr)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("işlem sayısı = %d, beklenen %d", len(got), len(tt.want))
+			}
+			want := make(map[uuid.UUID]bool, len(tt.want))
+			for _, transaction := range tt.want {
+				want[transaction.ID] = true
+			}
+			for _, transaction := range got {
+				if !want[transaction.ID] {
+					t.Errorf("beklenmeyen işlem %s", transaction.ID)
+				}
+			}
+		})
+	}
+}
+
+func TestTransactionServiceOutcomeMetrics(t *testing.T) {
+	tests := []struct {
+		name       string
+		txType     domain.TransactionType
+		setup      func(t *testing.T, svc *TransactionService, userID string)
+		run        func(svc *TransactionService, userID string) error
+		wantReason string
+	}{
+		{
+			name:   "başarılı işlem başarı sayacını artırır",
+			txType: domain.TransactionTypeCredit,
+			run: func(svc *TransactionService, userID string) error {
+				_, err := svc.Credit(context.Background(), userID, &domain.TransactionRequest{Amount: 10})
+				return err
+			},
+		},
+		{
+			name:   "yetersiz bakiye sebebiyle etiketlenir",
+			txType: domain.TransactionTypeDebit,
+			run: func(svc *TransactionService, userID string) error {
+				_, err := svc.Debit(context.Background(), userID, &domain.TransactionRequest{Amount: 500})
+				return err
+			},
+			wantReason: "insufficient_balance",
+		},
+		{
+			name:   "tekrarlanan referans sebebiyle etiketlenir",
+			txType: domain.TransactionTypeCredit,
+			setup: func(t *testing.T, svc *TransactionService, userID string) {
+				svc.SetUniqueReferences(true)
+				if _, err := svc.Credit(context.Background(), userID, &domain.TransactionRequest{Amount: 1, ReferenceID: "DUP-1"}); err != nil {
+					t.Fatalf("Credit: %v", err)
+				}
+			},
+			run: func(svc *TransactionService, userID string) error {
+				_, err := svc.Credit(context.Background(), userID, &domain.TransactionRequest{Amount: 1, ReferenceID: "DUP-1"})
+				return err
+			},
+			wantReason: "duplicate_reference",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			svc := env.transactionService()
+			userID := env.createUser(t, 100)
+			if tt.setup != nil {
+				tt.setup(t, svc, userID)
+			}
+
+			label := string(tt.txType)
+			attempts := metrics.TransactionAttemptsTotal.WithLabelValues(label)
+			successes := metrics.TransactionSuccessesTotal.WithLabelValues(label)
+			failures := metrics.TransactionFailuresTotal.WithLabelValues(label, tt.wantReason)
+			before := []float64{testutil.ToFloat64(attempts), testutil.ToFloat64(successes), testutil.ToFloat64(failures)}
+
+			err := tt.run(svc, userID)
+			if (err != nil) != (tt.wantReason != "") {
+				t.Fatalf("işlem hatası = %v, beklenen sebep %q", err, tt.wantReason)
+			}
+
+			wantSuccess, wantFailure := 1.0, 0.0
+			if tt.wantReason != "" {
+				wantSuccess, wantFailure = 0, 1
+			}
+			if got := testutil.ToFloat64(attempts) - before[0]; got != 1 {
+				t.Errorf("deneme sayacı %v arttı, beklenen 1", got)
+			}
+			if got := testutil.ToFloat64(successes) - before[1]; got != wantSuccess {
+				t.Errorf("başarı sayacı %v arttı, beklenen %v", got, wantSuccess)
+			}
+			if got := testutil.ToFloat64(failures) - before[2]; got != wantFailure {
+				t.Errorf("%q hata sayacı %v arttı, beklenen %v", tt.wantReason, got, wantFailure)
+			}
+		})
+	}
+}
+
+func TestTransactionServicePreview(t *testing.T) {
+	limitRequest := func(single, daily, weekly float64, dailyCount int) *domain.TransactionLimitRequest {
+		return &domain.TransactionLimitRequest{Currency: domain.CurrencyTRY, SingleLimit: single, DailyLimit: daily, WeeklyLimit: weekly, MonthlyLimit: 1e6, DailyCountLimit: dailyCount}
+	}
+
+	tests := []struct {
+		name      string
+		req       domain.TransactionPreviewRequest
+		recipient string
+		limit     *domain.TransactionLimitRequest
+		usedCount int
+		wantCodes []string
+	}{
+		{name: "tüm kontrollerden geçer", req: domain.TransactionPreviewRequest{Type: "debit", Amount: 30}, limit: limitRequest(50, 1000, 1000, 0)},
+		{name: "yeterli alıcıya transfer geçer", req: domain.TransactionPreviewRequest{Type: "transfer", Amount: 30}, recipient: "mevcut"},
+		{name: "geçersiz tip", req: domain.TransactionPreviewRequest{Type: "refund", Amount: 30}, wantCodes: []string{"invalid_type"}},
+		{name: "desteklenmeyen para birimi", req: domain.TransactionPreviewRequest{Type: "debit", Amount: 30, Currency: "XXX"}, wantCodes: []string{"unsupported_currency"}},
+		{name: "farklı para birimi", req: domain.TransactionPreviewRequest{Type: "debit", Amount: 30, Currency: domain.CurrencyUSD}, wantCodes: []string{"currency_mismatch"}},
+		{name: "yetersiz bakiye", req: domain.TransactionPreviewRequest{Type: "debit", Amount: 150}, wantCodes: []string{"insufficient_funds"}},
+		{name: "alıcı yok", req: domain.TransactionPreviewRequest{Type: "transfer", Amount: 30}, wantCodes: []string{"recipient_required"}},
+		{name: "kendine transfer", req: domain.TransactionPreviewRequest{Type: "transfer", Amount: 30}, recipient: "kendisi", wantCodes: []string{"self_transfer"}},
+		{name: "bilinmeyen alıcı", req: domain.TransactionPreviewRequest{Type: "transfer", Amount: 30}, recipient: "bilinmeyen", wantCodes: []string{"recipient_not_found"}},
+		{name: "tek işlem limiti", req: domain.TransactionPreviewRequest{Type: "debit", Amount: 30}, limit: limitRequest(20, 1000, 1000, 0), wantCodes: []string{"single_limit_exceeded"}},
+		{name: "günlük limit", req: domain.TransactionPreviewRequest{Type: "debit", Amount: 30}, limit: limitRequest(50, 25, 1000, 0), wantCodes: []string{"daily_limit_exceeded"}},
+		{name: "haftalık limit", req: domain.TransactionPreviewRequest{Type: "debit", Amount: 30}, limit: limitRequest(50, 1000, 25, 0), wantCodes: []string{"weekly_limit_exceeded"}},
+		{name: "günlük adet", req: domain.TransactionPreviewRequest{Type: "debit", Amount: 30}, limit: limitRequest(50, 1000, 1000, 1), usedCount: 1, wantCodes: []string{"daily_count_exceeded"}},
+		{name: "birden fazla neden", req: domain.TransactionPreviewRequest{Type: "debit", Amount: 150}, limit: limitRequest(50, 1000, 1000, 0), wantCodes: []string{"insufficient_funds", "single_limit_exceeded"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			svc := env.transactionService()
+			ctx := context.Background()
+			userID := env.createUser(t, 100)
+
+			limits := NewTransactionLimitService(newMemoryLimitRepository(), nil)
+			svc.SetLimits(limits)
+			if tt.limit != nil {
+				if _, err := limits.CreateTransactionLimit(ctx, uuid.MustParse(userID), *tt.limit); err != nil {
+					t.Fatalf("CreateTransactionLimit: %v", err)
+				}
+				for i := 0; i < tt.usedCount; i++ {
+					if err := limits.ReserveTransactionLimit(ctx, uuid.MustParse(userID), domain.CurrencyTRY, 1, uuid.New()); err != nil {
+						t.Fatalf("ReserveTransactionLimit: %v", err)
+					}
+				}
+			}
+
+			req := tt.req
+			switch tt.recipient {
+			case "mevcut":
+				recipient := uuid.MustParse(env.createUser(t, 0))
+				req.ToUserID = &recipient
+			case "kendisi":
+				self := uuid.MustParse(userID)
+				req.ToUserID = &self
+			case "bilinmeyen":
+				unknown := uuid.New()
+				req.ToUserID = &unknown
+			}
+
+			before, err := limits.GetTransactionLimit(ctx, uuid.MustParse(userID), domain.CurrencyTRY)
+			if err != nil && !errors.Is(err, domain.ErrTransactionLimitNotFound) {
+				t.Fatalf("GetTransactionLimit: %v", err)
+			}
+
+			preview, err := svc.Preview(ctx, userID, &req)
+			if err != nil {
+				t.Fatalf("Preview: %v", err)
+			}
+			codes := make([]string, 0, len(preview.Reasons))
+			for _, reason := range preview.Reasons {
+				codes = append(codes, reason.Code)
+			}
+			if len(codes) != len(tt.wantCodes) {
+				t.Fatalf("nedenler = %v, beklenen %v", codes, tt.wantCodes)
+			}
+			for i := range codes {
+				if codes[i] != tt.wantCodes[i] {
+					t.Errorf("nedenler = %v, beklenen %v", codes, tt.wantCodes)
+					break
+				}
+			}
+			if preview.Allowed != (len(tt.wantCodes) == 0) {
+				t.Errorf("Allowed = %v, nedenler %v", preview.Allowed, codes)
+			}
+
+			// Önizleme bakiyeyi ve limit kullanımını değiştirmez
+			if got := env.balanceAmount(t, userID); got != 100 {
+				t.Errorf("bakiye = %v, beklenen 100", got)
+			}
+			if before != nil {
+				after, err := limits.GetTransactionLimit(ctx, uuid.MustParse(userID), domain.CurrencyTRY)
+				if err != nil {
+					t.Fatalf("GetTransactionLimit: %v", err)
+				}
+				if after.DailyAmount != before.DailyAmount || after.DailyCount != before.DailyCount {
+					t.Errorf("limit kullanımı %v/%d → %v/%d değişti", before.DailyAmount, before.DailyCount, after.DailyAmount, after.DailyCount)
+				}
+			}
+		})
+	}
+}
+
+func TestTransactionServiceMinimumBalance(t *testing.T) {
+	tests := []struct {
+		name        string
+		minimum     float64
+		transfer    bool
+		amount      float64
+		wantErr     error
+		wantBalance float64
+	}{
+		{name: "overdraft izni olan kullanıcı sıfırın altına iner", minimum: -50, amount: 130, wantBalance: -30},
+		{name: "overdraft sınırına kadar borçlanılır", minimum: -50, amount: 150, wantBalance: -50},
+		{name: "overdraft sınırı aşılamaz", minimum: -50, amount: 151, wantErr: domain.ErrMinimumBalanceBreached, wantBalance: 100},
+		{name: "overdraft ile transfer", minimum: -50, transfer: true, amount: 120, wantBalance: -20},
+		{name: "minimumu olmayan kullanıcı sıfırda durur", amount: 101, wantErr: domain.ErrInsufficientBalance, wantBalance: 100},
+		{name: "minimumu olmayan kullanıcı transferde sıfırda durur", transfer: true, amount: 101, wantErr: domain.ErrInsufficientBalance, wantBalance: 100},
+		{name: "pozitif minimum bakiye korunur", minimum: 20, amount: 90, wantErr: domain.ErrMinimumBalanceBreached, wantBalance: 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			svc := env.transactionService()
+			ctx := context.Background()
+			userID := env.createUser(t, 100)
+			recipient := env.createUser(t, 0)
+
+			if tt.minimum != 0 {
+				if _, err := env.balanceService().SetMinimumBalance(ctx, userID, tt.minimum); err != nil {
+					t.Fatalf("SetMinimumBalance: %v", err)
+				}
+			}
+
+			var err error
+			if tt.transfer {
+				_, err = svc.Transfer(ctx, userID, &domain.TransferRequest{Amount: tt.amount, ToUserID: uuid.MustParse(recipient)})
+			} else {
+				_, err = svc.Debit(ctx, userID, &domain.TransactionRequest{Amount: tt.amount})
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("işlem(%v) = %v, beklenen %v", tt.amount, err, tt.wantErr)
+			}
+			if tt.wantErr == domain.ErrInsufficientBalance && errors.Is(err, domain.ErrMinimumBalanceBreached) {
+				t.Errorf("minimumu olmayan kullanıcı için hata = %v, beklenen ErrInsufficientBalance", err)
+			}
+			if got := env.balanceAmount(t, userID); got != tt.wantBalance {
+				t.Errorf("bakiye = %v, beklenen %v", got, tt.wantBalance)
+			}
+		})
+	}
+}
+
+// stubScreener her taramada aynı kararı veya hatayı döner
+type stubScreener struct {
+	result *domain.ScreeningResult
+	err    error
+	calls  int
+}
+
+func (s *stubScreener) Screen(ctx context.Context, transaction *domain.Transaction) (*domain.ScreeningResult, error) {
+	s.calls++
+	return s.result, s.err
+}
+
+func TestTransactionServiceScreening(t *testing.T) {
+	errProvider := errors.New("connection refused")
+
+	tests := []struct {
+		name        string
+		amount      float64
+		decision    domain.ScreeningDecision
+		providerErr error
+		failOpen    bool
+		wantErr     error
+		wantCalls   int
+		wantStatus  domain.TransactionState
+		wantBalance float64
+	}{
+		{name: "eşik altı taranmaz", amount: 40, decision: domain.ScreeningDeny, wantStatus: domain.TransactionStateCompleted, wantBalance: 60},
+		{name: "onaylanan işlem tamamlanır", amount: 60, decision: domain.ScreeningApprove, wantCalls: 1, wantStatus: domain.TransactionStateCompleted, wantBalance: 40},
+		{name: "reddedilen işlem failed kaydedilir", amount: 60, decision: domain.ScreeningDeny, wantErr: domain.ErrTransactionDenied, wantCalls: 1, wantStatus: domain.TransactionStateFailed, wantBalance: 100},
+		{name: "bekletilen işlem incelemeye alınır", amount: 60, decision: domain.ScreeningHold, wantCalls: 1, wantStatus: domain.TransactionStateHeld, wantBalance: 100},
+		{name: "servis yokken fail-open onaylar", amount: 60, providerErr: errProvider, failOpen: true, wantCalls: 1, wantStatus: domain.TransactionStateCompleted, wantBalance: 40},
+		{name: "servis yokken fail-closed reddeder", amount: 60, providerErr: errProvider, wantErr: domain.ErrScreeningUnavailable, wantCalls: 1, wantBalance: 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			svc := env.transactionService()
+			ctx := context.Background()
+			userID := env.createUser(t, 100)
+
+			screener := &stubScreener{err: tt.providerErr}
+			if tt.providerErr == nil {
+				screener.result = &domain.ScreeningResult{Decision: tt.decision, Reason: "kural-42"}
+			}
+			svc.SetScreening(screener, 50, tt.failOpen)
+
+			transaction, err := svc.Debit(ctx, userID, &domain.TransactionRequest{Amount: tt.amount})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Debit(%v) = %v, beklenen %v", tt.amount, err, tt.wantErr)
+			}
+			if screener.calls != tt.wantCalls {
+				t.Errorf("tarama çağrısı = %d, beklenen %d", screener.calls, tt.wantCalls)
+			}
+			if got := env.balanceAmount(t, userID); got != tt.wantBalance {
+				t.Errorf("bakiye = %v, beklenen %v", got, tt.wantBalance)
+			}
+
+			stored, err := env.transactionRepo.GetByUserIDWithFilter(ctx, userID, domain.TransactionFilter{})
+			if err != nil {
+				t.Fatalf("işlemler okunamadı: %v", err)
+			}
+			if tt.wantStatus == "" {
+				if len(stored) != 0 {
+					t.Errorf("%d işlem kaydedildi, beklenen 0", len(stored))
+				}
+				return
+			}
+			if len(stored) != 1 || stored[0].ID != transaction.ID {
+				t.Fatalf("kaydedilen işlemler = %d, beklenen yalnızca %s", len(stored), transaction.ID)
+			}
+			if stored[0].Status != string(tt.wantStatus) {
+				t.Errorf("durum = %s, beklenen %s", stored[0].Status, tt.wantStatus)
+			}
+			if tt.wantStatus != domain.TransactionStateCompleted && stored[0].StatusReason != "kural-42" {
+				t.Errorf("StatusReason = %q, beklenen kural-42", stored[0].StatusReason)
+			}
+		})
+	}
+}
+
+func TestTransactionServiceGetTransactionEvents(t *testing.T) {
+	tests := []struct {
+		name         string
+		viewer       string
+		unknown      bool
+		noEventStore bool
+		wantErr      error
+		wantVersions []int64
+	}{
+		{name: "gönderen sürüm sırasıyla görür", viewer: "sender", wantVersions: []int64{1, 2, 3}},
+		{name: "alıcı da tarafı olduğu için görür", viewer: "recipient", wantVersions: []int64{1, 2, 3}},
+		{name: "başka kullanıcı göremez", viewer: "other", wantErr: domain.ErrTransactionNotFound},
+		{name: "bilinmeyen işlem", viewer: "sender", unknown: true, wantErr: domain.ErrTransactionNotFound},
+		{name: "event store yoksa boş zaman çizelgesi", viewer: "sender", noEventStore: true, wantVersions: []int64{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			ctx := context.Background()
+			eventStore := repository.NewPostgresEventStore(env.db)
+			svc := env.transactionService()
+
+			users := map[string]string{
+				"sender":    env.createUser(t, 100),
+				"recipient": env.createUser(t, 0),
+				"other":     env.createUser(t, 0),
+			}
+			transfer, err := svc.Transfer(ctx, users["sender"], &domain.TransferRequest{Amount: 10, ToUserID: uuid.MustParse(users["recipient"])})
+			if err != nil {
+				t.Fatalf("Transfer: %v", err)
+			}
+
+			// Zaman damgaları sürümlerin tersine sıralıdır; zaman çizelgesi sürüme göre dizilmelidir
+			base := time.Now().Truncate(time.Second)
+			events := []domain.Event{
+				domain.NewTransactionCreatedEvent(transfer),
+				domain.NewTransactionStateChangedEvent(transfer, domain.TransactionStatePending, domain.TransactionStateHeld, "inceleme"),
+				domain.NewTransactionStateChangedEvent(transfer, domain.TransactionStateHeld, domain.TransactionStateCompleted, ""),
+			}
+			events[0].(*domain.TransactionCreatedEvent).Timestamp = base
+			events[1].(*domain.TransactionStateChangedEvent).Timestamp = base.Add(-time.Minute)
+			events[2].(*domain.TransactionStateChangedEvent).Timestamp = base.Add(-2 * time.Minute)
+			if err := eventStore.SaveEvents(ctx, transfer.ID, events, 0); err != nil {
+				t.Fatalf("SaveEvents: %v", err)
+			}
+			if !tt.noEventStore {
+				svc.SetEventStore(eventStore)
+			}
+
+			transactionID := transfer.ID
+			if tt.unknown {
+				transactionID = uuid.New()
+			}
+			got, err := svc.GetTransactionEvents(ctx, users[tt.viewer], transactionID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetTransactionEvents = %v, beklenen %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			versions := make([]int64, len(got))
+			for i, event := range got {
+				versions[i] = event.GetVersion()
+			}
+			if !reflect.DeepEqual(versions, tt.wantVersions) {
+				t.Errorf("sürümler = %v, beklenen %v", versions, tt.wantVersions)
+			}
+		})
+	}
+}
+
+func TestTransactionServiceCreditMissingBalance(t *testing.T) {
+	tests := []struct {
+		name        string
+		hasBalance  bool
+		wantBalance float64
+	}{
+		{name: "bakiyesi olmayan kullanıcıya bakiye açılır", wantBalance: 70},
+		{name: "var olan bakiyeye yazılır", hasBalance: true, wantBalance: 170},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			svc := env.transactionService()
+			ctx := context.Background()
+
+			var userID string
+			if tt.hasBalance {
+				userID = env.createUser(t, 100)
+			} else {
+				userID = env.createUserWithoutBalance(t)
+				// Bakiyesi olmayan kullanıcı için yetersiz bakiye değil, bulunamadı dönülür
+				_, err := env.balanceService().GetCurrentBalance(ctx, userID)
+				if !errors.Is(err, domain.ErrBalanceNotFound) || !errors.Is(err, domain.ErrNotFound) {
+					t.Fatalf("GetCurrentBalance = %v, beklenen ErrBalanceNotFound", err)
+				}
+				if errors.Is(err, domain.ErrInsufficientBalance) {
+					t.Fatalf("eksik bakiye ErrInsufficientBalance ile raporlandı")
+				}
+			}
+
+			for _, amount := range []float64{50, 20} {
+				if _, err := svc.Credit(ctx, userID, &domain.TransactionRequest{Amount: amount}); err != nil {
+					t.Fatalf("Credit(%v): %v", amount, err)
+				}
+			}
+
+			if got := env.balanceAmount(t, userID); got != tt.wantBalance {
+				t.Errorf("bakiye = %v, beklenen %v", got, tt.wantBalance)
+			}
+			var balances int64
+			if err := env.db.Model(&domain.Balance{}).Where("user_id = ?", userID).Count(&balances).Error; err != nil {
+				t.Fatalf("bakiyeler sayılamadı: %v", err)
+			}
+			if balances != 1 {
+				t.Errorf("%d bakiye kaydı var, beklenen 1", balances)
+			}
+		})
+	}
+}
+
+func TestTransactionServiceFirstTimeUser(t *testing.T) {
+	tests := []struct {
+		name string
+		// senderHasBalance false ise gönderen ilk kez işlem yapıyordur
+		senderHasBalance bool
+		// recipient transfer alıcısıdır: "funded", "new" (bakiyesi yok) veya "unknown" (kullanıcı yok); boşsa borç yapılır
+		recipient     string
+		hold          bool
+		wantErr       error
+		wantSender    float64
+		wantRecipient float64
+	}{
+		{name: "ilk borç yetersiz bakiye", wantErr: domain.ErrInsufficientBalance},
+		{name: "ilk transfer yetersiz bakiye", recipient: "funded", wantErr: domain.ErrInsufficientBalance, wantRecipient: 10},
+		{name: "ilk blokaj yetersiz bakiye", hold: true, wantErr: domain.ErrInsufficientBalance},
+		{name: "bakiyesi olmayan alıcıya transfer", senderHasBalance: true, recipient: "new", wantSender: 70, wantRecipient: 30},
+		{name: "olmayan kullanıcıya bakiye açılmaz", senderHasBalance: true, recipient: "unknown", wantErr: domain.ErrUserNotFound, wantSender: 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			svc := env.transactionService()
+			ctx := context.Background()
+
+			var userID string
+			if tt.senderHasBalance {
+				userID = env.createUser(t, 100)
+			} else {
+				userID = env.createUserWithoutBalance(t)
+			}
+
+			var recipientID string
+			switch tt.recipient {
+			case "funded":
+				recipientID = env.createUser(t, 10)
+			case "new":
+				recipientID = env.createUserWithoutBalance(t)
+			case "unknown":
+				recipientID = uuid.NewString()
+			}
+
+			var err error
+			switch {
+			case tt.hold:
+				_, err = env.balanceService().AuthorizeHold(ctx, userID, domain.BalanceHoldRequest{Amount: 30})
+			case recipientID != "":
+				_, err = svc.Transfer(ctx, userID, &domain.TransferRequest{Amount: 30, ToUserID: uuid.MustParse(recipientID)})
+			default:
+				_, err = svc.Debit(ctx, userID, &domain.TransactionRequest{Amount: 30})
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("işlem = %v, beklenen %v", err, tt.wantErr)
+			}
+			if errors.Is(err, domain.ErrBalanceNotFound) {
+				t.Errorf("ilk işlem bulunamadı hatası aldı: %v", err)
+			}
+
+			// Gönderenin bakiyesi ilk işlemde açılır
+			if got := env.balanceAmount(t, userID); got != tt.wantSender {
+				t.Errorf("gönderen bakiyesi = %v, beklenen %v", got, tt.wantSender)
+			}
+			switch tt.recipient {
+			case "funded", "new":
+				if got := env.balanceAmount(t, recipientID); got != tt.wantRecipient {
+					t.Errorf("alıcı bakiyesi = %v, beklenen %v", got, tt.wantRecipient)
+				}
+			case "unknown":
+				if _, err := env.balanceRepo.GetByUserID(ctx, recipientID); !errors.Is(err, domain.ErrBalanceNotFound) {
+					t.Errorf("olmayan kullanıcı için bakiye = %v, beklenen ErrBalanceNotFound", err)
+				}
+			}
+		})
+	}
+}
+
+func TestTransactionServiceReservesLimitInBalanceCurrency(t *testing.T) {
+	tests := []struct {
+		name      string
+		op        string
+		amount    float64
+		wantErr   error
+		wantUsage float64
+	}{
+		{name: "yatırma", op: "credit", amount: 30, wantUsage: 30},
+		{name: "çekim", op: "debit", amount: 30, wantUsage: 30},
+		{name: "transfer", op: "transfer", amount: 30, wantUsage: 30},
+		// TRY limiti 1000 olsa da USD işlemi USD limitine takılır
+		{name: "USD limiti aşılır", op: "debit", amount: 60, wantErr: domain.ErrTransactionLimitExceeded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			ctx := context.Background()
+			svc := env.transactionService()
+			svc.SetDefaultCurrency(string(domain.CurrencyUSD))
+
+			createUSDUser := func(amount float64) string {
+				t.Helper()
+				userID := env.createUserWithoutBalance(t)
+				now := time.Now()
+				err := env.balanceRepo.Create(ctx, &domain.Balance{ID: uuid.New(), UserID: uuid.MustParse(userID), Amount: amount, Currency: string(domain.CurrencyUSD), CreatedAt: now, UpdatedAt: now})
+				if err != nil {
+					t.Fatalf("bakiye oluşturulamadı: %v", err)
+				}
+				return userID
+			}
+			userID := createUSDUser(100)
+			uid := uuid.MustParse(userID)
+
+			repo := newMemoryLimitRepository()
+			usd := newTestLimit(uid, 50)
+			usd.Currency = domain.CurrencyUSD
+			try := newTestLimit(uid, 1000)
+			for _, limit := range []*domain.TransactionLimit{usd, try} {
+				if err := repo.Create(ctx, limit); err != nil {
+					t.Fatalf("limit oluşturulamadı: %v", err)
+				}
+			}
+			svc.SetLimits(NewTransactionLimitService(repo, nil))
+
+			var err error
+			switch tt.op {
+			case "credit":
+				_, err = svc.Credit(ctx, userID, &domain.TransactionRequest{Amount: tt.amount})
+			case "debit":
+				_, err = svc.Debit(ctx, userID, &domain.TransactionRequest{Amount: tt.amount})
+			case "transfer":
+				_, err = svc.Transfer(ctx, userID, &domain.TransferRequest{ToUserID: uuid.MustParse(createUSDUser(0)), Amount: tt.amount})
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("%s = %v, beklenen %v", tt.op, err, tt.wantErr)
+			}
+
+			for currency, want := range map[domain.Currency]float64{domain.CurrencyUSD: tt.wantUsage, domain.CurrencyTRY: 0} {
+				limit, err := repo.GetByUserIDAndCurrency(ctx, uid, currency)
+				if err != nil {
+					t.Fatalf("%s limiti okunamadı: %v", currency, err)
+				}
+				if limit.DailyAmount != want {
+					t.Errorf("%s günlük kullanım = %v, beklenen %v", currency, limit.DailyAmount, want)
+				}
+			}
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package service
 
 import (
+	"context"
+
 	"transaction-api-w-go/pkg/domain"
 	"transaction-api-w-go/pkg/repository"
 )
@@ -15,18 +17,26 @@ func NewUserService(userRepo *repository.UserRepository) *UserService {
 	}
 }
 
-func (s *UserService) List() ([]domain.User, error) {
-	return s.userRepo.List()
+func (s *UserService) List(ctx context.Context) ([]domain.User, error) {
+	return s.userRepo.List(ctx)
+}
+
+func (s *UserService) GetByID(ctx context.Context, id string) (*domain.User, error) {
+	return s.userRepo.GetByID(ctx, id)
 }
 
-func (s *UserService) GetByID(id string) (*domain.User, error) {
-	return s.userRepo.GetByID(id)
+func (s *UserService) Update(ctx context.Context, user *domain.User) error {
+	return s.userRepo.Update(ctx, user)
 }
 
-func (s *UserService) Update(user *domain.User) error {
-	return s.userRepo.Update(user)
+// SetLimitTier kullanıcıya limit seviyesi atar; kullanıcıya özel limitler seviyeye göre önceliklidir
+func (s *UserService) SetLimitTier(ctx context.Context, id string, tier domain.LimitTier) error {
+	if !tier.IsValid() {
+		return domain.ErrInvalidLimitTier
+	}
+	return s.userRepo.SetLimitTier(ctx, id, tier)
 }
 
-func (s *UserService) Delete(id string) error {
-	return s.userRepo.Delete(id)
+func (s *UserService) Delete(ctx context.Context, id string) error {
+	return s.userRepo.Delete(ctx, id)
 }
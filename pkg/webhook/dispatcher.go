@@ -0,0 +1,222 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"sync"
+	"time"
+
+	"transaction-api-w-go/pkg/circuitbreaker"
+	"transaction-api-w-go/pkg/clock"
+	"transaction-api-w-go/pkg/domain"
+	"transaction-api-w-go/pkg/metrics"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// DefaultRetryBatchSize tek yeniden deneme turunda gönderilen en fazla teslimat sayısı
+const DefaultRetryBatchSize = 100
+
+// DeliveryStore teslimatların ve deneme geçmişinin saklandığı depodur
+type DeliveryStore interface {
+	Create(ctx context.Context, delivery *domain.WebhookDelivery) error
+	Update(ctx context.Context, delivery *domain.WebhookDelivery) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.WebhookDelivery, error)
+	List(ctx context.Context, filter domain.WebhookDeliveryFilter) ([]*domain.WebhookDelivery, error)
+	// ListDue zamanı gelmiş pending ve retrying teslimatları en eskiden başlayarak döner
+	ListDue(ctx context.Context, now time.Time, limit int) ([]*domain.WebhookDelivery, error)
+	CreateAttempt(ctx context.Context, attempt *domain.WebhookDeliveryAttempt) error
+	ListAttempts(ctx context.Context, deliveryID uuid.UUID) ([]domain.WebhookDeliveryAttempt, error)
+}
+
+// DefaultDeliveryBreakerConfig alıcı başına circuit breaker ayarları; yanıt vermeyen bir alıcı
+// açık breaker süresince denenmez ve teslimatları deneme hakkı harcanmadan ertelenir
+func DefaultDeliveryBreakerConfig() circuitbreaker.Config {
+	return circuitbreaker.Config{
+		FailureThreshold:    5,
+		SuccessThreshold:    1,
+		Timeout:             time.Minute,
+		HalfOpenMaxRequests: 1,
+		WindowSize:          5 * time.Minute,
+		MinRequestCount:     5,
+	}
+}
+
+// Dispatcher webhook'ları kalıcı teslimat kayıtlarıyla en az bir kez teslim eder. Başarısız teslimatlar
+// üstel backoff ile yeniden denenir, deneme hakkı tükenenler dead-letter'a düşer ve elle yeniden
+// gönderilebilir. Her alıcı host'u kendi circuit breaker'ı ile korunur.
+type Dispatcher struct {
+	ctx         context.Context
+	store       DeliveryStore
+	notifier    *Notifier
+	clock       clock.Clock
+	maxAttempts int
+
+	mu       sync.Mutex
+	breakers map[string]*circuitbreaker.CircuitBreaker
+}
+
+func NewDispatcher(ctx context.Context, store DeliveryStore, notifier *Notifier, maxAttempts int) *Dispatcher {
+	return NewDispatcherWithClock(ctx, store, notifier, maxAttempts, clock.Real())
+}
+
+// NewDispatcherWithClock yeniden deneme zamanlarını verilen saate göre hesaplar. Alıcı breaker'ları ctx
+// iptal edildiğinde durur.
+func NewDispatcherWithClock(ctx context.Context, store DeliveryStore, notifier *Notifier, maxAttempts int, clk clock.Clock) *Dispatcher {
+	if maxAttempts <= 0 {
+		maxAttempts = domain.DefaultWebhookMaxAttempts
+	}
+	return &Dispatcher{
+		ctx:         ctx,
+		store:       store,
+		notifier:    notifier,
+		clock:       clk,
+		maxAttempts: maxAttempts,
+		breakers:    make(map[string]*circuitbreaker.CircuitBreaker),
+	}
+}
+
+// Enqueue teslimatı kaydeder ve hemen bir kez dener. Teslimat kaydedildiyse deneme başarısız olsa da
+// hata dönmez; yeniden denemeler RetryDue ile yapılır.
+func (d *Dispatcher) Enqueue(ctx context.Context, targetURL, event string, payload interface{}) (*domain.WebhookDelivery, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	delivery := domain.NewWebhookDelivery(targetURL, event, body, d.maxAttempts, d.clock.Now())
+	if err := d.store.Create(ctx, delivery); err != nil {
+		return nil, err
+	}
+	d.attempt(ctx, delivery)
+	return delivery, nil
+}
+
+// RetryDue zamanı gelmiş teslimatları dener ve denenen teslimat sayısını döner
+func (d *Dispatcher) RetryDue(ctx context.Context, limit int) (int, error) {
+	if limit <= 0 {
+		limit = DefaultRetryBatchSize
+	}
+
+	deliveries, err := d.store.ListDue(ctx, d.clock.Now(), limit)
+	if err != nil {
+		return 0, err
+	}
+	for _, delivery := range deliveries {
+		d.attempt(ctx, delivery)
+	}
+	return len(deliveries), nil
+}
+
+// Redeliver dead-letter'daki teslimatı sıfırlayıp hemen yeniden dener
+func (d *Dispatcher) Redeliver(ctx context.Context, id uuid.UUID) (*domain.WebhookDelivery, error) {
+	delivery, err := d.store.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := delivery.Redeliver(d.clock.Now()); err != nil {
+		return nil, err
+	}
+	if err := d.store.Update(ctx, delivery); err != nil {
+		return nil, err
+	}
+
+	d.attempt(ctx, delivery)
+	return delivery, nil
+}
+
+func (d *Dispatcher) ListDeliveries(ctx context.Context, filter domain.WebhookDeliveryFilter) ([]*domain.WebhookDelivery, error) {
+	filter.Normalize()
+	return d.store.List(ctx, filter)
+}
+
+// GetDelivery teslimatı deneme geçmişiyle birlikte döner
+func (d *Dispatcher) GetDelivery(ctx context.Context, id uuid.UUID) (*domain.WebhookDelivery, error) {
+	delivery, err := d.store.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if delivery.AttemptLog, err = d.store.ListAttempts(ctx, id); err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// attempt teslimatı bir kez gönderir, denemeyi ve sonucu kaydeder. Alıcının breaker'ı açıksa istek
+// gönderilmez ve teslimat deneme hakkı harcanmadan breaker süresi kadar ertelenir.
+func (d *Dispatcher) attempt(ctx context.Context, delivery *domain.WebhookDelivery) {
+	breaker := d.breakerFor(delivery.URL)
+	if !breaker.Ready() {
+		delivery.Defer(DefaultDeliveryBreakerConfig().Timeout, "circuit breaker open", d.clock.Now())
+		d.save(ctx, delivery)
+		return
+	}
+
+	start := time.Now()
+	var statusCode int
+	err := breaker.Execute(func() error {
+		var sendErr error
+		statusCode, sendErr = d.notifier.Send(ctx, delivery.URL, delivery.Event, delivery.ID.String(), delivery.Payload)
+		return sendErr
+	})
+	now := d.clock.Now()
+
+	attempt := &domain.WebhookDeliveryAttempt{
+		ID:         uuid.New(),
+		DeliveryID: delivery.ID,
+		Attempt:    delivery.Attempts + 1,
+		StatusCode: statusCode,
+		DurationMS: time.Since(start).Milliseconds(),
+		CreatedAt:  now,
+	}
+	if err != nil {
+		attempt.Error = err.Error()
+	}
+	if storeErr := d.store.CreateAttempt(ctx, attempt); storeErr != nil {
+		log.Error().Err(storeErr).Str("delivery_id", delivery.ID.String()).Msg("Failed to record webhook delivery attempt")
+	}
+
+	switch {
+	case err == nil:
+		delivery.RecordSuccess(statusCode, now)
+	case delivery.RecordFailure(statusCode, err.Error(), now):
+		log.Warn().Err(err).
+			Str("delivery_id", delivery.ID.String()).
+			Int("attempts", delivery.Attempts).
+			Time("next_attempt_at", *delivery.NextAttemptAt).
+			Msg("Webhook delivery failed, will retry")
+	default:
+		log.Error().Err(err).
+			Str("delivery_id", delivery.ID.String()).
+			Int("attempts", delivery.Attempts).
+			Msg("Webhook delivery moved to dead-letter")
+	}
+	metrics.WebhookDeliveriesTotal.WithLabelValues(delivery.Event, string(delivery.Status)).Inc()
+	d.save(ctx, delivery)
+}
+
+func (d *Dispatcher) save(ctx context.Context, delivery *domain.WebhookDelivery) {
+	if err := d.store.Update(ctx, delivery); err != nil {
+		log.Error().Err(err).Str("delivery_id", delivery.ID.String()).Msg("Failed to update webhook delivery")
+	}
+}
+
+// breakerFor alıcının host'una ait breaker'ı döner; ilk kullanımda oluşturulur
+func (d *Dispatcher) breakerFor(targetURL string) *circuitbreaker.CircuitBreaker {
+	host := targetURL
+	if parsed, err := url.Parse(targetURL); err == nil && parsed.Host != "" {
+		host = parsed.Host
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	breaker, ok := d.breakers[host]
+	if !ok {
+		breaker = circuitbreaker.NewCircuitBreakerWithContext(d.ctx, "webhook:"+host, DefaultDeliveryBreakerConfig())
+		d.breakers[host] = breaker
+	}
+	return breaker
+}
@@ -0,0 +1,118 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"transaction-api-w-go/pkg/clock"
+	"transaction-api-w-go/pkg/database/databasetest"
+	"transaction-api-w-go/pkg/domain"
+	"transaction-api-w-go/pkg/repository"
+)
+
+func TestDispatcherRetryAndDeadLetter(t *testing.T) {
+	tests := []struct {
+		name         string
+		failFirst    int32
+		maxAttempts  int
+		wantStatus   domain.WebhookDeliveryStatus
+		wantAttempts int
+	}{
+		{name: "hemen teslim edilir", failFirst: 0, maxAttempts: 3, wantStatus: domain.WebhookDeliveryDelivered, wantAttempts: 1},
+		{name: "geçici hata sonrası teslim edilir", failFirst: 2, maxAttempts: 3, wantStatus: domain.WebhookDeliveryDelivered, wantAttempts: 3},
+		{name: "kalıcı hata dead-letter'a düşer", failFirst: 100, maxAttempts: 3, wantStatus: domain.WebhookDeliveryDeadLetter, wantAttempts: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			const secret = "test-secret"
+			var calls atomic.Int32
+			var badSignatures atomic.Int32
+			deliveryIDs := make(chan string, 16)
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				timestamp, _ := strconv.ParseInt(r.Header.Get(TimestampHeader), 10, 64)
+				if !VerifySignature(secret, timestamp, body, r.Header.Get(SignatureHeader)) {
+					badSignatures.Add(1)
+				}
+				deliveryIDs <- r.Header.Get(DeliveryIDHeader)
+
+				if calls.Add(1) <= tt.failFirst {
+					w.WriteHeader(http.StatusInternalServerError)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			clk := clock.NewFake(time.Now())
+			notifier := NewNotifier(time.Second)
+			notifier.SetSecret(secret)
+			store := repository.NewWebhookDeliveryRepository(databasetest.Open(t))
+			dispatcher := NewDispatcherWithClock(ctx, store, notifier, tt.maxAttempts, clk)
+
+			delivery, err := dispatcher.Enqueue(ctx, server.URL, "transaction.completed", map[string]string{"id": "tx-1"})
+			if err != nil {
+				t.Fatalf("Enqueue: %v", err)
+			}
+
+			// Zamanı gelmeden yeniden deneme yapılmaz, backoff dolunca yapılır
+			for attempts := 1; attempts < tt.maxAttempts; attempts++ {
+				if n, err := dispatcher.RetryDue(ctx, 0); err != nil || n != 0 {
+					t.Fatalf("backoff dolmadan RetryDue = %d, %v; beklenen 0", n, err)
+				}
+				clk.Advance(domain.WebhookRetryBackoff(attempts))
+				if _, err := dispatcher.RetryDue(ctx, 0); err != nil {
+					t.Fatalf("RetryDue: %v", err)
+				}
+			}
+
+			got, err := dispatcher.GetDelivery(ctx, delivery.ID)
+			if err != nil {
+				t.Fatalf("GetDelivery: %v", err)
+			}
+			if got.Status != tt.wantStatus || got.Attempts != tt.wantAttempts {
+				t.Errorf("durum/deneme = %s/%d, beklenen %s/%d", got.Status, got.Attempts, tt.wantStatus, tt.wantAttempts)
+			}
+			if len(got.AttemptLog) != tt.wantAttempts {
+				t.Errorf("deneme geçmişi %d kayıt, beklenen %d", len(got.AttemptLog), tt.wantAttempts)
+			}
+			if int(calls.Load()) != tt.wantAttempts {
+				t.Errorf("alıcıya %d istek geldi, beklenen %d", calls.Load(), tt.wantAttempts)
+			}
+			if badSignatures.Load() != 0 {
+				t.Errorf("%d istek geçersiz imzayla geldi", badSignatures.Load())
+			}
+			for i := 0; i < tt.wantAttempts; i++ {
+				if id := <-deliveryIDs; id != delivery.ID.String() {
+					t.Errorf("teslimat kimliği = %q, beklenen %q; yeniden denemeler aynı kimliği taşımalı", id, delivery.ID)
+				}
+			}
+
+			// Dead-letter dışındaki teslimat elle yeniden gönderilemez; dead-letter'daki yeni haklarla denenir
+			redelivered, err := dispatcher.Redeliver(ctx, delivery.ID)
+			if tt.wantStatus != domain.WebhookDeliveryDeadLetter {
+				if !errors.Is(err, domain.ErrWebhookNotRedeliverable) {
+					t.Errorf("Redeliver = %v, beklenen ErrWebhookNotRedeliverable", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Redeliver: %v", err)
+			}
+			if redelivered.Status != domain.WebhookDeliveryRetrying || redelivered.Attempts != 1 {
+				t.Errorf("yeniden gönderim sonrası durum/deneme = %s/%d, beklenen retrying/1", redelivered.Status, redelivered.Attempts)
+			}
+		})
+	}
+}
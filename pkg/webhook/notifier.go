@@ -0,0 +1,76 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// DefaultTimeout tek bir webhook isteği için varsayılan zaman aşımı
+const DefaultTimeout = 5 * time.Second
+
+// Notifier olayları JSON gövdesiyle kullanıcı tanımlı URL'lere POST eder
+type Notifier struct {
+	client *http.Client
+	// secret boşsa istekler imzalanmaz
+	secret string
+}
+
+func NewNotifier(timeout time.Duration) *Notifier {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &Notifier{
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// SetSecret gönderilen her isteğin gövdesini verilen secret ile imzalar
+func (n *Notifier) SetSecret(secret string) {
+	n.secret = secret
+}
+
+// Notify payload'ı url'e gönderir; 2xx dışındaki yanıtlar hata sayılır
+func (n *Notifier) Notify(ctx context.Context, url, event string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	_, err = n.Send(ctx, url, event, "", body)
+	return err
+}
+
+// Send hazır gövdeyi url'e gönderir ve alıcının döndürdüğü durum kodunu döner; istek hiç
+// gönderilemediyse kod sıfırdır. deliveryID boş değilse DeliveryIDHeader ile iletilir.
+func (n *Notifier) Send(ctx context.Context, url, event, deliveryID string, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, event)
+	if deliveryID != "" {
+		req.Header.Set(DeliveryIDHeader, deliveryID)
+	}
+	if n.secret != "" {
+		timestamp := time.Now().Unix()
+		req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+		req.Header.Set(SignatureHeader, Sign(n.secret, timestamp, body))
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("webhook %s returned status %d", url, resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
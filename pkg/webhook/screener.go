@@ -0,0 +1,129 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"transaction-api-w-go/pkg/circuitbreaker"
+	"transaction-api-w-go/pkg/domain"
+)
+
+// ScreeningEvent tarama isteklerinde X-Webhook-Event başlığına yazılan olay adı
+const ScreeningEvent = "transaction.screening"
+
+// ScreenerBreakerName dolandırıcılık servisi circuit breaker'ının adı
+const ScreenerBreakerName = "fraud_screening"
+
+// DefaultScreenerBreakerConfig tarama servisi için circuit breaker ayarları; servis yanıt
+// vermediğinde her işlem zaman aşımını beklemesin diye breaker hızlı açılır
+func DefaultScreenerBreakerConfig() circuitbreaker.Config {
+	return circuitbreaker.Config{
+		FailureThreshold:    5,
+		SuccessThreshold:    2,
+		Timeout:             30 * time.Second,
+		HalfOpenMaxRequests: 1,
+		WindowSize:          time.Minute,
+		MinRequestCount:     5,
+	}
+}
+
+// screeningPayload tarama servisine gönderilen işlem özeti
+type screeningPayload struct {
+	TransactionID  string                 `json:"transaction_id"`
+	UserID         string                 `json:"user_id"`
+	Type           domain.TransactionType `json:"type"`
+	Amount         float64                `json:"amount"`
+	CounterpartyID string                 `json:"counterparty_id,omitempty"`
+	ReferenceID    string                 `json:"reference_id,omitempty"`
+}
+
+// Screener işlemleri senkron olarak dış dolandırıcılık servisine POST eder ve
+// {"decision":"approve|deny|hold","reason":"..."} biçimindeki yanıtı çözer
+type Screener struct {
+	client *http.Client
+	url    string
+	// breaker servis kesintisinde istekleri göndermeden kısa devre yapar; nil ise devre dışıdır
+	breaker *circuitbreaker.CircuitBreaker
+}
+
+func NewScreener(url string, timeout time.Duration) *Screener {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &Screener{
+		client: &http.Client{Timeout: timeout},
+		url:    url,
+	}
+}
+
+// SetCircuitBreaker tarama isteklerini verilen breaker üzerinden çalıştırır
+func (s *Screener) SetCircuitBreaker(breaker *circuitbreaker.CircuitBreaker) {
+	s.breaker = breaker
+}
+
+// CircuitBreaker kullanılan breaker'ı döner; ayarlanmamışsa nil
+func (s *Screener) CircuitBreaker() *circuitbreaker.CircuitBreaker {
+	return s.breaker
+}
+
+func (s *Screener) Screen(ctx context.Context, transaction *domain.Transaction) (*domain.ScreeningResult, error) {
+	payload := screeningPayload{
+		TransactionID: transaction.ID.String(),
+		UserID:        transaction.UserID.String(),
+		Type:          transaction.Type,
+		Amount:        transaction.Amount,
+		ReferenceID:   transaction.ReferenceID,
+	}
+	if transaction.CounterpartyID != nil {
+		payload.CounterpartyID = transaction.CounterpartyID.String()
+	}
+
+	var result *domain.ScreeningResult
+	call := func() error {
+		var err error
+		result, err = s.post(ctx, payload)
+		return err
+	}
+
+	if s.breaker == nil {
+		return result, call()
+	}
+	return result, s.breaker.Execute(call)
+}
+
+func (s *Screener) post(ctx context.Context, payload screeningPayload) (*domain.ScreeningResult, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, ScreeningEvent)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("screening service %s returned status %d", s.url, resp.StatusCode)
+	}
+
+	var result domain.ScreeningResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decode screening response: %w", err)
+	}
+	if !result.Decision.IsValid() {
+		return nil, fmt.Errorf("%w: %q", domain.ErrInvalidScreeningDecision, result.Decision)
+	}
+	return &result, nil
+}
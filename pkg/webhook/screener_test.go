@@ -0,0 +1,89 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"transaction-api-w-go/pkg/circuitbreaker"
+	"transaction-api-w-go/pkg/domain"
+
+	"github.com/google/uuid"
+)
+
+func TestScreenerScreen(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       int
+		body         string
+		breaker      bool
+		requests     int
+		wantDecision domain.ScreeningDecision
+		wantReason   string
+		wantErr      bool
+		wantInvalid  bool
+		wantCalls    int64
+	}{
+		{name: "onay", status: http.StatusOK, body: `{"decision":"approve"}`, requests: 1, wantDecision: domain.ScreeningApprove, wantCalls: 1},
+		{name: "ret nedeniyle", status: http.StatusOK, body: `{"decision":"deny","reason":"kara liste"}`, requests: 1, wantDecision: domain.ScreeningDeny, wantReason: "kara liste", wantCalls: 1},
+		{name: "bekletme", status: http.StatusOK, body: `{"decision":"hold"}`, requests: 1, wantDecision: domain.ScreeningHold, wantCalls: 1},
+		{name: "bilinmeyen karar", status: http.StatusOK, body: `{"decision":"maybe"}`, requests: 1, wantErr: true, wantInvalid: true, wantCalls: 1},
+		{name: "servis hatası", status: http.StatusServiceUnavailable, requests: 1, wantErr: true, wantCalls: 1},
+		{name: "açık breaker servise gitmez", status: http.StatusServiceUnavailable, breaker: true, requests: 5, wantErr: true, wantCalls: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int64
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls.Add(1)
+				if r.Method != http.MethodPost || r.Header.Get(EventHeader) != ScreeningEvent {
+					t.Errorf("istek = %s %s, beklenen POST %s", r.Method, r.Header.Get(EventHeader), ScreeningEvent)
+				}
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			screener := NewScreener(server.URL, time.Second)
+			if tt.breaker {
+				breaker := circuitbreaker.NewCircuitBreaker(ScreenerBreakerName, circuitbreaker.Config{
+					FailureThreshold:    2,
+					SuccessThreshold:    1,
+					Timeout:             time.Hour,
+					HalfOpenMaxRequests: 1,
+				})
+				defer breaker.Close()
+				screener.SetCircuitBreaker(breaker)
+			}
+
+			transaction := &domain.Transaction{ID: uuid.New(), UserID: uuid.New(), Type: domain.TransactionTypeDebit, Amount: 5000}
+			var (
+				result *domain.ScreeningResult
+				err    error
+			)
+			for i := 0; i < tt.requests; i++ {
+				result, err = screener.Screen(context.Background(), transaction)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Screen hatası = %v, hata bekleniyor: %v", err, tt.wantErr)
+			}
+			if tt.wantInvalid && !errors.Is(err, domain.ErrInvalidScreeningDecision) {
+				t.Errorf("Screen = %v, beklenen ErrInvalidScreeningDecision", err)
+			}
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("servise %d istek gitti, beklenen %d", got, tt.wantCalls)
+			}
+			if tt.wantErr {
+				return
+			}
+			if result.Decision != tt.wantDecision || result.Reason != tt.wantReason {
+				t.Errorf("sonuç = %s/%q, beklenen %s/%q", result.Decision, result.Reason, tt.wantDecision, tt.wantReason)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+)
+
+const (
+	// SignatureHeader gövdenin imzasını, TimestampHeader imzaya dahil edilen Unix zamanını taşır
+	SignatureHeader = "X-Webhook-Signature"
+	TimestampHeader = "X-Webhook-Timestamp"
+	// DeliveryIDHeader yeniden denemelerde değişmez; alıcılar tekrarlanan teslimatları bununla ayıklar
+	DeliveryIDHeader = "X-Webhook-Delivery"
+	EventHeader      = "X-Webhook-Event"
+)
+
+// Sign alıcıların doğrulaması gereken imzayı üretir: hex(HMAC-SHA256(secret, TIMESTAMP "." BODY))
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte{'.'})
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature imzanın secret ile üretildiğini sabit zamanlı karşılaştırmayla doğrular
+func VerifySignature(secret string, timestamp int64, body []byte, signature string) bool {
+	expected := Sign(secret, timestamp, body)
+	return hmac.Equal([]byte(expected), []byte(strings.ToLower(signature)))
+}
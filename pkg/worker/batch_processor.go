@@ -2,13 +2,35 @@ package worker
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"sync"
 	"time"
 	"transaction-api-w-go/pkg/domain"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	BatchOperationAdd      = "add"
+	BatchOperationWithdraw = "withdraw"
+	// BatchOperationTransfer FromUserID hesabından UserIDs içindeki her kullanıcıya Amount aktarır
+	BatchOperationTransfer = "transfer"
 )
 
+// DefaultBatchJobTimeout tek bir batch job'ın tüm bakiye işlemleri için verilen süre
+const DefaultBatchJobTimeout = 30 * time.Second
+
+// DefaultProcessedJobTTL işlenmiş job id'lerinin tekrar kontrolü için saklanma süresi
+const DefaultProcessedJobTTL = 24 * time.Hour
+
+var ErrUnknownBatchOperation = errors.New("unknown batch operation")
+
 type BatchJob struct {
+	// ID boş değilse aynı id ile gelen tekrar gönderimler TTL süresince atlanır
+	ID          string
 	UserIDs     []uint
+	FromUserID  uint
 	Amount      float64
 	Description string
 	Operation   string
@@ -21,6 +43,22 @@ type BatchProcessor struct {
 	ctx            context.Context
 	cancel         context.CancelFunc
 	stats          *BatchStats
+	jobTimeout     time.Duration
+	killSwitch     AutomationSwitch
+
+	processedTTL time.Duration
+	processed    map[string]BatchJobOutcome
+	processedMu  sync.Mutex
+}
+
+// BatchJobOutcome id'li bir batch job'ın sonucunu tutar
+type BatchJobOutcome struct {
+	JobID        string    `json:"job_id"`
+	SuccessCount int       `json:"success_count"`
+	FailedCount  int       `json:"failed_count"`
+	TotalAmount  float64   `json:"total_amount"`
+	ProcessedAt  time.Time `json:"processed_at"`
+	expiresAt    time.Time
 }
 
 type BatchStats struct {
@@ -28,7 +66,9 @@ type BatchStats struct {
 	TotalFailed        uint64
 	TotalAmount        float64
 	AverageProcessTime float64
-	mu                 sync.RWMutex
+	// TotalBatches ortalama işlem süresinin hesaplandığı batch sayısı
+	TotalBatches uint64
+	mu           sync.RWMutex
 }
 
 func NewBatchProcessor(balanceService domain.BalanceService) *BatchProcessor {
@@ -40,7 +80,59 @@ func NewBatchProcessor(balanceService domain.BalanceService) *BatchProcessor {
 		ctx:            ctx,
 		cancel:         cancel,
 		stats:          &BatchStats{},
+		jobTimeout:     DefaultBatchJobTimeout,
+		processedTTL:   DefaultProcessedJobTTL,
+		processed:      make(map[string]BatchJobOutcome),
+	}
+}
+
+// SetJobTimeout her batch job için uygulanacak süre sınırını ayarlar
+func (p *BatchProcessor) SetJobTimeout(timeout time.Duration) {
+	if timeout > 0 {
+		p.jobTimeout = timeout
+	}
+}
+
+// SetKillSwitch kuyruktaki job'ların işlenmeden önce kontrol edeceği duraklatma anahtarını bağlar;
+// Start'tan önce çağrılmalıdır
+func (p *BatchProcessor) SetKillSwitch(sw AutomationSwitch) {
+	p.killSwitch = sw
+}
+
+// GetJobOutcome id'si verilen job'ın kaydedilmiş sonucunu döner
+func (p *BatchProcessor) GetJobOutcome(jobID string) (BatchJobOutcome, bool) {
+	p.processedMu.Lock()
+	defer p.processedMu.Unlock()
+
+	outcome, ok := p.processed[jobID]
+	if !ok || time.Now().After(outcome.expiresAt) {
+		return BatchJobOutcome{}, false
 	}
+	return outcome, true
+}
+
+// isDuplicate job id daha önce işlendiyse true döner; süresi dolan kayıtları temizler
+func (p *BatchProcessor) isDuplicate(jobID string) bool {
+	p.processedMu.Lock()
+	defer p.processedMu.Unlock()
+
+	now := time.Now()
+	for id, outcome := range p.processed {
+		if now.After(outcome.expiresAt) {
+			delete(p.processed, id)
+		}
+	}
+
+	_, ok := p.processed[jobID]
+	return ok
+}
+
+func (p *BatchProcessor) recordOutcome(outcome BatchJobOutcome) {
+	p.processedMu.Lock()
+	defer p.processedMu.Unlock()
+
+	outcome.expiresAt = outcome.ProcessedAt.Add(p.processedTTL)
+	p.processed[outcome.JobID] = outcome
 }
 
 func (p *BatchProcessor) Start() {
@@ -48,8 +140,10 @@ func (p *BatchProcessor) Start() {
 	go p.process()
 }
 
+// Stop kuyruğu kapatır ve context'i iptal ederek devam eden bakiye işlemlerini durdurur
 func (p *BatchProcessor) Stop() {
 	close(p.jobQueue)
+	p.cancel()
 
 	p.wg.Wait()
 }
@@ -61,34 +155,67 @@ func (p *BatchProcessor) SubmitJob(job BatchJob) {
 	}
 }
 
-func (p *BatchProcessor) GetStats() BatchStats {
+func (p *BatchProcessor) GetStats() domain.TransactionStatsSnapshot {
 	p.stats.mu.RLock()
 	defer p.stats.mu.RUnlock()
-	return *p.stats
+
+	return domain.TransactionStatsSnapshot{
+		TotalProcessed:     p.stats.TotalProcessed,
+		TotalFailed:        p.stats.TotalFailed,
+		TotalAmount:        p.stats.TotalAmount,
+		AverageProcessTime: p.stats.AverageProcessTime,
+	}
+}
+
+// QueueLength kuyrukta bekleyen batch job sayısını döner
+func (p *BatchProcessor) QueueLength() int {
+	return len(p.jobQueue)
 }
 
 func (p *BatchProcessor) process() {
 	defer p.wg.Done()
 
 	for job := range p.jobQueue {
+		if !waitWhilePaused(p.ctx, p.killSwitch, "batch_processor") {
+			log.Warn().Str("job_id", job.ID).Msg("Batch processor stopped while paused, dropping job")
+			continue
+		}
+		if job.ID != "" && p.isDuplicate(job.ID) {
+			log.Info().Str("job_id", job.ID).Msg("Skipping duplicate batch job")
+			continue
+		}
+
 		startTime := time.Now()
 
 		successCount, failedCount, totalAmount := p.processBatch(job)
 
+		if job.ID != "" {
+			p.recordOutcome(BatchJobOutcome{
+				JobID:        job.ID,
+				SuccessCount: successCount,
+				FailedCount:  failedCount,
+				TotalAmount:  totalAmount,
+				ProcessedAt:  time.Now(),
+			})
+		}
+
 		p.stats.mu.Lock()
 		p.stats.TotalProcessed += uint64(successCount)
 		p.stats.TotalFailed += uint64(failedCount)
 		p.stats.TotalAmount += totalAmount
 
+		// Ortalama batch başına hesaplanır; TotalProcessed başarılı öğe sayısıdır ve ağırlık olarak kullanılamaz
 		processTime := time.Since(startTime).Seconds()
-		currentTotal := p.stats.TotalProcessed
-		currentAvg := p.stats.AverageProcessTime
-		p.stats.AverageProcessTime = (currentAvg*float64(currentTotal) + processTime) / float64(currentTotal+1)
+		p.stats.TotalBatches++
+		p.stats.AverageProcessTime += (processTime - p.stats.AverageProcessTime) / float64(p.stats.TotalBatches)
 		p.stats.mu.Unlock()
 	}
 }
 
 func (p *BatchProcessor) processBatch(job BatchJob) (successCount, failedCount int, totalAmount float64) {
+	ctx, cancel := context.WithTimeout(p.ctx, p.jobTimeout)
+	defer cancel()
+
 	var wg sync.WaitGroup
 	var mu sync.Mutex
 
@@ -97,11 +224,13 @@ func (p *BatchProcessor) processBatch(job BatchJob) (successCount, failedCount i
 		go func(uid uint) {
 			defer wg.Done()
 
-			var err error
-			if job.Operation == "add" {
-				err = p.balanceService.AddFunds(context.Background(), uid, job.Amount)
-			} else if job.Operation == "withdraw" {
-				err = p.balanceService.WithdrawFunds(context.Background(), uid, job.Amount)
+			err := p.applyOperation(ctx, job, uid)
+			if err != nil {
+				log.Warn().
+					Err(err).
+					Str("operation", job.Operation).
+					Uint("user_id", uid).
+					Msg("Batch item failed")
 			}
 
 			mu.Lock()
@@ -118,3 +247,20 @@ func (p *BatchProcessor) processBatch(job BatchJob) (successCount, failedCount i
 	wg.Wait()
 	return
 }
+
+func (p *BatchProcessor) applyOperation(ctx context.Context, job BatchJob, userID uint) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	switch job.Operation {
+	case BatchOperationAdd:
+		return p.balanceService.AddFunds(ctx, userID, job.Amount)
+	case BatchOperationWithdraw:
+		return p.balanceService.WithdrawFunds(ctx, userID, job.Amount)
+	case BatchOperationTransfer:
+		return p.balanceService.TransferFunds(ctx, job.FromUserID, userID, job.Amount)
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownBatchOperation, job.Operation)
+	}
+}
@@ -0,0 +1,304 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"transaction-api-w-go/pkg/domain"
+)
+
+// fakeBalanceService bakiyeleri bellekte tutar; yalnızca batch processor'ın kullandığı metodları uygular
+type fakeBalanceService struct {
+	domain.BalanceService
+	mu       sync.Mutex
+	balances map[uint]float64
+	// delay sıfırdan büyükse AddFunds bu süre kadar veya context iptal edilene kadar bekler
+	delay   time.Duration
+	started chan struct{}
+	// userDelays kullanıcı bazında delay'i geçersiz kılar
+	userDelays map[uint]time.Duration
+}
+
+func newFakeBalanceService(balances map[uint]float64) *fakeBalanceService {
+	svc := &fakeBalanceService{balances: make(map[uint]float64)}
+	for id, amount := range balances {
+		svc.balances[id] = amount
+	}
+	return svc
+}
+
+func (s *fakeBalanceService) AddFunds(ctx context.Context, userID uint, amount float64) error {
+	delay := s.delay
+	if d, ok := s.userDelays[userID]; ok {
+		delay = d
+	}
+	if delay > 0 {
+		if s.started != nil {
+			s.started <- struct{}{}
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.balances[userID] += amount
+	return nil
+}
+
+func (s *fakeBalanceService) WithdrawFunds(ctx context.Context, userID uint, amount float64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.balances[userID] < amount {
+		return domain.ErrInsufficientBalance
+	}
+	s.balances[userID] -= amount
+	return nil
+}
+
+func (s *fakeBalanceService) TransferFunds(ctx context.Context, fromUserID, toUserID uint, amount float64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.balances[fromUserID] < amount {
+		return domain.ErrInsufficientBalance
+	}
+	s.balances[fromUserID] -= amount
+	s.balances[toUserID] += amount
+	return nil
+}
+
+func (s *fakeBalanceService) balance(userID uint) float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.balances[userID]
+}
+
+// runBatchJobs job'ları işler, batches kadar batch tamamlanınca processor'ı durdurur.
+// Stop devam eden işlemleri iptal ettiğinden önce işlerin bitmesi beklenir.
+func runBatchJobs(t *testing.T, processor *BatchProcessor, batches uint64, jobs ...BatchJob) {
+	t.Helper()
+	processor.Start()
+	defer processor.Stop()
+
+	for _, job := range jobs {
+		processor.SubmitJob(job)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		processor.stats.mu.RLock()
+		done := processor.stats.TotalBatches >= batches && processor.QueueLength() == 0
+		processor.stats.mu.RUnlock()
+		if done {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%d batch işlenmesi beklenirken süre doldu", batches)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestBatchProcessorOperations(t *testing.T) {
+	tests := []struct {
+		name         string
+		balances     map[uint]float64
+		job          BatchJob
+		wantBalances map[uint]float64
+		wantSuccess  uint64
+		wantFailed   uint64
+		wantAmount   float64
+	}{
+		{
+			name:         "transfer her alıcıya aktarır",
+			balances:     map[uint]float64{1: 100},
+			job:          BatchJob{Operation: BatchOperationTransfer, FromUserID: 1, UserIDs: []uint{2, 3}, Amount: 30},
+			wantBalances: map[uint]float64{1: 40, 2: 30, 3: 30},
+			wantSuccess:  2,
+			wantAmount:   60,
+		},
+		{
+			name:         "yetersiz bakiyeli transfer başarısız sayılır",
+			balances:     map[uint]float64{1: 50},
+			job:          BatchJob{Operation: BatchOperationTransfer, FromUserID: 1, UserIDs: []uint{2, 3}, Amount: 30},
+			wantBalances: map[uint]float64{1: 20},
+			wantSuccess:  1,
+			wantFailed:   1,
+			wantAmount:   30,
+		},
+		{
+			name:         "bilinmeyen işlem başarısız sayılır",
+			balances:     map[uint]float64{2: 10},
+			job:          BatchJob{Operation: "refund", UserIDs: []uint{2, 3}, Amount: 5},
+			wantBalances: map[uint]float64{2: 10, 3: 0},
+			wantFailed:   2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			balances := newFakeBalanceService(tt.balances)
+			processor := NewBatchProcessor(balances)
+
+			runBatchJobs(t, processor, 1, tt.job)
+
+			stats := processor.GetStats()
+			if stats.TotalProcessed != tt.wantSuccess || stats.TotalFailed != tt.wantFailed {
+				t.Errorf("başarılı/başarısız = %d/%d, beklenen %d/%d", stats.TotalProcessed, stats.TotalFailed, tt.wantSuccess, tt.wantFailed)
+			}
+			if stats.TotalAmount != tt.wantAmount {
+				t.Errorf("toplam tutar = %v, beklenen %v", stats.TotalAmount, tt.wantAmount)
+			}
+			for userID, want := range tt.wantBalances {
+				if got := balances.balance(userID); got != want {
+					t.Errorf("kullanıcı %d bakiyesi = %v, beklenen %v", userID, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestBatchProcessorCancelsSlowBatch(t *testing.T) {
+	tests := []struct {
+		name       string
+		jobTimeout time.Duration
+		stop       bool
+	}{
+		{name: "processor durdurulunca iptal edilir", stop: true},
+		{name: "job süresi dolunca iptal edilir", jobTimeout: 50 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			balances := newFakeBalanceService(nil)
+			balances.delay = time.Minute
+			balances.started = make(chan struct{}, 2)
+			processor := NewBatchProcessor(balances)
+			processor.SetJobTimeout(tt.jobTimeout)
+
+			start := time.Now()
+			processor.Start()
+			processor.SubmitJob(BatchJob{Operation: BatchOperationAdd, UserIDs: []uint{1, 2}, Amount: 10})
+
+			for i := 0; i < 2; i++ {
+				select {
+				case <-balances.started:
+				case <-time.After(5 * time.Second):
+					t.Fatal("bakiye işlemi başlamadı")
+				}
+			}
+
+			if tt.stop {
+				processor.Stop()
+			} else {
+				defer processor.Stop()
+				deadline := time.Now().Add(5 * time.Second)
+				for processor.GetStats().TotalFailed < 2 && time.Now().Before(deadline) {
+					time.Sleep(time.Millisecond)
+				}
+			}
+
+			if elapsed := time.Since(start); elapsed > 5*time.Second {
+				t.Fatalf("yavaş batch %v sürdü, iptal beklenmiyordu", elapsed)
+			}
+			stats := processor.GetStats()
+			if stats.TotalProcessed != 0 || stats.TotalFailed != 2 {
+				t.Errorf("başarılı/başarısız = %d/%d, beklenen 0/2", stats.TotalProcessed, stats.TotalFailed)
+			}
+			if got := balances.balance(1) + balances.balance(2); got != 0 {
+				t.Errorf("iptal edilen işlemler bakiyeyi değiştirdi: %v", got)
+			}
+		})
+	}
+}
+
+func TestBatchProcessorSkipsDuplicateJobID(t *testing.T) {
+	tests := []struct {
+		name        string
+		ids         [2]string
+		wantBatches uint64
+		wantBalance float64
+	}{
+		{name: "aynı id bir kez uygulanır", ids: [2]string{"job-1", "job-1"}, wantBatches: 2, wantBalance: 10},
+		{name: "farklı id'ler ayrı uygulanır", ids: [2]string{"job-1", "job-2"}, wantBatches: 3, wantBalance: 20},
+		{name: "id'siz job'lar tekrar uygulanır", wantBatches: 3, wantBalance: 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			balances := newFakeBalanceService(nil)
+			processor := NewBatchProcessor(balances)
+
+			// Son job kuyruğun boşaldığını anlamak için ayrı bir kullanıcıya gönderilir
+			runBatchJobs(t, processor, tt.wantBatches,
+				BatchJob{ID: tt.ids[0], Operation: BatchOperationAdd, UserIDs: []uint{1}, Amount: 10},
+				BatchJob{ID: tt.ids[1], Operation: BatchOperationAdd, UserIDs: []uint{1}, Amount: 10},
+				BatchJob{ID: "marker", Operation: BatchOperationAdd, UserIDs: []uint{2}, Amount: 1},
+			)
+
+			if got := balances.balance(1); got != tt.wantBalance {
+				t.Errorf("bakiye = %v, beklenen %v", got, tt.wantBalance)
+			}
+			if tt.ids[0] == "" {
+				return
+			}
+			outcome, ok := processor.GetJobOutcome(tt.ids[0])
+			if !ok {
+				t.Fatalf("%s sonucu kaydedilmedi", tt.ids[0])
+			}
+			if outcome.SuccessCount != 1 || outcome.TotalAmount != 10 {
+				t.Errorf("sonuç = %+v, beklenen 1 başarılı ve 10 tutar", outcome)
+			}
+		})
+	}
+}
+
+func TestBatchProcessorAverageProcessTime(t *testing.T) {
+	tests := []struct {
+		name    string
+		jobs    []BatchJob
+		delays  map[uint]time.Duration
+		wantMin time.Duration
+		wantMax time.Duration
+	}{
+		{
+			name: "ortalama öğe sayısından bağımsız batch başına hesaplanır",
+			jobs: []BatchJob{
+				{Operation: BatchOperationAdd, UserIDs: []uint{1, 2}, Amount: 1},
+				{Operation: BatchOperationAdd, UserIDs: []uint{3}, Amount: 1},
+			},
+			delays:  map[uint]time.Duration{1: 10 * time.Millisecond, 2: 10 * time.Millisecond, 3: 90 * time.Millisecond},
+			wantMin: 50 * time.Millisecond,
+			wantMax: 75 * time.Millisecond,
+		},
+		{
+			name: "tek batch kendi süresini verir",
+			jobs: []BatchJob{
+				{Operation: BatchOperationAdd, UserIDs: []uint{1, 2, 3}, Amount: 1},
+			},
+			delays:  map[uint]time.Duration{1: 20 * time.Millisecond, 2: 40 * time.Millisecond, 3: 40 * time.Millisecond},
+			wantMin: 40 * time.Millisecond,
+			wantMax: 65 * time.Millisecond,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			balances := newFakeBalanceService(nil)
+			balances.userDelays = tt.delays
+			processor := NewBatchProcessor(balances)
+
+			runBatchJobs(t, processor, uint64(len(tt.jobs)), tt.jobs...)
+
+			average := time.Duration(processor.GetStats().AverageProcessTime * float64(time.Second))
+			if average < tt.wantMin || average >= tt.wantMax {
+				t.Errorf("ortalama işlem süresi = %v, beklenen [%v, %v)", average, tt.wantMin, tt.wantMax)
+			}
+		})
+	}
+}
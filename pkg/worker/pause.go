@@ -0,0 +1,41 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// DefaultPausePollInterval otomatik işlemler duraklatılmışken anahtarın yeniden kontrol edilme aralığı
+const DefaultPausePollInterval = time.Second
+
+// AutomationSwitch otomatik işlemlerin cluster genelinde duraklatılıp duraklatılmadığını bildirir
+// (ör. killswitch.Switch)
+type AutomationSwitch interface {
+	Engaged(ctx context.Context) bool
+}
+
+// waitWhilePaused anahtar çekili olduğu sürece bekler; kuyruktaki işler düşürülmez, anahtar
+// bırakılınca kaldıkları yerden işlenir. ctx iptal edilirse false döner.
+func waitWhilePaused(ctx context.Context, sw AutomationSwitch, worker string) bool {
+	if sw == nil || !sw.Engaged(ctx) {
+		return true
+	}
+
+	log.Warn().Str("worker", worker).Msg("Automation paused by kill switch, holding queued jobs")
+	ticker := time.NewTicker(DefaultPausePollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-ticker.C:
+			if !sw.Engaged(ctx) {
+				log.Info().Str("worker", worker).Msg("Automation resumed, processing queued jobs")
+				return true
+			}
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"transaction-api-w-go/pkg/domain"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	defaultProjectionBatchSize    = 500
+	defaultProjectionPollInterval = 5 * time.Second
+)
+
+// ProjectionRunner bir projeksiyonu checkpoint'inden itibaren event akışı üzerinde çalıştırır.
+// Checkpoint yalnızca başarıyla işlenen event'ler için ilerletilir; hata alan event bir sonraki
+// turda yeniden denenir, daha önce işlenenler tekrar işlenmez
+type ProjectionRunner struct {
+	eventStore   domain.EventStore
+	checkpoints  domain.CheckpointStore
+	projection   domain.Projection
+	batchSize    int
+	pollInterval time.Duration
+	ctx          context.Context
+	cancel       context.CancelFunc
+	wg           sync.WaitGroup
+	runMu        sync.Mutex
+}
+
+func NewProjectionRunner(eventStore domain.EventStore, checkpoints domain.CheckpointStore, projection domain.Projection, batchSize int, pollInterval time.Duration) *ProjectionRunner {
+	if batchSize <= 0 {
+		batchSize = defaultProjectionBatchSize
+	}
+	if pollInterval <= 0 {
+		pollInterval = defaultProjectionPollInterval
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &ProjectionRunner{
+		eventStore:   eventStore,
+		checkpoints:  checkpoints,
+		projection:   projection,
+		batchSize:    batchSize,
+		pollInterval: pollInterval,
+		ctx:          ctx,
+		cancel:       cancel,
+	}
+}
+
+func (r *ProjectionRunner) Start() {
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+
+		ticker := time.NewTicker(r.pollInterval)
+		defer ticker.Stop()
+
+		for {
+			if _, err := r.RunOnce(r.ctx); err != nil && r.ctx.Err() == nil {
+				log.Error().Err(err).Str("projection", r.projection.Name()).Msg("Projection run failed")
+			}
+
+			select {
+			case <-r.ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (r *ProjectionRunner) Stop() {
+	r.cancel()
+	r.wg.Wait()
+}
+
+// RunOnce checkpoint'ten sonraki tüm event'leri batch'ler halinde işler ve işlenen event sayısını döndürür
+func (r *ProjectionRunner) RunOnce(ctx context.Context) (int, error) {
+	r.runMu.Lock()
+	defer r.runMu.Unlock()
+
+	name := r.projection.Name()
+	checkpoint, err := r.checkpoints.Get(ctx, name)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load checkpoint for projection %s: %w", name, err)
+	}
+	if checkpoint == nil {
+		checkpoint = &domain.ProjectionCheckpoint{ProjectionName: name}
+	}
+
+	// Bu turda okunacak üst sınır sabitlenir; tur sırasında yazılan event'ler bir sonraki turda işlenir
+	endTime := time.Now()
+	var cursor *domain.EventCursor
+	if checkpoint.ProcessedCount > 0 {
+		cursor = checkpoint.Cursor()
+	}
+
+	processed := 0
+	for {
+		events, err := r.eventStore.GetEventsAfter(ctx, time.Time{}, endTime, cursor, r.batchSize)
+		if err != nil {
+			return processed, fmt.Errorf("failed to read events for projection %s: %w", name, err)
+		}
+
+		handled := 0
+		var handleErr error
+		for _, event := range events {
+			if err := r.projection.Handle(ctx, event); err != nil {
+				handleErr = fmt.Errorf("projection %s failed on event %s: %w", name, event.GetID(), err)
+				break
+			}
+			checkpoint.Advance(event)
+			handled++
+		}
+
+		// Batch içinde başarıyla işlenen kısım hata olsa da kalıcı hale getirilir
+		if handled > 0 {
+			if err := r.checkpoints.Save(ctx, checkpoint); err != nil {
+				return processed, fmt.Errorf("failed to save checkpoint for projection %s: %w", name, err)
+			}
+			processed += handled
+		}
+		if handleErr != nil {
+			return processed, handleErr
+		}
+		if len(events) < r.batchSize {
+			return processed, nil
+		}
+		cursor = checkpoint.Cursor()
+	}
+}
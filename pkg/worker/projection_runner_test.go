@@ -0,0 +1,108 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"transaction-api-w-go/pkg/database/databasetest"
+	"transaction-api-w-go/pkg/domain"
+	"transaction-api-w-go/pkg/repository"
+
+	"github.com/google/uuid"
+)
+
+// recordingProjection işlediği event'leri sırayla kaydeder; failOn verilirse o event'te hata döner
+type recordingProjection struct {
+	handled []uuid.UUID
+	failOn  uuid.UUID
+}
+
+func (p *recordingProjection) Name() string { return "test-projection" }
+
+func (p *recordingProjection) Handle(ctx context.Context, event domain.Event) error {
+	if event.GetID() == p.failOn {
+		return errors.New("projeksiyon hatası")
+	}
+	p.handled = append(p.handled, event.GetID())
+	return nil
+}
+
+func TestProjectionRunnerResumesFromCheckpoint(t *testing.T) {
+	tests := []struct {
+		name          string
+		initial       int
+		failAt        int
+		later         int
+		wantFirstRun  int
+		wantSecondRun int
+	}{
+		{name: "yeniden başlatmada yalnızca yeni event'ler işlenir", initial: 5, failAt: -1, later: 3, wantFirstRun: 5, wantSecondRun: 3},
+		{name: "hata alan event'ten devam edilir", initial: 5, failAt: 2, wantFirstRun: 2, wantSecondRun: 3},
+		{name: "yeni event yokken hiçbir şey işlenmez", initial: 4, failAt: -1, wantFirstRun: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := databasetest.Open(t)
+			ctx := context.Background()
+			eventStore := repository.NewPostgresEventStore(db)
+			checkpoints := repository.NewPostgresCheckpointStore(db)
+
+			base := time.Now().Add(-time.Hour).Truncate(time.Second)
+			var ids []uuid.UUID
+			writeEvents := func(n int) {
+				t.Helper()
+				for i := 0; i < n; i++ {
+					transaction := &domain.Transaction{ID: uuid.New(), UserID: uuid.New(), Type: domain.TransactionTypeCredit, Amount: 10}
+					event := domain.NewTransactionCreatedEvent(transaction)
+					event.Timestamp = base.Add(time.Duration(len(ids)) * time.Second)
+					if err := eventStore.SaveEvents(ctx, transaction.ID, []domain.Event{event}, 0); err != nil {
+						t.Fatalf("SaveEvents: %v", err)
+					}
+					ids = append(ids, event.ID)
+				}
+			}
+
+			writeEvents(tt.initial)
+			first := &recordingProjection{}
+			if tt.failAt >= 0 {
+				first.failOn = ids[tt.failAt]
+			}
+			processed, err := NewProjectionRunner(eventStore, checkpoints, first, 2, time.Minute).RunOnce(ctx)
+			if (err != nil) != (tt.failAt >= 0) {
+				t.Fatalf("ilk RunOnce hatası = %v", err)
+			}
+			if processed != tt.wantFirstRun || len(first.handled) != tt.wantFirstRun {
+				t.Fatalf("ilk turda işlenen = %d (%d kayıt), beklenen %d", processed, len(first.handled), tt.wantFirstRun)
+			}
+
+			// Yeniden başlatma: açılışta migration'lar tekrar çalışır, yeni runner ve projeksiyon yalnızca
+			// veritabanındaki event'leri ve checkpoint'i paylaşır
+			databasetest.Migrate(t, db)
+			writeEvents(tt.later)
+			second := &recordingProjection{}
+			processed, err = NewProjectionRunner(eventStore, checkpoints, second, 2, time.Minute).RunOnce(ctx)
+			if err != nil {
+				t.Fatalf("ikinci RunOnce: %v", err)
+			}
+			if processed != tt.wantSecondRun {
+				t.Fatalf("ikinci turda işlenen = %d, beklenen %d", processed, tt.wantSecondRun)
+			}
+			for i, id := range second.handled {
+				if want := ids[tt.wantFirstRun+i]; id != want {
+					t.Errorf("ikinci turun %d. event'i = %s, beklenen %s", i+1, id, want)
+				}
+			}
+
+			checkpoint, err := checkpoints.Get(ctx, first.Name())
+			if err != nil || checkpoint == nil {
+				t.Fatalf("checkpoint okunamadı: %v", err)
+			}
+			if checkpoint.LastEventID != ids[len(ids)-1] || checkpoint.ProcessedCount != int64(len(ids)) {
+				t.Errorf("checkpoint = %s/%d, beklenen %s/%d", checkpoint.LastEventID, checkpoint.ProcessedCount, ids[len(ids)-1], len(ids))
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"transaction-api-w-go/pkg/domain"
+	"transaction-api-w-go/pkg/repository"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// DefaultReconciliationInterval mutabakat job'unun varsayılan çalışma aralığı
+const DefaultReconciliationInterval = 24 * time.Hour
+
+// ReconciliationJob her kullanıcının bakiyesini işlem geçmişinden yeniden hesaplar, farkları
+// rapor tablosuna yazar ve fark bulunduğunda alarm event'i yayınlar
+type ReconciliationJob struct {
+	repo       *repository.ReconciliationRepository
+	eventStore domain.EventStore
+	interval   time.Duration
+	tolerance  float64
+	ctx        context.Context
+	cancel     context.CancelFunc
+	wg         sync.WaitGroup
+}
+
+func NewReconciliationJob(repo *repository.ReconciliationRepository, eventStore domain.EventStore, interval time.Duration) *ReconciliationJob {
+	if interval <= 0 {
+		interval = DefaultReconciliationInterval
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &ReconciliationJob{
+		repo:       repo,
+		eventStore: eventStore,
+		interval:   interval,
+		tolerance:  domain.DefaultReconciliationTolerance,
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+}
+
+func (j *ReconciliationJob) Start() {
+	j.wg.Add(1)
+	go func() {
+		defer j.wg.Done()
+
+		ticker := time.NewTicker(j.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-j.ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := j.RunOnce(j.ctx); err != nil {
+					log.Error().Err(err).Msg("Reconciliation job failed")
+				}
+			}
+		}
+	}()
+}
+
+func (j *ReconciliationJob) Stop() {
+	j.cancel()
+	j.wg.Wait()
+}
+
+// RunOnce mutabakatı bir kez çalıştırır ve raporu kaydeder
+func (j *ReconciliationJob) RunOnce(ctx context.Context) (*domain.ReconciliationReport, error) {
+	start := time.Now()
+
+	discrepancies, checked, err := j.repo.FindDiscrepancies(ctx, j.tolerance)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &domain.ReconciliationReport{
+		ID:               uuid.New(),
+		RunAt:            start,
+		UsersChecked:     checked,
+		DiscrepancyCount: len(discrepancies),
+		Discrepancies:    discrepancies,
+		Duration:         time.Since(start).String(),
+		CreatedAt:        time.Now(),
+	}
+
+	if err := j.repo.SaveReport(ctx, report); err != nil {
+		return nil, err
+	}
+
+	if report.HasDiscrepancies() {
+		log.Warn().
+			Str("report_id", report.ID.String()).
+			Int("discrepancies", report.DiscrepancyCount).
+			Msg("Balance reconciliation found discrepancies")
+		j.alert(ctx, report)
+	} else {
+		log.Info().
+			Int64("users_checked", checked).
+			Msg("Balance reconciliation completed")
+	}
+
+	return report, nil
+}
+
+func (j *ReconciliationJob) LatestReport(ctx context.Context) (*domain.ReconciliationReport, error) {
+	return j.repo.GetLatestReport(ctx)
+}
+
+func (j *ReconciliationJob) alert(ctx context.Context, report *domain.ReconciliationReport) {
+	if j.eventStore == nil {
+		return
+	}
+
+	event := domain.NewReconciliationDiscrepancyEvent(report)
+	if err := j.eventStore.SaveEvents(ctx, report.ID, []domain.Event{event}, 0); err != nil {
+		log.Error().Err(err).Str("report_id", report.ID.String()).Msg("Failed to save reconciliation alert event")
+	}
+}
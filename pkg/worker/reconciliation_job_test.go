@@ -0,0 +1,102 @@
+package worker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"transaction-api-w-go/pkg/database/databasetest"
+	"transaction-api-w-go/pkg/domain"
+	"transaction-api-w-go/pkg/repository"
+
+	"github.com/google/uuid"
+)
+
+func TestReconciliationJobRunOnce(t *testing.T) {
+	tests := []struct {
+		name      string
+		drift     float64
+		wantFlags int
+	}{
+		{name: "tutarlı bakiyeler işaretlenmez"},
+		{name: "kasıtlı fark işaretlenir", drift: 5, wantFlags: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := databasetest.Open(t)
+			ctx := context.Background()
+			now := time.Now()
+
+			createTransaction := func(userID uuid.UUID, txType domain.TransactionType, amount float64, status domain.TransactionState) {
+				t.Helper()
+				transaction := &domain.Transaction{
+					ID:        uuid.New(),
+					UserID:    userID,
+					Type:      txType,
+					Amount:    amount,
+					Status:    string(status),
+					CreatedAt: now,
+					UpdatedAt: now,
+				}
+				if err := db.Create(transaction).Error; err != nil {
+					t.Fatalf("işlem yazılamadı: %v", err)
+				}
+			}
+			createBalance := func(userID uuid.UUID, amount float64) {
+				t.Helper()
+				balance := &domain.Balance{ID: uuid.New(), UserID: userID, Amount: amount, Currency: string(domain.CurrencyTRY), CreatedAt: now, UpdatedAt: now}
+				if err := db.Create(balance).Error; err != nil {
+					t.Fatalf("bakiye yazılamadı: %v", err)
+				}
+			}
+
+			consistent := createRetentionUser(t, db)
+			createTransaction(consistent, domain.TransactionTypeCredit, 100, domain.TransactionStateCompleted)
+			createTransaction(consistent, domain.TransactionTypeDebit, 30, domain.TransactionStateCompleted)
+			// Tamamlanmamış işlemler bakiyeyi etkilemez
+			createTransaction(consistent, domain.TransactionTypeCredit, 50, domain.TransactionStatePending)
+			createBalance(consistent, 70)
+
+			drifted := createRetentionUser(t, db)
+			createTransaction(drifted, domain.TransactionTypeCredit, 50, domain.TransactionStateCompleted)
+			createBalance(drifted, 50+tt.drift)
+
+			eventStore := repository.NewPostgresEventStore(db)
+			job := NewReconciliationJob(repository.NewReconciliationRepository(db), eventStore, time.Hour)
+
+			report, err := job.RunOnce(ctx)
+			if err != nil {
+				t.Fatalf("RunOnce: %v", err)
+			}
+			if report.UsersChecked != 2 {
+				t.Errorf("kontrol edilen kullanıcı = %d, beklenen 2", report.UsersChecked)
+			}
+			if report.DiscrepancyCount != tt.wantFlags || len(report.Discrepancies) != tt.wantFlags {
+				t.Fatalf("fark sayısı = %d (%v), beklenen %d", report.DiscrepancyCount, report.Discrepancies, tt.wantFlags)
+			}
+			if tt.wantFlags > 0 {
+				got := report.Discrepancies[0]
+				if got.UserID != drifted || got.Expected != 50 || got.Actual != 50+tt.drift || got.Difference != tt.drift {
+					t.Errorf("fark = %+v, beklenen kullanıcı %s için 50/%v", got, drifted, 50+tt.drift)
+				}
+			}
+
+			latest, err := job.LatestReport(ctx)
+			if err != nil {
+				t.Fatalf("LatestReport: %v", err)
+			}
+			if latest.ID != report.ID || latest.DiscrepancyCount != tt.wantFlags {
+				t.Errorf("son rapor = %+v, beklenen %s", latest, report.ID)
+			}
+
+			events, err := eventStore.GetEventCount(ctx, report.ID)
+			if err != nil {
+				t.Fatalf("GetEventCount: %v", err)
+			}
+			if wantEvents := int64(tt.wantFlags); events != wantEvents {
+				t.Errorf("alarm event sayısı = %d, beklenen %d", events, wantEvents)
+			}
+		})
+	}
+}
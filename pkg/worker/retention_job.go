@@ -0,0 +1,148 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"transaction-api-w-go/pkg/domain"
+	"transaction-api-w-go/pkg/repository"
+
+	"github.com/rs/zerolog/log"
+)
+
+// maxBatchesPerRun tek bir çalışmada işlenecek en fazla batch sayısı
+const maxBatchesPerRun = 1000
+
+type RetentionResult struct {
+	TransactionsPurged int64     `json:"transactions_purged"`
+	EventsPurged       int64     `json:"events_purged"`
+	StartedAt          time.Time `json:"started_at"`
+	Duration           string    `json:"duration"`
+}
+
+// RetentionJob saklama süresi dolan işlem ve event'leri periyodik olarak batch'ler halinde temizler
+type RetentionJob struct {
+	repo       *repository.RetentionRepository
+	policy     domain.RetentionPolicy
+	ctx        context.Context
+	cancel     context.CancelFunc
+	wg         sync.WaitGroup
+	mu         sync.RWMutex
+	lastResult *RetentionResult
+}
+
+func NewRetentionJob(repo *repository.RetentionRepository, policy domain.RetentionPolicy) *RetentionJob {
+	defaults := domain.DefaultRetentionPolicy()
+	if policy.BatchSize <= 0 {
+		policy.BatchSize = defaults.BatchSize
+	}
+	if policy.Interval <= 0 {
+		policy.Interval = defaults.Interval
+	}
+	if policy.Mode != domain.RetentionModeDelete {
+		policy.Mode = domain.RetentionModeArchive
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &RetentionJob{
+		repo:   repo,
+		policy: policy,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+func (j *RetentionJob) Start() {
+	j.wg.Add(1)
+	go func() {
+		defer j.wg.Done()
+
+		ticker := time.NewTicker(j.policy.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-j.ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := j.RunOnce(j.ctx); err != nil {
+					log.Error().Err(err).Msg("Retention job failed")
+				}
+			}
+		}
+	}()
+}
+
+func (j *RetentionJob) Stop() {
+	j.cancel()
+	j.wg.Wait()
+}
+
+// RunOnce saklama politikasını bir kez uygular
+func (j *RetentionJob) RunOnce(ctx context.Context) (*RetentionResult, error) {
+	start := time.Now()
+	result := &RetentionResult{StartedAt: start}
+
+	if j.policy.TransactionRetention > 0 {
+		cutoff := start.Add(-j.policy.TransactionRetention)
+		purged, err := j.purge(ctx, func(ctx context.Context) (int64, error) {
+			return j.repo.PurgeTransactions(ctx, cutoff, j.policy.BatchSize, j.policy.Mode)
+		})
+		result.TransactionsPurged = purged
+		if err != nil {
+			return result, err
+		}
+	}
+
+	if j.policy.EventRetention > 0 {
+		cutoff := start.Add(-j.policy.EventRetention)
+		purged, err := j.purge(ctx, func(ctx context.Context) (int64, error) {
+			return j.repo.PurgeEvents(ctx, cutoff, j.policy.BatchSize, j.policy.Mode)
+		})
+		result.EventsPurged = purged
+		if err != nil {
+			return result, err
+		}
+	}
+
+	result.Duration = time.Since(start).String()
+
+	j.mu.Lock()
+	j.lastResult = result
+	j.mu.Unlock()
+
+	log.Info().
+		Int64("transactions", result.TransactionsPurged).
+		Int64("events", result.EventsPurged).
+		Str("mode", string(j.policy.Mode)).
+		Msg("Retention job completed")
+
+	return result, nil
+}
+
+func (j *RetentionJob) LastResult() *RetentionResult {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return j.lastResult
+}
+
+func (j *RetentionJob) purge(ctx context.Context, batch func(context.Context) (int64, error)) (int64, error) {
+	var total int64
+	for i := 0; i < maxBatchesPerRun; i++ {
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+
+		purged, err := batch(ctx)
+		if err != nil {
+			return total, err
+		}
+		total += purged
+
+		if purged < int64(j.policy.BatchSize) {
+			break
+		}
+	}
+	return total, nil
+}
@@ -0,0 +1,185 @@
+package worker
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"transaction-api-w-go/pkg/database/databasetest"
+	"transaction-api-w-go/pkg/domain"
+	"transaction-api-w-go/pkg/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func TestRetentionJobRunOnce(t *testing.T) {
+	tests := []struct {
+		name        string
+		mode        domain.RetentionMode
+		wantArchive bool
+		wantPurged  int64
+	}{
+		{name: "arşivleme", mode: domain.RetentionModeArchive, wantArchive: true, wantPurged: 4},
+		{name: "silme", mode: domain.RetentionModeDelete, wantPurged: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := databasetest.Open(t)
+			ctx := context.Background()
+			now := time.Now()
+			old := now.Add(-48 * time.Hour)
+			recent := now.Add(-time.Hour)
+			userID := createRetentionUser(t, db)
+
+			createTransaction := func(status domain.TransactionState, createdAt time.Time) uuid.UUID {
+				t.Helper()
+				transaction := &domain.Transaction{
+					ID:        uuid.New(),
+					UserID:    userID,
+					Type:      domain.TransactionTypeCredit,
+					Amount:    10,
+					Status:    string(status),
+					CreatedAt: createdAt,
+					UpdatedAt: createdAt,
+				}
+				if err := db.Create(transaction).Error; err != nil {
+					t.Fatalf("işlem yazılamadı: %v", err)
+				}
+				return transaction.ID
+			}
+			createEvent := func(aggregateID uuid.UUID, version int64, timestamp time.Time) uuid.UUID {
+				t.Helper()
+				event := &repository.EventStoreModel{
+					ID:          uuid.New(),
+					Type:        domain.EventTransactionCreated,
+					AggregateID: aggregateID,
+					Version:     version,
+					Timestamp:   timestamp,
+					Data:        []byte(`{}`),
+					CreatedAt:   timestamp,
+				}
+				if err := db.Create(event).Error; err != nil {
+					t.Fatalf("event yazılamadı: %v", err)
+				}
+				return event.ID
+			}
+
+			// Eski ve terminal durumdaki üç işlem batch boyutunu aşar; hepsi tek çalışmada temizlenmeli
+			oldCompleted := []uuid.UUID{
+				createTransaction(domain.TransactionStateCompleted, old),
+				createTransaction(domain.TransactionStateFailed, old),
+				createTransaction(domain.TransactionStateCancelled, old),
+			}
+			oldPending := createTransaction(domain.TransactionStatePending, old)
+			recentCompleted := createTransaction(domain.TransactionStateCompleted, recent)
+			// Makbuzu olan işlem arşivlenir ama silinmez; itiraz kaydı olan işlem korunur. Bellek içi
+			// veritabanı boş tabloya karşı NOT EXISTS'i diğer koşullarla birlikte yanlış değerlendirdiğinden
+			// bu tabloların dolu olması testin kendisi için de gereklidir.
+			withReceipt := createTransaction(domain.TransactionStateCompleted, old)
+			withDispute := createTransaction(domain.TransactionStateCompleted, old)
+			if err := db.Exec("INSERT INTO transaction_receipts (id, transaction_id, user_id, sequence, type, amount, balance_after, previous_hash, hash, created_at) VALUES (?, ?, ?, 1, 'credit', 10, 10, ?, ?, ?)",
+				uuid.NewString(), withReceipt.String(), userID.String(), strings.Repeat("0", 64), strings.Repeat("1", 64), old).Error; err != nil {
+				t.Fatalf("makbuz yazılamadı: %v", err)
+			}
+			if err := db.Exec("INSERT INTO disputes (id, transaction_id, user_id, reason, status, opened_at, created_at, updated_at) VALUES (?, ?, ?, 'x', ?, ?, ?, ?)",
+				uuid.NewString(), withDispute.String(), userID.String(), domain.DisputeStatusRejected, old, old, old).Error; err != nil {
+				t.Fatalf("itiraz yazılamadı: %v", err)
+			}
+
+			snapshotted, unsnapshotted := uuid.New(), uuid.New()
+			oldSnapshotted := createEvent(snapshotted, 1, old)
+			// Snapshot'tan sonraki event eski olsa da aggregate'i kurmak için gerekir
+			afterSnapshot := createEvent(snapshotted, 2, old)
+			recentEvent := createEvent(snapshotted, 3, recent)
+			withoutSnapshot := createEvent(unsnapshotted, 1, old)
+			if err := db.Exec("INSERT INTO aggregate_snapshots (aggregate_id, version, data) VALUES (?, ?, ?)", snapshotted.String(), 1, `{}`).Error; err != nil {
+				t.Fatalf("snapshot yazılamadı: %v", err)
+			}
+
+			job := NewRetentionJob(repository.NewRetentionRepository(db), domain.RetentionPolicy{
+				TransactionRetention: 24 * time.Hour,
+				EventRetention:       24 * time.Hour,
+				BatchSize:            2,
+				Mode:                 tt.mode,
+			})
+			result, err := job.RunOnce(ctx)
+			if err != nil {
+				t.Fatalf("RunOnce: %v", err)
+			}
+			if result.TransactionsPurged != tt.wantPurged || result.EventsPurged != 1 {
+				t.Errorf("temizlenen işlem/event = %d/%d, beklenen %d/1", result.TransactionsPurged, result.EventsPurged, tt.wantPurged)
+			}
+
+			for _, id := range oldCompleted {
+				if rowExists(t, db, "transactions", id) {
+					t.Errorf("eski işlem %s silinmedi", id)
+				}
+				if got := rowExists(t, db, "transactions_archive", id); got != tt.wantArchive {
+					t.Errorf("işlem %s arşivde: %v, beklenen %v", id, got, tt.wantArchive)
+				}
+			}
+			for _, id := range []uuid.UUID{oldPending, recentCompleted, withDispute} {
+				if !rowExists(t, db, "transactions", id) {
+					t.Errorf("korunması gereken işlem %s silindi", id)
+				}
+			}
+			if got := rowExists(t, db, "transactions_archive", withReceipt); got != tt.wantArchive {
+				t.Errorf("makbuzu olan işlem arşivde: %v, beklenen %v", got, tt.wantArchive)
+			}
+			if got := rowExists(t, db, "transactions", withReceipt); got == tt.wantArchive {
+				t.Errorf("makbuzu olan işlem tabloda: %v, beklenen %v", got, !tt.wantArchive)
+			}
+			var receipts int64
+			if err := db.Table("transaction_receipts").Where("transaction_id = ?", withReceipt.String()).Count(&receipts).Error; err != nil {
+				t.Fatalf("makbuz okunamadı: %v", err)
+			}
+			if receipts != 1 {
+				t.Errorf("makbuz sayısı = %d, beklenen 1; makbuz yerinde kalmalı", receipts)
+			}
+
+			if rowExists(t, db, "event_store", oldSnapshotted) {
+				t.Errorf("snapshot'ı olan eski event silinmedi")
+			}
+			if got := rowExists(t, db, "event_store_archive", oldSnapshotted); got != tt.wantArchive {
+				t.Errorf("event arşivde: %v, beklenen %v", got, tt.wantArchive)
+			}
+			for _, id := range []uuid.UUID{afterSnapshot, recentEvent, withoutSnapshot} {
+				if !rowExists(t, db, "event_store", id) {
+					t.Errorf("korunması gereken event %s silindi", id)
+				}
+			}
+		})
+	}
+}
+
+func createRetentionUser(t *testing.T, db *gorm.DB) uuid.UUID {
+	t.Helper()
+	now := time.Now()
+	user := &domain.User{
+		ID:        uuid.New(),
+		Password:  "x",
+		FirstName: "Test",
+		LastName:  "User",
+		Role:      domain.RoleUser,
+		LimitTier: domain.LimitTierBasic,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	user.Email = user.ID.String() + "@example.com"
+	if err := db.Create(user).Error; err != nil {
+		t.Fatalf("kullanıcı oluşturulamadı: %v", err)
+	}
+	return user.ID
+}
+
+func rowExists(t *testing.T, db *gorm.DB, table string, id uuid.UUID) bool {
+	t.Helper()
+	var count int64
+	if err := db.Table(table).Where("id = ?", id.String()).Count(&count).Error; err != nil {
+		t.Fatalf("%s okunamadı: %v", table, err)
+	}
+	return count > 0
+}
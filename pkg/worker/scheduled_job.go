@@ -0,0 +1,83 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"transaction-api-w-go/pkg/clock"
+	"transaction-api-w-go/pkg/domain"
+
+	"github.com/rs/zerolog/log"
+)
+
+// DefaultScheduledJobInterval zamanlanmış işlemlerin ve yeniden denemelerin kontrol aralığı
+const DefaultScheduledJobInterval = 30 * time.Second
+
+// ScheduledTransactionJob zamanı gelen zamanlanmış işlemleri ve backoff süresi dolan
+// başarısız işlemlerin yeniden denemelerini periyodik olarak çalıştırır
+type ScheduledTransactionJob struct {
+	service  domain.ScheduledTransactionService
+	interval time.Duration
+	clock    clock.Clock
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+func NewScheduledTransactionJob(service domain.ScheduledTransactionService, interval time.Duration) *ScheduledTransactionJob {
+	if interval <= 0 {
+		interval = DefaultScheduledJobInterval
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &ScheduledTransactionJob{
+		service:  service,
+		interval: interval,
+		clock:    clock.Real(),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+// SetClock ticker'ın oluşturulacağı saati değiştirir; Start'tan önce çağrılmalıdır
+func (j *ScheduledTransactionJob) SetClock(c clock.Clock) {
+	j.clock = c
+}
+
+func (j *ScheduledTransactionJob) Start() {
+	// Ticker goroutine başlamadan kurulur; sahte saat Start'tan hemen sonra ilerletilse de tick kaçmaz
+	ticker := j.clock.NewTicker(j.interval)
+
+	j.wg.Add(1)
+	go func() {
+		defer j.wg.Done()
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-j.ctx.Done():
+				return
+			case <-ticker.C():
+				err := j.RunOnce(j.ctx)
+				switch {
+				case errors.Is(err, domain.ErrAutomationPaused):
+					log.Debug().Msg("Scheduled transaction job skipped, automation paused")
+				case err != nil:
+					log.Error().Err(err).Msg("Scheduled transaction job failed")
+				}
+			}
+		}
+	}()
+}
+
+func (j *ScheduledTransactionJob) Stop() {
+	j.cancel()
+	j.wg.Wait()
+}
+
+// RunOnce zamanı gelen işlemleri ve yeniden denemeleri bir kez çalıştırır
+func (j *ScheduledTransactionJob) RunOnce(ctx context.Context) error {
+	return j.service.ExecuteScheduledTransactions(ctx)
+}
@@ -0,0 +1,80 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// DefaultSettlementInterval ödeme ağ geçidinde sonuç bekleyen ödemelerin sorgulanma aralığı
+const DefaultSettlementInterval = time.Minute
+
+// PaymentReconciler sonuç bekleyen dış ödemelerin durumunu ağ geçidine sorup sonuçlananları uygular
+type PaymentReconciler interface {
+	ReconcileExternalPayments(ctx context.Context, limit int) (int, error)
+}
+
+// SettlementJob pending_settlement durumundaki dış ödemeleri periyodik olarak ağ geçidiyle eşler;
+// ağ geçidinin sonucu hemen bildirmediği ödemeler bu job ile tamamlanır veya başarısız olur
+type SettlementJob struct {
+	reconciler PaymentReconciler
+	interval   time.Duration
+	batchSize  int
+	ctx        context.Context
+	cancel     context.CancelFunc
+	wg         sync.WaitGroup
+}
+
+func NewSettlementJob(reconciler PaymentReconciler, interval time.Duration, batchSize int) *SettlementJob {
+	if interval <= 0 {
+		interval = DefaultSettlementInterval
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &SettlementJob{
+		reconciler: reconciler,
+		interval:   interval,
+		batchSize:  batchSize,
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+}
+
+func (j *SettlementJob) Start() {
+	j.wg.Add(1)
+	go func() {
+		defer j.wg.Done()
+
+		ticker := time.NewTicker(j.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-j.ctx.Done():
+				return
+			case <-ticker.C:
+				j.RunOnce(j.ctx)
+			}
+		}
+	}()
+}
+
+func (j *SettlementJob) Stop() {
+	j.cancel()
+	j.wg.Wait()
+}
+
+// RunOnce sonuç bekleyen ödemeleri bir kez eşler ve sonuçlandırılan ödeme sayısını döner
+func (j *SettlementJob) RunOnce(ctx context.Context) int {
+	settled, err := j.reconciler.ReconcileExternalPayments(ctx, j.batchSize)
+	if err != nil {
+		log.Error().Err(err).Msg("Payment settlement job failed")
+		return settled
+	}
+	if settled > 0 {
+		log.Info().Int("settled", settled).Msg("External payments settled")
+	}
+	return settled
+}
@@ -2,18 +2,43 @@ package worker
 
 import (
 	"context"
+	"errors"
 	"sync"
 	"sync/atomic"
 	"time"
 	"transaction-api-w-go/pkg/domain"
 )
 
+var ErrWorkerPoolStopped = errors.New("transaction worker pool stopped")
+
 type TransactionJob struct {
 	TransactionID uint
 	FromUserID    uint
 	ToUserID      uint
 	Amount        float64
 	Description   string
+	// Callback opsiyoneldir; iş tamamlandığında worker goroutine'inde çağrılır
+	Callback func(JobResult)
+
+	result chan JobResult
+}
+
+// JobResult tek bir işin sonucunu taşır
+type JobResult struct {
+	TransactionID uint
+	Err           error
+	Duration      time.Duration
+}
+
+// complete sonucu callback'e ve bekleyen çağırana iletir
+func (j TransactionJob) complete(result JobResult) {
+	if j.Callback != nil {
+		j.Callback(result)
+	}
+	if j.result != nil {
+		j.result <- result
+		close(j.result)
+	}
 }
 
 type TransactionWorker struct {
@@ -25,6 +50,7 @@ type TransactionWorker struct {
 	failedCount        uint64
 	mu                 sync.RWMutex
 	ctx                context.Context
+	killSwitch         AutomationSwitch
 }
 
 type TransactionWorkerPool struct {
@@ -73,6 +99,14 @@ func NewTransactionWorkerPool(
 	return pool
 }
 
+// SetKillSwitch worker'ların her işten önce kontrol edeceği duraklatma anahtarını bağlar;
+// Start'tan önce çağrılmalıdır
+func (p *TransactionWorkerPool) SetKillSwitch(sw AutomationSwitch) {
+	for _, worker := range p.workers {
+		worker.killSwitch = sw
+	}
+}
+
 func (p *TransactionWorkerPool) Start() {
 	for _, worker := range p.workers {
 		p.wg.Add(1)
@@ -80,62 +114,105 @@ func (p *TransactionWorkerPool) Start() {
 	}
 }
 
+// Stop worker'ları durdurur ve kuyrukta kalan işleri ErrWorkerPoolStopped ile tamamlar.
+// Kuyruk kapatılmaz; durduktan sonra gönderilen işler de aynı hatayla sonuçlanır.
 func (p *TransactionWorkerPool) Stop() {
 	p.cancel()
 	p.wg.Wait()
-	close(p.jobQueue)
+
+	for {
+		select {
+		case job := <-p.jobQueue:
+			job.complete(JobResult{TransactionID: job.TransactionID, Err: ErrWorkerPoolStopped})
+		default:
+			return
+		}
+	}
 }
 
 func (p *TransactionWorkerPool) SubmitJob(job TransactionJob) {
+	if p.ctx.Err() != nil {
+		return
+	}
+
 	select {
 	case p.jobQueue <- job:
 	case <-p.ctx.Done():
 	}
 }
 
+// SubmitJobWithResult işi kuyruğa ekler ve sonucun yazılacağı bir kanal döner.
+// Kanal tek bir sonuç aldıktan sonra kapanır; pool durmuşsa ErrWorkerPoolStopped döner.
+func (p *TransactionWorkerPool) SubmitJobWithResult(job TransactionJob) <-chan JobResult {
+	job.result = make(chan JobResult, 1)
+
+	if p.ctx.Err() != nil {
+		job.complete(JobResult{TransactionID: job.TransactionID, Err: ErrWorkerPoolStopped})
+		return job.result
+	}
+
+	select {
+	case p.jobQueue <- job:
+	case <-p.ctx.Done():
+		job.complete(JobResult{TransactionID: job.TransactionID, Err: ErrWorkerPoolStopped})
+	}
+
+	return job.result
+}
+
 func (p *TransactionWorkerPool) GetStats() *domain.TransactionStats {
 	stats := p.transactionService.GetStats()
 	return stats
 }
 
+// WorkerCount pool'daki worker sayısını döner
+func (p *TransactionWorkerPool) WorkerCount() int {
+	return len(p.workers)
+}
+
+// QueueLength kuyrukta bekleyen iş sayısını döner
+func (p *TransactionWorkerPool) QueueLength() int {
+	return len(p.jobQueue)
+}
+
 func (w *TransactionWorker) start(wg *sync.WaitGroup) {
 	defer wg.Done()
 
-	for job := range w.jobQueue {
+	for {
+		var job TransactionJob
+		select {
+		case <-w.ctx.Done():
+			return
+		case job = <-w.jobQueue:
+		}
+
+		if !waitWhilePaused(w.ctx, w.killSwitch, "transaction_worker") {
+			job.complete(JobResult{TransactionID: job.TransactionID, Err: ErrWorkerPoolStopped})
+			continue
+		}
+
 		startTime := time.Now()
 
 		err := w.processTransaction(job)
+		elapsed := time.Since(startTime)
 
+		stats := w.transactionService.GetStats()
 		if err != nil {
 			atomic.AddUint64(&w.failedCount, 1)
-			atomic.AddUint64(&w.transactionService.GetStats().TotalFailed, 1)
+			stats.RecordFailure()
 		} else {
 			atomic.AddUint64(&w.processedCount, 1)
-			atomic.AddUint64(&w.transactionService.GetStats().TotalProcessed, 1)
+			stats.UpdateStats(job.Amount, elapsed.Seconds())
 		}
 
-		processTime := time.Since(startTime).Seconds()
-
-		stats := w.transactionService.GetStats()
-		stats.UpdateStats(job.Amount, processTime)
+		job.complete(JobResult{
+			TransactionID: job.TransactionID,
+			Err:           err,
+			Duration:      elapsed,
+		})
 	}
 }
 
 func (w *TransactionWorker) processTransaction(job TransactionJob) error {
-	startTime := time.Now()
-
-	err := w.transactionService.ProcessTransaction(w.ctx, job.TransactionID)
-	if err != nil {
-		atomic.AddUint64(&w.failedCount, 1)
-		return err
-	}
-
-	atomic.AddUint64(&w.processedCount, 1)
-
-	processTime := time.Since(startTime).Seconds()
-
-	stats := w.transactionService.GetStats()
-	stats.UpdateStats(job.Amount, processTime)
-
-	return nil
+	return w.transactionService.ProcessTransaction(w.ctx, job.TransactionID)
 }
@@ -0,0 +1,105 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"transaction-api-w-go/pkg/domain"
+)
+
+var errProcessFailed = errors.New("process failed")
+
+// fakeTransactionService yalnızca worker'ın kullandığı metodları uygular; failing içindeki
+// işlemler hata döner
+type fakeTransactionService struct {
+	domain.TransactionService
+	failing map[uint]bool
+	stats   *domain.TransactionStats
+}
+
+func newFakeTransactionService(failing ...uint) *fakeTransactionService {
+	svc := &fakeTransactionService{failing: make(map[uint]bool), stats: &domain.TransactionStats{}}
+	for _, id := range failing {
+		svc.failing[id] = true
+	}
+	return svc
+}
+
+func (s *fakeTransactionService) ProcessTransaction(ctx context.Context, transactionID uint) error {
+	if s.failing[transactionID] {
+		return errProcessFailed
+	}
+	return nil
+}
+
+func (s *fakeTransactionService) GetStats() *domain.TransactionStats {
+	return s.stats
+}
+
+func awaitJobResult(t *testing.T, results <-chan JobResult) JobResult {
+	t.Helper()
+	select {
+	case result, ok := <-results:
+		if !ok {
+			t.Fatal("sonuç kanalı sonuçsuz kapandı")
+		}
+		return result
+	case <-time.After(5 * time.Second):
+		t.Fatal("iş sonucu beklenirken süre doldu")
+		return JobResult{}
+	}
+}
+
+func TestTransactionWorkerPoolSubmitJobWithResult(t *testing.T) {
+	tests := []struct {
+		name          string
+		transactionID uint
+		wantErr       error
+	}{
+		{name: "başarılı iş", transactionID: 1},
+		{name: "başarısız iş", transactionID: 2, wantErr: errProcessFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool := NewTransactionWorkerPool(2, newFakeTransactionService(2), nil)
+			pool.Start()
+			t.Cleanup(pool.Stop)
+
+			var callback JobResult
+			called := false
+			result := awaitJobResult(t, pool.SubmitJobWithResult(TransactionJob{
+				TransactionID: tt.transactionID,
+				Amount:        10,
+				Callback: func(r JobResult) {
+					callback = r
+					called = true
+				},
+			}))
+
+			if result.TransactionID != tt.transactionID {
+				t.Errorf("TransactionID = %d, beklenen %d", result.TransactionID, tt.transactionID)
+			}
+			if !errors.Is(result.Err, tt.wantErr) {
+				t.Errorf("Err = %v, beklenen %v", result.Err, tt.wantErr)
+			}
+			// Callback sonuç kanalına yazılmadan önce aynı goroutine'de çağrılır
+			if !called || callback.TransactionID != tt.transactionID || !errors.Is(callback.Err, tt.wantErr) {
+				t.Errorf("callback sonucu = %+v (çağrıldı: %v)", callback, called)
+			}
+		})
+	}
+}
+
+func TestTransactionWorkerPoolSubmitJobWithResultAfterStop(t *testing.T) {
+	pool := NewTransactionWorkerPool(1, newFakeTransactionService(), nil)
+	pool.Start()
+	pool.Stop()
+
+	result := awaitJobResult(t, pool.SubmitJobWithResult(TransactionJob{TransactionID: 7}))
+	if !errors.Is(result.Err, ErrWorkerPoolStopped) {
+		t.Fatalf("Err = %v, beklenen ErrWorkerPoolStopped", result.Err)
+	}
+}
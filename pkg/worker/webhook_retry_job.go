@@ -0,0 +1,79 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// DefaultWebhookRetryInterval zamanı gelen webhook teslimatlarının kontrol aralığı
+const DefaultWebhookRetryInterval = 15 * time.Second
+
+// WebhookRetrier zamanı gelmiş başarısız webhook teslimatlarını yeniden dener
+type WebhookRetrier interface {
+	RetryDue(ctx context.Context, limit int) (int, error)
+}
+
+// WebhookRetryJob backoff süresi dolan webhook teslimatlarını periyodik olarak yeniden gönderir
+type WebhookRetryJob struct {
+	retrier   WebhookRetrier
+	interval  time.Duration
+	batchSize int
+	ctx       context.Context
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+}
+
+func NewWebhookRetryJob(retrier WebhookRetrier, interval time.Duration, batchSize int) *WebhookRetryJob {
+	if interval <= 0 {
+		interval = DefaultWebhookRetryInterval
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &WebhookRetryJob{
+		retrier:   retrier,
+		interval:  interval,
+		batchSize: batchSize,
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+}
+
+func (j *WebhookRetryJob) Start() {
+	j.wg.Add(1)
+	go func() {
+		defer j.wg.Done()
+
+		ticker := time.NewTicker(j.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-j.ctx.Done():
+				return
+			case <-ticker.C:
+				j.RunOnce(j.ctx)
+			}
+		}
+	}()
+}
+
+func (j *WebhookRetryJob) Stop() {
+	j.cancel()
+	j.wg.Wait()
+}
+
+// RunOnce zamanı gelen teslimatları bir kez dener ve denenen teslimat sayısını döner
+func (j *WebhookRetryJob) RunOnce(ctx context.Context) int {
+	attempted, err := j.retrier.RetryDue(ctx, j.batchSize)
+	if err != nil {
+		log.Error().Err(err).Msg("Webhook retry job failed")
+		return attempted
+	}
+	if attempted > 0 {
+		log.Info().Int("attempted", attempted).Msg("Webhook deliveries retried")
+	}
+	return attempted
+}